@@ -2,19 +2,26 @@ package main
 
 import (
 	"cargo-tracker/internal/config"
+	"cargo-tracker/internal/delivery/http/server"
+	"cargo-tracker/internal/infrastructure/currency"
 	"cargo-tracker/internal/infrastructure/database/postgres"
+	"cargo-tracker/internal/infrastructure/encryption"
+	"cargo-tracker/internal/infrastructure/notification"
+	"cargo-tracker/internal/infrastructure/secrets"
+	"cargo-tracker/internal/infrastructure/storage"
 	"cargo-tracker/internal/logger"
 	"cargo-tracker/internal/routes"
 	"context"
 	"errors"
 	"go.uber.org/zap"
 	"log"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Business calendars resolve provider time zones without system zoneinfo
 )
 
 func main() {
@@ -38,6 +45,25 @@ func main() {
 		zap.String("environment", env),
 	)
 
+	// Resolve secret:// references before anything reads the config
+	resolver := secrets.NewResolverFromConfig(&cfg.Secrets)
+	dbPasswordRef := cfg.Database.Password
+	secretsCtx, secretsCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	err = cfg.ResolveSecrets(secretsCtx, resolver)
+	secretsCancel()
+	if err != nil {
+		logger.Fatal("Failed to resolve secrets", zap.Error(err))
+	}
+
+	keyring, err := encryption.LoadKeyring(&cfg.Encryption)
+	if err != nil {
+		logger.Fatal("Failed to load encryption keys", zap.Error(err))
+	}
+	if keyring == nil {
+		logger.Warn("ENCRYPTION_KEYS is not set; PII columns will be stored in plaintext")
+	}
+	encryption.SetDefault(keyring)
+
 	if cfg.Database.Host == "" || cfg.Database.DBName == "" {
 		logger.Fatal("Database configuration is missing. Please set DB_HOST and DB_NAME environment variables.")
 	}
@@ -46,7 +72,11 @@ func main() {
 	}
 
 	// Initialize infrastructure
-	db, _ := postgres.NewDB(cfg)
+	dbPassword := secrets.NewValue(cfg.Database.Password)
+	db, err := postgres.NewDB(cfg, postgres.WithPasswordSource(dbPassword.Get))
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
 	defer func(db *postgres.DB) {
 		err := db.Close()
 		if err != nil {
@@ -54,39 +84,68 @@ func main() {
 		}
 	}(db)
 
-	// Start token cleanup job
-	//cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
-	//defer cleanupCancel()
-	//go userService.StartTokenCleanupJob(cleanupCtx, 1*time.Hour)
+	// Phone numbers stored before encryption was enabled are only found
+	// and kept unique once they have a blind index
+	if keyring != nil {
+		backfillCtx, backfillCancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		_, err := db.BackfillBlindIndexes(backfillCtx, keyring, 0)
+		backfillCancel()
+		if err != nil {
+			logger.Fatal("Failed to backfill blind indexes", zap.Error(err))
+		}
+	}
 
-	// Setup routes
-	router := routes.SetupRoutes(cfg, db)
+	// Background jobs run until shutdown cancels backgroundCtx; workers lets
+	// shutdown wait for them before the database is closed
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	var workers sync.WaitGroup
 
-	// Start server...
-	host := cfg.Server.Host
-	if host == "" {
-		host = "0.0.0.0"
+	// Pick up rotated database credentials for new connections
+	if secrets.IsReference(dbPasswordRef) {
+		routes.RunInBackground(backgroundCtx, &workers, func(ctx context.Context) {
+			resolver.Watch(ctx, dbPasswordRef, cfg.Database.Password, cfg.Secrets.RefreshInterval,
+				func(value string) {
+					dbPassword.Set(value)
+					logger.Info("Database password rotated", zap.String("event", "secret_rotated"))
+				},
+				func(err error) {
+					logger.Warn("Failed to refresh database password", zap.Error(err))
+				},
+			)
+		})
 	}
-	port := cfg.Server.Port
-	if port == "" {
-		port = "8080"
+
+	// Setup routes
+	store, err := storage.New(&cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to initialize file storage", zap.Error(err))
 	}
-	addr := net.JoinHostPort(host, port)
-
-	server := &http.Server{
-		Addr:         addr,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+
+	// Notifications are delivered in the background; Close flushes the queue on shutdown
+	webhookRepository := postgres.NewNotificationWebhookRepository(db)
+	webhookEventRepository := postgres.NewWebhookEventRepository(db)
+	pushRepository := postgres.NewPushRepository(db)
+	notifier := notification.New(cfg, postgres.NewNotificationTemplateRepository(db), webhookRepository, webhookEventRepository, postgres.NewChatLinkRepository(db), pushRepository)
+	defer notifier.Close()
+
+	rates, err := currency.New(&cfg.Currency)
+	if err != nil {
+		logger.Fatal("Failed to initialize exchange rates", zap.Error(err))
 	}
 
+	router := routes.SetupRoutes(backgroundCtx, &workers, cfg, db, store, notifier, rates)
+
+	// Start server...
+	srv := server.New(cfg, router)
+	addr := srv.Addr
+
 	// Start goroutine
 	go func() {
 		logger.Info("Server starting",
 			zap.String("address", addr),
 		)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
@@ -101,9 +160,13 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("Failed to shutdown server", zap.Error(err))
 	}
 
+	// Background jobs still use the database, which closes when main returns
+	stopBackground()
+	workers.Wait()
+
 	log.Println("Server exited properly")
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"cargo-tracker/internal/config"
+	"cargo-tracker/internal/infrastructure/database/postgres"
+	"cargo-tracker/internal/infrastructure/encryption"
+	"cargo-tracker/internal/infrastructure/secrets"
+	"cargo-tracker/internal/logger"
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// rotate-keys re-encrypts PII columns with the active encryption key. Run it
+// after adding a new key to ENCRYPTION_KEYS and pointing ENCRYPTION_ACTIVE_KEY_ID
+// at it; once it completes the old key can be removed from the list. It also
+// encrypts any rows written before encryption was enabled.
+func main() {
+	batchSize := flag.Int("batch-size", 500, "rows to process per query")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		os.Stderr.WriteString("Failed to load configuration: " + err.Error() + "\n")
+		os.Exit(1)
+	}
+
+	if err := logger.Init(cfg.Server.Environment); err != nil {
+		os.Stderr.WriteString("Failed to initialize logger: " + err.Error() + "\n")
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	if err := cfg.ResolveSecrets(ctx, secrets.NewResolverFromConfig(&cfg.Secrets)); err != nil {
+		logger.Fatal("Failed to resolve secrets", zap.Error(err))
+	}
+
+	keyring, err := encryption.LoadKeyring(&cfg.Encryption)
+	if err != nil {
+		logger.Fatal("Failed to load encryption keys", zap.Error(err))
+	}
+	if keyring == nil {
+		logger.Fatal("ENCRYPTION_KEYS is not set; nothing to rotate to")
+	}
+	encryption.SetDefault(keyring)
+
+	db, err := postgres.NewDB(cfg)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	rotated, err := db.RotateEncryptedColumns(ctx, keyring, *batchSize)
+	if err != nil {
+		logger.Fatal("Key rotation failed", zap.Int64("rotated", rotated), zap.Error(err))
+	}
+	if _, err := db.BackfillBlindIndexes(ctx, keyring, *batchSize); err != nil {
+		logger.Fatal("Blind index backfill failed", zap.Error(err))
+	}
+
+	logger.Info("Key rotation completed",
+		zap.String("active_key_id", keyring.ActiveKeyID()),
+		zap.Int64("rotated", rotated),
+	)
+}
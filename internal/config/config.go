@@ -1,27 +1,59 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	JWT       JWTConfig
-	SMTP      SMTPConfig
-	RateLimit RateLimitConfig
-	CORS      CORSConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	JWT          JWTConfig
+	SMTP         SMTPConfig
+	RateLimit    RateLimitConfig
+	CORS         CORSConfig
+	Compression  CompressionConfig
+	Request      RequestLimitConfig
+	Secrets      SecretsConfig
+	Encryption   EncryptionConfig
+	Storage      StorageConfig
+	Notification NotificationConfig
+	ChatBot      ChatBotConfig
+	Push         PushConfig
+	Sandbox      SandboxConfig
+	Risk         RiskConfig
+	Session      SessionConfig
+	SMS          SMSConfig
+	Invoicing    InvoicingConfig
+	StatsCache   StatsCacheConfig
+	Currency     CurrencyConfig
+	Cleanup      CleanupConfig
+	Jobs         JobsConfig
+	Reports      ReportsConfig
+	Watchdog     WatchdogConfig
+	Events       ShipmentEventsConfig
+	Quota        QuotaConfig
+	Fleet        FleetConfig
+	APIVersions  APIVersionsConfig
+	SelfTest     SelfTestConfig
 }
 
 type ServerConfig struct {
 	Port        string
 	Host        string
 	Environment string
+
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	RequestTimeout time.Duration // Deadline of the request context; 0 disables
 }
 
 type DatabaseConfig struct {
@@ -31,6 +63,16 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	MaxOpenConns       int
+	MaxIdleConns       int
+	ConnMaxLifetime    time.Duration
+	ConnMaxIdleTime    time.Duration
+	PrepareStmt        bool          // Cache prepared statements per connection
+	SlowQueryThreshold time.Duration // Queries slower than this are logged as warnings; 0 disables
+
+	BreakerThreshold     int           // Consecutive connection failures that open the circuit breaker
+	BreakerProbeInterval time.Duration // How often the database is pinged while the breaker is open
 }
 
 type JWTConfig struct {
@@ -47,6 +89,15 @@ type SMTPConfig struct {
 	From     string
 }
 
+// SMSConfig configures the HTTP SMS gateway used for one-time passwords.
+// Without a gateway URL messages are only logged.
+type SMSConfig struct {
+	GatewayURL string
+	APIKey     string
+	SenderID   string
+	Timeout    time.Duration
+}
+
 type RateLimitConfig struct {
 	GeneralRPS   float64 // Requests per second for general endpoints
 	GeneralBurst int     // Burst size for general endpoints
@@ -61,6 +112,287 @@ type CORSConfig struct {
 	MaxAge           int
 }
 
+type CompressionConfig struct {
+	Enabled     bool
+	Level       int // gzip/deflate level, 1 (fastest) to 9 (best)
+	BrotliLevel int // brotli level, 0 (fastest) to 11 (best)
+	MinSize     int // Responses smaller than this many bytes are sent uncompressed
+}
+
+type RequestLimitConfig struct {
+	MaxBodyBytes   int64 // Default limit for all endpoints
+	MaxUploadBytes int64 // Limit for file upload endpoints
+	MaxBulkBytes   int64 // Limit for bulk/batch endpoints
+}
+
+// SecretsConfig configures the external secrets backends. Any sensitive
+// setting may be given as a secret://vault/... or secret://aws/... reference
+// instead of a literal value.
+//
+// Only DB_PASSWORD is re-fetched while the server runs, because new database
+// connections read it through a secrets.Value. Every other reference,
+// JWT_SECRET included, is resolved once at startup: rotating one of them takes
+// a restart to apply.
+type SecretsConfig struct {
+	VaultAddress       string
+	VaultToken         string
+	VaultNamespace     string
+	AWSRegion          string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+	RefreshInterval    time.Duration // How often DB_PASSWORD is re-fetched; 0 disables
+}
+
+// EncryptionConfig holds the keys for column-level encryption of PII.
+// Keys is a comma separated list of id:base64key pairs; keep retired keys in
+// the list until rotation has re-encrypted every row.
+type EncryptionConfig struct {
+	Keys        string
+	ActiveKeyID string
+	IndexKey    string // base64 HMAC key for blind indexes
+}
+
+type StorageConfig struct {
+	Backend   string // Only "local" is supported for now
+	LocalPath string
+}
+
+type NotificationConfig struct {
+	DefaultLanguage    string // Template language used when the recipient has no preference
+	AppURL             string // Public URL of the web app, used for links in chat alerts
+	WebhookTimeout     time.Duration
+	WebhookMaxAttempts int
+	DigestHour         int // Local hour at which the daily digest is posted; negative disables it
+	// Delivered webhook events are archived for replay this long
+	WebhookEventRetention time.Duration
+}
+
+// ChatBotConfig holds the credentials of the messaging bots users can link
+// their accounts to. A bot is disabled while its token is empty.
+type ChatBotConfig struct {
+	TelegramBotToken      string
+	TelegramBotUsername   string // Used to build t.me deep links
+	TelegramWebhookSecret string // Expected in X-Telegram-Bot-Api-Secret-Token
+	ZaloAppID             string
+	ZaloOAID              string // Official Account ID, used to build deep links
+	ZaloAccessToken       string
+	ZaloSecretKey         string // OA secret key used to verify event signatures
+	LinkCodeTTL           time.Duration
+}
+
+// PushConfig holds the credentials of the mobile push services. FCM is
+// enabled when a service account is configured, APNs when a signing key is.
+type PushConfig struct {
+	FCMCredentialsFile string // Path to the Firebase service account JSON
+	APNsKeyFile        string // Path to the .p8 token signing key
+	APNsKeyID          string
+	APNsTeamID         string
+	APNsBundleID       string
+	APNsProduction     bool
+	TokenTTL           time.Duration // Tokens not refreshed for this long are pruned
+}
+
+// SandboxConfig controls sandbox accounts for integration partners. Sandbox
+// data is isolated from live data and never reaches real notification channels.
+type SandboxConfig struct {
+	Enabled bool // Allow self-registration of sandbox accounts
+}
+
+// RiskConfig holds the relative weights of the shipment risk factors. Weights
+// need not sum to 1; they are normalised over the factors that can be scored.
+type RiskConfig struct {
+	ReputationWeight  float64
+	DeviceWeight      float64
+	RouteWeight       float64
+	GoodsWeight       float64
+	SeasonalityWeight float64
+}
+
+// SessionConfig limits login sessions. A session is revoked after
+// InactivityTimeout without a refresh, and logging in beyond a role's cap
+// revokes that user's oldest sessions. Zero disables a limit.
+type SessionConfig struct {
+	InactivityTimeout   time.Duration
+	MaxSessionsCustomer int
+	MaxSessionsProvider int
+	MaxSessionsShipper  int
+	MaxSessionsAdmin    int
+
+	// How often idle sessions are revoked and expired refresh tokens and
+	// codes deleted; 0 disables the job
+	CleanupInterval time.Duration
+}
+
+// MaxSessions returns the concurrent session cap of a role
+func (c SessionConfig) MaxSessions(role string) int {
+	switch role {
+	case "customer":
+		return c.MaxSessionsCustomer
+	case "provider":
+		return c.MaxSessionsProvider
+	case "shipper":
+		return c.MaxSessionsShipper
+	case "admin":
+		return c.MaxSessionsAdmin
+	default:
+		return 0
+	}
+}
+
+// InvoicingConfig sets the charges applied when a billing period is closed.
+// Percentages are of the agreed shipment price, except the shipper bonus
+// which is of the shipper fee.
+type InvoicingConfig struct {
+	SLAPenaltyPercentPerDay float64
+	SLAPenaltyMaxPercent    float64
+	CancellationFeePercent  float64
+	DefaultCurrency         string
+	PaymentTermsDays        int
+
+	ShipperFeePercent   float64 // Share of the agreed price earned by the shipper
+	ShipperBonusPercent float64 // Paid for on-time deliveries rated 5 stars
+}
+
+// StatsCacheConfig controls caching of the statistics endpoints. Results are
+// served for TTL, then for Stale more while they reload in the background.
+// A TTL of zero disables the cache.
+type StatsCacheConfig struct {
+	TTL             time.Duration
+	Stale           time.Duration
+	RefreshInterval time.Duration // Background reloads while dashboards are polling
+}
+
+// CurrencyConfig selects where exchange rates come from. Rates are values of
+// one unit in the reporting currency, which statistics are aggregated in.
+type CurrencyConfig struct {
+	ReportingCurrency string
+	RateSource        string // "static" or "http"
+	StaticRates       string // e.g. "USD=25400,EUR=27500"
+	RatesURL          string
+	RatesTTL          time.Duration
+	Timeout           time.Duration
+}
+
+// CleanupConfig gates the admin bulk delete of test data. It is never
+// available when the environment is production.
+type CleanupConfig struct {
+	Enabled    bool
+	BatchSize  int
+	BatchPause time.Duration // Pause between batches to keep load on the database low
+}
+
+// JobsConfig sizes the worker pool running background jobs. Each worker
+// holds a lease on its job that progress updates extend; jobs whose lease
+// expires are picked up by another worker.
+type JobsConfig struct {
+	Workers      int
+	PollInterval time.Duration
+	Lease        time.Duration
+	Retention    time.Duration // Finished jobs and their result files are deleted after this
+}
+
+// ReportsConfig controls scheduled report emails. Subscriptions are sent at
+// SendHour local time on the first day of each period.
+type ReportsConfig struct {
+	PollInterval time.Duration // How often due subscriptions are looked up
+	SendHour     int
+}
+
+// WatchdogConfig controls the detection of shipments stuck in an intermediate
+// status. A shipment is reminded about once it has stayed in a status for the
+// status' threshold, escalated EscalateAfter later and, for stuck shipper
+// assignments, released back to the marketplace another EscalateAfter later.
+// A zero threshold leaves the status alone.
+type WatchdogConfig struct {
+	Interval              time.Duration // How often shipments are checked; 0 disables the watchdog
+	DemandCreatedAfter    time.Duration
+	OrderPostedAfter      time.Duration
+	ShippingAssignedAfter time.Duration
+	IssueReportedAfter    time.Duration
+	EscalateAfter         time.Duration
+	RevertAssignments     bool // Release stuck shipper assignments after escalation
+	// Free devices still held by finished or deleted shipments
+	ReleaseStaleDevices bool
+}
+
+// ShipmentEventsConfig controls the shipment event log. When enabled every
+// lifecycle change is also appended as an immutable event, which backs the
+// status history, the event timeline and point-in-time state. The shipments
+// table stays the source of truth either way.
+type ShipmentEventsConfig struct {
+	Enabled bool
+}
+
+// FleetConfig sets what the fleet compliance report holds devices to. A
+// device complies when it runs TargetFirmware or newer and its battery is
+// above LowBatteryPercent. Without TargetFirmware every known version counts
+// as current.
+type FleetConfig struct {
+	TargetFirmware         string
+	LowBatteryPercent      int
+	CriticalBatteryPercent int
+}
+
+// SelfTestConfig controls device self-tests. A test passes when the sensors
+// read sane values, the GPS has a fix, the battery is above the fleet's
+// critical level and the signal is at least MinSignalDBm. With
+// RequiredForAccept an order can only be accepted with a device that passed
+// one within MaxAge.
+type SelfTestConfig struct {
+	RequiredForAccept bool
+	MaxAge            time.Duration
+	MinSignalDBm      int
+}
+
+// APIVersionsConfig announces the retirement of old API versions. /api/v1
+// is frozen; once V1DeprecatedAt is set its responses carry Deprecation,
+// Sunset and successor-version Link headers pointing clients at /api/v2.
+type APIVersionsConfig struct {
+	V1DeprecatedAt *time.Time
+	V1SunsetAt     *time.Time // Announced date after which v1 may be removed
+}
+
+// QuotaConfig controls the subscription plans. Every account is on a plan;
+// its limits cap what the account can have open at once and how many API
+// calls it can make per calendar month (UTC), and its features decide what
+// it can use at all. Nothing is enforced unless Enabled is set.
+type QuotaConfig struct {
+	Enabled    bool
+	SignupPlan string // Plan of newly registered accounts
+	Free       PlanConfig
+	Pro        PlanConfig
+	Enterprise PlanConfig
+}
+
+type PlanConfig struct {
+	// Limits; zero is unlimited
+	ActiveShipments int64 // Shipments not yet completed or cancelled
+	Devices         int64 // Devices owned, shippers only
+	APICalls        int64 // Authenticated requests per month
+
+	// Features
+	PredictiveAlerts bool
+	Webhooks         bool
+}
+
+// Plan returns the settings of a plan; unknown plans get the free ones
+func (c QuotaConfig) Plan(name string) PlanConfig {
+	switch name {
+	case "pro":
+		return c.Pro
+	case "enterprise":
+		return c.Enterprise
+	default:
+		return c.Free
+	}
+}
+
+// SecretResolver resolves secret references into their values.
+type SecretResolver interface {
+	Resolve(ctx context.Context, value string) (string, error)
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AddConfigPath(".")
@@ -69,6 +401,114 @@ func Load() (*Config, error) {
 	}
 	viper.AutomaticEnv()
 
+	viper.SetDefault("SERVER_READ_TIMEOUT", "15s")
+	viper.SetDefault("SERVER_WRITE_TIMEOUT", "15s")
+	viper.SetDefault("SERVER_IDLE_TIMEOUT", "60s")
+	viper.SetDefault("SERVER_REQUEST_TIMEOUT", "15s")
+	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
+	viper.SetDefault("DB_MAX_IDLE_CONNS", 5)
+	viper.SetDefault("DB_CONN_MAX_LIFETIME", "5m")
+	viper.SetDefault("DB_CONN_MAX_IDLE_TIME", "2m")
+	viper.SetDefault("DB_PREPARE_STMT", true)
+	viper.SetDefault("DB_SLOW_QUERY_THRESHOLD", "200ms")
+	viper.SetDefault("DB_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("DB_BREAKER_PROBE_INTERVAL", "5s")
+	viper.SetDefault("COMPRESSION_ENABLED", true)
+	viper.SetDefault("COMPRESSION_LEVEL", 5)
+	viper.SetDefault("COMPRESSION_BROTLI_LEVEL", 4)
+	viper.SetDefault("COMPRESSION_MIN_SIZE", 1024)
+	viper.SetDefault("REQUEST_MAX_BODY_BYTES", 10<<20)
+	viper.SetDefault("REQUEST_MAX_UPLOAD_BYTES", 50<<20)
+	viper.SetDefault("REQUEST_MAX_BULK_BYTES", 20<<20)
+	// Without a rate every request would be rejected
+	viper.SetDefault("RATE_LIMIT_GENERAL_RPS", 10)
+	viper.SetDefault("RATE_LIMIT_GENERAL_BURST", 20)
+	viper.SetDefault("SECRETS_REFRESH_INTERVAL", "5m")
+	viper.SetDefault("STORAGE_BACKEND", "local")
+	viper.SetDefault("STORAGE_LOCAL_PATH", "./data/uploads")
+	viper.SetDefault("NOTIFICATION_DEFAULT_LANGUAGE", "en")
+	viper.SetDefault("NOTIFICATION_WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("NOTIFICATION_WEBHOOK_MAX_ATTEMPTS", 3)
+	viper.SetDefault("NOTIFICATION_DIGEST_HOUR", 8)
+	viper.SetDefault("NOTIFICATION_WEBHOOK_EVENT_RETENTION", "720h")
+	viper.SetDefault("CHAT_LINK_CODE_TTL", "15m")
+	viper.SetDefault("PUSH_TOKEN_TTL", "1440h")
+	viper.SetDefault("SANDBOX_ENABLED", false)
+	viper.SetDefault("RISK_WEIGHT_REPUTATION", 0.35)
+	viper.SetDefault("RISK_WEIGHT_DEVICE", 0.2)
+	viper.SetDefault("RISK_WEIGHT_ROUTE", 0.15)
+	viper.SetDefault("RISK_WEIGHT_GOODS", 0.3)
+	viper.SetDefault("RISK_WEIGHT_SEASONALITY", 0)
+	viper.SetDefault("SESSION_INACTIVITY_TIMEOUT", "72h")
+	viper.SetDefault("SESSION_CLEANUP_INTERVAL", "1h")
+	viper.SetDefault("SESSION_MAX_CUSTOMER", 5)
+	viper.SetDefault("SESSION_MAX_PROVIDER", 10)
+	viper.SetDefault("SESSION_MAX_SHIPPER", 2)
+	viper.SetDefault("SESSION_MAX_ADMIN", 3)
+	viper.SetDefault("SMS_TIMEOUT", "10s")
+	viper.SetDefault("INVOICE_SLA_PENALTY_PERCENT_PER_DAY", 2)
+	viper.SetDefault("INVOICE_SLA_PENALTY_MAX_PERCENT", 20)
+	viper.SetDefault("INVOICE_CANCELLATION_FEE_PERCENT", 10)
+	viper.SetDefault("INVOICE_DEFAULT_CURRENCY", "VND")
+	viper.SetDefault("INVOICE_PAYMENT_TERMS_DAYS", 30)
+	viper.SetDefault("INVOICE_SHIPPER_FEE_PERCENT", 70)
+	viper.SetDefault("INVOICE_SHIPPER_BONUS_PERCENT", 5)
+	viper.SetDefault("STATS_CACHE_TTL", "30s")
+	viper.SetDefault("STATS_CACHE_STALE", "2m")
+	viper.SetDefault("STATS_CACHE_REFRESH_INTERVAL", "25s")
+	viper.SetDefault("CURRENCY_REPORTING", "VND")
+	viper.SetDefault("CURRENCY_RATE_SOURCE", "static")
+	viper.SetDefault("CURRENCY_RATES_TTL", "1h")
+	viper.SetDefault("CURRENCY_RATES_TIMEOUT", "10s")
+	viper.SetDefault("CLEANUP_ENABLED", false)
+	viper.SetDefault("CLEANUP_BATCH_SIZE", 100)
+	viper.SetDefault("CLEANUP_BATCH_PAUSE", "500ms")
+	viper.SetDefault("JOBS_WORKERS", 2)
+	viper.SetDefault("JOBS_POLL_INTERVAL", "2s")
+	viper.SetDefault("JOBS_LEASE", "5m")
+	viper.SetDefault("JOBS_RETENTION", "168h")
+	viper.SetDefault("REPORTS_POLL_INTERVAL", "1m")
+	viper.SetDefault("REPORTS_SEND_HOUR", 7)
+	viper.SetDefault("WATCHDOG_INTERVAL", "15m")
+	viper.SetDefault("WATCHDOG_DEMAND_CREATED_AFTER", "72h")
+	viper.SetDefault("WATCHDOG_ORDER_POSTED_AFTER", "48h")
+	viper.SetDefault("WATCHDOG_SHIPPING_ASSIGNED_AFTER", "24h")
+	viper.SetDefault("WATCHDOG_ISSUE_REPORTED_AFTER", "48h")
+	viper.SetDefault("WATCHDOG_ESCALATE_AFTER", "24h")
+	viper.SetDefault("WATCHDOG_REVERT_ASSIGNMENTS", false)
+	viper.SetDefault("WATCHDOG_RELEASE_STALE_DEVICES", true)
+
+	viper.SetDefault("SHIPMENT_EVENTS_ENABLED", false)
+
+	viper.SetDefault("FLEET_TARGET_FIRMWARE", "")
+	viper.SetDefault("FLEET_LOW_BATTERY_PERCENT", 20)
+	viper.SetDefault("FLEET_CRITICAL_BATTERY_PERCENT", 10)
+
+	viper.SetDefault("SELF_TEST_REQUIRED_FOR_ACCEPT", false)
+	viper.SetDefault("SELF_TEST_MAX_AGE", "24h")
+	viper.SetDefault("SELF_TEST_MIN_SIGNAL_DBM", -110)
+
+	viper.SetDefault("API_V1_DEPRECATED_AT", "")
+	viper.SetDefault("API_V1_SUNSET_AT", "")
+
+	viper.SetDefault("QUOTA_ENABLED", false)
+	viper.SetDefault("QUOTA_SIGNUP_PLAN", "free")
+	viper.SetDefault("QUOTA_FREE_ACTIVE_SHIPMENTS", 5)
+	viper.SetDefault("QUOTA_FREE_DEVICES", 2)
+	viper.SetDefault("QUOTA_FREE_API_CALLS", 10000)
+	viper.SetDefault("QUOTA_FREE_PREDICTIVE_ALERTS", false)
+	viper.SetDefault("QUOTA_FREE_WEBHOOKS", false)
+	viper.SetDefault("QUOTA_PRO_ACTIVE_SHIPMENTS", 100)
+	viper.SetDefault("QUOTA_PRO_DEVICES", 25)
+	viper.SetDefault("QUOTA_PRO_API_CALLS", 250000)
+	viper.SetDefault("QUOTA_PRO_PREDICTIVE_ALERTS", true)
+	viper.SetDefault("QUOTA_PRO_WEBHOOKS", true)
+	viper.SetDefault("QUOTA_ENTERPRISE_ACTIVE_SHIPMENTS", 0)
+	viper.SetDefault("QUOTA_ENTERPRISE_DEVICES", 0)
+	viper.SetDefault("QUOTA_ENTERPRISE_API_CALLS", 0)
+	viper.SetDefault("QUOTA_ENTERPRISE_PREDICTIVE_ALERTS", true)
+	viper.SetDefault("QUOTA_ENTERPRISE_WEBHOOKS", true)
+
 	if err := viper.ReadInConfig(); err != nil {
 		var configFileNotFoundError viper.ConfigFileNotFoundError
 		if !errors.As(err, &configFileNotFoundError) {
@@ -77,11 +517,25 @@ func Load() (*Config, error) {
 		log.Printf("Warning: config file not found: %v. Falling back to environment variables only.", err)
 	}
 
+	v1DeprecatedAt, err := parseDate("API_V1_DEPRECATED_AT")
+	if err != nil {
+		return nil, err
+	}
+	v1SunsetAt, err := parseDate("API_V1_SUNSET_AT")
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port:        viper.GetString("SERVER_PORT"),
 			Host:        viper.GetString("SERVER_HOST"),
 			Environment: viper.GetString("ENVIRONMENT"),
+
+			ReadTimeout:    viper.GetDuration("SERVER_READ_TIMEOUT"),
+			WriteTimeout:   viper.GetDuration("SERVER_WRITE_TIMEOUT"),
+			IdleTimeout:    viper.GetDuration("SERVER_IDLE_TIMEOUT"),
+			RequestTimeout: viper.GetDuration("SERVER_REQUEST_TIMEOUT"),
 		},
 		Database: DatabaseConfig{
 			Host:     viper.GetString("DB_HOST"),
@@ -90,6 +544,16 @@ func Load() (*Config, error) {
 			Password: viper.GetString("DB_PASSWORD"),
 			DBName:   viper.GetString("DB_NAME"),
 			SSLMode:  viper.GetString("DB_SSLMODE"),
+
+			MaxOpenConns:       viper.GetInt("DB_MAX_OPEN_CONNS"),
+			MaxIdleConns:       viper.GetInt("DB_MAX_IDLE_CONNS"),
+			ConnMaxLifetime:    viper.GetDuration("DB_CONN_MAX_LIFETIME"),
+			ConnMaxIdleTime:    viper.GetDuration("DB_CONN_MAX_IDLE_TIME"),
+			PrepareStmt:        viper.GetBool("DB_PREPARE_STMT"),
+			SlowQueryThreshold: viper.GetDuration("DB_SLOW_QUERY_THRESHOLD"),
+
+			BreakerThreshold:     viper.GetInt("DB_BREAKER_THRESHOLD"),
+			BreakerProbeInterval: viper.GetDuration("DB_BREAKER_PROBE_INTERVAL"),
 		},
 		JWT: JWTConfig{
 			Secret:             viper.GetString("JWT_SECRET"),
@@ -115,14 +579,225 @@ func Load() (*Config, error) {
 			AllowCredentials: viper.GetBool("CORS_ALLOW_CREDENTIALS"),
 			MaxAge:           viper.GetInt("CORS_MAX_AGE"),
 		},
+		Compression: CompressionConfig{
+			Enabled:     viper.GetBool("COMPRESSION_ENABLED"),
+			Level:       viper.GetInt("COMPRESSION_LEVEL"),
+			BrotliLevel: viper.GetInt("COMPRESSION_BROTLI_LEVEL"),
+			MinSize:     viper.GetInt("COMPRESSION_MIN_SIZE"),
+		},
+		Request: RequestLimitConfig{
+			MaxBodyBytes:   viper.GetInt64("REQUEST_MAX_BODY_BYTES"),
+			MaxUploadBytes: viper.GetInt64("REQUEST_MAX_UPLOAD_BYTES"),
+			MaxBulkBytes:   viper.GetInt64("REQUEST_MAX_BULK_BYTES"),
+		},
+		Secrets: SecretsConfig{
+			VaultAddress:       viper.GetString("VAULT_ADDR"),
+			VaultToken:         viper.GetString("VAULT_TOKEN"),
+			VaultNamespace:     viper.GetString("VAULT_NAMESPACE"),
+			AWSRegion:          viper.GetString("AWS_REGION"),
+			AWSAccessKeyID:     viper.GetString("AWS_ACCESS_KEY_ID"),
+			AWSSecretAccessKey: viper.GetString("AWS_SECRET_ACCESS_KEY"),
+			AWSSessionToken:    viper.GetString("AWS_SESSION_TOKEN"),
+			RefreshInterval:    viper.GetDuration("SECRETS_REFRESH_INTERVAL"),
+		},
+		Encryption: EncryptionConfig{
+			Keys:        viper.GetString("ENCRYPTION_KEYS"),
+			ActiveKeyID: viper.GetString("ENCRYPTION_ACTIVE_KEY_ID"),
+			IndexKey:    viper.GetString("ENCRYPTION_INDEX_KEY"),
+		},
+		Storage: StorageConfig{
+			Backend:   viper.GetString("STORAGE_BACKEND"),
+			LocalPath: viper.GetString("STORAGE_LOCAL_PATH"),
+		},
+		Notification: NotificationConfig{
+			DefaultLanguage:    viper.GetString("NOTIFICATION_DEFAULT_LANGUAGE"),
+			AppURL:             viper.GetString("NOTIFICATION_APP_URL"),
+			WebhookTimeout:     viper.GetDuration("NOTIFICATION_WEBHOOK_TIMEOUT"),
+			WebhookMaxAttempts: viper.GetInt("NOTIFICATION_WEBHOOK_MAX_ATTEMPTS"),
+			DigestHour:         viper.GetInt("NOTIFICATION_DIGEST_HOUR"),
+
+			WebhookEventRetention: viper.GetDuration("NOTIFICATION_WEBHOOK_EVENT_RETENTION"),
+		},
+		ChatBot: ChatBotConfig{
+			TelegramBotToken:      viper.GetString("TELEGRAM_BOT_TOKEN"),
+			TelegramBotUsername:   viper.GetString("TELEGRAM_BOT_USERNAME"),
+			TelegramWebhookSecret: viper.GetString("TELEGRAM_WEBHOOK_SECRET"),
+			ZaloAppID:             viper.GetString("ZALO_APP_ID"),
+			ZaloOAID:              viper.GetString("ZALO_OA_ID"),
+			ZaloAccessToken:       viper.GetString("ZALO_OA_ACCESS_TOKEN"),
+			ZaloSecretKey:         viper.GetString("ZALO_OA_SECRET_KEY"),
+			LinkCodeTTL:           viper.GetDuration("CHAT_LINK_CODE_TTL"),
+		},
+		Push: PushConfig{
+			FCMCredentialsFile: viper.GetString("FCM_CREDENTIALS_FILE"),
+			APNsKeyFile:        viper.GetString("APNS_KEY_FILE"),
+			APNsKeyID:          viper.GetString("APNS_KEY_ID"),
+			APNsTeamID:         viper.GetString("APNS_TEAM_ID"),
+			APNsBundleID:       viper.GetString("APNS_BUNDLE_ID"),
+			APNsProduction:     viper.GetBool("APNS_PRODUCTION"),
+			TokenTTL:           viper.GetDuration("PUSH_TOKEN_TTL"),
+		},
+		Sandbox: SandboxConfig{
+			Enabled: viper.GetBool("SANDBOX_ENABLED"),
+		},
+		Risk: RiskConfig{
+			ReputationWeight:  viper.GetFloat64("RISK_WEIGHT_REPUTATION"),
+			DeviceWeight:      viper.GetFloat64("RISK_WEIGHT_DEVICE"),
+			RouteWeight:       viper.GetFloat64("RISK_WEIGHT_ROUTE"),
+			GoodsWeight:       viper.GetFloat64("RISK_WEIGHT_GOODS"),
+			SeasonalityWeight: viper.GetFloat64("RISK_WEIGHT_SEASONALITY"),
+		},
+		Session: SessionConfig{
+			InactivityTimeout:   viper.GetDuration("SESSION_INACTIVITY_TIMEOUT"),
+			CleanupInterval:     viper.GetDuration("SESSION_CLEANUP_INTERVAL"),
+			MaxSessionsCustomer: viper.GetInt("SESSION_MAX_CUSTOMER"),
+			MaxSessionsProvider: viper.GetInt("SESSION_MAX_PROVIDER"),
+			MaxSessionsShipper:  viper.GetInt("SESSION_MAX_SHIPPER"),
+			MaxSessionsAdmin:    viper.GetInt("SESSION_MAX_ADMIN"),
+		},
+		SMS: SMSConfig{
+			GatewayURL: viper.GetString("SMS_GATEWAY_URL"),
+			APIKey:     viper.GetString("SMS_API_KEY"),
+			SenderID:   viper.GetString("SMS_SENDER_ID"),
+			Timeout:    viper.GetDuration("SMS_TIMEOUT"),
+		},
+		Invoicing: InvoicingConfig{
+			SLAPenaltyPercentPerDay: viper.GetFloat64("INVOICE_SLA_PENALTY_PERCENT_PER_DAY"),
+			SLAPenaltyMaxPercent:    viper.GetFloat64("INVOICE_SLA_PENALTY_MAX_PERCENT"),
+			CancellationFeePercent:  viper.GetFloat64("INVOICE_CANCELLATION_FEE_PERCENT"),
+			DefaultCurrency:         viper.GetString("INVOICE_DEFAULT_CURRENCY"),
+			PaymentTermsDays:        viper.GetInt("INVOICE_PAYMENT_TERMS_DAYS"),
+			ShipperFeePercent:       viper.GetFloat64("INVOICE_SHIPPER_FEE_PERCENT"),
+			ShipperBonusPercent:     viper.GetFloat64("INVOICE_SHIPPER_BONUS_PERCENT"),
+		},
+		StatsCache: StatsCacheConfig{
+			TTL:             viper.GetDuration("STATS_CACHE_TTL"),
+			Stale:           viper.GetDuration("STATS_CACHE_STALE"),
+			RefreshInterval: viper.GetDuration("STATS_CACHE_REFRESH_INTERVAL"),
+		},
+		Currency: CurrencyConfig{
+			ReportingCurrency: viper.GetString("CURRENCY_REPORTING"),
+			RateSource:        viper.GetString("CURRENCY_RATE_SOURCE"),
+			StaticRates:       viper.GetString("CURRENCY_RATES"),
+			RatesURL:          viper.GetString("CURRENCY_RATES_URL"),
+			RatesTTL:          viper.GetDuration("CURRENCY_RATES_TTL"),
+			Timeout:           viper.GetDuration("CURRENCY_RATES_TIMEOUT"),
+		},
+		Cleanup: CleanupConfig{
+			Enabled:    viper.GetBool("CLEANUP_ENABLED"),
+			BatchSize:  viper.GetInt("CLEANUP_BATCH_SIZE"),
+			BatchPause: viper.GetDuration("CLEANUP_BATCH_PAUSE"),
+		},
+		Jobs: JobsConfig{
+			Workers:      viper.GetInt("JOBS_WORKERS"),
+			PollInterval: viper.GetDuration("JOBS_POLL_INTERVAL"),
+			Lease:        viper.GetDuration("JOBS_LEASE"),
+			Retention:    viper.GetDuration("JOBS_RETENTION"),
+		},
+		Reports: ReportsConfig{
+			PollInterval: viper.GetDuration("REPORTS_POLL_INTERVAL"),
+			SendHour:     viper.GetInt("REPORTS_SEND_HOUR"),
+		},
+		Watchdog: WatchdogConfig{
+			Interval:              viper.GetDuration("WATCHDOG_INTERVAL"),
+			DemandCreatedAfter:    viper.GetDuration("WATCHDOG_DEMAND_CREATED_AFTER"),
+			OrderPostedAfter:      viper.GetDuration("WATCHDOG_ORDER_POSTED_AFTER"),
+			ShippingAssignedAfter: viper.GetDuration("WATCHDOG_SHIPPING_ASSIGNED_AFTER"),
+			IssueReportedAfter:    viper.GetDuration("WATCHDOG_ISSUE_REPORTED_AFTER"),
+			EscalateAfter:         viper.GetDuration("WATCHDOG_ESCALATE_AFTER"),
+			RevertAssignments:     viper.GetBool("WATCHDOG_REVERT_ASSIGNMENTS"),
+			ReleaseStaleDevices:   viper.GetBool("WATCHDOG_RELEASE_STALE_DEVICES"),
+		},
+		Events: ShipmentEventsConfig{
+			Enabled: viper.GetBool("SHIPMENT_EVENTS_ENABLED"),
+		},
+		Quota: QuotaConfig{
+			Enabled:    viper.GetBool("QUOTA_ENABLED"),
+			SignupPlan: viper.GetString("QUOTA_SIGNUP_PLAN"),
+			Free:       loadPlanConfig("QUOTA_FREE_"),
+			Pro:        loadPlanConfig("QUOTA_PRO_"),
+			Enterprise: loadPlanConfig("QUOTA_ENTERPRISE_"),
+		},
+		Fleet: FleetConfig{
+			TargetFirmware:         viper.GetString("FLEET_TARGET_FIRMWARE"),
+			LowBatteryPercent:      viper.GetInt("FLEET_LOW_BATTERY_PERCENT"),
+			CriticalBatteryPercent: viper.GetInt("FLEET_CRITICAL_BATTERY_PERCENT"),
+		},
+		SelfTest: SelfTestConfig{
+			RequiredForAccept: viper.GetBool("SELF_TEST_REQUIRED_FOR_ACCEPT"),
+			MaxAge:            viper.GetDuration("SELF_TEST_MAX_AGE"),
+			MinSignalDBm:      viper.GetInt("SELF_TEST_MIN_SIGNAL_DBM"),
+		},
+		APIVersions: APIVersionsConfig{
+			V1DeprecatedAt: v1DeprecatedAt,
+			V1SunsetAt:     v1SunsetAt,
+		},
 	}
 
 	return config, nil
 }
 
+// parseDate reads an optional date (2006-01-02 or RFC 3339) from key
+func parseDate(key string) (*time.Time, error) {
+	value := strings.TrimSpace(viper.GetString(key))
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s %q: expected YYYY-MM-DD or RFC 3339", key, value)
+}
+
+// loadPlanConfig reads the settings of one plan from the keys under prefix
+func loadPlanConfig(prefix string) PlanConfig {
+	return PlanConfig{
+		ActiveShipments:  viper.GetInt64(prefix + "ACTIVE_SHIPMENTS"),
+		Devices:          viper.GetInt64(prefix + "DEVICES"),
+		APICalls:         viper.GetInt64(prefix + "API_CALLS"),
+		PredictiveAlerts: viper.GetBool(prefix + "PREDICTIVE_ALERTS"),
+		Webhooks:         viper.GetBool(prefix + "WEBHOOKS"),
+	}
+}
+
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode,
 	)
 }
+
+// ResolveSecrets replaces secret references in sensitive settings with the
+// values fetched through r. Plain values are left untouched. The values are
+// read once; see SecretsConfig for which ones are refreshed afterwards.
+func (c *Config) ResolveSecrets(ctx context.Context, r SecretResolver) error {
+	fields := map[string]*string{
+		"DB_HOST":       &c.Database.Host,
+		"DB_USER":       &c.Database.User,
+		"DB_PASSWORD":   &c.Database.Password,
+		"DB_NAME":       &c.Database.DBName,
+		"JWT_SECRET":    &c.JWT.Secret,
+		"SMTP_PASSWORD": &c.SMTP.Password,
+		"SMS_API_KEY":   &c.SMS.APIKey,
+
+		"ENCRYPTION_KEYS":      &c.Encryption.Keys,
+		"ENCRYPTION_INDEX_KEY": &c.Encryption.IndexKey,
+
+		"TELEGRAM_BOT_TOKEN":      &c.ChatBot.TelegramBotToken,
+		"TELEGRAM_WEBHOOK_SECRET": &c.ChatBot.TelegramWebhookSecret,
+		"ZALO_OA_ACCESS_TOKEN":    &c.ChatBot.ZaloAccessToken,
+		"ZALO_OA_SECRET_KEY":      &c.ChatBot.ZaloSecretKey,
+	}
+
+	for name, field := range fields {
+		value, err := r.Resolve(ctx, *field)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+		*field = value
+	}
+
+	return nil
+}
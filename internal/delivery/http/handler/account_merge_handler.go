@@ -0,0 +1,98 @@
+package handler
+
+import (
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/usecase/user"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AccountMergeHandler struct {
+	service *user.AccountMergeService
+}
+
+func NewAccountMergeHandler(service *user.AccountMergeService) *AccountMergeHandler {
+	return &AccountMergeHandler{service: service}
+}
+
+func (h *AccountMergeHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	merges := router.Group("/account-merges")
+	{
+		merges.POST("/preview", h.PreviewMerge)
+		merges.POST("", h.MergeAccounts)
+		merges.GET("/:duplicateId", h.GetMerge)
+	}
+}
+
+func (h *AccountMergeHandler) PreviewMerge(c *gin.Context) {
+	var req user.MergeAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.PreviewMerge(c.Request.Context(), &req)
+	if err != nil {
+		respondWithMergeError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Merge preview generated successfully", result)
+}
+
+func (h *AccountMergeHandler) MergeAccounts(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	var req user.MergeAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.MergeAccounts(c.Request.Context(), adminID, &req)
+	if err != nil {
+		respondWithMergeError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Accounts merged successfully", result)
+}
+
+func (h *AccountMergeHandler) GetMerge(c *gin.Context) {
+	duplicateID, err := uuid.Parse(c.Param("duplicateId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	result, err := h.service.GetMerge(c.Request.Context(), duplicateID)
+	if err != nil {
+		respondWithMergeError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account merge retrieved successfully", result)
+}
+
+func respondWithMergeError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainUser.ErrUserNotFound),
+		errors.Is(err, appErrors.ErrUserNotFound),
+		errors.Is(err, domainUser.ErrMergeNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainUser.ErrAlreadyMerged):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "CONFIRMATION_MISMATCH":
+		utils.ErrorResponse(c, http.StatusConflict, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process account merge")
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/usecase/user"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AddressBookHandler struct {
+	service *user.AddressBookService
+}
+
+func NewAddressBookHandler(service *user.AddressBookService) *AddressBookHandler {
+	return &AddressBookHandler{service: service}
+}
+
+func (h *AddressBookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	addresses := router.Group("/profile/addresses")
+	{
+		addresses.GET("", h.ListAddresses)
+		addresses.POST("", h.CreateAddress)
+		addresses.GET("/:id", h.GetAddress)
+		addresses.PUT("/:id", h.UpdateAddress)
+		addresses.DELETE("/:id", h.DeleteAddress)
+	}
+}
+
+func (h *AddressBookHandler) ListAddresses(c *gin.Context) {
+	ownerID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListAddresses(c.Request.Context(), ownerID)
+	if err != nil {
+		respondWithAddressError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Addresses retrieved successfully", result)
+}
+
+func (h *AddressBookHandler) GetAddress(c *gin.Context) {
+	ownerID := c.MustGet("userID").(uuid.UUID)
+
+	addressID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid address ID")
+		return
+	}
+
+	result, err := h.service.GetAddress(c.Request.Context(), ownerID, addressID)
+	if err != nil {
+		respondWithAddressError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Address retrieved successfully", result)
+}
+
+func (h *AddressBookHandler) CreateAddress(c *gin.Context) {
+	ownerID := c.MustGet("userID").(uuid.UUID)
+
+	var req user.SaveAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	sanitizeAddressRequest(&req)
+
+	result, err := h.service.CreateAddress(c.Request.Context(), ownerID, &req)
+	if err != nil {
+		respondWithAddressError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Address saved successfully", result)
+}
+
+func (h *AddressBookHandler) UpdateAddress(c *gin.Context) {
+	ownerID := c.MustGet("userID").(uuid.UUID)
+
+	addressID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid address ID")
+		return
+	}
+
+	var req user.SaveAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	sanitizeAddressRequest(&req)
+
+	result, err := h.service.UpdateAddress(c.Request.Context(), ownerID, addressID, &req)
+	if err != nil {
+		respondWithAddressError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Address updated successfully", result)
+}
+
+func (h *AddressBookHandler) DeleteAddress(c *gin.Context) {
+	ownerID := c.MustGet("userID").(uuid.UUID)
+
+	addressID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid address ID")
+		return
+	}
+
+	if err := h.service.DeleteAddress(c.Request.Context(), ownerID, addressID); err != nil {
+		respondWithAddressError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Address deleted successfully", nil)
+}
+
+func sanitizeAddressRequest(req *user.SaveAddressRequest) {
+	req.Label = utils.SanitizeString(req.Label)
+	req.Line1 = utils.SanitizeString(req.Line1)
+	req.City = utils.SanitizeString(req.City)
+	if req.ContactName != nil {
+		sanitized := utils.SanitizeString(*req.ContactName)
+		req.ContactName = &sanitized
+	}
+	if req.ContactPhone != nil {
+		sanitized := utils.SanitizePhone(*req.ContactPhone)
+		req.ContactPhone = &sanitized
+	}
+}
+
+func respondWithAddressError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainUser.ErrAddressNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainUser.ErrAddressLabelTaken):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process address request")
+	}
+}
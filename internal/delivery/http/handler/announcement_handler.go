@@ -0,0 +1,156 @@
+package handler
+
+import (
+	domainAnnouncement "cargo-tracker/internal/domain/announcement"
+	"cargo-tracker/internal/usecase/announcement"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AnnouncementHandler struct {
+	service *announcement.Service
+}
+
+func NewAnnouncementHandler(service *announcement.Service) *AnnouncementHandler {
+	return &AnnouncementHandler{service: service}
+}
+
+// RegisterRoutes registers the banner endpoint clients poll without signing in
+func (h *AnnouncementHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/announcements", h.ActiveBanners)
+}
+
+func (h *AnnouncementHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	announcements := router.Group("/announcements")
+	{
+		announcements.POST("", h.CreateAnnouncement)
+		announcements.GET("", h.ListAnnouncements)
+		announcements.PUT("/:id", h.UpdateAnnouncement)
+		announcements.POST("/:id/end", h.EndAnnouncement)
+		announcements.DELETE("/:id", h.DeleteAnnouncement)
+	}
+}
+
+func (h *AnnouncementHandler) ActiveBanners(c *gin.Context) {
+	result, err := h.service.ActiveBanners(c.Request.Context())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve announcements")
+		return
+	}
+
+	// Short enough for a new maintenance notice to reach clients quickly
+	c.Header("Cache-Control", "public, max-age=60")
+	utils.SuccessResponse(c, http.StatusOK, "Announcements retrieved successfully", result)
+}
+
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	var req announcement.CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.CreateAnnouncement(c.Request.Context(), adminID, &req)
+	if err != nil {
+		respondWithAnnouncementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Announcement created successfully", result)
+}
+
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	var query announcement.AnnouncementQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.ListAnnouncements(c.Request.Context(), &query)
+	if err != nil {
+		respondWithAnnouncementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Announcements retrieved successfully", result)
+}
+
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	announcementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid announcement ID")
+		return
+	}
+
+	var req announcement.UpdateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.UpdateAnnouncement(c.Request.Context(), adminID, announcementID, &req)
+	if err != nil {
+		respondWithAnnouncementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Announcement updated successfully", result)
+}
+
+func (h *AnnouncementHandler) EndAnnouncement(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	announcementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid announcement ID")
+		return
+	}
+
+	result, err := h.service.EndAnnouncement(c.Request.Context(), adminID, announcementID)
+	if err != nil {
+		respondWithAnnouncementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Announcement ended successfully", result)
+}
+
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	announcementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid announcement ID")
+		return
+	}
+
+	if err := h.service.DeleteAnnouncement(c.Request.Context(), adminID, announcementID); err != nil {
+		respondWithAnnouncementError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Announcement deleted successfully", nil)
+}
+
+func respondWithAnnouncementError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainAnnouncement.ErrAnnouncementNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "ANNOUNCEMENT_EXPIRED":
+		utils.ErrorResponse(c, http.StatusConflict, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process announcement request")
+	}
+}
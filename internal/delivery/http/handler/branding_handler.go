@@ -0,0 +1,171 @@
+package handler
+
+import (
+	domainStorage "cargo-tracker/internal/domain/storage"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/usecase/user"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BrandingHandler struct {
+	service *user.BrandingService
+}
+
+func NewBrandingHandler(service *user.BrandingService) *BrandingHandler {
+	return &BrandingHandler{service: service}
+}
+
+// RegisterRoutes registers the public branding lookups used by tracking pages.
+func (h *BrandingHandler) RegisterRoutes(router *gin.RouterGroup) {
+	branding := router.Group("/branding")
+	{
+		branding.GET("/:providerId", h.GetProviderBranding)
+		branding.GET("/:providerId/logo", h.GetLogo)
+	}
+}
+
+func (h *BrandingHandler) RegisterProviderRoutes(router *gin.RouterGroup) {
+	branding := router.Group("/profile/branding")
+	{
+		branding.GET("", h.GetBranding)
+		branding.PUT("", h.UpdateBranding)
+		branding.PUT("/logo", h.UploadLogo)
+		branding.DELETE("/logo", h.DeleteLogo)
+	}
+}
+
+func (h *BrandingHandler) GetProviderBranding(c *gin.Context) {
+	providerID, err := uuid.Parse(c.Param("providerId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid provider ID")
+		return
+	}
+
+	result, err := h.service.GetBranding(c.Request.Context(), providerID)
+	if err != nil {
+		respondWithBrandingError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branding retrieved successfully", result)
+}
+
+func (h *BrandingHandler) GetLogo(c *gin.Context) {
+	providerID, err := uuid.Parse(c.Param("providerId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid provider ID")
+		return
+	}
+
+	content, contentType, err := h.service.OpenLogo(c.Request.Context(), providerID)
+	if err != nil {
+		respondWithBrandingError(c, err)
+		return
+	}
+	defer content.Close()
+
+	// Logo URLs change on every upload, so they can be cached for long
+	c.DataFromReader(http.StatusOK, -1, contentType, content, map[string]string{
+		"Cache-Control": "public, max-age=86400",
+	})
+}
+
+func (h *BrandingHandler) GetBranding(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.GetBranding(c.Request.Context(), providerID)
+	if err != nil {
+		respondWithBrandingError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branding retrieved successfully", result)
+}
+
+func (h *BrandingHandler) UpdateBranding(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	var req user.UpdateBrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.DisplayName != nil {
+		sanitized := utils.SanitizeString(*req.DisplayName)
+		req.DisplayName = &sanitized
+	}
+	if req.SupportPhone != nil {
+		sanitized := utils.SanitizePhone(*req.SupportPhone)
+		req.SupportPhone = &sanitized
+	}
+	if req.SupportEmail != nil {
+		sanitized := utils.SanitizeEmail(*req.SupportEmail)
+		req.SupportEmail = &sanitized
+	}
+
+	result, err := h.service.UpdateBranding(c.Request.Context(), providerID, &req)
+	if err != nil {
+		respondWithBrandingError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branding updated successfully", result)
+}
+
+func (h *BrandingHandler) UploadLogo(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File is required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read uploaded file")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.UploadLogo(c.Request.Context(), providerID, file)
+	if err != nil {
+		respondWithBrandingError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logo uploaded successfully", result)
+}
+
+func (h *BrandingHandler) DeleteLogo(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.service.DeleteLogo(c.Request.Context(), providerID); err != nil {
+		respondWithBrandingError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logo removed successfully", nil)
+}
+
+func respondWithBrandingError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainUser.ErrBrandingNotFound),
+		errors.Is(err, domainUser.ErrUserNotFound),
+		errors.Is(err, domainStorage.ErrObjectNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNSUPPORTED_FILE_TYPE":
+		utils.ErrorResponse(c, http.StatusUnsupportedMediaType, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process branding request")
+	}
+}
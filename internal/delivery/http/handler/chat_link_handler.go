@@ -0,0 +1,140 @@
+package handler
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/usecase/notification"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// webhookSignatureHeaders names the header carrying each bot's webhook signature
+var webhookSignatureHeaders = map[domainNotification.ChatProvider]string{
+	domainNotification.ChatTelegram: "X-Telegram-Bot-Api-Secret-Token",
+	domainNotification.ChatZalo:     "X-ZEvent-Signature",
+}
+
+type ChatLinkHandler struct {
+	service *notification.ChatLinkService
+}
+
+func NewChatLinkHandler(service *notification.ChatLinkService) *ChatLinkHandler {
+	return &ChatLinkHandler{service: service}
+}
+
+// RegisterRoutes registers the public bot webhook endpoints
+func (h *ChatLinkHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/integrations/:provider/webhook", h.HandleBotWebhook)
+}
+
+func (h *ChatLinkHandler) RegisterProtectedRoutes(router *gin.RouterGroup) {
+	links := router.Group("/chat-links")
+	{
+		links.GET("", h.ListLinks)
+		links.POST("/code", h.CreateLinkCode)
+		links.GET("/deliveries", h.ListDeliveries)
+		links.DELETE("/:provider", h.Unlink)
+	}
+}
+
+func (h *ChatLinkHandler) ListLinks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListLinks(c.Request.Context(), userID)
+	if err != nil {
+		respondWithChatLinkError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Chat links retrieved successfully", result)
+}
+
+func (h *ChatLinkHandler) CreateLinkCode(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req notification.CreateLinkCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.CreateLinkCode(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondWithChatLinkError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Link code created successfully", result)
+}
+
+func (h *ChatLinkHandler) ListDeliveries(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	result, err := h.service.ListDeliveries(c.Request.Context(), userID, limit)
+	if err != nil {
+		respondWithChatLinkError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Chat deliveries retrieved successfully", result)
+}
+
+func (h *ChatLinkHandler) Unlink(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	provider := domainNotification.ChatProvider(c.Param("provider"))
+
+	if err := h.service.Unlink(c.Request.Context(), userID, provider); err != nil {
+		respondWithChatLinkError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Chat unlinked successfully", nil)
+}
+
+func (h *ChatLinkHandler) HandleBotWebhook(c *gin.Context) {
+	provider := domainNotification.ChatProvider(c.Param("provider"))
+	header, ok := webhookSignatureHeaders[provider]
+	if !ok {
+		utils.ErrorResponse(c, http.StatusNotFound, "Unknown messaging provider")
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.HandleWebhook(c.Request.Context(), provider, c.GetHeader(header), body); err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrUnauthorized):
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid webhook signature")
+		case errors.Is(err, domainNotification.ErrChatLinkNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "Messaging provider is not enabled")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process webhook")
+		}
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func respondWithChatLinkError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainNotification.ErrChatLinkNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process chat link request")
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	domainCleanup "cargo-tracker/internal/domain/cleanup"
+	"cargo-tracker/internal/usecase/cleanup"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CleanupHandler struct {
+	service *cleanup.Service
+}
+
+func NewCleanupHandler(service *cleanup.Service) *CleanupHandler {
+	return &CleanupHandler{service: service}
+}
+
+func (h *CleanupHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	jobs := router.Group("/cleanup/jobs")
+	{
+		jobs.POST("", h.StartJob)
+		jobs.GET("", h.ListJobs)
+		jobs.GET("/:id", h.GetJob)
+		jobs.POST("/:id/cancel", h.CancelJob)
+	}
+}
+
+func (h *CleanupHandler) StartJob(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	var req cleanup.StartJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.StartJob(c.Request.Context(), adminID, &req)
+	if err != nil {
+		respondWithCleanupError(c, err)
+		return
+	}
+
+	if result.DryRun {
+		utils.SuccessResponse(c, http.StatusOK, "Cleanup dry run completed", result)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusAccepted, "Cleanup job started", result)
+}
+
+func (h *CleanupHandler) ListJobs(c *gin.Context) {
+	result, err := h.service.ListJobs(c.Request.Context())
+	if err != nil {
+		respondWithCleanupError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cleanup jobs retrieved successfully", result)
+}
+
+func (h *CleanupHandler) GetJob(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	result, err := h.service.GetJob(c.Request.Context(), jobID)
+	if err != nil {
+		respondWithCleanupError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cleanup job retrieved successfully", result)
+}
+
+func (h *CleanupHandler) CancelJob(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	result, err := h.service.CancelJob(c.Request.Context(), jobID)
+	if err != nil {
+		respondWithCleanupError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Cleanup job cancellation requested", result)
+}
+
+func respondWithCleanupError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainCleanup.ErrJobNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainCleanup.ErrDisabled):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, domainCleanup.ErrJobRunning):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "JOB_FINISHED":
+		utils.ErrorResponse(c, http.StatusConflict, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process cleanup job")
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	"cargo-tracker/internal/usecase/device"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceDecommissionHandler struct {
+	service *device.DecommissionService
+}
+
+func NewDeviceDecommissionHandler(service *device.DecommissionService) *DeviceDecommissionHandler {
+	return &DeviceDecommissionHandler{service: service}
+}
+
+func (h *DeviceDecommissionHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	devices := router.Group("/devices")
+	{
+		devices.POST("/:id/decommission", h.Decommission)
+		devices.GET("/:id/decommission", h.GetCertificate)
+		devices.GET("/:id/decommission/archive", h.DownloadArchive)
+	}
+}
+
+func (h *DeviceDecommissionHandler) Decommission(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	var req device.DecommissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Reason = utils.SanitizeString(req.Reason)
+
+	result, err := h.service.Decommission(c.Request.Context(), deviceID, adminID, &req)
+	if err != nil {
+		respondWithDecommissionError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Device decommissioned successfully", result)
+}
+
+func (h *DeviceDecommissionHandler) GetCertificate(c *gin.Context) {
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	result, err := h.service.GetCertificate(c.Request.Context(), deviceID)
+	if err != nil {
+		respondWithDecommissionError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Decommission certificate retrieved successfully", result)
+}
+
+func (h *DeviceDecommissionHandler) DownloadArchive(c *gin.Context) {
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	content, certificate, err := h.service.OpenArchive(c.Request.Context(), deviceID)
+	if err != nil {
+		respondWithDecommissionError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, certificate.ArchiveSize, "application/json", content, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="device-%s-decommission.json"`, certificate.HardwareUID),
+		"Digest":              "sha-256=" + certificate.ArchiveSHA256,
+	})
+}
+
+func respondWithDecommissionError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainDevice.ErrDeviceNotFound),
+		errors.Is(err, domainDevice.ErrDecommissionNotFound),
+		errors.Is(err, domainStorage.ErrObjectNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainDevice.ErrAlreadyDecommissioned):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "DEVICE_IN_USE":
+		utils.ErrorResponse(c, http.StatusConflict, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process decommission request")
+	}
+}
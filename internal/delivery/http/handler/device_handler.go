@@ -1,9 +1,13 @@
 package handler
 
 import (
+	domainDevice "cargo-tracker/internal/domain/device"
 	"cargo-tracker/internal/usecase/device"
 	"cargo-tracker/pkg/utils"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -39,11 +43,29 @@ func (h *DeviceHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
 		devices.POST("/:id/unassign-owner", h.UnassignOwner)
 		devices.PUT("/:id/status", h.UpdateStatus)
 		devices.PUT("/:id/battery", h.UpdateBattery)
-		devices.POST("/bulk-assign", h.BulkAssignOwner)
+		devices.POST("/:id/credential", h.IssueCredential)
 		devices.GET("/statistics", h.GetStatistics)
 	}
 }
 
+func (h *DeviceHandler) RegisterAdminBulkRoutes(router *gin.RouterGroup) {
+	devices := router.Group("/devices")
+	{
+		// Admin-only bulk routes (larger request body limit)
+		devices.POST("/bulk-assign", h.BulkAssignOwner)
+	}
+}
+
+// RegisterFleetRoutes registers the fleet compliance report. Admins see
+// every device, shippers the devices they own.
+func (h *DeviceHandler) RegisterFleetRoutes(router *gin.RouterGroup) {
+	fleet := router.Group("/fleet")
+	{
+		fleet.GET("/compliance", h.GetFleetCompliance)
+		fleet.GET("/compliance/csv", h.DownloadFleetComplianceCSV)
+	}
+}
+
 func (h *DeviceHandler) CreateDevice(c *gin.Context) {
 	var req device.CreateDeviceRequest
 
@@ -54,6 +76,9 @@ func (h *DeviceHandler) CreateDevice(c *gin.Context) {
 
 	device, err := h.service.CreateDevice(c.Request.Context(), &req)
 	if err != nil {
+		if respondWithPlanLimit(c, err) {
+			return
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -147,6 +172,9 @@ func (h *DeviceHandler) AssignOwner(c *gin.Context) {
 
 	device, err := h.service.AssignOwner(c.Request.Context(), deviceID, &req)
 	if err != nil {
+		if respondWithPlanLimit(c, err) {
+			return
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -176,6 +204,31 @@ func (h *DeviceHandler) UnassignOwner(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Owner unassigned successfully", device)
 }
 
+// IssueCredential issues the secret the device authenticates to the device
+// API with, revoking the previous one
+func (h *DeviceHandler) IssueCredential(c *gin.Context) {
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	result, err := h.service.IssueCredential(c.Request.Context(), deviceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainDevice.ErrDeviceNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, domainDevice.ErrDeviceRetired):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to issue device credential")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Device credential issued successfully", result)
+}
+
 func (h *DeviceHandler) UpdateStatus(c *gin.Context) {
 	deviceID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -261,6 +314,49 @@ func (h *DeviceHandler) GetStatistics(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Statistics retrieved successfully", stats)
 }
 
+func (h *DeviceHandler) GetFleetCompliance(c *gin.Context) {
+	var req device.FleetComplianceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	report, err := h.service.GetFleetCompliance(c.Request.Context(), fleetOwner(c), &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fleet compliance retrieved successfully", report)
+}
+
+func (h *DeviceHandler) DownloadFleetComplianceCSV(c *gin.Context) {
+	var req device.FleetComplianceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	content, err := h.service.ExportFleetComplianceCSV(c.Request.Context(), fleetOwner(c), &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="fleet-compliance-%s.csv"`, time.Now().UTC().Format("2006-01-02")))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+}
+
+// fleetOwner limits the fleet to the caller's own devices unless they are
+// an admin
+func fleetOwner(c *gin.Context) *uuid.UUID {
+	if c.GetString("role") == "admin" {
+		return nil
+	}
+	userID := c.MustGet("userID").(uuid.UUID)
+	return &userID
+}
+
 func (h *DeviceHandler) GetAvailableDevices(c *gin.Context) {
 	var shipperID *uuid.UUID
 	if shipperIDStr := c.Query("shipper_id"); shipperIDStr != "" {
@@ -272,7 +368,14 @@ func (h *DeviceHandler) GetAvailableDevices(c *gin.Context) {
 		shipperID = &id
 	}
 
-	devices, err := h.service.GetAvailableDevices(c.Request.Context(), shipperID)
+	// Comma separated, e.g. the required_capabilities of a shipment's rules
+	capabilities, err := device.ParseCapabilities(c.Query("capabilities"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	devices, err := h.service.GetAvailableDevices(c.Request.Context(), shipperID, capabilities)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
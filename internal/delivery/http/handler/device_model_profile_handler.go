@@ -0,0 +1,85 @@
+package handler
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/usecase/device"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceModelProfileHandler struct {
+	service *device.ModelProfileService
+}
+
+func NewDeviceModelProfileHandler(service *device.ModelProfileService) *DeviceModelProfileHandler {
+	return &DeviceModelProfileHandler{service: service}
+}
+
+// RegisterRoutes lets any signed in user see which sensors each model has
+func (h *DeviceModelProfileHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/device-models", h.ListProfiles)
+}
+
+func (h *DeviceModelProfileHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	profiles := router.Group("/device-models")
+	{
+		profiles.PUT("/:model", h.SaveProfile)
+		profiles.DELETE("/:model", h.DeleteProfile)
+	}
+}
+
+func (h *DeviceModelProfileHandler) ListProfiles(c *gin.Context) {
+	result, err := h.service.ListProfiles(c.Request.Context())
+	if err != nil {
+		respondWithModelProfileError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device model profiles retrieved successfully", result)
+}
+
+func (h *DeviceModelProfileHandler) SaveProfile(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	var req device.UpsertModelProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.SaveProfile(c.Request.Context(), adminID, c.Param("model"), &req)
+	if err != nil {
+		respondWithModelProfileError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device model profile saved successfully", result)
+}
+
+func (h *DeviceModelProfileHandler) DeleteProfile(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.service.DeleteProfile(c.Request.Context(), adminID, c.Param("model")); err != nil {
+		respondWithModelProfileError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device model profile deleted successfully", nil)
+}
+
+func respondWithModelProfileError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainDevice.ErrModelProfileNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process device model request")
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/usecase/device"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceSelfTestHandler struct {
+	service *device.SelfTestService
+}
+
+func NewDeviceSelfTestHandler(service *device.SelfTestService) *DeviceSelfTestHandler {
+	return &DeviceSelfTestHandler{service: service}
+}
+
+// RegisterRoutes registers self-test requests and history. Admins reach
+// every device, shippers the devices they own.
+func (h *DeviceSelfTestHandler) RegisterRoutes(router *gin.RouterGroup) {
+	devices := router.Group("/devices")
+	{
+		devices.POST("/:id/self-tests", h.RequestSelfTest)
+		devices.GET("/:id/self-tests", h.ListSelfTests)
+	}
+}
+
+// RegisterDeviceRoutes registers the device API side: trackers poll for a
+// pending self-test and report its results
+func (h *DeviceSelfTestHandler) RegisterDeviceRoutes(router *gin.RouterGroup) {
+	ingest := router.Group("/ingest")
+	{
+		ingest.GET("/self-test", h.GetPendingSelfTest)
+		ingest.POST("/self-test", h.ReportSelfTest)
+	}
+}
+
+func (h *DeviceSelfTestHandler) RequestSelfTest(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	result, err := h.service.RequestSelfTest(c.Request.Context(), userID, fleetOwner(c), deviceID)
+	if err != nil {
+		respondWithSelfTestError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Device self-test requested successfully", result)
+}
+
+func (h *DeviceSelfTestHandler) ListSelfTests(c *gin.Context) {
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	result, err := h.service.ListSelfTests(c.Request.Context(), fleetOwner(c), deviceID)
+	if err != nil {
+		respondWithSelfTestError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device self-tests retrieved successfully", result)
+}
+
+func (h *DeviceSelfTestHandler) GetPendingSelfTest(c *gin.Context) {
+	deviceID := c.MustGet("deviceID").(uuid.UUID)
+
+	result, err := h.service.GetPendingSelfTest(c.Request.Context(), deviceID)
+	if err != nil {
+		respondWithSelfTestError(c, err)
+		return
+	}
+	if result == nil {
+		utils.SuccessResponse(c, http.StatusOK, "No pending self-test", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pending self-test retrieved successfully", result)
+}
+
+func (h *DeviceSelfTestHandler) ReportSelfTest(c *gin.Context) {
+	deviceID := c.MustGet("deviceID").(uuid.UUID)
+
+	var req device.SelfTestReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.ReportSelfTest(c.Request.Context(), deviceID, &req)
+	if err != nil {
+		respondWithSelfTestError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device self-test reported successfully", result)
+}
+
+func respondWithSelfTestError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainDevice.ErrDeviceNotFound),
+		errors.Is(err, domainDevice.ErrSelfTestNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainDevice.ErrSelfTestPending),
+		errors.Is(err, domainDevice.ErrDeviceRetired):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process device self-test")
+	}
+}
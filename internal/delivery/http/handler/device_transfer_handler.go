@@ -0,0 +1,125 @@
+package handler
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/usecase/device"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceTransferHandler struct {
+	service *device.TransferService
+}
+
+func NewDeviceTransferHandler(service *device.TransferService) *DeviceTransferHandler {
+	return &DeviceTransferHandler{service: service}
+}
+
+func (h *DeviceTransferHandler) RegisterShipperRoutes(router *gin.RouterGroup) {
+	router.POST("/devices/:id/transfers", h.InitiateTransfer)
+
+	transfers := router.Group("/device-transfers")
+	{
+		transfers.GET("", h.ListTransfers)
+		transfers.POST("/:transferId/accept", h.AcceptTransfer)
+		transfers.POST("/:transferId/decline", h.DeclineTransfer)
+		transfers.POST("/:transferId/cancel", h.CancelTransfer)
+	}
+}
+
+func (h *DeviceTransferHandler) InitiateTransfer(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	var req device.InitiateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.InitiateTransfer(c.Request.Context(), shipperID, deviceID, &req)
+	if err != nil {
+		respondWithTransferError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Device transfer initiated successfully", result)
+}
+
+func (h *DeviceTransferHandler) ListTransfers(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListTransfers(c.Request.Context(), shipperID)
+	if err != nil {
+		respondWithTransferError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device transfers retrieved successfully", result)
+}
+
+func (h *DeviceTransferHandler) AcceptTransfer(c *gin.Context) {
+	h.respond(c, h.service.AcceptTransfer, "Device transfer accepted successfully")
+}
+
+func (h *DeviceTransferHandler) DeclineTransfer(c *gin.Context) {
+	h.respond(c, h.service.DeclineTransfer, "Device transfer declined successfully")
+}
+
+func (h *DeviceTransferHandler) CancelTransfer(c *gin.Context) {
+	h.respond(c, h.service.CancelTransfer, "Device transfer cancelled successfully")
+}
+
+// respond runs an answer to a pending transfer
+func (h *DeviceTransferHandler) respond(c *gin.Context, action func(ctx context.Context, shipperID, transferID uuid.UUID) (*device.TransferResponse, error), message string) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	transferID, err := uuid.Parse(c.Param("transferId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid transfer ID")
+		return
+	}
+
+	result, err := action(c.Request.Context(), shipperID, transferID)
+	if err != nil {
+		respondWithTransferError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, message, result)
+}
+
+func respondWithTransferError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainDevice.ErrDeviceNotFound),
+		errors.Is(err, domainDevice.ErrTransferNotFound),
+		errors.Is(err, appErrors.ErrUserNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainDevice.ErrTransferPending),
+		errors.Is(err, domainDevice.ErrTransferNotPending),
+		errors.Is(err, domainDevice.ErrDeviceInUse):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr) && appErr.Code == "QUOTA_EXCEEDED":
+		utils.ErrorResponse(c, http.StatusTooManyRequests, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	case errors.Is(err, appErrors.ErrUserInactive):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process device transfer")
+	}
+}
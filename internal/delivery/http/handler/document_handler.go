@@ -0,0 +1,244 @@
+package handler
+
+import (
+	domainDocument "cargo-tracker/internal/domain/document"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	"cargo-tracker/internal/usecase/document"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DocumentHandler struct {
+	service *document.Service
+}
+
+func NewDocumentHandler(service *document.Service) *DocumentHandler {
+	return &DocumentHandler{service: service}
+}
+
+func (h *DocumentHandler) RegisterRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		// Shipment party routes
+		shipments.GET("/:id/documents", h.ListDocuments)
+		shipments.GET("/:id/documents/checklist", h.GetChecklist)
+		shipments.GET("/:id/documents/:documentId/download", h.DownloadDocument)
+		shipments.PUT("/:id/documents/:documentId/visibility", h.UpdateDocumentVisibility)
+	}
+}
+
+func (h *DocumentHandler) RegisterUploadRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		// Shipment party routes (larger request body limit)
+		shipments.POST("/:id/documents", h.UploadDocument)
+	}
+}
+
+func (h *DocumentHandler) RegisterProviderRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		// Provider routes
+		shipments.PUT("/:id/documents/checklist", h.SetChecklist)
+		shipments.POST("/:id/documents/checklist/:itemId/review", h.ReviewChecklistItem)
+	}
+}
+
+func (h *DocumentHandler) SetChecklist(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	providerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var req document.SetChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.SetChecklist(c.Request.Context(), providerID, shipmentID, &req)
+	if err != nil {
+		respondWithDocumentServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Document checklist updated successfully", result)
+}
+
+func (h *DocumentHandler) GetChecklist(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.GetChecklist(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithDocumentServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Document checklist retrieved successfully", result)
+}
+
+func (h *DocumentHandler) ReviewChecklistItem(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	providerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid checklist item ID")
+		return
+	}
+
+	var req document.ReviewChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.ReviewChecklistItem(c.Request.Context(), providerID, shipmentID, itemID, &req)
+	if err != nil {
+		respondWithDocumentServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Document reviewed successfully", result)
+}
+
+func (h *DocumentHandler) UploadDocument(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var req document.UploadDocumentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File is required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read uploaded file")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.UploadDocument(c.Request.Context(), userID, shipmentID, &req, fileHeader.Filename, file)
+	if err != nil {
+		respondWithDocumentServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Document uploaded successfully", result)
+}
+
+func (h *DocumentHandler) ListDocuments(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.ListAttachments(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithDocumentServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Documents retrieved successfully", result)
+}
+
+func (h *DocumentHandler) DownloadDocument(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+
+	attachment, content, err := h.service.OpenAttachment(c.Request.Context(), userID, shipmentID, documentID)
+	if err != nil {
+		respondWithDocumentServiceError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.SizeBytes, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, attachment.FileName),
+	})
+}
+
+func (h *DocumentHandler) UpdateDocumentVisibility(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+
+	var req document.VisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.UpdateVisibility(c.Request.Context(), userID, shipmentID, documentID, &req)
+	if err != nil {
+		respondWithDocumentServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Document visibility updated successfully", result)
+}
+
+func respondWithDocumentServiceError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound),
+		errors.Is(err, domainDocument.ErrChecklistItemNotFound),
+		errors.Is(err, domainDocument.ErrAttachmentNotFound),
+		errors.Is(err, domainStorage.ErrObjectNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr) && appErr.Code == "UNSUPPORTED_FILE_TYPE":
+		utils.ErrorResponse(c, http.StatusUnsupportedMediaType, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process document request")
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	domainInterop "cargo-tracker/internal/domain/interop"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/usecase/interop"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InteropHandler struct {
+	service *interop.Service
+}
+
+func NewInteropHandler(service *interop.Service) *InteropHandler {
+	return &InteropHandler{service: service}
+}
+
+// RegisterRoutes registers partner mapping management and milestone exports,
+// shared by customers and providers.
+func (h *InteropHandler) RegisterRoutes(router *gin.RouterGroup) {
+	partners := router.Group("/interop/partners")
+	{
+		partners.GET("", h.ListMappings)
+		partners.POST("", h.CreateMapping)
+		partners.GET("/:id", h.GetMapping)
+		partners.PUT("/:id", h.UpdateMapping)
+		partners.DELETE("/:id", h.DeleteMapping)
+		partners.GET("/:id/shipments/:shipmentId/milestones", h.ExportMilestones)
+	}
+}
+
+// RegisterImportRoutes registers shipment imports, which create demands on
+// behalf of the customer.
+func (h *InteropHandler) RegisterImportRoutes(router *gin.RouterGroup) {
+	partners := router.Group("/interop/partners")
+	{
+		partners.POST("/:id/import", h.Import)
+	}
+}
+
+func (h *InteropHandler) ListMappings(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListMappings(c.Request.Context(), userID)
+	if err != nil {
+		respondWithInteropError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Partner mappings retrieved successfully", result)
+}
+
+func (h *InteropHandler) GetMapping(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	mappingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid partner mapping ID")
+		return
+	}
+
+	result, err := h.service.GetMapping(c.Request.Context(), userID, mappingID)
+	if err != nil {
+		respondWithInteropError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Partner mapping retrieved successfully", result)
+}
+
+func (h *InteropHandler) CreateMapping(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req interop.SavePartnerMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Name = utils.SanitizeString(req.Name)
+
+	result, err := h.service.CreateMapping(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondWithInteropError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Partner mapping created successfully", result)
+}
+
+func (h *InteropHandler) UpdateMapping(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	mappingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid partner mapping ID")
+		return
+	}
+
+	var req interop.SavePartnerMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Name = utils.SanitizeString(req.Name)
+
+	result, err := h.service.UpdateMapping(c.Request.Context(), userID, mappingID, &req)
+	if err != nil {
+		respondWithInteropError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Partner mapping updated successfully", result)
+}
+
+func (h *InteropHandler) DeleteMapping(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	mappingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid partner mapping ID")
+		return
+	}
+
+	if err := h.service.DeleteMapping(c.Request.Context(), userID, mappingID); err != nil {
+		respondWithInteropError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Partner mapping deleted successfully", nil)
+}
+
+func (h *InteropHandler) Import(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	mappingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid partner mapping ID")
+		return
+	}
+
+	message, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body too large")
+		return
+	}
+
+	if wantsAsync(c) {
+		queued, err := h.service.QueueImport(c.Request.Context(), userID, mappingID, message)
+		if err != nil {
+			respondWithInteropError(c, err)
+			return
+		}
+		respondWithQueuedJob(c, queued)
+		return
+	}
+
+	result, err := h.service.Import(c.Request.Context(), userID, mappingID, message)
+	if err != nil {
+		respondWithInteropError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Imported > 0 && result.Failed == 0 {
+		status = http.StatusCreated
+	}
+	utils.SuccessResponse(c, status, "Shipment import processed", result)
+}
+
+// ExportMilestones returns the partner document as-is, without the usual
+// response envelope, so it can be forwarded unchanged.
+func (h *InteropHandler) ExportMilestones(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	mappingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid partner mapping ID")
+		return
+	}
+	shipmentID, err := uuid.Parse(c.Param("shipmentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.ExportMilestones(c.Request.Context(), userID, mappingID, shipmentID)
+	if err != nil {
+		respondWithInteropError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func respondWithInteropError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainInterop.ErrPartnerNotFound),
+		errors.Is(err, domainShipment.ErrShipmentNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainInterop.ErrPartnerInactive):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process interop request")
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/usecase/user"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvitationHandler struct {
+	service *user.InvitationService
+}
+
+func NewInvitationHandler(service *user.InvitationService) *InvitationHandler {
+	return &InvitationHandler{service: service}
+}
+
+func (h *InvitationHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	invitations := router.Group("/invitations")
+	{
+		invitations.POST("", h.CreateInvitation)
+		invitations.GET("", h.ListInvitations)
+		invitations.DELETE("/:id", h.RevokeInvitation)
+	}
+}
+
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	var req user.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.CreateInvitation(c.Request.Context(), adminID, &req)
+	if err != nil {
+		respondWithInvitationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Invitation created successfully", result)
+}
+
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	var query user.InvitationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.ListInvitations(c.Request.Context(), &query)
+	if err != nil {
+		respondWithInvitationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitations retrieved successfully", result)
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invitation ID")
+		return
+	}
+
+	if err := h.service.RevokeInvitation(c.Request.Context(), adminID, invitationID); err != nil {
+		respondWithInvitationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation revoked successfully", nil)
+}
+
+func respondWithInvitationError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainUser.ErrInvitationNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainUser.ErrInvitationUsed),
+		errors.Is(err, appErrors.ErrUserAlreadyExists):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process invitation")
+	}
+}
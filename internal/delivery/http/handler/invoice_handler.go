@@ -0,0 +1,337 @@
+package handler
+
+import (
+	domainInvoice "cargo-tracker/internal/domain/invoice"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/usecase/invoice"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvoiceHandler struct {
+	service *invoice.Service
+}
+
+func NewInvoiceHandler(service *invoice.Service) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+// RegisterRoutes registers the invoice routes shared by providers and customers
+func (h *InvoiceHandler) RegisterRoutes(router *gin.RouterGroup) {
+	invoices := router.Group("/invoices")
+	{
+		invoices.GET("", h.ListInvoices)
+		invoices.GET("/:id", h.GetInvoice)
+		invoices.GET("/:id/pdf", h.DownloadPDF)
+		invoices.GET("/:id/csv", h.DownloadCSV)
+	}
+}
+
+func (h *InvoiceHandler) RegisterProviderRoutes(router *gin.RouterGroup) {
+	invoices := router.Group("/invoices")
+	{
+		invoices.POST("/:id/adjustments", h.AddAdjustment)
+		invoices.PUT("/:id/payment", h.RecordPayment)
+		invoices.POST("/:id/void", h.VoidInvoice)
+	}
+}
+
+// RegisterShipperRoutes registers the earnings routes; shippers only see
+// their own figures
+func (h *InvoiceHandler) RegisterShipperRoutes(router *gin.RouterGroup) {
+	earnings := router.Group("/earnings")
+	{
+		earnings.GET("", h.GetEarnings)
+		earnings.GET("/summary", h.GetEarningsSummary)
+		earnings.GET("/csv", h.DownloadEarningsCSV)
+	}
+}
+
+func (h *InvoiceHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	router.POST("/invoices/close-period", h.ClosePeriod)
+	router.GET("/shippers/:id/earnings", h.GetShipperEarnings)
+}
+
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	userRole := c.MustGet("role").(string)
+
+	var req invoice.InvoiceFilterRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.ListInvoices(c.Request.Context(), userID, userRole, &req)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invoices retrieved successfully", result)
+}
+
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	result, err := h.service.GetInvoice(c.Request.Context(), userID, invoiceID)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invoice retrieved successfully", result)
+}
+
+func (h *InvoiceHandler) DownloadPDF(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	content, number, err := h.service.ExportPDF(c.Request.Context(), userID, invoiceID)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, number))
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+func (h *InvoiceHandler) DownloadCSV(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	content, number, err := h.service.ExportCSV(c.Request.Context(), userID, invoiceID)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, number))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+}
+
+func (h *InvoiceHandler) AddAdjustment(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	var req invoice.AdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Description = utils.SanitizeText(req.Description)
+
+	result, err := h.service.AddAdjustment(c.Request.Context(), providerID, invoiceID, &req)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Adjustment added successfully", result)
+}
+
+func (h *InvoiceHandler) RecordPayment(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	var req invoice.PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Reference != nil {
+		sanitized := utils.SanitizeString(*req.Reference)
+		req.Reference = &sanitized
+	}
+
+	result, err := h.service.RecordPayment(c.Request.Context(), providerID, invoiceID, &req)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment status updated successfully", result)
+}
+
+func (h *InvoiceHandler) VoidInvoice(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	result, err := h.service.VoidInvoice(c.Request.Context(), providerID, invoiceID)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invoice voided successfully", result)
+}
+
+func (h *InvoiceHandler) ClosePeriod(c *gin.Context) {
+	var req invoice.ClosePeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if wantsAsync(c) {
+		adminID := c.MustGet("userID").(uuid.UUID)
+		queued, err := h.service.QueueClosePeriod(c.Request.Context(), adminID, &req)
+		if err != nil {
+			respondWithInvoiceError(c, err)
+			return
+		}
+		respondWithQueuedJob(c, queued)
+		return
+	}
+
+	result, err := h.service.ClosePeriodByName(c.Request.Context(), &req)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Billing period closed successfully", result)
+}
+
+func (h *InvoiceHandler) GetEarnings(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	var req invoice.EarningsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.GetEarnings(c.Request.Context(), shipperID, &req)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Earnings retrieved successfully", result)
+}
+
+func (h *InvoiceHandler) GetEarningsSummary(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	var req invoice.EarningsSummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.GetEarningsSummary(c.Request.Context(), shipperID, &req)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Earnings summary retrieved successfully", result)
+}
+
+func (h *InvoiceHandler) DownloadEarningsCSV(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	var req invoice.EarningsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	if wantsAsync(c) {
+		queued, err := h.service.QueueEarningsCSV(c.Request.Context(), shipperID, &req)
+		if err != nil {
+			respondWithInvoiceError(c, err)
+			return
+		}
+		respondWithQueuedJob(c, queued)
+		return
+	}
+
+	content, name, err := h.service.ExportEarningsCSV(c.Request.Context(), shipperID, &req)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+}
+
+func (h *InvoiceHandler) GetShipperEarnings(c *gin.Context) {
+	shipperID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipper ID")
+		return
+	}
+
+	var req invoice.EarningsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.GetShipperEarnings(c.Request.Context(), shipperID, &req)
+	if err != nil {
+		respondWithInvoiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Earnings retrieved successfully", result)
+}
+
+func respondWithInvoiceError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainInvoice.ErrInvoiceNotFound),
+		errors.Is(err, domainUser.ErrUserNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainInvoice.ErrInvoiceClosed),
+		errors.Is(err, domainInvoice.ErrInvoiceExists):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process invoice request")
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	domainJob "cargo-tracker/internal/domain/job"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	"cargo-tracker/internal/usecase/job"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type JobHandler struct {
+	service *job.Service
+}
+
+func NewJobHandler(service *job.Service) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+func (h *JobHandler) RegisterRoutes(router *gin.RouterGroup) {
+	jobs := router.Group("/jobs")
+	{
+		jobs.GET("", h.ListJobs)
+		jobs.GET("/:id", h.GetJob)
+		jobs.GET("/:id/result", h.DownloadResult)
+	}
+}
+
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListJobs(c.Request.Context(), userID)
+	if err != nil {
+		respondWithJobError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Jobs retrieved successfully", result)
+}
+
+func (h *JobHandler) GetJob(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	userRole := c.MustGet("role").(string)
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	result, err := h.service.GetJob(c.Request.Context(), userID, userRole, jobID)
+	if err != nil {
+		respondWithJobError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Job retrieved successfully", result)
+}
+
+func (h *JobHandler) DownloadResult(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	userRole := c.MustGet("role").(string)
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	content, file, err := h.service.OpenResult(c.Request.Context(), userID, userRole, jobID)
+	if err != nil {
+		respondWithJobError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, file.SizeBytes, file.ContentType, content, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, file.FileName),
+	})
+}
+
+// wantsAsync reports whether the client asked for heavy work to run as a
+// background job instead of within the request
+func wantsAsync(c *gin.Context) bool {
+	return c.Query("async") == "true"
+}
+
+// respondWithQueuedJob answers a request whose work was moved to a job. The
+// Location header points at the job to poll.
+func respondWithQueuedJob(c *gin.Context, queued *job.JobResponse) {
+	c.Header("Location", fmt.Sprintf("/api/v1/jobs/%s", queued.ID))
+	utils.SuccessResponse(c, http.StatusAccepted, "Job queued", queued)
+}
+
+func respondWithJobError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainJob.ErrJobNotFound),
+		errors.Is(err, domainStorage.ErrObjectNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "JOB_NOT_FINISHED":
+		utils.ErrorResponse(c, http.StatusConflict, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process job request")
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/usecase/notification"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationTemplateHandler struct {
+	service *notification.Service
+}
+
+func NewNotificationTemplateHandler(service *notification.Service) *NotificationTemplateHandler {
+	return &NotificationTemplateHandler{service: service}
+}
+
+func (h *NotificationTemplateHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	templates := router.Group("/notification-templates")
+	{
+		templates.GET("", h.ListTemplates)
+		templates.POST("", h.SaveTemplate)
+		templates.GET("/events", h.ListEvents)
+		templates.GET("/versions", h.ListVersions)
+		templates.POST("/preview", h.Preview)
+		templates.GET("/:id", h.GetTemplate)
+		templates.POST("/:id/activate", h.ActivateVersion)
+	}
+}
+
+func (h *NotificationTemplateHandler) ListTemplates(c *gin.Context) {
+	result, err := h.service.ListTemplates(c.Request.Context())
+	if err != nil {
+		respondWithTemplateError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification templates retrieved successfully", result)
+}
+
+func (h *NotificationTemplateHandler) ListEvents(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Notification events retrieved successfully", notification.EventCatalog())
+}
+
+func (h *NotificationTemplateHandler) ListVersions(c *gin.Context) {
+	var req notification.TemplateVersionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.ListVersions(c.Request.Context(), &req)
+	if err != nil {
+		respondWithTemplateError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Template versions retrieved successfully", result)
+}
+
+func (h *NotificationTemplateHandler) GetTemplate(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	result, err := h.service.GetTemplate(c.Request.Context(), templateID)
+	if err != nil {
+		respondWithTemplateError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification template retrieved successfully", result)
+}
+
+func (h *NotificationTemplateHandler) SaveTemplate(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	var req notification.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Event = utils.SanitizeString(req.Event)
+	req.Language = utils.SanitizeString(req.Language)
+
+	result, err := h.service.SaveTemplate(c.Request.Context(), adminID, &req)
+	if err != nil {
+		respondWithTemplateError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Notification template saved successfully", result)
+}
+
+func (h *NotificationTemplateHandler) ActivateVersion(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	result, err := h.service.ActivateVersion(c.Request.Context(), adminID, templateID)
+	if err != nil {
+		respondWithTemplateError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Template version activated successfully", result)
+}
+
+func (h *NotificationTemplateHandler) Preview(c *gin.Context) {
+	var req notification.PreviewTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.Preview(c.Request.Context(), &req)
+	if err != nil {
+		respondWithTemplateError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Template rendered successfully", result)
+}
+
+func respondWithTemplateError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainNotification.ErrTemplateNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process notification template request")
+	}
+}
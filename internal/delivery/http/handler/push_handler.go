@@ -0,0 +1,152 @@
+package handler
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/usecase/notification"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PushHandler struct {
+	service *notification.PushService
+}
+
+func NewPushHandler(service *notification.PushService) *PushHandler {
+	return &PushHandler{service: service}
+}
+
+func (h *PushHandler) RegisterRoutes(router *gin.RouterGroup) {
+	push := router.Group("/push")
+	{
+		push.GET("/tokens", h.ListTokens)
+		push.POST("/tokens", h.RegisterToken)
+		push.DELETE("/tokens/:id", h.UnregisterToken)
+		push.GET("/subscriptions", h.ListSubscriptions)
+		push.POST("/subscriptions/shipments/:shipmentId", h.SubscribeShipment)
+		push.DELETE("/subscriptions/shipments/:shipmentId", h.UnsubscribeShipment)
+	}
+}
+
+func (h *PushHandler) ListTokens(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListTokens(c.Request.Context(), userID)
+	if err != nil {
+		respondWithPushError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Push tokens retrieved successfully", result)
+}
+
+func (h *PushHandler) RegisterToken(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req notification.RegisterPushTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.DeviceName != nil {
+		sanitized := utils.SanitizeString(*req.DeviceName)
+		req.DeviceName = &sanitized
+	}
+	if req.AppVersion != nil {
+		sanitized := utils.SanitizeString(*req.AppVersion)
+		req.AppVersion = &sanitized
+	}
+
+	result, err := h.service.RegisterToken(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondWithPushError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Push token registered successfully", result)
+}
+
+func (h *PushHandler) UnregisterToken(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	tokenID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	if err := h.service.UnregisterToken(c.Request.Context(), userID, tokenID); err != nil {
+		respondWithPushError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Push token removed successfully", nil)
+}
+
+func (h *PushHandler) ListSubscriptions(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		respondWithPushError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Push subscriptions retrieved successfully", result)
+}
+
+func (h *PushHandler) SubscribeShipment(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	userRole := c.MustGet("role").(string)
+
+	shipmentID, err := uuid.Parse(c.Param("shipmentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	if err := h.service.SubscribeShipment(c.Request.Context(), userID, userRole, shipmentID); err != nil {
+		respondWithPushError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Subscribed to shipment alerts", nil)
+}
+
+func (h *PushHandler) UnsubscribeShipment(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	shipmentID, err := uuid.Parse(c.Param("shipmentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	if err := h.service.UnsubscribeShipment(c.Request.Context(), userID, shipmentID); err != nil {
+		respondWithPushError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unsubscribed from shipment alerts", nil)
+}
+
+func respondWithPushError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainNotification.ErrPushTokenNotFound),
+		errors.Is(err, domainShipment.ErrShipmentNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process push request")
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/usecase/quota"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type QuotaHandler struct {
+	service *quota.Service
+}
+
+func NewQuotaHandler(service *quota.Service) *QuotaHandler {
+	return &QuotaHandler{service: service}
+}
+
+func (h *QuotaHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/plans", h.ListPlans)
+	router.GET("/profile/quota", h.GetMyUsage)
+}
+
+func (h *QuotaHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	router.GET("/users/:user_id/quota", h.GetUserUsage)
+	router.PUT("/users/:user_id/plan", h.ChangePlan)
+}
+
+func (h *QuotaHandler) ListPlans(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Plans retrieved successfully", h.service.ListPlans())
+}
+
+func (h *QuotaHandler) GetMyUsage(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.GetUsage(c.Request.Context(), userID)
+	if err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quota usage retrieved successfully", result)
+}
+
+func (h *QuotaHandler) GetUserUsage(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	result, err := h.service.GetUsage(c.Request.Context(), userID)
+	if err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quota usage retrieved successfully", result)
+}
+
+func (h *QuotaHandler) ChangePlan(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req quota.ChangePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.ChangePlan(c.Request.Context(), adminID, userID, &req)
+	if err != nil {
+		respondWithQuotaError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User plan changed successfully", result)
+}
+
+func respondWithQuotaError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainUser.ErrUserNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process quota request")
+	}
+}
+
+// respondWithPlanLimit answers errors raised by the limits and features of
+// the caller's plan and reports whether err was one, for handlers that map
+// other errors themselves
+func respondWithPlanLimit(c *gin.Context, err error) bool {
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) {
+		return false
+	}
+	switch appErr.Code {
+	case "QUOTA_EXCEEDED":
+		utils.ErrorResponse(c, http.StatusTooManyRequests, appErr.Message)
+	case "PLAN_UPGRADE_REQUIRED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	domainQuotation "cargo-tracker/internal/domain/quotation"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/usecase/quotation"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type QuotationHandler struct {
+	service *quotation.Service
+}
+
+func NewQuotationHandler(service *quotation.Service) *QuotationHandler {
+	return &QuotationHandler{service: service}
+}
+
+func (h *QuotationHandler) RegisterCustomerRoutes(router *gin.RouterGroup) {
+	requests := router.Group("/quote-requests")
+	{
+		requests.POST("", h.RequestQuotes)
+		requests.GET("", h.ListRequests)
+		requests.GET("/:id", h.GetRequest)
+		requests.POST("/:id/cancel", h.CancelRequest)
+		requests.POST("/:id/quotes/:quoteId/accept", h.AcceptQuote)
+	}
+}
+
+func (h *QuotationHandler) RegisterProviderRoutes(router *gin.RouterGroup) {
+	quotes := router.Group("/quotes")
+	{
+		quotes.GET("", h.ListIncomingQuotes)
+		quotes.POST("/:id/submit", h.SubmitQuote)
+		quotes.POST("/:id/decline", h.DeclineQuote)
+	}
+}
+
+func (h *QuotationHandler) RequestQuotes(c *gin.Context) {
+	customerID := c.MustGet("userID").(uuid.UUID)
+
+	var req quotation.CreateQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.GoodsDescription = utils.SanitizeText(req.GoodsDescription)
+	req.PickupAddress = utils.SanitizeText(req.PickupAddress)
+	req.DeliveryAddress = utils.SanitizeText(req.DeliveryAddress)
+	if req.CustomerNotes != nil {
+		sanitized := utils.SanitizeText(*req.CustomerNotes)
+		req.CustomerNotes = &sanitized
+	}
+
+	result, err := h.service.RequestQuotes(c.Request.Context(), customerID, &req)
+	if err != nil {
+		respondWithQuotationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Quote request sent to providers", result)
+}
+
+func (h *QuotationHandler) ListRequests(c *gin.Context) {
+	customerID := c.MustGet("userID").(uuid.UUID)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	result, err := h.service.ListRequests(c.Request.Context(), customerID, page, pageSize)
+	if err != nil {
+		respondWithQuotationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quote requests retrieved successfully", result)
+}
+
+func (h *QuotationHandler) GetRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	customerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid quote request ID")
+		return
+	}
+
+	result, err := h.service.GetRequest(c.Request.Context(), customerID, requestID)
+	if err != nil {
+		respondWithQuotationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quote request retrieved successfully", result)
+}
+
+func (h *QuotationHandler) CancelRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	customerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid quote request ID")
+		return
+	}
+
+	result, err := h.service.CancelRequest(c.Request.Context(), customerID, requestID)
+	if err != nil {
+		respondWithQuotationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quote request cancelled successfully", result)
+}
+
+func (h *QuotationHandler) AcceptQuote(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	customerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid quote request ID")
+		return
+	}
+	quoteID, err := uuid.Parse(c.Param("quoteId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid quote ID")
+		return
+	}
+
+	result, err := h.service.AcceptQuote(c.Request.Context(), customerID, requestID, quoteID)
+	if err != nil {
+		respondWithQuotationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Quote accepted and shipment demand created", result)
+}
+
+func (h *QuotationHandler) ListIncomingQuotes(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	var filter quotation.QuoteFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.ListIncomingQuotes(c.Request.Context(), providerID, &filter)
+	if err != nil {
+		respondWithQuotationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quotes retrieved successfully", result)
+}
+
+func (h *QuotationHandler) SubmitQuote(c *gin.Context) {
+	quoteID, err := uuid.Parse(c.Param("id"))
+	providerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid quote ID")
+		return
+	}
+
+	var req quotation.SubmitQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Notes != nil {
+		sanitized := utils.SanitizeText(*req.Notes)
+		req.Notes = &sanitized
+	}
+
+	result, err := h.service.SubmitQuote(c.Request.Context(), providerID, quoteID, &req)
+	if err != nil {
+		respondWithQuotationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quote submitted successfully", result)
+}
+
+func (h *QuotationHandler) DeclineQuote(c *gin.Context) {
+	quoteID, err := uuid.Parse(c.Param("id"))
+	providerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid quote ID")
+		return
+	}
+
+	var req quotation.DeclineQuoteRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.Reason != nil {
+		sanitized := utils.SanitizeText(*req.Reason)
+		req.Reason = &sanitized
+	}
+
+	result, err := h.service.DeclineQuote(c.Request.Context(), providerID, quoteID, &req)
+	if err != nil {
+		respondWithQuotationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quote declined successfully", result)
+}
+
+func respondWithQuotationError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainQuotation.ErrQuoteRequestNotFound),
+		errors.Is(err, domainQuotation.ErrQuoteNotFound),
+		errors.Is(err, domainShipment.ErrShipmentNotFound),
+		errors.Is(err, appErrors.ErrUserNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainQuotation.ErrRequestClosed),
+		errors.Is(err, domainQuotation.ErrQuoteNotSubmitted),
+		errors.Is(err, domainQuotation.ErrQuoteExpired):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized),
+		errors.Is(err, appErrors.ErrUserInactive):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr) && appErr.Code == "QUOTA_EXCEEDED":
+		utils.ErrorResponse(c, http.StatusTooManyRequests, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process quotation request")
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"cargo-tracker/internal/usecase/reference"
+	"cargo-tracker/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReferenceHandler struct {
+	service *reference.Service
+}
+
+func NewReferenceHandler(service *reference.Service) *ReferenceHandler {
+	return &ReferenceHandler{service: service}
+}
+
+// RegisterRoutes registers the lookup endpoint; registration forms need the
+// roles before anyone is signed in
+func (h *ReferenceHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/reference-data", h.GetReferenceData)
+}
+
+func (h *ReferenceHandler) GetReferenceData(c *gin.Context) {
+	result, err := h.service.GetReferenceData(c.Request.Context())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve reference data")
+		return
+	}
+
+	// Only changes with a deployment
+	c.Header("Cache-Control", "public, max-age=3600")
+	utils.SuccessResponse(c, http.StatusOK, "Reference data retrieved successfully", result)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	domainReport "cargo-tracker/internal/domain/report"
+	"cargo-tracker/internal/usecase/report"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReportHandler struct {
+	service *report.Service
+}
+
+func NewReportHandler(service *report.Service) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+func (h *ReportHandler) RegisterRoutes(router *gin.RouterGroup) {
+	subscriptions := router.Group("/reports/subscriptions")
+	{
+		subscriptions.POST("", h.CreateSubscription)
+		subscriptions.GET("", h.ListSubscriptions)
+		subscriptions.PUT("/:id", h.UpdateSubscription)
+		subscriptions.DELETE("/:id", h.DeleteSubscription)
+		subscriptions.GET("/:id/deliveries", h.ListDeliveries)
+	}
+}
+
+func (h *ReportHandler) CreateSubscription(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	userRole := c.MustGet("role").(string)
+
+	var req report.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.CreateSubscription(c.Request.Context(), userID, userRole, &req)
+	if err != nil {
+		respondWithReportError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Report subscription created successfully", result)
+}
+
+func (h *ReportHandler) ListSubscriptions(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		respondWithReportError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report subscriptions retrieved successfully", result)
+}
+
+func (h *ReportHandler) UpdateSubscription(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+
+	var req report.UpdateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.UpdateSubscription(c.Request.Context(), userID, subscriptionID, &req)
+	if err != nil {
+		respondWithReportError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report subscription updated successfully", result)
+}
+
+func (h *ReportHandler) DeleteSubscription(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+
+	if err := h.service.DeleteSubscription(c.Request.Context(), userID, subscriptionID); err != nil {
+		respondWithReportError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report subscription deleted successfully", nil)
+}
+
+func (h *ReportHandler) ListDeliveries(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+
+	result, err := h.service.ListDeliveries(c.Request.Context(), userID, subscriptionID)
+	if err != nil {
+		respondWithReportError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report deliveries retrieved successfully", result)
+}
+
+func respondWithReportError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainReport.ErrSubscriptionNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainReport.ErrDeliveryUnavailable):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process report subscription")
+	}
+}
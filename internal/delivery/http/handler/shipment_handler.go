@@ -1,9 +1,18 @@
 package handler
 
 import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainStorage "cargo-tracker/internal/domain/storage"
 	"cargo-tracker/internal/usecase/shipment"
+	appErrors "cargo-tracker/pkg/errors"
 	"cargo-tracker/pkg/utils"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,9 +30,16 @@ func (h *ShipmentHandler) RegisterRoutes(router *gin.RouterGroup) {
 	shipments := router.Group("/shipments")
 	{
 		// Public routes
+		shipments.GET("/statistics", h.GetStatistics)
+	}
+}
+
+// RegisterReadRoutes registers shipment reads available to every signed-in role
+func (h *ShipmentHandler) RegisterReadRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
 		shipments.GET("", h.ListShipments)
 		shipments.GET("/:id", h.GetShipment)
-		shipments.GET("/statistics", h.GetStatistics)
 	}
 }
 
@@ -43,18 +59,187 @@ func (h *ShipmentHandler) RegisterProviderRoutes(router *gin.RouterGroup) {
 	{
 		// Provider routes
 		shipments.POST("/:id/post-order", h.PostOrder)
+		shipments.PUT("/:id/rules", h.UpdateRules)
+		shipments.GET("/:id/suggested-shippers", h.SuggestShippers)
+		shipments.POST("/:id/suggested-shippers/notify", h.NotifySuggestedShippers)
+		shipments.POST("/:id/packages", h.AddPackage)
+		shipments.PUT("/:id/packages/:packageId", h.UpdatePackage)
+		shipments.DELETE("/:id/packages/:packageId", h.RemovePackage)
 	}
 }
 
+// RegisterProviderAnalyticsRoutes registers the provider's comparison of
+// the shippers handling its shipments.
+func (h *ShipmentHandler) RegisterProviderAnalyticsRoutes(router *gin.RouterGroup) {
+	router.GET("/providers/me/shipper-comparison", h.CompareShippers)
+}
+
 func (h *ShipmentHandler) RegisterShipperRoutes(router *gin.RouterGroup) {
 	shipments := router.Group("/shipments")
 	{
 		// Shipper routes
+		shipments.GET("/:id/accept-preview", h.PreviewAcceptOrder)
+		shipments.GET("/:id/lane-statistics", h.GetShipmentLaneStatistics)
 		shipments.POST("/:id/accept", h.AcceptOrder)
 		shipments.POST("/:id/confirm-rules", h.ConfirmRules)
 		shipments.POST("/:id/start-shipping", h.StartShipping)
 		shipments.POST("/:id/complete", h.CompleteDelivery)
 		shipments.POST("/:id/report-issue", h.ReportIssue)
+		shipments.POST("/:id/packages/:packageId/assign-device", h.AssignPackageDevice)
+		shipments.POST("/:id/packages/:packageId/outcome", h.RecordPackageOutcome)
+	}
+	router.GET("/lanes/statistics", h.GetLaneStatistics)
+}
+
+// RegisterAdminRoutes registers the admin overrides, which skip the normal
+// checks but always need a justification, their audit trail, and the
+// postmortem bundle of a shipment
+func (h *ShipmentHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		shipments.POST("/:id/force-status", h.ForceStatus)
+		shipments.POST("/:id/force-confirm-rules", h.ForceConfirmRules)
+		shipments.GET("/:id/overrides", h.ListOverrides)
+		shipments.POST("/:id/postmortem", h.QueuePostmortemBundle)
+	}
+	router.POST("/devices/:id/force-release", h.ForceReleaseDevice)
+}
+
+// RegisterDeviceRoutes registers the device API, called by trackers with
+// their device credential rather than by users
+func (h *ShipmentHandler) RegisterDeviceRoutes(router *gin.RouterGroup) {
+	ingest := router.Group("/ingest")
+	{
+		ingest.GET("/config", h.GetDeviceConfig)
+	}
+}
+
+// RegisterTripRoutes registers multi-stop trip management for shippers.
+func (h *ShipmentHandler) RegisterTripRoutes(router *gin.RouterGroup) {
+	trips := router.Group("/trips")
+	{
+		trips.GET("", h.ListTrips)
+		trips.POST("", h.CreateTrip)
+		trips.GET("/:id", h.GetTrip)
+		trips.POST("/:id/stops/:stopId/complete", h.CompleteTripStop)
+	}
+}
+
+// RegisterPackageRoutes registers package listing for any shipment party.
+func (h *ShipmentHandler) RegisterPackageRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		shipments.GET("/:id/packages", h.ListPackages)
+	}
+}
+
+// RegisterDocumentRoutes registers printable document downloads, shared by
+// providers and shippers.
+func (h *ShipmentHandler) RegisterDocumentRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		shipments.GET("/:id/label", h.DownloadLabel)
+		shipments.GET("/:id/manifest", h.DownloadManifest)
+	}
+}
+
+// RegisterAccessGrantRoutes registers third-party access management for the
+// shipment's customer and provider.
+func (h *ShipmentHandler) RegisterAccessGrantRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		shipments.GET("/:id/access-grants", h.ListAccessGrants)
+		shipments.POST("/:id/access-grants", h.CreateAccessGrant)
+		shipments.DELETE("/:id/access-grants/:grantId", h.RevokeAccessGrant)
+	}
+}
+
+// RegisterDelegationRoutes registers booking delegation management for
+// customers and their forwarders.
+func (h *ShipmentHandler) RegisterDelegationRoutes(router *gin.RouterGroup) {
+	delegations := router.Group("/delegations")
+	{
+		delegations.GET("", h.ListDelegations)
+		delegations.POST("", h.CreateDelegation)
+		delegations.DELETE("/:id", h.RevokeDelegation)
+	}
+}
+
+// RegisterReceivedAccessGrantRoutes registers the grantee's view of the
+// shipments shared with them.
+func (h *ShipmentHandler) RegisterReceivedAccessGrantRoutes(router *gin.RouterGroup) {
+	router.GET("/access-grants/received", h.ListReceivedAccessGrants)
+}
+
+// RegisterTermsRoutes registers terms of carriage publishing for providers.
+func (h *ShipmentHandler) RegisterTermsRoutes(router *gin.RouterGroup) {
+	terms := router.Group("/terms")
+	{
+		terms.GET("", h.ListTerms)
+		terms.POST("", h.PublishTerms)
+	}
+}
+
+// RegisterShipmentTermsRoutes registers the terms and signed acceptance of a
+// shipment for its parties.
+func (h *ShipmentHandler) RegisterShipmentTermsRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		shipments.GET("/:id/terms", h.GetShipmentTerms)
+		shipments.GET("/:id/terms/signature", h.GetTermsSignature)
+	}
+}
+
+// RegisterEventRoutes registers the event timeline and point-in-time state of
+// a shipment for anyone allowed to read it.
+func (h *ShipmentHandler) RegisterEventRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		shipments.GET("/:id/events", h.ListEvents)
+		shipments.GET("/:id/events/state", h.GetStateAt)
+		shipments.GET("/:id/rules/history", h.GetRulesHistory)
+	}
+}
+
+// RegisterNoteRoutes registers the notes thread of a shipment; who can read
+// and write which notes is decided per shipment.
+func (h *ShipmentHandler) RegisterNoteRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		shipments.GET("/:id/notes", h.ListNotes)
+		shipments.POST("/:id/notes", h.CreateNote)
+		shipments.GET("/:id/notes/:noteId", h.GetNote)
+		shipments.PUT("/:id/notes/:noteId", h.UpdateNote)
+		shipments.DELETE("/:id/notes/:noteId", h.DeleteNote)
+	}
+}
+
+// RegisterCalendarRoutes registers the public view of provider business
+// calendars and delivery estimates.
+func (h *ShipmentHandler) RegisterCalendarRoutes(router *gin.RouterGroup) {
+	calendars := router.Group("/calendars")
+	{
+		calendars.GET("/:providerId", h.GetCalendar)
+		calendars.GET("/:providerId/estimate", h.EstimateDelivery)
+	}
+}
+
+// RegisterProviderCalendarRoutes registers business calendar management for
+// providers.
+func (h *ShipmentHandler) RegisterProviderCalendarRoutes(router *gin.RouterGroup) {
+	calendar := router.Group("/profile/calendar")
+	{
+		calendar.GET("", h.GetOwnCalendar)
+		calendar.PUT("", h.UpdateCalendar)
+		calendar.DELETE("", h.DeleteCalendar)
+	}
+}
+
+// RegisterSandboxRoutes registers sandbox maintenance for sandbox accounts.
+func (h *ShipmentHandler) RegisterSandboxRoutes(router *gin.RouterGroup) {
+	sandbox := router.Group("/sandbox")
+	{
+		sandbox.POST("/reset", h.ResetSandbox)
 	}
 }
 
@@ -81,6 +266,14 @@ func (h *ShipmentHandler) CreateDemand(c *gin.Context) {
 
 	result, err := h.service.CreateDemand(c.Request.Context(), customerUUID, &req)
 	if err != nil {
+		if respondWithPlanLimit(c, err) {
+			return
+		}
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) && appErr.Code == "NOT_DELEGATED" {
+			utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -123,6 +316,9 @@ func (h *ShipmentHandler) PostOrder(c *gin.Context) {
 
 	result, err := h.service.PostOrder(c.Request.Context(), shipmentID, providerUUID, &req)
 	if err != nil {
+		if respondWithPlanLimit(c, err) {
+			return
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -158,6 +354,9 @@ func (h *ShipmentHandler) AcceptOrder(c *gin.Context) {
 
 	result, err := h.service.AcceptOrder(c.Request.Context(), shipmentID, shipperUUID, &req)
 	if err != nil {
+		if respondWithPlanLimit(c, err) {
+			return
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -165,6 +364,78 @@ func (h *ShipmentHandler) AcceptOrder(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Order accepted successfully", result)
 }
 
+func (h *ShipmentHandler) PreviewAcceptOrder(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var query shipment.AcceptPreviewQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.PreviewAcceptOrder(c.Request.Context(), shipmentID, shipperID, &query)
+	if err != nil {
+		var appErr *appErrors.AppError
+		switch {
+		case errors.Is(err, domainShipment.ErrShipmentNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		case errors.As(err, &appErr):
+			utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to preview order acceptance")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Acceptance preview retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) GetLaneStatistics(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var query shipment.LaneStatisticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.GetLaneStatistics(c.Request.Context(), userID, &query)
+	if err != nil {
+		respondWithLaneError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Lane statistics retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) GetShipmentLaneStatistics(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var query shipment.LanePeriodQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.GetShipmentLaneStatistics(c.Request.Context(), shipmentID, &query)
+	if err != nil {
+		respondWithLaneError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Lane statistics retrieved successfully", result)
+}
+
 func (h *ShipmentHandler) ConfirmRules(c *gin.Context) {
 	shipmentID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -185,7 +456,36 @@ func (h *ShipmentHandler) ConfirmRules(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.ConfirmRules(c.Request.Context(), shipmentID, shipperUUID)
+	// Acceptance of the provider's terms of carriage is sent as a multipart
+	// form with the terms_id and a signature image
+	var terms *shipment.AcceptTermsInput
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		termsID, err := uuid.Parse(c.PostForm("terms_id"))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid terms ID")
+			return
+		}
+		fileHeader, err := c.FormFile("signature")
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Signature is required")
+			return
+		}
+		signature, err := fileHeader.Open()
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read uploaded signature")
+			return
+		}
+		defer signature.Close()
+
+		terms = &shipment.AcceptTermsInput{
+			TermsID:   termsID,
+			Signature: signature,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+	}
+
+	result, err := h.service.ConfirmRules(c.Request.Context(), shipmentID, shipperUUID, terms)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -389,6 +689,104 @@ func (h *ShipmentHandler) ListShipments(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Shipments retrieved successfully", result)
 }
 
+// UpdateRules changes the shipping rules before the shipper confirms them
+func (h *ShipmentHandler) UpdateRules(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var req shipment.PostOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.UpdateRules(c.Request.Context(), shipmentID, providerID, &req)
+	if err != nil {
+		if respondWithPlanLimit(c, err) {
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipping rules updated successfully", result)
+}
+
+func (h *ShipmentHandler) SuggestShippers(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var query shipment.SuggestedShippersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.SuggestShippers(c.Request.Context(), providerID, shipmentID, &query)
+	if err != nil {
+		respondWithMatchingError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Suggested shippers retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) NotifySuggestedShippers(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var query shipment.SuggestedShippersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.NotifySuggestedShippers(c.Request.Context(), providerID, shipmentID, &query)
+	if err != nil {
+		respondWithMatchingError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Suggested shippers notified successfully", result)
+}
+
+func (h *ShipmentHandler) CompareShippers(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	var query shipment.ShipperComparisonQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.CompareShippers(c.Request.Context(), providerID, &query)
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to compare shippers")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipper comparison retrieved successfully", result)
+}
+
 func (h *ShipmentHandler) GetStatistics(c *gin.Context) {
 	result, err := h.service.GetStatistics(c.Request.Context())
 	if err != nil {
@@ -398,3 +796,1076 @@ func (h *ShipmentHandler) GetStatistics(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Statistics retrieved successfully", result)
 }
+
+func (h *ShipmentHandler) DownloadLabel(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	label, err := h.service.GenerateLabel(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithDocumentError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="label-%s.pdf"`, shipmentID))
+	c.Data(http.StatusOK, "application/pdf", label)
+}
+
+func (h *ShipmentHandler) DownloadManifest(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	manifest, err := h.service.GenerateManifest(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithDocumentError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="manifest-%s.pdf"`, shipmentID))
+	c.Data(http.StatusOK, "application/pdf", manifest)
+}
+
+func (h *ShipmentHandler) ListPackages(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.ListPackages(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithPackageError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Packages retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) AddPackage(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	providerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var req shipment.CreatePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Description = utils.SanitizeText(req.Description)
+
+	result, err := h.service.AddPackage(c.Request.Context(), providerID, shipmentID, &req)
+	if err != nil {
+		respondWithPackageError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Package added successfully", result)
+}
+
+func (h *ShipmentHandler) UpdatePackage(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	providerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+	packageID, err := uuid.Parse(c.Param("packageId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid package ID")
+		return
+	}
+
+	var req shipment.UpdatePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Description != nil {
+		sanitized := utils.SanitizeText(*req.Description)
+		req.Description = &sanitized
+	}
+
+	result, err := h.service.UpdatePackage(c.Request.Context(), providerID, shipmentID, packageID, &req)
+	if err != nil {
+		respondWithPackageError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Package updated successfully", result)
+}
+
+func (h *ShipmentHandler) RemovePackage(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	providerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+	packageID, err := uuid.Parse(c.Param("packageId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid package ID")
+		return
+	}
+
+	if err := h.service.RemovePackage(c.Request.Context(), providerID, shipmentID, packageID); err != nil {
+		respondWithPackageError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Package removed successfully", nil)
+}
+
+func (h *ShipmentHandler) AssignPackageDevice(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	shipperID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+	packageID, err := uuid.Parse(c.Param("packageId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid package ID")
+		return
+	}
+
+	var req shipment.AssignPackageDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.AssignPackageDevice(c.Request.Context(), shipperID, shipmentID, packageID, &req)
+	if err != nil {
+		respondWithPackageError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device assigned to package successfully", result)
+}
+
+func (h *ShipmentHandler) RecordPackageOutcome(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	shipperID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+	packageID, err := uuid.Parse(c.Param("packageId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid package ID")
+		return
+	}
+
+	var req shipment.RecordPackageOutcomeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Note != nil {
+		sanitized := utils.SanitizeText(*req.Note)
+		req.Note = &sanitized
+	}
+
+	result, err := h.service.RecordPackageOutcome(c.Request.Context(), shipperID, shipmentID, packageID, &req)
+	if err != nil {
+		respondWithPackageError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Package outcome recorded successfully", result)
+}
+
+func (h *ShipmentHandler) CreateAccessGrant(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	ownerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var req shipment.CreateAccessGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.GranteeEmail != nil {
+		sanitized := utils.SanitizeEmail(*req.GranteeEmail)
+		req.GranteeEmail = &sanitized
+	}
+	if req.Note != nil {
+		sanitized := utils.SanitizeText(*req.Note)
+		req.Note = &sanitized
+	}
+
+	result, err := h.service.CreateAccessGrant(c.Request.Context(), ownerID, shipmentID, &req)
+	if err != nil {
+		respondWithAccessGrantError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Access granted successfully", result)
+}
+
+func (h *ShipmentHandler) ListAccessGrants(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	ownerID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.ListAccessGrants(c.Request.Context(), ownerID, shipmentID)
+	if err != nil {
+		respondWithAccessGrantError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Access grants retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) RevokeAccessGrant(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+	grantID, err := uuid.Parse(c.Param("grantId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid access grant ID")
+		return
+	}
+	ownerID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.service.RevokeAccessGrant(c.Request.Context(), ownerID, shipmentID, grantID); err != nil {
+		respondWithAccessGrantError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Access revoked successfully", nil)
+}
+
+func (h *ShipmentHandler) CreateDelegation(c *gin.Context) {
+	customerID := c.MustGet("userID").(uuid.UUID)
+
+	var req shipment.CreateDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.DelegateEmail != nil {
+		sanitized := utils.SanitizeEmail(*req.DelegateEmail)
+		req.DelegateEmail = &sanitized
+	}
+	if req.Note != nil {
+		sanitized := utils.SanitizeText(*req.Note)
+		req.Note = &sanitized
+	}
+
+	result, err := h.service.CreateDelegation(c.Request.Context(), customerID, &req)
+	if err != nil {
+		respondWithDelegationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Delegation created successfully", result)
+}
+
+func (h *ShipmentHandler) ListDelegations(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListDelegations(c.Request.Context(), userID)
+	if err != nil {
+		respondWithDelegationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Delegations retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) RevokeDelegation(c *gin.Context) {
+	delegationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid delegation ID")
+		return
+	}
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.service.RevokeDelegation(c.Request.Context(), userID, delegationID); err != nil {
+		respondWithDelegationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Delegation revoked successfully", nil)
+}
+
+func (h *ShipmentHandler) ListReceivedAccessGrants(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListReceivedAccessGrants(c.Request.Context(), userID)
+	if err != nil {
+		respondWithAccessGrantError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Access grants retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) PublishTerms(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	var req shipment.PublishTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Title = utils.SanitizeString(req.Title)
+	req.Body = utils.SanitizeText(req.Body)
+
+	result, err := h.service.PublishTerms(c.Request.Context(), providerID, &req)
+	if err != nil {
+		respondWithTermsError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Terms of carriage published successfully", result)
+}
+
+func (h *ShipmentHandler) ListTerms(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListTerms(c.Request.Context(), providerID)
+	if err != nil {
+		respondWithTermsError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Terms of carriage retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) GetShipmentTerms(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.GetShipmentTerms(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithTermsError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Terms of carriage retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) GetTermsSignature(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	content, contentType, err := h.service.OpenTermsSignature(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithTermsError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, -1, contentType, content, map[string]string{
+		"Cache-Control": "private, no-store",
+	})
+}
+
+func (h *ShipmentHandler) ListEvents(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.ListEvents(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithEventError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipment events retrieved successfully", result)
+}
+
+// GetRulesHistory returns every version of the shipment's rules with the
+// fields each one changed
+func (h *ShipmentHandler) GetRulesHistory(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.GetRulesHistory(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithEventError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rules history retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) CreateNote(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var req shipment.CreateNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Body = utils.SanitizeText(req.Body)
+
+	result, err := h.service.CreateNote(c.Request.Context(), userID, shipmentID, &req)
+	if err != nil {
+		respondWithNoteError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Note added successfully", result)
+}
+
+func (h *ShipmentHandler) ListNotes(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.ListNotes(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		respondWithNoteError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notes retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) GetNote(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, noteID, ok := parseNoteParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetNote(c.Request.Context(), userID, shipmentID, noteID)
+	if err != nil {
+		respondWithNoteError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Note retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) UpdateNote(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, noteID, ok := parseNoteParams(c)
+	if !ok {
+		return
+	}
+
+	var req shipment.UpdateNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Body != nil {
+		sanitized := utils.SanitizeText(*req.Body)
+		req.Body = &sanitized
+	}
+
+	result, err := h.service.UpdateNote(c.Request.Context(), userID, shipmentID, noteID, &req)
+	if err != nil {
+		respondWithNoteError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Note updated successfully", result)
+}
+
+func (h *ShipmentHandler) DeleteNote(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, noteID, ok := parseNoteParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteNote(c.Request.Context(), userID, shipmentID, noteID); err != nil {
+		respondWithNoteError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Note deleted successfully", nil)
+}
+
+func parseNoteParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid note ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return shipmentID, noteID, true
+}
+
+func (h *ShipmentHandler) GetStateAt(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	at := time.Now()
+	if raw := c.Query("at"); raw != "" {
+		at, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid at, expected RFC 3339")
+			return
+		}
+	}
+
+	result, err := h.service.GetStateAt(c.Request.Context(), userID, shipmentID, at)
+	if err != nil {
+		respondWithEventError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipment state retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) GetCalendar(c *gin.Context) {
+	providerID, err := uuid.Parse(c.Param("providerId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid provider ID")
+		return
+	}
+
+	result, err := h.service.GetCalendar(c.Request.Context(), providerID)
+	if err != nil {
+		respondWithCalendarError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Business calendar retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) EstimateDelivery(c *gin.Context) {
+	providerID, err := uuid.Parse(c.Param("providerId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid provider ID")
+		return
+	}
+
+	transitDays, err := strconv.Atoi(c.DefaultQuery("transit_days", "0"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid transit_days")
+		return
+	}
+	from := time.Now()
+	if raw := c.Query("from"); raw != "" {
+		from, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid from, expected RFC 3339")
+			return
+		}
+	}
+
+	result, err := h.service.EstimateDelivery(c.Request.Context(), providerID, from, transitDays)
+	if err != nil {
+		respondWithCalendarError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Delivery estimated successfully", result)
+}
+
+func (h *ShipmentHandler) GetOwnCalendar(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.GetCalendar(c.Request.Context(), providerID)
+	if err != nil {
+		respondWithCalendarError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Business calendar retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) UpdateCalendar(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	var req shipment.UpdateCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	for i := range req.Holidays {
+		req.Holidays[i].Name = utils.SanitizeString(req.Holidays[i].Name)
+	}
+
+	result, err := h.service.UpdateCalendar(c.Request.Context(), providerID, &req)
+	if err != nil {
+		respondWithCalendarError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Business calendar updated successfully", result)
+}
+
+func (h *ShipmentHandler) DeleteCalendar(c *gin.Context) {
+	providerID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.service.DeleteCalendar(c.Request.Context(), providerID); err != nil {
+		respondWithCalendarError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Business calendar deleted successfully", nil)
+}
+
+func (h *ShipmentHandler) GetDeviceConfig(c *gin.Context) {
+	deviceID := c.MustGet("deviceID").(uuid.UUID)
+
+	result, err := h.service.GetDeviceConfig(c.Request.Context(), deviceID)
+	if err != nil {
+		if errors.Is(err, domainDevice.ErrDeviceNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve device configuration")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device configuration retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) ForceStatus(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var req shipment.ForceStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Justification = utils.SanitizeText(req.Justification)
+
+	result, err := h.service.ForceStatus(c.Request.Context(), adminID, shipmentID, &req)
+	if err != nil {
+		respondWithOverrideError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipment status overridden successfully", result)
+}
+
+func (h *ShipmentHandler) ForceConfirmRules(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	var req shipment.OverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Justification = utils.SanitizeText(req.Justification)
+
+	result, err := h.service.ForceConfirmRules(c.Request.Context(), adminID, shipmentID, &req)
+	if err != nil {
+		respondWithOverrideError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipping rules confirmed by override", result)
+}
+
+func (h *ShipmentHandler) ForceReleaseDevice(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	var req shipment.OverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Justification = utils.SanitizeText(req.Justification)
+
+	result, err := h.service.ForceReleaseDevice(c.Request.Context(), adminID, deviceID, &req)
+	if err != nil {
+		respondWithOverrideError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Device released by override", result)
+}
+
+func (h *ShipmentHandler) ListOverrides(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.ListOverrides(c.Request.Context(), shipmentID)
+	if err != nil {
+		respondWithOverrideError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Overrides retrieved successfully", result)
+}
+
+// QueuePostmortemBundle always runs as a job: the bundle copies every
+// attachment of the shipment. The zip is downloaded from the job result.
+func (h *ShipmentHandler) QueuePostmortemBundle(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	queued, err := h.service.QueuePostmortemBundle(c.Request.Context(), adminID, shipmentID)
+	if err != nil {
+		if errors.Is(err, domainShipment.ErrShipmentNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to queue postmortem bundle")
+		return
+	}
+
+	respondWithQueuedJob(c, queued)
+}
+
+func respondWithOverrideError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound),
+		errors.Is(err, domainDevice.ErrDeviceNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainShipment.ErrInvalidStatus),
+		errors.Is(err, domainShipment.ErrShipperRequired),
+		errors.Is(err, domainShipment.ErrRulesRequired):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to apply override")
+	}
+}
+
+func respondWithPackageError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound),
+		errors.Is(err, domainShipment.ErrPackageNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domainShipment.ErrDeviceUnavailable):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process package request")
+	}
+}
+
+func respondWithMatchingError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to suggest shippers")
+	}
+}
+
+func respondWithLaneError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get lane statistics")
+	}
+}
+
+func respondWithDocumentError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate document")
+	}
+}
+
+func (h *ShipmentHandler) ResetSandbox(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ResetSandbox(c.Request.Context(), userID)
+	if err != nil {
+		var appErr *appErrors.AppError
+		switch {
+		case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+			utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to reset sandbox data")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sandbox data reset successfully", result)
+}
+
+func respondWithAccessGrantError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound),
+		errors.Is(err, domainShipment.ErrAccessGrantNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr) && appErr.Code == "GRANTEE_NOT_FOUND":
+		utils.ErrorResponse(c, http.StatusNotFound, appErr.Message)
+	case errors.As(err, &appErr) && appErr.Code == "ALREADY_REVOKED":
+		utils.ErrorResponse(c, http.StatusConflict, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process access grant request")
+	}
+}
+
+func respondWithDelegationError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrDelegationNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "DELEGATE_NOT_FOUND":
+		utils.ErrorResponse(c, http.StatusNotFound, appErr.Message)
+	case errors.As(err, &appErr) && (appErr.Code == "ALREADY_DELEGATED" || appErr.Code == "ALREADY_REVOKED"):
+		utils.ErrorResponse(c, http.StatusConflict, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process delegation request")
+	}
+}
+
+func respondWithTermsError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound),
+		errors.Is(err, domainShipment.ErrTermsNotFound),
+		errors.Is(err, domainShipment.ErrTermsAcceptanceNotFound),
+		errors.Is(err, domainStorage.ErrObjectNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process terms of carriage request")
+	}
+}
+
+func (h *ShipmentHandler) CreateTrip(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	var req shipment.CreateTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Name != nil {
+		sanitized := utils.SanitizeString(*req.Name)
+		req.Name = &sanitized
+	}
+
+	result, err := h.service.CreateTrip(c.Request.Context(), shipperID, &req)
+	if err != nil {
+		respondWithTripError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Trip created successfully", result)
+}
+
+func (h *ShipmentHandler) ListTrips(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListTrips(c.Request.Context(), shipperID, c.Query("status"))
+	if err != nil {
+		respondWithTripError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trips retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) GetTrip(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	tripID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid trip ID")
+		return
+	}
+
+	result, err := h.service.GetTrip(c.Request.Context(), shipperID, tripID)
+	if err != nil {
+		respondWithTripError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trip retrieved successfully", result)
+}
+
+func (h *ShipmentHandler) CompleteTripStop(c *gin.Context) {
+	shipperID := c.MustGet("userID").(uuid.UUID)
+
+	tripID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid trip ID")
+		return
+	}
+	stopID, err := uuid.Parse(c.Param("stopId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid stop ID")
+		return
+	}
+
+	var req shipment.CompleteDeliveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.CompleteTripStop(c.Request.Context(), shipperID, tripID, stopID, &req)
+	if err != nil {
+		respondWithTripError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stop completed successfully", result)
+}
+
+func respondWithTripError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrTripNotFound),
+		errors.Is(err, domainShipment.ErrShipmentNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr) && (appErr.Code == "ALREADY_IN_TRIP" || appErr.Code == "DEVICE_UNAVAILABLE"):
+		utils.ErrorResponse(c, http.StatusConflict, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process trip request")
+	}
+}
+
+func respondWithCalendarError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrCalendarNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process business calendar request")
+	}
+}
+
+func respondWithNoteError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound),
+		errors.Is(err, domainShipment.ErrNoteNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process note request")
+	}
+}
+
+func respondWithEventError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainShipment.ErrShipmentNotFound),
+		errors.Is(err, domainShipment.ErrEventLogDisabled):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, appErrors.ErrUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve shipment events")
+	}
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/usecase/shipment"
+	"cargo-tracker/internal/usecase/user"
+	"cargo-tracker/pkg/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ShipmentV2Handler serves the /api/v2 shipment reads. It calls the same
+// shipment service as v1 and only maps the results to the v2 shapes, so
+// the v1 responses stay frozen while v2 evolves.
+type ShipmentV2Handler struct {
+	service *shipment.Service
+}
+
+func NewShipmentV2Handler(service *shipment.Service) *ShipmentV2Handler {
+	return &ShipmentV2Handler{service: service}
+}
+
+// RegisterReadRoutes registers shipment reads available to every signed-in role
+func (h *ShipmentV2Handler) RegisterReadRoutes(router *gin.RouterGroup) {
+	shipments := router.Group("/shipments")
+	{
+		shipments.GET("", h.ListShipments)
+		shipments.GET("/:id", h.GetShipment)
+	}
+}
+
+// V2 response shapes. Compared to v1 the flat fields are grouped: parties,
+// goods (with the value as an amount and currency), route, schedule and
+// risk, and lists are paginated under a pagination object.
+
+type ShipmentV2 struct {
+	ID          uuid.UUID                     `json:"id"`
+	Status      domainShipment.ShipmentStatus `json:"status"`
+	Parties     PartiesV2                     `json:"parties"`
+	Device      *shipment.DeviceInfo          `json:"device"`
+	Goods       GoodsV2                       `json:"goods"`
+	Route       RouteV2                       `json:"route"`
+	Schedule    ScheduleV2                    `json:"schedule"`
+	Flags       ShipmentFlagsV2               `json:"flags"`
+	AlertsCount int                           `json:"alerts_count"`
+	Risk        *RiskV2                       `json:"risk"`
+	Warnings    []shipment.RuleWarning        `json:"warnings"`
+	Notes       NotesV2                       `json:"notes"`
+	CreatedAt   time.Time                     `json:"created_at"`
+	UpdatedAt   time.Time                     `json:"updated_at"`
+}
+
+type PartiesV2 struct {
+	Customer *shipment.PartyInfo `json:"customer"`
+	Provider *shipment.PartyInfo `json:"provider"`
+	Shipper  *shipment.PartyInfo `json:"shipper"`
+	BookedBy *uuid.UUID          `json:"booked_by"`
+}
+
+type GoodsV2 struct {
+	Description string   `json:"description"`
+	Category    *string  `json:"category"`
+	Value       *MoneyV2 `json:"value"`
+	Weight      *float64 `json:"weight"`
+}
+
+type MoneyV2 struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+type RouteV2 struct {
+	Pickup   AddressV2 `json:"pickup"`
+	Delivery AddressV2 `json:"delivery"`
+}
+
+type AddressV2 struct {
+	Address   string     `json:"address"`
+	AddressID *uuid.UUID `json:"address_id"`
+}
+
+type ScheduleV2 struct {
+	Pickup          MilestoneV2 `json:"pickup"`
+	Delivery        MilestoneV2 `json:"delivery"`
+	DurationMinutes *int        `json:"duration_minutes"`
+}
+
+type MilestoneV2 struct {
+	EstimatedAt *time.Time `json:"estimated_at"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	ActualAt    *time.Time `json:"actual_at"`
+}
+
+type ShipmentFlagsV2 struct {
+	IsDelayed      bool `json:"is_delayed"`
+	HasRules       bool `json:"has_rules"`
+	RulesConfirmed bool `json:"rules_confirmed"`
+}
+
+type RiskV2 struct {
+	Score   int                         `json:"score"`
+	Factors []domainShipment.RiskFactor `json:"factors"`
+}
+
+type NotesV2 struct {
+	Customer   *string `json:"customer"`
+	Completion *string `json:"completion"`
+	Rating     *int    `json:"rating"`
+}
+
+type ShipmentDetailV2 struct {
+	ShipmentV2
+	Rules           *shipment.ShippingRulesResponse   `json:"rules"`
+	StatusHistory   []shipment.StatusHistory          `json:"status_history"`
+	RecentAlerts    []shipment.AlertSummary           `json:"recent_alerts"`
+	Packages        []shipment.PackageResponse        `json:"packages"`
+	Branding        *user.BrandingResponse            `json:"branding"`
+	TermsAcceptance *shipment.TermsAcceptanceResponse `json:"terms_acceptance"`
+}
+
+type ShipmentListV2 struct {
+	Items      []ShipmentV2 `json:"items"`
+	Pagination PaginationV2 `json:"pagination"`
+}
+
+type PaginationV2 struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+func (h *ShipmentV2Handler) GetShipment(c *gin.Context) {
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shipment ID")
+		return
+	}
+
+	result, err := h.service.GetShipment(c.Request.Context(), userID, shipmentID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipment retrieved successfully", toShipmentDetailV2(result))
+}
+
+func (h *ShipmentV2Handler) ListShipments(c *gin.Context) {
+	var filter shipment.ShipmentFilterRequest
+	userID := c.MustGet("userID").(uuid.UUID)
+	userRole := c.MustGet("role").(string)
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.ListShipments(c.Request.Context(), userID, userRole, &filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipments retrieved successfully", toShipmentListV2(result))
+}
+
+func toShipmentV2(s *shipment.ShipmentResponse) ShipmentV2 {
+	v2 := ShipmentV2{
+		ID:     s.ID,
+		Status: s.Status,
+		Parties: PartiesV2{
+			Customer: s.Customer,
+			Provider: s.Provider,
+			Shipper:  s.Shipper,
+			BookedBy: s.BookedBy,
+		},
+		Device: s.Device,
+		Goods: GoodsV2{
+			Description: s.GoodsDescription,
+			Category:    s.GoodsCategory,
+			Weight:      s.GoodsWeight,
+		},
+		Route: RouteV2{
+			Pickup:   AddressV2{Address: s.PickupAddress, AddressID: s.PickupAddressID},
+			Delivery: AddressV2{Address: s.DeliveryAddress, AddressID: s.DeliveryAddressID},
+		},
+		Schedule: ScheduleV2{
+			Pickup: MilestoneV2{EstimatedAt: s.EstimatedPickupAt, ActualAt: s.ActualPickupAt},
+			Delivery: MilestoneV2{
+				EstimatedAt: s.EstimatedDeliveryAt,
+				DueAt:       s.DeliveryDueAt,
+				ActualAt:    s.ActualDeliveryAt,
+			},
+			DurationMinutes: s.DurationMinutes,
+		},
+		Flags: ShipmentFlagsV2{
+			IsDelayed:      s.IsDelayed,
+			HasRules:       s.HasRules,
+			RulesConfirmed: s.RulesConfirmed,
+		},
+		AlertsCount: s.AlertsCount,
+		Warnings:    s.Warnings,
+		Notes: NotesV2{
+			Customer:   s.CustomerNotes,
+			Completion: s.CompletionNotes,
+			Rating:     s.CustomerRating,
+		},
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+	if v2.Warnings == nil {
+		v2.Warnings = []shipment.RuleWarning{}
+	}
+	if s.GoodsValue != nil {
+		v2.Goods.Value = &MoneyV2{Amount: *s.GoodsValue, Currency: s.GoodsCurrency}
+	}
+	if s.RiskScore != nil {
+		v2.Risk = &RiskV2{Score: *s.RiskScore, Factors: s.RiskFactors}
+		if v2.Risk.Factors == nil {
+			v2.Risk.Factors = []domainShipment.RiskFactor{}
+		}
+	}
+	return v2
+}
+
+func toShipmentDetailV2(d *shipment.ShipmentDetailResponse) ShipmentDetailV2 {
+	return ShipmentDetailV2{
+		ShipmentV2:      toShipmentV2(d.ShipmentResponse),
+		Rules:           d.Rules,
+		StatusHistory:   d.StatusHistory,
+		RecentAlerts:    d.RecentAlerts,
+		Packages:        d.Packages,
+		Branding:        d.Branding,
+		TermsAcceptance: d.TermsAcceptance,
+	}
+}
+
+func toShipmentListV2(l *shipment.ShipmentListResponse) ShipmentListV2 {
+	items := make([]ShipmentV2, len(l.Shipments))
+	for i := range l.Shipments {
+		items[i] = toShipmentV2(&l.Shipments[i])
+	}
+	return ShipmentListV2{
+		Items: items,
+		Pagination: PaginationV2{
+			Page:       l.Page,
+			PageSize:   l.PageSize,
+			Total:      l.Total,
+			TotalPages: l.TotalPages,
+		},
+	}
+}
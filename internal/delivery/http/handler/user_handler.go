@@ -1,6 +1,7 @@
 package handler
 
 import (
+	domainUser "cargo-tracker/internal/domain/user"
 	"cargo-tracker/internal/usecase/user"
 	"errors"
 	"net/http"
@@ -30,6 +31,8 @@ func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
 		userGroup.POST("/login", h.Login)
 		userGroup.POST("/forgot-password", h.ForgotPassword)
 		userGroup.POST("/reset-password", h.ResetPassword)
+		userGroup.POST("/forgot-password/phone", h.ForgotPasswordByPhone)
+		userGroup.POST("/reset-password/phone", h.ResetPasswordByPhone)
 		userGroup.POST("/refresh", h.RefreshToken)
 		userGroup.POST("/revoke", h.RevokeToken)
 	}
@@ -38,8 +41,19 @@ func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
 func (h *UserHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
 	admin := router.Group("")
 	{
-		admin.GET("/users", h.GetAllUsers)
+		admin.GET("/users", h.ListUsers)
+		admin.GET("/users/count", h.CountUsers)
 		admin.DELETE("/users/:user_id", h.DeleteUser)
+		admin.PUT("/users/:user_id/role", h.ChangeRole)
+	}
+}
+
+// RegisterAnalyticsRoutes registers read-only user listing for analysts
+func (h *UserHandler) RegisterAnalyticsRoutes(router *gin.RouterGroup) {
+	analytics := router.Group("/analytics")
+	{
+		analytics.GET("/users", h.ListUsers)
+		analytics.GET("/users/count", h.CountUsers)
 	}
 }
 
@@ -49,6 +63,8 @@ func (h *UserHandler) RegisterProfileRoutes(router *gin.RouterGroup) {
 		profile.GET("", h.GetProfile)
 		profile.PUT("", h.UpdateProfile)
 		profile.POST("/change-password", h.ChangePassword)
+		profile.POST("/phone/verification", h.RequestPhoneVerification)
+		profile.POST("/phone/verify", h.VerifyPhone)
 	}
 }
 
@@ -119,6 +135,42 @@ func (h *UserHandler) ForgotPassword(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "If the email exists, a reset link has been sent", nil)
 }
 
+func (h *UserHandler) ForgotPasswordByPhone(c *gin.Context) {
+	var req user.ForgotPasswordPhoneRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.PhoneNumber = utils.SanitizeString(req.PhoneNumber)
+
+	if err := h.service.ForgotPasswordByPhone(c.Request.Context(), &req); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "If the phone number is registered and verified, a code has been sent", nil)
+}
+
+func (h *UserHandler) ResetPasswordByPhone(c *gin.Context) {
+	var req user.ResetPasswordPhoneRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.PhoneNumber = utils.SanitizeString(req.PhoneNumber)
+
+	if err := h.service.ResetPasswordByPhone(c.Request.Context(), &req); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
+}
+
 func (h *UserHandler) ResetPassword(c *gin.Context) {
 	var req user.ResetPasswordRequest
 
@@ -135,16 +187,47 @@ func (h *UserHandler) ResetPassword(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
 }
 
-func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.service.GetAllUsers(c.Request.Context())
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	var filter user.UserFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	users, err := h.service.ListUsers(c.Request.Context(), &filter)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get users")
+		respondWithUserListError(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", users)
 }
 
+func (h *UserHandler) CountUsers(c *gin.Context) {
+	var filter user.UserFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.CountUsers(c.Request.Context(), &filter)
+	if err != nil {
+		respondWithUserListError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Users counted successfully", result)
+}
+
+func respondWithUserListError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+		return
+	}
+	utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get users")
+}
+
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userIDParam := c.Param("user_id")
 	userID, err := uuid.Parse(userIDParam)
@@ -162,6 +245,30 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
 }
 
+func (h *UserHandler) ChangeRole(c *gin.Context) {
+	adminID := c.MustGet("userID").(uuid.UUID)
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req user.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.ChangeRole(c.Request.Context(), adminID, userID, &req)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User role changed successfully", result)
+}
+
 func (h *UserHandler) RefreshToken(c *gin.Context) {
 	refreshToken := c.GetHeader("Authorization")
 	if refreshToken == "" {
@@ -304,18 +411,50 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Password changed successfully", nil)
 }
 
+func (h *UserHandler) RequestPhoneVerification(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.service.RequestPhoneVerification(c.Request.Context(), userID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Verification code sent", nil)
+}
+
+func (h *UserHandler) VerifyPhone(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req user.VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.VerifyPhone(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Phone number verified successfully", result)
+}
+
 func respondWithError(c *gin.Context, err error) {
 	if err == nil {
 		return
 	}
 
 	switch {
-	case errors.Is(err, appErrors.ErrUserAlreadyExists):
+	case errors.Is(err, appErrors.ErrUserAlreadyExists),
+		errors.Is(err, domainUser.ErrPhoneAlreadyExists):
 		utils.ErrorResponse(c, http.StatusConflict, err.Error())
 	case errors.Is(err, appErrors.ErrInvalidCredentials),
 		errors.Is(err, appErrors.ErrInvalidToken),
 		errors.Is(err, appErrors.ErrTokenInvalid),
 		errors.Is(err, appErrors.ErrTokenExpired),
+		errors.Is(err, appErrors.ErrSessionDisplaced),
+		errors.Is(err, appErrors.ErrSessionIdle),
 		errors.Is(err, appErrors.ErrUnauthorized):
 		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 	case errors.Is(err, appErrors.ErrUserInactive),
@@ -329,6 +468,10 @@ func respondWithError(c *gin.Context, err error) {
 			switch appErr.Code {
 			case "VALIDATION_ERROR", "WEAK_PASSWORD":
 				utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+			case "OTP_RATE_LIMITED":
+				utils.ErrorResponse(c, http.StatusTooManyRequests, appErr.Message)
+			case "ROLE_NOT_ALLOWED", "INVITATION_REQUIRED":
+				utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
 			default:
 				utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
 			}
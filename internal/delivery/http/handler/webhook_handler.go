@@ -0,0 +1,240 @@
+package handler
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/usecase/notification"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	service *notification.WebhookService
+}
+
+func NewWebhookHandler(service *notification.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+func (h *WebhookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.GET("", h.ListWebhooks)
+		webhooks.POST("", h.CreateWebhook)
+		webhooks.PUT("/:id", h.UpdateWebhook)
+		webhooks.DELETE("/:id", h.DeleteWebhook)
+		webhooks.POST("/:id/test", h.TestWebhook)
+		webhooks.POST("/:id/preview", h.PreviewPayload)
+		webhooks.POST("/:id/rotate-secret", h.RotateSigningSecret)
+		webhooks.GET("/events", h.ListEvents)
+		webhooks.POST("/:id/replay", h.ReplayEvents)
+	}
+}
+
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	result, err := h.service.ListWebhooks(c.Request.Context(), userID)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhooks retrieved successfully", result)
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	userRole := c.MustGet("role").(string)
+
+	var req notification.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Name = utils.SanitizeString(req.Name)
+
+	result, err := h.service.CreateWebhook(c.Request.Context(), userID, userRole, &req)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Webhook created successfully", result)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	var req notification.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Name != nil {
+		sanitized := utils.SanitizeString(*req.Name)
+		req.Name = &sanitized
+	}
+
+	result, err := h.service.UpdateWebhook(c.Request.Context(), userID, webhookID, &req)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook updated successfully", result)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), userID, webhookID); err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook deleted successfully", nil)
+}
+
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	result, err := h.service.TestWebhook(c.Request.Context(), userID, webhookID)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	message := "Test message delivered"
+	if !result.Healthy {
+		message = "Test message could not be delivered"
+	}
+	utils.SuccessResponse(c, http.StatusOK, message, result)
+}
+
+// PreviewPayload shows a sample event filtered the way the webhook receives it
+func (h *WebhookHandler) PreviewPayload(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	var req notification.PreviewWebhookPayloadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.PreviewPayload(c.Request.Context(), userID, webhookID, &req)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook payload preview built successfully", result)
+}
+
+func (h *WebhookHandler) RotateSigningSecret(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	result, err := h.service.RotateSigningSecret(c.Request.Context(), userID, webhookID)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Signing secret rotated", result)
+}
+
+func (h *WebhookHandler) ListEvents(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var query notification.WebhookEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.service.ListEvents(c.Request.Context(), userID, &query)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook events retrieved successfully", result)
+}
+
+// ReplayEvents always runs as a job: replays can be long and the target
+// endpoint may have just recovered
+func (h *WebhookHandler) ReplayEvents(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	var req notification.ReplayWebhookEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	queued, err := h.service.QueueReplay(c.Request.Context(), userID, webhookID, &req)
+	if err != nil {
+		respondWithWebhookError(c, err)
+		return
+	}
+
+	respondWithQueuedJob(c, queued)
+}
+
+func respondWithWebhookError(c *gin.Context, err error) {
+	var appErr *appErrors.AppError
+	switch {
+	case errors.Is(err, domainNotification.ErrWebhookNotFound),
+		errors.Is(err, domainNotification.ErrNoWebhookEvents):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED",
+		errors.As(err, &appErr) && appErr.Code == "PLAN_UPGRADE_REQUIRED":
+		utils.ErrorResponse(c, http.StatusForbidden, appErr.Message)
+	case errors.As(err, &appErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, appErr.Message)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process webhook request")
+	}
+}
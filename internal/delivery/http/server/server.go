@@ -0,0 +1,70 @@
+// Package server builds the HTTP engine and server shared by the
+// application and anything else that needs the full router, so both run
+// the same middleware stack in the same order.
+package server
+
+import (
+	"cargo-tracker/internal/config"
+	"cargo-tracker/internal/middleware"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewEngine creates a gin engine for the configured environment with the
+// global middleware installed. Order matters: recovery wraps everything so
+// a panic anywhere still gets an answer, the request ID is assigned before
+// anything logs, and limits run last so rejected requests are still logged
+// and carry CORS headers. bodyLimits is filled in as routes are registered.
+func NewEngine(cfg *config.Config, bodyLimits *middleware.BodyLimits) *gin.Engine {
+	gin.SetMode(ginMode(cfg.Server.Environment))
+
+	engine := gin.New()
+	engine.Use(
+		middleware.RecoveryMiddleware(),
+		middleware.RequestIDMiddleware(),
+		middleware.LoggingMiddleware(),
+		middleware.TimeoutMiddleware(cfg.Server.RequestTimeout),
+		middleware.SecurityHeadersMiddleware(),
+		middleware.CORSMiddleware(&cfg.CORS),
+		middleware.CompressionMiddleware(&cfg.Compression),
+		middleware.RequestSizeLimitMiddleware(bodyLimits),
+		middleware.RateLimitMiddleware(cfg.RateLimit.GeneralRPS, cfg.RateLimit.GeneralBurst),
+	)
+	return engine
+}
+
+// New creates the HTTP server for handler, listening on the configured
+// address with the configured timeouts
+func New(cfg *config.Config, handler http.Handler) *http.Server {
+	host := cfg.Server.Host
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	port := cfg.Server.Port
+	if port == "" {
+		port = "8080"
+	}
+
+	return &http.Server{
+		Addr:         net.JoinHostPort(host, port),
+		Handler:      handler,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+}
+
+// ginMode maps the environment to a gin mode. Only development gets debug
+// output such as the route table printed at startup.
+func ginMode(environment string) string {
+	switch environment {
+	case "production", "staging":
+		return gin.ReleaseMode
+	case "test":
+		return gin.TestMode
+	default:
+		return gin.DebugMode
+	}
+}
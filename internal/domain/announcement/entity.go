@@ -0,0 +1,54 @@
+package announcement
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Severity tells clients how prominently to show an announcement
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Status is where an announcement is in its time window
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled"
+	StatusActive    Status = "active"
+	StatusExpired   Status = "expired"
+)
+
+// Announcement is a message from operations, such as planned maintenance,
+// shown to every client between StartsAt and EndsAt
+type Announcement struct {
+	ID       uuid.UUID
+	Title    string
+	Message  string
+	Severity Severity
+	// Window the announcement is shown in, EndsAt is exclusive
+	StartsAt time.Time
+	EndsAt   time.Time
+	// Optional downtime the announcement is about, for clients to count down to
+	MaintenanceStartsAt *time.Time
+	MaintenanceEndsAt   *time.Time
+	CreatedBy           uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Status reports where the announcement's window stands at now
+func (a *Announcement) Status(now time.Time) Status {
+	switch {
+	case now.Before(a.StartsAt):
+		return StatusScheduled
+	case now.Before(a.EndsAt):
+		return StatusActive
+	}
+	return StatusExpired
+}
@@ -0,0 +1,7 @@
+package announcement
+
+import "errors"
+
+var (
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+)
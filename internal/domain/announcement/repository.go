@@ -0,0 +1,23 @@
+package announcement
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for announcement repository operations
+type Repository interface {
+	Create(ctx context.Context, a *Announcement) error
+	GetByID(ctx context.Context, announcementID uuid.UUID) (*Announcement, error)
+	Update(ctx context.Context, a *Announcement) error
+	Delete(ctx context.Context, announcementID uuid.UUID) error
+
+	// List returns announcements newest first, leaving out the ones that
+	// ended before now unless includeExpired is set
+	List(ctx context.Context, now time.Time, includeExpired bool) ([]*Announcement, error)
+	// ListUnexpired returns the active and scheduled announcements at now,
+	// soonest first
+	ListUnexpired(ctx context.Context, now time.Time) ([]*Announcement, error)
+}
@@ -0,0 +1,66 @@
+package cleanup
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Target is the kind of record a cleanup job deletes
+type Target string
+
+const (
+	TargetShipments Target = "shipments"
+	TargetDevices   Target = "devices"
+	TargetUsers     Target = "users"
+)
+
+// JobStatus represents the progress of a cleanup job
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+// IsFinished reports whether the job no longer makes progress
+func (s JobStatus) IsFinished() bool {
+	return s != JobRunning
+}
+
+// Filter selects the records a job deletes. Empty fields do not restrict.
+type Filter struct {
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	SandboxOnly   bool       `json:"sandbox_only,omitempty"` // Shipments and users
+	Status        string     `json:"status,omitempty"`       // Shipment or device status
+	Role          string     `json:"role,omitempty"`         // Users
+	Search        string     `json:"search,omitempty"`       // Goods description, device name or UID, user email or name
+}
+
+// Job deletes the records matching a filter in throttled batches
+type Job struct {
+	ID          uuid.UUID
+	Target      Target
+	Filter      Filter
+	DryRun      bool // Count matches without deleting
+	Status      JobStatus
+	Matched     int64
+	Deleted     int64
+	Skipped     int64 // Matched records that could not be deleted, e.g. devices in use
+	LastError   *string
+	RequestedBy uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+// BatchResult is the outcome of deleting one batch
+type BatchResult struct {
+	Deleted int64
+	Skipped []uuid.UUID // Records that must be kept, e.g. devices in use
+	// Stored files of deleted shipment attachments, to remove from storage
+	StorageKeys []string
+}
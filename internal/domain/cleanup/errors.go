@@ -0,0 +1,9 @@
+package cleanup
+
+import "errors"
+
+var (
+	ErrJobNotFound = errors.New("cleanup job not found")
+	ErrJobRunning  = errors.New("another cleanup job is still running")
+	ErrDisabled    = errors.New("bulk cleanup is disabled in this environment")
+)
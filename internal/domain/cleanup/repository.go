@@ -0,0 +1,25 @@
+package cleanup
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for cleanup repository operations
+type Repository interface {
+	CreateJob(ctx context.Context, job *Job) error
+	UpdateJob(ctx context.Context, job *Job) error
+	GetJob(ctx context.Context, jobID uuid.UUID) (*Job, error)
+	ListJobs(ctx context.Context, limit int) ([]*Job, error)
+
+	Count(ctx context.Context, target Target, filter *Filter, exclude []uuid.UUID) (int64, error)
+	// NextBatch returns up to limit IDs matching the filter, skipping the
+	// excluded ones
+	NextBatch(ctx context.Context, target Target, filter *Filter, exclude []uuid.UUID, limit int) ([]uuid.UUID, error)
+	// DeleteBatch deletes the records and everything that depends on them
+	// in one transaction. Records that must be kept are skipped.
+	DeleteBatch(ctx context.Context, target Target, ids []uuid.UUID) (*BatchResult, error)
+	// FailRunning marks jobs left running by a previous process as failed
+	FailRunning(ctx context.Context, reason string) (int64, error)
+}
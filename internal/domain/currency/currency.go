@@ -0,0 +1,20 @@
+package currency
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("no exchange rate for currency")
+	ErrRatesUnavailable = errors.New("exchange rates are unavailable")
+)
+
+// RateSource provides exchange rates into the reporting currency, the
+// currency platform-wide figures such as statistics are aggregated in.
+type RateSource interface {
+	ReportingCurrency() string
+	// Rate returns the value of one unit of currency in the reporting
+	// currency
+	Rate(ctx context.Context, currency string) (float64, error)
+}
@@ -0,0 +1,73 @@
+package device
+
+import (
+	"context"
+	"time"
+)
+
+// Capability is something a tracker can measure
+type Capability string
+
+const (
+	CapabilityTemperature Capability = "temperature"
+	CapabilityHumidity    Capability = "humidity"
+	CapabilityLight       Capability = "light"
+	CapabilityTilt        Capability = "tilt"
+	CapabilityImpact      Capability = "impact"
+	CapabilityGPS         Capability = "gps"
+)
+
+// Capabilities lists every known capability
+var Capabilities = []Capability{
+	CapabilityTemperature,
+	CapabilityHumidity,
+	CapabilityLight,
+	CapabilityTilt,
+	CapabilityImpact,
+	CapabilityGPS,
+}
+
+// IsValid reports whether c is a known capability
+func (c Capability) IsValid() bool {
+	for _, known := range Capabilities {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ModelProfile records the sensors of one tracker model. Devices are linked
+// to it by their Model.
+type ModelProfile struct {
+	Model        string
+	Capabilities []Capability
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Missing returns the required capabilities that are not in have
+func Missing(have, required []Capability) []Capability {
+	var missing []Capability
+	for _, c := range required {
+		found := false
+		for _, h := range have {
+			if h == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// ModelProfileRepository stores tracker model profiles
+type ModelProfileRepository interface {
+	// Upsert creates the profile or replaces its capabilities
+	Upsert(ctx context.Context, profile *ModelProfile) error
+	List(ctx context.Context) ([]*ModelProfile, error)
+	Delete(ctx context.Context, model string) error
+}
@@ -0,0 +1,93 @@
+package device
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FirmwareStatus compares a device's firmware with the fleet's target
+type FirmwareStatus string
+
+const (
+	FirmwareCurrent  FirmwareStatus = "current"  // Target version or newer
+	FirmwareOutdated FirmwareStatus = "outdated" // Older than the target
+	FirmwareUnknown  FirmwareStatus = "unknown"  // Device never reported its version
+)
+
+// BatteryHealth buckets a device's last reported battery level
+type BatteryHealth string
+
+const (
+	BatteryGood     BatteryHealth = "good"
+	BatteryLow      BatteryHealth = "low"
+	BatteryCritical BatteryHealth = "critical"
+	BatteryUnknown  BatteryHealth = "unknown" // No level reported yet
+)
+
+// CompareFirmware orders two firmware versions such as "1.10.2" and
+// "v1.9", numerically part by part. Parts that are not numbers compare as
+// text. It returns -1, 0 or 1.
+func CompareFirmware(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(strings.TrimSpace(a), "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(strings.TrimSpace(b), "v"), ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y string
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if c := comparePart(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func comparePart(x, y string) int {
+	nx, errX := strconv.Atoi(orZero(x))
+	ny, errY := strconv.Atoi(orZero(y))
+	if errX == nil && errY == nil {
+		switch {
+		case nx < ny:
+			return -1
+		case nx > ny:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(x, y)
+}
+
+func orZero(part string) string {
+	if part == "" {
+		return "0"
+	}
+	return part
+}
+
+// ClassifyFirmware compares version with target. Every reported version is
+// current when there is no target.
+func ClassifyFirmware(version *string, target string) FirmwareStatus {
+	if version == nil || strings.TrimSpace(*version) == "" {
+		return FirmwareUnknown
+	}
+	if target == "" || CompareFirmware(*version, target) >= 0 {
+		return FirmwareCurrent
+	}
+	return FirmwareOutdated
+}
+
+// ClassifyBattery buckets level; a level at or below a threshold falls in it
+func ClassifyBattery(level *int, low, critical int) BatteryHealth {
+	switch {
+	case level == nil:
+		return BatteryUnknown
+	case *level <= critical:
+		return BatteryCritical
+	case *level <= low:
+		return BatteryLow
+	}
+	return BatteryGood
+}
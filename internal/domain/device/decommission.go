@@ -0,0 +1,31 @@
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Decommission is the certificate recorded when a device is retired for good.
+// It points at the archive holding the device's history as it was before the
+// device was anonymized.
+type Decommission struct {
+	ID               uuid.UUID
+	DeviceID         uuid.UUID
+	HardwareUID      string
+	Reason           string
+	DecommissionedBy uuid.UUID
+	OwnerUnlinked    bool // The device had an owner that was removed
+	ShipmentCount    int  // Shipments the device tracked, as exported
+	ArchiveKey       string
+	ArchiveSHA256    string
+	ArchiveSize      int64
+	CreatedAt        time.Time
+}
+
+// DecommissionRepository stores decommission certificates
+type DecommissionRepository interface {
+	Create(ctx context.Context, decommission *Decommission) error
+	GetByDevice(ctx context.Context, deviceID uuid.UUID) (*Decommission, error)
+}
@@ -19,8 +19,13 @@ type Device struct {
 	BatteryLevel      *int
 	TotalTrips        int
 	LastSeenAt        *time.Time
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	// When the device API credential was last issued; nil if it has none
+	CredentialIssuedAt *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+
+	// Capabilities of the model; nil when the model has no profile
+	Capabilities []Capability
 }
 
 // DeviceStatus represents the status of a device
@@ -11,4 +11,14 @@ var (
 	ErrNoOwner                 = errors.New("device has no owner")
 	ErrAssignmentFailed        = errors.New("assignment failed")
 	ErrUnassignmentFailed      = errors.New("unassignment failed")
+	ErrDecommissionNotFound    = errors.New("device has not been decommissioned")
+	ErrAlreadyDecommissioned   = errors.New("device is already decommissioned")
+	ErrTransferNotFound        = errors.New("device transfer not found")
+	ErrTransferPending         = errors.New("device already has a pending transfer")
+	ErrTransferNotPending      = errors.New("device transfer is no longer pending")
+	ErrModelProfileNotFound    = errors.New("device model profile not found")
+	ErrDeviceRetired           = errors.New("device is retired")
+	ErrInvalidCredential       = errors.New("invalid device credential")
+	ErrSelfTestNotFound        = errors.New("no pending self-test")
+	ErrSelfTestPending         = errors.New("device already has a pending self-test")
 )
@@ -18,7 +18,17 @@ type Repository interface {
 	UpdateStatus(ctx context.Context, deviceID uuid.UUID, status DeviceStatus) error
 	UpdateBattery(ctx context.Context, deviceID uuid.UUID, batteryLevel int) error
 	UpdateLastSeen(ctx context.Context, deviceID uuid.UUID) error
+	// SetCredentialHash replaces the hash of the secret the device
+	// authenticates to the device API with
+	SetCredentialHash(ctx context.Context, deviceID uuid.UUID, hash string) error
+	GetByCredentialHash(ctx context.Context, hash string) (*Device, error)
+	// Anonymize retires a device and clears everything linking it to a
+	// shipper: owner, name, current shipment and last readings
+	Anonymize(ctx context.Context, deviceID uuid.UUID) error
 	List(ctx context.Context, filter *Filter) ([]*Device, int64, error)
+	// ListFleet returns every device that is not retired, only those owned
+	// by ownerID when it is set
+	ListFleet(ctx context.Context, ownerID *uuid.UUID) ([]*Device, error)
 	GetStatistics(ctx context.Context) (*Statistics, error)
 }
 
@@ -34,6 +44,9 @@ type Filter struct {
 	PageSize       int
 	SortBy         string
 	SortOrder      string
+
+	// Only devices whose model has all of these
+	Capabilities []Capability
 }
 
 // Statistics represents device statistics
@@ -0,0 +1,56 @@
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SelfTestStatus represents the state of a device self-test
+type SelfTestStatus string
+
+const (
+	SelfTestPending SelfTestStatus = "pending"
+	SelfTestPassed  SelfTestStatus = "passed"
+	SelfTestFailed  SelfTestStatus = "failed"
+)
+
+// SelfTest is a check of a device before it is given a shipment. An admin or
+// the owning shipper requests it, the device picks it up, runs it and
+// reports its sensor sanity readings, GPS fix, battery and signal.
+type SelfTest struct {
+	ID          uuid.UUID
+	DeviceID    uuid.UUID
+	RequestedBy uuid.UUID
+	Status      SelfTestStatus
+	RequestedAt time.Time
+	CompletedAt *time.Time
+
+	// Results, set once the device reported
+	SensorsOK    *bool
+	Readings     map[string]float64 // Sanity readings by sensor
+	GPSFix       *bool
+	BatteryLevel *int
+	SignalDBm    *int
+	// Failures says why a failed test failed, one entry per check
+	Failures []string
+}
+
+// SelfTestRepository stores device self-tests
+type SelfTestRepository interface {
+	// Create fails with ErrSelfTestPending when the device already has a
+	// pending self-test
+	Create(ctx context.Context, test *SelfTest) error
+	// GetPending returns the device's pending self-test, or
+	// ErrSelfTestNotFound when there is none
+	GetPending(ctx context.Context, deviceID uuid.UUID) (*SelfTest, error)
+	// Complete records the results of a pending self-test. It fails with
+	// ErrSelfTestNotFound when the test is no longer pending.
+	Complete(ctx context.Context, test *SelfTest) error
+	// ListByDevice returns the device's most recent self-tests, newest first
+	ListByDevice(ctx context.Context, deviceID uuid.UUID, limit int) ([]*SelfTest, error)
+	// LastPassedAt returns when the device last passed a self-test, nil when
+	// it never did
+	LastPassedAt(ctx context.Context, deviceID uuid.UUID) (*time.Time, error)
+}
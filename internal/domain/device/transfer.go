@@ -0,0 +1,54 @@
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferStatus represents the state of an ownership transfer
+type TransferStatus string
+
+const (
+	TransferPending   TransferStatus = "pending"
+	TransferAccepted  TransferStatus = "accepted"
+	TransferDeclined  TransferStatus = "declined"
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+// Transfer hands a device from one shipper to another. The current owner
+// offers the device and the recipient accepts it. Trips and shipments the
+// device tracked stay with the shipper who ran them; only data recorded
+// after the transfer belongs to the new owner.
+type Transfer struct {
+	ID            uuid.UUID
+	DeviceID      uuid.UUID
+	FromShipperID uuid.UUID
+	ToShipperID   uuid.UUID
+	Status        TransferStatus
+	Note          *string
+	CreatedAt     time.Time
+	RespondedAt   *time.Time
+}
+
+// TransferRepository stores ownership transfers
+type TransferRepository interface {
+	// Create fails with ErrTransferPending when the device already has a
+	// pending transfer
+	Create(ctx context.Context, transfer *Transfer) error
+	GetByID(ctx context.Context, transferID uuid.UUID) (*Transfer, error)
+	// ListByShipper returns the transfers a shipper sent or received,
+	// newest first
+	ListByShipper(ctx context.Context, shipperID uuid.UUID) ([]*Transfer, error)
+	// HasOpenWork reports whether the device is on a shipment or trip that
+	// has not finished
+	HasOpenWork(ctx context.Context, deviceID uuid.UUID) (bool, error)
+	// Accept moves the device to the recipient. It fails with
+	// ErrDeviceInUse when the device has open work and with
+	// ErrTransferNotPending when the transfer was answered or the device
+	// changed hands in the meantime.
+	Accept(ctx context.Context, transferID uuid.UUID, at time.Time) error
+	// Close declines or cancels a pending transfer
+	Close(ctx context.Context, transferID uuid.UUID, status TransferStatus, at time.Time) error
+}
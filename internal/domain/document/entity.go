@@ -0,0 +1,131 @@
+package document
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DocumentType identifies the kind of shipping document
+type DocumentType string
+
+const (
+	TypeCommercialInvoice    DocumentType = "commercial_invoice"
+	TypePackingList          DocumentType = "packing_list"
+	TypeCertificateOfOrigin  DocumentType = "certificate_of_origin"
+	TypeCustomsDeclaration   DocumentType = "customs_declaration"
+	TypeBillOfLading         DocumentType = "bill_of_lading"
+	TypeHealthCertificate    DocumentType = "health_certificate"
+	TypeDangerousGoodsNotice DocumentType = "dangerous_goods_declaration"
+	TypeOther                DocumentType = "other"
+)
+
+// ChecklistStatus represents the review state of a required document
+type ChecklistStatus string
+
+const (
+	ChecklistPending   ChecklistStatus = "pending"   // Waiting for upload
+	ChecklistSubmitted ChecklistStatus = "submitted" // Uploaded, waiting for review
+	ChecklistVerified  ChecklistStatus = "verified"  // Accepted by provider
+	ChecklistRejected  ChecklistStatus = "rejected"  // Needs a new upload
+)
+
+// ChecklistItem is one document slot a shipment needs before it can ship
+type ChecklistItem struct {
+	ID           uuid.UUID
+	ShipmentID   uuid.UUID
+	DocumentType DocumentType
+	Label        string
+	Required     bool
+	Status       ChecklistStatus
+	AttachmentID *uuid.UUID
+	ReviewedBy   *uuid.UUID
+	ReviewedAt   *time.Time
+	ReviewNote   *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// IsComplete reports whether the item no longer blocks the shipment
+func (i *ChecklistItem) IsComplete() bool {
+	return !i.Required || i.Status == ChecklistVerified
+}
+
+// Attachment is a file uploaded against a shipment
+type Attachment struct {
+	ID              uuid.UUID
+	ShipmentID      uuid.UUID
+	ChecklistItemID *uuid.UUID
+	DocumentType    DocumentType
+	FileName        string
+	ContentType     string
+	SizeBytes       int64
+	StorageKey      string
+	UploadedBy      uuid.UUID
+	Visibility      Visibility
+	CreatedAt       time.Time
+}
+
+// Party is a role a user holds on a shipment
+type Party string
+
+const (
+	PartyCustomer Party = "customer"
+	PartyProvider Party = "provider"
+	PartyShipper  Party = "shipper"
+	PartyAdmin    Party = "admin"
+	PartyGrantee  Party = "grantee" // Third party with a documents access grant
+)
+
+// Visibility lists the shipment parties that may see an attachment
+type Visibility struct {
+	Customer bool
+	Provider bool
+	Shipper  bool
+	Admin    bool
+}
+
+// DefaultVisibility is applied when the uploader does not choose: commercial
+// invoices carry prices the shipper has no business seeing, everything else
+// is needed along the route.
+func DefaultVisibility(t DocumentType) Visibility {
+	v := Visibility{Customer: true, Provider: true, Shipper: true, Admin: true}
+	if t == TypeCommercialInvoice {
+		v.Shipper = false
+	}
+	return v
+}
+
+// Allows reports whether party may see the attachment. Grantees only see
+// attachments open to every shipment party.
+func (v Visibility) Allows(party Party) bool {
+	switch party {
+	case PartyCustomer:
+		return v.Customer
+	case PartyProvider:
+		return v.Provider
+	case PartyShipper:
+		return v.Shipper
+	case PartyAdmin:
+		return v.Admin
+	case PartyGrantee:
+		return v.Customer && v.Provider && v.Shipper
+	default:
+		return false
+	}
+}
+
+// With returns v with party allowed
+func (v Visibility) With(party Party) Visibility {
+	switch party {
+	case PartyCustomer:
+		v.Customer = true
+	case PartyProvider:
+		v.Provider = true
+	case PartyShipper:
+		v.Shipper = true
+	case PartyAdmin:
+		v.Admin = true
+	}
+	return v
+}
@@ -0,0 +1,10 @@
+package document
+
+import "errors"
+
+var (
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrDocumentsIncomplete   = errors.New("required documents are not complete")
+	ErrUnsupportedFileType   = errors.New("unsupported file type")
+)
@@ -0,0 +1,21 @@
+package document
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for shipment document operations
+type Repository interface {
+	ReplaceChecklist(ctx context.Context, shipmentID uuid.UUID, items []*ChecklistItem) error
+	GetChecklist(ctx context.Context, shipmentID uuid.UUID) ([]*ChecklistItem, error)
+	GetChecklistItem(ctx context.Context, itemID uuid.UUID) (*ChecklistItem, error)
+	UpdateChecklistItem(ctx context.Context, item *ChecklistItem) error
+	CountIncompleteRequired(ctx context.Context, shipmentID uuid.UUID) (int64, error)
+
+	CreateAttachment(ctx context.Context, attachment *Attachment) error
+	GetAttachment(ctx context.Context, attachmentID uuid.UUID) (*Attachment, error)
+	ListAttachments(ctx context.Context, shipmentID uuid.UUID) ([]*Attachment, error)
+	UpdateAttachmentVisibility(ctx context.Context, attachment *Attachment) error
+}
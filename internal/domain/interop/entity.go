@@ -0,0 +1,63 @@
+package interop
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format is the wire format milestones are exported in
+type Format string
+
+const (
+	FormatEPCIS Format = "epcis" // GS1 EPCIS 2.0 JSON-LD document
+	FormatJSON  Format = "json"  // Flat JSON records shaped by the partner's export mapping
+)
+
+// PartnerMapping describes how shipment data is exchanged with one trading
+// partner of a customer or provider.
+type PartnerMapping struct {
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+	Name    string
+	Format  Format
+
+	// RecordsPath points at the array of shipment records inside an import
+	// message. Empty means the message is a single record or a bare array.
+	RecordsPath string
+	// ImportFields maps shipment fields (e.g. "pickup_address") to dotted
+	// paths in the partner's message (e.g. "shipFrom.address.0.line")
+	ImportFields map[string]string
+	// DefaultProviderID is used for imported records without a provider
+	DefaultProviderID *uuid.UUID
+
+	// ExportFields maps output keys to milestone fields for FormatJSON
+	ExportFields map[string]string
+
+	IsActive  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MilestoneType is a shipment lifecycle event shared with partners
+type MilestoneType string
+
+const (
+	MilestoneCreated        MilestoneType = "shipment_created"
+	MilestonePickedUp       MilestoneType = "picked_up"
+	MilestoneDelivered      MilestoneType = "delivered"
+	MilestonePackageOutcome MilestoneType = "package_outcome"
+	MilestoneIssueReported  MilestoneType = "issue_reported"
+	MilestoneCancelled      MilestoneType = "cancelled"
+)
+
+// Milestone is one exported shipment event
+type Milestone struct {
+	Type       MilestoneType
+	ShipmentID uuid.UUID
+	PackageID  *uuid.UUID
+	Status     string
+	Location   string
+	Note       *string
+	OccurredAt time.Time
+}
@@ -0,0 +1,8 @@
+package interop
+
+import "errors"
+
+var (
+	ErrPartnerNotFound = errors.New("partner mapping not found")
+	ErrPartnerInactive = errors.New("partner mapping is inactive")
+)
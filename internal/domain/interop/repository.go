@@ -0,0 +1,16 @@
+package interop
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for partner mapping operations
+type Repository interface {
+	Create(ctx context.Context, mapping *PartnerMapping) error
+	GetByID(ctx context.Context, mappingID uuid.UUID) (*PartnerMapping, error)
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*PartnerMapping, error)
+	Update(ctx context.Context, mapping *PartnerMapping) error
+	Delete(ctx context.Context, mappingID uuid.UUID) error
+}
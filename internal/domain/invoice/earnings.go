@@ -0,0 +1,21 @@
+package invoice
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipperDelivery is a shipment a shipper delivered, with the price agreed
+// through an accepted quote when there was one
+type ShipperDelivery struct {
+	ShipmentID       uuid.UUID
+	ShipperID        uuid.UUID
+	Status           string
+	GoodsDescription string
+	Price            *float64
+	Currency         *string
+	DeliveryDueAt    *time.Time
+	ActualDeliveryAt time.Time
+	CustomerRating   *int
+}
@@ -0,0 +1,98 @@
+package invoice
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status represents the payment status of an invoice
+type Status string
+
+const (
+	StatusIssued Status = "issued" // Sent to the customer, awaiting payment
+	StatusPaid   Status = "paid"   // Payment received
+	StatusVoid   Status = "void"   // Cancelled by the provider; not payable
+)
+
+// LineKind classifies invoice lines
+type LineKind string
+
+const (
+	LineShipmentFee     LineKind = "shipment_fee"     // Agreed price of a delivered shipment
+	LineCancellationFee LineKind = "cancellation_fee" // Charged for a cancellation after acceptance
+	LineSLAPenalty      LineKind = "sla_penalty"      // Credit for a late delivery, negative
+	LineAdjustment      LineKind = "adjustment"       // Manual correction by the provider
+)
+
+// Invoice bills a customer for one provider's shipments in a billing period
+type Invoice struct {
+	ID         uuid.UUID
+	Number     string
+	ProviderID uuid.UUID
+	CustomerID uuid.UUID
+
+	// Billing period, PeriodEnd is exclusive
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+
+	Currency    string
+	Subtotal    float64 // Sum of fee lines
+	Adjustments float64 // Sum of penalty and adjustment lines
+	Total       float64
+
+	Status           Status
+	DueAt            time.Time
+	PaidAt           *time.Time
+	PaymentReference *string
+
+	Lines []InvoiceLine
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// InvoiceLine is one charge or credit of an invoice
+type InvoiceLine struct {
+	ID          uuid.UUID
+	InvoiceID   uuid.UUID
+	ShipmentID  *uuid.UUID
+	Kind        LineKind
+	Description string
+	Amount      float64
+	Priced      bool // False when the shipment had no agreed price
+	CreatedAt   time.Time
+}
+
+// IsFee reports whether the line counts towards the subtotal
+func (k LineKind) IsFee() bool {
+	return k == LineShipmentFee || k == LineCancellationFee
+}
+
+// Recalculate updates the subtotal, adjustments and total from the lines
+func (i *Invoice) Recalculate() {
+	i.Subtotal, i.Adjustments = 0, 0
+	for _, line := range i.Lines {
+		if line.Kind.IsFee() {
+			i.Subtotal += line.Amount
+		} else {
+			i.Adjustments += line.Amount
+		}
+	}
+	i.Total = i.Subtotal + i.Adjustments
+}
+
+// BillableShipment is a finished shipment not invoiced yet, with the price
+// agreed through an accepted quote when there was one
+type BillableShipment struct {
+	ShipmentID       uuid.UUID
+	CustomerID       uuid.UUID
+	ProviderID       uuid.UUID
+	Status           string
+	GoodsDescription string
+	Price            *float64
+	Currency         *string
+	DeliveryDueAt    *time.Time
+	ActualDeliveryAt *time.Time
+	FinishedAt       time.Time
+}
@@ -0,0 +1,9 @@
+package invoice
+
+import "errors"
+
+var (
+	ErrInvoiceNotFound = errors.New("invoice not found")
+	ErrInvoiceExists   = errors.New("invoice already exists for this period")
+	ErrInvoiceClosed   = errors.New("invoice is paid or void")
+)
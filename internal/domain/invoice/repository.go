@@ -0,0 +1,35 @@
+package invoice
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for invoice repository operations
+type Repository interface {
+	// ListBillable returns shipments finished in [from, to) that are not on
+	// any invoice yet
+	ListBillable(ctx context.Context, from, to time.Time) ([]*BillableShipment, error)
+	// Create stores an invoice with its lines
+	Create(ctx context.Context, invoice *Invoice) error
+	GetByID(ctx context.Context, invoiceID uuid.UUID) (*Invoice, error)
+	List(ctx context.Context, filter *Filter) ([]*Invoice, int64, error)
+	// AddLine appends a line and stores the recalculated totals of invoice
+	AddLine(ctx context.Context, invoice *Invoice, line *InvoiceLine) error
+	UpdateStatus(ctx context.Context, invoice *Invoice) error
+	// ListShipperDeliveries returns the shipments the shipper delivered in
+	// [from, to)
+	ListShipperDeliveries(ctx context.Context, shipperID uuid.UUID, from, to time.Time) ([]*ShipperDelivery, error)
+}
+
+// Filter represents filtering options for listing invoices
+type Filter struct {
+	ProviderID  *uuid.UUID
+	CustomerID  *uuid.UUID
+	Status      *Status
+	PeriodStart *time.Time
+	Page        int
+	PageSize    int
+}
@@ -0,0 +1,58 @@
+package job
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status represents the lifecycle of a background job
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// IsFinished reports whether the job will not run again
+func (s Status) IsFinished() bool {
+	return s == StatusSucceeded || s == StatusFailed
+}
+
+// Job is long-running work queued by a request and executed by a worker.
+// Payload and Result are JSON documents whose shape depends on Kind.
+type Job struct {
+	ID              uuid.UUID
+	Kind            string
+	OwnerID         uuid.UUID
+	Status          Status
+	Payload         []byte
+	Progress        int // Percent, 0-100
+	ProgressMessage *string
+	Result          []byte
+	ResultFile      *File
+	Error           *string
+	Attempts        int
+	MaxAttempts     int
+	RunAt           time.Time  // Earliest time the next attempt may start
+	LockedUntil     *time.Time // Lease of the worker running the job
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+}
+
+// File is a downloadable result kept in storage
+type File struct {
+	StorageKey  string
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+}
+
+// CanRetry reports whether a failed attempt may be repeated
+func (j *Job) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
@@ -0,0 +1,9 @@
+package job
+
+import "errors"
+
+var (
+	ErrJobNotFound    = errors.New("job not found")
+	ErrNoJobAvailable = errors.New("no job available")
+	ErrLeaseLost      = errors.New("job lease lost")
+)
@@ -0,0 +1,28 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for job repository operations
+type Repository interface {
+	Create(ctx context.Context, job *Job) error
+	GetByID(ctx context.Context, jobID uuid.UUID) (*Job, error)
+	ListByOwner(ctx context.Context, ownerID uuid.UUID, limit int) ([]*Job, error)
+
+	// Claim leases the next due job of one of the kinds to a worker. Jobs
+	// whose lease expired, because their worker died, are claimed again.
+	Claim(ctx context.Context, kinds []string, lease time.Duration) (*Job, error)
+	// UpdateProgress records progress and extends the lease of a running job
+	UpdateProgress(ctx context.Context, jobID uuid.UUID, progress int, message string, lease time.Duration) error
+	// Finish stores the outcome of an attempt: the status, result, error and
+	// next run time of job
+	Finish(ctx context.Context, job *Job) error
+
+	// DeleteFinishedBefore removes finished jobs and returns the storage
+	// keys of their result files
+	DeleteFinishedBefore(ctx context.Context, before time.Time) ([]string, error)
+}
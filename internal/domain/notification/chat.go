@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatProvider identifies a messaging app a bot runs on
+type ChatProvider string
+
+const (
+	ChatTelegram ChatProvider = "telegram"
+	ChatZalo     ChatProvider = "zalo"
+)
+
+// ChatLink connects a user account to a chat with one of the bots
+type ChatLink struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Provider  ChatProvider
+	ChatID    string
+	IsActive  bool
+	LinkedAt  time.Time
+	UpdatedAt time.Time
+}
+
+// ChatLinkCode is a one-time code a user sends to the bot to link their chat
+type ChatLinkCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Provider  ChatProvider
+	CodeHash  string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// ChatDeliveryStatus tracks a bot message through the provider's receipts
+type ChatDeliveryStatus string
+
+const (
+	ChatDeliveryFailed    ChatDeliveryStatus = "failed"
+	ChatDeliverySent      ChatDeliveryStatus = "sent"
+	ChatDeliveryDelivered ChatDeliveryStatus = "delivered"
+	ChatDeliverySeen      ChatDeliveryStatus = "seen"
+)
+
+// Rank orders statuses so late receipts never move a delivery backwards
+func (s ChatDeliveryStatus) Rank() int {
+	switch s {
+	case ChatDeliverySent:
+		return 1
+	case ChatDeliveryDelivered:
+		return 2
+	case ChatDeliverySeen:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ChatDelivery records one message pushed through a bot
+type ChatDelivery struct {
+	ID                uuid.UUID
+	LinkID            uuid.UUID
+	UserID            uuid.UUID
+	Provider          ChatProvider
+	Event             string
+	ProviderMessageID *string
+	Status            ChatDeliveryStatus
+	Error             *string
+	SentAt            time.Time
+	DeliveredAt       *time.Time
+	SeenAt            *time.Time
+}
+
+// ChatEventKind is the kind of an inbound bot webhook event
+type ChatEventKind string
+
+const (
+	ChatEventMessage   ChatEventKind = "message"
+	ChatEventDelivered ChatEventKind = "delivered"
+	ChatEventSeen      ChatEventKind = "seen"
+	// ChatEventBlocked means the user blocked or left the bot
+	ChatEventBlocked ChatEventKind = "blocked"
+)
+
+// ChatEvent is a provider independent view of a bot webhook event
+type ChatEvent struct {
+	Kind       ChatEventKind
+	ChatID     string
+	Text       string
+	MessageIDs []string
+	At         time.Time
+}
+
+// ChatBot sends messages through a messaging app bot and understands its webhooks
+type ChatBot interface {
+	Provider() ChatProvider
+	// Send delivers text to a chat and returns the provider's message ID
+	Send(ctx context.Context, chatID, text string) (string, error)
+	// DeepLink returns a link that opens the bot with the code prefilled, if supported
+	DeepLink(code string) string
+	// VerifyWebhook checks the authenticity of an inbound webhook request
+	VerifyWebhook(signature string, body []byte) bool
+	ParseWebhook(body []byte) ([]ChatEvent, error)
+}
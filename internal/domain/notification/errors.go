@@ -0,0 +1,16 @@
+package notification
+
+import "errors"
+
+var (
+	ErrTemplateNotFound = errors.New("notification template not found")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrNoWebhookEvents  = errors.New("no archived webhook events match")
+	ErrChatLinkNotFound = errors.New("chat link not found")
+	ErrInvalidLinkCode  = errors.New("link code is invalid or has expired")
+	// ErrChatUnreachable is returned by bots when the chat blocked the bot or no longer exists
+	ErrChatUnreachable   = errors.New("chat is no longer reachable")
+	ErrPushTokenNotFound = errors.New("push token not found")
+	// ErrPushTokenInvalid is returned by push senders for tokens the provider no longer accepts
+	ErrPushTokenInvalid = errors.New("push token is no longer valid")
+)
@@ -0,0 +1,24 @@
+package notification
+
+import "context"
+
+// Attachment is a file sent along with an email
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Mail is an email sent directly to a list of addresses, such as a
+// scheduled report, rather than a notification addressed to a user
+type Mail struct {
+	To          []string
+	Subject     string
+	Body        string // Plain text
+	Attachments []Attachment
+}
+
+// Mailer sends emails over the configured mail server
+type Mailer interface {
+	SendMail(ctx context.Context, mail *Mail) error
+}
@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Severity ranks how urgently a notification needs attention
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityCritical Severity = "critical"
+)
+
+// Rank orders severities so channels can filter by a minimum level
+func (s Severity) Rank() int {
+	if s == SeverityCritical {
+		return 1
+	}
+	return 0
+}
+
+// Message is a notification addressed to a single user. Operational alerts
+// that are not meant for a particular user leave UserID empty and are only
+// delivered to platform-wide channels.
+type Message struct {
+	UserID   uuid.UUID
+	Email    string
+	Name     string
+	Language string // Preferred language, empty for the configured default
+	Event    string // e.g. quote_requested, used for routing and templates
+	Severity Severity
+	Subject  string
+	Body     string
+	Link     string // Application path the recipient can act on, e.g. /shipments/<id>
+	Data     map[string]string
+	Sandbox  bool // Raised by sandbox data; never delivered to real channels
+
+	// Webhook deliveries of an archived event carry its ID and time. Replays
+	// resend them unchanged and are marked so receivers can drop events
+	// they already processed.
+	EventID    uuid.UUID
+	OccurredAt time.Time
+	Replay     bool
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
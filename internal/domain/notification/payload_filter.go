@@ -0,0 +1,135 @@
+package notification
+
+import "strings"
+
+// PayloadPreset is a redaction preset for webhook payloads
+type PayloadPreset string
+
+const (
+	// PresetFull sends the whole notification
+	PresetFull PayloadPreset = "full"
+	// PresetNoPII drops the free text and the data that name or locate
+	// people: the body, addresses and contact details
+	PresetNoPII PayloadPreset = "no_pii"
+	// PresetMinimal only keeps the event, its severity and the IDs in its
+	// data; receivers fetch the rest through the API
+	PresetMinimal PayloadPreset = "minimal"
+)
+
+// AllEvents is the PayloadFilters key applying to events without their own
+// filter
+const AllEvents = "*"
+
+// Selectable payload fields. The event ID, event name, severity and time
+// are always sent; data keys are selected as "data.<key>" or "data.*".
+const (
+	FieldSubject = "subject"
+	FieldBody    = "body"
+	FieldLink    = "link"
+	FieldData    = "data.*"
+)
+
+// piiDataKeys are the data keys the no_pii preset removes
+var piiDataKeys = map[string]bool{
+	"pickup_address":   true,
+	"delivery_address": true,
+	"recipient_name":   true,
+	"recipient_email":  true,
+	"email":            true,
+	"phone":            true,
+	"full_name":        true,
+}
+
+// PayloadFilter decides what of an event a webhook receives. The preset is
+// applied first; Fields, when set, then narrows the payload to the listed
+// fields.
+type PayloadFilter struct {
+	Preset PayloadPreset
+	Fields []string
+}
+
+// IsValidPayloadField reports whether field can be selected in a filter
+func IsValidPayloadField(field string) bool {
+	switch field {
+	case FieldSubject, FieldBody, FieldLink, FieldData:
+		return true
+	}
+	key, ok := strings.CutPrefix(field, "data.")
+	return ok && key != ""
+}
+
+// Apply returns a copy of msg holding only what the filter lets through
+func (f PayloadFilter) Apply(msg *Message) *Message {
+	out := *msg
+	out.Data = make(map[string]string, len(msg.Data))
+	for key, value := range msg.Data {
+		out.Data[key] = value
+	}
+
+	switch f.Preset {
+	case PresetNoPII:
+		out.Body = ""
+		for key := range out.Data {
+			if piiDataKeys[key] {
+				delete(out.Data, key)
+			}
+		}
+	case PresetMinimal:
+		out.Subject = ""
+		out.Body = ""
+		out.Link = ""
+		for key := range out.Data {
+			if !strings.HasSuffix(key, "_id") {
+				delete(out.Data, key)
+			}
+		}
+	}
+
+	if len(f.Fields) > 0 {
+		selected := make(map[string]bool, len(f.Fields))
+		for _, field := range f.Fields {
+			selected[field] = true
+		}
+		if !selected[FieldSubject] {
+			out.Subject = ""
+		}
+		if !selected[FieldBody] {
+			out.Body = ""
+		}
+		if !selected[FieldLink] {
+			out.Link = ""
+		}
+		if !selected[FieldData] {
+			for key := range out.Data {
+				if !selected["data."+key] {
+					delete(out.Data, key)
+				}
+			}
+		}
+	}
+
+	if len(out.Data) == 0 {
+		out.Data = nil
+	}
+	return &out
+}
+
+// PayloadFilterFor returns the filter of the webhook for event: its own
+// filter, else the one for all events, else nil
+func (w *Webhook) PayloadFilterFor(event string) *PayloadFilter {
+	if f, ok := w.PayloadFilters[event]; ok {
+		return &f
+	}
+	if f, ok := w.PayloadFilters[AllEvents]; ok {
+		return &f
+	}
+	return nil
+}
+
+// FilterPayload returns msg as the webhook is configured to receive it
+func (w *Webhook) FilterPayload(msg *Message) *Message {
+	if f := w.PayloadFilterFor(msg.Event); f != nil {
+		return f.Apply(msg)
+	}
+	return msg
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PushProvider is the push service a device token belongs to
+type PushProvider string
+
+const (
+	PushFCM  PushProvider = "fcm"
+	PushAPNs PushProvider = "apns"
+)
+
+// PushToken is a mobile device registered to receive push notifications
+type PushToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Provider   PushProvider
+	Token      string
+	Platform   string // android or ios
+	DeviceName *string
+	AppVersion *string
+	LastSeenAt time.Time
+	CreatedAt  time.Time
+}
+
+// ShipmentTopic is the push topic carrying the alerts of one shipment
+func ShipmentTopic(shipmentID uuid.UUID) string {
+	return "shipment:" + shipmentID.String()
+}
+
+// PushSubscription subscribes a user to a push topic
+type PushSubscription struct {
+	UserID    uuid.UUID
+	Topic     string
+	CreatedAt time.Time
+}
+
+// PushMessage is the provider independent payload of a push notification
+type PushMessage struct {
+	Title    string
+	Body     string
+	Data     map[string]string
+	ThreadID string // Groups notifications of the same topic on the device
+	Critical bool
+}
+
+// PushSender delivers push notifications through FCM or APNs
+type PushSender interface {
+	Provider() PushProvider
+	// Send returns ErrPushTokenInvalid when the provider reports the token
+	// as unregistered or malformed
+	Send(ctx context.Context, token string, msg *PushMessage) error
+}
@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateRepository defines the interface for notification template storage
+type TemplateRepository interface {
+	// CreateVersion stores t as the next version of its event, channel and
+	// language and makes it the active one.
+	CreateVersion(ctx context.Context, t *Template) error
+	GetByID(ctx context.Context, templateID uuid.UUID) (*Template, error)
+	GetActive(ctx context.Context, event string, channel Channel, language string) (*Template, error)
+	ListActive(ctx context.Context) ([]*Template, error)
+	ListVersions(ctx context.Context, event string, channel Channel, language string) ([]*Template, error)
+	// Activate makes an earlier version active again
+	Activate(ctx context.Context, templateID uuid.UUID) error
+}
+
+// WebhookRepository defines the interface for chat webhook storage
+type WebhookRepository interface {
+	Create(ctx context.Context, hook *Webhook) error
+	GetByID(ctx context.Context, webhookID uuid.UUID) (*Webhook, error)
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*Webhook, error)
+	Update(ctx context.Context, hook *Webhook) error
+	Delete(ctx context.Context, webhookID uuid.UUID) error
+	// ListForRecipient returns the active webhooks that may receive a
+	// notification for userID: its own webhooks, or the platform webhooks
+	// when userID is empty.
+	ListForRecipient(ctx context.Context, userID uuid.UUID) ([]*Webhook, error)
+	ListDigestSubscribers(ctx context.Context) ([]*Webhook, error)
+	// RecordDelivery updates the delivery health of a webhook; deliveryErr is
+	// nil on success.
+	RecordDelivery(ctx context.Context, webhookID uuid.UUID, at time.Time, deliveryErr error) error
+}
+
+// WebhookEventRepository archives the events delivered to webhooks
+type WebhookEventRepository interface {
+	Create(ctx context.Context, event *WebhookEvent) error
+	// RecordDelivery stores the outcome of the first delivery of an event
+	RecordDelivery(ctx context.Context, eventID uuid.UUID, deliveryErr error) error
+	// RecordReplay stores the outcome of a replay of an event
+	RecordReplay(ctx context.Context, eventID uuid.UUID, at time.Time, deliveryErr error) error
+	List(ctx context.Context, filter *WebhookEventFilter) ([]*WebhookEvent, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ChatLinkRepository defines the interface for bot chat links and their deliveries
+type ChatLinkRepository interface {
+	CreateCode(ctx context.Context, code *ChatLinkCode) error
+	// ConsumeCode marks an unused, unexpired code as used and returns it
+	ConsumeCode(ctx context.Context, provider ChatProvider, codeHash string, now time.Time) (*ChatLinkCode, error)
+
+	// SaveLink links a chat to the user, replacing any previous chat of the
+	// same provider for that user
+	SaveLink(ctx context.Context, link *ChatLink) error
+	ListLinks(ctx context.Context, userID uuid.UUID) ([]*ChatLink, error)
+	ListActiveLinks(ctx context.Context, userID uuid.UUID) ([]*ChatLink, error)
+	DeleteLink(ctx context.Context, userID uuid.UUID, provider ChatProvider) error
+	DeactivateChat(ctx context.Context, provider ChatProvider, chatID string) error
+
+	CreateDelivery(ctx context.Context, delivery *ChatDelivery) error
+	// UpdateDeliveryStatus applies a receipt to the deliveries of the given
+	// provider message IDs; statuses never move backwards
+	UpdateDeliveryStatus(ctx context.Context, provider ChatProvider, messageIDs []string, status ChatDeliveryStatus, at time.Time) error
+	ListDeliveries(ctx context.Context, userID uuid.UUID, limit int) ([]*ChatDelivery, error)
+}
+
+// PushRepository defines the interface for push tokens and topic subscriptions
+type PushRepository interface {
+	// SaveToken registers a device token, moving it to the user when it was
+	// registered by another account on the same device
+	SaveToken(ctx context.Context, token *PushToken) error
+	ListTokens(ctx context.Context, userID uuid.UUID) ([]*PushToken, error)
+	ListTokensForUsers(ctx context.Context, userIDs []uuid.UUID) ([]*PushToken, error)
+	DeleteToken(ctx context.Context, userID, tokenID uuid.UUID) error
+	DeleteByValue(ctx context.Context, provider PushProvider, token string) error
+	DeleteStale(ctx context.Context, notSeenSince time.Time) (int64, error)
+
+	Subscribe(ctx context.Context, userID uuid.UUID, topic string) error
+	Unsubscribe(ctx context.Context, userID uuid.UUID, topic string) error
+	ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]*PushSubscription, error)
+	ListSubscribers(ctx context.Context, topic string) ([]uuid.UUID, error)
+}
@@ -0,0 +1,9 @@
+package notification
+
+import "context"
+
+// SMSSender delivers text messages to phone numbers. It is used for
+// transactional messages such as one-time passwords rather than alerts.
+type SMSSender interface {
+	SendSMS(ctx context.Context, phoneNumber, text string) error
+}
@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channel is a delivery channel a template is written for
+type Channel string
+
+const (
+	ChannelEmail Channel = "email"
+	ChannelChat  Channel = "chat" // Slack, Microsoft Teams, Telegram and Zalo
+	ChannelPush  Channel = "push" // Mobile push through FCM and APNs
+)
+
+// Template is one version of the wording used for an event on a channel in a
+// language. Only one version per event, channel and language is active.
+type Template struct {
+	ID        uuid.UUID
+	Event     string
+	Channel   Channel
+	Language  string
+	Version   int
+	Subject   string
+	Body      string
+	IsActive  bool
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time
+}
+
+// placeholderPattern matches {{ variable }} placeholders
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_.]+)\s*\}\}`)
+
+// Render substitutes {{variable}} placeholders in subject and body. Unknown
+// variables are left as-is and reported in missing.
+func (t *Template) Render(vars map[string]string) (subject, body string, missing []string) {
+	unknown := make(map[string]bool)
+	replace := func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(m string) string {
+			name := placeholderPattern.FindStringSubmatch(m)[1]
+			if v, ok := vars[name]; ok {
+				return v
+			}
+			unknown[name] = true
+			return m
+		})
+	}
+
+	subject = replace(t.Subject)
+	body = replace(t.Body)
+	for name := range unknown {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return subject, body, missing
+}
+
+// Variables lists the distinct placeholders used by the template
+func (t *Template) Variables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range placeholderPattern.FindAllStringSubmatch(t.Subject+"\n"+t.Body, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MessageVariables returns the variables available to templates for a message:
+// its data plus the recipient and the built-in subject and body.
+func MessageVariables(msg *Message) map[string]string {
+	vars := make(map[string]string, len(msg.Data)+5)
+	for k, v := range msg.Data {
+		vars[k] = v
+	}
+	vars["recipient_name"] = msg.Name
+	vars["recipient_email"] = msg.Email
+	vars["event"] = msg.Event
+	vars["default_subject"] = msg.Subject
+	vars["default_body"] = msg.Body
+	return vars
+}
+
+// NormalizeLanguage lowercases a language tag and keeps only its primary subtag
+func NormalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
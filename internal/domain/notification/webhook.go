@@ -0,0 +1,158 @@
+package notification
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookKind identifies the chat service a webhook posts to
+type WebhookKind string
+
+const (
+	WebhookSlack WebhookKind = "slack"
+	WebhookTeams WebhookKind = "teams"
+	// WebhookGeneric receives signed JSON events at any public https endpoint
+	WebhookGeneric WebhookKind = "generic"
+)
+
+// SigningSecretGracePeriod is how long the previous signing secret keeps being
+// used alongside a newly rotated one, giving receivers time to switch over
+const SigningSecretGracePeriod = 24 * time.Hour
+
+// WebhookScope decides which notifications a webhook receives
+type WebhookScope string
+
+const (
+	// WebhookScopeOwner receives the notifications addressed to its owner
+	WebhookScopeOwner WebhookScope = "owner"
+	// WebhookScopePlatform receives operational alerts for the whole platform; admin only
+	WebhookScopePlatform WebhookScope = "platform"
+)
+
+// Webhook is an outgoing integration: a Slack or Microsoft Teams incoming
+// webhook, or a generic endpoint receiving signed JSON events
+type Webhook struct {
+	ID          uuid.UUID
+	OwnerID     uuid.UUID
+	Name        string
+	Kind        WebhookKind
+	URL         string
+	Scope       WebhookScope
+	MinSeverity Severity
+	DailyDigest bool
+	IsActive    bool
+
+	// PayloadFilters limit what the webhook receives, by event name or
+	// AllEvents; events without a filter are sent in full
+	PayloadFilters map[string]PayloadFilter
+
+	// Request signing; the previous secret stays valid for the grace period
+	// after a rotation
+	SigningSecret         string
+	PreviousSigningSecret *string
+	SecretRotatedAt       *time.Time
+
+	// Delivery health
+	LastDeliveryAt      *time.Time
+	LastSuccessAt       *time.Time
+	LastError           *string
+	ConsecutiveFailures int
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Accepts reports whether msg should be posted to the webhook
+func (w *Webhook) Accepts(msg *Message) bool {
+	if !w.IsActive {
+		return false
+	}
+	switch w.Scope {
+	case WebhookScopePlatform:
+		if msg.UserID != uuid.Nil {
+			return false
+		}
+	default:
+		if msg.UserID != w.OwnerID {
+			return false
+		}
+	}
+	return msg.Severity.Rank() >= w.MinSeverity.Rank()
+}
+
+// SigningSecrets returns the secrets deliveries are signed with at now: the
+// current one, plus the previous one during the rotation grace period
+func (w *Webhook) SigningSecrets(now time.Time) []string {
+	secrets := []string{w.SigningSecret}
+	if w.PreviousSigningSecret != nil && w.SecretRotatedAt != nil &&
+		now.Before(w.SecretRotatedAt.Add(SigningSecretGracePeriod)) {
+		secrets = append(secrets, *w.PreviousSigningSecret)
+	}
+	return secrets
+}
+
+// MaskedURL hides the secret path of the webhook URL, keeping only the host
+func (w *Webhook) MaskedURL() string {
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Host == "" {
+		return "****"
+	}
+	return u.Scheme + "://" + u.Host + "/****"
+}
+
+// WebhookEvent is a notification as it was delivered to a webhook, archived
+// so it can be replayed after the receiving endpoint was down
+type WebhookEvent struct {
+	ID        uuid.UUID // Event ID sent in X-Cargo-Event-Id
+	WebhookID uuid.UUID
+	OwnerID   uuid.UUID
+	Event     string
+	Severity  Severity
+	Subject   string
+	Body      string
+	Link      string
+	Data      map[string]string
+	Delivered bool
+	LastError *string
+
+	ReplayCount    int
+	LastReplayedAt *time.Time
+	CreatedAt      time.Time
+}
+
+// Message rebuilds the notification of an archived event, marked as a replay
+func (e *WebhookEvent) Message() *Message {
+	return &Message{
+		UserID:     e.OwnerID,
+		Event:      e.Event,
+		Severity:   e.Severity,
+		Subject:    e.Subject,
+		Body:       e.Body,
+		Link:       e.Link,
+		Data:       e.Data,
+		EventID:    e.ID,
+		OccurredAt: e.CreatedAt,
+		Replay:     true,
+	}
+}
+
+// WebhookEventFilter selects archived events of one owner. Empty fields do
+// not restrict; events are returned oldest first.
+type WebhookEventFilter struct {
+	OwnerID   uuid.UUID
+	WebhookID *uuid.UUID
+	Event     string
+	Delivered *bool
+	From      *time.Time
+	To        *time.Time
+	IDs       []uuid.UUID
+	Limit     int
+}
+
+// WebhookSender posts a message to a single webhook
+type WebhookSender interface {
+	Deliver(ctx context.Context, hook *Webhook, msg *Message) error
+}
@@ -0,0 +1,32 @@
+package quota
+
+import "time"
+
+// Metric is a usage a plan can cap
+type Metric string
+
+const (
+	MetricActiveShipments Metric = "active_shipments"
+	MetricDevices         Metric = "devices"
+	MetricAPICalls        Metric = "api_calls"
+)
+
+// PeriodStart returns the start of the calendar month (UTC) t falls in; API
+// calls are counted per month
+func PeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodEnd returns the start of the month after the one periodStart opens
+func PeriodEnd(periodStart time.Time) time.Time {
+	return periodStart.AddDate(0, 1, 0)
+}
+
+// Feature is a capability only some plans include
+type Feature string
+
+const (
+	FeaturePredictiveAlerts Feature = "predictive_alerts"
+	FeatureWebhooks         Feature = "webhooks"
+)
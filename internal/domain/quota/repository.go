@@ -0,0 +1,22 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for measuring account usage
+type Repository interface {
+	// IncrementAPICalls counts one API call of the user in the period and
+	// returns the calls counted in it so far, this one included
+	IncrementAPICalls(ctx context.Context, userID uuid.UUID, periodStart time.Time) (int64, error)
+	GetAPICalls(ctx context.Context, userID uuid.UUID, periodStart time.Time) (int64, error)
+
+	// CountActiveShipments counts the unfinished shipments the user takes
+	// part in as role. Providers are only charged once they post an order.
+	CountActiveShipments(ctx context.Context, userID uuid.UUID, role string) (int64, error)
+	// CountDevices counts the devices the shipper owns
+	CountDevices(ctx context.Context, shipperID uuid.UUID) (int64, error)
+}
@@ -0,0 +1,75 @@
+package quotation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestStatus represents the status of a customer's quote request
+type RequestStatus string
+
+const (
+	RequestOpen      RequestStatus = "open"      // Waiting for provider quotes
+	RequestAccepted  RequestStatus = "accepted"  // A quote was accepted and a shipment created
+	RequestCancelled RequestStatus = "cancelled" // Withdrawn by the customer
+	RequestExpired   RequestStatus = "expired"   // No quote accepted before the deadline
+)
+
+// QuoteStatus represents the status of a single provider's quote
+type QuoteStatus string
+
+const (
+	QuoteRequested QuoteStatus = "requested" // Sent to provider, no answer yet
+	QuoteSubmitted QuoteStatus = "submitted" // Provider offered price and lead time
+	QuoteDeclined  QuoteStatus = "declined"  // Provider will not serve this request
+	QuoteAccepted  QuoteStatus = "accepted"  // Customer chose this quote
+	QuoteRejected  QuoteStatus = "rejected"  // Customer chose another quote or cancelled
+)
+
+// QuoteRequest is a customer's demand sent to several providers for pricing
+type QuoteRequest struct {
+	ID         uuid.UUID
+	CustomerID uuid.UUID
+	Status     RequestStatus
+
+	// Goods and route, copied onto the shipment when a quote is accepted
+	GoodsDescription    string
+	GoodsValue          *float64
+	GoodsCurrency       *string // Provider default applies when empty
+	GoodsWeight         *float64
+	PickupAddress       string
+	DeliveryAddress     string
+	EstimatedPickupAt   *time.Time
+	EstimatedDeliveryAt *time.Time
+	CustomerNotes       *string
+
+	ExpiresAt  time.Time
+	ShipmentID *uuid.UUID
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Quotes []*Quote
+}
+
+// IsExpired reports whether the request can no longer accept quotes
+func (r *QuoteRequest) IsExpired(now time.Time) bool {
+	return r.Status == RequestOpen && now.After(r.ExpiresAt)
+}
+
+// Quote is one provider's answer to a quote request
+type Quote struct {
+	ID            uuid.UUID
+	RequestID     uuid.UUID
+	ProviderID    uuid.UUID
+	Status        QuoteStatus
+	Price         *float64
+	Currency      string
+	LeadTimeHours *int
+	ValidUntil    *time.Time
+	Notes         *string
+	RespondedAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
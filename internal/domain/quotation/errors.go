@@ -0,0 +1,11 @@
+package quotation
+
+import "errors"
+
+var (
+	ErrQuoteRequestNotFound = errors.New("quote request not found")
+	ErrQuoteNotFound        = errors.New("quote not found")
+	ErrRequestClosed        = errors.New("quote request is no longer open")
+	ErrQuoteNotSubmitted    = errors.New("quote has not been submitted")
+	ErrQuoteExpired         = errors.New("quote is no longer valid")
+)
@@ -0,0 +1,24 @@
+package quotation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for quotation repository operations
+type Repository interface {
+	CreateRequest(ctx context.Context, request *QuoteRequest, providerIDs []uuid.UUID) error
+	GetRequestByID(ctx context.Context, requestID uuid.UUID) (*QuoteRequest, error)
+	ListRequestsByCustomer(ctx context.Context, customerID uuid.UUID, page, pageSize int) ([]*QuoteRequest, int64, error)
+
+	GetQuoteByID(ctx context.Context, quoteID uuid.UUID) (*Quote, error)
+	ListQuotesByProvider(ctx context.Context, providerID uuid.UUID, status *QuoteStatus, page, pageSize int) ([]*Quote, int64, error)
+	UpdateQuote(ctx context.Context, quote *Quote) error
+
+	// AcceptQuote marks the quote accepted, rejects the others and links the
+	// request to the created shipment in a single transaction.
+	AcceptQuote(ctx context.Context, requestID, quoteID, shipmentID uuid.UUID) error
+	// CloseRequest sets a terminal status and rejects all outstanding quotes.
+	CloseRequest(ctx context.Context, requestID uuid.UUID, status RequestStatus) error
+}
@@ -0,0 +1,80 @@
+package reference
+
+// Kinds of reference data, as named in lookups and the ref validation tag
+const (
+	KindRole          = "role"
+	KindIssueType     = "issue_type"
+	KindViolationType = "violation_type"
+	KindGoodsCategory = "goods_category"
+)
+
+// Entry is a value of a reference table. IDs are assigned by the seeding
+// migrations and stay with their value for good.
+type Entry struct {
+	ID          int
+	Code        string
+	Name        string
+	Description string
+}
+
+// ViolationType is a condition the shipping rules limit
+type ViolationType struct {
+	Entry
+	Unit string
+}
+
+// GoodsCategory is a kind of goods and the temperature it is usually kept
+// at. Keywords recognise it in a goods description when a shipment names no
+// category.
+type GoodsCategory struct {
+	Entry
+	TempMin  *float64
+	TempMax  *float64
+	Keywords []string
+}
+
+// Data holds every reference table, each ordered by ID
+type Data struct {
+	Roles           []Entry
+	IssueTypes      []Entry
+	ViolationTypes  []ViolationType
+	GoodsCategories []GoodsCategory
+}
+
+// Has reports whether code is a value of kind
+func (d *Data) Has(kind, code string) bool {
+	switch kind {
+	case KindRole:
+		return hasCode(d.Roles, code)
+	case KindIssueType:
+		return hasCode(d.IssueTypes, code)
+	case KindViolationType:
+		for _, v := range d.ViolationTypes {
+			if v.Code == code {
+				return true
+			}
+		}
+	case KindGoodsCategory:
+		return d.GoodsCategory(code) != nil
+	}
+	return false
+}
+
+// GoodsCategory returns the goods category with code, or nil
+func (d *Data) GoodsCategory(code string) *GoodsCategory {
+	for i := range d.GoodsCategories {
+		if d.GoodsCategories[i].Code == code {
+			return &d.GoodsCategories[i]
+		}
+	}
+	return nil
+}
+
+func hasCode(entries []Entry, code string) bool {
+	for _, e := range entries {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+package reference
+
+import "context"
+
+// Repository defines the interface for reading the reference tables
+type Repository interface {
+	Load(ctx context.Context) (*Data, error)
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type is the kind of report a subscription delivers
+type Type string
+
+const (
+	// TypeShipmentSummary counts the owner's shipments, deliveries and
+	// reported issues over the period
+	TypeShipmentSummary Type = "shipment_summary"
+)
+
+// Cadence is how often a subscription is delivered
+type Cadence string
+
+const (
+	CadenceDaily   Cadence = "daily"
+	CadenceWeekly  Cadence = "weekly"  // Mondays, covering the previous week
+	CadenceMonthly Cadence = "monthly" // The 1st, covering the previous month
+)
+
+// Format is how the report reaches the recipients
+type Format string
+
+const (
+	FormatText Format = "text" // In the email body only
+	FormatCSV  Format = "csv"  // Email body plus a CSV attachment
+)
+
+// DeliveryStatus is the outcome of one delivery
+type DeliveryStatus string
+
+const (
+	DeliverySent   DeliveryStatus = "sent"
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Subscription emails a report to a list of recipients on a schedule
+type Subscription struct {
+	ID         uuid.UUID
+	OwnerID    uuid.UUID
+	ReportType Type
+	Cadence    Cadence
+	Format     Format
+	Recipients []string
+	Timezone   string // IANA name, e.g. Asia/Ho_Chi_Minh
+	Active     bool
+	NextRunAt  time.Time
+	LastRunAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Delivery records one attempt to send a subscription's report
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	Status         DeliveryStatus
+	Recipients     []string
+	Error          *string
+	CreatedAt      time.Time
+}
+
+// ShipmentSummary is the content of a shipment summary report. Periods are
+// half-open: [PeriodStart, PeriodEnd).
+type ShipmentSummary struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+
+	Created   int // Shipments created in the period
+	Completed int // Delivered in the period, fully or partially
+	OnTime    int // Completed no later than their delivery deadline
+	Late      int
+	Cancelled int // Cancelled in the period
+	// Shipments reported with an issue, including quality violations,
+	// that were last updated in the period
+	IssuesReported int
+	InTransit      int // In transit at the end of the period
+	Overdue        int // In transit past their delivery deadline at the end of the period
+}
@@ -0,0 +1,8 @@
+package report
+
+import "errors"
+
+var (
+	ErrSubscriptionNotFound = errors.New("report subscription not found")
+	ErrDeliveryUnavailable  = errors.New("report email delivery is not configured")
+)
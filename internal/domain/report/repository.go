@@ -0,0 +1,30 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for report repository operations
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, ownerID uuid.UUID) ([]*Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error
+
+	// ListDue returns up to limit active subscriptions due at now
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Subscription, error)
+	// Advance moves a subscription's next run from due to next. It reports
+	// false when another scheduler already did, so each run is claimed once.
+	Advance(ctx context.Context, subscriptionID uuid.UUID, due, next time.Time) (bool, error)
+
+	CreateDelivery(ctx context.Context, delivery *Delivery) error
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*Delivery, error)
+
+	// ShipmentSummary summarises the shipments the user is a party of as
+	// the given role, leaving sandbox data out
+	ShipmentSummary(ctx context.Context, userID uuid.UUID, role string, from, to time.Time) (*ShipmentSummary, error)
+}
@@ -0,0 +1,44 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessScope names the part of a shipment's data an access grant opens up
+type AccessScope string
+
+const (
+	ScopeDetails   AccessScope = "details"   // Shipment detail view and packages
+	ScopeDocuments AccessScope = "documents" // Document checklist and attachments
+)
+
+// AccessGrant gives a third party, such as an insurer or auditor, temporary
+// read access to a single shipment
+type AccessGrant struct {
+	ID         uuid.UUID
+	ShipmentID uuid.UUID
+	GrantedBy  uuid.UUID
+	GranteeID  uuid.UUID
+	Scopes     []AccessScope
+	Note       *string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// IsActive reports whether the grant can still be used at the given time
+func (g *AccessGrant) IsActive(at time.Time) bool {
+	return g.RevokedAt == nil && at.Before(g.ExpiresAt)
+}
+
+// Allows reports whether the grant covers the scope
+func (g *AccessGrant) Allows(scope AccessScope) bool {
+	for _, s := range g.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxCalendarScanDays bounds the search for the next business day so a
+// calendar with every day off cannot loop forever
+const maxCalendarScanDays = 366
+
+// Holiday is a closed day in the calendar's time zone
+type Holiday struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name,omitempty"`
+}
+
+// BusinessCalendar describes when a provider works. Deadlines and delivery
+// estimates only count working time.
+type BusinessCalendar struct {
+	ProviderID   uuid.UUID
+	Timezone     string // IANA name, e.g. Asia/Ho_Chi_Minh
+	WorkingDays  []time.Weekday
+	OpenMinute   int  // Minutes after midnight the working day starts
+	CloseMinute  int  // Minutes after midnight the working day ends
+	CutoffMinute *int // Orders received later start processing the next business day
+	Holidays     []Holiday
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Location returns the calendar's time zone, UTC if it cannot be loaded
+func (c *BusinessCalendar) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsBusinessDay reports whether the calendar day of t is a working day
+func (c *BusinessCalendar) IsBusinessDay(t time.Time) bool {
+	t = t.In(c.Location())
+
+	working := false
+	for _, d := range c.WorkingDays {
+		if d == t.Weekday() {
+			working = true
+			break
+		}
+	}
+	if !working {
+		return false
+	}
+
+	date := t.Format("2006-01-02")
+	for _, h := range c.Holidays {
+		if h.Date == date {
+			return false
+		}
+	}
+	return true
+}
+
+// Deadline returns when something promised for t is actually due. Within
+// working hours that is t itself; outside them the promise rolls over to the
+// next opening, so weekends and holidays do not count against the provider.
+func (c *BusinessCalendar) Deadline(t time.Time) time.Time {
+	local := t.In(c.Location())
+	if c.IsBusinessDay(local) {
+		open, closing := c.at(local, c.OpenMinute), c.at(local, c.CloseMinute)
+		if local.Before(open) {
+			return open
+		}
+		if !local.After(closing) {
+			return t
+		}
+	}
+
+	next, ok := c.nextBusinessDay(local)
+	if !ok {
+		return t
+	}
+	return c.at(next, c.OpenMinute)
+}
+
+// EstimateDelivery returns the end of the working day on which an order
+// received at from is delivered, after transitDays full business days.
+// Orders received after the cutoff or outside working hours start the next
+// business day.
+func (c *BusinessCalendar) EstimateDelivery(from time.Time, transitDays int) (time.Time, bool) {
+	day := from.In(c.Location())
+
+	startsToday := c.IsBusinessDay(day) && !day.After(c.at(day, c.CloseMinute))
+	if startsToday && c.CutoffMinute != nil && day.After(c.at(day, *c.CutoffMinute)) {
+		startsToday = false
+	}
+	if !startsToday {
+		next, ok := c.nextBusinessDay(day)
+		if !ok {
+			return time.Time{}, false
+		}
+		day = next
+	}
+
+	for i := 0; i < transitDays; i++ {
+		next, ok := c.nextBusinessDay(day)
+		if !ok {
+			return time.Time{}, false
+		}
+		day = next
+	}
+	return c.at(day, c.CloseMinute), true
+}
+
+// nextBusinessDay returns midnight of the first business day after t's day
+func (c *BusinessCalendar) nextBusinessDay(t time.Time) (time.Time, bool) {
+	day := c.at(t, 0)
+	for i := 0; i < maxCalendarScanDays; i++ {
+		day = day.AddDate(0, 0, 1)
+		if c.IsBusinessDay(day) {
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// at returns minute minutes after midnight on t's calendar day
+func (c *BusinessCalendar) at(t time.Time, minute int) time.Time {
+	t = t.In(c.Location())
+	return time.Date(t.Year(), t.Month(), t.Day(), minute/60, minute%60, 0, 0, t.Location())
+}
@@ -0,0 +1,46 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ComparisonQuery selects the provider shipments shippers are compared on
+type ComparisonQuery struct {
+	ProviderID uuid.UUID
+	Sandbox    bool
+	// Shipments created in [From, To)
+	From time.Time
+	To   time.Time
+	// Optional lane, matched against the normalized pickup and delivery
+	// addresses
+	PickupCity   string
+	DeliveryCity string
+}
+
+// ShipperPerformance aggregates the shipments one shipper handled for a
+// provider
+type ShipperPerformance struct {
+	ShipperID   uuid.UUID
+	ShipperName string
+
+	Assigned   int // Shipments the shipper accepted
+	Delivered  int // Completed or partially completed
+	OnTime     int // Delivered by the calendar adjusted or estimated deadline
+	Partial    int // Delivered with at least one package exception
+	Cancelled  int // Cancelled after the shipper accepted
+	OpenIssues int // Currently in issue_reported
+	Rated      int
+	AvgRating  float64
+
+	// Agreed prices of accepted quotes, per currency
+	Costs []ShipperCost
+}
+
+// ShipperCost sums the agreed prices of a shipper's shipments in one currency
+type ShipperCost struct {
+	Currency  string
+	Shipments int
+	Total     float64
+}
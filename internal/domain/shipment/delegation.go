@@ -0,0 +1,23 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delegation lets another account, typically a freight forwarder, create and
+// manage shipments in a customer's name
+type Delegation struct {
+	ID         uuid.UUID
+	CustomerID uuid.UUID
+	DelegateID uuid.UUID
+	Note       *string
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// IsActive reports whether the delegate may still act for the customer
+func (d *Delegation) IsActive() bool {
+	return d.RevokedAt == nil
+}
@@ -1,6 +1,7 @@
 package shipment
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,15 +11,37 @@ import (
 type ShipmentStatus string
 
 const (
-	StatusDemandCreated    ShipmentStatus = "demand_created"    // Customer creates demand
-	StatusOrderPosted      ShipmentStatus = "order_posted"      // Provider posts to marketplace
-	StatusShippingAssigned ShipmentStatus = "shipping_assigned" // Shipper accepts order
-	StatusInTransit        ShipmentStatus = "in_transit"        // Actively shipping
-	StatusCompleted        ShipmentStatus = "completed"         // Successfully delivered
-	StatusIssueReported    ShipmentStatus = "issue_reported"    // Problem during shipping
-	StatusCancelled        ShipmentStatus = "cancelled"         // Cancelled before completion
+	StatusDemandCreated      ShipmentStatus = "demand_created"      // Customer creates demand
+	StatusOrderPosted        ShipmentStatus = "order_posted"        // Provider posts to marketplace
+	StatusShippingAssigned   ShipmentStatus = "shipping_assigned"   // Shipper accepts order
+	StatusInTransit          ShipmentStatus = "in_transit"          // Actively shipping
+	StatusCompleted          ShipmentStatus = "completed"           // Successfully delivered
+	StatusPartiallyCompleted ShipmentStatus = "partially_completed" // Delivered with package exceptions
+	StatusIssueReported      ShipmentStatus = "issue_reported"      // Problem during shipping
+	StatusCancelled          ShipmentStatus = "cancelled"           // Cancelled before completion
 )
 
+// IsValid reports whether the status is one a shipment can be in
+func (s ShipmentStatus) IsValid() bool {
+	switch s {
+	case StatusDemandCreated, StatusOrderPosted, StatusShippingAssigned, StatusInTransit,
+		StatusCompleted, StatusPartiallyCompleted, StatusIssueReported, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// TerminalStatuses returns the statuses a shipment ends in
+func TerminalStatuses() []ShipmentStatus {
+	return []ShipmentStatus{StatusCompleted, StatusPartiallyCompleted, StatusCancelled}
+}
+
+// IsTerminal reports whether the shipment is over; its device is no longer
+// needed
+func (s ShipmentStatus) IsTerminal() bool {
+	return slices.Contains(TerminalStatuses(), s)
+}
+
 // Shipment represents a shipping order entity in the domain
 type Shipment struct {
 	ID uuid.UUID
@@ -27,36 +50,123 @@ type Shipment struct {
 	CustomerID uuid.UUID
 	ProviderID uuid.UUID
 	ShipperID  *uuid.UUID
+	// BookedBy is the delegate who created the shipment in the customer's
+	// name; nil when the customer booked it
+	BookedBy *uuid.UUID
 
 	// Device assignment
 	LinkedDeviceID *uuid.UUID
 
+	// Version of the shipping rules in force; nil until rules are set, and
+	// for rules set before they were versioned
+	RulesVersionID *uuid.UUID
+
 	// Status
 	Status ShipmentStatus
 
 	// Goods information
 	GoodsDescription string
+	GoodsCategory    *string // Code of a reference goods category
 	GoodsValue       *float64
+	GoodsCurrency    string
 	GoodsWeight      *float64
+	// Exchange rate of GoodsCurrency into the reporting currency, captured
+	// when the shipment was created
+	GoodsValueRate *ExchangeRate
 
-	// Addresses
-	PickupAddress   string
-	DeliveryAddress string
+	// Addresses, with the address book entries they were taken from
+	PickupAddress     string
+	DeliveryAddress   string
+	PickupAddressID   *uuid.UUID
+	DeliveryAddressID *uuid.UUID
 
 	// Timing
 	EstimatedPickupAt   *time.Time
 	EstimatedDeliveryAt *time.Time
-	ActualPickupAt      *time.Time
-	ActualDeliveryAt    *time.Time
+	// DeliveryDueAt is EstimatedDeliveryAt moved into the provider's working
+	// hours; delays are measured against it
+	DeliveryDueAt    *time.Time
+	ActualPickupAt   *time.Time
+	ActualDeliveryAt *time.Time
 
 	// Notes and feedback
 	CustomerNotes   *string
 	CompletionNotes *string
 	CustomerRating  *int
 
+	// Proof of delivery (photo/signature location), encrypted at rest
+	ProofOfDeliveryURL *string
+
+	// Risk of the current assignment, computed when the order is posted and
+	// again when a shipper accepts it
+	Risk *RiskAssessment
+
+	// Sandbox shipments belong to sandbox accounts and are isolated from live data
+	IsSandbox bool
+
 	// Metadata
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// StatusChangedAt is when the shipment entered its current status
+	StatusChangedAt time.Time
+}
+
+// PackageOutcome is the delivery result recorded for a single package
+type PackageOutcome string
+
+const (
+	OutcomePending   PackageOutcome = "pending"   // Not confirmed yet
+	OutcomeDelivered PackageOutcome = "delivered" // Handed over in good condition
+	OutcomeLost      PackageOutcome = "lost"      // Never arrived
+	OutcomeDamaged   PackageOutcome = "damaged"   // Arrived but damaged
+	OutcomeRefused   PackageOutcome = "refused"   // Recipient refused to accept it
+)
+
+// IsException reports whether the outcome is a delivery exception
+func (o PackageOutcome) IsException() bool {
+	return o == OutcomeLost || o == OutcomeDamaged || o == OutcomeRefused
+}
+
+// Package is one box or pallet of a shipment, optionally carrying its own tracker
+type Package struct {
+	ID          uuid.UUID
+	ShipmentID  uuid.UUID
+	Sequence    int // 1-based position within the shipment, printed on labels
+	Description string
+	Weight      *float64
+	DeviceID    *uuid.UUID
+
+	// Delivery confirmation
+	Outcome           PackageOutcome
+	OutcomeNote       *string
+	OutcomeRecordedBy *uuid.UUID
+	OutcomeAt         *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ShipmentDetail is a shipment with everything its detail view shows, read
+// in one go. Rules and TermsAcceptance are nil when not set yet.
+type ShipmentDetail struct {
+	Shipment        *Shipment
+	Rules           *ShippingRules
+	Packages        []*Package
+	TermsAcceptance *TermsAcceptance
+}
+
+// DeviceAssignment tells which active shipment, and which package of it, a
+// device is currently reporting for. PackageID is nil for the shipment-level device.
+// For a trip device, ShipmentID is the next stop and TripShipmentIDs lists
+// every member shipment not delivered yet; readings apply to all of them.
+type DeviceAssignment struct {
+	DeviceID   uuid.UUID
+	ShipmentID uuid.UUID
+	PackageID  *uuid.UUID
+	Status     ShipmentStatus
+
+	TripID          *uuid.UUID
+	TripShipmentIDs []uuid.UUID
 }
 
 // ShippingRules represents quality control rules for shipment
@@ -79,6 +189,24 @@ type ShippingRules struct {
 	ConfirmedAt           *time.Time
 }
 
+// ExchangeRate is the value of one unit of a currency in the reporting
+// currency at a point in time, kept so reports do not move with the market
+type ExchangeRate struct {
+	Currency string // Reporting currency the rate converts into
+	Rate     float64
+	RatedAt  time.Time
+}
+
+// ReportingGoodsValue returns the goods value in the reporting currency,
+// or nil when there is no value or no rate for it
+func (s *Shipment) ReportingGoodsValue() *float64 {
+	if s.GoodsValue == nil || s.GoodsValueRate == nil {
+		return nil
+	}
+	value := *s.GoodsValue * s.GoodsValueRate.Rate
+	return &value
+}
+
 // Statistics represents shipment statistics
 type Statistics struct {
 	TotalShipments      int
@@ -89,7 +217,16 @@ type Statistics struct {
 	OnTimeDeliveryRate  float64
 	IssueRate           float64
 	TopShippers         []TopShipperStats
-	RevenueToday        float64
+	// Goods value delivered today in the reporting currency, and per goods
+	// currency before conversion
+	RevenueToday           float64
+	RevenueTodayByCurrency map[string]float64
+	UnconvertedToday       int // Deliveries without an exchange rate, left out of RevenueToday
+
+	// Package level delivery outcomes
+	PartialCompletionRate float64
+	PackageOutcomes       map[string]int
+	PackageExceptionRate  float64
 }
 
 // TopShipperStats represents statistics by shipper
@@ -16,4 +16,14 @@ var (
 	ErrShipmentCancelled       = errors.New("shipment is cancelled")
 	ErrInvalidParties          = errors.New("invalid parties")
 	ErrDeviceUnavailable       = errors.New("device is unavailable")
+	ErrPackageNotFound         = errors.New("package not found")
+	ErrDeviceNotAssigned       = errors.New("device is not assigned to an active shipment")
+	ErrAccessGrantNotFound     = errors.New("access grant not found")
+	ErrDelegationNotFound      = errors.New("delegation not found")
+	ErrNoteNotFound            = errors.New("note not found")
+	ErrTermsNotFound           = errors.New("terms of carriage not found")
+	ErrTermsAcceptanceNotFound = errors.New("terms acceptance not found")
+	ErrCalendarNotFound        = errors.New("business calendar not found")
+	ErrTripNotFound            = errors.New("trip not found")
+	ErrEventLogDisabled        = errors.New("shipment event log is disabled")
 )
@@ -0,0 +1,182 @@
+package shipment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType names a change in a shipment's lifecycle
+type EventType string
+
+const (
+	EventCreated       EventType = "created"
+	EventRulesSet      EventType = "rules_set"
+	EventAccepted      EventType = "accepted"
+	EventConfirmed     EventType = "confirmed"
+	EventStarted       EventType = "started"
+	EventCompleted     EventType = "completed"
+	EventIssueReported EventType = "issue_reported"
+	EventCancelled     EventType = "cancelled"
+	EventRated         EventType = "rated"
+	// EventDeviceLinked is the shipment's tracker replaced by a trip's one
+	EventDeviceLinked EventType = "device_linked"
+	// EventReverted is a stuck assignment released back to the marketplace
+	EventReverted EventType = "reverted"
+	// EventOverridden is a change an admin forced past the normal checks;
+	// Reason holds the justification
+	EventOverridden EventType = "overridden"
+)
+
+// Event is an immutable record of one change to a shipment. Data only holds
+// what the change set; replaying a shipment's events in order rebuilds its
+// state at any point in time.
+type Event struct {
+	ID         int64
+	ShipmentID uuid.UUID
+	Type       EventType
+	// ActorID is nil for changes made by the platform itself
+	ActorID    *uuid.UUID
+	Data       EventData
+	OccurredAt time.Time
+}
+
+// EventData carries the fields an event changed. Unset fields are unchanged.
+type EventData struct {
+	Status *ShipmentStatus `json:"status,omitempty"`
+
+	CustomerID       *uuid.UUID `json:"customer_id,omitempty"`
+	ProviderID       *uuid.UUID `json:"provider_id,omitempty"`
+	BookedBy         *uuid.UUID `json:"booked_by,omitempty"`
+	GoodsDescription *string    `json:"goods_description,omitempty"`
+	PickupAddress    *string    `json:"pickup_address,omitempty"`
+	DeliveryAddress  *string    `json:"delivery_address,omitempty"`
+
+	EstimatedPickupAt   *time.Time `json:"estimated_pickup_at,omitempty"`
+	EstimatedDeliveryAt *time.Time `json:"estimated_delivery_at,omitempty"`
+
+	Rules *ShippingRules `json:"rules,omitempty"`
+
+	ShipperID *uuid.UUID `json:"shipper_id,omitempty"`
+	DeviceID  *uuid.UUID `json:"device_id,omitempty"`
+	// ClearAssignment removes the shipper and device
+	ClearAssignment bool  `json:"clear_assignment,omitempty"`
+	RulesConfirmed  *bool `json:"rules_confirmed,omitempty"`
+
+	ActualPickupAt   *time.Time `json:"actual_pickup_at,omitempty"`
+	ActualDeliveryAt *time.Time `json:"actual_delivery_at,omitempty"`
+	CompletionNotes  *string    `json:"completion_notes,omitempty"`
+	CustomerRating   *int       `json:"customer_rating,omitempty"`
+
+	// Free text explaining the change, such as a cancellation reason
+	Reason *string `json:"reason,omitempty"`
+}
+
+// Snapshot is a shipment's state rebuilt from its events
+type Snapshot struct {
+	ShipmentID uuid.UUID
+	// Version is the number of events applied
+	Version int
+	AsOf    time.Time
+
+	Status           ShipmentStatus
+	CustomerID       uuid.UUID
+	ProviderID       uuid.UUID
+	BookedBy         *uuid.UUID
+	ShipperID        *uuid.UUID
+	DeviceID         *uuid.UUID
+	GoodsDescription string
+	PickupAddress    string
+	DeliveryAddress  string
+
+	EstimatedPickupAt   *time.Time
+	EstimatedDeliveryAt *time.Time
+	ActualPickupAt      *time.Time
+	ActualDeliveryAt    *time.Time
+
+	Rules          *ShippingRules
+	RulesConfirmed bool
+
+	CompletionNotes *string
+	CustomerRating  *int
+}
+
+// Replay folds events, oldest first, into the state they leave the shipment in
+func Replay(shipmentID uuid.UUID, events []*Event) *Snapshot {
+	snap := &Snapshot{ShipmentID: shipmentID}
+	for _, e := range events {
+		snap.apply(e)
+	}
+	return snap
+}
+
+func (s *Snapshot) apply(e *Event) {
+	s.Version++
+	s.AsOf = e.OccurredAt
+
+	d := e.Data
+	if d.Status != nil {
+		s.Status = *d.Status
+	}
+	if d.CustomerID != nil {
+		s.CustomerID = *d.CustomerID
+	}
+	if d.ProviderID != nil {
+		s.ProviderID = *d.ProviderID
+	}
+	if d.BookedBy != nil {
+		s.BookedBy = d.BookedBy
+	}
+	if d.GoodsDescription != nil {
+		s.GoodsDescription = *d.GoodsDescription
+	}
+	if d.PickupAddress != nil {
+		s.PickupAddress = *d.PickupAddress
+	}
+	if d.DeliveryAddress != nil {
+		s.DeliveryAddress = *d.DeliveryAddress
+	}
+	if d.EstimatedPickupAt != nil {
+		s.EstimatedPickupAt = d.EstimatedPickupAt
+	}
+	if d.EstimatedDeliveryAt != nil {
+		s.EstimatedDeliveryAt = d.EstimatedDeliveryAt
+	}
+	if d.Rules != nil {
+		s.Rules = d.Rules
+	}
+	if d.ClearAssignment {
+		s.ShipperID = nil
+		s.DeviceID = nil
+	}
+	if d.ShipperID != nil {
+		s.ShipperID = d.ShipperID
+	}
+	if d.DeviceID != nil {
+		s.DeviceID = d.DeviceID
+	}
+	if d.RulesConfirmed != nil {
+		s.RulesConfirmed = *d.RulesConfirmed
+	}
+	if d.ActualPickupAt != nil {
+		s.ActualPickupAt = d.ActualPickupAt
+	}
+	if d.ActualDeliveryAt != nil {
+		s.ActualDeliveryAt = d.ActualDeliveryAt
+	}
+	if d.CompletionNotes != nil {
+		s.CompletionNotes = d.CompletionNotes
+	}
+	if d.CustomerRating != nil {
+		s.CustomerRating = d.CustomerRating
+	}
+}
+
+// EventRepository stores the append-only shipment event log
+type EventRepository interface {
+	Append(ctx context.Context, event *Event) error
+	// List returns a shipment's events oldest first, up to and including
+	// until when it is set
+	List(ctx context.Context, shipmentID uuid.UUID, until *time.Time) ([]*Event, error)
+}
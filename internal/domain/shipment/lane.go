@@ -0,0 +1,35 @@
+package shipment
+
+import "time"
+
+// LaneQuery selects the past shipments of a lane, the pickup and delivery
+// areas they ran between
+type LaneQuery struct {
+	// Normalized areas, matched against the normalized pickup and delivery
+	// addresses
+	PickupArea   string
+	DeliveryArea string
+	Sandbox      bool
+	// Shipments created in [From, To)
+	From time.Time
+	To   time.Time
+}
+
+// LaneStatistics aggregates the shipments a shipper accepted on a lane
+type LaneStatistics struct {
+	Shipments int // Shipments a shipper accepted
+	Delivered int // Completed or partially completed
+	OnTime    int // Delivered by the calendar adjusted or estimated deadline
+	Partial   int // Delivered with at least one package exception
+	Cancelled int // Cancelled after a shipper accepted
+
+	// Transit is measured from actual pickup to actual delivery, over the
+	// delivered shipments that recorded both
+	Timed int
+	// Transit time percentiles in minutes; nil when Timed is zero
+	TransitAvg *float64
+	TransitP50 *float64
+	TransitP75 *float64
+	TransitP90 *float64
+	TransitP95 *float64
+}
@@ -0,0 +1,26 @@
+package shipment
+
+import "github.com/google/uuid"
+
+// MatchQuery selects the shippers that could take a posted order
+type MatchQuery struct {
+	Sandbox bool
+	// Device capabilities the shipment's rules need
+	Capabilities []string
+}
+
+// ShipperCandidate is a shipper with at least one available device, with
+// what the matching scores them on
+type ShipperCandidate struct {
+	ShipperID   uuid.UUID
+	ShipperName string
+	Record      ShipperRecord
+
+	// Shipments assigned to the shipper and not delivered yet
+	ActiveShipments int
+	// Available devices the shipper owns; Capable ones have every required
+	// capability and Unprofiled ones are of a model without a profile
+	AvailableDevices  int
+	CapableDevices    int
+	UnprofiledDevices int
+}
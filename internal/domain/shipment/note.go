@@ -0,0 +1,44 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteVisibility decides who can read a shipment note
+type NoteVisibility string
+
+const (
+	NoteInternal NoteVisibility = "internal" // Platform staff only
+	NoteParties  NoteVisibility = "parties"  // Staff and everyone who can view the shipment
+)
+
+// Note is a free-text comment attached to a shipment, outside its formal
+// fields
+type Note struct {
+	ID         uuid.UUID
+	ShipmentID uuid.UUID
+	AuthorID   uuid.UUID
+	Visibility NoteVisibility
+	Body       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	// Revisions hold the earlier versions of the note, oldest first. Only
+	// loaded with a single note.
+	Revisions []NoteRevision
+}
+
+// NoteRevision is a note as it stood before an edit
+type NoteRevision struct {
+	Body       string
+	Visibility NoteVisibility
+	EditedBy   uuid.UUID
+	EditedAt   time.Time
+}
+
+// IsEdited reports whether the note was changed after it was written
+func (n *Note) IsEdited() bool {
+	return n.UpdatedAt.After(n.CreatedAt)
+}
@@ -0,0 +1,49 @@
+package shipment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OverrideAction names a change an admin forced past the normal checks
+type OverrideAction string
+
+const (
+	OverrideForceStatus   OverrideAction = "force_status"
+	OverrideReleaseDevice OverrideAction = "release_device"
+	OverrideConfirmRules  OverrideAction = "confirm_rules"
+)
+
+// Override is the audit record of an admin override. The override and its
+// record are saved together, so every forced change has one.
+type Override struct {
+	ID     uuid.UUID
+	Action OverrideAction
+	// ShipmentID is nil for a device released while not linked to a shipment
+	ShipmentID *uuid.UUID
+	DeviceID   *uuid.UUID
+	AdminID    uuid.UUID
+	// Why the admin had to step in; always given
+	Justification string
+	// FromStatus and ToStatus are the shipment statuses before and after
+	FromStatus *ShipmentStatus
+	ToStatus   *ShipmentStatus
+	CreatedAt  time.Time
+}
+
+// OverrideRepository applies admin overrides together with their audit
+// records
+type OverrideRepository interface {
+	// ForceStatus moves the shipment to o.ToStatus from whatever status it
+	// is in. Moving it to a terminal status also releases its device.
+	ForceStatus(ctx context.Context, o *Override) error
+	// ReleaseDevice makes o.DeviceID available and detaches it from any
+	// shipment it is still held by
+	ReleaseDevice(ctx context.Context, o *Override) error
+	// ConfirmRules confirms the shipment's rules on behalf of its shipper
+	ConfirmRules(ctx context.Context, o *Override, shipperID uuid.UUID) error
+	// ListByShipment returns the overrides of a shipment, newest first
+	ListByShipment(ctx context.Context, shipmentID uuid.UUID) ([]*Override, error)
+}
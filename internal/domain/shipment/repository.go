@@ -11,8 +11,13 @@ import (
 type Repository interface {
 	Create(ctx context.Context, shipment *Shipment) error
 	GetByID(ctx context.Context, shipmentID uuid.UUID) (*Shipment, error)
+	// GetDetail reads the shipment, its parties, device, rules, packages and
+	// terms acceptance in a single query
+	GetDetail(ctx context.Context, shipmentID uuid.UUID) (*ShipmentDetail, error)
 	Update(ctx context.Context, shipment *Shipment) error
 	Delete(ctx context.Context, shipmentID uuid.UUID) error
+	// UpdateStatus sets the shipment's status. A terminal status frees the
+	// devices the shipment held in the same transaction.
 	UpdateStatus(ctx context.Context, shipmentID uuid.UUID, status ShipmentStatus) error
 	List(ctx context.Context, filter *Filter) ([]*Shipment, int64, error)
 	GetStatistics(ctx context.Context) (*Statistics, error)
@@ -20,14 +25,119 @@ type Repository interface {
 	SetActualPickup(ctx context.Context, shipmentID uuid.UUID, pickupTime time.Time) error
 	SetActualDelivery(ctx context.Context, shipmentID uuid.UUID, deliveryTime time.Time, notes *string) error
 	SetCustomerRating(ctx context.Context, shipmentID uuid.UUID, rating int, feedback *string) error
-	GetMarketplaceListings(ctx context.Context, page, pageSize int) ([]*Shipment, int64, error)
+	GetMarketplaceListings(ctx context.Context, sandbox bool, page, pageSize int) ([]*Shipment, int64, error)
 	AssignShipper(ctx context.Context, shipmentID, shipperID uuid.UUID) error
 	AssignDevice(ctx context.Context, shipmentID, deviceID uuid.UUID) error
 
 	CreateRules(ctx context.Context, rules *ShippingRules) error
 	GetRulesByShipmentID(ctx context.Context, shipmentID uuid.UUID) (*ShippingRules, error)
-	UpdateRules(ctx context.Context, rules *ShippingRules) error
+	// UpdateRules saves changed rules as their next version, made by editedBy
+	UpdateRules(ctx context.Context, rules *ShippingRules, editedBy uuid.UUID) error
+	// ListRulesVersions returns a shipment's rules versions, oldest first
+	ListRulesVersions(ctx context.Context, shipmentID uuid.UUID) ([]*RulesVersion, error)
 	ConfirmRules(ctx context.Context, shipmentID, shipperID uuid.UUID) error
+
+	CreatePackage(ctx context.Context, pkg *Package) error
+	GetPackageByID(ctx context.Context, packageID uuid.UUID) (*Package, error)
+	ListPackages(ctx context.Context, shipmentID uuid.UUID) ([]*Package, error)
+	UpdatePackage(ctx context.Context, pkg *Package) error
+	DeletePackage(ctx context.Context, packageID uuid.UUID) error
+	AssignPackageDevice(ctx context.Context, packageID, deviceID uuid.UUID) error
+	RecordPackageOutcome(ctx context.Context, pkg *Package) error
+	// ConfirmPendingPackages marks every still pending package of a shipment delivered
+	ConfirmPendingPackages(ctx context.Context, shipmentID, recordedBy uuid.UUID, at time.Time) error
+	// ResolveDevice finds the active shipment a device reports for, either as
+	// the shipment-level tracker or through one of its packages.
+	ResolveDevice(ctx context.Context, deviceID uuid.UUID) (*DeviceAssignment, error)
+	// SetRiskAssessment stores the latest risk assessment of a shipment
+	SetRiskAssessment(ctx context.Context, shipmentID uuid.UUID, risk *RiskAssessment) error
+	// GetShipperRecord summarises the shipper's finished shipments
+	GetShipperRecord(ctx context.Context, shipperID uuid.UUID) (*ShipperRecord, error)
+	// CompareShippers aggregates, per shipper, the provider shipments
+	// matching query, best on-time record first
+	CompareShippers(ctx context.Context, query *ComparisonQuery) ([]*ShipperPerformance, error)
+	// GetLaneStatistics aggregates the accepted shipments on the lane of query
+	GetLaneStatistics(ctx context.Context, query *LaneQuery) (*LaneStatistics, error)
+	// ListShipperCandidates returns the active shippers that own an
+	// available device, in the query's sandbox mode
+	ListShipperCandidates(ctx context.Context, query *MatchQuery) ([]*ShipperCandidate, error)
+	// SetDeliveryDueAt stores the calendar adjusted delivery deadline
+	SetDeliveryDueAt(ctx context.Context, shipmentID uuid.UUID, dueAt *time.Time) error
+	// DeleteSandboxData removes every sandbox shipment the user is a party of
+	DeleteSandboxData(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// ListStuck returns the shipments matching query, longest stuck first
+	ListStuck(ctx context.Context, query *StuckQuery) ([]*Shipment, error)
+	// RecordWatchdogStage marks a stage as handled for the shipment's current
+	// stay in its status. It returns false when the stage was already recorded.
+	RecordWatchdogStage(ctx context.Context, s *Shipment, stage WatchdogStage) (bool, error)
+	// RevertAssignment puts a shipment that is still shipping_assigned back
+	// on the marketplace: shipper, device and rules confirmation are cleared
+	// and the device is made available again
+	RevertAssignment(ctx context.Context, shipmentID uuid.UUID) error
+	// ReleaseStaleDevices frees up to limit devices left held by finished or
+	// deleted shipments and returns them
+	ReleaseStaleDevices(ctx context.Context, limit int) ([]*StaleDevice, error)
+}
+
+// AccessGrantRepository stores third-party access grants to shipments
+type AccessGrantRepository interface {
+	Create(ctx context.Context, grant *AccessGrant) error
+	GetByID(ctx context.Context, grantID uuid.UUID) (*AccessGrant, error)
+	ListByShipment(ctx context.Context, shipmentID uuid.UUID) ([]*AccessGrant, error)
+	// ListActiveForGrantee returns the unrevoked, unexpired grants a user holds
+	ListActiveForGrantee(ctx context.Context, granteeID uuid.UUID, at time.Time) ([]*AccessGrant, error)
+	// FindActive returns the grantee's usable grants on one shipment
+	FindActive(ctx context.Context, shipmentID, granteeID uuid.UUID, at time.Time) ([]*AccessGrant, error)
+	Revoke(ctx context.Context, grantID uuid.UUID, at time.Time) error
+}
+
+// DelegationRepository stores the delegations customers grant to forwarders
+type DelegationRepository interface {
+	Create(ctx context.Context, delegation *Delegation) error
+	GetByID(ctx context.Context, delegationID uuid.UUID) (*Delegation, error)
+	// ListByCustomer returns every delegation a customer granted, revoked
+	// ones included
+	ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*Delegation, error)
+	// ListActiveForDelegate returns the customers a user may currently act for
+	ListActiveForDelegate(ctx context.Context, delegateID uuid.UUID) ([]*Delegation, error)
+	// FindActive returns the unrevoked delegation between the two, or
+	// ErrDelegationNotFound
+	FindActive(ctx context.Context, customerID, delegateID uuid.UUID) (*Delegation, error)
+	Revoke(ctx context.Context, delegationID uuid.UUID, at time.Time) error
+}
+
+// NoteRepository stores shipment notes and their edit history
+type NoteRepository interface {
+	Create(ctx context.Context, note *Note) error
+	// GetByID returns a note with its revisions
+	GetByID(ctx context.Context, noteID uuid.UUID) (*Note, error)
+	// ListByShipment returns a shipment's notes with one of visibilities,
+	// oldest first
+	ListByShipment(ctx context.Context, shipmentID uuid.UUID, visibilities []NoteVisibility) ([]*Note, error)
+	// Update saves the note's new body and visibility along with the
+	// revision it replaces
+	Update(ctx context.Context, note *Note, previous NoteRevision) error
+	Delete(ctx context.Context, noteID uuid.UUID) error
+}
+
+// TermsRepository stores provider terms of carriage and shipper acceptances
+type TermsRepository interface {
+	// CreateTerms stores terms as the provider's next version
+	CreateTerms(ctx context.Context, terms *CarriageTerms) error
+	GetTerms(ctx context.Context, termsID uuid.UUID) (*CarriageTerms, error)
+	GetLatestTerms(ctx context.Context, providerID uuid.UUID) (*CarriageTerms, error)
+	ListTerms(ctx context.Context, providerID uuid.UUID) ([]*CarriageTerms, error)
+
+	CreateAcceptance(ctx context.Context, acceptance *TermsAcceptance) error
+	GetAcceptance(ctx context.Context, shipmentID uuid.UUID) (*TermsAcceptance, error)
+}
+
+// CalendarRepository stores provider business calendars
+type CalendarRepository interface {
+	Get(ctx context.Context, providerID uuid.UUID) (*BusinessCalendar, error)
+	Save(ctx context.Context, calendar *BusinessCalendar) error
+	Delete(ctx context.Context, providerID uuid.UUID) error
 }
 
 // Filter represents filtering options for listing shipments
@@ -37,6 +147,7 @@ type Filter struct {
 	ProviderID *uuid.UUID
 	ShipperID  *uuid.UUID
 	DeviceID   *uuid.UUID
+	Sandbox    *bool
 
 	// Date range filters
 	CreatedAfter   *time.Time
@@ -49,7 +160,7 @@ type Filter struct {
 	IsDelayed *bool
 	HasDevice *bool
 
-	// Search
+	// Search, lowercased and unaccented (see utils.NormalizeSearch)
 	Search string
 
 	// Pagination
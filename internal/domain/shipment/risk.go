@@ -0,0 +1,36 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RiskFactor is one weighted input of a risk assessment, scored 0 (safe) to
+// 100 (risky)
+type RiskFactor struct {
+	Name   string  `json:"name"`
+	Score  int     `json:"score"`
+	Weight float64 `json:"weight"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// RiskAssessment is the 0–100 risk score of a shipment assignment with the
+// factors it was computed from
+type RiskAssessment struct {
+	Score      int
+	Factors    []RiskFactor
+	AssessedAt time.Time
+}
+
+// ShipperRecord summarises a shipper's finished shipments for reputation scoring
+type ShipperRecord struct {
+	ShipperID uuid.UUID
+	Finished  int // Completed, partially completed or cancelled
+	Completed int
+	Partial   int
+	Cancelled int
+	Issues    int // Shipments currently in issue_reported
+	Rated     int
+	AvgRating float64
+}
@@ -0,0 +1,70 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RulesVersion is one state a shipment's shipping rules have been in. A
+// version is added each time the rules are set or changed.
+type RulesVersion struct {
+	ID         uuid.UUID
+	ShipmentID uuid.UUID
+	Version    int
+	// Rules holds the thresholds of this version; confirmation is only
+	// tracked on the current rules
+	Rules ShippingRules
+	SetBy uuid.UUID
+	SetAt time.Time
+}
+
+// RulesChange is one field that differs between two rules versions. From and
+// To are nil where the field was unset.
+type RulesChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// DiffRules lists the thresholds that differ from prev to next, named as in
+// the API. A nil prev diffs against rules with nothing set.
+func DiffRules(prev, next *ShippingRules) []RulesChange {
+	if prev == nil {
+		prev = &ShippingRules{}
+	}
+
+	changes := []RulesChange{}
+	addInt := func(field string, from, to int) {
+		if from != to {
+			changes = append(changes, RulesChange{Field: field, From: from, To: to})
+		}
+	}
+	addFloat := func(field string, from, to *float64) {
+		if from == nil && to == nil || from != nil && to != nil && *from == *to {
+			return
+		}
+		change := RulesChange{Field: field}
+		if from != nil {
+			change.From = *from
+		}
+		if to != nil {
+			change.To = *to
+		}
+		changes = append(changes, change)
+	}
+
+	addInt("report_cycle_sec", prev.ReportCycleSec, next.ReportCycleSec)
+	addFloat("temp_min", prev.TempMin, next.TempMin)
+	addFloat("temp_max", prev.TempMax, next.TempMax)
+	addFloat("humidity_min", prev.HumidityMin, next.HumidityMin)
+	addFloat("humidity_max", prev.HumidityMax, next.HumidityMax)
+	addFloat("light_max", prev.LightMax, next.LightMax)
+	addFloat("tilt_max_angle", prev.TiltMaxAngle, next.TiltMaxAngle)
+	addFloat("impact_threshold_g", prev.ImpactThresholdG, next.ImpactThresholdG)
+	if prev.EnablePredictiveAlert != next.EnablePredictiveAlert {
+		changes = append(changes, RulesChange{Field: "enable_predictive_alert", From: prev.EnablePredictiveAlert, To: next.EnablePredictiveAlert})
+	}
+	addInt("alert_buffer_time_min", prev.AlertBufferTimeMin, next.AlertBufferTimeMin)
+	return changes
+}
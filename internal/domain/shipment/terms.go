@@ -0,0 +1,33 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CarriageTerms is one published version of a provider's terms of carriage.
+// Versions are immutable; publishing new terms adds a version.
+type CarriageTerms struct {
+	ID         uuid.UUID
+	ProviderID uuid.UUID
+	Version    int
+	Title      string
+	Body       string
+	CreatedAt  time.Time
+}
+
+// TermsAcceptance records a shipper legally accepting a terms version for a
+// shipment, signed when the shipping rules are confirmed
+type TermsAcceptance struct {
+	ID                   uuid.UUID
+	ShipmentID           uuid.UUID
+	TermsID              uuid.UUID
+	TermsVersion         int
+	ShipperID            uuid.UUID
+	IPAddress            string
+	UserAgent            *string
+	SignatureKey         string
+	SignatureContentType string
+	AcceptedAt           time.Time
+}
@@ -0,0 +1,78 @@
+package shipment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TripStatus represents the state of a multi-stop trip
+type TripStatus string
+
+const (
+	TripActive    TripStatus = "active"
+	TripCompleted TripStatus = "completed"
+	TripCancelled TripStatus = "cancelled"
+)
+
+// Trip groups several shipments a shipper carries in one vehicle. The trip's
+// device is linked to every member shipment, so its telemetry applies to all
+// of them.
+type Trip struct {
+	ID          uuid.UUID
+	ShipperID   uuid.UUID
+	DeviceID    uuid.UUID
+	Name        *string
+	Status      TripStatus
+	Stops       []TripStop // Ordered by Sequence
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TripStop is the delivery of one member shipment, in visiting order
+type TripStop struct {
+	ID          uuid.UUID
+	TripID      uuid.UUID
+	ShipmentID  uuid.UUID
+	Sequence    int
+	CompletedAt *time.Time
+}
+
+// PendingStops returns the stops not completed yet, in order
+func (t *Trip) PendingStops() []TripStop {
+	var pending []TripStop
+	for _, stop := range t.Stops {
+		if stop.CompletedAt == nil {
+			pending = append(pending, stop)
+		}
+	}
+	return pending
+}
+
+// Stop returns the stop of a member shipment
+func (t *Trip) Stop(shipmentID uuid.UUID) (*TripStop, bool) {
+	for i := range t.Stops {
+		if t.Stops[i].ShipmentID == shipmentID {
+			return &t.Stops[i], true
+		}
+	}
+	return nil, false
+}
+
+// TripRepository defines the interface for trip storage
+type TripRepository interface {
+	// Create stores the trip together with its stops and, in the same
+	// transaction, links the trip device to every member shipment, makes the
+	// devices the members had available and marks the trip device in transit
+	Create(ctx context.Context, trip *Trip) error
+	GetByID(ctx context.Context, tripID uuid.UUID) (*Trip, error)
+	ListByShipper(ctx context.Context, shipperID uuid.UUID, status *TripStatus) ([]*Trip, error)
+	// FindActiveByShipment returns the active trip a shipment is a stop of
+	FindActiveByShipment(ctx context.Context, shipmentID uuid.UUID) (*Trip, error)
+	// FindActiveByDevice returns the active trip a device reports for
+	FindActiveByDevice(ctx context.Context, deviceID uuid.UUID) (*Trip, error)
+	CompleteStop(ctx context.Context, stopID uuid.UUID, at time.Time) error
+	UpdateStatus(ctx context.Context, tripID uuid.UUID, status TripStatus, at time.Time) error
+}
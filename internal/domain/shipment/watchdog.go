@@ -0,0 +1,47 @@
+package shipment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WatchdogStage is a step the watchdog takes for a shipment that stays in
+// an intermediate status for too long
+type WatchdogStage string
+
+const (
+	// WatchdogReminded means the party expected to act was reminded
+	WatchdogReminded WatchdogStage = "reminded"
+	// WatchdogEscalated means the other parties and operations were told
+	WatchdogEscalated WatchdogStage = "escalated"
+	// WatchdogReverted means the assignment was released to the marketplace
+	WatchdogReverted WatchdogStage = "reverted"
+)
+
+// StuckQuery selects the shipments due for a watchdog stage
+type StuckQuery struct {
+	Status ShipmentStatus
+	// Only shipments that entered Status before this time
+	ChangedBefore time.Time
+	// Leave out shipments that already went through Stage during their
+	// current stay in Status
+	Stage WatchdogStage
+	// When set, only shipments whose Previous stage was recorded before
+	// PreviousBefore, so that stages are spaced out
+	Previous       WatchdogStage
+	PreviousBefore time.Time
+	Limit          int
+}
+
+// StaleDevice is a device the watchdog released because no active shipment
+// or trip held it any more
+type StaleDevice struct {
+	DeviceID uuid.UUID
+	// The shipment the device still pointed at, if any, and its status;
+	// the status is nil when the shipment no longer exists
+	ShipmentID     *uuid.UUID
+	ShipmentStatus *ShipmentStatus
+	// Status the device was left in
+	DeviceStatus string
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrObjectNotFound = errors.New("stored object not found")
+	ErrInvalidKey     = errors.New("invalid storage key")
+)
+
+// Store persists uploaded files and generated exports. Keys are slash
+// separated paths such as shipments/<id>/documents/<file>.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AddressStatus tells whether a saved address is known to be deliverable
+type AddressStatus string
+
+const (
+	// AddressUnverified is a new or edited address not yet used successfully
+	AddressUnverified AddressStatus = "unverified"
+	// AddressVerified has received or dispatched a completed shipment
+	AddressVerified AddressStatus = "verified"
+)
+
+// SavedAddress is an entry of a user's address book, such as a warehouse or
+// store, that can be referenced when creating shipments
+type SavedAddress struct {
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+	Label   string
+
+	Line1       string
+	Line2       *string
+	Ward        *string
+	District    *string
+	City        string
+	Province    *string
+	PostalCode  *string
+	CountryCode string // ISO 3166-1 alpha-2
+
+	Latitude  *float64
+	Longitude *float64
+
+	ContactName  *string
+	ContactPhone *string
+
+	Status     AddressStatus
+	VerifiedAt *time.Time
+
+	// Reuse analytics
+	UseCount   int
+	LastUsedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Formatted returns the address on one line, as stored on shipments
+func (a *SavedAddress) Formatted() string {
+	parts := []string{a.Line1}
+	for _, part := range []*string{a.Line2, a.Ward, a.District} {
+		if part != nil && *part != "" {
+			parts = append(parts, *part)
+		}
+	}
+	parts = append(parts, a.City)
+	if a.Province != nil && *a.Province != "" {
+		parts = append(parts, *a.Province)
+	}
+	if a.PostalCode != nil && *a.PostalCode != "" {
+		parts = append(parts, *a.PostalCode)
+	}
+	parts = append(parts, a.CountryCode)
+	return strings.Join(parts, ", ")
+}
+
+// HasCoordinates reports whether the address has been geocoded
+func (a *SavedAddress) HasCoordinates() bool {
+	return a.Latitude != nil && a.Longitude != nil
+}
@@ -0,0 +1,28 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Branding holds how a provider is presented on shared tracking links and
+// exported documents. Unset fields fall back to the platform defaults.
+type Branding struct {
+	ProviderID      uuid.UUID
+	DisplayName     *string
+	PrimaryColor    *string
+	AccentColor     *string
+	SupportEmail    *string
+	SupportPhone    *string
+	SupportURL      *string
+	LogoKey         *string
+	LogoContentType *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// HasLogo reports whether a logo has been uploaded
+func (b *Branding) HasLogo() bool {
+	return b.LogoKey != nil && *b.LogoKey != ""
+}
@@ -14,13 +14,28 @@ type User struct {
 	PasswordHashed string
 	FullName       string
 	PhoneNumber    *string
+	PhoneVerified  *time.Time // Set once the number proved reachable by OTP
 	Role           string
 	Address        *string
 	IsActive       bool
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	IsSandbox      bool // Sandbox accounts only see and create sandbox data
+	Plan           string
+	// Providers: currency of shipments that do not name one
+	DefaultCurrency *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
+// Plans an account can be on; the plan decides its usage quotas and features
+const (
+	PlanFree       = "free"
+	PlanPro        = "pro"
+	PlanEnterprise = "enterprise"
+)
+
+// Plans lists the plans from the cheapest up
+var Plans = []string{PlanFree, PlanPro, PlanEnterprise}
+
 // PasswordResetToken represents a password reset token entity
 type PasswordResetToken struct {
 	ID        uuid.UUID
@@ -31,16 +46,32 @@ type PasswordResetToken struct {
 	CreatedAt time.Time
 }
 
-// RefreshToken represents a refresh token entity
+// Reasons a refresh token was revoked
+const (
+	RevokeReasonLogout       = "logout"
+	RevokeReasonRotated      = "rotated"
+	RevokeReasonRevokedAll   = "revoked_all"
+	RevokeReasonInactivity   = "inactivity"
+	RevokeReasonSessionLimit = "session_limit"
+	RevokeReasonMerged       = "merged" // Account was merged into another
+	RevokeReasonRoleChanged  = "role_changed"
+)
+
+// RefreshToken represents a refresh token entity. A session is the chain of
+// refresh tokens issued from one login; each refresh revokes the previous
+// token and carries the session start and activity forward.
 type RefreshToken struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Token     string
-	ExpiresAt time.Time
-	Revoked   bool
-	RevokedAt time.Time
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	Token            string
+	ExpiresAt        time.Time
+	Revoked          bool
+	RevokedAt        time.Time
+	RevokeReason     string
+	SessionStartedAt time.Time
+	LastActivityAt   time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 // IsExpired checks if the refresh token is expired
@@ -52,3 +83,9 @@ func (rt *RefreshToken) IsExpired() bool {
 func (rt *RefreshToken) IsActive() bool {
 	return !rt.Revoked && !rt.IsExpired()
 }
+
+// IsIdle checks if the session saw no activity for longer than timeout. A
+// zero timeout never idles.
+func (rt *RefreshToken) IsIdle(now time.Time, timeout time.Duration) bool {
+	return timeout > 0 && now.Sub(rt.LastActivityAt) > timeout
+}
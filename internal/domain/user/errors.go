@@ -3,12 +3,24 @@ package user
 import "errors"
 
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrUserAlreadyExists = errors.New("user already exists")
-	ErrUserInactive      = errors.New("user account is inactive")
-	ErrInvalidUserRole   = errors.New("invalid user role")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrPhoneAlreadyExists = errors.New("phone number is already registered")
+	ErrUserInactive       = errors.New("user account is inactive")
+	ErrInvalidUserRole    = errors.New("invalid user role")
 
 	ErrTokenInvalid   = errors.New("token is invalid")
 	ErrTokenExpired   = errors.New("token has expired")
 	ErrResetTokenUsed = errors.New("reset token has already been used")
+	ErrOTPNotFound    = errors.New("no active verification code")
+
+	ErrBrandingNotFound  = errors.New("branding not found")
+	ErrAddressNotFound   = errors.New("address not found")
+	ErrAddressLabelTaken = errors.New("an address with this label already exists")
+
+	ErrMergeNotFound = errors.New("account merge not found")
+	ErrAlreadyMerged = errors.New("account has already been merged")
+
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationUsed     = errors.New("invitation is no longer valid")
 )
@@ -0,0 +1,61 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvitationStatus is derived from an invitation's timestamps
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationRevoked  InvitationStatus = "revoked"
+	InvitationExpired  InvitationStatus = "expired"
+)
+
+// Invitation lets one email address register with a role that cannot be
+// chosen at self-registration. Only a hash of the token is stored.
+type Invitation struct {
+	ID         uuid.UUID
+	Email      string
+	Role       string
+	TokenHash  string
+	InvitedBy  uuid.UUID
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+	AcceptedBy *uuid.UUID
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Status returns where the invitation stands at now
+func (i *Invitation) Status(now time.Time) InvitationStatus {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	}
+	return InvitationPending
+}
+
+// InvitationRepository stores registration invitations
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *Invitation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Invitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Invitation, error)
+	// List returns invitations newest first; pendingOnly leaves out accepted,
+	// revoked and expired ones
+	List(ctx context.Context, pendingOnly bool, limit, offset int) ([]*Invitation, int64, error)
+	// Accept marks a pending invitation as used by userID. It fails with
+	// ErrInvitationUsed when the invitation was accepted or revoked meanwhile.
+	Accept(ctx context.Context, id, userID uuid.UUID, at time.Time) error
+	// Revoke withdraws a pending invitation
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,41 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MergeCount is how many rows of one table column reference the duplicate
+// account. Skipped rows conflict with rows of the primary account, such as
+// a saved address with the same label, and stay with the duplicate.
+type MergeCount struct {
+	Table   string `json:"table"`
+	Column  string `json:"column"`
+	Moved   int64  `json:"moved"`
+	Skipped int64  `json:"skipped"`
+}
+
+// AccountMerge records that a duplicate account was folded into a primary
+// account. Merges cannot be undone.
+type AccountMerge struct {
+	ID              uuid.UUID
+	PrimaryUserID   uuid.UUID
+	DuplicateUserID uuid.UUID
+	MergedBy        uuid.UUID
+	Counts          []MergeCount
+	CreatedAt       time.Time
+}
+
+// MergeRepository moves the data of a duplicate account to a primary one
+type MergeRepository interface {
+	// Preview counts the rows a merge would move and skip
+	Preview(ctx context.Context, primaryID, duplicateID uuid.UUID) ([]MergeCount, error)
+	// Merge moves the rows, revokes the duplicate's sessions, deactivates it
+	// and stores the audit record in one transaction. merge.Counts is set to
+	// what was actually moved.
+	Merge(ctx context.Context, merge *AccountMerge) error
+	// GetByDuplicate returns the merge a duplicate account was folded into
+	GetByDuplicate(ctx context.Context, duplicateID uuid.UUID) (*AccountMerge, error)
+}
@@ -0,0 +1,33 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OTPPurpose is what a one-time password was issued for
+type OTPPurpose string
+
+const (
+	OTPVerifyPhone   OTPPurpose = "verify_phone"
+	OTPResetPassword OTPPurpose = "reset_password"
+)
+
+// PhoneOTP is a one-time password sent by SMS. Only a hash of the code is
+// stored; expiry and failed attempts are tracked server-side.
+type PhoneOTP struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Purpose    OTPPurpose
+	CodeHash   string
+	ExpiresAt  time.Time
+	Attempts   int
+	ConsumedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// IsUsable checks if the code can still be tried
+func (o *PhoneOTP) IsUsable(now time.Time, maxAttempts int) bool {
+	return o.ConsumedAt == nil && now.Before(o.ExpiresAt) && o.Attempts < maxAttempts
+}
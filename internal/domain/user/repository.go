@@ -12,22 +12,84 @@ type Repository interface {
 	Create(ctx context.Context, user *User) error
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByID(ctx context.Context, userID uuid.UUID) (*User, error)
-	GetAll(ctx context.Context) ([]*User, error)
+	GetByPhone(ctx context.Context, phoneNumber string) (*User, error)
+	// List returns a page of the users matching filter and their total count
+	List(ctx context.Context, filter *Filter) ([]*User, int64, error)
+	// Count counts the users matching filter, ignoring its pagination
+	Count(ctx context.Context, filter *Filter) (int64, error)
 	Update(ctx context.Context, user *User) error
 	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	UpdateRole(ctx context.Context, userID uuid.UUID, role string) error
+	UpdatePlan(ctx context.Context, userID uuid.UUID, plan string) error
 	Delete(ctx context.Context, userID uuid.UUID) error
 
 	CreatePasswordResetToken(ctx context.Context, token *PasswordResetToken) error
 	GetPasswordResetToken(ctx context.Context, token string) (*PasswordResetToken, error)
 	MarkTokenAsUsed(ctx context.Context, tokenID uuid.UUID) error
+
+	SetPhoneVerified(ctx context.Context, userID uuid.UUID, at *time.Time) error
+}
+
+// Filter selects users for the admin listing. Empty fields do not restrict.
+type Filter struct {
+	Role             string
+	IsActive         *bool
+	RegisteredAfter  *time.Time
+	RegisteredBefore *time.Time
+
+	// Search on name and email, lowercased and unaccented (see
+	// utils.NormalizeSearch)
+	Search string
+
+	// Pagination
+	Page      int
+	PageSize  int
+	SortBy    string
+	SortOrder string
+}
+
+// OTPRepository defines the interface for one-time password operations
+type OTPRepository interface {
+	Create(ctx context.Context, otp *PhoneOTP) error
+	// GetLatest returns the most recent unconsumed code of a user for purpose
+	GetLatest(ctx context.Context, userID uuid.UUID, purpose OTPPurpose) (*PhoneOTP, error)
+	// CountSince counts codes issued to a user for purpose since a time
+	CountSince(ctx context.Context, userID uuid.UUID, purpose OTPPurpose, since time.Time) (int64, error)
+	IncrementAttempts(ctx context.Context, otpID uuid.UUID) error
+	Consume(ctx context.Context, otpID uuid.UUID, at time.Time) error
+	DeleteExpired(ctx context.Context, olderThan time.Duration) error
 }
 
 // RefreshTokenRepository defines the interface for refresh token operations
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, token *RefreshToken) error
 	GetByToken(ctx context.Context, token string) (*RefreshToken, error)
-	Revoke(ctx context.Context, tokenID uuid.UUID) error
-	RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error
+	// FindByToken returns a token whether or not it is still active
+	FindByToken(ctx context.Context, token string) (*RefreshToken, error)
+	Revoke(ctx context.Context, tokenID uuid.UUID, reason string) error
+	RevokeAllUserTokens(ctx context.Context, userID uuid.UUID, reason string) error
+	// RevokeIdle revokes active tokens with no activity since idleSince
+	RevokeIdle(ctx context.Context, idleSince time.Time) (int64, error)
 	DeleteExpired(ctx context.Context, olderThan time.Duration) error
 	GetUserTokens(ctx context.Context, userID uuid.UUID) ([]*RefreshToken, error)
 }
+
+// BrandingRepository defines the interface for provider branding operations
+type BrandingRepository interface {
+	Get(ctx context.Context, providerID uuid.UUID) (*Branding, error)
+	Save(ctx context.Context, branding *Branding) error
+}
+
+// AddressRepository defines the interface for address book operations
+type AddressRepository interface {
+	Create(ctx context.Context, address *SavedAddress) error
+	GetByID(ctx context.Context, addressID uuid.UUID) (*SavedAddress, error)
+	// ListByOwner returns the address book of a user, most used first
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*SavedAddress, error)
+	Update(ctx context.Context, address *SavedAddress) error
+	Delete(ctx context.Context, addressID uuid.UUID) error
+	// RecordUse counts a shipment created from the address
+	RecordUse(ctx context.Context, addressID uuid.UUID, at time.Time) error
+	// MarkVerified marks unverified addresses as verified
+	MarkVerified(ctx context.Context, addressIDs []uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,55 @@
+package currency
+
+import (
+	"cargo-tracker/internal/config"
+	domainCurrency "cargo-tracker/internal/domain/currency"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// New creates the exchange rate source selected in the configuration
+func New(cfg *config.CurrencyConfig) (domainCurrency.RateSource, error) {
+	reporting := strings.ToUpper(cfg.ReportingCurrency)
+	if reporting == "" {
+		reporting = "VND"
+	}
+
+	switch cfg.RateSource {
+	case "", "static":
+		rates, err := parseRates(cfg.StaticRates)
+		if err != nil {
+			return nil, err
+		}
+		return NewStaticSource(reporting, rates), nil
+	case "http":
+		if cfg.RatesURL == "" {
+			return nil, fmt.Errorf("CURRENCY_RATES_URL is required for the http rate source")
+		}
+		return NewHTTPSource(reporting, cfg.RatesURL, cfg.RatesTTL, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported exchange rate source %q", cfg.RateSource)
+	}
+}
+
+// parseRates reads a "USD=25400,EUR=27500" list of values of one unit in
+// the reporting currency
+func parseRates(s string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		code, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid exchange rate %q, expected CODE=rate", pair)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for %s: %q", code, value)
+		}
+		rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	return rates, nil
+}
@@ -0,0 +1,107 @@
+package currency
+
+import (
+	domainCurrency "cargo-tracker/internal/domain/currency"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/pkg/cache"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HTTPSource fetches rates from an HTTP endpoint answering
+// {"base": "VND", "rates": {"USD": 25400, "EUR": 27500}}, where each rate is
+// the value of one unit in the base currency. The base must match the
+// reporting currency. Rates are cached for the configured TTL and the last
+// good set keeps being served for another TTL while the endpoint fails.
+type HTTPSource struct {
+	client    *http.Client
+	url       string
+	reporting string
+	rates     *cache.Value[map[string]float64]
+}
+
+func NewHTTPSource(reporting, url string, ttl, timeout time.Duration) *HTTPSource {
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	s := &HTTPSource{
+		client:    &http.Client{Timeout: timeout},
+		url:       url,
+		reporting: reporting,
+	}
+	s.rates = cache.NewValue(s.fetch, ttl, ttl)
+	return s
+}
+
+func (s *HTTPSource) ReportingCurrency() string {
+	return s.reporting
+}
+
+func (s *HTTPSource) Rate(ctx context.Context, currency string) (float64, error) {
+	currency = strings.ToUpper(currency)
+	if currency == s.reporting {
+		return 1, nil
+	}
+
+	rates, err := s.rates.Get(ctx)
+	if err != nil {
+		logger.Warn("Failed to fetch exchange rates",
+			zap.Error(err),
+			zap.String("event", "exchange_rates_unavailable"),
+		)
+		return 0, domainCurrency.ErrRatesUnavailable
+	}
+	rate, ok := rates[currency]
+	if !ok {
+		return 0, domainCurrency.ErrUnknownCurrency
+	}
+	return rate, nil
+}
+
+func (s *HTTPSource) fetch(ctx context.Context) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build exchange rate request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("exchange rate request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("exchange rate endpoint returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var payload struct {
+		Base  string             `json:"base"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rates: %w", err)
+	}
+	if !strings.EqualFold(payload.Base, s.reporting) {
+		return nil, fmt.Errorf("exchange rate base %q does not match reporting currency %s", payload.Base, s.reporting)
+	}
+
+	rates := make(map[string]float64, len(payload.Rates))
+	for code, rate := range payload.Rates {
+		if rate > 0 {
+			rates[strings.ToUpper(code)] = rate
+		}
+	}
+	return rates, nil
+}
@@ -0,0 +1,33 @@
+package currency
+
+import (
+	domainCurrency "cargo-tracker/internal/domain/currency"
+	"context"
+	"strings"
+)
+
+// StaticSource serves fixed rates from the configuration
+type StaticSource struct {
+	reporting string
+	rates     map[string]float64
+}
+
+func NewStaticSource(reporting string, rates map[string]float64) *StaticSource {
+	return &StaticSource{reporting: reporting, rates: rates}
+}
+
+func (s *StaticSource) ReportingCurrency() string {
+	return s.reporting
+}
+
+func (s *StaticSource) Rate(_ context.Context, currency string) (float64, error) {
+	currency = strings.ToUpper(currency)
+	if currency == s.reporting {
+		return 1, nil
+	}
+	rate, ok := s.rates[currency]
+	if !ok {
+		return 0, domainCurrency.ErrUnknownCurrency
+	}
+	return rate, nil
+}
@@ -0,0 +1,346 @@
+package memory
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// offlineAfter is how long a device may stay silent before it counts as offline
+const offlineAfter = 5 * time.Minute
+
+// DeviceRepository implements domain.Device.Repository interface
+type DeviceRepository struct {
+	store *Store
+}
+
+// NewDeviceRepository creates a new in-memory device repository
+func NewDeviceRepository(store *Store) domainDevice.Repository {
+	return &DeviceRepository{store: store}
+}
+
+func (r *DeviceRepository) Create(ctx context.Context, d *domainDevice.Device) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.devices {
+		if existing.HardwareUID == d.HardwareUID {
+			return domainDevice.ErrDeviceAlreadyExists
+		}
+	}
+
+	d.ID = uuid.New()
+	d.CreatedAt = time.Now()
+	d.UpdatedAt = d.CreatedAt
+	d.Status = domainDevice.StatusAvailable
+	d.TotalTrips = 0
+
+	stored := *d
+	r.store.devices[d.ID] = &stored
+	return nil
+}
+
+func (r *DeviceRepository) GetByID(ctx context.Context, deviceID uuid.UUID) (*domainDevice.Device, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	d, ok := r.store.devices[deviceID]
+	if !ok {
+		return nil, domainDevice.ErrDeviceNotFound
+	}
+	found := *d
+	return &found, nil
+}
+
+func (r *DeviceRepository) GetByHardwareUID(ctx context.Context, hardwareUID string) (*domainDevice.Device, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, d := range r.store.devices {
+		if d.HardwareUID == hardwareUID {
+			found := *d
+			return &found, nil
+		}
+	}
+	return nil, domainDevice.ErrDeviceNotFound
+}
+
+func (r *DeviceRepository) Update(ctx context.Context, d *domainDevice.Device) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.devices[d.ID]
+	if !ok {
+		return domainDevice.ErrDeviceNotFound
+	}
+
+	d.UpdatedAt = time.Now()
+	stored.DeviceName = d.DeviceName
+	stored.Model = d.Model
+	stored.Status = d.Status
+	stored.FirmwareVersion = d.FirmwareVersion
+	stored.UpdatedAt = d.UpdatedAt
+	return nil
+}
+
+func (r *DeviceRepository) AssignOwner(ctx context.Context, deviceID, shipperID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.devices[deviceID]
+	if !ok || sameID(d.OwnerShipperID, shipperID) {
+		return domainDevice.ErrAssignmentFailed
+	}
+	d.OwnerShipperID = &shipperID
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *DeviceRepository) UnassignOwner(ctx context.Context, deviceID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.devices[deviceID]
+	if !ok || d.OwnerShipperID == nil {
+		return domainDevice.ErrUnassignmentFailed
+	}
+	d.OwnerShipperID = nil
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *DeviceRepository) UpdateStatus(ctx context.Context, deviceID uuid.UUID, status domainDevice.DeviceStatus) error {
+	return r.update(deviceID, func(d *domainDevice.Device) { d.Status = status })
+}
+
+func (r *DeviceRepository) UpdateBattery(ctx context.Context, deviceID uuid.UUID, batteryLevel int) error {
+	return r.update(deviceID, func(d *domainDevice.Device) { d.BatteryLevel = &batteryLevel })
+}
+
+func (r *DeviceRepository) UpdateLastSeen(ctx context.Context, deviceID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Like the Postgres repository, an unknown device is not an error
+	if d, ok := r.store.devices[deviceID]; ok {
+		now := time.Now()
+		d.LastSeenAt = &now
+		d.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r *DeviceRepository) SetCredentialHash(ctx context.Context, deviceID uuid.UUID, hash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.devices[deviceID]
+	if !ok {
+		return domainDevice.ErrDeviceNotFound
+	}
+	now := time.Now()
+	r.store.deviceCredentials[deviceID] = hash
+	d.CredentialIssuedAt = &now
+	d.UpdatedAt = now
+	return nil
+}
+
+func (r *DeviceRepository) GetByCredentialHash(ctx context.Context, hash string) (*domainDevice.Device, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for deviceID, credential := range r.store.deviceCredentials {
+		if credential == hash {
+			if d, ok := r.store.devices[deviceID]; ok {
+				found := *d
+				return &found, nil
+			}
+		}
+	}
+	return nil, domainDevice.ErrDeviceNotFound
+}
+
+func (r *DeviceRepository) Delete(ctx context.Context, deviceID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.devices[deviceID]
+	if !ok || d.CurrentShipmentID != nil {
+		return domainDevice.ErrDeviceInUse
+	}
+	d.Status = domainDevice.StatusRetired
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *DeviceRepository) Anonymize(ctx context.Context, deviceID uuid.UUID) error {
+	return r.update(deviceID, func(d *domainDevice.Device) {
+		d.Status = domainDevice.StatusRetired
+		d.OwnerShipperID = nil
+		d.DeviceName = nil
+		d.CurrentShipmentID = nil
+		d.BatteryLevel = nil
+	})
+}
+
+// update applies change to a stored device and bumps its updated_at
+func (r *DeviceRepository) update(deviceID uuid.UUID, change func(*domainDevice.Device)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.devices[deviceID]
+	if !ok {
+		return domainDevice.ErrDeviceNotFound
+	}
+	change(d)
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *DeviceRepository) GetStatistics(ctx context.Context) (*domainDevice.Statistics, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stats := &domainDevice.Statistics{}
+	owned := make(map[uuid.UUID]int)
+	for _, d := range r.store.devices {
+		stats.TotalDevices++
+		switch d.Status {
+		case domainDevice.StatusAvailable:
+			stats.AvailableDevices++
+		case domainDevice.StatusInTransit:
+			stats.InTransitDevices++
+		case domainDevice.StatusMaintenance:
+			stats.MaintenanceDevices++
+		case domainDevice.StatusRetired:
+			stats.RetiredDevices++
+		}
+		if d.BatteryLevel != nil && *d.BatteryLevel < 20 {
+			stats.LowBatteryDevices++
+		}
+		if isOffline(d) {
+			stats.OfflineDevices++
+		}
+		if d.OwnerShipperID != nil {
+			owned[*d.OwnerShipperID]++
+		}
+	}
+
+	for ownerID, count := range owned {
+		owner, ok := r.store.users[ownerID]
+		if !ok || owner.Role != "shipper" {
+			continue
+		}
+		stats.ByOwner = append(stats.ByOwner, domainDevice.OwnerStats{
+			OwnerID:     ownerID.String(),
+			OwnerName:   owner.FullName,
+			DeviceCount: count,
+		})
+	}
+	sort.Slice(stats.ByOwner, func(i, j int) bool {
+		return stats.ByOwner[i].DeviceCount > stats.ByOwner[j].DeviceCount
+	})
+
+	return stats, nil
+}
+
+func (r *DeviceRepository) List(ctx context.Context, filter *domainDevice.Filter) ([]*domainDevice.Device, int64, error) {
+	key, err := deviceSortKey(filter.SortBy)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.store.mu.RLock()
+	matched := make([]*domainDevice.Device, 0, len(r.store.devices))
+	for _, d := range r.store.devices {
+		if matchesDeviceFilter(d, filter) {
+			found := *d
+			matched = append(matched, &found)
+		}
+	}
+	r.store.mu.RUnlock()
+
+	sortRows(matched, filter.SortOrder, key, func(d *domainDevice.Device) uuid.UUID { return d.ID })
+	return paginate(matched, filter.Page, filter.PageSize), int64(len(matched)), nil
+}
+
+func matchesDeviceFilter(d *domainDevice.Device, filter *domainDevice.Filter) bool {
+	if filter.Status != nil && d.Status != *filter.Status {
+		return false
+	}
+	if filter.OwnerShipperID != nil && !sameID(d.OwnerShipperID, *filter.OwnerShipperID) {
+		return false
+	}
+	if filter.MinBattery != nil && (d.BatteryLevel == nil || *d.BatteryLevel < *filter.MinBattery) {
+		return false
+	}
+	if filter.MaxBattery != nil && (d.BatteryLevel == nil || *d.BatteryLevel > *filter.MaxBattery) {
+		return false
+	}
+	if filter.IsOffline != nil && *filter.IsOffline && !isOffline(d) {
+		return false
+	}
+	if filter.Search != "" {
+		search := strings.ToLower(filter.Search)
+		name := ""
+		if d.DeviceName != nil {
+			name = strings.ToLower(*d.DeviceName)
+		}
+		if !strings.Contains(strings.ToLower(d.HardwareUID), search) && !strings.Contains(name, search) {
+			return false
+		}
+	}
+	for _, capability := range filter.Capabilities {
+		if !slices.Contains(d.Capabilities, capability) {
+			return false
+		}
+	}
+	return true
+}
+
+func isOffline(d *domainDevice.Device) bool {
+	return d.LastSeenAt == nil || d.LastSeenAt.Before(time.Now().Add(-offlineAfter))
+}
+
+func deviceSortKey(sortBy string) (func(*domainDevice.Device) sortValue, error) {
+	switch sortBy {
+	case "", "created_at":
+		return func(d *domainDevice.Device) sortValue { return timeValue(d.CreatedAt) }, nil
+	case "updated_at":
+		return func(d *domainDevice.Device) sortValue { return timeValue(d.UpdatedAt) }, nil
+	case "battery_level":
+		return func(d *domainDevice.Device) sortValue { return optionalIntValue(d.BatteryLevel) }, nil
+	case "total_trips":
+		return func(d *domainDevice.Device) sortValue { return numberValue(float64(d.TotalTrips)) }, nil
+	case "last_seen_at":
+		return func(d *domainDevice.Device) sortValue { return optionalTimeValue(d.LastSeenAt) }, nil
+	}
+	return nil, fmt.Errorf("failed to list devices: unknown sort column %q", sortBy)
+}
+
+func (r *DeviceRepository) ListFleet(ctx context.Context, ownerID *uuid.UUID) ([]*domainDevice.Device, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	devices := make([]*domainDevice.Device, 0)
+	for _, d := range r.store.devices {
+		if d.Status == domainDevice.StatusRetired {
+			continue
+		}
+		if ownerID != nil && !sameID(d.OwnerShipperID, *ownerID) {
+			continue
+		}
+		found := *d
+		devices = append(devices, &found)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].HardwareUID < devices[j].HardwareUID
+	})
+	return devices, nil
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"cargo-tracker/internal/domain/user"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OTPRepository implements domain.User.OTPRepository interface
+type OTPRepository struct {
+	store *Store
+}
+
+// NewOTPRepository creates a new in-memory one-time password repository
+func NewOTPRepository(store *Store) user.OTPRepository {
+	return &OTPRepository{store: store}
+}
+
+func (r *OTPRepository) Create(ctx context.Context, otp *user.PhoneOTP) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	otp.ID = uuid.New()
+	otp.CreatedAt = time.Now()
+
+	stored := *otp
+	r.store.otps[otp.ID] = &stored
+	return nil
+}
+
+func (r *OTPRepository) GetLatest(ctx context.Context, userID uuid.UUID, purpose user.OTPPurpose) (*user.PhoneOTP, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var latest *user.PhoneOTP
+	for _, otp := range r.store.otps {
+		if otp.UserID != userID || otp.Purpose != purpose || otp.ConsumedAt != nil {
+			continue
+		}
+		if latest == nil || otp.CreatedAt.After(latest.CreatedAt) {
+			latest = otp
+		}
+	}
+	if latest == nil {
+		return nil, user.ErrOTPNotFound
+	}
+	found := *latest
+	return &found, nil
+}
+
+func (r *OTPRepository) CountSince(ctx context.Context, userID uuid.UUID, purpose user.OTPPurpose, since time.Time) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, otp := range r.store.otps {
+		if otp.UserID == userID && otp.Purpose == purpose && !otp.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *OTPRepository) IncrementAttempts(ctx context.Context, otpID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if otp, ok := r.store.otps[otpID]; ok {
+		otp.Attempts++
+	}
+	return nil
+}
+
+func (r *OTPRepository) Consume(ctx context.Context, otpID uuid.UUID, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	otp, ok := r.store.otps[otpID]
+	if !ok || otp.ConsumedAt != nil {
+		return user.ErrOTPNotFound
+	}
+	otp.ConsumedAt = &at
+	return nil
+}
+
+func (r *OTPRepository) DeleteExpired(ctx context.Context, olderThan time.Duration) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cutoffTime := time.Now().Add(-olderThan)
+	for id, otp := range r.store.otps {
+		if otp.ExpiresAt.Before(cutoffTime) {
+			delete(r.store.otps, id)
+		}
+	}
+	return nil
+}
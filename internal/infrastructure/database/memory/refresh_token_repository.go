@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"cargo-tracker/internal/domain/user"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenRepository implements domain.User.RefreshTokenRepository interface
+type RefreshTokenRepository struct {
+	store *Store
+}
+
+// NewRefreshTokenRepository creates a new in-memory refresh token repository
+func NewRefreshTokenRepository(store *Store) user.RefreshTokenRepository {
+	return &RefreshTokenRepository{store: store}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *user.RefreshToken) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	token.UpdatedAt = token.CreatedAt
+	token.Revoked = false
+	if token.SessionStartedAt.IsZero() {
+		token.SessionStartedAt = token.CreatedAt
+	}
+	if token.LastActivityAt.IsZero() {
+		token.LastActivityAt = token.CreatedAt
+	}
+
+	stored := *token
+	r.store.refreshTokens[token.ID] = &stored
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetByToken(ctx context.Context, token string) (*user.RefreshToken, error) {
+	found, err := r.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !found.IsActive() {
+		return nil, user.ErrTokenInvalid
+	}
+	return found, nil
+}
+
+func (r *RefreshTokenRepository) FindByToken(ctx context.Context, token string) (*user.RefreshToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, t := range r.store.refreshTokens {
+		if t.Token == token {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, user.ErrTokenInvalid
+}
+
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenID uuid.UUID, reason string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, ok := r.store.refreshTokens[tokenID]
+	if !ok || t.Revoked {
+		return user.ErrTokenInvalid
+	}
+	revoke(t, reason, time.Now())
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID, reason string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	for _, t := range r.store.refreshTokens {
+		if t.UserID == userID && !t.Revoked {
+			revoke(t, reason, now)
+		}
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeIdle(ctx context.Context, idleSince time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var revoked int64
+	for _, t := range r.store.refreshTokens {
+		if !t.Revoked && t.ExpiresAt.After(now) && t.LastActivityAt.Before(idleSince) {
+			revoke(t, user.RevokeReasonInactivity, now)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, olderThan time.Duration) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cutoffTime := time.Now().Add(-olderThan)
+	for id, t := range r.store.refreshTokens {
+		if t.ExpiresAt.Before(cutoffTime) || (t.Revoked && t.RevokedAt.Before(cutoffTime)) {
+			delete(r.store.refreshTokens, id)
+		}
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetUserTokens(ctx context.Context, userID uuid.UUID) ([]*user.RefreshToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tokens := make([]*user.RefreshToken, 0)
+	for _, t := range r.store.refreshTokens {
+		if t.UserID == userID && t.IsActive() {
+			found := *t
+			tokens = append(tokens, &found)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+func revoke(t *user.RefreshToken, reason string, at time.Time) {
+	t.Revoked = true
+	t.RevokedAt = at
+	t.RevokeReason = reason
+	t.UpdatedAt = at
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/domain/user"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUserRepositoryListPaginatesNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserRepository(NewStore())
+
+	for _, name := range []string{"Ánh Nguyễn", "Bao Tran", "Chi Le"} {
+		u := &user.User{Username: name, Email: name + "@example.com", FullName: name, Role: "customer"}
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Create(%s): %v", name, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	page, total, err := users.List(ctx, &user.Filter{Page: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 3 || len(page) != 2 {
+		t.Fatalf("List returned %d users of %d, want 2 of 3", len(page), total)
+	}
+	if page[0].FullName != "Chi Le" {
+		t.Errorf("first user = %q, want the newest, Chi Le", page[0].FullName)
+	}
+
+	// Search is matched against the normalized name, as search_text is
+	found, total, err := users.List(ctx, &user.Filter{Search: "anh nguyen"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 1 || found[0].FullName != "Ánh Nguyễn" {
+		t.Errorf("search found %d users, want Ánh Nguyễn only", total)
+	}
+}
+
+func TestUserRepositoryRejectsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserRepository(NewStore())
+	phone := "+84901234567"
+
+	if err := users.Create(ctx, &user.User{Username: "a", Email: "a@example.com", PhoneNumber: &phone}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := users.Create(ctx, &user.User{Username: "b", Email: "a@example.com"}); !errors.Is(err, user.ErrUserAlreadyExists) {
+		t.Errorf("duplicate email: err = %v, want ErrUserAlreadyExists", err)
+	}
+	if err := users.Create(ctx, &user.User{Username: "c", Email: "c@example.com", PhoneNumber: &phone}); !errors.Is(err, user.ErrPhoneAlreadyExists) {
+		t.Errorf("duplicate phone: err = %v, want ErrPhoneAlreadyExists", err)
+	}
+}
+
+func TestShipmentRepositoryReleasesDeviceWhenFinished(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	devices := NewDeviceRepository(store)
+	shipments := NewShipmentRepository(store)
+
+	d := &device.Device{HardwareUID: "HW-1"}
+	if err := devices.Create(ctx, d); err != nil {
+		t.Fatalf("Create device: %v", err)
+	}
+	s := &shipment.Shipment{CustomerID: uuid.New(), ProviderID: uuid.New(), GoodsDescription: "Vaccines"}
+	if err := shipments.Create(ctx, s); err != nil {
+		t.Fatalf("Create shipment: %v", err)
+	}
+
+	if err := shipments.AssignDevice(ctx, s.ID, d.ID); err != nil {
+		t.Fatalf("AssignDevice: %v", err)
+	}
+	held, _ := devices.GetByID(ctx, d.ID)
+	if held.Status != device.StatusInTransit || !sameID(held.CurrentShipmentID, s.ID) {
+		t.Fatalf("assigned device is %s on %v, want in_transit on the shipment", held.Status, held.CurrentShipmentID)
+	}
+
+	if err := shipments.UpdateStatus(ctx, s.ID, shipment.StatusCompleted); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	released, _ := devices.GetByID(ctx, d.ID)
+	if released.Status != device.StatusAvailable || released.CurrentShipmentID != nil {
+		t.Errorf("device is %s on %v after completion, want available and free", released.Status, released.CurrentShipmentID)
+	}
+}
+
+func TestShipmentRepositoryListFilters(t *testing.T) {
+	ctx := context.Background()
+	shipments := NewShipmentRepository(NewStore())
+	customerID := uuid.New()
+	past := time.Now().Add(-time.Hour)
+
+	delayed := &shipment.Shipment{CustomerID: customerID, Status: shipment.StatusInTransit, EstimatedDeliveryAt: &past}
+	onTime := &shipment.Shipment{CustomerID: customerID, Status: shipment.StatusInTransit}
+	other := &shipment.Shipment{CustomerID: uuid.New(), Status: shipment.StatusInTransit, EstimatedDeliveryAt: &past}
+	for _, s := range []*shipment.Shipment{delayed, onTime, other} {
+		if err := shipments.Create(ctx, s); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	isDelayed := true
+	found, total, err := shipments.List(ctx, &shipment.Filter{CustomerID: &customerID, IsDelayed: &isDelayed})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 1 || found[0].ID != delayed.ID {
+		t.Errorf("List found %d shipments, want the delayed one of the customer", total)
+	}
+
+	if _, _, err := shipments.List(ctx, &shipment.Filter{SortBy: "status; DROP TABLE"}); err == nil {
+		t.Error("List accepted an unknown sort column")
+	}
+}
@@ -0,0 +1,950 @@
+package memory
+
+import (
+	"cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/domain/shipment"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// activeShipmentStatuses are the statuses a device reports for
+var activeShipmentStatuses = []shipment.ShipmentStatus{
+	shipment.StatusShippingAssigned,
+	shipment.StatusInTransit,
+	shipment.StatusIssueReported,
+}
+
+// ShipmentRepository implements domain.Shipment.Repository interface. Trips
+// are not stored in memory, so releasing devices never keeps one back for an
+// active trip.
+type ShipmentRepository struct {
+	store *Store
+}
+
+// NewShipmentRepository creates a new in-memory shipment repository
+func NewShipmentRepository(store *Store) shipment.Repository {
+	return &ShipmentRepository{store: store}
+}
+
+func (r *ShipmentRepository) Create(ctx context.Context, s *shipment.Shipment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = s.CreatedAt
+	s.StatusChangedAt = s.CreatedAt
+	if s.Status == "" {
+		s.Status = shipment.StatusDemandCreated
+	}
+
+	stored := *s
+	r.store.shipments[s.ID] = &stored
+	return nil
+}
+
+func (r *ShipmentRepository) GetByID(ctx context.Context, shipmentID uuid.UUID) (*shipment.Shipment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s, ok := r.store.shipments[shipmentID]
+	if !ok {
+		return nil, shipment.ErrShipmentNotFound
+	}
+	found := *s
+	return &found, nil
+}
+
+// GetDetail returns the shipment with its rules and packages. Terms of
+// carriage are not stored in memory, so TermsAcceptance is always nil.
+func (r *ShipmentRepository) GetDetail(ctx context.Context, shipmentID uuid.UUID) (*shipment.ShipmentDetail, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s, ok := r.store.shipments[shipmentID]
+	if !ok {
+		return nil, shipment.ErrShipmentNotFound
+	}
+
+	found := *s
+	detail := &shipment.ShipmentDetail{
+		Shipment: &found,
+		Packages: r.packagesOf(shipmentID),
+	}
+	if rules, ok := r.store.rules[shipmentID]; ok {
+		foundRules := *rules
+		detail.Rules = &foundRules
+	}
+	return detail, nil
+}
+
+func (r *ShipmentRepository) Update(ctx context.Context, s *shipment.Shipment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.shipments[s.ID]
+	if !ok {
+		return shipment.ErrShipmentNotFound
+	}
+
+	s.UpdatedAt = time.Now()
+	setStatus(stored, s.Status, s.UpdatedAt)
+	stored.ShipperID = s.ShipperID
+	stored.LinkedDeviceID = s.LinkedDeviceID
+	stored.GoodsDescription = s.GoodsDescription
+	stored.GoodsCategory = s.GoodsCategory
+	stored.GoodsValue = s.GoodsValue
+	stored.GoodsCurrency = s.GoodsCurrency
+	stored.GoodsValueRate = s.GoodsValueRate
+	stored.GoodsWeight = s.GoodsWeight
+	stored.PickupAddress = s.PickupAddress
+	stored.DeliveryAddress = s.DeliveryAddress
+	stored.EstimatedPickupAt = s.EstimatedPickupAt
+	stored.EstimatedDeliveryAt = s.EstimatedDeliveryAt
+	stored.DeliveryDueAt = s.DeliveryDueAt
+	stored.ActualPickupAt = s.ActualPickupAt
+	stored.ActualDeliveryAt = s.ActualDeliveryAt
+	stored.CustomerNotes = s.CustomerNotes
+	stored.CompletionNotes = s.CompletionNotes
+	stored.CustomerRating = s.CustomerRating
+	stored.ProofOfDeliveryURL = s.ProofOfDeliveryURL
+	stored.UpdatedAt = s.UpdatedAt
+	return nil
+}
+
+func (r *ShipmentRepository) Delete(ctx context.Context, shipmentID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.shipments[shipmentID]; !ok {
+		return shipment.ErrShipmentNotFound
+	}
+	r.deleteShipment(shipmentID)
+	return nil
+}
+
+// deleteShipment removes a shipment with the rows that cascade with it. The
+// caller holds the store lock.
+func (r *ShipmentRepository) deleteShipment(shipmentID uuid.UUID) {
+	delete(r.store.shipments, shipmentID)
+	delete(r.store.rules, shipmentID)
+	delete(r.store.rulesVersions, shipmentID)
+	for id, pkg := range r.store.packages {
+		if pkg.ShipmentID == shipmentID {
+			delete(r.store.packages, id)
+		}
+	}
+}
+
+func (r *ShipmentRepository) UpdateStatus(ctx context.Context, shipmentID uuid.UUID, status shipment.ShipmentStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s, ok := r.store.shipments[shipmentID]
+	if !ok {
+		return shipment.ErrShipmentNotFound
+	}
+	now := time.Now()
+	setStatus(s, status, now)
+	s.UpdatedAt = now
+
+	if status.IsTerminal() {
+		r.releaseShipmentDevices(shipmentID, now)
+	}
+	return nil
+}
+
+// setStatus changes a shipment's status and, as the status trigger does,
+// restarts its status clock when the status actually changes
+func setStatus(s *shipment.Shipment, status shipment.ShipmentStatus, at time.Time) {
+	if s.Status != status {
+		s.StatusChangedAt = at
+	}
+	s.Status = status
+}
+
+// releaseShipmentDevices frees the devices a finished shipment held: those
+// still pointing at it, and its shipment and package trackers that point at
+// nothing or at another finished shipment. The caller holds the store lock.
+func (r *ShipmentRepository) releaseShipmentDevices(shipmentID uuid.UUID, at time.Time) {
+	trackers := make(map[uuid.UUID]bool)
+	if s, ok := r.store.shipments[shipmentID]; ok && s.LinkedDeviceID != nil {
+		trackers[*s.LinkedDeviceID] = true
+	}
+	for _, pkg := range r.store.packages {
+		if pkg.ShipmentID == shipmentID && pkg.DeviceID != nil {
+			trackers[*pkg.DeviceID] = true
+		}
+	}
+
+	for id, d := range r.store.devices {
+		held := d.CurrentShipmentID != nil && *d.CurrentShipmentID == shipmentID
+		if !held && trackers[id] {
+			held = d.CurrentShipmentID == nil || r.isFinished(*d.CurrentShipmentID)
+		}
+		if held {
+			releaseDevice(d, at)
+		}
+	}
+}
+
+// isFinished reports whether a stored shipment is in a terminal status. The
+// caller holds the store lock.
+func (r *ShipmentRepository) isFinished(shipmentID uuid.UUID) bool {
+	s, ok := r.store.shipments[shipmentID]
+	return ok && s.Status.IsTerminal()
+}
+
+// releaseDevice clears a device's shipment. Devices that were not in transit
+// keep their status.
+func releaseDevice(d *device.Device, at time.Time) {
+	d.CurrentShipmentID = nil
+	if d.Status == device.StatusInTransit {
+		d.Status = device.StatusAvailable
+	}
+	d.UpdatedAt = at
+}
+
+func (r *ShipmentRepository) List(ctx context.Context, filter *shipment.Filter) ([]*shipment.Shipment, int64, error) {
+	key, err := shipmentSortKey(filter.SortBy)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.store.mu.RLock()
+	matched := make([]*shipment.Shipment, 0, len(r.store.shipments))
+	for _, s := range r.store.shipments {
+		if r.matchesFilter(s, filter) {
+			found := *s
+			matched = append(matched, &found)
+		}
+	}
+	r.store.mu.RUnlock()
+
+	sortRows(matched, filter.SortOrder, key, func(s *shipment.Shipment) uuid.UUID { return s.ID })
+	return paginate(matched, filter.Page, filter.PageSize), int64(len(matched)), nil
+}
+
+// matchesFilter reports whether a shipment passes filter. The caller holds
+// the store lock.
+func (r *ShipmentRepository) matchesFilter(s *shipment.Shipment, filter *shipment.Filter) bool {
+	if filter.Status != nil && s.Status != *filter.Status {
+		return false
+	}
+	if filter.CustomerID != nil && s.CustomerID != *filter.CustomerID {
+		return false
+	}
+	if filter.ProviderID != nil && s.ProviderID != *filter.ProviderID {
+		return false
+	}
+	if filter.ShipperID != nil && !sameID(s.ShipperID, *filter.ShipperID) {
+		return false
+	}
+	if filter.DeviceID != nil && !sameID(s.LinkedDeviceID, *filter.DeviceID) && !r.hasPackageDevice(s.ID, *filter.DeviceID) {
+		return false
+	}
+	if filter.Sandbox != nil && s.IsSandbox != *filter.Sandbox {
+		return false
+	}
+	if filter.CreatedAfter != nil && s.CreatedAt.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && s.CreatedAt.After(*filter.CreatedBefore) {
+		return false
+	}
+	if filter.DeliveryAfter != nil && (s.EstimatedDeliveryAt == nil || s.EstimatedDeliveryAt.Before(*filter.DeliveryAfter)) {
+		return false
+	}
+	if filter.DeliveryBefore != nil && (s.EstimatedDeliveryAt == nil || s.EstimatedDeliveryAt.After(*filter.DeliveryBefore)) {
+		return false
+	}
+	if filter.HasIssues != nil && *filter.HasIssues && s.Status != shipment.StatusIssueReported {
+		return false
+	}
+	if filter.IsDelayed != nil && *filter.IsDelayed && !isDelayed(s, time.Now()) {
+		return false
+	}
+	if filter.HasDevice != nil && (s.LinkedDeviceID != nil) != *filter.HasDevice {
+		return false
+	}
+	if filter.Search != "" {
+		// Mirrors the search_text column: normalized description and addresses
+		searchText := utils.NormalizeSearch(s.GoodsDescription) + " " +
+			utils.NormalizeSearch(s.PickupAddress) + " " +
+			utils.NormalizeSearch(s.DeliveryAddress)
+		if !strings.Contains(searchText, filter.Search) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDelayed reports whether a shipment in transit is past its due time
+func isDelayed(s *shipment.Shipment, now time.Time) bool {
+	due := s.DeliveryDueAt
+	if due == nil {
+		due = s.EstimatedDeliveryAt
+	}
+	return s.Status == shipment.StatusInTransit && due != nil && due.Before(now)
+}
+
+// hasPackageDevice reports whether one of the shipment's packages carries the
+// device. The caller holds the store lock.
+func (r *ShipmentRepository) hasPackageDevice(shipmentID, deviceID uuid.UUID) bool {
+	for _, pkg := range r.store.packages {
+		if pkg.ShipmentID == shipmentID && sameID(pkg.DeviceID, deviceID) {
+			return true
+		}
+	}
+	return false
+}
+
+func shipmentSortKey(sortBy string) (func(*shipment.Shipment) sortValue, error) {
+	switch sortBy {
+	case "", "created_at":
+		return func(s *shipment.Shipment) sortValue { return timeValue(s.CreatedAt) }, nil
+	case "updated_at":
+		return func(s *shipment.Shipment) sortValue { return timeValue(s.UpdatedAt) }, nil
+	case "estimated_delivery_at":
+		return func(s *shipment.Shipment) sortValue { return optionalTimeValue(s.EstimatedDeliveryAt) }, nil
+	case "actual_delivery_at":
+		return func(s *shipment.Shipment) sortValue { return optionalTimeValue(s.ActualDeliveryAt) }, nil
+	case "goods_value":
+		return func(s *shipment.Shipment) sortValue { return optionalFloatValue(s.GoodsValue) }, nil
+	}
+	return nil, fmt.Errorf("failed to list shipments: unknown sort column %q", sortBy)
+}
+
+func (r *ShipmentRepository) GetStatistics(ctx context.Context) (*shipment.Statistics, error) {
+	return nil, ErrNotSupported
+}
+
+func (r *ShipmentRepository) SetRiskAssessment(ctx context.Context, shipmentID uuid.UUID, risk *shipment.RiskAssessment) error {
+	return r.update(shipmentID, func(s *shipment.Shipment) { s.Risk = risk })
+}
+
+func (r *ShipmentRepository) GetShipperRecord(ctx context.Context, shipperID uuid.UUID) (*shipment.ShipperRecord, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record := &shipment.ShipperRecord{ShipperID: shipperID}
+	var ratingSum int
+	for _, s := range r.store.shipments {
+		if !sameID(s.ShipperID, shipperID) {
+			continue
+		}
+		switch s.Status {
+		case shipment.StatusCompleted:
+			record.Completed++
+		case shipment.StatusPartiallyCompleted:
+			record.Partial++
+		case shipment.StatusCancelled:
+			record.Cancelled++
+		case shipment.StatusIssueReported:
+			record.Issues++
+		}
+		if s.CustomerRating != nil {
+			record.Rated++
+			ratingSum += *s.CustomerRating
+		}
+	}
+	record.Finished = record.Completed + record.Partial + record.Cancelled
+	if record.Rated > 0 {
+		record.AvgRating = float64(ratingSum) / float64(record.Rated)
+	}
+	return record, nil
+}
+
+func (r *ShipmentRepository) ListShipperCandidates(ctx context.Context, query *shipment.MatchQuery) ([]*shipment.ShipperCandidate, error) {
+	return nil, ErrNotSupported
+}
+
+func (r *ShipmentRepository) CompareShippers(ctx context.Context, query *shipment.ComparisonQuery) ([]*shipment.ShipperPerformance, error) {
+	return nil, ErrNotSupported
+}
+
+func (r *ShipmentRepository) GetLaneStatistics(ctx context.Context, query *shipment.LaneQuery) (*shipment.LaneStatistics, error) {
+	return nil, ErrNotSupported
+}
+
+func (r *ShipmentRepository) SetDeliveryDueAt(ctx context.Context, shipmentID uuid.UUID, dueAt *time.Time) error {
+	return r.update(shipmentID, func(s *shipment.Shipment) { s.DeliveryDueAt = dueAt })
+}
+
+func (r *ShipmentRepository) SetActualPickup(ctx context.Context, shipmentID uuid.UUID, pickupTime time.Time) error {
+	return r.update(shipmentID, func(s *shipment.Shipment) { s.ActualPickupAt = &pickupTime })
+}
+
+func (r *ShipmentRepository) SetActualDelivery(ctx context.Context, shipmentID uuid.UUID, deliveryTime time.Time, notes *string) error {
+	return r.update(shipmentID, func(s *shipment.Shipment) {
+		s.ActualDeliveryAt = &deliveryTime
+		if notes != nil {
+			completionNotes := *notes
+			s.CompletionNotes = &completionNotes
+		}
+	})
+}
+
+func (r *ShipmentRepository) SetCustomerRating(ctx context.Context, shipmentID uuid.UUID, rating int, feedback *string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s, ok := r.store.shipments[shipmentID]
+	if !ok || (s.Status != shipment.StatusCompleted && s.Status != shipment.StatusPartiallyCompleted) {
+		return appErrors.NewAppError("RATING_FAILED", "Shipment not completed or not found", nil)
+	}
+
+	s.CustomerRating = &rating
+	if feedback != nil {
+		var notes string
+		if s.CompletionNotes != nil {
+			notes = *s.CompletionNotes
+		}
+		notes += "\nCustomer Feedback: " + *feedback
+		s.CompletionNotes = &notes
+	}
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *ShipmentRepository) GetMarketplaceListings(ctx context.Context, sandbox bool, page, pageSize int) ([]*shipment.Shipment, int64, error) {
+	status := shipment.StatusOrderPosted
+	filter := &shipment.Filter{
+		Status:    &status,
+		Sandbox:   &sandbox,
+		Page:      page,
+		PageSize:  pageSize,
+		SortBy:    "created_at",
+		SortOrder: "desc",
+	}
+
+	return r.List(ctx, filter)
+}
+
+func (r *ShipmentRepository) AssignShipper(ctx context.Context, shipmentID, shipperID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s, ok := r.store.shipments[shipmentID]
+	if !ok || s.ShipperID != nil {
+		return appErrors.NewAppError("ASSIGNMENT_FAILED", "Shipment already has a shipper or not found", nil)
+	}
+	s.ShipperID = &shipperID
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *ShipmentRepository) AssignDevice(ctx context.Context, shipmentID, deviceID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s, ok := r.store.shipments[shipmentID]
+	if !ok || s.LinkedDeviceID != nil {
+		return appErrors.NewAppError("ASSIGNMENT_FAILED", "Shipment already has a device or not found", nil)
+	}
+
+	now := time.Now()
+	s.LinkedDeviceID = &deviceID
+	s.UpdatedAt = now
+	if d, ok := r.store.devices[deviceID]; ok && d.CurrentShipmentID == nil {
+		d.CurrentShipmentID = &shipmentID
+		d.Status = device.StatusInTransit
+		d.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r *ShipmentRepository) CreateRules(ctx context.Context, rules *shipment.ShippingRules) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.shipments[rules.ShipmentID]; !ok {
+		return shipment.ErrShipmentNotFound
+	}
+	if _, ok := r.store.rules[rules.ShipmentID]; ok {
+		return fmt.Errorf("rules already exist for this shipment")
+	}
+
+	rules.ID = uuid.New()
+	rules.SetAt = time.Now()
+
+	stored := *rules
+	r.store.rules[rules.ShipmentID] = &stored
+	r.addRulesVersion(rules, 1, rules.SetByProviderID, rules.SetAt)
+	return nil
+}
+
+func (r *ShipmentRepository) ConfirmRules(ctx context.Context, shipmentID, shipperID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rules, ok := r.store.rules[shipmentID]
+	if !ok || rules.ConfirmedByShipperID != nil {
+		return fmt.Errorf("shipping rules not found")
+	}
+	now := time.Now()
+	rules.ConfirmedByShipperID = &shipperID
+	rules.ConfirmedAt = &now
+	return nil
+}
+
+func (r *ShipmentRepository) UpdateRules(ctx context.Context, rules *shipment.ShippingRules, editedBy uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var current *shipment.ShippingRules
+	for _, stored := range r.store.rules {
+		if stored.ID == rules.ID {
+			current = stored
+			break
+		}
+	}
+	if current == nil {
+		return fmt.Errorf("shipping rules not found")
+	}
+
+	latest := len(r.store.rulesVersions[current.ShipmentID])
+	// Rules set before versioning become the first version now
+	if latest == 0 {
+		latest = 1
+		r.addRulesVersion(current, latest, current.SetByProviderID, current.SetAt)
+	}
+
+	now := time.Now()
+	current.ReportCycleSec = rules.ReportCycleSec
+	current.TempMin = rules.TempMin
+	current.TempMax = rules.TempMax
+	current.HumidityMin = rules.HumidityMin
+	current.HumidityMax = rules.HumidityMax
+	current.LightMax = rules.LightMax
+	current.TiltMaxAngle = rules.TiltMaxAngle
+	current.ImpactThresholdG = rules.ImpactThresholdG
+	current.EnablePredictiveAlert = rules.EnablePredictiveAlert
+	current.AlertBufferTimeMin = rules.AlertBufferTimeMin
+	current.SetAt = now
+	rules.SetAt = now
+
+	r.addRulesVersion(rules, latest+1, editedBy, now)
+	return nil
+}
+
+func (r *ShipmentRepository) ListRulesVersions(ctx context.Context, shipmentID uuid.UUID) ([]*shipment.RulesVersion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.rulesVersions[shipmentID]
+	versions := make([]*shipment.RulesVersion, len(stored))
+	for i, version := range stored {
+		found := *version
+		versions[i] = &found
+	}
+	return versions, nil
+}
+
+// addRulesVersion records rules as the given version and makes it the one
+// the shipment is held to. The caller holds the store lock.
+func (r *ShipmentRepository) addRulesVersion(rules *shipment.ShippingRules, version int, setBy uuid.UUID, setAt time.Time) {
+	versionRules := *rules
+	versionRules.ConfirmedByShipperID = nil
+	versionRules.ConfirmedAt = nil
+
+	rulesVersion := &shipment.RulesVersion{
+		ID:         uuid.New(),
+		ShipmentID: rules.ShipmentID,
+		Version:    version,
+		Rules:      versionRules,
+		SetBy:      setBy,
+		SetAt:      setAt,
+	}
+	r.store.rulesVersions[rules.ShipmentID] = append(r.store.rulesVersions[rules.ShipmentID], rulesVersion)
+
+	if s, ok := r.store.shipments[rules.ShipmentID]; ok {
+		s.RulesVersionID = &rulesVersion.ID
+	}
+}
+
+func (r *ShipmentRepository) GetRulesByShipmentID(ctx context.Context, shipmentID uuid.UUID) (*shipment.ShippingRules, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rules, ok := r.store.rules[shipmentID]
+	if !ok {
+		return nil, nil // Rules are optional
+	}
+	found := *rules
+	return &found, nil
+}
+
+func (r *ShipmentRepository) CreatePackage(ctx context.Context, pkg *shipment.Package) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.shipments[pkg.ShipmentID]; !ok {
+		return shipment.ErrShipmentNotFound
+	}
+
+	var maxSequence int
+	for _, existing := range r.store.packages {
+		if existing.ShipmentID == pkg.ShipmentID {
+			maxSequence = max(maxSequence, existing.Sequence)
+		}
+	}
+
+	now := time.Now()
+	if pkg.ID == uuid.Nil {
+		pkg.ID = uuid.New()
+	}
+	pkg.Sequence = maxSequence + 1
+	if pkg.Outcome == "" {
+		pkg.Outcome = shipment.OutcomePending
+	}
+	pkg.CreatedAt = now
+	pkg.UpdatedAt = now
+
+	stored := *pkg
+	r.store.packages[pkg.ID] = &stored
+	return nil
+}
+
+func (r *ShipmentRepository) GetPackageByID(ctx context.Context, packageID uuid.UUID) (*shipment.Package, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	pkg, ok := r.store.packages[packageID]
+	if !ok {
+		return nil, shipment.ErrPackageNotFound
+	}
+	found := *pkg
+	return &found, nil
+}
+
+func (r *ShipmentRepository) ListPackages(ctx context.Context, shipmentID uuid.UUID) ([]*shipment.Package, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.packagesOf(shipmentID), nil
+}
+
+// packagesOf returns copies of a shipment's packages in label order. The
+// caller holds the store lock.
+func (r *ShipmentRepository) packagesOf(shipmentID uuid.UUID) []*shipment.Package {
+	packages := make([]*shipment.Package, 0)
+	for _, pkg := range r.store.packages {
+		if pkg.ShipmentID == shipmentID {
+			found := *pkg
+			packages = append(packages, &found)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Sequence < packages[j].Sequence
+	})
+	return packages
+}
+
+func (r *ShipmentRepository) UpdatePackage(ctx context.Context, pkg *shipment.Package) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.packages[pkg.ID]
+	if !ok {
+		return shipment.ErrPackageNotFound
+	}
+	pkg.UpdatedAt = time.Now()
+	stored.Description = pkg.Description
+	stored.Weight = pkg.Weight
+	stored.UpdatedAt = pkg.UpdatedAt
+	return nil
+}
+
+func (r *ShipmentRepository) DeletePackage(ctx context.Context, packageID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pkg, ok := r.store.packages[packageID]
+	if !ok || pkg.DeviceID != nil {
+		return appErrors.NewAppError("DELETE_FAILED", "Package not found or already has a device", nil)
+	}
+	delete(r.store.packages, packageID)
+	return nil
+}
+
+func (r *ShipmentRepository) AssignPackageDevice(ctx context.Context, packageID, deviceID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pkg, ok := r.store.packages[packageID]
+	if !ok {
+		return shipment.ErrPackageNotFound
+	}
+	if pkg.DeviceID != nil {
+		return appErrors.NewAppError("ASSIGNMENT_FAILED", "Package already has a device", nil)
+	}
+	d, ok := r.store.devices[deviceID]
+	if !ok || d.CurrentShipmentID != nil {
+		return shipment.ErrDeviceUnavailable
+	}
+
+	now := time.Now()
+	pkg.DeviceID = &deviceID
+	pkg.UpdatedAt = now
+	shipmentID := pkg.ShipmentID
+	d.CurrentShipmentID = &shipmentID
+	d.Status = device.StatusInTransit
+	d.UpdatedAt = now
+	return nil
+}
+
+func (r *ShipmentRepository) RecordPackageOutcome(ctx context.Context, pkg *shipment.Package) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.packages[pkg.ID]
+	if !ok {
+		return shipment.ErrPackageNotFound
+	}
+	pkg.UpdatedAt = time.Now()
+	stored.Outcome = pkg.Outcome
+	stored.OutcomeNote = pkg.OutcomeNote
+	stored.OutcomeRecordedBy = pkg.OutcomeRecordedBy
+	stored.OutcomeAt = pkg.OutcomeAt
+	stored.UpdatedAt = pkg.UpdatedAt
+	return nil
+}
+
+func (r *ShipmentRepository) ConfirmPendingPackages(ctx context.Context, shipmentID, recordedBy uuid.UUID, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	for _, pkg := range r.store.packages {
+		if pkg.ShipmentID != shipmentID || pkg.Outcome != shipment.OutcomePending {
+			continue
+		}
+		outcomeAt := at
+		recorder := recordedBy
+		pkg.Outcome = shipment.OutcomeDelivered
+		pkg.OutcomeRecordedBy = &recorder
+		pkg.OutcomeAt = &outcomeAt
+		pkg.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r *ShipmentRepository) ResolveDevice(ctx context.Context, deviceID uuid.UUID) (*shipment.DeviceAssignment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// A package tracker is more specific than the shipment-level one
+	var pkg *shipment.Package
+	var pkgShipment *shipment.Shipment
+	for _, p := range r.store.packages {
+		s, ok := r.store.shipments[p.ShipmentID]
+		if !ok || !sameID(p.DeviceID, deviceID) || !slices.Contains(activeShipmentStatuses, s.Status) {
+			continue
+		}
+		if pkgShipment == nil || s.CreatedAt.After(pkgShipment.CreatedAt) {
+			pkg, pkgShipment = p, s
+		}
+	}
+	if pkg != nil {
+		packageID := pkg.ID
+		return &shipment.DeviceAssignment{
+			DeviceID:   deviceID,
+			ShipmentID: pkgShipment.ID,
+			PackageID:  &packageID,
+			Status:     pkgShipment.Status,
+		}, nil
+	}
+
+	var linked *shipment.Shipment
+	for _, s := range r.store.shipments {
+		if !sameID(s.LinkedDeviceID, deviceID) || !slices.Contains(activeShipmentStatuses, s.Status) {
+			continue
+		}
+		if linked == nil || s.CreatedAt.After(linked.CreatedAt) {
+			linked = s
+		}
+	}
+	if linked == nil {
+		return nil, shipment.ErrDeviceNotAssigned
+	}
+
+	return &shipment.DeviceAssignment{
+		DeviceID:   deviceID,
+		ShipmentID: linked.ID,
+		Status:     linked.Status,
+	}, nil
+}
+
+func (r *ShipmentRepository) DeleteSandboxData(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for id, s := range r.store.shipments {
+		if s.IsSandbox && (s.CustomerID == userID || s.ProviderID == userID || sameID(s.ShipperID, userID)) {
+			r.deleteShipment(id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *ShipmentRepository) ListStuck(ctx context.Context, query *shipment.StuckQuery) ([]*shipment.Shipment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stuck := make([]*shipment.Shipment, 0)
+	for _, s := range r.store.shipments {
+		if s.Status != query.Status || !s.StatusChangedAt.Before(query.ChangedBefore) {
+			continue
+		}
+		if _, handled := r.store.watchdogStages[watchdogStageKey{s.ID, s.StatusChangedAt, query.Stage}]; handled {
+			continue
+		}
+		if query.Previous != "" {
+			recordedAt, ok := r.store.watchdogStages[watchdogStageKey{s.ID, s.StatusChangedAt, query.Previous}]
+			if !ok || !recordedAt.Before(query.PreviousBefore) {
+				continue
+			}
+		}
+		found := *s
+		stuck = append(stuck, &found)
+	}
+
+	sort.Slice(stuck, func(i, j int) bool {
+		return stuck[i].StatusChangedAt.Before(stuck[j].StatusChangedAt)
+	})
+	if query.Limit > 0 && len(stuck) > query.Limit {
+		stuck = stuck[:query.Limit]
+	}
+	return stuck, nil
+}
+
+func (r *ShipmentRepository) RecordWatchdogStage(ctx context.Context, s *shipment.Shipment, stage shipment.WatchdogStage) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := watchdogStageKey{s.ID, s.StatusChangedAt, stage}
+	if _, ok := r.store.watchdogStages[key]; ok {
+		return false, nil
+	}
+	r.store.watchdogStages[key] = time.Now()
+	return true, nil
+}
+
+func (r *ShipmentRepository) RevertAssignment(ctx context.Context, shipmentID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s, ok := r.store.shipments[shipmentID]
+	if !ok || s.Status != shipment.StatusShippingAssigned {
+		return shipment.ErrShipmentNotFound
+	}
+
+	now := time.Now()
+	if s.LinkedDeviceID != nil {
+		if d, ok := r.store.devices[*s.LinkedDeviceID]; ok && sameID(d.CurrentShipmentID, shipmentID) {
+			d.CurrentShipmentID = nil
+			d.Status = device.StatusAvailable
+			d.UpdatedAt = now
+		}
+	}
+	setStatus(s, shipment.StatusOrderPosted, now)
+	s.ShipperID = nil
+	s.LinkedDeviceID = nil
+	s.UpdatedAt = now
+
+	// The next shipper confirms the rules again
+	if rules, ok := r.store.rules[shipmentID]; ok {
+		rules.ConfirmedByShipperID = nil
+		rules.ConfirmedAt = nil
+	}
+	return nil
+}
+
+func (r *ShipmentRepository) ReleaseStaleDevices(ctx context.Context, limit int) ([]*shipment.StaleDevice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// A device is stale when it points at a finished or deleted shipment, or
+	// is in transit for no active shipment or package
+	stale := make([]*device.Device, 0)
+	for _, d := range r.store.devices {
+		if d.CurrentShipmentID != nil {
+			s, ok := r.store.shipments[*d.CurrentShipmentID]
+			if !ok || s.Status.IsTerminal() {
+				stale = append(stale, d)
+			}
+		} else if d.Status == device.StatusInTransit && !r.isTracking(d.ID) {
+			stale = append(stale, d)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].UpdatedAt.Before(stale[j].UpdatedAt)
+	})
+	if len(stale) > limit {
+		stale = stale[:limit]
+	}
+
+	now := time.Now()
+	released := make([]*shipment.StaleDevice, len(stale))
+	for i, d := range stale {
+		released[i] = &shipment.StaleDevice{
+			DeviceID:   d.ID,
+			ShipmentID: d.CurrentShipmentID,
+		}
+		if d.CurrentShipmentID != nil {
+			if s, ok := r.store.shipments[*d.CurrentShipmentID]; ok {
+				status := s.Status
+				released[i].ShipmentStatus = &status
+			}
+		}
+		releaseDevice(d, now)
+		released[i].DeviceStatus = string(d.Status)
+	}
+	return released, nil
+}
+
+// isTracking reports whether an unfinished shipment or one of its packages
+// holds the device. The caller holds the store lock.
+func (r *ShipmentRepository) isTracking(deviceID uuid.UUID) bool {
+	for _, s := range r.store.shipments {
+		if !s.Status.IsTerminal() && sameID(s.LinkedDeviceID, deviceID) {
+			return true
+		}
+	}
+	for _, pkg := range r.store.packages {
+		s, ok := r.store.shipments[pkg.ShipmentID]
+		if ok && sameID(pkg.DeviceID, deviceID) && !s.Status.IsTerminal() {
+			return true
+		}
+	}
+	return false
+}
+
+// update applies change to a stored shipment and bumps its updated_at
+func (r *ShipmentRepository) update(shipmentID uuid.UUID, change func(*shipment.Shipment)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s, ok := r.store.shipments[shipmentID]
+	if !ok {
+		return shipment.ErrShipmentNotFound
+	}
+	change(s)
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func sameID(id *uuid.UUID, want uuid.UUID) bool {
+	return id != nil && *id == want
+}
@@ -0,0 +1,160 @@
+// Package memory implements the user, device and shipment repositories on
+// maps held in memory. They follow the filtering, sorting, pagination and
+// error semantics of the Postgres repositories so unit tests and demos can
+// run without a database. Aggregate reports that only make sense on SQL
+// (statistics across shipments, shipper comparisons, lane statistics) return
+// ErrNotSupported.
+package memory
+
+import (
+	"cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/domain/user"
+	"cmp"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotSupported is returned by the queries the in-memory repositories do not implement
+var ErrNotSupported = errors.New("not supported by the in-memory repository")
+
+// Store holds the rows of the in-memory repositories. Repositories built on
+// the same store see each other's writes, as tables of one database would,
+// so assigning a device to a shipment updates the device too.
+type Store struct {
+	mu sync.RWMutex
+
+	users         map[uuid.UUID]*user.User
+	resetTokens   map[uuid.UUID]*user.PasswordResetToken
+	refreshTokens map[uuid.UUID]*user.RefreshToken
+	otps          map[uuid.UUID]*user.PhoneOTP
+
+	devices           map[uuid.UUID]*device.Device
+	deviceCredentials map[uuid.UUID]string
+
+	shipments      map[uuid.UUID]*shipment.Shipment
+	rules          map[uuid.UUID]*shipment.ShippingRules // By shipment
+	rulesVersions  map[uuid.UUID][]*shipment.RulesVersion
+	packages       map[uuid.UUID]*shipment.Package
+	watchdogStages map[watchdogStageKey]time.Time // When the stage was recorded
+}
+
+type watchdogStageKey struct {
+	shipmentID      uuid.UUID
+	statusChangedAt time.Time
+	stage           shipment.WatchdogStage
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{
+		users:             make(map[uuid.UUID]*user.User),
+		resetTokens:       make(map[uuid.UUID]*user.PasswordResetToken),
+		refreshTokens:     make(map[uuid.UUID]*user.RefreshToken),
+		otps:              make(map[uuid.UUID]*user.PhoneOTP),
+		devices:           make(map[uuid.UUID]*device.Device),
+		deviceCredentials: make(map[uuid.UUID]string),
+		shipments:         make(map[uuid.UUID]*shipment.Shipment),
+		rules:             make(map[uuid.UUID]*shipment.ShippingRules),
+		rulesVersions:     make(map[uuid.UUID][]*shipment.RulesVersion),
+		packages:          make(map[uuid.UUID]*shipment.Package),
+		watchdogStages:    make(map[watchdogStageKey]time.Time),
+	}
+}
+
+// paginate returns the page of items, with the Postgres repositories'
+// defaults of page 1 and 20 items per page
+func paginate[T any](items []T, page, pageSize int) []T {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+	if offset >= len(items) {
+		return []T{}
+	}
+	return items[offset:min(offset+pageSize, len(items))]
+}
+
+// sortRows orders rows by the value key returns, descending unless sortOrder
+// is asc as in the Postgres listings. Ties are broken by id.
+func sortRows[T any](rows []T, sortOrder string, key func(T) sortValue, id func(T) uuid.UUID) {
+	desc := strings.ToLower(sortOrder) != "asc"
+	sort.SliceStable(rows, func(i, j int) bool {
+		c := key(rows[i]).compare(key(rows[j]))
+		if c == 0 {
+			return id(rows[i]).String() < id(rows[j]).String()
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// sortValue is a column value as Postgres orders it: NULL sorts after every
+// other value
+type sortValue struct {
+	null   bool
+	time   time.Time
+	number float64
+	text   string
+}
+
+func (v sortValue) compare(other sortValue) int {
+	switch {
+	case v.null && other.null:
+		return 0
+	case v.null:
+		return 1
+	case other.null:
+		return -1
+	}
+	if c := v.time.Compare(other.time); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.number, other.number); c != 0 {
+		return c
+	}
+	return strings.Compare(v.text, other.text)
+}
+
+func timeValue(t time.Time) sortValue {
+	return sortValue{time: t}
+}
+
+func optionalTimeValue(t *time.Time) sortValue {
+	if t == nil {
+		return sortValue{null: true}
+	}
+	return sortValue{time: *t}
+}
+
+func numberValue(n float64) sortValue {
+	return sortValue{number: n}
+}
+
+func optionalIntValue(n *int) sortValue {
+	if n == nil {
+		return sortValue{null: true}
+	}
+	return sortValue{number: float64(*n)}
+}
+
+func optionalFloatValue(n *float64) sortValue {
+	if n == nil {
+		return sortValue{null: true}
+	}
+	return sortValue{number: *n}
+}
+
+func textValue(s string) sortValue {
+	return sortValue{text: s}
+}
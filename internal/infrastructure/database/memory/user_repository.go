@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"cargo-tracker/internal/domain/user"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserRepository implements domain.User.Repository interface
+type UserRepository struct {
+	store *Store
+}
+
+// NewUserRepository creates a new in-memory user repository
+func NewUserRepository(store *Store) user.Repository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.Email == u.Email || existing.Username == u.Username {
+			return user.ErrUserAlreadyExists
+		}
+		if samePhone(existing.PhoneNumber, u.PhoneNumber) {
+			return user.ErrPhoneAlreadyExists
+		}
+	}
+
+	u.ID = uuid.New()
+	u.CreatedAt = time.Now()
+	u.UpdatedAt = u.CreatedAt
+	u.IsActive = true
+
+	stored := *u
+	r.store.users[u.ID] = &stored
+	return nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, u := range r.store.users {
+		if u.Email == email {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (r *UserRepository) GetByPhone(ctx context.Context, phoneNumber string) (*user.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, u := range r.store.users {
+		if samePhone(u.PhoneNumber, &phoneNumber) {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, userID uuid.UUID) (*user.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	u, ok := r.store.users[userID]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	found := *u
+	return &found, nil
+}
+
+func (r *UserRepository) List(ctx context.Context, filter *user.Filter) ([]*user.User, int64, error) {
+	key, err := userSortKey(filter.SortBy)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.store.mu.RLock()
+	matched := r.filter(filter)
+	r.store.mu.RUnlock()
+
+	sortRows(matched, filter.SortOrder, key, func(u *user.User) uuid.UUID { return u.ID })
+	return paginate(matched, filter.Page, filter.PageSize), int64(len(matched)), nil
+}
+
+func (r *UserRepository) Count(ctx context.Context, filter *user.Filter) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return int64(len(r.filter(filter))), nil
+}
+
+// filter returns copies of the users matching filter. The caller holds the
+// store lock.
+func (r *UserRepository) filter(filter *user.Filter) []*user.User {
+	matched := make([]*user.User, 0, len(r.store.users))
+	for _, u := range r.store.users {
+		if filter.Role != "" && u.Role != filter.Role {
+			continue
+		}
+		if filter.IsActive != nil && u.IsActive != *filter.IsActive {
+			continue
+		}
+		if filter.RegisteredAfter != nil && u.CreatedAt.Before(*filter.RegisteredAfter) {
+			continue
+		}
+		if filter.RegisteredBefore != nil && u.CreatedAt.After(*filter.RegisteredBefore) {
+			continue
+		}
+		if filter.Search != "" {
+			// Mirrors the search_text column: normalized name and email
+			searchText := utils.NormalizeSearch(u.FullName) + " " + utils.NormalizeSearch(u.Email)
+			if !strings.Contains(searchText, filter.Search) {
+				continue
+			}
+		}
+		found := *u
+		matched = append(matched, &found)
+	}
+	return matched
+}
+
+func userSortKey(sortBy string) (func(*user.User) sortValue, error) {
+	switch sortBy {
+	case "", "created_at":
+		return func(u *user.User) sortValue { return timeValue(u.CreatedAt) }, nil
+	case "full_name":
+		return func(u *user.User) sortValue { return textValue(u.FullName) }, nil
+	case "email":
+		return func(u *user.User) sortValue { return textValue(u.Email) }, nil
+	case "role":
+		return func(u *user.User) sortValue { return textValue(u.Role) }, nil
+	}
+	return nil, fmt.Errorf("failed to list users: unknown sort column %q", sortBy)
+}
+
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.users[u.ID]
+	if !ok {
+		return user.ErrUserNotFound
+	}
+	for id, existing := range r.store.users {
+		if id != u.ID && samePhone(existing.PhoneNumber, u.PhoneNumber) {
+			return user.ErrPhoneAlreadyExists
+		}
+	}
+
+	u.UpdatedAt = time.Now()
+	stored.FullName = u.FullName
+	stored.PhoneNumber = u.PhoneNumber
+	stored.PhoneVerified = u.PhoneVerified
+	stored.Address = u.Address
+	stored.DefaultCurrency = u.DefaultCurrency
+	stored.UpdatedAt = u.UpdatedAt
+	return nil
+}
+
+func (r *UserRepository) SetPhoneVerified(ctx context.Context, userID uuid.UUID, at *time.Time) error {
+	return r.update(userID, func(u *user.User) { u.PhoneVerified = at })
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	return r.update(userID, func(u *user.User) { u.PasswordHashed = passwordHash })
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role string) error {
+	return r.update(userID, func(u *user.User) { u.Role = role })
+}
+
+func (r *UserRepository) UpdatePlan(ctx context.Context, userID uuid.UUID, plan string) error {
+	return r.update(userID, func(u *user.User) { u.Plan = plan })
+}
+
+// update applies change to a stored user and bumps its updated_at
+func (r *UserRepository) update(userID uuid.UUID, change func(*user.User)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[userID]
+	if !ok {
+		return user.ErrUserNotFound
+	}
+	change(u)
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return user.ErrUserNotFound
+	}
+	delete(r.store.users, userID)
+	return nil
+}
+
+func (r *UserRepository) CreatePasswordResetToken(ctx context.Context, token *user.PasswordResetToken) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	token.Used = false
+
+	stored := *token
+	r.store.resetTokens[token.ID] = &stored
+	return nil
+}
+
+func (r *UserRepository) GetPasswordResetToken(ctx context.Context, token string) (*user.PasswordResetToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	now := time.Now()
+	for _, t := range r.store.resetTokens {
+		if t.Token == token && !t.Used && t.ExpiresAt.After(now) {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, user.ErrTokenInvalid
+}
+
+func (r *UserRepository) MarkTokenAsUsed(ctx context.Context, tokenID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if t, ok := r.store.resetTokens[tokenID]; ok {
+		t.Used = true
+	}
+	return nil
+}
+
+// samePhone reports whether both numbers are set and equal, as the unique
+// index on phone numbers compares them
+func samePhone(a, b *string) bool {
+	return a != nil && b != nil && *a == *b
+}
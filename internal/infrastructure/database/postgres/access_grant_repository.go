@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccessGrantRepository implements domain.Shipment.AccessGrantRepository interface
+type AccessGrantRepository struct {
+	db *DB
+}
+
+// NewAccessGrantRepository creates a new shipment access grant repository
+func NewAccessGrantRepository(db *DB) shipment.AccessGrantRepository {
+	return &AccessGrantRepository{db: db}
+}
+
+func (r *AccessGrantRepository) Create(ctx context.Context, grant *shipment.AccessGrant) error {
+	if grant.ID == uuid.Nil {
+		grant.ID = uuid.New()
+	}
+	grant.CreatedAt = time.Now()
+
+	dbModel := toAccessGrantModel(grant)
+	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to create access grant: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AccessGrantRepository) GetByID(ctx context.Context, grantID uuid.UUID) (*shipment.AccessGrant, error) {
+	var dbModel models.ShipmentAccessGrantModel
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ?", grantID).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrAccessGrantNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access grant: %w", err)
+	}
+
+	return toAccessGrantEntity(&dbModel), nil
+}
+
+func (r *AccessGrantRepository) ListByShipment(ctx context.Context, shipmentID uuid.UUID) ([]*shipment.AccessGrant, error) {
+	var dbModels []models.ShipmentAccessGrantModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Order("created_at DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access grants: %w", err)
+	}
+
+	return toAccessGrantEntities(dbModels), nil
+}
+
+func (r *AccessGrantRepository) ListActiveForGrantee(ctx context.Context, granteeID uuid.UUID, at time.Time) ([]*shipment.AccessGrant, error) {
+	var dbModels []models.ShipmentAccessGrantModel
+	err := r.db.DB.WithContext(ctx).
+		Where("grantee_id = ? AND revoked_at IS NULL AND expires_at > ?", granteeID, at).
+		Order("expires_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access grants: %w", err)
+	}
+
+	return toAccessGrantEntities(dbModels), nil
+}
+
+func (r *AccessGrantRepository) FindActive(ctx context.Context, shipmentID, granteeID uuid.UUID, at time.Time) ([]*shipment.AccessGrant, error) {
+	var dbModels []models.ShipmentAccessGrantModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ? AND grantee_id = ? AND revoked_at IS NULL AND expires_at > ?", shipmentID, granteeID, at).
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find access grants: %w", err)
+	}
+
+	return toAccessGrantEntities(dbModels), nil
+}
+
+func (r *AccessGrantRepository) Revoke(ctx context.Context, grantID uuid.UUID, at time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.ShipmentAccessGrantModel{}).
+		Where("id = ? AND revoked_at IS NULL", grantID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke access grant: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrAccessGrantNotFound
+	}
+
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toAccessGrantModel(g *shipment.AccessGrant) *models.ShipmentAccessGrantModel {
+	scopes := make([]string, len(g.Scopes))
+	for i, s := range g.Scopes {
+		scopes[i] = string(s)
+	}
+
+	return &models.ShipmentAccessGrantModel{
+		ID:         g.ID,
+		ShipmentID: g.ShipmentID,
+		GrantedBy:  g.GrantedBy,
+		GranteeID:  g.GranteeID,
+		Scopes:     scopes,
+		Note:       g.Note,
+		ExpiresAt:  g.ExpiresAt,
+		RevokedAt:  g.RevokedAt,
+		CreatedAt:  g.CreatedAt,
+	}
+}
+
+func toAccessGrantEntity(m *models.ShipmentAccessGrantModel) *shipment.AccessGrant {
+	scopes := make([]shipment.AccessScope, len(m.Scopes))
+	for i, s := range m.Scopes {
+		scopes[i] = shipment.AccessScope(s)
+	}
+
+	return &shipment.AccessGrant{
+		ID:         m.ID,
+		ShipmentID: m.ShipmentID,
+		GrantedBy:  m.GrantedBy,
+		GranteeID:  m.GranteeID,
+		Scopes:     scopes,
+		Note:       m.Note,
+		ExpiresAt:  m.ExpiresAt,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func toAccessGrantEntities(dbModels []models.ShipmentAccessGrantModel) []*shipment.AccessGrant {
+	grants := make([]*shipment.AccessGrant, len(dbModels))
+	for i := range dbModels {
+		grants[i] = toAccessGrantEntity(&dbModels[i])
+	}
+	return grants
+}
@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// mergeTarget is a column referencing a user that a merge moves to the
+// primary account. Rows whose unique key would collide with a row of the
+// primary account are left behind; singleton tables hold one row per user
+// and only move when the primary account has none.
+type mergeTarget struct {
+	table     string
+	column    string
+	uniqueKey []string
+	singleton bool
+}
+
+// Columns recording who performed an action (document uploads and reviews,
+// terms signatures, package outcomes, decommissions, template edits) are
+// audit history and keep pointing at the account that acted.
+var mergeTargets = []mergeTarget{
+	{table: "shipments", column: "customer_id"},
+	{table: "shipments", column: "provider_id"},
+	{table: "shipments", column: "shipper_id"},
+	{table: "devices", column: "owner_shipper_id"},
+	{table: "trips", column: "shipper_id"},
+	{table: "quote_requests", column: "customer_id"},
+	{table: "quotes", column: "provider_id", uniqueKey: []string{"request_id"}},
+	{table: "invoices", column: "provider_id", uniqueKey: []string{"customer_id", "currency", "period_start"}},
+	{table: "invoices", column: "customer_id", uniqueKey: []string{"provider_id", "currency", "period_start"}},
+	{table: "saved_addresses", column: "owner_id", uniqueKey: []string{"label"}},
+	{table: "shipment_access_grants", column: "granted_by"},
+	{table: "shipment_access_grants", column: "grantee_id"},
+	{table: "carriage_terms", column: "provider_id", uniqueKey: []string{"version"}},
+	{table: "business_calendars", column: "provider_id", singleton: true},
+	{table: "provider_branding", column: "provider_id", singleton: true},
+	{table: "interop_partner_mappings", column: "owner_id", uniqueKey: []string{"name"}},
+	{table: "interop_partner_mappings", column: "default_provider_id"},
+	{table: "notification_webhooks", column: "owner_id"},
+	{table: "chat_links", column: "user_id", uniqueKey: []string{"provider"}},
+	{table: "chat_deliveries", column: "user_id"},
+	{table: "push_tokens", column: "user_id"},
+	{table: "push_subscriptions", column: "user_id", uniqueKey: []string{"topic"}},
+}
+
+// conflict returns the condition matching rows of the duplicate that collide
+// with rows of the primary account
+func (t mergeTarget) conflict() string {
+	if t.singleton {
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM %[1]s p WHERE p.%[2]s = @primary)", t.table, t.column)
+	}
+	if len(t.uniqueKey) == 0 {
+		return "FALSE"
+	}
+	conds := make([]string, len(t.uniqueKey))
+	for i, key := range t.uniqueKey {
+		conds[i] = fmt.Sprintf("p.%[1]s = %[2]s.%[1]s", key, t.table)
+	}
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s p WHERE p.%s = @primary AND %s)",
+		t.table, t.column, strings.Join(conds, " AND "))
+}
+
+// AccountMergeRepository implements domain.User.MergeRepository interface
+type AccountMergeRepository struct {
+	db *DB
+}
+
+// NewAccountMergeRepository creates a new account merge repository
+func NewAccountMergeRepository(db *DB) user.MergeRepository {
+	return &AccountMergeRepository{db: db}
+}
+
+func (r *AccountMergeRepository) Preview(ctx context.Context, primaryID, duplicateID uuid.UUID) ([]user.MergeCount, error) {
+	args := map[string]interface{}{"primary": primaryID, "duplicate": duplicateID}
+
+	counts := make([]user.MergeCount, 0, len(mergeTargets))
+	for _, t := range mergeTargets {
+		var row struct {
+			Moved   int64
+			Skipped int64
+		}
+		query := fmt.Sprintf(`SELECT COUNT(*) FILTER (WHERE NOT (%[3]s)) AS moved, COUNT(*) FILTER (WHERE %[3]s) AS skipped
+			FROM %[1]s WHERE %[2]s = @duplicate`, t.table, t.column, t.conflict())
+		if err := r.db.DB.WithContext(ctx).Raw(query, args).Scan(&row).Error; err != nil {
+			return nil, fmt.Errorf("failed to count %s.%s: %w", t.table, t.column, err)
+		}
+		counts = append(counts, user.MergeCount{Table: t.table, Column: t.column, Moved: row.Moved, Skipped: row.Skipped})
+	}
+	return counts, nil
+}
+
+func (r *AccountMergeRepository) Merge(ctx context.Context, merge *user.AccountMerge) error {
+	args := map[string]interface{}{"primary": merge.PrimaryUserID, "duplicate": merge.DuplicateUserID}
+
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock both accounts so concurrent merges of either one serialize
+		var locked int64
+		if err := tx.Raw("SELECT COUNT(*) FROM (SELECT id FROM users WHERE id IN (@primary, @duplicate) ORDER BY id FOR UPDATE) u", args).
+			Scan(&locked).Error; err != nil {
+			return fmt.Errorf("failed to lock accounts: %w", err)
+		}
+		if locked != 2 {
+			return user.ErrUserNotFound
+		}
+
+		counts := make([]user.MergeCount, 0, len(mergeTargets))
+		for _, t := range mergeTargets {
+			var skipped int64
+			if err := tx.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = @duplicate AND %s", t.table, t.column, t.conflict()), args).
+				Scan(&skipped).Error; err != nil {
+				return fmt.Errorf("failed to count %s.%s: %w", t.table, t.column, err)
+			}
+
+			result := tx.Exec(fmt.Sprintf("UPDATE %[1]s SET %[2]s = @primary WHERE %[2]s = @duplicate AND NOT (%[3]s)",
+				t.table, t.column, t.conflict()), args)
+			if result.Error != nil {
+				return fmt.Errorf("failed to move %s.%s: %w", t.table, t.column, result.Error)
+			}
+			counts = append(counts, user.MergeCount{Table: t.table, Column: t.column, Moved: result.RowsAffected, Skipped: skipped})
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.RefreshTokenModel{}).
+			Where("user_id = ? AND revoked = false", merge.DuplicateUserID).
+			Updates(map[string]interface{}{
+				"revoked":       true,
+				"revoked_at":    now,
+				"revoke_reason": user.RevokeReasonMerged,
+				"updated_at":    now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+
+		if err := tx.Model(&models.UserModel{}).
+			Where("id = ?", merge.DuplicateUserID).
+			Updates(map[string]interface{}{
+				"is_active":  false,
+				"updated_at": now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to deactivate account: %w", err)
+		}
+
+		if merge.ID == uuid.Nil {
+			merge.ID = uuid.New()
+		}
+		merge.Counts = counts
+		merge.CreatedAt = now
+		if err := tx.Create(toAccountMergeModel(merge)).Error; err != nil {
+			if strings.Contains(err.Error(), "duplicate key") {
+				return user.ErrAlreadyMerged
+			}
+			return fmt.Errorf("failed to record account merge: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *AccountMergeRepository) GetByDuplicate(ctx context.Context, duplicateID uuid.UUID) (*user.AccountMerge, error) {
+	var dbModel models.AccountMergeModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "duplicate_user_id = ?", duplicateID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrMergeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account merge: %w", err)
+	}
+
+	return toAccountMergeEntity(&dbModel), nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toAccountMergeModel(m *user.AccountMerge) *models.AccountMergeModel {
+	counts := make([]models.AccountMergeCount, len(m.Counts))
+	for i, c := range m.Counts {
+		counts[i] = models.AccountMergeCount(c)
+	}
+	return &models.AccountMergeModel{
+		ID:              m.ID,
+		PrimaryUserID:   m.PrimaryUserID,
+		DuplicateUserID: m.DuplicateUserID,
+		MergedBy:        m.MergedBy,
+		Counts:          counts,
+		CreatedAt:       m.CreatedAt,
+	}
+}
+
+func toAccountMergeEntity(m *models.AccountMergeModel) *user.AccountMerge {
+	counts := make([]user.MergeCount, len(m.Counts))
+	for i, c := range m.Counts {
+		counts[i] = user.MergeCount(c)
+	}
+	return &user.AccountMerge{
+		ID:              m.ID,
+		PrimaryUserID:   m.PrimaryUserID,
+		DuplicateUserID: m.DuplicateUserID,
+		MergedBy:        m.MergedBy,
+		Counts:          counts,
+		CreatedAt:       m.CreatedAt,
+	}
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AddressRepository implements domain.User.AddressRepository interface
+type AddressRepository struct {
+	db *DB
+}
+
+// NewAddressRepository creates a new address book repository
+func NewAddressRepository(db *DB) user.AddressRepository {
+	return &AddressRepository{db: db}
+}
+
+func (r *AddressRepository) Create(ctx context.Context, address *user.SavedAddress) error {
+	if address.ID == uuid.Nil {
+		address.ID = uuid.New()
+	}
+	now := time.Now()
+	address.CreatedAt = now
+	address.UpdatedAt = now
+
+	if err := r.db.DB.WithContext(ctx).Create(toSavedAddressModel(address)).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return user.ErrAddressLabelTaken
+		}
+		return fmt.Errorf("failed to create address: %w", err)
+	}
+	return nil
+}
+
+func (r *AddressRepository) GetByID(ctx context.Context, addressID uuid.UUID) (*user.SavedAddress, error) {
+	var dbModel models.SavedAddressModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", addressID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get address: %w", err)
+	}
+
+	return toSavedAddressEntity(&dbModel), nil
+}
+
+func (r *AddressRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*user.SavedAddress, error) {
+	var dbModels []models.SavedAddressModel
+	err := r.db.DB.WithContext(ctx).
+		Where("owner_id = ?", ownerID).
+		Order("use_count DESC, label ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses: %w", err)
+	}
+
+	addresses := make([]*user.SavedAddress, len(dbModels))
+	for i := range dbModels {
+		addresses[i] = toSavedAddressEntity(&dbModels[i])
+	}
+	return addresses, nil
+}
+
+func (r *AddressRepository) Update(ctx context.Context, address *user.SavedAddress) error {
+	address.UpdatedAt = time.Now()
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.SavedAddressModel{}).
+		Where("id = ?", address.ID).
+		Select("label", "line1", "line2", "ward", "district", "city", "province", "postal_code", "country_code",
+			"latitude", "longitude", "contact_name", "contact_phone", "status", "verified_at", "updated_at").
+		Updates(toSavedAddressModel(address))
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "duplicate key") {
+			return user.ErrAddressLabelTaken
+		}
+		return fmt.Errorf("failed to update address: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrAddressNotFound
+	}
+	return nil
+}
+
+func (r *AddressRepository) Delete(ctx context.Context, addressID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).Delete(&models.SavedAddressModel{}, "id = ?", addressID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete address: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrAddressNotFound
+	}
+	return nil
+}
+
+func (r *AddressRepository) RecordUse(ctx context.Context, addressID uuid.UUID, at time.Time) error {
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.SavedAddressModel{}).
+		Where("id = ?", addressID).
+		Updates(map[string]interface{}{
+			"use_count":    gorm.Expr("use_count + 1"),
+			"last_used_at": at,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record address use: %w", err)
+	}
+	return nil
+}
+
+func (r *AddressRepository) MarkVerified(ctx context.Context, addressIDs []uuid.UUID, at time.Time) error {
+	if len(addressIDs) == 0 {
+		return nil
+	}
+
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.SavedAddressModel{}).
+		Where("id IN ? AND status = ?", addressIDs, string(user.AddressUnverified)).
+		Updates(map[string]interface{}{
+			"status":      string(user.AddressVerified),
+			"verified_at": at,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to verify addresses: %w", err)
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toSavedAddressModel(a *user.SavedAddress) *models.SavedAddressModel {
+	return &models.SavedAddressModel{
+		ID:           a.ID,
+		OwnerID:      a.OwnerID,
+		Label:        a.Label,
+		Line1:        a.Line1,
+		Line2:        a.Line2,
+		Ward:         a.Ward,
+		District:     a.District,
+		City:         a.City,
+		Province:     a.Province,
+		PostalCode:   a.PostalCode,
+		CountryCode:  a.CountryCode,
+		Latitude:     a.Latitude,
+		Longitude:    a.Longitude,
+		ContactName:  a.ContactName,
+		ContactPhone: a.ContactPhone,
+		Status:       string(a.Status),
+		VerifiedAt:   a.VerifiedAt,
+		UseCount:     a.UseCount,
+		LastUsedAt:   a.LastUsedAt,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
+
+func toSavedAddressEntity(m *models.SavedAddressModel) *user.SavedAddress {
+	return &user.SavedAddress{
+		ID:           m.ID,
+		OwnerID:      m.OwnerID,
+		Label:        m.Label,
+		Line1:        m.Line1,
+		Line2:        m.Line2,
+		Ward:         m.Ward,
+		District:     m.District,
+		City:         m.City,
+		Province:     m.Province,
+		PostalCode:   m.PostalCode,
+		CountryCode:  m.CountryCode,
+		Latitude:     m.Latitude,
+		Longitude:    m.Longitude,
+		ContactName:  m.ContactName,
+		ContactPhone: m.ContactPhone,
+		Status:       user.AddressStatus(m.Status),
+		VerifiedAt:   m.VerifiedAt,
+		UseCount:     m.UseCount,
+		LastUsedAt:   m.LastUsedAt,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	domainAnnouncement "cargo-tracker/internal/domain/announcement"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnnouncementRepository implements domain.Announcement.Repository interface
+type AnnouncementRepository struct {
+	db *DB
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(db *DB) domainAnnouncement.Repository {
+	return &AnnouncementRepository{db: db}
+}
+
+func (r *AnnouncementRepository) Create(ctx context.Context, a *domainAnnouncement.Announcement) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+
+	if err := r.db.DB.WithContext(ctx).Create(toAnnouncementModel(a)).Error; err != nil {
+		return fmt.Errorf("failed to create announcement: %w", err)
+	}
+	return nil
+}
+
+func (r *AnnouncementRepository) GetByID(ctx context.Context, announcementID uuid.UUID) (*domainAnnouncement.Announcement, error) {
+	var dbModel models.AnnouncementModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", announcementID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainAnnouncement.ErrAnnouncementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get announcement: %w", err)
+	}
+
+	return toAnnouncementEntity(&dbModel), nil
+}
+
+func (r *AnnouncementRepository) Update(ctx context.Context, a *domainAnnouncement.Announcement) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.AnnouncementModel{}).
+		Where("id = ?", a.ID).
+		Updates(map[string]interface{}{
+			"title":                 a.Title,
+			"message":               a.Message,
+			"severity":              string(a.Severity),
+			"starts_at":             a.StartsAt,
+			"ends_at":               a.EndsAt,
+			"maintenance_starts_at": a.MaintenanceStartsAt,
+			"maintenance_ends_at":   a.MaintenanceEndsAt,
+			"updated_at":            time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update announcement: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainAnnouncement.ErrAnnouncementNotFound
+	}
+	return nil
+}
+
+func (r *AnnouncementRepository) Delete(ctx context.Context, announcementID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Delete(&models.AnnouncementModel{}, "id = ?", announcementID)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete announcement: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainAnnouncement.ErrAnnouncementNotFound
+	}
+	return nil
+}
+
+func (r *AnnouncementRepository) List(ctx context.Context, now time.Time, includeExpired bool) ([]*domainAnnouncement.Announcement, error) {
+	db := r.db.DB.WithContext(ctx)
+	if !includeExpired {
+		db = db.Where("ends_at > ?", now)
+	}
+
+	var dbModels []models.AnnouncementModel
+	if err := db.Order("starts_at DESC").Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list announcements: %w", err)
+	}
+
+	return toAnnouncementEntities(dbModels), nil
+}
+
+func (r *AnnouncementRepository) ListUnexpired(ctx context.Context, now time.Time) ([]*domainAnnouncement.Announcement, error) {
+	var dbModels []models.AnnouncementModel
+	if err := r.db.DB.WithContext(ctx).
+		Where("ends_at > ?", now).
+		Order("starts_at ASC").
+		Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list unexpired announcements: %w", err)
+	}
+
+	return toAnnouncementEntities(dbModels), nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toAnnouncementModel(a *domainAnnouncement.Announcement) *models.AnnouncementModel {
+	return &models.AnnouncementModel{
+		ID:                  a.ID,
+		Title:               a.Title,
+		Message:             a.Message,
+		Severity:            string(a.Severity),
+		StartsAt:            a.StartsAt,
+		EndsAt:              a.EndsAt,
+		MaintenanceStartsAt: a.MaintenanceStartsAt,
+		MaintenanceEndsAt:   a.MaintenanceEndsAt,
+		CreatedBy:           a.CreatedBy,
+		CreatedAt:           a.CreatedAt,
+		UpdatedAt:           a.UpdatedAt,
+	}
+}
+
+func toAnnouncementEntity(m *models.AnnouncementModel) *domainAnnouncement.Announcement {
+	return &domainAnnouncement.Announcement{
+		ID:                  m.ID,
+		Title:               m.Title,
+		Message:             m.Message,
+		Severity:            domainAnnouncement.Severity(m.Severity),
+		StartsAt:            m.StartsAt,
+		EndsAt:              m.EndsAt,
+		MaintenanceStartsAt: m.MaintenanceStartsAt,
+		MaintenanceEndsAt:   m.MaintenanceEndsAt,
+		CreatedBy:           m.CreatedBy,
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+	}
+}
+
+func toAnnouncementEntities(dbModels []models.AnnouncementModel) []*domainAnnouncement.Announcement {
+	announcements := make([]*domainAnnouncement.Announcement, len(dbModels))
+	for i := range dbModels {
+		announcements[i] = toAnnouncementEntity(&dbModels[i])
+	}
+	return announcements
+}
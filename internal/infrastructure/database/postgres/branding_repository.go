@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BrandingRepository implements domain.User.BrandingRepository interface
+type BrandingRepository struct {
+	db *DB
+}
+
+// NewBrandingRepository creates a new provider branding repository
+func NewBrandingRepository(db *DB) user.BrandingRepository {
+	return &BrandingRepository{db: db}
+}
+
+func (r *BrandingRepository) Get(ctx context.Context, providerID uuid.UUID) (*user.Branding, error) {
+	var dbModel models.ProviderBrandingModel
+	err := r.db.DB.WithContext(ctx).
+		Where("provider_id = ?", providerID).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrBrandingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branding: %w", err)
+	}
+
+	return toBrandingEntity(&dbModel), nil
+}
+
+func (r *BrandingRepository) Save(ctx context.Context, branding *user.Branding) error {
+	now := time.Now()
+	if branding.CreatedAt.IsZero() {
+		branding.CreatedAt = now
+	}
+	branding.UpdatedAt = now
+
+	dbModel := toBrandingModel(branding)
+	err := r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "provider_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"display_name", "primary_color", "accent_color",
+				"support_email", "support_phone", "support_url",
+				"logo_key", "logo_content_type", "updated_at",
+			}),
+		}).
+		Create(dbModel).Error
+	if err != nil {
+		return fmt.Errorf("failed to save branding: %w", err)
+	}
+
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toBrandingModel(b *user.Branding) *models.ProviderBrandingModel {
+	return &models.ProviderBrandingModel{
+		ProviderID:      b.ProviderID,
+		DisplayName:     b.DisplayName,
+		PrimaryColor:    b.PrimaryColor,
+		AccentColor:     b.AccentColor,
+		SupportEmail:    b.SupportEmail,
+		SupportPhone:    b.SupportPhone,
+		SupportURL:      b.SupportURL,
+		LogoKey:         b.LogoKey,
+		LogoContentType: b.LogoContentType,
+		CreatedAt:       b.CreatedAt,
+		UpdatedAt:       b.UpdatedAt,
+	}
+}
+
+func toBrandingEntity(m *models.ProviderBrandingModel) *user.Branding {
+	return &user.Branding{
+		ProviderID:      m.ProviderID,
+		DisplayName:     m.DisplayName,
+		PrimaryColor:    m.PrimaryColor,
+		AccentColor:     m.AccentColor,
+		SupportEmail:    m.SupportEmail,
+		SupportPhone:    m.SupportPhone,
+		SupportURL:      m.SupportURL,
+		LogoKey:         m.LogoKey,
+		LogoContentType: m.LogoContentType,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
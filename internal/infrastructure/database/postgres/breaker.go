@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/logger"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrDatabaseUnavailable is returned by every query while the breaker is open
+var ErrDatabaseUnavailable = errors.New("database unavailable")
+
+const (
+	// DefaultBreakerThreshold is used when DB_BREAKER_THRESHOLD is unset
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerProbeInterval is used when DB_BREAKER_PROBE_INTERVAL is unset
+	DefaultBreakerProbeInterval = 5 * time.Second
+
+	probeTimeout = 3 * time.Second
+)
+
+// Breaker stops queries from reaching the database once it looks down.
+//
+// It counts consecutive connection failures across every repository call.
+// After threshold of them the breaker opens: queries fail immediately with
+// ErrDatabaseUnavailable and the database is pinged every probe interval.
+// The first successful ping closes the breaker again. Query errors such as
+// constraint violations or missing rows are not connection failures and
+// never open it. A nil Breaker is always closed.
+type Breaker struct {
+	threshold     int
+	probeInterval time.Duration
+	ping          func(ctx context.Context) error
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+}
+
+func newBreaker(threshold int, probeInterval time.Duration, ping func(ctx context.Context) error) *Breaker {
+	if threshold <= 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	if probeInterval <= 0 {
+		probeInterval = DefaultBreakerProbeInterval
+	}
+	return &Breaker{
+		threshold:     threshold,
+		probeInterval: probeInterval,
+		ping:          ping,
+	}
+}
+
+// Available reports whether queries are let through
+func (b *Breaker) Available() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.openedAt.IsZero()
+}
+
+// RetryAfter is how long callers should wait before trying again, which is
+// when the next probe runs
+func (b *Breaker) RetryAfter() time.Duration {
+	if b == nil {
+		return 0
+	}
+	return b.probeInterval
+}
+
+// register hooks the breaker into every kind of gorm statement
+func (b *Breaker) register(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("breaker:before_create", b.before),
+		cb.Create().After("gorm:create").Register("breaker:after_create", b.after),
+		cb.Query().Before("gorm:query").Register("breaker:before_query", b.before),
+		cb.Query().After("gorm:query").Register("breaker:after_query", b.after),
+		cb.Update().Before("gorm:update").Register("breaker:before_update", b.before),
+		cb.Update().After("gorm:update").Register("breaker:after_update", b.after),
+		cb.Delete().Before("gorm:delete").Register("breaker:before_delete", b.before),
+		cb.Delete().After("gorm:delete").Register("breaker:after_delete", b.after),
+		cb.Row().Before("gorm:row").Register("breaker:before_row", b.before),
+		cb.Row().After("gorm:row").Register("breaker:after_row", b.after),
+		cb.Raw().Before("gorm:raw").Register("breaker:before_raw", b.before),
+		cb.Raw().After("gorm:raw").Register("breaker:after_raw", b.after),
+	)
+}
+
+// before fails the statement without running it while the breaker is open.
+// gorm skips the query itself once the statement carries an error.
+func (b *Breaker) before(db *gorm.DB) {
+	if !b.Available() {
+		_ = db.AddError(ErrDatabaseUnavailable)
+	}
+}
+
+func (b *Breaker) after(db *gorm.DB) {
+	err := db.Error
+	if errors.Is(err, ErrDatabaseUnavailable) {
+		return
+	}
+	if err == nil || !isConnectionError(err) {
+		b.mu.Lock()
+		if b.openedAt.IsZero() {
+			b.failures = 0
+		}
+		b.mu.Unlock()
+		return
+	}
+
+	b.mu.Lock()
+	b.failures++
+	trip := b.openedAt.IsZero() && b.failures >= b.threshold
+	if trip {
+		b.openedAt = time.Now()
+	}
+	b.mu.Unlock()
+
+	if trip {
+		logger.Error("Database circuit breaker opened",
+			zap.Int("consecutive_failures", b.threshold),
+			zap.Error(err),
+			zap.String("event", "db_breaker_opened"),
+		)
+		go b.probe()
+	}
+}
+
+// probe pings the database until it answers, then closes the breaker
+func (b *Breaker) probe() {
+	ticker := time.NewTicker(b.probeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		err := b.ping(ctx)
+		cancel()
+		if err != nil {
+			logger.Warn("Database still unavailable", zap.Error(err))
+			continue
+		}
+
+		b.mu.Lock()
+		outage := time.Since(b.openedAt)
+		b.openedAt = time.Time{}
+		b.failures = 0
+		b.mu.Unlock()
+
+		logger.Info("Database circuit breaker closed",
+			zap.Duration("outage", outage),
+			zap.String("event", "db_breaker_closed"),
+		)
+		return
+	}
+}
+
+// isConnectionError tells errors caused by an unreachable or failing server
+// apart from errors caused by the statement itself
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// The caller gave up, which says nothing about the server
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08 is connection exceptions; 57P0x is the server shutting
+		// down; 53300 is the server out of connection slots
+		return strings.HasPrefix(pgErr.Code, "08") ||
+			strings.HasPrefix(pgErr.Code, "57P0") ||
+			pgErr.Code == "53300"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarRepository implements domain.Shipment.CalendarRepository interface
+type CalendarRepository struct {
+	db *DB
+}
+
+// NewCalendarRepository creates a new business calendar repository
+func NewCalendarRepository(db *DB) shipment.CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+func (r *CalendarRepository) Get(ctx context.Context, providerID uuid.UUID) (*shipment.BusinessCalendar, error) {
+	var dbModel models.BusinessCalendarModel
+	err := r.db.DB.WithContext(ctx).
+		Where("provider_id = ?", providerID).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrCalendarNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business calendar: %w", err)
+	}
+
+	return toCalendarEntity(&dbModel), nil
+}
+
+func (r *CalendarRepository) Save(ctx context.Context, calendar *shipment.BusinessCalendar) error {
+	now := time.Now()
+	if calendar.CreatedAt.IsZero() {
+		calendar.CreatedAt = now
+	}
+	calendar.UpdatedAt = now
+
+	dbModel := toCalendarModel(calendar)
+	err := r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "provider_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"timezone", "working_days", "open_minute", "close_minute",
+				"cutoff_minute", "holidays", "updated_at",
+			}),
+		}).
+		Create(dbModel).Error
+	if err != nil {
+		return fmt.Errorf("failed to save business calendar: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CalendarRepository) Delete(ctx context.Context, providerID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("provider_id = ?", providerID).
+		Delete(&models.BusinessCalendarModel{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete business calendar: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrCalendarNotFound
+	}
+
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toCalendarModel(c *shipment.BusinessCalendar) *models.BusinessCalendarModel {
+	days := make([]int, len(c.WorkingDays))
+	for i, d := range c.WorkingDays {
+		days[i] = int(d)
+	}
+	holidays := make([]models.BusinessHoliday, len(c.Holidays))
+	for i, h := range c.Holidays {
+		holidays[i] = models.BusinessHoliday{Date: h.Date, Name: h.Name}
+	}
+
+	return &models.BusinessCalendarModel{
+		ProviderID:   c.ProviderID,
+		Timezone:     c.Timezone,
+		WorkingDays:  days,
+		OpenMinute:   c.OpenMinute,
+		CloseMinute:  c.CloseMinute,
+		CutoffMinute: c.CutoffMinute,
+		Holidays:     holidays,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+func toCalendarEntity(m *models.BusinessCalendarModel) *shipment.BusinessCalendar {
+	days := make([]time.Weekday, len(m.WorkingDays))
+	for i, d := range m.WorkingDays {
+		days[i] = time.Weekday(d)
+	}
+	holidays := make([]shipment.Holiday, len(m.Holidays))
+	for i, h := range m.Holidays {
+		holidays[i] = shipment.Holiday{Date: h.Date, Name: h.Name}
+	}
+
+	return &shipment.BusinessCalendar{
+		ProviderID:   m.ProviderID,
+		Timezone:     m.Timezone,
+		WorkingDays:  days,
+		OpenMinute:   m.OpenMinute,
+		CloseMinute:  m.CloseMinute,
+		CutoffMinute: m.CutoffMinute,
+		Holidays:     holidays,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
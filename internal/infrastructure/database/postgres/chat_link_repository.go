@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChatLinkRepository implements domain.Notification.ChatLinkRepository interface
+type ChatLinkRepository struct {
+	db *DB
+}
+
+// NewChatLinkRepository creates a new chat link repository
+func NewChatLinkRepository(db *DB) domainNotification.ChatLinkRepository {
+	return &ChatLinkRepository{db: db}
+}
+
+func (r *ChatLinkRepository) CreateCode(ctx context.Context, code *domainNotification.ChatLinkCode) error {
+	if code.ID == uuid.Nil {
+		code.ID = uuid.New()
+	}
+	code.CreatedAt = time.Now()
+
+	if err := r.db.DB.WithContext(ctx).Create(toChatLinkCodeModel(code)).Error; err != nil {
+		return fmt.Errorf("failed to create link code: %w", err)
+	}
+	return nil
+}
+
+func (r *ChatLinkRepository) ConsumeCode(ctx context.Context, provider domainNotification.ChatProvider, codeHash string, now time.Time) (*domainNotification.ChatLinkCode, error) {
+	var dbModel models.ChatLinkCodeModel
+	result := r.db.DB.WithContext(ctx).
+		Model(&dbModel).
+		Clauses(clause.Returning{}).
+		Where("provider = ? AND code_hash = ? AND used_at IS NULL AND expires_at > ?", string(provider), codeHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to consume link code: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, domainNotification.ErrInvalidLinkCode
+	}
+
+	return toChatLinkCodeEntity(&dbModel), nil
+}
+
+func (r *ChatLinkRepository) SaveLink(ctx context.Context, link *domainNotification.ChatLink) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// A chat belongs to one person; detach it from any other account first
+		if err := tx.Model(&models.ChatLinkModel{}).
+			Where("provider = ? AND chat_id = ? AND user_id <> ?", string(link.Provider), link.ChatID, link.UserID).
+			Updates(map[string]interface{}{"is_active": false, "updated_at": now}).Error; err != nil {
+			return fmt.Errorf("failed to detach chat: %w", err)
+		}
+
+		if link.ID == uuid.Nil {
+			link.ID = uuid.New()
+		}
+		link.IsActive = true
+		link.LinkedAt = now
+		link.UpdatedAt = now
+
+		dbModel := toChatLinkModel(link)
+		err := tx.Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "provider"}},
+				DoUpdates: clause.AssignmentColumns([]string{"chat_id", "is_active", "linked_at", "updated_at"}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "id"}}},
+		).Create(dbModel).Error
+		if err != nil {
+			return fmt.Errorf("failed to save chat link: %w", err)
+		}
+		link.ID = dbModel.ID
+		return nil
+	})
+}
+
+func (r *ChatLinkRepository) ListLinks(ctx context.Context, userID uuid.UUID) ([]*domainNotification.ChatLink, error) {
+	return r.listLinks(r.db.DB.WithContext(ctx).Where("user_id = ?", userID))
+}
+
+func (r *ChatLinkRepository) ListActiveLinks(ctx context.Context, userID uuid.UUID) ([]*domainNotification.ChatLink, error) {
+	return r.listLinks(r.db.DB.WithContext(ctx).Where("user_id = ? AND is_active = ?", userID, true))
+}
+
+func (r *ChatLinkRepository) listLinks(query *gorm.DB) ([]*domainNotification.ChatLink, error) {
+	var dbModels []models.ChatLinkModel
+	if err := query.Order("provider ASC").Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list chat links: %w", err)
+	}
+
+	links := make([]*domainNotification.ChatLink, len(dbModels))
+	for i := range dbModels {
+		links[i] = toChatLinkEntity(&dbModels[i])
+	}
+	return links, nil
+}
+
+func (r *ChatLinkRepository) DeleteLink(ctx context.Context, userID uuid.UUID, provider domainNotification.ChatProvider) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("user_id = ? AND provider = ?", userID, string(provider)).
+		Delete(&models.ChatLinkModel{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete chat link: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainNotification.ErrChatLinkNotFound
+	}
+	return nil
+}
+
+func (r *ChatLinkRepository) DeactivateChat(ctx context.Context, provider domainNotification.ChatProvider, chatID string) error {
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.ChatLinkModel{}).
+		Where("provider = ? AND chat_id = ? AND is_active = ?", string(provider), chatID, true).
+		Updates(map[string]interface{}{"is_active": false, "updated_at": time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("failed to deactivate chat link: %w", err)
+	}
+	return nil
+}
+
+func (r *ChatLinkRepository) CreateDelivery(ctx context.Context, delivery *domainNotification.ChatDelivery) error {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+
+	if err := r.db.DB.WithContext(ctx).Create(toChatDeliveryModel(delivery)).Error; err != nil {
+		return fmt.Errorf("failed to record chat delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *ChatLinkRepository) UpdateDeliveryStatus(ctx context.Context, provider domainNotification.ChatProvider, messageIDs []string, status domainNotification.ChatDeliveryStatus, at time.Time) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	updates := map[string]interface{}{"status": string(status)}
+	var previous []string
+	switch status {
+	case domainNotification.ChatDeliveryDelivered:
+		updates["delivered_at"] = at
+		previous = []string{string(domainNotification.ChatDeliverySent)}
+	case domainNotification.ChatDeliverySeen:
+		updates["seen_at"] = at
+		updates["delivered_at"] = gorm.Expr("COALESCE(delivered_at, ?)", at)
+		previous = []string{string(domainNotification.ChatDeliverySent), string(domainNotification.ChatDeliveryDelivered)}
+	default:
+		return fmt.Errorf("unsupported receipt status %q", status)
+	}
+
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.ChatDeliveryModel{}).
+		Where("provider = ? AND provider_message_id IN ? AND status IN ?", string(provider), messageIDs, previous).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to update chat delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *ChatLinkRepository) ListDeliveries(ctx context.Context, userID uuid.UUID, limit int) ([]*domainNotification.ChatDelivery, error) {
+	var dbModels []models.ChatDeliveryModel
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("sent_at DESC").
+		Limit(limit).
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat deliveries: %w", err)
+	}
+
+	deliveries := make([]*domainNotification.ChatDelivery, len(dbModels))
+	for i := range dbModels {
+		deliveries[i] = toChatDeliveryEntity(&dbModels[i])
+	}
+	return deliveries, nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toChatLinkModel(l *domainNotification.ChatLink) *models.ChatLinkModel {
+	return &models.ChatLinkModel{
+		ID:        l.ID,
+		UserID:    l.UserID,
+		Provider:  string(l.Provider),
+		ChatID:    l.ChatID,
+		IsActive:  l.IsActive,
+		LinkedAt:  l.LinkedAt,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
+
+func toChatLinkEntity(m *models.ChatLinkModel) *domainNotification.ChatLink {
+	return &domainNotification.ChatLink{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Provider:  domainNotification.ChatProvider(m.Provider),
+		ChatID:    m.ChatID,
+		IsActive:  m.IsActive,
+		LinkedAt:  m.LinkedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func toChatLinkCodeModel(c *domainNotification.ChatLinkCode) *models.ChatLinkCodeModel {
+	return &models.ChatLinkCodeModel{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		Provider:  string(c.Provider),
+		CodeHash:  c.CodeHash,
+		ExpiresAt: c.ExpiresAt,
+		UsedAt:    c.UsedAt,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func toChatLinkCodeEntity(m *models.ChatLinkCodeModel) *domainNotification.ChatLinkCode {
+	return &domainNotification.ChatLinkCode{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Provider:  domainNotification.ChatProvider(m.Provider),
+		CodeHash:  m.CodeHash,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func toChatDeliveryModel(d *domainNotification.ChatDelivery) *models.ChatDeliveryModel {
+	return &models.ChatDeliveryModel{
+		ID:                d.ID,
+		LinkID:            d.LinkID,
+		UserID:            d.UserID,
+		Provider:          string(d.Provider),
+		Event:             d.Event,
+		ProviderMessageID: d.ProviderMessageID,
+		Status:            string(d.Status),
+		Error:             d.Error,
+		SentAt:            d.SentAt,
+		DeliveredAt:       d.DeliveredAt,
+		SeenAt:            d.SeenAt,
+	}
+}
+
+func toChatDeliveryEntity(m *models.ChatDeliveryModel) *domainNotification.ChatDelivery {
+	return &domainNotification.ChatDelivery{
+		ID:                m.ID,
+		LinkID:            m.LinkID,
+		UserID:            m.UserID,
+		Provider:          domainNotification.ChatProvider(m.Provider),
+		Event:             m.Event,
+		ProviderMessageID: m.ProviderMessageID,
+		Status:            domainNotification.ChatDeliveryStatus(m.Status),
+		Error:             m.Error,
+		SentAt:            m.SentAt,
+		DeliveredAt:       m.DeliveredAt,
+		SeenAt:            m.SeenAt,
+	}
+}
@@ -0,0 +1,372 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/cleanup"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CleanupRepository implements domain.Cleanup.Repository interface
+type CleanupRepository struct {
+	db *DB
+}
+
+// NewCleanupRepository creates a new cleanup repository
+func NewCleanupRepository(db *DB) cleanup.Repository {
+	return &CleanupRepository{db: db}
+}
+
+func (r *CleanupRepository) CreateJob(ctx context.Context, job *cleanup.Job) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+
+	dbModel := toCleanupJobModel(job)
+	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to create cleanup job: %w", err)
+	}
+	return nil
+}
+
+func (r *CleanupRepository) UpdateJob(ctx context.Context, job *cleanup.Job) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.CleanupJobModel{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"status":      string(job.Status),
+			"matched":     job.Matched,
+			"deleted":     job.Deleted,
+			"skipped":     job.Skipped,
+			"last_error":  job.LastError,
+			"finished_at": job.FinishedAt,
+			"updated_at":  job.UpdatedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update cleanup job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return cleanup.ErrJobNotFound
+	}
+	return nil
+}
+
+func (r *CleanupRepository) GetJob(ctx context.Context, jobID uuid.UUID) (*cleanup.Job, error) {
+	var dbModel models.CleanupJobModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", jobID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, cleanup.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleanup job: %w", err)
+	}
+
+	return toCleanupJobEntity(&dbModel), nil
+}
+
+func (r *CleanupRepository) ListJobs(ctx context.Context, limit int) ([]*cleanup.Job, error) {
+	var dbModels []models.CleanupJobModel
+	if err := r.db.DB.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list cleanup jobs: %w", err)
+	}
+
+	jobs := make([]*cleanup.Job, len(dbModels))
+	for i := range dbModels {
+		jobs[i] = toCleanupJobEntity(&dbModels[i])
+	}
+	return jobs, nil
+}
+
+func (r *CleanupRepository) Count(ctx context.Context, target cleanup.Target, filter *cleanup.Filter, exclude []uuid.UUID) (int64, error) {
+	db, err := r.matching(ctx, target, filter, exclude)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", target, err)
+	}
+	return count, nil
+}
+
+func (r *CleanupRepository) NextBatch(ctx context.Context, target cleanup.Target, filter *cleanup.Filter, exclude []uuid.UUID, limit int) ([]uuid.UUID, error) {
+	db, err := r.matching(ctx, target, filter, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	if err := db.Order("created_at, id").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to select %s: %w", target, err)
+	}
+	return ids, nil
+}
+
+// matching builds the query selecting the records of target that match filter
+func (r *CleanupRepository) matching(ctx context.Context, target cleanup.Target, filter *cleanup.Filter, exclude []uuid.UUID) (*gorm.DB, error) {
+	db := r.db.DB.WithContext(ctx)
+	switch target {
+	case cleanup.TargetShipments:
+		db = db.Model(&models.ShipmentModel{})
+		if filter.SandboxOnly {
+			db = db.Where("is_sandbox")
+		}
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		if filter.Search != "" {
+			db = db.Where("search_text LIKE ?", "%"+escapeLike(filter.Search)+"%")
+		}
+	case cleanup.TargetDevices:
+		db = db.Model(&models.DeviceModel{})
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		if filter.Search != "" {
+			pattern := "%" + escapeLike(filter.Search) + "%"
+			db = db.Where("hardware_uid ILIKE ? OR device_name ILIKE ?", pattern, pattern)
+		}
+	case cleanup.TargetUsers:
+		// Admin accounts are never bulk deleted
+		db = db.Model(&models.UserModel{}).Where("role <> ?", "admin")
+		if filter.SandboxOnly {
+			db = db.Where("is_sandbox")
+		}
+		if filter.Role != "" {
+			db = db.Where("role = ?", filter.Role)
+		}
+		if filter.Search != "" {
+			db = db.Where("search_text LIKE ?", "%"+escapeLike(filter.Search)+"%")
+		}
+	default:
+		return nil, fmt.Errorf("unknown cleanup target %q", target)
+	}
+
+	if filter.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if len(exclude) > 0 {
+		db = db.Where("id NOT IN ?", exclude)
+	}
+	return db, nil
+}
+
+func (r *CleanupRepository) DeleteBatch(ctx context.Context, target cleanup.Target, ids []uuid.UUID) (*cleanup.BatchResult, error) {
+	result := &cleanup.BatchResult{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		switch target {
+		case cleanup.TargetShipments:
+			return deleteShipments(tx, ids, result)
+		case cleanup.TargetDevices:
+			return deleteDevices(tx, ids, result)
+		case cleanup.TargetUsers:
+			return deleteUsers(tx, ids, result)
+		}
+		return fmt.Errorf("unknown cleanup target %q", target)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *CleanupRepository) FailRunning(ctx context.Context, reason string) (int64, error) {
+	now := time.Now()
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.CleanupJobModel{}).
+		Where("status = ?", string(cleanup.JobRunning)).
+		Updates(map[string]interface{}{
+			"status":      string(cleanup.JobFailed),
+			"last_error":  reason,
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to fail running cleanup jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// deleteShipments removes shipments with their packages, rules, documents,
+// access grants and terms acceptances. Invoice lines and quote requests keep
+// their rows with the shipment reference cleared.
+func deleteShipments(tx *gorm.DB, ids []uuid.UUID, result *cleanup.BatchResult) error {
+	var keys []string
+	if err := tx.Table("shipment_attachments").
+		Where("shipment_id IN ?", ids).
+		Pluck("storage_key", &keys).Error; err != nil {
+		return fmt.Errorf("failed to list shipment attachments: %w", err)
+	}
+
+	// Free devices still tracking a deleted shipment
+	if err := tx.Model(&models.DeviceModel{}).
+		Where("current_shipment_id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":              "available",
+			"current_shipment_id": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to release devices: %w", err)
+	}
+
+	if err := tx.Where("shipment_id IN ?", ids).Delete(&models.ShippingRulesModel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete shipping rules: %w", err)
+	}
+
+	deleted := tx.Where("id IN ?", ids).Delete(&models.ShipmentModel{})
+	if deleted.Error != nil {
+		return fmt.Errorf("failed to delete shipments: %w", deleted.Error)
+	}
+
+	result.Deleted += deleted.RowsAffected
+	result.StorageKeys = append(result.StorageKeys, keys...)
+	return nil
+}
+
+// deleteDevices removes devices with their trips. Devices on a shipment and
+// devices with a decommission certificate are kept.
+func deleteDevices(tx *gorm.DB, ids []uuid.UUID, result *cleanup.BatchResult) error {
+	var deletable []uuid.UUID
+	if err := tx.Model(&models.DeviceModel{}).
+		Where("id IN ?", ids).
+		Where("current_shipment_id IS NULL AND status <> ?", "in_transit").
+		Where("NOT EXISTS (SELECT 1 FROM device_decommissions d WHERE d.device_id = devices.id)").
+		Pluck("id", &deletable).Error; err != nil {
+		return fmt.Errorf("failed to select devices: %w", err)
+	}
+	kept := make(map[uuid.UUID]bool, len(deletable))
+	for _, id := range deletable {
+		kept[id] = true
+	}
+	for _, id := range ids {
+		if !kept[id] {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+	if len(deletable) == 0 {
+		return nil
+	}
+
+	if err := tx.Exec("DELETE FROM trips WHERE device_id IN ?", deletable).Error; err != nil {
+		return fmt.Errorf("failed to delete trips: %w", err)
+	}
+	if err := tx.Model(&models.ShipmentModel{}).
+		Where("linked_device_id IN ?", deletable).
+		Update("linked_device_id", nil).Error; err != nil {
+		return fmt.Errorf("failed to unlink shipments: %w", err)
+	}
+
+	deleted := tx.Where("id IN ?", deletable).Delete(&models.DeviceModel{})
+	if deleted.Error != nil {
+		return fmt.Errorf("failed to delete devices: %w", deleted.Error)
+	}
+	result.Deleted += deleted.RowsAffected
+	return nil
+}
+
+// userOwnedShipments matches shipments a user ordered or provided
+const userOwnedShipments = "customer_id = @user OR provider_id = @user"
+
+// deleteUsers removes users together with the shipments, quotes and invoices
+// they are a party to. Each user is deleted under its own savepoint so a
+// user still referenced by records of other accounts (shipments they carried,
+// document uploads, decommissions, merges) is skipped instead of failing the
+// batch.
+func deleteUsers(tx *gorm.DB, ids []uuid.UUID, result *cleanup.BatchResult) error {
+	for _, id := range ids {
+		args := map[string]interface{}{"user": id}
+
+		if err := tx.SavePoint("cleanup_user").Error; err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		var shipmentIDs []uuid.UUID
+		err := tx.Model(&models.ShipmentModel{}).Where(userOwnedShipments, args).Pluck("id", &shipmentIDs).Error
+		batch := &cleanup.BatchResult{}
+		if err == nil && len(shipmentIDs) > 0 {
+			err = deleteShipments(tx, shipmentIDs, batch)
+		}
+		for _, stmt := range []string{
+			"DELETE FROM quotes WHERE provider_id = @user",
+			"DELETE FROM quote_requests WHERE customer_id = @user",
+			"DELETE FROM invoices WHERE provider_id = @user OR customer_id = @user",
+			"DELETE FROM users WHERE id = @user",
+		} {
+			if err != nil {
+				break
+			}
+			err = tx.Exec(stmt, args).Error
+		}
+
+		if err != nil {
+			if rbErr := tx.RollbackTo("cleanup_user").Error; rbErr != nil {
+				return fmt.Errorf("failed to roll back user %s: %w", id, rbErr)
+			}
+			result.Skipped = append(result.Skipped, id)
+			continue
+		}
+		result.Deleted++
+		result.StorageKeys = append(result.StorageKeys, batch.StorageKeys...)
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toCleanupJobModel(j *cleanup.Job) *models.CleanupJobModel {
+	var requestedBy *uuid.UUID
+	if j.RequestedBy != uuid.Nil {
+		requestedBy = &j.RequestedBy
+	}
+	return &models.CleanupJobModel{
+		ID:          j.ID,
+		Target:      string(j.Target),
+		Filter:      models.CleanupJobFilter(j.Filter),
+		DryRun:      j.DryRun,
+		Status:      string(j.Status),
+		Matched:     j.Matched,
+		Deleted:     j.Deleted,
+		Skipped:     j.Skipped,
+		LastError:   j.LastError,
+		RequestedBy: requestedBy,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+		FinishedAt:  j.FinishedAt,
+	}
+}
+
+func toCleanupJobEntity(m *models.CleanupJobModel) *cleanup.Job {
+	job := &cleanup.Job{
+		ID:         m.ID,
+		Target:     cleanup.Target(m.Target),
+		Filter:     cleanup.Filter(m.Filter),
+		DryRun:     m.DryRun,
+		Status:     cleanup.JobStatus(m.Status),
+		Matched:    m.Matched,
+		Deleted:    m.Deleted,
+		Skipped:    m.Skipped,
+		LastError:  m.LastError,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+		FinishedAt: m.FinishedAt,
+	}
+	if m.RequestedBy != nil {
+		job.RequestedBy = *m.RequestedBy
+	}
+	return job
+}
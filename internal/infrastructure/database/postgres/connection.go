@@ -3,22 +3,65 @@ package postgres
 import (
 	"cargo-tracker/internal/config"
 	"cargo-tracker/internal/logger"
+	"context"
 	"fmt"
-	"time"
+	"log"
+	"os"
+	"strings"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
 )
 
+// DefaultMaxOpenConns is used when DB_MAX_OPEN_CONNS is unset or invalid.
+const DefaultMaxOpenConns = 25
+
 type DB struct {
 	*gorm.DB
+	// Breaker fails queries fast while the database is unreachable
+	Breaker *Breaker
+}
+
+// Option customises how the database connection is opened.
+type Option func(*options)
+
+type options struct {
+	passwordSource func() string
+}
+
+// WithPasswordSource makes every new connection read its password from source,
+// so a rotated database password takes effect without a restart.
+func WithPasswordSource(source func() string) Option {
+	return func(o *options) {
+		o.passwordSource = source
+	}
 }
 
-func NewDB(cfg *config.Config) (*DB, error) {
-	dsn := cfg.Database.DSN()
+func NewDB(cfg *config.Config, opts ...Option) (*DB, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	connConfig, err := pgx.ParseConfig(cfg.Database.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing database config: %w", err)
+	}
+
+	var stdlibOpts []stdlib.OptionOpenDB
+	if o.passwordSource != nil {
+		stdlibOpts = append(stdlibOpts, stdlib.OptionBeforeConnect(func(ctx context.Context, cc *pgx.ConnConfig) error {
+			if password := o.passwordSource(); password != "" {
+				cc.Password = password
+			}
+			return nil
+		}))
+	}
+	conn := stdlib.OpenDB(*connConfig, stdlibOpts...)
 
 	var gormLogLevel gormLogger.LogLevel
 	if cfg.Server.Environment == "production" {
@@ -27,8 +70,26 @@ func NewDB(cfg *config.Config) (*DB, error) {
 		gormLogLevel = gormLogger.Info
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormLogger.Default.LogMode(gormLogLevel),
+	dbCfg := cfg.Database
+	maxOpen := dbCfg.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = DefaultMaxOpenConns
+	}
+	maxIdle := dbCfg.MaxIdleConns
+	if maxIdle < 0 || maxIdle > maxOpen {
+		maxIdle = maxOpen
+	}
+
+	queryLogger := gormLogger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), gormLogger.Config{
+		SlowThreshold:             dbCfg.SlowQueryThreshold,
+		LogLevel:                  gormLogLevel,
+		IgnoreRecordNotFoundError: true,
+		Colorful:                  cfg.Server.Environment != "production",
+	})
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		Logger:      queryLogger,
+		PrepareStmt: dbCfg.PrepareStmt,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error opening database: %w", err)
@@ -39,22 +100,33 @@ func NewDB(cfg *config.Config) (*DB, error) {
 		return nil, fmt.Errorf("error getting sql.DB: %w", err)
 	}
 
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	sqlDB.SetMaxOpenConns(maxOpen)
+	sqlDB.SetMaxIdleConns(maxIdle)
+	sqlDB.SetConnMaxLifetime(dbCfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(dbCfg.ConnMaxIdleTime)
 
 	if err := sqlDB.Ping(); err != nil {
 		return nil, fmt.Errorf("error connecting to database: %w", err)
 	}
 
+	breaker := newBreaker(dbCfg.BreakerThreshold, dbCfg.BreakerProbeInterval, sqlDB.PingContext)
+	if err := breaker.register(db); err != nil {
+		return nil, fmt.Errorf("error registering circuit breaker: %w", err)
+	}
+
 	logger.Info("Database connection established",
 		zap.String("host", cfg.Database.Host),
 		zap.String("database", cfg.Database.DBName),
-		zap.Int("max_open_connections", 25),
-		zap.Int("max_idle_connections", 5),
+		zap.Int("max_open_connections", maxOpen),
+		zap.Int("max_idle_connections", maxIdle),
+		zap.Duration("conn_max_lifetime", dbCfg.ConnMaxLifetime),
+		zap.Duration("conn_max_idle_time", dbCfg.ConnMaxIdleTime),
+		zap.Bool("prepare_stmt", dbCfg.PrepareStmt),
+		zap.Duration("slow_query_threshold", dbCfg.SlowQueryThreshold),
+		zap.Int("breaker_threshold", breaker.threshold),
 	)
 
-	return &DB{DB: db}, nil
+	return &DB{DB: db, Breaker: breaker}, nil
 }
 
 func (d *DB) Close() error {
@@ -72,3 +144,11 @@ func (d *DB) Health() error {
 	}
 	return sqlDB.Ping()
 }
+
+// likeEscaper escapes the LIKE wildcards in user supplied search terms.
+// Backslash is the default LIKE escape character in Postgres.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
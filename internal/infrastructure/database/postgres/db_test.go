@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/logger"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// newMockDB opens a DB on a sqlmock connection. Statements must be expected
+// in the order they are issued.
+func newMockDB(tb testing.TB) (*DB, sqlmock.Sqlmock) {
+	tb.Helper()
+	logger.Logger = zap.NewNop()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		tb.Fatalf("sqlmock.New: %v", err)
+	}
+	tb.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+	if err != nil {
+		tb.Fatalf("gorm.Open: %v", err)
+	}
+	return &DB{DB: db}, mock
+}
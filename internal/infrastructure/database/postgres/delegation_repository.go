@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DelegationRepository implements domain.Shipment.DelegationRepository interface
+type DelegationRepository struct {
+	db *DB
+}
+
+// NewDelegationRepository creates a new customer delegation repository
+func NewDelegationRepository(db *DB) shipment.DelegationRepository {
+	return &DelegationRepository{db: db}
+}
+
+func (r *DelegationRepository) Create(ctx context.Context, delegation *shipment.Delegation) error {
+	if delegation.ID == uuid.Nil {
+		delegation.ID = uuid.New()
+	}
+	delegation.CreatedAt = time.Now()
+
+	dbModel := toDelegationModel(delegation)
+	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to create delegation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DelegationRepository) GetByID(ctx context.Context, delegationID uuid.UUID) (*shipment.Delegation, error) {
+	var dbModel models.CustomerDelegationModel
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ?", delegationID).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrDelegationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegation: %w", err)
+	}
+
+	return toDelegationEntity(&dbModel), nil
+}
+
+func (r *DelegationRepository) ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*shipment.Delegation, error) {
+	var dbModels []models.CustomerDelegationModel
+	err := r.db.DB.WithContext(ctx).
+		Where("customer_id = ?", customerID).
+		Order("created_at DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delegations: %w", err)
+	}
+
+	return toDelegationEntities(dbModels), nil
+}
+
+func (r *DelegationRepository) ListActiveForDelegate(ctx context.Context, delegateID uuid.UUID) ([]*shipment.Delegation, error) {
+	var dbModels []models.CustomerDelegationModel
+	err := r.db.DB.WithContext(ctx).
+		Where("delegate_id = ? AND revoked_at IS NULL", delegateID).
+		Order("created_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delegations: %w", err)
+	}
+
+	return toDelegationEntities(dbModels), nil
+}
+
+func (r *DelegationRepository) FindActive(ctx context.Context, customerID, delegateID uuid.UUID) (*shipment.Delegation, error) {
+	var dbModel models.CustomerDelegationModel
+	err := r.db.DB.WithContext(ctx).
+		Where("customer_id = ? AND delegate_id = ? AND revoked_at IS NULL", customerID, delegateID).
+		Take(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrDelegationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find delegation: %w", err)
+	}
+
+	return toDelegationEntity(&dbModel), nil
+}
+
+func (r *DelegationRepository) Revoke(ctx context.Context, delegationID uuid.UUID, at time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.CustomerDelegationModel{}).
+		Where("id = ? AND revoked_at IS NULL", delegationID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke delegation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrDelegationNotFound
+	}
+
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toDelegationModel(d *shipment.Delegation) *models.CustomerDelegationModel {
+	return &models.CustomerDelegationModel{
+		ID:         d.ID,
+		CustomerID: d.CustomerID,
+		DelegateID: d.DelegateID,
+		Note:       d.Note,
+		RevokedAt:  d.RevokedAt,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+func toDelegationEntity(m *models.CustomerDelegationModel) *shipment.Delegation {
+	return &shipment.Delegation{
+		ID:         m.ID,
+		CustomerID: m.CustomerID,
+		DelegateID: m.DelegateID,
+		Note:       m.Note,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func toDelegationEntities(dbModels []models.CustomerDelegationModel) []*shipment.Delegation {
+	delegations := make([]*shipment.Delegation, len(dbModels))
+	for i := range dbModels {
+		delegations[i] = toDelegationEntity(&dbModels[i])
+	}
+	return delegations
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeviceDecommissionRepository implements domain.Device.DecommissionRepository interface
+type DeviceDecommissionRepository struct {
+	db *DB
+}
+
+// NewDeviceDecommissionRepository creates a new decommission repository
+func NewDeviceDecommissionRepository(db *DB) domainDevice.DecommissionRepository {
+	return &DeviceDecommissionRepository{db: db}
+}
+
+func (r *DeviceDecommissionRepository) Create(ctx context.Context, d *domainDevice.Decommission) error {
+	d.ID = uuid.New()
+	d.CreatedAt = time.Now()
+
+	if err := r.db.DB.WithContext(ctx).Create(toDeviceDecommissionModel(d)).Error; err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
+			return domainDevice.ErrAlreadyDecommissioned
+		}
+		return fmt.Errorf("failed to create decommission: %w", err)
+	}
+	return nil
+}
+
+func (r *DeviceDecommissionRepository) GetByDevice(ctx context.Context, deviceID uuid.UUID) (*domainDevice.Decommission, error) {
+	var dbModel models.DeviceDecommissionModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "device_id = ?", deviceID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainDevice.ErrDecommissionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decommission: %w", err)
+	}
+
+	return toDeviceDecommissionEntity(&dbModel), nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toDeviceDecommissionModel(d *domainDevice.Decommission) *models.DeviceDecommissionModel {
+	return &models.DeviceDecommissionModel{
+		ID:               d.ID,
+		DeviceID:         d.DeviceID,
+		HardwareUID:      d.HardwareUID,
+		Reason:           d.Reason,
+		DecommissionedBy: d.DecommissionedBy,
+		OwnerUnlinked:    d.OwnerUnlinked,
+		ShipmentCount:    d.ShipmentCount,
+		ArchiveKey:       d.ArchiveKey,
+		ArchiveSHA256:    d.ArchiveSHA256,
+		ArchiveSize:      d.ArchiveSize,
+		CreatedAt:        d.CreatedAt,
+	}
+}
+
+func toDeviceDecommissionEntity(m *models.DeviceDecommissionModel) *domainDevice.Decommission {
+	return &domainDevice.Decommission{
+		ID:               m.ID,
+		DeviceID:         m.DeviceID,
+		HardwareUID:      m.HardwareUID,
+		Reason:           m.Reason,
+		DecommissionedBy: m.DecommissionedBy,
+		OwnerUnlinked:    m.OwnerUnlinked,
+		ShipmentCount:    m.ShipmentCount,
+		ArchiveKey:       m.ArchiveKey,
+		ArchiveSHA256:    m.ArchiveSHA256,
+		ArchiveSize:      m.ArchiveSize,
+		CreatedAt:        m.CreatedAt,
+	}
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// DeviceModelProfileRepository implements domain.Device.ModelProfileRepository interface
+type DeviceModelProfileRepository struct {
+	db *DB
+}
+
+// NewDeviceModelProfileRepository creates a new model profile repository
+func NewDeviceModelProfileRepository(db *DB) domainDevice.ModelProfileRepository {
+	return &DeviceModelProfileRepository{db: db}
+}
+
+func (r *DeviceModelProfileRepository) Upsert(ctx context.Context, p *domainDevice.ModelProfile) error {
+	now := time.Now()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	dbModel := toModelProfileModel(p)
+	err := r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "model"}},
+			DoUpdates: clause.AssignmentColumns([]string{"capabilities", "updated_at"}),
+		}, clause.Returning{Columns: []clause.Column{{Name: "created_at"}}}).
+		Create(dbModel).Error
+	if err != nil {
+		return fmt.Errorf("failed to save device model profile: %w", err)
+	}
+
+	p.CreatedAt = dbModel.CreatedAt
+	return nil
+}
+
+func (r *DeviceModelProfileRepository) List(ctx context.Context) ([]*domainDevice.ModelProfile, error) {
+	var dbModels []models.ModelProfileModel
+	if err := r.db.DB.WithContext(ctx).Order("model ASC").Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list device model profiles: %w", err)
+	}
+
+	profiles := make([]*domainDevice.ModelProfile, len(dbModels))
+	for i := range dbModels {
+		profiles[i] = toModelProfileEntity(&dbModels[i])
+	}
+	return profiles, nil
+}
+
+func (r *DeviceModelProfileRepository) Delete(ctx context.Context, model string) error {
+	result := r.db.DB.WithContext(ctx).
+		Delete(&models.ModelProfileModel{}, "model = ?", model)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete device model profile: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainDevice.ErrModelProfileNotFound
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toModelProfileModel(p *domainDevice.ModelProfile) *models.ModelProfileModel {
+	capabilities := make([]string, len(p.Capabilities))
+	for i, c := range p.Capabilities {
+		capabilities[i] = string(c)
+	}
+	return &models.ModelProfileModel{
+		Model:        p.Model,
+		Capabilities: capabilities,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+func toModelProfileEntity(m *models.ModelProfileModel) *domainDevice.ModelProfile {
+	return &domainDevice.ModelProfile{
+		Model:        m.Model,
+		Capabilities: toCapabilities(m),
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	domainDevice "cargo-tracker/internal/domain/device"
 	"cargo-tracker/internal/infrastructure/database/postgres/models"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -49,7 +50,7 @@ func (r *DeviceRepository) Create(ctx context.Context, d *domainDevice.Device) e
 func (r *DeviceRepository) GetByID(ctx context.Context, deviceID uuid.UUID) (*domainDevice.Device, error) {
 	var dbModel models.DeviceModel
 	err := r.db.DB.WithContext(ctx).
-		Preload("OwnerShipper").
+		Preload("Profile").
 		Where("id = ?", deviceID).
 		First(&dbModel).Error
 
@@ -66,7 +67,7 @@ func (r *DeviceRepository) GetByID(ctx context.Context, deviceID uuid.UUID) (*do
 func (r *DeviceRepository) GetByHardwareUID(ctx context.Context, hardwareUID string) (*domainDevice.Device, error) {
 	var dbModel models.DeviceModel
 	err := r.db.DB.WithContext(ctx).
-		Preload("OwnerShipper").
+		Preload("Profile").
 		Where("hardware_uid = ?", hardwareUID).
 		First(&dbModel).Error
 
@@ -191,6 +192,44 @@ func (r *DeviceRepository) UpdateLastSeen(ctx context.Context, deviceID uuid.UUI
 		}).Error
 }
 
+func (r *DeviceRepository) SetCredentialHash(ctx context.Context, deviceID uuid.UUID, hash string) error {
+	now := time.Now()
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.DeviceModel{}).
+		Where("id = ?", deviceID).
+		Updates(map[string]interface{}{
+			"credential_hash":      hash,
+			"credential_issued_at": now,
+			"updated_at":           now,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to set device credential: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainDevice.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
+func (r *DeviceRepository) GetByCredentialHash(ctx context.Context, hash string) (*domainDevice.Device, error) {
+	var dbModel models.DeviceModel
+	err := r.db.DB.WithContext(ctx).
+		Preload("Profile").
+		Where("credential_hash = ?", hash).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainDevice.ErrDeviceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+
+	return toDeviceEntity(&dbModel), nil
+}
+
 func (r *DeviceRepository) Delete(ctx context.Context, deviceID uuid.UUID) error {
 	result := r.db.DB.WithContext(ctx).
 		Model(&models.DeviceModel{}).
@@ -210,6 +249,29 @@ func (r *DeviceRepository) Delete(ctx context.Context, deviceID uuid.UUID) error
 	return nil
 }
 
+func (r *DeviceRepository) Anonymize(ctx context.Context, deviceID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.DeviceModel{}).
+		Where("id = ?", deviceID).
+		Updates(map[string]interface{}{
+			"status":              string(domainDevice.StatusRetired),
+			"owner_shipper_id":    nil,
+			"device_name":         nil,
+			"current_shipment_id": nil,
+			"battery_level":       nil,
+			"updated_at":          time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to anonymize device: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainDevice.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
 func (r *DeviceRepository) GetStatistics(ctx context.Context) (*domainDevice.Statistics, error) {
 	stats := &domainDevice.Statistics{}
 	err := r.db.DB.WithContext(ctx).Raw(`
@@ -251,7 +313,7 @@ func (r *DeviceRepository) List(ctx context.Context, filter *domainDevice.Filter
 	var total int64
 
 	db := r.db.DB.WithContext(ctx).Model(&models.DeviceModel{}).
-		Preload("OwnerShipper").
+		Preload("Profile").
 		Joins("LEFT JOIN users u ON devices.owner_shipper_id = u.id")
 
 	// Apply filters
@@ -274,6 +336,14 @@ func (r *DeviceRepository) List(ctx context.Context, filter *domainDevice.Filter
 		search := "%" + filter.Search + "%"
 		db = db.Where("devices.hardware_uid ILIKE ? OR devices.device_name ILIKE ?", search, search)
 	}
+	if len(filter.Capabilities) > 0 {
+		required, err := json.Marshal(filter.Capabilities)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode capabilities: %w", err)
+		}
+		db = db.Where("devices.model IN (?)", r.db.DB.Model(&models.ModelProfileModel{}).
+			Select("model").Where("capabilities @> ?::jsonb", string(required)))
+	}
 
 	// Count total
 	if err := db.Count(&total).Error; err != nil {
@@ -320,6 +390,25 @@ func (r *DeviceRepository) List(ctx context.Context, filter *domainDevice.Filter
 	return devices, total, nil
 }
 
+func (r *DeviceRepository) ListFleet(ctx context.Context, ownerID *uuid.UUID) ([]*domainDevice.Device, error) {
+	db := r.db.DB.WithContext(ctx).
+		Where("status <> ?", string(domainDevice.StatusRetired))
+	if ownerID != nil {
+		db = db.Where("owner_shipper_id = ?", *ownerID)
+	}
+
+	var dbModels []models.DeviceModel
+	if err := db.Order("hardware_uid ASC").Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list fleet: %w", err)
+	}
+
+	devices := make([]*domainDevice.Device, len(dbModels))
+	for i := range dbModels {
+		devices[i] = toDeviceEntity(&dbModels[i])
+	}
+	return devices, nil
+}
+
 // Helper functions to convert between domain entities and database models
 
 func toDeviceModel(d *domainDevice.Device) *models.DeviceModel {
@@ -343,18 +432,33 @@ func toDeviceModel(d *domainDevice.Device) *models.DeviceModel {
 func toDeviceEntity(m *models.DeviceModel) *domainDevice.Device {
 	status := domainDevice.DeviceStatus(m.Status)
 	return &domainDevice.Device{
-		ID:                m.ID,
-		HardwareUID:       m.HardwareUID,
-		DeviceName:        m.DeviceName,
-		Model:             m.Model,
-		OwnerShipperID:    m.OwnerShipperID,
-		CurrentShipmentID: m.CurrentShipmentID,
-		Status:            status,
-		FirmwareVersion:   m.FirmwareVersion,
-		BatteryLevel:      m.BatteryLevel,
-		TotalTrips:        m.TotalTrips,
-		LastSeenAt:        m.LastSeenAt,
-		CreatedAt:         m.CreatedAt,
-		UpdatedAt:         m.UpdatedAt,
+		ID:                 m.ID,
+		HardwareUID:        m.HardwareUID,
+		DeviceName:         m.DeviceName,
+		Model:              m.Model,
+		OwnerShipperID:     m.OwnerShipperID,
+		CurrentShipmentID:  m.CurrentShipmentID,
+		Status:             status,
+		Capabilities:       toCapabilities(m.Profile),
+		FirmwareVersion:    m.FirmwareVersion,
+		BatteryLevel:       m.BatteryLevel,
+		TotalTrips:         m.TotalTrips,
+		LastSeenAt:         m.LastSeenAt,
+		CredentialIssuedAt: m.CredentialIssuedAt,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+}
+
+// toCapabilities returns the capabilities of a preloaded profile, nil when
+// the device's model has none
+func toCapabilities(profile *models.ModelProfileModel) []domainDevice.Capability {
+	if profile == nil {
+		return nil
+	}
+	capabilities := make([]domainDevice.Capability, len(profile.Capabilities))
+	for i, c := range profile.Capabilities {
+		capabilities[i] = domainDevice.Capability(c)
 	}
+	return capabilities
 }
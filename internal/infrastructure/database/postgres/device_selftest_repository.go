@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeviceSelfTestRepository implements domain.Device.SelfTestRepository interface
+type DeviceSelfTestRepository struct {
+	db *DB
+}
+
+// NewDeviceSelfTestRepository creates a new device self-test repository
+func NewDeviceSelfTestRepository(db *DB) domainDevice.SelfTestRepository {
+	return &DeviceSelfTestRepository{db: db}
+}
+
+func (r *DeviceSelfTestRepository) Create(ctx context.Context, t *domainDevice.SelfTest) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+
+	if err := r.db.DB.WithContext(ctx).Create(toDeviceSelfTestModel(t)).Error; err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
+			return domainDevice.ErrSelfTestPending
+		}
+		return fmt.Errorf("failed to create device self-test: %w", err)
+	}
+	return nil
+}
+
+func (r *DeviceSelfTestRepository) GetPending(ctx context.Context, deviceID uuid.UUID) (*domainDevice.SelfTest, error) {
+	var dbModel models.DeviceSelfTestModel
+	err := r.db.DB.WithContext(ctx).
+		First(&dbModel, "device_id = ? AND status = ?", deviceID, string(domainDevice.SelfTestPending)).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainDevice.ErrSelfTestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending self-test: %w", err)
+	}
+
+	return toDeviceSelfTestEntity(&dbModel), nil
+}
+
+func (r *DeviceSelfTestRepository) Complete(ctx context.Context, t *domainDevice.SelfTest) error {
+	m := toDeviceSelfTestModel(t)
+
+	// Map updates bypass GORM serializers, so encode the JSON columns explicitly
+	readings, err := json.Marshal(m.Readings)
+	if err != nil {
+		return fmt.Errorf("failed to encode self-test readings: %w", err)
+	}
+	failures, err := json.Marshal(m.Failures)
+	if err != nil {
+		return fmt.Errorf("failed to encode self-test failures: %w", err)
+	}
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.DeviceSelfTestModel{}).
+		Where("id = ? AND status = ?", t.ID, string(domainDevice.SelfTestPending)).
+		Updates(map[string]interface{}{
+			"status":        m.Status,
+			"completed_at":  m.CompletedAt,
+			"sensors_ok":    m.SensorsOK,
+			"readings":      string(readings),
+			"gps_fix":       m.GPSFix,
+			"battery_level": m.BatteryLevel,
+			"signal_dbm":    m.SignalDBm,
+			"failures":      string(failures),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete device self-test: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainDevice.ErrSelfTestNotFound
+	}
+	return nil
+}
+
+func (r *DeviceSelfTestRepository) ListByDevice(ctx context.Context, deviceID uuid.UUID, limit int) ([]*domainDevice.SelfTest, error) {
+	var dbModels []models.DeviceSelfTestModel
+	if err := r.db.DB.WithContext(ctx).
+		Where("device_id = ?", deviceID).
+		Order("requested_at DESC").
+		Limit(limit).
+		Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list device self-tests: %w", err)
+	}
+
+	tests := make([]*domainDevice.SelfTest, len(dbModels))
+	for i := range dbModels {
+		tests[i] = toDeviceSelfTestEntity(&dbModels[i])
+	}
+	return tests, nil
+}
+
+func (r *DeviceSelfTestRepository) LastPassedAt(ctx context.Context, deviceID uuid.UUID) (*time.Time, error) {
+	var last *time.Time
+	if err := r.db.DB.WithContext(ctx).
+		Model(&models.DeviceSelfTestModel{}).
+		Select("MAX(completed_at)").
+		Where("device_id = ? AND status = ?", deviceID, string(domainDevice.SelfTestPassed)).
+		Scan(&last).Error; err != nil {
+		return nil, fmt.Errorf("failed to get last passed self-test: %w", err)
+	}
+	return last, nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toDeviceSelfTestModel(t *domainDevice.SelfTest) *models.DeviceSelfTestModel {
+	m := &models.DeviceSelfTestModel{
+		ID:           t.ID,
+		DeviceID:     t.DeviceID,
+		RequestedBy:  t.RequestedBy,
+		Status:       string(t.Status),
+		RequestedAt:  t.RequestedAt,
+		CompletedAt:  t.CompletedAt,
+		SensorsOK:    t.SensorsOK,
+		Readings:     t.Readings,
+		GPSFix:       t.GPSFix,
+		BatteryLevel: t.BatteryLevel,
+		SignalDBm:    t.SignalDBm,
+		Failures:     t.Failures,
+	}
+	if m.Readings == nil {
+		m.Readings = map[string]float64{}
+	}
+	if m.Failures == nil {
+		m.Failures = []string{}
+	}
+	return m
+}
+
+func toDeviceSelfTestEntity(m *models.DeviceSelfTestModel) *domainDevice.SelfTest {
+	return &domainDevice.SelfTest{
+		ID:           m.ID,
+		DeviceID:     m.DeviceID,
+		RequestedBy:  m.RequestedBy,
+		Status:       domainDevice.SelfTestStatus(m.Status),
+		RequestedAt:  m.RequestedAt,
+		CompletedAt:  m.CompletedAt,
+		SensorsOK:    m.SensorsOK,
+		Readings:     m.Readings,
+		GPSFix:       m.GPSFix,
+		BatteryLevel: m.BatteryLevel,
+		SignalDBm:    m.SignalDBm,
+		Failures:     m.Failures,
+	}
+}
@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// deviceOpenWork matches devices on an unfinished shipment or active trip
+const deviceOpenWork = `EXISTS (
+		SELECT 1 FROM shipments s
+		WHERE s.linked_device_id = @device
+		  AND s.status NOT IN ('completed', 'partially_completed', 'cancelled')
+	) OR EXISTS (
+		SELECT 1 FROM trips t WHERE t.device_id = @device AND t.status = 'active'
+	)`
+
+// DeviceTransferRepository implements domain.Device.TransferRepository interface
+type DeviceTransferRepository struct {
+	db *DB
+}
+
+// NewDeviceTransferRepository creates a new device transfer repository
+func NewDeviceTransferRepository(db *DB) domainDevice.TransferRepository {
+	return &DeviceTransferRepository{db: db}
+}
+
+func (r *DeviceTransferRepository) Create(ctx context.Context, t *domainDevice.Transfer) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+
+	if err := r.db.DB.WithContext(ctx).Create(toDeviceTransferModel(t)).Error; err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
+			return domainDevice.ErrTransferPending
+		}
+		return fmt.Errorf("failed to create device transfer: %w", err)
+	}
+	return nil
+}
+
+func (r *DeviceTransferRepository) GetByID(ctx context.Context, transferID uuid.UUID) (*domainDevice.Transfer, error) {
+	var dbModel models.DeviceTransferModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", transferID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainDevice.ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device transfer: %w", err)
+	}
+
+	return toDeviceTransferEntity(&dbModel), nil
+}
+
+func (r *DeviceTransferRepository) ListByShipper(ctx context.Context, shipperID uuid.UUID) ([]*domainDevice.Transfer, error) {
+	var dbModels []models.DeviceTransferModel
+	if err := r.db.DB.WithContext(ctx).
+		Where("from_shipper_id = ? OR to_shipper_id = ?", shipperID, shipperID).
+		Order("created_at DESC").
+		Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list device transfers: %w", err)
+	}
+
+	transfers := make([]*domainDevice.Transfer, len(dbModels))
+	for i := range dbModels {
+		transfers[i] = toDeviceTransferEntity(&dbModels[i])
+	}
+	return transfers, nil
+}
+
+func (r *DeviceTransferRepository) HasOpenWork(ctx context.Context, deviceID uuid.UUID) (bool, error) {
+	var busy bool
+	if err := r.db.DB.WithContext(ctx).
+		Raw("SELECT "+deviceOpenWork, map[string]interface{}{"device": deviceID}).
+		Scan(&busy).Error; err != nil {
+		return false, fmt.Errorf("failed to check device work: %w", err)
+	}
+	return busy, nil
+}
+
+func (r *DeviceTransferRepository) Accept(ctx context.Context, transferID uuid.UUID, at time.Time) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var transfer models.DeviceTransferModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&transfer, "id = ?", transferID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainDevice.ErrTransferNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock device transfer: %w", err)
+		}
+		if transfer.Status != string(domainDevice.TransferPending) {
+			return domainDevice.ErrTransferNotPending
+		}
+
+		// Lock the device so no shipment or trip picks it up meanwhile
+		var device models.DeviceModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&device, "id = ?", transfer.DeviceID).Error; err != nil {
+			return fmt.Errorf("failed to lock device: %w", err)
+		}
+		if device.OwnerShipperID == nil || *device.OwnerShipperID != transfer.FromShipperID ||
+			device.Status == string(domainDevice.StatusRetired) {
+			return domainDevice.ErrTransferNotPending
+		}
+
+		var busy bool
+		if err := tx.Raw("SELECT "+deviceOpenWork, map[string]interface{}{"device": device.ID}).
+			Scan(&busy).Error; err != nil {
+			return fmt.Errorf("failed to check device work: %w", err)
+		}
+		if busy || device.CurrentShipmentID != nil || device.Status == string(domainDevice.StatusInTransit) {
+			return domainDevice.ErrDeviceInUse
+		}
+
+		if err := tx.Model(&models.DeviceModel{}).
+			Where("id = ?", device.ID).
+			Updates(map[string]interface{}{
+				"owner_shipper_id": transfer.ToShipperID,
+				"updated_at":       at,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to move device owner: %w", err)
+		}
+
+		if err := tx.Model(&models.DeviceTransferModel{}).
+			Where("id = ?", transfer.ID).
+			Updates(map[string]interface{}{
+				"status":       string(domainDevice.TransferAccepted),
+				"responded_at": at,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to accept device transfer: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *DeviceTransferRepository) Close(ctx context.Context, transferID uuid.UUID, status domainDevice.TransferStatus, at time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.DeviceTransferModel{}).
+		Where("id = ? AND status = ?", transferID, string(domainDevice.TransferPending)).
+		Updates(map[string]interface{}{
+			"status":       string(status),
+			"responded_at": at,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to close device transfer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainDevice.ErrTransferNotPending
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toDeviceTransferModel(t *domainDevice.Transfer) *models.DeviceTransferModel {
+	return &models.DeviceTransferModel{
+		ID:            t.ID,
+		DeviceID:      t.DeviceID,
+		FromShipperID: t.FromShipperID,
+		ToShipperID:   t.ToShipperID,
+		Status:        string(t.Status),
+		Note:          t.Note,
+		CreatedAt:     t.CreatedAt,
+		RespondedAt:   t.RespondedAt,
+	}
+}
+
+func toDeviceTransferEntity(m *models.DeviceTransferModel) *domainDevice.Transfer {
+	return &domainDevice.Transfer{
+		ID:            m.ID,
+		DeviceID:      m.DeviceID,
+		FromShipperID: m.FromShipperID,
+		ToShipperID:   m.ToShipperID,
+		Status:        domainDevice.TransferStatus(m.Status),
+		Note:          m.Note,
+		CreatedAt:     m.CreatedAt,
+		RespondedAt:   m.RespondedAt,
+	}
+}
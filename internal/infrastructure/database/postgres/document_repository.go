@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	domainDocument "cargo-tracker/internal/domain/document"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DocumentRepository implements domain.Document.Repository interface
+type DocumentRepository struct {
+	db *DB
+}
+
+// NewDocumentRepository creates a new document repository
+func NewDocumentRepository(db *DB) domainDocument.Repository {
+	return &DocumentRepository{db: db}
+}
+
+func (r *DocumentRepository) ReplaceChecklist(ctx context.Context, shipmentID uuid.UUID, items []*domainDocument.ChecklistItem) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shipment_id = ?", shipmentID).Delete(&models.DocumentChecklistItemModel{}).Error; err != nil {
+			return fmt.Errorf("failed to clear checklist: %w", err)
+		}
+
+		now := time.Now()
+		for _, item := range items {
+			item.ID = uuid.New()
+			item.ShipmentID = shipmentID
+			item.CreatedAt = now
+			item.UpdatedAt = now
+
+			if err := tx.Create(toChecklistItemModel(item)).Error; err != nil {
+				return fmt.Errorf("failed to create checklist item: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *DocumentRepository) GetChecklist(ctx context.Context, shipmentID uuid.UUID) ([]*domainDocument.ChecklistItem, error) {
+	var dbModels []models.DocumentChecklistItemModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Order("created_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist: %w", err)
+	}
+
+	items := make([]*domainDocument.ChecklistItem, len(dbModels))
+	for i := range dbModels {
+		items[i] = toChecklistItemEntity(&dbModels[i])
+	}
+	return items, nil
+}
+
+func (r *DocumentRepository) GetChecklistItem(ctx context.Context, itemID uuid.UUID) (*domainDocument.ChecklistItem, error) {
+	var dbModel models.DocumentChecklistItemModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", itemID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainDocument.ErrChecklistItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist item: %w", err)
+	}
+
+	return toChecklistItemEntity(&dbModel), nil
+}
+
+func (r *DocumentRepository) UpdateChecklistItem(ctx context.Context, item *domainDocument.ChecklistItem) error {
+	item.UpdatedAt = time.Now()
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.DocumentChecklistItemModel{}).
+		Where("id = ?", item.ID).
+		Updates(map[string]interface{}{
+			"status":        string(item.Status),
+			"attachment_id": item.AttachmentID,
+			"reviewed_by":   item.ReviewedBy,
+			"reviewed_at":   item.ReviewedAt,
+			"review_note":   item.ReviewNote,
+			"updated_at":    item.UpdatedAt,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update checklist item: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainDocument.ErrChecklistItemNotFound
+	}
+	return nil
+}
+
+func (r *DocumentRepository) CountIncompleteRequired(ctx context.Context, shipmentID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.DocumentChecklistItemModel{}).
+		Where("shipment_id = ? AND required = ? AND status <> ?", shipmentID, true, string(domainDocument.ChecklistVerified)).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count checklist items: %w", err)
+	}
+	return count, nil
+}
+
+func (r *DocumentRepository) CreateAttachment(ctx context.Context, a *domainDocument.Attachment) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	a.CreatedAt = time.Now()
+
+	if err := r.db.DB.WithContext(ctx).Create(toAttachmentModel(a)).Error; err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentRepository) GetAttachment(ctx context.Context, attachmentID uuid.UUID) (*domainDocument.Attachment, error) {
+	var dbModel models.AttachmentModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", attachmentID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainDocument.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return toAttachmentEntity(&dbModel), nil
+}
+
+func (r *DocumentRepository) ListAttachments(ctx context.Context, shipmentID uuid.UUID) ([]*domainDocument.Attachment, error) {
+	var dbModels []models.AttachmentModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Order("created_at DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	attachments := make([]*domainDocument.Attachment, len(dbModels))
+	for i := range dbModels {
+		attachments[i] = toAttachmentEntity(&dbModels[i])
+	}
+	return attachments, nil
+}
+
+func (r *DocumentRepository) UpdateAttachmentVisibility(ctx context.Context, a *domainDocument.Attachment) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.AttachmentModel{}).
+		Where("id = ?", a.ID).
+		Updates(map[string]interface{}{
+			"visible_to_customer": a.Visibility.Customer,
+			"visible_to_provider": a.Visibility.Provider,
+			"visible_to_shipper":  a.Visibility.Shipper,
+			"visible_to_admin":    a.Visibility.Admin,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update attachment visibility: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainDocument.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toChecklistItemModel(i *domainDocument.ChecklistItem) *models.DocumentChecklistItemModel {
+	return &models.DocumentChecklistItemModel{
+		ID:           i.ID,
+		ShipmentID:   i.ShipmentID,
+		DocumentType: string(i.DocumentType),
+		Label:        i.Label,
+		Required:     i.Required,
+		Status:       string(i.Status),
+		AttachmentID: i.AttachmentID,
+		ReviewedBy:   i.ReviewedBy,
+		ReviewedAt:   i.ReviewedAt,
+		ReviewNote:   i.ReviewNote,
+		CreatedAt:    i.CreatedAt,
+		UpdatedAt:    i.UpdatedAt,
+	}
+}
+
+func toChecklistItemEntity(m *models.DocumentChecklistItemModel) *domainDocument.ChecklistItem {
+	return &domainDocument.ChecklistItem{
+		ID:           m.ID,
+		ShipmentID:   m.ShipmentID,
+		DocumentType: domainDocument.DocumentType(m.DocumentType),
+		Label:        m.Label,
+		Required:     m.Required,
+		Status:       domainDocument.ChecklistStatus(m.Status),
+		AttachmentID: m.AttachmentID,
+		ReviewedBy:   m.ReviewedBy,
+		ReviewedAt:   m.ReviewedAt,
+		ReviewNote:   m.ReviewNote,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+func toAttachmentModel(a *domainDocument.Attachment) *models.AttachmentModel {
+	return &models.AttachmentModel{
+		ID:              a.ID,
+		ShipmentID:      a.ShipmentID,
+		ChecklistItemID: a.ChecklistItemID,
+		DocumentType:    string(a.DocumentType),
+		FileName:        a.FileName,
+		ContentType:     a.ContentType,
+		SizeBytes:       a.SizeBytes,
+		StorageKey:      a.StorageKey,
+		UploadedBy:      a.UploadedBy,
+		VisibleCustomer: a.Visibility.Customer,
+		VisibleProvider: a.Visibility.Provider,
+		VisibleShipper:  a.Visibility.Shipper,
+		VisibleAdmin:    a.Visibility.Admin,
+		CreatedAt:       a.CreatedAt,
+	}
+}
+
+func toAttachmentEntity(m *models.AttachmentModel) *domainDocument.Attachment {
+	return &domainDocument.Attachment{
+		ID:              m.ID,
+		ShipmentID:      m.ShipmentID,
+		ChecklistItemID: m.ChecklistItemID,
+		DocumentType:    domainDocument.DocumentType(m.DocumentType),
+		FileName:        m.FileName,
+		ContentType:     m.ContentType,
+		SizeBytes:       m.SizeBytes,
+		StorageKey:      m.StorageKey,
+		UploadedBy:      m.UploadedBy,
+		Visibility: domainDocument.Visibility{
+			Customer: m.VisibleCustomer,
+			Provider: m.VisibleProvider,
+			Shipper:  m.VisibleShipper,
+			Admin:    m.VisibleAdmin,
+		},
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/infrastructure/encryption"
+	"cargo-tracker/internal/logger"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// encryptedColumn is a column whose model field uses the encrypted serializer.
+type encryptedColumn struct {
+	table  string
+	column string
+	// indexColumn, when set, receives the blind index of the plaintext
+	indexColumn string
+}
+
+var encryptedColumns = []encryptedColumn{
+	{table: "users", column: "phone_number", indexColumn: "phone_number_hash"},
+	{table: "users", column: "address"},
+	{table: "shipments", column: "proof_of_delivery_url"},
+	{table: "saved_addresses", column: "contact_phone"},
+	{table: "notification_webhooks", column: "url"},
+	{table: "notification_webhooks", column: "signing_secret"},
+	{table: "notification_webhooks", column: "previous_signing_secret"},
+}
+
+// binding names the cell of this column in the given row.
+func (c encryptedColumn) binding(id uuid.UUID) encryption.Binding {
+	return encryption.Binding{Table: c.table, Column: c.column, RowID: id.String()}
+}
+
+// RotateEncryptedColumns re-encrypts every value that is still plaintext, in
+// the legacy envelope or sealed with a retired key so that it uses the active
+// key. It is safe to run repeatedly and alongside the application, and returns
+// the number of values rewritten.
+func (d *DB) RotateEncryptedColumns(ctx context.Context, keyring *encryption.Keyring, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	var total int64
+	for _, col := range encryptedColumns {
+		count, err := d.rotateColumn(ctx, keyring, col, batchSize)
+		total += count
+		if err != nil {
+			return total, err
+		}
+
+		logger.Info("Encrypted column rotated",
+			zap.String("table", col.table),
+			zap.String("column", col.column),
+			zap.Int64("rows", count),
+			zap.String("event", "encryption_column_rotated"),
+		)
+	}
+
+	return total, nil
+}
+
+func (d *DB) rotateColumn(ctx context.Context, keyring *encryption.Keyring, col encryptedColumn, batchSize int) (int64, error) {
+	type row struct {
+		ID    uuid.UUID
+		Value string
+	}
+
+	// Key ids may contain LIKE wildcards
+	activePrefix := escapeLike(keyring.ActivePrefix()) + "%"
+	selectSQL := fmt.Sprintf(
+		"SELECT id, %[2]s AS value FROM %[1]s WHERE %[2]s IS NOT NULL AND %[2]s NOT LIKE ? AND id > ? ORDER BY id LIMIT ?",
+		col.table, col.column,
+	)
+	updateWhere := fmt.Sprintf("id = ? AND %s = ?", col.column)
+
+	var (
+		count  int64
+		lastID uuid.UUID
+	)
+	for {
+		var rows []row
+		if err := d.DB.WithContext(ctx).Raw(selectSQL, activePrefix, lastID, batchSize).Scan(&rows).Error; err != nil {
+			return count, fmt.Errorf("failed to read %s.%s: %w", col.table, col.column, err)
+		}
+		if len(rows) == 0 {
+			return count, nil
+		}
+
+		for _, r := range rows {
+			lastID = r.ID
+			binding := col.binding(r.ID)
+
+			plaintext, err := keyring.Decrypt(r.Value, binding)
+			if err != nil {
+				return count, fmt.Errorf("failed to decrypt %s.%s for %s: %w", col.table, col.column, r.ID, err)
+			}
+			encrypted, err := keyring.Encrypt(plaintext, binding)
+			if err != nil {
+				return count, err
+			}
+
+			updates := map[string]interface{}{col.column: encrypted}
+			if index := keyring.BlindIndex(plaintext); col.indexColumn != "" && index != "" {
+				updates[col.indexColumn] = index
+			}
+			// Only replace the value that was read: if the application wrote
+			// the cell in the meantime, its value is newer and already sealed
+			// with the active key
+			result := d.DB.WithContext(ctx).Table(col.table).Where(updateWhere, r.ID, r.Value).UpdateColumns(updates)
+			if result.Error != nil {
+				return count, fmt.Errorf("failed to update %s.%s for %s: %w", col.table, col.column, r.ID, result.Error)
+			}
+			count += result.RowsAffected
+		}
+	}
+}
+
+// BackfillBlindIndexes sets the blind index of every value that has none,
+// such as rows written before encryption was enabled. It is safe to run
+// repeatedly and returns the number of rows updated.
+func (d *DB) BackfillBlindIndexes(ctx context.Context, keyring *encryption.Keyring, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	var total int64
+	for _, col := range encryptedColumns {
+		if col.indexColumn == "" {
+			continue
+		}
+		count, err := d.backfillBlindIndex(ctx, keyring, col, batchSize)
+		total += count
+		if err != nil {
+			return total, err
+		}
+		if count > 0 {
+			logger.Info("Blind index backfilled",
+				zap.String("table", col.table),
+				zap.String("column", col.indexColumn),
+				zap.Int64("rows", count),
+				zap.String("event", "blind_index_backfilled"),
+			)
+		}
+	}
+
+	return total, nil
+}
+
+func (d *DB) backfillBlindIndex(ctx context.Context, keyring *encryption.Keyring, col encryptedColumn, batchSize int) (int64, error) {
+	type row struct {
+		ID    uuid.UUID
+		Value string
+	}
+
+	selectSQL := fmt.Sprintf(
+		"SELECT id, %[2]s AS value FROM %[1]s WHERE %[2]s IS NOT NULL AND %[3]s IS NULL AND id > ? ORDER BY id LIMIT ?",
+		col.table, col.column, col.indexColumn,
+	)
+	updateWhere := fmt.Sprintf("id = ? AND %s = ?", col.column)
+
+	var (
+		count  int64
+		lastID uuid.UUID
+	)
+	for {
+		var rows []row
+		if err := d.DB.WithContext(ctx).Raw(selectSQL, lastID, batchSize).Scan(&rows).Error; err != nil {
+			return count, fmt.Errorf("failed to read %s.%s: %w", col.table, col.column, err)
+		}
+		if len(rows) == 0 {
+			return count, nil
+		}
+
+		for _, r := range rows {
+			lastID = r.ID
+
+			plaintext, err := keyring.Decrypt(r.Value, col.binding(r.ID))
+			if err != nil {
+				return count, fmt.Errorf("failed to decrypt %s.%s for %s: %w", col.table, col.column, r.ID, err)
+			}
+			// The index is only valid for the value it was computed from
+			result := d.DB.WithContext(ctx).Table(col.table).Where(updateWhere, r.ID, r.Value).
+				UpdateColumn(col.indexColumn, keyring.BlindIndex(plaintext))
+			if result.Error != nil {
+				return count, fmt.Errorf("failed to update %s.%s for %s: %w", col.table, col.indexColumn, r.ID, result.Error)
+			}
+			count += result.RowsAffected
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/infrastructure/encryption"
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func testKeyring(t *testing.T, activeID string) *encryption.Keyring {
+	t.Helper()
+	key := make([]byte, 32)
+	k, err := encryption.NewKeyring(activeID, map[string][]byte{activeID: key}, key)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return k
+}
+
+// sealedFor matches a value that decrypts to plaintext in the given cell
+type sealedFor struct {
+	keyring   *encryption.Keyring
+	binding   encryption.Binding
+	plaintext string
+}
+
+func (s sealedFor) Match(v driver.Value) bool {
+	sealed, ok := v.(string)
+	if !ok || s.keyring.NeedsRotation(sealed) {
+		return false
+	}
+	plaintext, err := s.keyring.Decrypt(sealed, s.binding)
+	return err == nil && plaintext == s.plaintext
+}
+
+func TestRotateColumnOnlyReplacesTheValueItRead(t *testing.T) {
+	db, mock := newMockDB(t)
+	// The underscore is a LIKE wildcard and must not match other key ids
+	keyring := testKeyring(t, "key_2")
+	col := encryptedColumn{table: "users", column: "address"}
+
+	rotated, raced := uuid.New(), uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, address AS value FROM users WHERE address IS NOT NULL AND address NOT LIKE $1 AND id > $2`)).
+		WithArgs(`enc:v2:key\_2:%`, uuid.Nil, 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).
+			AddRow(rotated, "12 Ly Thuong Kiet").
+			AddRow(raced, "34 Tran Hung Dao"))
+
+	updateSQL := regexp.QuoteMeta(`UPDATE "users" SET "address"=$1 WHERE id = $2 AND address = $3`)
+	mock.ExpectBegin()
+	mock.ExpectExec(updateSQL).
+		WithArgs(sealedFor{keyring, col.binding(rotated), "12 Ly Thuong Kiet"}, rotated, "12 Ly Thuong Kiet").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// The application rewrote the second address after it was read
+	mock.ExpectBegin()
+	mock.ExpectExec(updateSQL).
+		WithArgs(sqlmock.AnyArg(), raced, "34 Tran Hung Dao").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(`SELECT id, address AS value FROM users`).
+		WithArgs(`enc:v2:key\_2:%`, raced, 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "value"}))
+
+	count, err := db.rotateColumn(context.Background(), keyring, col, 500)
+	if err != nil {
+		t.Fatalf("rotateColumn: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("rotateColumn counted %d rows, want 1: the raced row was not rewritten", count)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
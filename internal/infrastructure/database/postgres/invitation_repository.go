@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationRepository implements domain.User.InvitationRepository interface
+type InvitationRepository struct {
+	db *DB
+}
+
+// NewInvitationRepository creates a new invitation repository
+func NewInvitationRepository(db *DB) user.InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+func (r *InvitationRepository) Create(ctx context.Context, invitation *user.Invitation) error {
+	invitation.ID = uuid.New()
+	invitation.CreatedAt = time.Now()
+
+	if err := r.db.DB.WithContext(ctx).Create(toInvitationModel(invitation)).Error; err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *InvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.Invitation, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *InvitationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*user.Invitation, error) {
+	return r.first(ctx, "token_hash = ?", tokenHash)
+}
+
+func (r *InvitationRepository) first(ctx context.Context, query string, args ...interface{}) (*user.Invitation, error) {
+	var dbModel models.InvitationModel
+	err := r.db.DB.WithContext(ctx).Where(query, args...).First(&dbModel).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	return toInvitationEntity(&dbModel), nil
+}
+
+func (r *InvitationRepository) List(ctx context.Context, pendingOnly bool, limit, offset int) ([]*user.Invitation, int64, error) {
+	query := r.db.DB.WithContext(ctx).Model(&models.InvitationModel{})
+	if pendingOnly {
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", time.Now())
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count invitations: %w", err)
+	}
+
+	var dbModels []models.InvitationModel
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&dbModels).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	invitations := make([]*user.Invitation, len(dbModels))
+	for i := range dbModels {
+		invitations[i] = toInvitationEntity(&dbModels[i])
+	}
+	return invitations, total, nil
+}
+
+func (r *InvitationRepository) Accept(ctx context.Context, id, userID uuid.UUID, at time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.InvitationModel{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"accepted_at": at,
+			"accepted_by": userID,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to accept invitation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrInvitationUsed
+	}
+	return nil
+}
+
+func (r *InvitationRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.InvitationModel{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrInvitationUsed
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toInvitationModel(i *user.Invitation) *models.InvitationModel {
+	return &models.InvitationModel{
+		ID:         i.ID,
+		Email:      i.Email,
+		Role:       i.Role,
+		TokenHash:  i.TokenHash,
+		InvitedBy:  i.InvitedBy,
+		ExpiresAt:  i.ExpiresAt,
+		AcceptedAt: i.AcceptedAt,
+		AcceptedBy: i.AcceptedBy,
+		RevokedAt:  i.RevokedAt,
+		CreatedAt:  i.CreatedAt,
+	}
+}
+
+func toInvitationEntity(m *models.InvitationModel) *user.Invitation {
+	return &user.Invitation{
+		ID:         m.ID,
+		Email:      m.Email,
+		Role:       m.Role,
+		TokenHash:  m.TokenHash,
+		InvitedBy:  m.InvitedBy,
+		ExpiresAt:  m.ExpiresAt,
+		AcceptedAt: m.AcceptedAt,
+		AcceptedBy: m.AcceptedBy,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
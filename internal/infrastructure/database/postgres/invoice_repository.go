@@ -0,0 +1,349 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/invoice"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvoiceRepository implements domain.Invoice.Repository interface
+type InvoiceRepository struct {
+	db *DB
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *DB) invoice.Repository {
+	return &InvoiceRepository{db: db}
+}
+
+func (r *InvoiceRepository) ListBillable(ctx context.Context, from, to time.Time) ([]*invoice.BillableShipment, error) {
+	var rows []struct {
+		ShipmentID       uuid.UUID
+		CustomerID       uuid.UUID
+		ProviderID       uuid.UUID
+		Status           string
+		GoodsDescription string
+		Price            *float64
+		Currency         *string
+		DeliveryDueAt    *time.Time
+		ActualDeliveryAt *time.Time
+		FinishedAt       time.Time
+	}
+
+	// Delivered shipments are billed in the period they were delivered;
+	// shipments cancelled after a shipper accepted them in the period they
+	// were cancelled. Sandbox shipments are never billed.
+	err := r.db.DB.WithContext(ctx).Raw(`
+		SELECT s.id AS shipment_id, s.customer_id, s.provider_id, s.status, s.goods_description,
+		       q.price, q.currency, s.delivery_due_at, s.actual_delivery_at,
+		       COALESCE(s.actual_delivery_at, s.updated_at) AS finished_at
+		FROM shipments s
+		LEFT JOIN quote_requests qr ON qr.shipment_id = s.id
+		LEFT JOIN quotes q ON q.request_id = qr.id AND q.status = 'accepted' AND q.provider_id = s.provider_id
+		WHERE s.is_sandbox = FALSE
+		  AND (
+		        (s.status IN ('completed', 'partially_completed') AND s.actual_delivery_at >= ? AND s.actual_delivery_at < ?)
+		     OR (s.status = 'cancelled' AND s.shipper_id IS NOT NULL AND s.updated_at >= ? AND s.updated_at < ?)
+		  )
+		  AND NOT EXISTS (
+		        SELECT 1 FROM invoice_lines l
+		        WHERE l.shipment_id = s.id AND l.kind IN ('shipment_fee', 'cancellation_fee')
+		  )
+		ORDER BY s.provider_id, s.customer_id, finished_at`,
+		from, to, from, to).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list billable shipments: %w", err)
+	}
+
+	billable := make([]*invoice.BillableShipment, len(rows))
+	for i, row := range rows {
+		billable[i] = &invoice.BillableShipment{
+			ShipmentID:       row.ShipmentID,
+			CustomerID:       row.CustomerID,
+			ProviderID:       row.ProviderID,
+			Status:           row.Status,
+			GoodsDescription: row.GoodsDescription,
+			Price:            row.Price,
+			Currency:         row.Currency,
+			DeliveryDueAt:    row.DeliveryDueAt,
+			ActualDeliveryAt: row.ActualDeliveryAt,
+			FinishedAt:       row.FinishedAt,
+		}
+	}
+	return billable, nil
+}
+
+func (r *InvoiceRepository) Create(ctx context.Context, inv *invoice.Invoice) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if inv.ID == uuid.Nil {
+			inv.ID = uuid.New()
+		}
+		inv.CreatedAt = now
+		inv.UpdatedAt = now
+
+		if err := tx.Omit("Lines").Create(toInvoiceModel(inv)).Error; err != nil {
+			if strings.Contains(err.Error(), "duplicate key") {
+				return invoice.ErrInvoiceExists
+			}
+			return fmt.Errorf("failed to create invoice: %w", err)
+		}
+
+		for i := range inv.Lines {
+			line := &inv.Lines[i]
+			line.ID = uuid.New()
+			line.InvoiceID = inv.ID
+			line.CreatedAt = now
+			if err := tx.Create(toInvoiceLineModel(line)).Error; err != nil {
+				if strings.Contains(err.Error(), "duplicate key") {
+					// Billed concurrently by another run
+					return invoice.ErrInvoiceExists
+				}
+				return fmt.Errorf("failed to create invoice line: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *InvoiceRepository) GetByID(ctx context.Context, invoiceID uuid.UUID) (*invoice.Invoice, error) {
+	var dbModel models.InvoiceModel
+	err := r.withLines(r.db.DB.WithContext(ctx)).
+		First(&dbModel, "id = ?", invoiceID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, invoice.ErrInvoiceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	return toInvoiceEntity(&dbModel), nil
+}
+
+func (r *InvoiceRepository) List(ctx context.Context, filter *invoice.Filter) ([]*invoice.Invoice, int64, error) {
+	db := r.db.DB.WithContext(ctx).Model(&models.InvoiceModel{})
+	if filter.ProviderID != nil {
+		db = db.Where("provider_id = ?", *filter.ProviderID)
+	}
+	if filter.CustomerID != nil {
+		db = db.Where("customer_id = ?", *filter.CustomerID)
+	}
+	if filter.Status != nil {
+		db = db.Where("status = ?", string(*filter.Status))
+	}
+	if filter.PeriodStart != nil {
+		db = db.Where("period_start = ?", *filter.PeriodStart)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	var dbModels []models.InvoiceModel
+	err := db.Order("period_start DESC, number").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	invoices := make([]*invoice.Invoice, len(dbModels))
+	for i := range dbModels {
+		invoices[i] = toInvoiceEntity(&dbModels[i])
+	}
+	return invoices, total, nil
+}
+
+func (r *InvoiceRepository) AddLine(ctx context.Context, inv *invoice.Invoice, line *invoice.InvoiceLine) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		line.ID = uuid.New()
+		line.InvoiceID = inv.ID
+		line.CreatedAt = now
+
+		if err := tx.Create(toInvoiceLineModel(line)).Error; err != nil {
+			return fmt.Errorf("failed to create invoice line: %w", err)
+		}
+
+		// Only open invoices take new lines
+		result := tx.Model(&models.InvoiceModel{}).
+			Where("id = ? AND status = ?", inv.ID, string(invoice.StatusIssued)).
+			Updates(map[string]interface{}{
+				"subtotal":    inv.Subtotal,
+				"adjustments": inv.Adjustments,
+				"total":       inv.Total,
+				"updated_at":  now,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update invoice totals: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return invoice.ErrInvoiceClosed
+		}
+		inv.UpdatedAt = now
+		return nil
+	})
+}
+
+func (r *InvoiceRepository) UpdateStatus(ctx context.Context, inv *invoice.Invoice) error {
+	inv.UpdatedAt = time.Now()
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.InvoiceModel{}).
+		Where("id = ?", inv.ID).
+		Updates(map[string]interface{}{
+			"status":            string(inv.Status),
+			"paid_at":           inv.PaidAt,
+			"payment_reference": inv.PaymentReference,
+			"updated_at":        inv.UpdatedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update invoice status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return invoice.ErrInvoiceNotFound
+	}
+	return nil
+}
+
+func (r *InvoiceRepository) ListShipperDeliveries(ctx context.Context, shipperID uuid.UUID, from, to time.Time) ([]*invoice.ShipperDelivery, error) {
+	var rows []struct {
+		ShipmentID       uuid.UUID
+		ShipperID        uuid.UUID
+		Status           string
+		GoodsDescription string
+		Price            *float64
+		Currency         *string
+		DeliveryDueAt    *time.Time
+		ActualDeliveryAt time.Time
+		CustomerRating   *int
+	}
+
+	err := r.db.DB.WithContext(ctx).Raw(`
+		SELECT s.id AS shipment_id, s.shipper_id, s.status, s.goods_description,
+		       q.price, q.currency, s.delivery_due_at, s.actual_delivery_at, s.customer_rating
+		FROM shipments s
+		LEFT JOIN quote_requests qr ON qr.shipment_id = s.id
+		LEFT JOIN quotes q ON q.request_id = qr.id AND q.status = 'accepted' AND q.provider_id = s.provider_id
+		WHERE s.shipper_id = ?
+		  AND s.is_sandbox = FALSE
+		  AND s.status IN ('completed', 'partially_completed')
+		  AND s.actual_delivery_at >= ? AND s.actual_delivery_at < ?
+		ORDER BY s.actual_delivery_at`,
+		shipperID, from, to).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipper deliveries: %w", err)
+	}
+
+	deliveries := make([]*invoice.ShipperDelivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = &invoice.ShipperDelivery{
+			ShipmentID:       row.ShipmentID,
+			ShipperID:        row.ShipperID,
+			Status:           row.Status,
+			GoodsDescription: row.GoodsDescription,
+			Price:            row.Price,
+			Currency:         row.Currency,
+			DeliveryDueAt:    row.DeliveryDueAt,
+			ActualDeliveryAt: row.ActualDeliveryAt,
+			CustomerRating:   row.CustomerRating,
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *InvoiceRepository) withLines(db *gorm.DB) *gorm.DB {
+	return db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC, kind ASC")
+	})
+}
+
+// Helper functions to convert between domain entities and database models
+func toInvoiceModel(i *invoice.Invoice) *models.InvoiceModel {
+	return &models.InvoiceModel{
+		ID:               i.ID,
+		Number:           i.Number,
+		ProviderID:       i.ProviderID,
+		CustomerID:       i.CustomerID,
+		PeriodStart:      i.PeriodStart,
+		PeriodEnd:        i.PeriodEnd,
+		Currency:         i.Currency,
+		Subtotal:         i.Subtotal,
+		Adjustments:      i.Adjustments,
+		Total:            i.Total,
+		Status:           string(i.Status),
+		DueAt:            i.DueAt,
+		PaidAt:           i.PaidAt,
+		PaymentReference: i.PaymentReference,
+		CreatedAt:        i.CreatedAt,
+		UpdatedAt:        i.UpdatedAt,
+	}
+}
+
+func toInvoiceLineModel(l *invoice.InvoiceLine) *models.InvoiceLineModel {
+	return &models.InvoiceLineModel{
+		ID:          l.ID,
+		InvoiceID:   l.InvoiceID,
+		ShipmentID:  l.ShipmentID,
+		Kind:        string(l.Kind),
+		Description: l.Description,
+		Amount:      l.Amount,
+		Priced:      l.Priced,
+		CreatedAt:   l.CreatedAt,
+	}
+}
+
+func toInvoiceEntity(m *models.InvoiceModel) *invoice.Invoice {
+	inv := &invoice.Invoice{
+		ID:               m.ID,
+		Number:           m.Number,
+		ProviderID:       m.ProviderID,
+		CustomerID:       m.CustomerID,
+		PeriodStart:      m.PeriodStart,
+		PeriodEnd:        m.PeriodEnd,
+		Currency:         m.Currency,
+		Subtotal:         m.Subtotal,
+		Adjustments:      m.Adjustments,
+		Total:            m.Total,
+		Status:           invoice.Status(m.Status),
+		DueAt:            m.DueAt,
+		PaidAt:           m.PaidAt,
+		PaymentReference: m.PaymentReference,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+		Lines:            make([]invoice.InvoiceLine, len(m.Lines)),
+	}
+	for i, l := range m.Lines {
+		inv.Lines[i] = invoice.InvoiceLine{
+			ID:          l.ID,
+			InvoiceID:   l.InvoiceID,
+			ShipmentID:  l.ShipmentID,
+			Kind:        invoice.LineKind(l.Kind),
+			Description: l.Description,
+			Amount:      l.Amount,
+			Priced:      l.Priced,
+			CreatedAt:   l.CreatedAt,
+		}
+	}
+	return inv
+}
@@ -0,0 +1,272 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/job"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// abandonedJobError is recorded on jobs whose worker stopped on the last
+// allowed attempt
+const abandonedJobError = "worker stopped before the job finished"
+
+// JobRepository implements domain.Job.Repository interface
+type JobRepository struct {
+	db *DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *DB) job.Repository {
+	return &JobRepository{db: db}
+}
+
+func (r *JobRepository) Create(ctx context.Context, j *job.Job) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+
+	dbModel := toJobModel(j)
+	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	return nil
+}
+
+func (r *JobRepository) GetByID(ctx context.Context, jobID uuid.UUID) (*job.Job, error) {
+	var dbModel models.JobModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", jobID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, job.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return toJobEntity(&dbModel), nil
+}
+
+func (r *JobRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID, limit int) ([]*job.Job, error) {
+	var dbModels []models.JobModel
+	if err := r.db.DB.WithContext(ctx).
+		Where("owner_id = ?", ownerID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	jobs := make([]*job.Job, len(dbModels))
+	for i := range dbModels {
+		jobs[i] = toJobEntity(&dbModels[i])
+	}
+	return jobs, nil
+}
+
+func (r *JobRepository) Claim(ctx context.Context, kinds []string, lease time.Duration) (*job.Job, error) {
+	if len(kinds) == 0 {
+		return nil, job.ErrNoJobAvailable
+	}
+
+	now := time.Now()
+	var claimed *job.Job
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Jobs abandoned on their last attempt are not run again; work that
+		// is not safe to repeat is only allowed a single attempt
+		if err := tx.Model(&models.JobModel{}).
+			Where("status = ? AND locked_until < ? AND attempts >= max_attempts", string(job.StatusRunning), now).
+			Updates(map[string]interface{}{
+				"status":       string(job.StatusFailed),
+				"error":        abandonedJobError,
+				"locked_until": nil,
+				"finished_at":  now,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to fail abandoned jobs: %w", err)
+		}
+
+		var dbModel models.JobModel
+		err := tx.Raw(`SELECT * FROM jobs
+			WHERE kind IN ? AND ((status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?))
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED`,
+			kinds, string(job.StatusQueued), now, string(job.StatusRunning), now).
+			Scan(&dbModel).Error
+		if err != nil {
+			return fmt.Errorf("failed to select job: %w", err)
+		}
+		if dbModel.ID == uuid.Nil {
+			return job.ErrNoJobAvailable
+		}
+
+		lockedUntil := now.Add(lease)
+		dbModel.Status = string(job.StatusRunning)
+		dbModel.Attempts++
+		dbModel.LockedUntil = &lockedUntil
+		if dbModel.StartedAt == nil {
+			dbModel.StartedAt = &now
+		}
+		if err := tx.Model(&models.JobModel{}).
+			Where("id = ?", dbModel.ID).
+			Updates(map[string]interface{}{
+				"status":       dbModel.Status,
+				"attempts":     dbModel.Attempts,
+				"locked_until": lockedUntil,
+				"started_at":   dbModel.StartedAt,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to claim job: %w", err)
+		}
+
+		claimed = toJobEntity(&dbModel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (r *JobRepository) UpdateProgress(ctx context.Context, jobID uuid.UUID, progress int, message string, lease time.Duration) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"progress":     progress,
+		"locked_until": now.Add(lease),
+		"updated_at":   now,
+	}
+	if message != "" {
+		updates["progress_message"] = message
+	}
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.JobModel{}).
+		Where("id = ? AND status = ?", jobID, string(job.StatusRunning)).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update job progress: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return job.ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *JobRepository) Finish(ctx context.Context, j *job.Job) error {
+	dbModel := toJobModel(j)
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.JobModel{}).
+		Where("id = ? AND status = ? AND attempts = ?", j.ID, string(job.StatusRunning), j.Attempts).
+		Updates(map[string]interface{}{
+			"status":           dbModel.Status,
+			"progress":         dbModel.Progress,
+			"progress_message": dbModel.ProgressMessage,
+			"result":           dbModel.Result,
+			"result_key":       dbModel.ResultKey,
+			"result_file_name": dbModel.ResultFileName,
+			"result_type":      dbModel.ResultType,
+			"result_size":      dbModel.ResultSize,
+			"error":            dbModel.Error,
+			"run_at":           dbModel.RunAt,
+			"locked_until":     nil,
+			"finished_at":      dbModel.FinishedAt,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to finish job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return job.ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *JobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) ([]string, error) {
+	var keys []string
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.JobModel{}).
+			Where("finished_at < ? AND result_key IS NOT NULL", before).
+			Pluck("result_key", &keys).Error; err != nil {
+			return fmt.Errorf("failed to list expired job results: %w", err)
+		}
+		if err := tx.Where("finished_at < ?", before).Delete(&models.JobModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete expired jobs: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toJobModel(j *job.Job) *models.JobModel {
+	m := &models.JobModel{
+		ID:              j.ID,
+		Kind:            j.Kind,
+		OwnerID:         j.OwnerID,
+		Status:          string(j.Status),
+		Payload:         j.Payload,
+		Progress:        j.Progress,
+		ProgressMessage: j.ProgressMessage,
+		Result:          j.Result,
+		Error:           j.Error,
+		Attempts:        j.Attempts,
+		MaxAttempts:     j.MaxAttempts,
+		RunAt:           j.RunAt,
+		LockedUntil:     j.LockedUntil,
+		CreatedAt:       j.CreatedAt,
+		UpdatedAt:       j.UpdatedAt,
+		StartedAt:       j.StartedAt,
+		FinishedAt:      j.FinishedAt,
+	}
+	if f := j.ResultFile; f != nil {
+		m.ResultKey = &f.StorageKey
+		m.ResultFileName = &f.FileName
+		m.ResultType = &f.ContentType
+		m.ResultSize = &f.SizeBytes
+	}
+	return m
+}
+
+func toJobEntity(m *models.JobModel) *job.Job {
+	j := &job.Job{
+		ID:              m.ID,
+		Kind:            m.Kind,
+		OwnerID:         m.OwnerID,
+		Status:          job.Status(m.Status),
+		Payload:         m.Payload,
+		Progress:        m.Progress,
+		ProgressMessage: m.ProgressMessage,
+		Result:          m.Result,
+		Error:           m.Error,
+		Attempts:        m.Attempts,
+		MaxAttempts:     m.MaxAttempts,
+		RunAt:           m.RunAt,
+		LockedUntil:     m.LockedUntil,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+		StartedAt:       m.StartedAt,
+		FinishedAt:      m.FinishedAt,
+	}
+	if m.ResultKey != nil {
+		j.ResultFile = &job.File{StorageKey: *m.ResultKey}
+		if m.ResultFileName != nil {
+			j.ResultFile.FileName = *m.ResultFileName
+		}
+		if m.ResultType != nil {
+			j.ResultFile.ContentType = *m.ResultType
+		}
+		if m.ResultSize != nil {
+			j.ResultFile.SizeBytes = *m.ResultSize
+		}
+	}
+	return j
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentAccessGrantModel represents the database model for AccessGrant
+type ShipmentAccessGrantModel struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ShipmentID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	GrantedBy  uuid.UUID  `gorm:"type:uuid;not null"`
+	GranteeID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Scopes     []string   `gorm:"type:jsonb;serializer:json;not null"`
+	Note       *string    `gorm:"type:text"`
+	ExpiresAt  time.Time  `gorm:"not null"`
+	RevokedAt  *time.Time `gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"not null"`
+}
+
+func (ShipmentAccessGrantModel) TableName() string {
+	return "shipment_access_grants"
+}
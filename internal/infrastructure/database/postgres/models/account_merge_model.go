@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountMergeModel represents the database model for AccountMerge
+type AccountMergeModel struct {
+	ID              uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PrimaryUserID   uuid.UUID           `gorm:"type:uuid;not null;index"`
+	DuplicateUserID uuid.UUID           `gorm:"type:uuid;not null;uniqueIndex"`
+	MergedBy        uuid.UUID           `gorm:"type:uuid;not null"`
+	Counts          []AccountMergeCount `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt       time.Time           `gorm:"not null"`
+}
+
+// AccountMergeCount is stored inside the counts JSONB column
+type AccountMergeCount struct {
+	Table   string `json:"table"`
+	Column  string `json:"column"`
+	Moved   int64  `json:"moved"`
+	Skipped int64  `json:"skipped"`
+}
+
+func (AccountMergeModel) TableName() string {
+	return "account_merges"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnnouncementModel represents the database model for announcement.Announcement
+type AnnouncementModel struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Title               string     `gorm:"type:varchar(200);not null"`
+	Message             string     `gorm:"type:text;not null"`
+	Severity            string     `gorm:"type:varchar(20);not null;default:'info'"`
+	StartsAt            time.Time  `gorm:"not null"`
+	EndsAt              time.Time  `gorm:"not null;index"`
+	MaintenanceStartsAt *time.Time `gorm:"type:timestamptz"`
+	MaintenanceEndsAt   *time.Time `gorm:"type:timestamptz"`
+	CreatedBy           uuid.UUID  `gorm:"type:uuid;not null"`
+	CreatedAt           time.Time  `gorm:"not null"`
+	UpdatedAt           time.Time  `gorm:"not null"`
+}
+
+func (AnnouncementModel) TableName() string {
+	return "announcements"
+}
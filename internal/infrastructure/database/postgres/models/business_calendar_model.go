@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BusinessCalendarModel represents the database model for BusinessCalendar
+type BusinessCalendarModel struct {
+	ProviderID   uuid.UUID         `gorm:"type:uuid;primary_key"`
+	Timezone     string            `gorm:"type:varchar(64);not null"`
+	WorkingDays  []int             `gorm:"type:jsonb;serializer:json;not null"`
+	OpenMinute   int               `gorm:"not null"`
+	CloseMinute  int               `gorm:"not null"`
+	CutoffMinute *int              `gorm:""`
+	Holidays     []BusinessHoliday `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt    time.Time         `gorm:"not null"`
+	UpdatedAt    time.Time         `gorm:"not null"`
+}
+
+// BusinessHoliday is one entry of BusinessCalendarModel.Holidays
+type BusinessHoliday struct {
+	Date string `json:"date"`
+	Name string `json:"name,omitempty"`
+}
+
+func (BusinessCalendarModel) TableName() string {
+	return "business_calendars"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CarriageTermsModel represents the database model for CarriageTerms
+type CarriageTermsModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProviderID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:uq_carriage_terms_version"`
+	Version    int       `gorm:"not null;uniqueIndex:uq_carriage_terms_version"`
+	Title      string    `gorm:"type:varchar(255);not null"`
+	Body       string    `gorm:"type:text;not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (CarriageTermsModel) TableName() string {
+	return "carriage_terms"
+}
+
+// TermsAcceptanceModel represents the database model for TermsAcceptance
+type TermsAcceptanceModel struct {
+	ID                   uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ShipmentID           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	TermsID              uuid.UUID `gorm:"type:uuid;not null"`
+	TermsVersion         int       `gorm:"not null"`
+	ShipperID            uuid.UUID `gorm:"type:uuid;not null"`
+	IPAddress            string    `gorm:"column:ip_address;type:varchar(45);not null"`
+	UserAgent            *string   `gorm:"type:varchar(500)"`
+	SignatureKey         string    `gorm:"type:text;not null"`
+	SignatureContentType string    `gorm:"type:varchar(100);not null"`
+	AcceptedAt           time.Time `gorm:"not null"`
+}
+
+func (TermsAcceptanceModel) TableName() string {
+	return "terms_acceptances"
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatLinkModel represents the database model for ChatLink
+type ChatLinkModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	Provider  string    `gorm:"type:varchar(20);not null"`
+	ChatID    string    `gorm:"type:varchar(100);not null"`
+	IsActive  bool      `gorm:"not null;default:true"`
+	LinkedAt  time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (ChatLinkModel) TableName() string {
+	return "chat_links"
+}
+
+// ChatLinkCodeModel represents the database model for ChatLinkCode
+type ChatLinkCodeModel struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null"`
+	Provider  string     `gorm:"type:varchar(20);not null"`
+	CodeHash  string     `gorm:"type:varchar(64);not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time `gorm:"type:timestamptz"`
+	CreatedAt time.Time  `gorm:"not null"`
+}
+
+func (ChatLinkCodeModel) TableName() string {
+	return "chat_link_codes"
+}
+
+// ChatDeliveryModel represents the database model for ChatDelivery
+type ChatDeliveryModel struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	LinkID            uuid.UUID  `gorm:"type:uuid;not null"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null"`
+	Provider          string     `gorm:"type:varchar(20);not null"`
+	Event             string     `gorm:"type:varchar(100);not null"`
+	ProviderMessageID *string    `gorm:"type:varchar(100)"`
+	Status            string     `gorm:"type:varchar(20);not null"`
+	Error             *string    `gorm:"type:text"`
+	SentAt            time.Time  `gorm:"not null"`
+	DeliveredAt       *time.Time `gorm:"type:timestamptz"`
+	SeenAt            *time.Time `gorm:"type:timestamptz"`
+}
+
+func (ChatDeliveryModel) TableName() string {
+	return "chat_deliveries"
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CleanupJobModel represents the database model for cleanup Job
+type CleanupJobModel struct {
+	ID          uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Target      string           `gorm:"type:varchar(20);not null"`
+	Filter      CleanupJobFilter `gorm:"type:jsonb;serializer:json;not null"`
+	DryRun      bool             `gorm:"not null;default:false"`
+	Status      string           `gorm:"type:varchar(20);not null;default:'running'"`
+	Matched     int64            `gorm:"not null;default:0"`
+	Deleted     int64            `gorm:"not null;default:0"`
+	Skipped     int64            `gorm:"not null;default:0"`
+	LastError   *string          `gorm:"type:text"`
+	RequestedBy *uuid.UUID       `gorm:"type:uuid"`
+	CreatedAt   time.Time        `gorm:"not null"`
+	UpdatedAt   time.Time        `gorm:"not null"`
+	FinishedAt  *time.Time       `gorm:"type:timestamptz"`
+}
+
+// CleanupJobFilter is stored inside the filter JSONB column
+type CleanupJobFilter struct {
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	SandboxOnly   bool       `json:"sandbox_only,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	Role          string     `json:"role,omitempty"`
+	Search        string     `json:"search,omitempty"`
+}
+
+func (CleanupJobModel) TableName() string {
+	return "cleanup_jobs"
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomerDelegationModel represents the database model for Delegation
+type CustomerDelegationModel struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CustomerID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	DelegateID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Note       *string    `gorm:"type:text"`
+	RevokedAt  *time.Time `gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"not null"`
+}
+
+func (CustomerDelegationModel) TableName() string {
+	return "customer_delegations"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceDecommissionModel represents the database model for Decommission
+type DeviceDecommissionModel struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	DeviceID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	HardwareUID      string    `gorm:"type:varchar(255);not null"`
+	Reason           string    `gorm:"type:text;not null"`
+	DecommissionedBy uuid.UUID `gorm:"type:uuid;not null"`
+	OwnerUnlinked    bool      `gorm:"not null;default:false"`
+	ShipmentCount    int       `gorm:"not null;default:0"`
+	ArchiveKey       string    `gorm:"type:varchar(500);not null"`
+	ArchiveSHA256    string    `gorm:"column:archive_sha256;type:varchar(64);not null"`
+	ArchiveSize      int64     `gorm:"not null"`
+	CreatedAt        time.Time `gorm:"not null"`
+}
+
+func (DeviceDecommissionModel) TableName() string {
+	return "device_decommissions"
+}
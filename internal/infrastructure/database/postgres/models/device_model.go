@@ -19,10 +19,27 @@ type DeviceModel struct {
 	BatteryLevel      *int       `gorm:"type:integer"`
 	TotalTrips        int        `gorm:"type:integer;default:0"`
 	LastSeenAt        *time.Time `gorm:"type:timestamp"`
-	CreatedAt         time.Time  `gorm:"not null"`
-	UpdatedAt         time.Time  `gorm:"not null"`
+	// Hash of the device API secret; never loaded into the domain entity
+	CredentialHash     *string    `gorm:"type:varchar(64);uniqueIndex"`
+	CredentialIssuedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt          time.Time  `gorm:"not null"`
+	UpdatedAt          time.Time  `gorm:"not null"`
+
+	Profile *ModelProfileModel `gorm:"foreignKey:Model;references:Model"`
 }
 
 func (DeviceModel) TableName() string {
 	return "devices"
 }
+
+// ModelProfileModel represents the database model for tracker model profiles
+type ModelProfileModel struct {
+	Model        string    `gorm:"type:varchar(50);primaryKey"`
+	Capabilities []string  `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+	UpdatedAt    time.Time `gorm:"not null"`
+}
+
+func (ModelProfileModel) TableName() string {
+	return "device_model_profiles"
+}
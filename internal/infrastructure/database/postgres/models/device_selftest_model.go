@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceSelfTestModel represents the database model for device SelfTest
+type DeviceSelfTestModel struct {
+	ID           uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	DeviceID     uuid.UUID          `gorm:"type:uuid;not null;index"`
+	RequestedBy  uuid.UUID          `gorm:"type:uuid;not null"`
+	Status       string             `gorm:"type:varchar(20);not null;default:'pending'"`
+	RequestedAt  time.Time          `gorm:"not null"`
+	CompletedAt  *time.Time         `gorm:"type:timestamptz"`
+	SensorsOK    *bool              `gorm:"column:sensors_ok"`
+	Readings     map[string]float64 `gorm:"type:jsonb;serializer:json;not null"`
+	GPSFix       *bool              `gorm:"column:gps_fix"`
+	BatteryLevel *int
+	SignalDBm    *int     `gorm:"column:signal_dbm"`
+	Failures     []string `gorm:"type:jsonb;serializer:json;not null"`
+}
+
+func (DeviceSelfTestModel) TableName() string {
+	return "device_self_tests"
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceTransferModel represents the database model for device Transfer
+type DeviceTransferModel struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	DeviceID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FromShipperID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ToShipperID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Status        string     `gorm:"type:varchar(20);not null;default:'pending'"`
+	Note          *string    `gorm:"type:text"`
+	CreatedAt     time.Time  `gorm:"not null"`
+	RespondedAt   *time.Time `gorm:"type:timestamptz"`
+}
+
+func (DeviceTransferModel) TableName() string {
+	return "device_transfers"
+}
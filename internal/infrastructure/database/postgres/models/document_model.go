@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DocumentChecklistItemModel represents the database model for a required shipment document
+type DocumentChecklistItemModel struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ShipmentID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	DocumentType string     `gorm:"type:varchar(50);not null"`
+	Label        string     `gorm:"type:varchar(255);not null"`
+	Required     bool       `gorm:"default:true;not null"`
+	Status       string     `gorm:"type:varchar(20);not null;default:'pending'"`
+	AttachmentID *uuid.UUID `gorm:"type:uuid"`
+	ReviewedBy   *uuid.UUID `gorm:"type:uuid"`
+	ReviewedAt   *time.Time `gorm:"type:timestamptz"`
+	ReviewNote   *string    `gorm:"type:text"`
+	CreatedAt    time.Time  `gorm:"not null"`
+	UpdatedAt    time.Time  `gorm:"not null"`
+}
+
+func (DocumentChecklistItemModel) TableName() string {
+	return "shipment_document_checklist"
+}
+
+// AttachmentModel represents the database model for a file attached to a shipment
+type AttachmentModel struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ShipmentID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ChecklistItemID *uuid.UUID `gorm:"type:uuid"`
+	DocumentType    string     `gorm:"type:varchar(50);not null"`
+	FileName        string     `gorm:"type:varchar(255);not null"`
+	ContentType     string     `gorm:"type:varchar(100);not null"`
+	SizeBytes       int64      `gorm:"not null"`
+	StorageKey      string     `gorm:"type:text;not null"`
+	UploadedBy      uuid.UUID  `gorm:"type:uuid;not null"`
+	VisibleCustomer bool       `gorm:"column:visible_to_customer;not null;default:true"`
+	VisibleProvider bool       `gorm:"column:visible_to_provider;not null;default:true"`
+	VisibleShipper  bool       `gorm:"column:visible_to_shipper;not null;default:true"`
+	VisibleAdmin    bool       `gorm:"column:visible_to_admin;not null;default:true"`
+	CreatedAt       time.Time  `gorm:"not null;index"`
+}
+
+func (AttachmentModel) TableName() string {
+	return "shipment_attachments"
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvitationModel represents the database model for Invitation
+type InvitationModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Email      string    `gorm:"type:varchar(255);not null"`
+	Role       string    `gorm:"type:varchar(50);not null"`
+	TokenHash  string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	InvitedBy  uuid.UUID `gorm:"type:uuid;not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	AcceptedAt *time.Time
+	AcceptedBy *uuid.UUID `gorm:"type:uuid"`
+	RevokedAt  *time.Time
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (InvitationModel) TableName() string {
+	return "user_invitations"
+}
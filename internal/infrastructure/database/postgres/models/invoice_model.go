@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceModel represents the database model for Invoice
+type InvoiceModel struct {
+	ID               uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Number           string             `gorm:"type:varchar(30);not null;uniqueIndex"`
+	ProviderID       uuid.UUID          `gorm:"type:uuid;not null;index"`
+	CustomerID       uuid.UUID          `gorm:"type:uuid;not null;index"`
+	PeriodStart      time.Time          `gorm:"type:timestamptz;not null"`
+	PeriodEnd        time.Time          `gorm:"type:timestamptz;not null"`
+	Currency         string             `gorm:"type:varchar(3);not null"`
+	Subtotal         float64            `gorm:"type:decimal(14,2);not null"`
+	Adjustments      float64            `gorm:"type:decimal(14,2);not null"`
+	Total            float64            `gorm:"type:decimal(14,2);not null"`
+	Status           string             `gorm:"type:varchar(20);not null"`
+	DueAt            time.Time          `gorm:"type:timestamptz;not null"`
+	PaidAt           *time.Time         `gorm:"type:timestamptz"`
+	PaymentReference *string            `gorm:"type:varchar(255)"`
+	CreatedAt        time.Time          `gorm:"not null"`
+	UpdatedAt        time.Time          `gorm:"not null"`
+	Lines            []InvoiceLineModel `gorm:"foreignKey:InvoiceID"`
+}
+
+func (InvoiceModel) TableName() string {
+	return "invoices"
+}
+
+// InvoiceLineModel represents the database model for InvoiceLine
+type InvoiceLineModel struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InvoiceID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ShipmentID  *uuid.UUID `gorm:"type:uuid"`
+	Kind        string     `gorm:"type:varchar(30);not null"`
+	Description string     `gorm:"type:text;not null"`
+	Amount      float64    `gorm:"type:decimal(14,2);not null"`
+	Priced      bool       `gorm:"not null;default:true"`
+	CreatedAt   time.Time  `gorm:"not null"`
+}
+
+func (InvoiceLineModel) TableName() string {
+	return "invoice_lines"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobModel represents the database model for Job
+type JobModel struct {
+	ID              uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Kind            string          `gorm:"type:varchar(50);not null"`
+	OwnerID         uuid.UUID       `gorm:"type:uuid;not null;index"`
+	Status          string          `gorm:"type:varchar(20);not null;default:'queued'"`
+	Payload         json.RawMessage `gorm:"type:jsonb;serializer:json;not null"`
+	Progress        int             `gorm:"not null;default:0"`
+	ProgressMessage *string         `gorm:"type:varchar(255)"`
+	Result          json.RawMessage `gorm:"type:jsonb;serializer:json"`
+	ResultKey       *string         `gorm:"type:text"`
+	ResultFileName  *string         `gorm:"type:varchar(255)"`
+	ResultType      *string         `gorm:"type:varchar(100)"`
+	ResultSize      *int64
+	Error           *string    `gorm:"type:text"`
+	Attempts        int        `gorm:"not null;default:0"`
+	MaxAttempts     int        `gorm:"not null;default:1"`
+	RunAt           time.Time  `gorm:"not null"`
+	LockedUntil     *time.Time `gorm:"type:timestamptz"`
+	CreatedAt       time.Time  `gorm:"not null"`
+	UpdatedAt       time.Time  `gorm:"not null"`
+	StartedAt       *time.Time `gorm:"type:timestamptz"`
+	FinishedAt      *time.Time `gorm:"type:timestamptz"`
+}
+
+func (JobModel) TableName() string {
+	return "jobs"
+}
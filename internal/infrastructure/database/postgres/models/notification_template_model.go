@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTemplateModel represents the database model for NotificationTemplate
+type NotificationTemplateModel struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Event     string     `gorm:"type:varchar(100);not null"`
+	Channel   string     `gorm:"type:varchar(20);not null"`
+	Language  string     `gorm:"type:varchar(10);not null"`
+	Version   int        `gorm:"type:integer;not null"`
+	Subject   string     `gorm:"type:text;not null"`
+	Body      string     `gorm:"type:text;not null"`
+	IsActive  bool       `gorm:"not null;default:false"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time  `gorm:"not null"`
+}
+
+func (NotificationTemplateModel) TableName() string {
+	return "notification_templates"
+}
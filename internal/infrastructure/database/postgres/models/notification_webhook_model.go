@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationWebhookModel represents the database model for Webhook
+type NotificationWebhookModel struct {
+	ID                  uuid.UUID                       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OwnerID             uuid.UUID                       `gorm:"type:uuid;not null;index"`
+	Name                string                          `gorm:"type:varchar(100);not null"`
+	Kind                string                          `gorm:"type:varchar(20);not null"`
+	URL                 string                          `gorm:"type:text;not null;serializer:encrypted"`
+	Scope               string                          `gorm:"type:varchar(20);not null"`
+	MinSeverity         string                          `gorm:"type:varchar(20);not null"`
+	DailyDigest         bool                            `gorm:"not null;default:false"`
+	IsActive            bool                            `gorm:"not null;default:true"`
+	PayloadFilters      map[string]WebhookPayloadFilter `gorm:"type:jsonb;serializer:json;not null"`
+	SigningSecret       string                          `gorm:"type:text;not null;serializer:encrypted"`
+	PreviousSecret      *string                         `gorm:"column:previous_signing_secret;type:text;serializer:encrypted"`
+	SecretRotatedAt     *time.Time                      `gorm:"type:timestamptz"`
+	LastDeliveryAt      *time.Time                      `gorm:"type:timestamptz"`
+	LastSuccessAt       *time.Time                      `gorm:"type:timestamptz"`
+	LastError           *string                         `gorm:"type:text"`
+	ConsecutiveFailures int                             `gorm:"type:integer;not null;default:0"`
+	CreatedAt           time.Time                       `gorm:"not null"`
+	UpdatedAt           time.Time                       `gorm:"not null"`
+}
+
+func (NotificationWebhookModel) TableName() string {
+	return "notification_webhooks"
+}
+
+// WebhookPayloadFilter is stored inside the payload_filters JSONB column
+type WebhookPayloadFilter struct {
+	Preset string   `json:"preset,omitempty"`
+	Fields []string `json:"fields,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PartnerMappingModel represents the database model for interop PartnerMapping
+type PartnerMappingModel struct {
+	ID                uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OwnerID           uuid.UUID         `gorm:"type:uuid;not null;index"`
+	Name              string            `gorm:"type:varchar(100);not null"`
+	Format            string            `gorm:"type:varchar(20);not null"`
+	RecordsPath       string            `gorm:"type:varchar(255);not null;default:''"`
+	ImportFields      map[string]string `gorm:"type:jsonb;not null;serializer:json"`
+	DefaultProviderID *uuid.UUID        `gorm:"type:uuid"`
+	ExportFields      map[string]string `gorm:"type:jsonb;not null;serializer:json"`
+	IsActive          bool              `gorm:"not null;default:true"`
+	CreatedAt         time.Time         `gorm:"not null"`
+	UpdatedAt         time.Time         `gorm:"not null"`
+}
+
+func (PartnerMappingModel) TableName() string {
+	return "interop_partner_mappings"
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhoneOTPModel represents the database model for PhoneOTP
+type PhoneOTPModel struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Purpose    string     `gorm:"type:varchar(30);not null"`
+	CodeHash   string     `gorm:"type:varchar(64);not null"`
+	ExpiresAt  time.Time  `gorm:"not null;index"`
+	Attempts   int        `gorm:"not null;default:0"`
+	ConsumedAt *time.Time `gorm:"type:timestamp"`
+	CreatedAt  time.Time  `gorm:"not null"`
+}
+
+func (PhoneOTPModel) TableName() string {
+	return "phone_otps"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProviderBrandingModel represents the database model for provider Branding
+type ProviderBrandingModel struct {
+	ProviderID      uuid.UUID `gorm:"type:uuid;primary_key"`
+	DisplayName     *string   `gorm:"type:varchar(255)"`
+	PrimaryColor    *string   `gorm:"type:varchar(7)"`
+	AccentColor     *string   `gorm:"type:varchar(7)"`
+	SupportEmail    *string   `gorm:"type:varchar(255)"`
+	SupportPhone    *string   `gorm:"type:varchar(50)"`
+	SupportURL      *string   `gorm:"column:support_url;type:varchar(500)"`
+	LogoKey         *string   `gorm:"type:text"`
+	LogoContentType *string   `gorm:"type:varchar(100)"`
+	CreatedAt       time.Time `gorm:"not null"`
+	UpdatedAt       time.Time `gorm:"not null"`
+}
+
+func (ProviderBrandingModel) TableName() string {
+	return "provider_branding"
+}
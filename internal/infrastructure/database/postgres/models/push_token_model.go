@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PushTokenModel represents the database model for PushToken
+type PushTokenModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Provider   string    `gorm:"type:varchar(10);not null"`
+	Token      string    `gorm:"type:varchar(512);not null"`
+	Platform   string    `gorm:"type:varchar(10);not null"`
+	DeviceName *string   `gorm:"type:varchar(100)"`
+	AppVersion *string   `gorm:"type:varchar(50)"`
+	LastSeenAt time.Time `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (PushTokenModel) TableName() string {
+	return "push_tokens"
+}
+
+// PushSubscriptionModel represents the database model for PushSubscription
+type PushSubscriptionModel struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Topic     string    `gorm:"type:varchar(100);primaryKey"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (PushSubscriptionModel) TableName() string {
+	return "push_subscriptions"
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuoteRequestModel represents the database model for QuoteRequest
+type QuoteRequestModel struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CustomerID          uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Status              string     `gorm:"type:varchar(20);not null;default:'open';index"`
+	GoodsDescription    string     `gorm:"type:text;not null"`
+	GoodsValue          *float64   `gorm:"type:decimal(12,2)"`
+	GoodsCurrency       *string    `gorm:"type:varchar(3)"`
+	GoodsWeight         *float64   `gorm:"type:decimal(8,2)"`
+	PickupAddress       string     `gorm:"type:text;not null"`
+	DeliveryAddress     string     `gorm:"type:text;not null"`
+	EstimatedPickupAt   *time.Time `gorm:"type:timestamptz"`
+	EstimatedDeliveryAt *time.Time `gorm:"type:timestamptz"`
+	CustomerNotes       *string    `gorm:"type:text"`
+	ExpiresAt           time.Time  `gorm:"type:timestamptz;not null"`
+	ShipmentID          *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt           time.Time  `gorm:"not null;index"`
+	UpdatedAt           time.Time  `gorm:"not null"`
+
+	Quotes []QuoteModel `gorm:"foreignKey:RequestID"`
+}
+
+func (QuoteRequestModel) TableName() string {
+	return "quote_requests"
+}
+
+// QuoteModel represents the database model for Quote
+type QuoteModel struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RequestID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ProviderID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Status        string     `gorm:"type:varchar(20);not null;default:'requested'"`
+	Price         *float64   `gorm:"type:decimal(12,2)"`
+	Currency      string     `gorm:"type:varchar(3);not null;default:'VND'"`
+	LeadTimeHours *int       `gorm:"type:integer"`
+	ValidUntil    *time.Time `gorm:"type:timestamptz"`
+	Notes         *string    `gorm:"type:text"`
+	RespondedAt   *time.Time `gorm:"type:timestamptz"`
+	CreatedAt     time.Time  `gorm:"not null"`
+	UpdatedAt     time.Time  `gorm:"not null"`
+}
+
+func (QuoteModel) TableName() string {
+	return "quotes"
+}
@@ -0,0 +1,54 @@
+package models
+
+// RoleRefModel represents the database model for a reference.Entry of ref_roles
+type RoleRefModel struct {
+	ID          int    `gorm:"primaryKey"`
+	Code        string `gorm:"type:varchar(30);not null;uniqueIndex"`
+	Name        string `gorm:"type:varchar(100);not null"`
+	Description string `gorm:"type:text;not null"`
+}
+
+func (RoleRefModel) TableName() string {
+	return "ref_roles"
+}
+
+// IssueTypeRefModel represents the database model for a reference.Entry of
+// ref_issue_types
+type IssueTypeRefModel struct {
+	ID          int    `gorm:"primaryKey"`
+	Code        string `gorm:"type:varchar(50);not null;uniqueIndex"`
+	Name        string `gorm:"type:varchar(100);not null"`
+	Description string `gorm:"type:text;not null"`
+}
+
+func (IssueTypeRefModel) TableName() string {
+	return "ref_issue_types"
+}
+
+// ViolationTypeRefModel represents the database model for reference.ViolationType
+type ViolationTypeRefModel struct {
+	ID          int    `gorm:"primaryKey"`
+	Code        string `gorm:"type:varchar(50);not null;uniqueIndex"`
+	Name        string `gorm:"type:varchar(100);not null"`
+	Description string `gorm:"type:text;not null"`
+	Unit        string `gorm:"type:varchar(20);not null"`
+}
+
+func (ViolationTypeRefModel) TableName() string {
+	return "ref_violation_types"
+}
+
+// GoodsCategoryRefModel represents the database model for reference.GoodsCategory
+type GoodsCategoryRefModel struct {
+	ID          int      `gorm:"primaryKey"`
+	Code        string   `gorm:"type:varchar(50);not null;uniqueIndex"`
+	Name        string   `gorm:"type:varchar(100);not null"`
+	Description string   `gorm:"type:text;not null"`
+	TempMin     *float64 `gorm:"type:decimal(5,1)"`
+	TempMax     *float64 `gorm:"type:decimal(5,1)"`
+	Keywords    []string `gorm:"type:jsonb;serializer:json;not null"`
+}
+
+func (GoodsCategoryRefModel) TableName() string {
+	return "ref_goods_categories"
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportSubscriptionModel represents the database model for report.Subscription
+type ReportSubscriptionModel struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OwnerID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ReportType string     `gorm:"type:varchar(50);not null"`
+	Cadence    string     `gorm:"type:varchar(20);not null"`
+	Format     string     `gorm:"type:varchar(20);not null;default:'text'"`
+	Recipients []string   `gorm:"type:jsonb;serializer:json;not null"`
+	Timezone   string     `gorm:"type:varchar(64);not null"`
+	Active     bool       `gorm:"not null;default:true"`
+	NextRunAt  time.Time  `gorm:"not null"`
+	LastRunAt  *time.Time `gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"not null"`
+	UpdatedAt  time.Time  `gorm:"not null"`
+}
+
+func (ReportSubscriptionModel) TableName() string {
+	return "report_subscriptions"
+}
+
+// ReportDeliveryModel represents the database model for report.Delivery
+type ReportDeliveryModel struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SubscriptionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	PeriodStart    time.Time `gorm:"not null"`
+	PeriodEnd      time.Time `gorm:"not null"`
+	Status         string    `gorm:"type:varchar(20);not null"`
+	Recipients     []string  `gorm:"type:jsonb;serializer:json;not null"`
+	Error          *string   `gorm:"type:text"`
+	CreatedAt      time.Time `gorm:"not null"`
+}
+
+func (ReportDeliveryModel) TableName() string {
+	return "report_deliveries"
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedAddressModel represents the database model for SavedAddress
+type SavedAddressModel struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OwnerID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Label        string     `gorm:"type:varchar(100);not null"`
+	Line1        string     `gorm:"column:line1;type:varchar(255);not null"`
+	Line2        *string    `gorm:"column:line2;type:varchar(255)"`
+	Ward         *string    `gorm:"type:varchar(100)"`
+	District     *string    `gorm:"type:varchar(100)"`
+	City         string     `gorm:"type:varchar(100);not null"`
+	Province     *string    `gorm:"type:varchar(100)"`
+	PostalCode   *string    `gorm:"type:varchar(20)"`
+	CountryCode  string     `gorm:"type:char(2);not null"`
+	Latitude     *float64   `gorm:"type:double precision"`
+	Longitude    *float64   `gorm:"type:double precision"`
+	ContactName  *string    `gorm:"type:varchar(255)"`
+	ContactPhone *string    `gorm:"type:text;serializer:encrypted"`
+	Status       string     `gorm:"type:varchar(20);not null"`
+	VerifiedAt   *time.Time `gorm:"type:timestamptz"`
+	UseCount     int        `gorm:"type:integer;not null;default:0"`
+	LastUsedAt   *time.Time `gorm:"type:timestamptz"`
+	CreatedAt    time.Time  `gorm:"not null"`
+	UpdatedAt    time.Time  `gorm:"not null"`
+}
+
+func (SavedAddressModel) TableName() string {
+	return "saved_addresses"
+}
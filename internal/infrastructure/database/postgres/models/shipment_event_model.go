@@ -0,0 +1,22 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentEventModel represents the database model for shipment Event
+type ShipmentEventModel struct {
+	ID         int64           `gorm:"primaryKey;autoIncrement"`
+	ShipmentID uuid.UUID       `gorm:"type:uuid;not null;index"`
+	Type       string          `gorm:"type:varchar(30);not null"`
+	ActorID    *uuid.UUID      `gorm:"type:uuid"`
+	Data       json.RawMessage `gorm:"type:jsonb;serializer:json;not null"`
+	OccurredAt time.Time       `gorm:"not null"`
+}
+
+func (ShipmentEventModel) TableName() string {
+	return "shipment_events"
+}
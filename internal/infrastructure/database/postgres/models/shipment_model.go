@@ -8,26 +8,43 @@ import (
 
 // ShipmentModel represents the database model for Shipments
 type ShipmentModel struct {
-	ID                  uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	CustomerID          uuid.UUID  `gorm:"type:uuid;not null;index"`
-	ProviderID          uuid.UUID  `gorm:"type:uuid;not null;index"`
-	ShipperID           *uuid.UUID `gorm:"type:uuid;index"`
-	LinkedDeviceID      *uuid.UUID `gorm:"type:uuid"`
-	Status              string     `gorm:"type:shipment_status;not null;default:'demand_created';index"`
-	GoodsDescription    string     `gorm:"type:text;not null"`
-	GoodsValue          *float64   `gorm:"type:decimal(12,2)"`
-	GoodsWeight         *float64   `gorm:"type:decimal(8,2)"`
-	PickupAddress       string     `gorm:"type:text;not null"`
-	DeliveryAddress     string     `gorm:"type:text;not null"`
-	EstimatedPickupAt   *time.Time `gorm:"type:timestamptz"`
-	EstimatedDeliveryAt *time.Time `gorm:"type:timestamptz"`
-	ActualPickupAt      *time.Time `gorm:"type:timestamptz"`
-	ActualDeliveryAt    *time.Time `gorm:"type:timestamptz"`
-	CustomerNotes       *string    `gorm:"type:text"`
-	CompletionNotes     *string    `gorm:"type:text"`
-	CustomerRating      *int       `gorm:"type:integer;check:customer_rating >= 1 AND customer_rating <= 5"`
-	CreatedAt           time.Time  `gorm:"not null;index"`
-	UpdatedAt           time.Time  `gorm:"not null"`
+	ID                  uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CustomerID          uuid.UUID            `gorm:"type:uuid;not null;index"`
+	ProviderID          uuid.UUID            `gorm:"type:uuid;not null;index"`
+	ShipperID           *uuid.UUID           `gorm:"type:uuid;index"`
+	BookedBy            *uuid.UUID           `gorm:"type:uuid"`
+	LinkedDeviceID      *uuid.UUID           `gorm:"type:uuid"`
+	RulesVersionID      *uuid.UUID           `gorm:"type:uuid"`
+	Status              string               `gorm:"type:shipment_status;not null;default:'demand_created';index"`
+	GoodsDescription    string               `gorm:"type:text;not null"`
+	GoodsCategory       *string              `gorm:"type:varchar(50)"`
+	GoodsValue          *float64             `gorm:"type:decimal(12,2)"`
+	GoodsCurrency       string               `gorm:"type:varchar(3);not null;default:'VND'"`
+	ExchangeRate        *float64             `gorm:"type:decimal(20,8)"`
+	ExchangeCurrency    *string              `gorm:"type:varchar(3)"`
+	ExchangeRatedAt     *time.Time           `gorm:"type:timestamptz"`
+	GoodsWeight         *float64             `gorm:"type:decimal(8,2)"`
+	PickupAddress       string               `gorm:"type:text;not null"`
+	DeliveryAddress     string               `gorm:"type:text;not null"`
+	PickupAddressID     *uuid.UUID           `gorm:"type:uuid"`
+	DeliveryAddressID   *uuid.UUID           `gorm:"type:uuid"`
+	EstimatedPickupAt   *time.Time           `gorm:"type:timestamptz"`
+	EstimatedDeliveryAt *time.Time           `gorm:"type:timestamptz"`
+	DeliveryDueAt       *time.Time           `gorm:"type:timestamptz"`
+	ActualPickupAt      *time.Time           `gorm:"type:timestamptz"`
+	ActualDeliveryAt    *time.Time           `gorm:"type:timestamptz"`
+	CustomerNotes       *string              `gorm:"type:text"`
+	CompletionNotes     *string              `gorm:"type:text"`
+	CustomerRating      *int                 `gorm:"type:integer;check:customer_rating >= 1 AND customer_rating <= 5"`
+	ProofOfDeliveryURL  *string              `gorm:"type:text;serializer:encrypted"`
+	RiskScore           *int                 `gorm:"type:integer"`
+	RiskFactors         []ShipmentRiskFactor `gorm:"type:jsonb;serializer:json"`
+	RiskAssessedAt      *time.Time           `gorm:"type:timestamptz"`
+	IsSandbox           bool                 `gorm:"not null;default:false;index"`
+	CreatedAt           time.Time            `gorm:"not null;index"`
+	UpdatedAt           time.Time            `gorm:"not null"`
+	// Maintained by a trigger whenever status changes
+	StatusChangedAt time.Time `gorm:"->;type:timestamptz"`
 
 	// Relations
 	Customer *UserModel   `gorm:"foreignKey:CustomerID"`
@@ -40,6 +57,38 @@ func (ShipmentModel) TableName() string {
 	return "shipments"
 }
 
+// ShipmentRiskFactor is one entry of ShipmentModel.RiskFactors
+type ShipmentRiskFactor struct {
+	Name   string  `json:"name"`
+	Score  int     `json:"score"`
+	Weight float64 `json:"weight"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// PackageModel represents the database model for shipment packages. The
+// JSON names match the columns so ShipmentDetailModel can decode packages
+// aggregated with json_agg.
+type PackageModel struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ShipmentID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"shipment_id"`
+	Sequence    int        `gorm:"type:integer;not null" json:"sequence"`
+	Description string     `gorm:"type:text;not null" json:"description"`
+	Weight      *float64   `gorm:"type:decimal(8,2)" json:"weight"`
+	DeviceID    *uuid.UUID `gorm:"type:uuid;index" json:"device_id"`
+
+	Outcome           string     `gorm:"type:varchar(20);not null;default:'pending'" json:"outcome"`
+	OutcomeNote       *string    `gorm:"type:text" json:"outcome_note"`
+	OutcomeRecordedBy *uuid.UUID `gorm:"type:uuid" json:"outcome_recorded_by"`
+	OutcomeAt         *time.Time `gorm:"type:timestamptz" json:"outcome_at"`
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (PackageModel) TableName() string {
+	return "shipment_packages"
+}
+
 // ShippingRulesModel represents the database model for ShippingRules
 type ShippingRulesModel struct {
 	ID                    uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -65,3 +114,54 @@ type ShippingRulesModel struct {
 func (ShippingRulesModel) TableName() string {
 	return "shipping_rules"
 }
+
+// ShippingRulesVersionModel represents the database model for RulesVersion
+type ShippingRulesVersionModel struct {
+	ID                    uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ShipmentID            uuid.UUID `gorm:"type:uuid;not null;index"`
+	Version               int       `gorm:"type:integer;not null"`
+	ReportCycleSec        int       `gorm:"type:integer;not null"`
+	TempMin               *float64  `gorm:"type:decimal(5,2)"`
+	TempMax               *float64  `gorm:"type:decimal(5,2)"`
+	HumidityMin           *float64  `gorm:"type:decimal(5,2)"`
+	HumidityMax           *float64  `gorm:"type:decimal(5,2)"`
+	LightMax              *float64  `gorm:"type:decimal(10,2)"`
+	TiltMaxAngle          *float64  `gorm:"type:decimal(5,2)"`
+	ImpactThresholdG      *float64  `gorm:"type:decimal(5,2)"`
+	EnablePredictiveAlert bool      `gorm:"default:false;not null"`
+	AlertBufferTimeMin    int       `gorm:"type:integer;default:0"`
+	SetBy                 uuid.UUID `gorm:"type:uuid;not null"`
+	SetAt                 time.Time `gorm:"not null"`
+}
+
+func (ShippingRulesVersionModel) TableName() string {
+	return "shipping_rules_versions"
+}
+
+// WatchdogStageModel records a watchdog step taken for a stuck shipment
+type WatchdogStageModel struct {
+	ShipmentID      uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Status          string    `gorm:"type:shipment_status;not null"`
+	StatusChangedAt time.Time `gorm:"type:timestamptz;primaryKey"`
+	Stage           string    `gorm:"type:varchar(20);primaryKey"`
+	CreatedAt       time.Time `gorm:"not null"`
+}
+
+func (WatchdogStageModel) TableName() string {
+	return "shipment_watchdog_stages"
+}
+
+// ShipmentDetailModel reads a shipment together with everything its detail
+// view shows. Rules and terms acceptance are joined; packages come back as a
+// JSON array from a lateral subquery so the whole detail is one round trip.
+type ShipmentDetailModel struct {
+	ShipmentModel
+
+	Rules           *ShippingRulesModel   `gorm:"foreignKey:ShipmentID"`
+	TermsAcceptance *TermsAcceptanceModel `gorm:"foreignKey:ShipmentID"`
+	Packages        []PackageModel        `gorm:"->;-:migration;serializer:json"`
+}
+
+func (ShipmentDetailModel) TableName() string {
+	return "shipments"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentNoteModel represents the database model for Note
+type ShipmentNoteModel struct {
+	ID         uuid.UUID                   `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ShipmentID uuid.UUID                   `gorm:"type:uuid;not null;index"`
+	AuthorID   uuid.UUID                   `gorm:"type:uuid;not null"`
+	Visibility string                      `gorm:"type:varchar(20);not null"`
+	Body       string                      `gorm:"type:text;not null"`
+	CreatedAt  time.Time                   `gorm:"not null"`
+	UpdatedAt  time.Time                   `gorm:"not null"`
+	Revisions  []ShipmentNoteRevisionModel `gorm:"foreignKey:NoteID"`
+}
+
+func (ShipmentNoteModel) TableName() string {
+	return "shipment_notes"
+}
+
+// ShipmentNoteRevisionModel represents the database model for NoteRevision
+type ShipmentNoteRevisionModel struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	NoteID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Body       string    `gorm:"type:text;not null"`
+	Visibility string    `gorm:"type:varchar(20);not null"`
+	EditedBy   uuid.UUID `gorm:"type:uuid;not null"`
+	EditedAt   time.Time `gorm:"not null"`
+}
+
+func (ShipmentNoteRevisionModel) TableName() string {
+	return "shipment_note_revisions"
+}
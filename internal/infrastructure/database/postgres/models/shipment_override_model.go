@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentOverrideModel represents the database model for Override
+type ShipmentOverrideModel struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Action        string     `gorm:"type:varchar(30);not null"`
+	ShipmentID    *uuid.UUID `gorm:"type:uuid;index"`
+	DeviceID      *uuid.UUID `gorm:"type:uuid"`
+	AdminID       uuid.UUID  `gorm:"type:uuid;not null"`
+	Justification string     `gorm:"type:text;not null"`
+	FromStatus    *string    `gorm:"type:varchar(30)"`
+	ToStatus      *string    `gorm:"type:varchar(30)"`
+	CreatedAt     time.Time  `gorm:"not null"`
+}
+
+func (ShipmentOverrideModel) TableName() string {
+	return "shipment_overrides"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TripModel represents the database model for Trip
+type TripModel struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ShipperID   uuid.UUID       `gorm:"type:uuid;not null;index"`
+	DeviceID    uuid.UUID       `gorm:"type:uuid;not null;index"`
+	Name        *string         `gorm:"type:varchar(100)"`
+	Status      string          `gorm:"type:varchar(20);not null"`
+	CompletedAt *time.Time      `gorm:"type:timestamptz"`
+	CreatedAt   time.Time       `gorm:"not null"`
+	UpdatedAt   time.Time       `gorm:"not null"`
+	Stops       []TripStopModel `gorm:"foreignKey:TripID"`
+}
+
+func (TripModel) TableName() string {
+	return "trips"
+}
+
+// TripStopModel represents the database model for TripStop
+type TripStopModel struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TripID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ShipmentID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Sequence    int        `gorm:"type:integer;not null"`
+	CompletedAt *time.Time `gorm:"type:timestamptz"`
+}
+
+func (TripStopModel) TableName() string {
+	return "trip_stops"
+}
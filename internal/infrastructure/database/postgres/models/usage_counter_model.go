@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageCounterModel represents the database model for a monthly usage counter
+type UsageCounterModel struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Metric      string    `gorm:"type:varchar(30);primaryKey"`
+	PeriodStart time.Time `gorm:"type:date;primaryKey"`
+	Count       int64     `gorm:"not null;default:0"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (UsageCounterModel) TableName() string {
+	return "usage_counters"
+}
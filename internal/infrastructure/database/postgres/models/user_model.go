@@ -8,17 +8,22 @@ import (
 
 // UserModel represents the database model for User
 type UserModel struct {
-	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Username       string    `gorm:"type:varchar(100);not null;uniqueIndex"`
-	Email          string    `gorm:"type:varchar(255);not null;uniqueIndex"`
-	PasswordHashed string    `gorm:"type:varchar(255);not null"`
-	FullName       string    `gorm:"type:varchar(255);not null"`
-	PhoneNumber    *string   `gorm:"type:varchar(20)"`
-	Role           string    `gorm:"type:varchar(50);not null;default:'user'"`
-	Address        *string   `gorm:"type:text"`
-	IsActive       bool      `gorm:"default:true;not null"`
-	CreatedAt      time.Time `gorm:"not null"`
-	UpdatedAt      time.Time `gorm:"not null"`
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Username        string     `gorm:"type:varchar(100);not null;uniqueIndex"`
+	Email           string     `gorm:"type:varchar(255);not null;uniqueIndex"`
+	PasswordHashed  string     `gorm:"type:varchar(255);not null"`
+	FullName        string     `gorm:"type:varchar(255);not null"`
+	PhoneNumber     *string    `gorm:"type:text;serializer:encrypted"`
+	PhoneHash       *string    `gorm:"column:phone_number_hash;type:varchar(64);uniqueIndex"`
+	PhoneVerified   *time.Time `gorm:"column:phone_verified_at"`
+	Role            string     `gorm:"type:varchar(50);not null;default:'user'"`
+	Address         *string    `gorm:"type:text;serializer:encrypted"`
+	IsActive        bool       `gorm:"default:true;not null"`
+	IsSandbox       bool       `gorm:"default:false;not null"`
+	Plan            string     `gorm:"type:varchar(20);not null;default:'free'"`
+	DefaultCurrency *string    `gorm:"type:varchar(3)"`
+	CreatedAt       time.Time  `gorm:"not null"`
+	UpdatedAt       time.Time  `gorm:"not null"`
 }
 
 func (UserModel) TableName() string {
@@ -41,14 +46,17 @@ func (PasswordResetTokenModel) TableName() string {
 
 // RefreshTokenModel represents the database model for RefreshToken
 type RefreshTokenModel struct {
-	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
-	Token     string     `gorm:"type:varchar(500);not null;unique;index"`
-	ExpiresAt time.Time  `gorm:"not null;index"`
-	Revoked   bool       `gorm:"default:false;index"`
-	RevokedAt *time.Time `gorm:"type:timestamp"`
-	CreatedAt time.Time  `gorm:"not null"`
-	UpdatedAt time.Time  `gorm:"not null"`
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Token            string     `gorm:"type:varchar(500);not null;unique;index"`
+	ExpiresAt        time.Time  `gorm:"not null;index"`
+	Revoked          bool       `gorm:"default:false;index"`
+	RevokedAt        *time.Time `gorm:"type:timestamp"`
+	RevokeReason     *string    `gorm:"type:varchar(50)"`
+	SessionStartedAt time.Time  `gorm:"not null"`
+	LastActivityAt   time.Time  `gorm:"not null;index"`
+	CreatedAt        time.Time  `gorm:"not null"`
+	UpdatedAt        time.Time  `gorm:"not null"`
 }
 
 func (RefreshTokenModel) TableName() string {
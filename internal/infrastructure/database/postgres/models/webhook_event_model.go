@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventModel represents the database model for WebhookEvent
+type WebhookEventModel struct {
+	ID             uuid.UUID         `gorm:"type:uuid;primary_key"`
+	WebhookID      uuid.UUID         `gorm:"type:uuid;not null;index"`
+	OwnerID        uuid.UUID         `gorm:"type:uuid;not null;index"`
+	Event          string            `gorm:"type:varchar(100);not null"`
+	Severity       string            `gorm:"type:varchar(20);not null"`
+	Subject        string            `gorm:"type:text;not null"`
+	Body           string            `gorm:"type:text;not null"`
+	Link           string            `gorm:"type:text"`
+	Data           map[string]string `gorm:"type:jsonb;serializer:json;not null"`
+	Delivered      bool              `gorm:"not null;default:false"`
+	LastError      *string           `gorm:"type:text"`
+	ReplayCount    int               `gorm:"not null;default:0"`
+	LastReplayedAt *time.Time        `gorm:"type:timestamptz"`
+	CreatedAt      time.Time         `gorm:"not null"`
+}
+
+func (WebhookEventModel) TableName() string {
+	return "webhook_events"
+}
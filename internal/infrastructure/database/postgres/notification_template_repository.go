@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationTemplateRepository implements domain.Notification.TemplateRepository interface
+type NotificationTemplateRepository struct {
+	db *DB
+}
+
+// NewNotificationTemplateRepository creates a new notification template repository
+func NewNotificationTemplateRepository(db *DB) domainNotification.TemplateRepository {
+	return &NotificationTemplateRepository{db: db}
+}
+
+func (r *NotificationTemplateRepository) CreateVersion(ctx context.Context, t *domainNotification.Template) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize writers of the same template so versions stay gapless
+		var current []models.NotificationTemplateModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("event = ? AND channel = ? AND language = ?", t.Event, string(t.Channel), t.Language).
+			Find(&current).Error; err != nil {
+			return fmt.Errorf("failed to lock template versions: %w", err)
+		}
+
+		version := 0
+		for _, m := range current {
+			if m.Version > version {
+				version = m.Version
+			}
+		}
+
+		if err := deactivateTemplate(tx, t.Event, string(t.Channel), t.Language); err != nil {
+			return err
+		}
+
+		if t.ID == uuid.Nil {
+			t.ID = uuid.New()
+		}
+		t.Version = version + 1
+		t.IsActive = true
+		t.CreatedAt = time.Now()
+
+		if err := tx.Create(toNotificationTemplateModel(t)).Error; err != nil {
+			return fmt.Errorf("failed to create template: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *NotificationTemplateRepository) GetByID(ctx context.Context, templateID uuid.UUID) (*domainNotification.Template, error) {
+	var dbModel models.NotificationTemplateModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", templateID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainNotification.ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return toNotificationTemplateEntity(&dbModel), nil
+}
+
+func (r *NotificationTemplateRepository) GetActive(ctx context.Context, event string, channel domainNotification.Channel, language string) (*domainNotification.Template, error) {
+	var dbModel models.NotificationTemplateModel
+	err := r.db.DB.WithContext(ctx).
+		Where("event = ? AND channel = ? AND language = ? AND is_active = ?", event, string(channel), language, true).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainNotification.ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active template: %w", err)
+	}
+
+	return toNotificationTemplateEntity(&dbModel), nil
+}
+
+func (r *NotificationTemplateRepository) ListActive(ctx context.Context) ([]*domainNotification.Template, error) {
+	var dbModels []models.NotificationTemplateModel
+	err := r.db.DB.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("event ASC, channel ASC, language ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	return toNotificationTemplateEntities(dbModels), nil
+}
+
+func (r *NotificationTemplateRepository) ListVersions(ctx context.Context, event string, channel domainNotification.Channel, language string) ([]*domainNotification.Template, error) {
+	var dbModels []models.NotificationTemplateModel
+	err := r.db.DB.WithContext(ctx).
+		Where("event = ? AND channel = ? AND language = ?", event, string(channel), language).
+		Order("version DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template versions: %w", err)
+	}
+
+	return toNotificationTemplateEntities(dbModels), nil
+}
+
+func (r *NotificationTemplateRepository) Activate(ctx context.Context, templateID uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dbModel models.NotificationTemplateModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dbModel, "id = ?", templateID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainNotification.ErrTemplateNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get template: %w", err)
+		}
+
+		if err := deactivateTemplate(tx, dbModel.Event, dbModel.Channel, dbModel.Language); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.NotificationTemplateModel{}).
+			Where("id = ?", templateID).
+			Update("is_active", true).Error; err != nil {
+			return fmt.Errorf("failed to activate template: %w", err)
+		}
+		return nil
+	})
+}
+
+func deactivateTemplate(tx *gorm.DB, event, channel, language string) error {
+	err := tx.Model(&models.NotificationTemplateModel{}).
+		Where("event = ? AND channel = ? AND language = ? AND is_active = ?", event, channel, language, true).
+		Update("is_active", false).Error
+	if err != nil {
+		return fmt.Errorf("failed to deactivate template: %w", err)
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toNotificationTemplateModel(t *domainNotification.Template) *models.NotificationTemplateModel {
+	return &models.NotificationTemplateModel{
+		ID:        t.ID,
+		Event:     t.Event,
+		Channel:   string(t.Channel),
+		Language:  t.Language,
+		Version:   t.Version,
+		Subject:   t.Subject,
+		Body:      t.Body,
+		IsActive:  t.IsActive,
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func toNotificationTemplateEntity(m *models.NotificationTemplateModel) *domainNotification.Template {
+	return &domainNotification.Template{
+		ID:        m.ID,
+		Event:     m.Event,
+		Channel:   domainNotification.Channel(m.Channel),
+		Language:  m.Language,
+		Version:   m.Version,
+		Subject:   m.Subject,
+		Body:      m.Body,
+		IsActive:  m.IsActive,
+		CreatedBy: m.CreatedBy,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func toNotificationTemplateEntities(dbModels []models.NotificationTemplateModel) []*domainNotification.Template {
+	templates := make([]*domainNotification.Template, len(dbModels))
+	for i := range dbModels {
+		templates[i] = toNotificationTemplateEntity(&dbModels[i])
+	}
+	return templates
+}
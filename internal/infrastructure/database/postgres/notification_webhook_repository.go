@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxWebhookErrorLength bounds the stored delivery error message
+const maxWebhookErrorLength = 500
+
+// NotificationWebhookRepository implements domain.Notification.WebhookRepository interface
+type NotificationWebhookRepository struct {
+	db *DB
+}
+
+// NewNotificationWebhookRepository creates a new webhook repository
+func NewNotificationWebhookRepository(db *DB) domainNotification.WebhookRepository {
+	return &NotificationWebhookRepository{db: db}
+}
+
+func (r *NotificationWebhookRepository) Create(ctx context.Context, hook *domainNotification.Webhook) error {
+	if hook.ID == uuid.Nil {
+		hook.ID = uuid.New()
+	}
+	now := time.Now()
+	hook.CreatedAt = now
+	hook.UpdatedAt = now
+
+	if err := r.db.DB.WithContext(ctx).Create(toNotificationWebhookModel(hook)).Error; err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationWebhookRepository) GetByID(ctx context.Context, webhookID uuid.UUID) (*domainNotification.Webhook, error) {
+	var dbModel models.NotificationWebhookModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", webhookID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainNotification.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return toNotificationWebhookEntity(&dbModel), nil
+}
+
+func (r *NotificationWebhookRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domainNotification.Webhook, error) {
+	var dbModels []models.NotificationWebhookModel
+	err := r.db.DB.WithContext(ctx).
+		Where("owner_id = ?", ownerID).
+		Order("created_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return toNotificationWebhookEntities(dbModels), nil
+}
+
+func (r *NotificationWebhookRepository) Update(ctx context.Context, hook *domainNotification.Webhook) error {
+	hook.UpdatedAt = time.Now()
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.NotificationWebhookModel{}).
+		Where("id = ?", hook.ID).
+		Select("name", "url", "min_severity", "daily_digest", "is_active", "payload_filters", "consecutive_failures",
+			"signing_secret", "previous_signing_secret", "secret_rotated_at", "updated_at").
+		Updates(toNotificationWebhookModel(hook))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainNotification.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *NotificationWebhookRepository) Delete(ctx context.Context, webhookID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).Delete(&models.NotificationWebhookModel{}, "id = ?", webhookID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainNotification.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *NotificationWebhookRepository) ListForRecipient(ctx context.Context, userID uuid.UUID) ([]*domainNotification.Webhook, error) {
+	query := r.db.DB.WithContext(ctx).Where("is_active = ?", true)
+	if userID == uuid.Nil {
+		query = query.Where("scope = ?", string(domainNotification.WebhookScopePlatform))
+	} else {
+		query = query.Where("owner_id = ? AND scope = ?", userID, string(domainNotification.WebhookScopeOwner))
+	}
+
+	var dbModels []models.NotificationWebhookModel
+	if err := query.Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return toNotificationWebhookEntities(dbModels), nil
+}
+
+func (r *NotificationWebhookRepository) ListDigestSubscribers(ctx context.Context) ([]*domainNotification.Webhook, error) {
+	var dbModels []models.NotificationWebhookModel
+	err := r.db.DB.WithContext(ctx).
+		Where("is_active = ? AND daily_digest = ?", true, true).
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list digest webhooks: %w", err)
+	}
+
+	return toNotificationWebhookEntities(dbModels), nil
+}
+
+func (r *NotificationWebhookRepository) RecordDelivery(ctx context.Context, webhookID uuid.UUID, at time.Time, deliveryErr error) error {
+	updates := map[string]interface{}{
+		"last_delivery_at": at,
+		"updated_at":       time.Now(),
+	}
+	if deliveryErr == nil {
+		updates["last_success_at"] = at
+		updates["last_error"] = nil
+		updates["consecutive_failures"] = 0
+	} else {
+		message := deliveryErr.Error()
+		if len(message) > maxWebhookErrorLength {
+			message = message[:maxWebhookErrorLength]
+		}
+		updates["last_error"] = message
+		updates["consecutive_failures"] = gorm.Expr("consecutive_failures + 1")
+	}
+
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.NotificationWebhookModel{}).
+		Where("id = ?", webhookID).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toNotificationWebhookModel(w *domainNotification.Webhook) *models.NotificationWebhookModel {
+	return &models.NotificationWebhookModel{
+		ID:                  w.ID,
+		OwnerID:             w.OwnerID,
+		Name:                w.Name,
+		Kind:                string(w.Kind),
+		URL:                 w.URL,
+		Scope:               string(w.Scope),
+		MinSeverity:         string(w.MinSeverity),
+		DailyDigest:         w.DailyDigest,
+		IsActive:            w.IsActive,
+		PayloadFilters:      toWebhookPayloadFilterModels(w.PayloadFilters),
+		SigningSecret:       w.SigningSecret,
+		PreviousSecret:      w.PreviousSigningSecret,
+		SecretRotatedAt:     w.SecretRotatedAt,
+		LastDeliveryAt:      w.LastDeliveryAt,
+		LastSuccessAt:       w.LastSuccessAt,
+		LastError:           w.LastError,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		CreatedAt:           w.CreatedAt,
+		UpdatedAt:           w.UpdatedAt,
+	}
+}
+
+func toNotificationWebhookEntity(m *models.NotificationWebhookModel) *domainNotification.Webhook {
+	return &domainNotification.Webhook{
+		ID:                    m.ID,
+		OwnerID:               m.OwnerID,
+		Name:                  m.Name,
+		Kind:                  domainNotification.WebhookKind(m.Kind),
+		URL:                   m.URL,
+		Scope:                 domainNotification.WebhookScope(m.Scope),
+		MinSeverity:           domainNotification.Severity(m.MinSeverity),
+		DailyDigest:           m.DailyDigest,
+		IsActive:              m.IsActive,
+		PayloadFilters:        toWebhookPayloadFilters(m.PayloadFilters),
+		SigningSecret:         m.SigningSecret,
+		PreviousSigningSecret: m.PreviousSecret,
+		SecretRotatedAt:       m.SecretRotatedAt,
+		LastDeliveryAt:        m.LastDeliveryAt,
+		LastSuccessAt:         m.LastSuccessAt,
+		LastError:             m.LastError,
+		ConsecutiveFailures:   m.ConsecutiveFailures,
+		CreatedAt:             m.CreatedAt,
+		UpdatedAt:             m.UpdatedAt,
+	}
+}
+
+func toNotificationWebhookEntities(dbModels []models.NotificationWebhookModel) []*domainNotification.Webhook {
+	hooks := make([]*domainNotification.Webhook, len(dbModels))
+	for i := range dbModels {
+		hooks[i] = toNotificationWebhookEntity(&dbModels[i])
+	}
+	return hooks
+}
+
+func toWebhookPayloadFilterModels(filters map[string]domainNotification.PayloadFilter) map[string]models.WebhookPayloadFilter {
+	m := make(map[string]models.WebhookPayloadFilter, len(filters))
+	for event, f := range filters {
+		m[event] = models.WebhookPayloadFilter{Preset: string(f.Preset), Fields: f.Fields}
+	}
+	return m
+}
+
+func toWebhookPayloadFilters(m map[string]models.WebhookPayloadFilter) map[string]domainNotification.PayloadFilter {
+	if len(m) == 0 {
+		return nil
+	}
+	filters := make(map[string]domainNotification.PayloadFilter, len(m))
+	for event, f := range m {
+		filters[event] = domainNotification.PayloadFilter{Preset: domainNotification.PayloadPreset(f.Preset), Fields: f.Fields}
+	}
+	return filters
+}
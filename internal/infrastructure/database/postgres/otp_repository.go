@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OTPRepository implements domain.User.OTPRepository interface
+type OTPRepository struct {
+	db *DB
+}
+
+// NewOTPRepository creates a new one-time password repository
+func NewOTPRepository(db *DB) user.OTPRepository {
+	return &OTPRepository{db: db}
+}
+
+func (r *OTPRepository) Create(ctx context.Context, otp *user.PhoneOTP) error {
+	otp.ID = uuid.New()
+	otp.CreatedAt = time.Now()
+
+	if err := r.db.DB.WithContext(ctx).Create(toPhoneOTPModel(otp)).Error; err != nil {
+		return fmt.Errorf("failed to create otp: %w", err)
+	}
+	return nil
+}
+
+func (r *OTPRepository) GetLatest(ctx context.Context, userID uuid.UUID, purpose user.OTPPurpose) (*user.PhoneOTP, error) {
+	var dbModel models.PhoneOTPModel
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ? AND purpose = ? AND consumed_at IS NULL", userID, string(purpose)).
+		Order("created_at DESC").
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrOTPNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get otp: %w", err)
+	}
+
+	return toPhoneOTPEntity(&dbModel), nil
+}
+
+func (r *OTPRepository) CountSince(ctx context.Context, userID uuid.UUID, purpose user.OTPPurpose, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.PhoneOTPModel{}).
+		Where("user_id = ? AND purpose = ? AND created_at >= ?", userID, string(purpose), since).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count otps: %w", err)
+	}
+	return count, nil
+}
+
+func (r *OTPRepository) IncrementAttempts(ctx context.Context, otpID uuid.UUID) error {
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.PhoneOTPModel{}).
+		Where("id = ?", otpID).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+	if err != nil {
+		return fmt.Errorf("failed to record otp attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *OTPRepository) Consume(ctx context.Context, otpID uuid.UUID, at time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.PhoneOTPModel{}).
+		Where("id = ? AND consumed_at IS NULL", otpID).
+		Update("consumed_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to consume otp: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Consumed concurrently; the code must not work twice
+		return user.ErrOTPNotFound
+	}
+	return nil
+}
+
+func (r *OTPRepository) DeleteExpired(ctx context.Context, olderThan time.Duration) error {
+	cutoffTime := time.Now().Add(-olderThan)
+	return r.db.DB.WithContext(ctx).
+		Where("expires_at < ?", cutoffTime).
+		Delete(&models.PhoneOTPModel{}).Error
+}
+
+// Helper functions to convert between domain entities and database models
+func toPhoneOTPModel(o *user.PhoneOTP) *models.PhoneOTPModel {
+	return &models.PhoneOTPModel{
+		ID:         o.ID,
+		UserID:     o.UserID,
+		Purpose:    string(o.Purpose),
+		CodeHash:   o.CodeHash,
+		ExpiresAt:  o.ExpiresAt,
+		Attempts:   o.Attempts,
+		ConsumedAt: o.ConsumedAt,
+		CreatedAt:  o.CreatedAt,
+	}
+}
+
+func toPhoneOTPEntity(m *models.PhoneOTPModel) *user.PhoneOTP {
+	return &user.PhoneOTP{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Purpose:    user.OTPPurpose(m.Purpose),
+		CodeHash:   m.CodeHash,
+		ExpiresAt:  m.ExpiresAt,
+		Attempts:   m.Attempts,
+		ConsumedAt: m.ConsumedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	domainInterop "cargo-tracker/internal/domain/interop"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PartnerMappingRepository implements domain.Interop.Repository interface
+type PartnerMappingRepository struct {
+	db *DB
+}
+
+// NewPartnerMappingRepository creates a new partner mapping repository
+func NewPartnerMappingRepository(db *DB) domainInterop.Repository {
+	return &PartnerMappingRepository{db: db}
+}
+
+func (r *PartnerMappingRepository) Create(ctx context.Context, mapping *domainInterop.PartnerMapping) error {
+	if mapping.ID == uuid.Nil {
+		mapping.ID = uuid.New()
+	}
+	now := time.Now()
+	mapping.CreatedAt = now
+	mapping.UpdatedAt = now
+
+	if err := r.db.DB.WithContext(ctx).Create(toPartnerMappingModel(mapping)).Error; err != nil {
+		return fmt.Errorf("failed to create partner mapping: %w", err)
+	}
+	return nil
+}
+
+func (r *PartnerMappingRepository) GetByID(ctx context.Context, mappingID uuid.UUID) (*domainInterop.PartnerMapping, error) {
+	var dbModel models.PartnerMappingModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", mappingID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainInterop.ErrPartnerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner mapping: %w", err)
+	}
+
+	return toPartnerMappingEntity(&dbModel), nil
+}
+
+func (r *PartnerMappingRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domainInterop.PartnerMapping, error) {
+	var dbModels []models.PartnerMappingModel
+	err := r.db.DB.WithContext(ctx).
+		Where("owner_id = ?", ownerID).
+		Order("created_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partner mappings: %w", err)
+	}
+
+	mappings := make([]*domainInterop.PartnerMapping, len(dbModels))
+	for i := range dbModels {
+		mappings[i] = toPartnerMappingEntity(&dbModels[i])
+	}
+	return mappings, nil
+}
+
+func (r *PartnerMappingRepository) Update(ctx context.Context, mapping *domainInterop.PartnerMapping) error {
+	mapping.UpdatedAt = time.Now()
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.PartnerMappingModel{}).
+		Where("id = ?", mapping.ID).
+		Select("name", "format", "records_path", "import_fields", "default_provider_id", "export_fields", "is_active", "updated_at").
+		Updates(toPartnerMappingModel(mapping))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update partner mapping: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainInterop.ErrPartnerNotFound
+	}
+	return nil
+}
+
+func (r *PartnerMappingRepository) Delete(ctx context.Context, mappingID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).Delete(&models.PartnerMappingModel{}, "id = ?", mappingID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete partner mapping: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainInterop.ErrPartnerNotFound
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toPartnerMappingModel(m *domainInterop.PartnerMapping) *models.PartnerMappingModel {
+	importFields := m.ImportFields
+	if importFields == nil {
+		importFields = map[string]string{}
+	}
+	exportFields := m.ExportFields
+	if exportFields == nil {
+		exportFields = map[string]string{}
+	}
+
+	return &models.PartnerMappingModel{
+		ID:                m.ID,
+		OwnerID:           m.OwnerID,
+		Name:              m.Name,
+		Format:            string(m.Format),
+		RecordsPath:       m.RecordsPath,
+		ImportFields:      importFields,
+		DefaultProviderID: m.DefaultProviderID,
+		ExportFields:      exportFields,
+		IsActive:          m.IsActive,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
+
+func toPartnerMappingEntity(m *models.PartnerMappingModel) *domainInterop.PartnerMapping {
+	return &domainInterop.PartnerMapping{
+		ID:                m.ID,
+		OwnerID:           m.OwnerID,
+		Name:              m.Name,
+		Format:            domainInterop.Format(m.Format),
+		RecordsPath:       m.RecordsPath,
+		ImportFields:      m.ImportFields,
+		DefaultProviderID: m.DefaultProviderID,
+		ExportFields:      m.ExportFields,
+		IsActive:          m.IsActive,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
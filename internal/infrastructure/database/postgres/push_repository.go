@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// PushRepository implements domain.Notification.PushRepository interface
+type PushRepository struct {
+	db *DB
+}
+
+// NewPushRepository creates a new push token repository
+func NewPushRepository(db *DB) domainNotification.PushRepository {
+	return &PushRepository{db: db}
+}
+
+func (r *PushRepository) SaveToken(ctx context.Context, token *domainNotification.PushToken) error {
+	now := time.Now()
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	token.LastSeenAt = now
+	token.CreatedAt = now
+
+	dbModel := toPushTokenModel(token)
+	err := r.db.DB.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "token"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "device_name", "app_version", "last_seen_at"}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "created_at"}}},
+	).Create(dbModel).Error
+	if err != nil {
+		return fmt.Errorf("failed to save push token: %w", err)
+	}
+
+	token.ID = dbModel.ID
+	token.CreatedAt = dbModel.CreatedAt
+	return nil
+}
+
+func (r *PushRepository) ListTokens(ctx context.Context, userID uuid.UUID) ([]*domainNotification.PushToken, error) {
+	return r.ListTokensForUsers(ctx, []uuid.UUID{userID})
+}
+
+func (r *PushRepository) ListTokensForUsers(ctx context.Context, userIDs []uuid.UUID) ([]*domainNotification.PushToken, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var dbModels []models.PushTokenModel
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Order("last_seen_at DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push tokens: %w", err)
+	}
+
+	tokens := make([]*domainNotification.PushToken, len(dbModels))
+	for i := range dbModels {
+		tokens[i] = toPushTokenEntity(&dbModels[i])
+	}
+	return tokens, nil
+}
+
+func (r *PushRepository) DeleteToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", tokenID, userID).
+		Delete(&models.PushTokenModel{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete push token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainNotification.ErrPushTokenNotFound
+	}
+	return nil
+}
+
+func (r *PushRepository) DeleteByValue(ctx context.Context, provider domainNotification.PushProvider, token string) error {
+	err := r.db.DB.WithContext(ctx).
+		Where("provider = ? AND token = ?", string(provider), token).
+		Delete(&models.PushTokenModel{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete push token: %w", err)
+	}
+	return nil
+}
+
+func (r *PushRepository) DeleteStale(ctx context.Context, notSeenSince time.Time) (int64, error) {
+	result := r.db.DB.WithContext(ctx).
+		Where("last_seen_at < ?", notSeenSince).
+		Delete(&models.PushTokenModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete stale push tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *PushRepository) Subscribe(ctx context.Context, userID uuid.UUID, topic string) error {
+	err := r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.PushSubscriptionModel{UserID: userID, Topic: topic, CreatedAt: time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to topic: %w", err)
+	}
+	return nil
+}
+
+func (r *PushRepository) Unsubscribe(ctx context.Context, userID uuid.UUID, topic string) error {
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ? AND topic = ?", userID, topic).
+		Delete(&models.PushSubscriptionModel{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe from topic: %w", err)
+	}
+	return nil
+}
+
+func (r *PushRepository) ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]*domainNotification.PushSubscription, error) {
+	var dbModels []models.PushSubscriptionModel
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push subscriptions: %w", err)
+	}
+
+	subs := make([]*domainNotification.PushSubscription, len(dbModels))
+	for i, m := range dbModels {
+		subs[i] = &domainNotification.PushSubscription{UserID: m.UserID, Topic: m.Topic, CreatedAt: m.CreatedAt}
+	}
+	return subs, nil
+}
+
+func (r *PushRepository) ListSubscribers(ctx context.Context, topic string) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.PushSubscriptionModel{}).
+		Where("topic = ?", topic).
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topic subscribers: %w", err)
+	}
+	return userIDs, nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toPushTokenModel(t *domainNotification.PushToken) *models.PushTokenModel {
+	return &models.PushTokenModel{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Provider:   string(t.Provider),
+		Token:      t.Token,
+		Platform:   t.Platform,
+		DeviceName: t.DeviceName,
+		AppVersion: t.AppVersion,
+		LastSeenAt: t.LastSeenAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+func toPushTokenEntity(m *models.PushTokenModel) *domainNotification.PushToken {
+	return &domainNotification.PushToken{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Provider:   domainNotification.PushProvider(m.Provider),
+		Token:      m.Token,
+		Platform:   m.Platform,
+		DeviceName: m.DeviceName,
+		AppVersion: m.AppVersion,
+		LastSeenAt: m.LastSeenAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainQuota "cargo-tracker/internal/domain/quota"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QuotaRepository implements domain.Quota.Repository interface
+type QuotaRepository struct {
+	db *DB
+}
+
+// NewQuotaRepository creates a new quota repository
+func NewQuotaRepository(db *DB) domainQuota.Repository {
+	return &QuotaRepository{db: db}
+}
+
+func (r *QuotaRepository) IncrementAPICalls(ctx context.Context, userID uuid.UUID, periodStart time.Time) (int64, error) {
+	// A single upsert, so concurrent requests never lose a count
+	var count int64
+	err := r.db.DB.WithContext(ctx).Raw(`
+		INSERT INTO usage_counters (user_id, metric, period_start, count, updated_at)
+		VALUES (?, ?, ?, 1, NOW())
+		ON CONFLICT (user_id, metric, period_start)
+		DO UPDATE SET count = usage_counters.count + 1, updated_at = NOW()
+		RETURNING count`,
+		userID, string(domainQuota.MetricAPICalls), periodStart,
+	).Scan(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count api call: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *QuotaRepository) GetAPICalls(ctx context.Context, userID uuid.UUID, periodStart time.Time) (int64, error) {
+	var dbModel models.UsageCounterModel
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ? AND metric = ? AND period_start = ?", userID, string(domainQuota.MetricAPICalls), periodStart).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get api calls: %w", err)
+	}
+
+	return dbModel.Count, nil
+}
+
+func (r *QuotaRepository) CountActiveShipments(ctx context.Context, userID uuid.UUID, role string) (int64, error) {
+	column, ok := reportPartyColumns[role]
+	if !ok {
+		return 0, nil
+	}
+
+	db := r.db.DB.WithContext(ctx).Model(&models.ShipmentModel{}).
+		Where(column+" = ?", userID).
+		// Finished shipments no longer count towards the quota
+		Where("status NOT IN ?", finishedShipmentStatuses)
+	if role == "provider" {
+		db = db.Where("status <> ?", string(domainShipment.StatusDemandCreated))
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count active shipments: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *QuotaRepository) CountDevices(ctx context.Context, shipperID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.DB.WithContext(ctx).Model(&models.DeviceModel{}).
+		Where("owner_shipper_id = ?", shipperID).
+		Where("status <> ?", string(domainDevice.StatusRetired)).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count devices: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,335 @@
+package postgres
+
+import (
+	domainQuotation "cargo-tracker/internal/domain/quotation"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QuotationRepository implements domain.Quotation.Repository interface
+type QuotationRepository struct {
+	db *DB
+}
+
+// NewQuotationRepository creates a new quotation repository
+func NewQuotationRepository(db *DB) domainQuotation.Repository {
+	return &QuotationRepository{db: db}
+}
+
+func (r *QuotationRepository) CreateRequest(ctx context.Context, request *domainQuotation.QuoteRequest, providerIDs []uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if request.ID == uuid.Nil {
+			request.ID = uuid.New()
+		}
+		request.CreatedAt = now
+		request.UpdatedAt = now
+
+		if err := tx.Omit("Quotes").Create(toQuoteRequestModel(request)).Error; err != nil {
+			return fmt.Errorf("failed to create quote request: %w", err)
+		}
+
+		request.Quotes = make([]*domainQuotation.Quote, 0, len(providerIDs))
+		for _, providerID := range providerIDs {
+			quote := &domainQuotation.Quote{
+				ID:         uuid.New(),
+				RequestID:  request.ID,
+				ProviderID: providerID,
+				Status:     domainQuotation.QuoteRequested,
+				Currency:   "VND",
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			if err := tx.Create(toQuoteModel(quote)).Error; err != nil {
+				return fmt.Errorf("failed to create quote: %w", err)
+			}
+			request.Quotes = append(request.Quotes, quote)
+		}
+		return nil
+	})
+}
+
+func (r *QuotationRepository) GetRequestByID(ctx context.Context, requestID uuid.UUID) (*domainQuotation.QuoteRequest, error) {
+	var dbModel models.QuoteRequestModel
+	err := r.db.DB.WithContext(ctx).
+		Preload("Quotes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&dbModel, "id = ?", requestID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainQuotation.ErrQuoteRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote request: %w", err)
+	}
+
+	return toQuoteRequestEntity(&dbModel), nil
+}
+
+func (r *QuotationRepository) ListRequestsByCustomer(ctx context.Context, customerID uuid.UUID, page, pageSize int) ([]*domainQuotation.QuoteRequest, int64, error) {
+	var dbModels []models.QuoteRequestModel
+	var total int64
+
+	query := r.db.DB.WithContext(ctx).
+		Model(&models.QuoteRequestModel{}).
+		Where("customer_id = ?", customerID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count quote requests: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.
+		Preload("Quotes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Order("created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list quote requests: %w", err)
+	}
+
+	requests := make([]*domainQuotation.QuoteRequest, len(dbModels))
+	for i := range dbModels {
+		requests[i] = toQuoteRequestEntity(&dbModels[i])
+	}
+	return requests, total, nil
+}
+
+func (r *QuotationRepository) GetQuoteByID(ctx context.Context, quoteID uuid.UUID) (*domainQuotation.Quote, error) {
+	var dbModel models.QuoteModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", quoteID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainQuotation.ErrQuoteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	return toQuoteEntity(&dbModel), nil
+}
+
+func (r *QuotationRepository) ListQuotesByProvider(ctx context.Context, providerID uuid.UUID, status *domainQuotation.QuoteStatus, page, pageSize int) ([]*domainQuotation.Quote, int64, error) {
+	var dbModels []models.QuoteModel
+	var total int64
+
+	query := r.db.DB.WithContext(ctx).
+		Model(&models.QuoteModel{}).
+		Where("provider_id = ?", providerID)
+	if status != nil {
+		query = query.Where("status = ?", string(*status))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count quotes: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.
+		Order("created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list quotes: %w", err)
+	}
+
+	quotes := make([]*domainQuotation.Quote, len(dbModels))
+	for i := range dbModels {
+		quotes[i] = toQuoteEntity(&dbModels[i])
+	}
+	return quotes, total, nil
+}
+
+func (r *QuotationRepository) UpdateQuote(ctx context.Context, quote *domainQuotation.Quote) error {
+	quote.UpdatedAt = time.Now()
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.QuoteModel{}).
+		Where("id = ?", quote.ID).
+		Updates(map[string]interface{}{
+			"status":          string(quote.Status),
+			"price":           quote.Price,
+			"currency":        quote.Currency,
+			"lead_time_hours": quote.LeadTimeHours,
+			"valid_until":     quote.ValidUntil,
+			"notes":           quote.Notes,
+			"responded_at":    quote.RespondedAt,
+			"updated_at":      quote.UpdatedAt,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quote: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainQuotation.ErrQuoteNotFound
+	}
+	return nil
+}
+
+func (r *QuotationRepository) AcceptQuote(ctx context.Context, requestID, quoteID, shipmentID uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// Guard against two concurrent accepts on the same request
+		result := tx.Model(&models.QuoteRequestModel{}).
+			Where("id = ? AND status = ?", requestID, string(domainQuotation.RequestOpen)).
+			Updates(map[string]interface{}{
+				"status":      string(domainQuotation.RequestAccepted),
+				"shipment_id": shipmentID,
+				"updated_at":  now,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to accept quote request: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domainQuotation.ErrRequestClosed
+		}
+
+		result = tx.Model(&models.QuoteModel{}).
+			Where("id = ? AND request_id = ? AND status = ?", quoteID, requestID, string(domainQuotation.QuoteSubmitted)).
+			Updates(map[string]interface{}{
+				"status":     string(domainQuotation.QuoteAccepted),
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to accept quote: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domainQuotation.ErrQuoteNotSubmitted
+		}
+
+		return rejectOpenQuotes(tx, requestID, now)
+	})
+}
+
+func (r *QuotationRepository) CloseRequest(ctx context.Context, requestID uuid.UUID, status domainQuotation.RequestStatus) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&models.QuoteRequestModel{}).
+			Where("id = ? AND status = ?", requestID, string(domainQuotation.RequestOpen)).
+			Updates(map[string]interface{}{
+				"status":     string(status),
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to close quote request: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domainQuotation.ErrRequestClosed
+		}
+
+		return rejectOpenQuotes(tx, requestID, now)
+	})
+}
+
+// rejectOpenQuotes rejects every quote of the request that was not accepted
+func rejectOpenQuotes(tx *gorm.DB, requestID uuid.UUID, now time.Time) error {
+	err := tx.Model(&models.QuoteModel{}).
+		Where("request_id = ? AND status IN ?", requestID, []string{
+			string(domainQuotation.QuoteRequested),
+			string(domainQuotation.QuoteSubmitted),
+		}).
+		Updates(map[string]interface{}{
+			"status":     string(domainQuotation.QuoteRejected),
+			"updated_at": now,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reject quotes: %w", err)
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toQuoteRequestModel(r *domainQuotation.QuoteRequest) *models.QuoteRequestModel {
+	return &models.QuoteRequestModel{
+		ID:                  r.ID,
+		CustomerID:          r.CustomerID,
+		Status:              string(r.Status),
+		GoodsDescription:    r.GoodsDescription,
+		GoodsValue:          r.GoodsValue,
+		GoodsCurrency:       r.GoodsCurrency,
+		GoodsWeight:         r.GoodsWeight,
+		PickupAddress:       r.PickupAddress,
+		DeliveryAddress:     r.DeliveryAddress,
+		EstimatedPickupAt:   r.EstimatedPickupAt,
+		EstimatedDeliveryAt: r.EstimatedDeliveryAt,
+		CustomerNotes:       r.CustomerNotes,
+		ExpiresAt:           r.ExpiresAt,
+		ShipmentID:          r.ShipmentID,
+		CreatedAt:           r.CreatedAt,
+		UpdatedAt:           r.UpdatedAt,
+	}
+}
+
+func toQuoteRequestEntity(m *models.QuoteRequestModel) *domainQuotation.QuoteRequest {
+	quotes := make([]*domainQuotation.Quote, len(m.Quotes))
+	for i := range m.Quotes {
+		quotes[i] = toQuoteEntity(&m.Quotes[i])
+	}
+
+	return &domainQuotation.QuoteRequest{
+		ID:                  m.ID,
+		CustomerID:          m.CustomerID,
+		Status:              domainQuotation.RequestStatus(m.Status),
+		GoodsDescription:    m.GoodsDescription,
+		GoodsValue:          m.GoodsValue,
+		GoodsCurrency:       m.GoodsCurrency,
+		GoodsWeight:         m.GoodsWeight,
+		PickupAddress:       m.PickupAddress,
+		DeliveryAddress:     m.DeliveryAddress,
+		EstimatedPickupAt:   m.EstimatedPickupAt,
+		EstimatedDeliveryAt: m.EstimatedDeliveryAt,
+		CustomerNotes:       m.CustomerNotes,
+		ExpiresAt:           m.ExpiresAt,
+		ShipmentID:          m.ShipmentID,
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+		Quotes:              quotes,
+	}
+}
+
+func toQuoteModel(q *domainQuotation.Quote) *models.QuoteModel {
+	return &models.QuoteModel{
+		ID:            q.ID,
+		RequestID:     q.RequestID,
+		ProviderID:    q.ProviderID,
+		Status:        string(q.Status),
+		Price:         q.Price,
+		Currency:      q.Currency,
+		LeadTimeHours: q.LeadTimeHours,
+		ValidUntil:    q.ValidUntil,
+		Notes:         q.Notes,
+		RespondedAt:   q.RespondedAt,
+		CreatedAt:     q.CreatedAt,
+		UpdatedAt:     q.UpdatedAt,
+	}
+}
+
+func toQuoteEntity(m *models.QuoteModel) *domainQuotation.Quote {
+	return &domainQuotation.Quote{
+		ID:            m.ID,
+		RequestID:     m.RequestID,
+		ProviderID:    m.ProviderID,
+		Status:        domainQuotation.QuoteStatus(m.Status),
+		Price:         m.Price,
+		Currency:      m.Currency,
+		LeadTimeHours: m.LeadTimeHours,
+		ValidUntil:    m.ValidUntil,
+		Notes:         m.Notes,
+		RespondedAt:   m.RespondedAt,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
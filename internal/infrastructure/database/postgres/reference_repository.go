@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	domainReference "cargo-tracker/internal/domain/reference"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"fmt"
+)
+
+// ReferenceRepository implements domain.Reference.Repository interface
+type ReferenceRepository struct {
+	db *DB
+}
+
+// NewReferenceRepository creates a new reference repository
+func NewReferenceRepository(db *DB) domainReference.Repository {
+	return &ReferenceRepository{db: db}
+}
+
+func (r *ReferenceRepository) Load(ctx context.Context) (*domainReference.Data, error) {
+	db := r.db.DB.WithContext(ctx)
+
+	var roles []models.RoleRefModel
+	if err := db.Order("id").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+	var issueTypes []models.IssueTypeRefModel
+	if err := db.Order("id").Find(&issueTypes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load issue types: %w", err)
+	}
+	var violationTypes []models.ViolationTypeRefModel
+	if err := db.Order("id").Find(&violationTypes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load violation types: %w", err)
+	}
+	var goodsCategories []models.GoodsCategoryRefModel
+	if err := db.Order("id").Find(&goodsCategories).Error; err != nil {
+		return nil, fmt.Errorf("failed to load goods categories: %w", err)
+	}
+
+	data := &domainReference.Data{
+		Roles:           make([]domainReference.Entry, len(roles)),
+		IssueTypes:      make([]domainReference.Entry, len(issueTypes)),
+		ViolationTypes:  make([]domainReference.ViolationType, len(violationTypes)),
+		GoodsCategories: make([]domainReference.GoodsCategory, len(goodsCategories)),
+	}
+	for i, m := range roles {
+		data.Roles[i] = domainReference.Entry{ID: m.ID, Code: m.Code, Name: m.Name, Description: m.Description}
+	}
+	for i, m := range issueTypes {
+		data.IssueTypes[i] = domainReference.Entry{ID: m.ID, Code: m.Code, Name: m.Name, Description: m.Description}
+	}
+	for i, m := range violationTypes {
+		data.ViolationTypes[i] = domainReference.ViolationType{
+			Entry: domainReference.Entry{ID: m.ID, Code: m.Code, Name: m.Name, Description: m.Description},
+			Unit:  m.Unit,
+		}
+	}
+	for i, m := range goodsCategories {
+		data.GoodsCategories[i] = domainReference.GoodsCategory{
+			Entry:    domainReference.Entry{ID: m.ID, Code: m.Code, Name: m.Name, Description: m.Description},
+			TempMin:  m.TempMin,
+			TempMax:  m.TempMax,
+			Keywords: m.Keywords,
+		}
+	}
+
+	return data, nil
+}
@@ -27,6 +27,12 @@ func (r *RefreshTokenRepository) Create(ctx context.Context, token *user.Refresh
 	token.CreatedAt = time.Now()
 	token.UpdatedAt = time.Now()
 	token.Revoked = false
+	if token.SessionStartedAt.IsZero() {
+		token.SessionStartedAt = token.CreatedAt
+	}
+	if token.LastActivityAt.IsZero() {
+		token.LastActivityAt = token.CreatedAt
+	}
 
 	dbModel := toRefreshTokenModel(token)
 	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
@@ -56,15 +62,32 @@ func (r *RefreshTokenRepository) GetByToken(ctx context.Context, token string) (
 	return toRefreshTokenEntity(&dbModel), nil
 }
 
-func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenID uuid.UUID) error {
+func (r *RefreshTokenRepository) FindByToken(ctx context.Context, token string) (*user.RefreshToken, error) {
+	var dbModel models.RefreshTokenModel
+	err := r.db.DB.WithContext(ctx).
+		Where("token = ?", token).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	return toRefreshTokenEntity(&dbModel), nil
+}
+
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenID uuid.UUID, reason string) error {
 	now := time.Now()
 	result := r.db.DB.WithContext(ctx).
 		Model(&models.RefreshTokenModel{}).
 		Where("id = ? AND revoked = false", tokenID).
 		Updates(map[string]interface{}{
-			"revoked":    true,
-			"revoked_at": now,
-			"updated_at": now,
+			"revoked":       true,
+			"revoked_at":    now,
+			"revoke_reason": reason,
+			"updated_at":    now,
 		})
 
 	if result.Error != nil {
@@ -77,20 +100,39 @@ func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenID uuid.UUID)
 	return nil
 }
 
-func (r *RefreshTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+func (r *RefreshTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID, reason string) error {
 	now := time.Now()
 	result := r.db.DB.WithContext(ctx).
 		Model(&models.RefreshTokenModel{}).
 		Where("user_id = ? AND revoked = false", userID).
 		Updates(map[string]interface{}{
-			"revoked":    true,
-			"revoked_at": now,
-			"updated_at": now,
+			"revoked":       true,
+			"revoked_at":    now,
+			"revoke_reason": reason,
+			"updated_at":    now,
 		})
 
 	return result.Error
 }
 
+func (r *RefreshTokenRepository) RevokeIdle(ctx context.Context, idleSince time.Time) (int64, error) {
+	now := time.Now()
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.RefreshTokenModel{}).
+		Where("revoked = false AND expires_at > ? AND last_activity_at < ?", now, idleSince).
+		Updates(map[string]interface{}{
+			"revoked":       true,
+			"revoked_at":    now,
+			"revoke_reason": user.RevokeReasonInactivity,
+			"updated_at":    now,
+		})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke idle tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
 func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, olderThan time.Duration) error {
 	cutoffTime := time.Now().Add(-olderThan)
 	result := r.db.DB.WithContext(ctx).
@@ -127,15 +169,23 @@ func toRefreshTokenModel(t *user.RefreshToken) *models.RefreshTokenModel {
 		revokedAt = &t.RevokedAt
 	}
 
+	var revokeReason *string
+	if t.RevokeReason != "" {
+		revokeReason = &t.RevokeReason
+	}
+
 	return &models.RefreshTokenModel{
-		ID:        t.ID,
-		UserID:    t.UserID,
-		Token:     t.Token,
-		ExpiresAt: t.ExpiresAt,
-		Revoked:   t.Revoked,
-		RevokedAt: revokedAt,
-		CreatedAt: t.CreatedAt,
-		UpdatedAt: t.UpdatedAt,
+		ID:               t.ID,
+		UserID:           t.UserID,
+		Token:            t.Token,
+		ExpiresAt:        t.ExpiresAt,
+		Revoked:          t.Revoked,
+		RevokedAt:        revokedAt,
+		RevokeReason:     revokeReason,
+		SessionStartedAt: t.SessionStartedAt,
+		LastActivityAt:   t.LastActivityAt,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
 	}
 }
 
@@ -145,14 +195,22 @@ func toRefreshTokenEntity(m *models.RefreshTokenModel) *user.RefreshToken {
 		revokedAt = *m.RevokedAt
 	}
 
+	var revokeReason string
+	if m.RevokeReason != nil {
+		revokeReason = *m.RevokeReason
+	}
+
 	return &user.RefreshToken{
-		ID:        m.ID,
-		UserID:    m.UserID,
-		Token:     m.Token,
-		ExpiresAt: m.ExpiresAt,
-		Revoked:   m.Revoked,
-		RevokedAt: revokedAt,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:               m.ID,
+		UserID:           m.UserID,
+		Token:            m.Token,
+		ExpiresAt:        m.ExpiresAt,
+		Revoked:          m.Revoked,
+		RevokedAt:        revokedAt,
+		RevokeReason:     revokeReason,
+		SessionStartedAt: m.SessionStartedAt,
+		LastActivityAt:   m.LastActivityAt,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
 	}
 }
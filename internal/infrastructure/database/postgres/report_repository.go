@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	domainReport "cargo-tracker/internal/domain/report"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// reportPartyColumns maps a role to the shipments column naming its party
+var reportPartyColumns = map[string]string{
+	"customer": "customer_id",
+	"provider": "provider_id",
+	"shipper":  "shipper_id",
+}
+
+// ReportRepository implements domain.Report.Repository interface
+type ReportRepository struct {
+	db *DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *DB) domainReport.Repository {
+	return &ReportRepository{db: db}
+}
+
+func (r *ReportRepository) CreateSubscription(ctx context.Context, sub *domainReport.Subscription) error {
+	if sub.ID == uuid.Nil {
+		sub.ID = uuid.New()
+	}
+
+	if err := r.db.DB.WithContext(ctx).Create(toReportSubscriptionModel(sub)).Error; err != nil {
+		return fmt.Errorf("failed to create report subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *ReportRepository) GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*domainReport.Subscription, error) {
+	var dbModel models.ReportSubscriptionModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", subscriptionID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainReport.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report subscription: %w", err)
+	}
+
+	return toReportSubscriptionEntity(&dbModel), nil
+}
+
+func (r *ReportRepository) ListSubscriptions(ctx context.Context, ownerID uuid.UUID) ([]*domainReport.Subscription, error) {
+	var dbModels []models.ReportSubscriptionModel
+	if err := r.db.DB.WithContext(ctx).
+		Where("owner_id = ?", ownerID).
+		Order("created_at ASC").
+		Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list report subscriptions: %w", err)
+	}
+
+	return toReportSubscriptionEntities(dbModels), nil
+}
+
+func (r *ReportRepository) UpdateSubscription(ctx context.Context, sub *domainReport.Subscription) error {
+	// Map updates bypass GORM serializers, so encode the recipients explicitly
+	recipients, err := json.Marshal(sub.Recipients)
+	if err != nil {
+		return fmt.Errorf("failed to encode report recipients: %w", err)
+	}
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.ReportSubscriptionModel{}).
+		Where("id = ?", sub.ID).
+		Updates(map[string]interface{}{
+			"cadence":     string(sub.Cadence),
+			"format":      string(sub.Format),
+			"recipients":  string(recipients),
+			"timezone":    sub.Timezone,
+			"active":      sub.Active,
+			"next_run_at": sub.NextRunAt,
+			"updated_at":  time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update report subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainReport.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *ReportRepository) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Delete(&models.ReportSubscriptionModel{}, "id = ?", subscriptionID)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete report subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainReport.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *ReportRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domainReport.Subscription, error) {
+	var dbModels []models.ReportSubscriptionModel
+	if err := r.db.DB.WithContext(ctx).
+		Where("active AND next_run_at <= ?", now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list due report subscriptions: %w", err)
+	}
+
+	return toReportSubscriptionEntities(dbModels), nil
+}
+
+func (r *ReportRepository) Advance(ctx context.Context, subscriptionID uuid.UUID, due, next time.Time) (bool, error) {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.ReportSubscriptionModel{}).
+		Where("id = ? AND next_run_at = ?", subscriptionID, due).
+		Updates(map[string]interface{}{
+			"next_run_at": next,
+			"last_run_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to advance report subscription: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *ReportRepository) CreateDelivery(ctx context.Context, delivery *domainReport.Delivery) error {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+
+	dbModel := &models.ReportDeliveryModel{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		PeriodStart:    delivery.PeriodStart,
+		PeriodEnd:      delivery.PeriodEnd,
+		Status:         string(delivery.Status),
+		Recipients:     delivery.Recipients,
+		Error:          delivery.Error,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to record report delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *ReportRepository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*domainReport.Delivery, error) {
+	var dbModels []models.ReportDeliveryModel
+	if err := r.db.DB.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list report deliveries: %w", err)
+	}
+
+	deliveries := make([]*domainReport.Delivery, len(dbModels))
+	for i, m := range dbModels {
+		deliveries[i] = &domainReport.Delivery{
+			ID:             m.ID,
+			SubscriptionID: m.SubscriptionID,
+			PeriodStart:    m.PeriodStart,
+			PeriodEnd:      m.PeriodEnd,
+			Status:         domainReport.DeliveryStatus(m.Status),
+			Recipients:     m.Recipients,
+			Error:          m.Error,
+			CreatedAt:      m.CreatedAt,
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *ReportRepository) ShipmentSummary(ctx context.Context, userID uuid.UUID, role string, from, to time.Time) (*domainReport.ShipmentSummary, error) {
+	column, ok := reportPartyColumns[role]
+	if !ok {
+		return nil, fmt.Errorf("no shipment summary for role %q", role)
+	}
+
+	var row struct {
+		Created        int
+		Completed      int
+		OnTime         int
+		Cancelled      int
+		IssuesReported int
+		InTransit      int
+		Overdue        int
+	}
+	err := r.db.DB.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) FILTER (WHERE created_at >= @from AND created_at < @to) AS created,
+			COUNT(*) FILTER (WHERE status IN ('completed', 'partially_completed')
+				AND actual_delivery_at >= @from AND actual_delivery_at < @to) AS completed,
+			COUNT(*) FILTER (WHERE status IN ('completed', 'partially_completed')
+				AND actual_delivery_at >= @from AND actual_delivery_at < @to
+				AND actual_delivery_at <= COALESCE(delivery_due_at, estimated_delivery_at)) AS on_time,
+			COUNT(*) FILTER (WHERE status = 'cancelled' AND updated_at >= @from AND updated_at < @to) AS cancelled,
+			COUNT(*) FILTER (WHERE status = 'issue_reported' AND updated_at >= @from AND updated_at < @to) AS issues_reported,
+			COUNT(*) FILTER (WHERE status = 'in_transit' AND created_at < @to) AS in_transit,
+			COUNT(*) FILTER (WHERE status = 'in_transit' AND created_at < @to
+				AND COALESCE(delivery_due_at, estimated_delivery_at) < @to) AS overdue
+		FROM shipments
+		WHERE `+column+` = @user AND NOT is_sandbox
+	`, map[string]interface{}{"user": userID, "from": from, "to": to}).Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarise shipments: %w", err)
+	}
+
+	return &domainReport.ShipmentSummary{
+		PeriodStart:    from,
+		PeriodEnd:      to,
+		Created:        row.Created,
+		Completed:      row.Completed,
+		OnTime:         row.OnTime,
+		Late:           row.Completed - row.OnTime,
+		Cancelled:      row.Cancelled,
+		IssuesReported: row.IssuesReported,
+		InTransit:      row.InTransit,
+		Overdue:        row.Overdue,
+	}, nil
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toReportSubscriptionModel(s *domainReport.Subscription) *models.ReportSubscriptionModel {
+	return &models.ReportSubscriptionModel{
+		ID:         s.ID,
+		OwnerID:    s.OwnerID,
+		ReportType: string(s.ReportType),
+		Cadence:    string(s.Cadence),
+		Format:     string(s.Format),
+		Recipients: s.Recipients,
+		Timezone:   s.Timezone,
+		Active:     s.Active,
+		NextRunAt:  s.NextRunAt,
+		LastRunAt:  s.LastRunAt,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+func toReportSubscriptionEntity(m *models.ReportSubscriptionModel) *domainReport.Subscription {
+	return &domainReport.Subscription{
+		ID:         m.ID,
+		OwnerID:    m.OwnerID,
+		ReportType: domainReport.Type(m.ReportType),
+		Cadence:    domainReport.Cadence(m.Cadence),
+		Format:     domainReport.Format(m.Format),
+		Recipients: m.Recipients,
+		Timezone:   m.Timezone,
+		Active:     m.Active,
+		NextRunAt:  m.NextRunAt,
+		LastRunAt:  m.LastRunAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func toReportSubscriptionEntities(dbModels []models.ReportSubscriptionModel) []*domainReport.Subscription {
+	subs := make([]*domainReport.Subscription, len(dbModels))
+	for i := range dbModels {
+		subs[i] = toReportSubscriptionEntity(&dbModels[i])
+	}
+	return subs
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentEventRepository implements domain.Shipment.EventRepository interface
+type ShipmentEventRepository struct {
+	db *DB
+}
+
+// NewShipmentEventRepository creates a new shipment event repository
+func NewShipmentEventRepository(db *DB) shipment.EventRepository {
+	return &ShipmentEventRepository{db: db}
+}
+
+func (r *ShipmentEventRepository) Append(ctx context.Context, event *shipment.Event) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode shipment event: %w", err)
+	}
+
+	dbModel := &models.ShipmentEventModel{
+		ShipmentID: event.ShipmentID,
+		Type:       string(event.Type),
+		ActorID:    event.ActorID,
+		Data:       data,
+		OccurredAt: event.OccurredAt,
+	}
+	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to append shipment event: %w", err)
+	}
+
+	event.ID = dbModel.ID
+	return nil
+}
+
+func (r *ShipmentEventRepository) List(ctx context.Context, shipmentID uuid.UUID, until *time.Time) ([]*shipment.Event, error) {
+	query := r.db.DB.WithContext(ctx).Where("shipment_id = ?", shipmentID)
+	if until != nil {
+		query = query.Where("occurred_at <= ?", *until)
+	}
+
+	var dbModels []models.ShipmentEventModel
+	if err := query.Order("id ASC").Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list shipment events: %w", err)
+	}
+
+	events := make([]*shipment.Event, 0, len(dbModels))
+	for i := range dbModels {
+		event, err := toShipmentEventEntity(&dbModels[i])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Helper functions to convert between domain entities and database models
+func toShipmentEventEntity(m *models.ShipmentEventModel) (*shipment.Event, error) {
+	event := &shipment.Event{
+		ID:         m.ID,
+		ShipmentID: m.ShipmentID,
+		Type:       shipment.EventType(m.Type),
+		ActorID:    m.ActorID,
+		OccurredAt: m.OccurredAt,
+	}
+	if len(m.Data) > 0 {
+		if err := json.Unmarshal(m.Data, &event.Data); err != nil {
+			return nil, fmt.Errorf("failed to decode shipment event %d: %w", m.ID, err)
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NoteRepository implements domain.Shipment.NoteRepository interface
+type NoteRepository struct {
+	db *DB
+}
+
+// NewNoteRepository creates a new shipment note repository
+func NewNoteRepository(db *DB) shipment.NoteRepository {
+	return &NoteRepository{db: db}
+}
+
+func (r *NoteRepository) Create(ctx context.Context, note *shipment.Note) error {
+	if note.ID == uuid.Nil {
+		note.ID = uuid.New()
+	}
+	now := time.Now()
+	note.CreatedAt = now
+	note.UpdatedAt = now
+
+	if err := r.db.DB.WithContext(ctx).Omit("Revisions").Create(toNoteModel(note)).Error; err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+
+	return nil
+}
+
+func (r *NoteRepository) GetByID(ctx context.Context, noteID uuid.UUID) (*shipment.Note, error) {
+	var dbModel models.ShipmentNoteModel
+	err := r.db.DB.WithContext(ctx).
+		Preload("Revisions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&dbModel, "id = ?", noteID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrNoteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+
+	return toNoteEntity(&dbModel), nil
+}
+
+func (r *NoteRepository) ListByShipment(ctx context.Context, shipmentID uuid.UUID, visibilities []shipment.NoteVisibility) ([]*shipment.Note, error) {
+	values := make([]string, len(visibilities))
+	for i, v := range visibilities {
+		values[i] = string(v)
+	}
+
+	var dbModels []models.ShipmentNoteModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ? AND visibility IN ?", shipmentID, values).
+		Order("created_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+
+	notes := make([]*shipment.Note, len(dbModels))
+	for i := range dbModels {
+		notes[i] = toNoteEntity(&dbModels[i])
+	}
+	return notes, nil
+}
+
+func (r *NoteRepository) Update(ctx context.Context, note *shipment.Note, previous shipment.NoteRevision) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ShipmentNoteModel{}).
+			Where("id = ?", note.ID).
+			Updates(map[string]interface{}{
+				"body":       note.Body,
+				"visibility": string(note.Visibility),
+				"updated_at": note.UpdatedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update note: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return shipment.ErrNoteNotFound
+		}
+
+		revision := toNoteRevisionModel(note.ID, &previous)
+		if err := tx.Create(revision).Error; err != nil {
+			return fmt.Errorf("failed to save note revision: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *NoteRepository) Delete(ctx context.Context, noteID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Delete(&models.ShipmentNoteModel{}, "id = ?", noteID)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete note: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrNoteNotFound
+	}
+	return nil
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toNoteModel(n *shipment.Note) *models.ShipmentNoteModel {
+	return &models.ShipmentNoteModel{
+		ID:         n.ID,
+		ShipmentID: n.ShipmentID,
+		AuthorID:   n.AuthorID,
+		Visibility: string(n.Visibility),
+		Body:       n.Body,
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+	}
+}
+
+func toNoteEntity(m *models.ShipmentNoteModel) *shipment.Note {
+	note := &shipment.Note{
+		ID:         m.ID,
+		ShipmentID: m.ShipmentID,
+		AuthorID:   m.AuthorID,
+		Visibility: shipment.NoteVisibility(m.Visibility),
+		Body:       m.Body,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+	for _, rev := range m.Revisions {
+		note.Revisions = append(note.Revisions, shipment.NoteRevision{
+			Body:       rev.Body,
+			Visibility: shipment.NoteVisibility(rev.Visibility),
+			EditedBy:   rev.EditedBy,
+			EditedAt:   rev.EditedAt,
+		})
+	}
+	return note
+}
+
+func toNoteRevisionModel(noteID uuid.UUID, rev *shipment.NoteRevision) *models.ShipmentNoteRevisionModel {
+	return &models.ShipmentNoteRevisionModel{
+		NoteID:     noteID,
+		Body:       rev.Body,
+		Visibility: string(rev.Visibility),
+		EditedBy:   rev.EditedBy,
+		EditedAt:   rev.EditedAt,
+	}
+}
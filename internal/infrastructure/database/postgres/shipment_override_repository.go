@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OverrideRepository implements domain.Shipment.OverrideRepository interface
+type OverrideRepository struct {
+	db *DB
+}
+
+// NewOverrideRepository creates a new admin override repository
+func NewOverrideRepository(db *DB) shipment.OverrideRepository {
+	return &OverrideRepository{db: db}
+}
+
+func (r *OverrideRepository) ForceStatus(ctx context.Context, o *shipment.Override) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dbModel models.ShipmentModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&dbModel, "id = ?", *o.ShipmentID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return shipment.ErrShipmentNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock shipment: %w", err)
+		}
+
+		from := shipment.ShipmentStatus(dbModel.Status)
+		o.FromStatus = &from
+
+		if err := tx.Model(&models.ShipmentModel{}).
+			Where("id = ?", dbModel.ID).
+			Updates(map[string]interface{}{
+				"status":     string(*o.ToStatus),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to force shipment status: %w", err)
+		}
+
+		if o.ToStatus.IsTerminal() {
+			if err := releaseShipmentDevices(tx, dbModel.ID); err != nil {
+				return err
+			}
+		}
+
+		return createOverride(tx, o)
+	})
+}
+
+func (r *OverrideRepository) ReleaseDevice(ctx context.Context, o *shipment.Override) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var device models.DeviceModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&device, "id = ?", *o.DeviceID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domainDevice.ErrDeviceNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock device: %w", err)
+		}
+		o.ShipmentID = device.CurrentShipmentID
+
+		if err := tx.Model(&models.DeviceModel{}).
+			Where("id = ?", device.ID).
+			Updates(map[string]interface{}{
+				"current_shipment_id": nil,
+				"status":              "available",
+				"updated_at":          time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to release device: %w", err)
+		}
+
+		return createOverride(tx, o)
+	})
+}
+
+func (r *OverrideRepository) ConfirmRules(ctx context.Context, o *shipment.Override, shipperID uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ShippingRulesModel{}).
+			Where("shipment_id = ? AND confirmed_by_shipper_id IS NULL", *o.ShipmentID).
+			Updates(map[string]interface{}{
+				"confirmed_by_shipper_id": shipperID,
+				"confirmed_at":            time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to confirm shipping rules: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no unconfirmed shipping rules to confirm")
+		}
+
+		return createOverride(tx, o)
+	})
+}
+
+func (r *OverrideRepository) ListByShipment(ctx context.Context, shipmentID uuid.UUID) ([]*shipment.Override, error) {
+	var dbModels []models.ShipmentOverrideModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Order("created_at DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overrides: %w", err)
+	}
+
+	overrides := make([]*shipment.Override, len(dbModels))
+	for i := range dbModels {
+		overrides[i] = toOverrideEntity(&dbModels[i])
+	}
+	return overrides, nil
+}
+
+func createOverride(tx *gorm.DB, o *shipment.Override) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	o.CreatedAt = time.Now()
+
+	if err := tx.Create(toOverrideModel(o)).Error; err != nil {
+		return fmt.Errorf("failed to record override: %w", err)
+	}
+	return nil
+}
+
+func toOverrideModel(o *shipment.Override) *models.ShipmentOverrideModel {
+	m := &models.ShipmentOverrideModel{
+		ID:            o.ID,
+		Action:        string(o.Action),
+		ShipmentID:    o.ShipmentID,
+		DeviceID:      o.DeviceID,
+		AdminID:       o.AdminID,
+		Justification: o.Justification,
+		CreatedAt:     o.CreatedAt,
+	}
+	if o.FromStatus != nil {
+		from := string(*o.FromStatus)
+		m.FromStatus = &from
+	}
+	if o.ToStatus != nil {
+		to := string(*o.ToStatus)
+		m.ToStatus = &to
+	}
+	return m
+}
+
+func toOverrideEntity(m *models.ShipmentOverrideModel) *shipment.Override {
+	o := &shipment.Override{
+		ID:            m.ID,
+		Action:        shipment.OverrideAction(m.Action),
+		ShipmentID:    m.ShipmentID,
+		DeviceID:      m.DeviceID,
+		AdminID:       m.AdminID,
+		Justification: m.Justification,
+		CreatedAt:     m.CreatedAt,
+	}
+	if m.FromStatus != nil {
+		from := shipment.ShipmentStatus(*m.FromStatus)
+		o.FromStatus = &from
+	}
+	if m.ToStatus != nil {
+		to := shipment.ShipmentStatus(*m.ToStatus)
+		o.ToStatus = &to
+	}
+	return o
+}
@@ -3,21 +3,34 @@ package postgres
 import (
 	"cargo-tracker/internal/domain/shipment"
 	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"cargo-tracker/internal/infrastructure/encryption"
 	appErrors "cargo-tracker/pkg/errors"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ShipmentRepository struct {
 	db *DB
 }
 
+// finishedShipmentStatuses are the terminal shipment statuses, as stored
+var finishedShipmentStatuses = func() []string {
+	statuses := make([]string, 0, len(shipment.TerminalStatuses()))
+	for _, status := range shipment.TerminalStatuses() {
+		statuses = append(statuses, string(status))
+	}
+	return statuses
+}()
+
 func NewShipmentRepository(db *DB) *ShipmentRepository {
 	return &ShipmentRepository{db: db}
 }
@@ -62,9 +75,57 @@ func (r *ShipmentRepository) GetByID(ctx context.Context, shipmentID uuid.UUID)
 	return toShipmentEntity(&dbModel), nil
 }
 
+// packagesSubquery aggregates a shipment's packages in label order
+const packagesSubquery = `(SELECT COALESCE(json_agg(p ORDER BY p.sequence), '[]')
+	FROM shipment_packages p WHERE p.shipment_id = shipments.id) AS packages`
+
+func (r *ShipmentRepository) GetDetail(ctx context.Context, shipmentID uuid.UUID) (*shipment.ShipmentDetail, error) {
+	var dbModel models.ShipmentDetailModel
+	err := r.db.DB.WithContext(ctx).
+		Joins("Customer").
+		Joins("Provider").
+		Joins("Shipper").
+		Joins("Device").
+		Joins("Rules").
+		Joins("TermsAcceptance").
+		Select("shipments.*, "+packagesSubquery).
+		Where("shipments.id = ?", shipmentID).
+		Take(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrShipmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipment detail: %w", err)
+	}
+
+	detail := &shipment.ShipmentDetail{
+		Shipment: toShipmentEntity(&dbModel.ShipmentModel),
+		Packages: make([]*shipment.Package, len(dbModel.Packages)),
+	}
+	if dbModel.Rules != nil {
+		detail.Rules = toShippingRulesEntity(dbModel.Rules)
+	}
+	for i := range dbModel.Packages {
+		detail.Packages[i] = toPackageEntity(&dbModel.Packages[i])
+	}
+	if dbModel.TermsAcceptance != nil {
+		detail.TermsAcceptance = toTermsAcceptanceEntity(dbModel.TermsAcceptance)
+	}
+	return detail, nil
+}
+
 func (r *ShipmentRepository) Update(ctx context.Context, s *shipment.Shipment) error {
 	s.UpdatedAt = time.Now()
 
+	// Map updates bypass GORM serializers, so encrypt explicitly
+	proofOfDeliveryURL, err := encryption.EncryptOptional(s.ProofOfDeliveryURL,
+		encryption.Binding{Table: "shipments", Column: "proof_of_delivery_url", RowID: s.ID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to encrypt proof of delivery: %w", err)
+	}
+	rate, rateCurrency, ratedAt := toExchangeColumns(s.GoodsValueRate)
+
 	result := r.db.DB.WithContext(ctx).
 		Model(&models.ShipmentModel{}).
 		Where("id = ?", s.ID).
@@ -73,17 +134,24 @@ func (r *ShipmentRepository) Update(ctx context.Context, s *shipment.Shipment) e
 			"linked_device_id":      s.LinkedDeviceID,
 			"status":                string(s.Status),
 			"goods_description":     s.GoodsDescription,
+			"goods_category":        s.GoodsCategory,
 			"goods_value":           s.GoodsValue,
+			"goods_currency":        s.GoodsCurrency,
+			"exchange_rate":         rate,
+			"exchange_currency":     rateCurrency,
+			"exchange_rated_at":     ratedAt,
 			"goods_weight":          s.GoodsWeight,
 			"pickup_address":        s.PickupAddress,
 			"delivery_address":      s.DeliveryAddress,
 			"estimated_pickup_at":   s.EstimatedPickupAt,
 			"estimated_delivery_at": s.EstimatedDeliveryAt,
+			"delivery_due_at":       s.DeliveryDueAt,
 			"actual_pickup_at":      s.ActualPickupAt,
 			"actual_delivery_at":    s.ActualDeliveryAt,
 			"customer_notes":        s.CustomerNotes,
 			"completion_notes":      s.CompletionNotes,
 			"customer_rating":       s.CustomerRating,
+			"proof_of_delivery_url": proofOfDeliveryURL,
 			"updated_at":            s.UpdatedAt,
 		})
 
@@ -113,21 +181,63 @@ func (r *ShipmentRepository) Delete(ctx context.Context, shipmentID uuid.UUID) e
 }
 
 func (r *ShipmentRepository) UpdateStatus(ctx context.Context, shipmentID uuid.UUID, status shipment.ShipmentStatus) error {
-	result := r.db.DB.WithContext(ctx).
-		Model(&models.ShipmentModel{}).
-		Where("id = ?", shipmentID).
-		Updates(map[string]interface{}{
-			"status":     string(status),
-			"updated_at": time.Now(),
-		})
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ShipmentModel{}).
+			Where("id = ?", shipmentID).
+			Updates(map[string]interface{}{
+				"status":     string(status),
+				"updated_at": time.Now(),
+			})
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to update shipment status: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return shipment.ErrShipmentNotFound
-	}
+		if result.Error != nil {
+			return fmt.Errorf("failed to update shipment status: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return shipment.ErrShipmentNotFound
+		}
+
+		if status.IsTerminal() {
+			return releaseShipmentDevices(tx, shipmentID)
+		}
+		return nil
+	})
+}
 
+// releaseShipmentDevices frees the devices a finished shipment held: those
+// still pointing at it, and its shipment and package trackers left in
+// transit or pointing at another finished shipment. A device an active trip
+// still needs for other stops is kept. Devices that were not in transit
+// keep their status, only the shipment is cleared.
+func releaseShipmentDevices(tx *gorm.DB, shipmentID uuid.UUID) error {
+	err := tx.Exec(`
+		UPDATE devices d
+		SET current_shipment_id = NULL,
+			status = CASE WHEN d.status = 'in_transit' THEN 'available' ELSE d.status END,
+			updated_at = ?
+		WHERE (
+			d.current_shipment_id = ?
+			OR (d.id IN (
+					SELECT linked_device_id FROM shipments WHERE id = ? AND linked_device_id IS NOT NULL
+					UNION
+					SELECT device_id FROM shipment_packages WHERE shipment_id = ? AND device_id IS NOT NULL
+				)
+				AND (d.current_shipment_id IS NULL
+					OR d.current_shipment_id IN (SELECT id FROM shipments WHERE status IN ?)))
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM trips t
+			JOIN trip_stops ts ON ts.trip_id = t.id
+			JOIN shipments m ON m.id = ts.shipment_id
+			WHERE t.device_id = d.id AND t.status = 'active'
+				AND ts.completed_at IS NULL AND ts.shipment_id <> ?
+				AND m.status NOT IN ?
+		)`,
+		time.Now(), shipmentID, shipmentID, shipmentID, finishedShipmentStatuses,
+		shipmentID, finishedShipmentStatuses,
+	).Error
+	if err != nil {
+		return fmt.Errorf("failed to release devices: %w", err)
+	}
 	return nil
 }
 
@@ -155,7 +265,11 @@ func (r *ShipmentRepository) List(ctx context.Context, filter *shipment.Filter)
 		db = db.Where("shipper_id = ?", *filter.ShipperID)
 	}
 	if filter.DeviceID != nil {
-		db = db.Where("linked_device_id = ?", *filter.DeviceID)
+		db = db.Where("linked_device_id = ? OR id IN (?)", *filter.DeviceID,
+			r.db.DB.Model(&models.PackageModel{}).Select("shipment_id").Where("device_id = ?", *filter.DeviceID))
+	}
+	if filter.Sandbox != nil {
+		db = db.Where("is_sandbox = ?", *filter.Sandbox)
 	}
 	if filter.CreatedAfter != nil {
 		db = db.Where("created_at >= ?", filter.CreatedAfter)
@@ -174,7 +288,7 @@ func (r *ShipmentRepository) List(ctx context.Context, filter *shipment.Filter)
 	}
 	if filter.IsDelayed != nil && *filter.IsDelayed {
 		now := time.Now()
-		db = db.Where("status = ? AND estimated_delivery_at < ?", string(shipment.StatusInTransit), now)
+		db = db.Where("status = ? AND COALESCE(delivery_due_at, estimated_delivery_at) < ?", string(shipment.StatusInTransit), now)
 	}
 	if filter.HasDevice != nil {
 		if *filter.HasDevice {
@@ -184,9 +298,8 @@ func (r *ShipmentRepository) List(ctx context.Context, filter *shipment.Filter)
 		}
 	}
 	if filter.Search != "" {
-		search := "%" + filter.Search + "%"
-		db = db.Where("goods_description ILIKE ? OR pickup_address ILIKE ? OR delivery_address ILIKE ?",
-			search, search, search)
+		// search_text holds the normalized description and addresses
+		db = db.Where("search_text LIKE ?", "%"+escapeLike(filter.Search)+"%")
 	}
 
 	// Count total
@@ -239,9 +352,9 @@ func (r *ShipmentRepository) GetStatistics(ctx context.Context) (*shipment.Stati
 		ByStatus: make(map[string]int),
 	}
 
-	// Get total and basic counts
+	// Get total and basic counts, leaving sandbox data out of live metrics
 	var totalShipments int64
-	r.db.DB.WithContext(ctx).Model(&models.ShipmentModel{}).Count(&totalShipments)
+	r.db.DB.WithContext(ctx).Model(&models.ShipmentModel{}).Where("NOT is_sandbox").Count(&totalShipments)
 	stats.TotalShipments = int(totalShipments)
 
 	// Get total and by status
@@ -252,6 +365,7 @@ func (r *ShipmentRepository) GetStatistics(ctx context.Context) (*shipment.Stati
 	err := r.db.DB.WithContext(ctx).Raw(`
 		SELECT status, COUNT(*) as count
 		FROM shipments
+		WHERE NOT is_sandbox
 		GROUP BY status
 	`).Scan(&statusCounts).Error
 	if err != nil {
@@ -267,7 +381,7 @@ func (r *ShipmentRepository) GetStatistics(ctx context.Context) (*shipment.Stati
 	err = r.db.DB.WithContext(ctx).Raw(`
 		SELECT COUNT(*) as count
 		FROM shipments
-		WHERE status IN ('in_transit', 'shipping_assigned')
+		WHERE status IN ('in_transit', 'shipping_assigned') AND NOT is_sandbox
 	`).Scan(&stats.ActiveShipments).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active shipments: %w", err)
@@ -278,22 +392,41 @@ func (r *ShipmentRepository) GetStatistics(ctx context.Context) (*shipment.Stati
 	err = r.db.DB.WithContext(ctx).Raw(`
 		SELECT COUNT(*) as count
 		FROM shipments
-		WHERE status = 'completed' AND DATE(actual_delivery_at) = DATE(?)
+		WHERE status = 'completed' AND DATE(actual_delivery_at) = DATE(?) AND NOT is_sandbox
 	`, today).Scan(&stats.CompletedToday).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get completed today: %w", err)
 	}
 
-	// Get revenue today
+	// Get revenue today per currency, converted at the rates captured when
+	// each shipment was created
+	var revenue []struct {
+		Currency    string
+		Native      float64
+		Converted   float64
+		Unconverted int
+	}
 	err = r.db.DB.WithContext(ctx).Raw(`
-		SELECT COALESCE(SUM(goods_value), 0) as total
+		SELECT goods_currency AS currency,
+		       SUM(goods_value) AS native,
+		       COALESCE(SUM(goods_value * exchange_rate), 0) AS converted,
+		       COUNT(*) FILTER (WHERE exchange_rate IS NULL) AS unconverted
 		FROM shipments
-		WHERE status = 'completed' AND DATE(actual_delivery_at) = DATE(?)
-	`, today).Scan(&stats.RevenueToday).Error
+		WHERE status = 'completed' AND DATE(actual_delivery_at) = DATE(?) AND NOT is_sandbox
+		  AND goods_value IS NOT NULL
+		GROUP BY goods_currency
+	`, today).Scan(&revenue).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get revenue today: %w", err)
 	}
 
+	stats.RevenueTodayByCurrency = make(map[string]float64, len(revenue))
+	for _, rev := range revenue {
+		stats.RevenueTodayByCurrency[rev.Currency] = rev.Native
+		stats.RevenueToday += rev.Converted
+		stats.UnconvertedToday += rev.Unconverted
+	}
+
 	// Calculate metrics
 	if stats.TotalShipments > 0 {
 		completedCount := stats.ByStatus["completed"]
@@ -304,7 +437,7 @@ func (r *ShipmentRepository) GetStatistics(ctx context.Context) (*shipment.Stati
 		err = r.db.DB.WithContext(ctx).Raw(`
 			SELECT COUNT(*) as count
 			FROM shipments
-			WHERE status = 'completed' AND actual_delivery_at <= estimated_delivery_at
+			WHERE status = 'completed' AND actual_delivery_at <= COALESCE(delivery_due_at, estimated_delivery_at) AND NOT is_sandbox
 		`).Scan(&onTimeCount).Error
 		if err != nil {
 			return nil, fmt.Errorf("failed to get on-time delivery count: %w", err)
@@ -316,20 +449,407 @@ func (r *ShipmentRepository) GetStatistics(ctx context.Context) (*shipment.Stati
 
 		stats.IssueRate = float64(issueCount) / float64(stats.TotalShipments) * 100
 
+		// Share of delivered shipments that had package exceptions
+		partialCount := stats.ByStatus[string(shipment.StatusPartiallyCompleted)]
+		if completedCount+partialCount > 0 {
+			stats.PartialCompletionRate = float64(partialCount) / float64(completedCount+partialCount) * 100
+		}
+
 		// Get average delivery time
 		err = r.db.DB.WithContext(ctx).Raw(`
 		SELECT AVG(EXTRACT(EPOCH FROM (actual_delivery_at - actual_pickup_at)) / 3600.0) as avg_hours
 		FROM shipments
-		WHERE status = 'completed' AND actual_pickup_at IS NOT NULL AND actual_delivery_at IS NOT NULL
+		WHERE status = 'completed' AND actual_pickup_at IS NOT NULL AND actual_delivery_at IS NOT NULL AND NOT is_sandbox
 		`).Scan(&stats.AverageDeliveryTime).Error
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// Package outcomes across all shipments
+	var outcomeCounts []struct {
+		Outcome string
+		Count   int
+	}
+	err = r.db.DB.WithContext(ctx).Raw(`
+		SELECT p.outcome, COUNT(*) as count
+		FROM shipment_packages p
+		JOIN shipments s ON s.id = p.shipment_id
+		WHERE NOT s.is_sandbox
+		GROUP BY p.outcome
+	`).Scan(&outcomeCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package outcome counts: %w", err)
+	}
+
+	stats.PackageOutcomes = make(map[string]int)
+	var confirmed, exceptions int
+	for _, oc := range outcomeCounts {
+		stats.PackageOutcomes[oc.Outcome] = oc.Count
+		outcome := shipment.PackageOutcome(oc.Outcome)
+		if outcome == shipment.OutcomePending {
+			continue
+		}
+		confirmed += oc.Count
+		if outcome.IsException() {
+			exceptions += oc.Count
+		}
+	}
+	if confirmed > 0 {
+		stats.PackageExceptionRate = float64(exceptions) / float64(confirmed) * 100
+	}
+
 	return stats, nil
 }
 
+func (r *ShipmentRepository) SetRiskAssessment(ctx context.Context, shipmentID uuid.UUID, risk *shipment.RiskAssessment) error {
+	score, factors, assessedAt := toRiskColumns(risk)
+
+	// Map updates bypass GORM serializers, so encode the factors explicitly
+	var factorsJSON interface{}
+	if factors != nil {
+		encoded, err := json.Marshal(factors)
+		if err != nil {
+			return fmt.Errorf("failed to encode risk factors: %w", err)
+		}
+		factorsJSON = string(encoded)
+	}
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.ShipmentModel{}).
+		Where("id = ?", shipmentID).
+		Updates(map[string]interface{}{
+			"risk_score":       score,
+			"risk_factors":     factorsJSON,
+			"risk_assessed_at": assessedAt,
+			"updated_at":       time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to set risk assessment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrShipmentNotFound
+	}
+
+	return nil
+}
+
+func (r *ShipmentRepository) GetShipperRecord(ctx context.Context, shipperID uuid.UUID) (*shipment.ShipperRecord, error) {
+	var row struct {
+		Finished  int
+		Completed int
+		Partial   int
+		Cancelled int
+		Issues    int
+		Rated     int
+		AvgRating float64
+	}
+	err := r.db.DB.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) FILTER (WHERE status IN ('completed', 'partially_completed', 'cancelled')) AS finished,
+			COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE status = 'partially_completed') AS partial,
+			COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled,
+			COUNT(*) FILTER (WHERE status = 'issue_reported') AS issues,
+			COUNT(customer_rating) AS rated,
+			COALESCE(AVG(customer_rating), 0) AS avg_rating
+		FROM shipments
+		WHERE shipper_id = ?
+	`, shipperID).Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipper record: %w", err)
+	}
+
+	return &shipment.ShipperRecord{
+		ShipperID: shipperID,
+		Finished:  row.Finished,
+		Completed: row.Completed,
+		Partial:   row.Partial,
+		Cancelled: row.Cancelled,
+		Issues:    row.Issues,
+		Rated:     row.Rated,
+		AvgRating: row.AvgRating,
+	}, nil
+}
+
+func (r *ShipmentRepository) ListShipperCandidates(ctx context.Context, query *shipment.MatchQuery) ([]*shipment.ShipperCandidate, error) {
+	// Every device is capable when nothing is required
+	capabilities := query.Capabilities
+	if capabilities == nil {
+		capabilities = []string{}
+	}
+	required, err := json.Marshal(capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode capabilities: %w", err)
+	}
+
+	var rows []struct {
+		ShipperID         uuid.UUID
+		ShipperName       string
+		Finished          int
+		Completed         int
+		Partial           int
+		Cancelled         int
+		Issues            int
+		Rated             int
+		AvgRating         float64
+		ActiveShipments   int
+		AvailableDevices  int
+		CapableDevices    int
+		UnprofiledDevices int
+	}
+	err = r.db.DB.WithContext(ctx).Raw(`
+		SELECT
+			u.id AS shipper_id,
+			u.full_name AS shipper_name,
+			s.finished, s.completed, s.partial, s.cancelled, s.issues, s.rated, s.avg_rating,
+			s.active_shipments,
+			d.available_devices, d.capable_devices, d.unprofiled_devices
+		FROM users u
+		CROSS JOIN LATERAL (
+			SELECT
+				COUNT(*) FILTER (WHERE status IN ('completed', 'partially_completed', 'cancelled')) AS finished,
+				COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+				COUNT(*) FILTER (WHERE status = 'partially_completed') AS partial,
+				COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled,
+				COUNT(*) FILTER (WHERE status = 'issue_reported') AS issues,
+				COUNT(customer_rating) AS rated,
+				COALESCE(AVG(customer_rating), 0) AS avg_rating,
+				COUNT(*) FILTER (WHERE status IN ('shipping_assigned', 'in_transit', 'issue_reported')) AS active_shipments
+			FROM shipments
+			WHERE shipper_id = u.id
+		) s
+		CROSS JOIN LATERAL (
+			SELECT
+				COUNT(*) AS available_devices,
+				COUNT(*) FILTER (WHERE p.capabilities @> ?::jsonb) AS capable_devices,
+				COUNT(*) FILTER (WHERE p.model IS NULL) AS unprofiled_devices
+			FROM devices dv
+			LEFT JOIN device_model_profiles p ON p.model = dv.model
+			WHERE dv.owner_shipper_id = u.id AND dv.status = 'available'
+		) d
+		WHERE u.role = 'shipper' AND u.is_active AND u.is_sandbox = ?
+			AND d.available_devices > 0
+	`, string(required), query.Sandbox).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipper candidates: %w", err)
+	}
+
+	candidates := make([]*shipment.ShipperCandidate, len(rows))
+	for i, row := range rows {
+		candidates[i] = &shipment.ShipperCandidate{
+			ShipperID:   row.ShipperID,
+			ShipperName: row.ShipperName,
+			Record: shipment.ShipperRecord{
+				ShipperID: row.ShipperID,
+				Finished:  row.Finished,
+				Completed: row.Completed,
+				Partial:   row.Partial,
+				Cancelled: row.Cancelled,
+				Issues:    row.Issues,
+				Rated:     row.Rated,
+				AvgRating: row.AvgRating,
+			},
+			ActiveShipments:   row.ActiveShipments,
+			AvailableDevices:  row.AvailableDevices,
+			CapableDevices:    row.CapableDevices,
+			UnprofiledDevices: row.UnprofiledDevices,
+		}
+	}
+	return candidates, nil
+}
+
+func (r *ShipmentRepository) CompareShippers(ctx context.Context, query *shipment.ComparisonQuery) ([]*shipment.ShipperPerformance, error) {
+	// Shared by both aggregates so they count the same shipments
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("s.provider_id = ? AND s.shipper_id IS NOT NULL AND s.is_sandbox = ?", query.ProviderID, query.Sandbox).
+			Where("s.created_at >= ? AND s.created_at < ?", query.From, query.To)
+		if query.PickupCity != "" {
+			db = db.Where("search_normalize(s.pickup_address) LIKE ?", "%"+escapeLike(query.PickupCity)+"%")
+		}
+		if query.DeliveryCity != "" {
+			db = db.Where("search_normalize(s.delivery_address) LIKE ?", "%"+escapeLike(query.DeliveryCity)+"%")
+		}
+		return db
+	}
+
+	var rows []struct {
+		ShipperID   uuid.UUID
+		ShipperName string
+		Assigned    int
+		Delivered   int
+		OnTime      int
+		Partial     int
+		Cancelled   int
+		OpenIssues  int
+		Rated       int
+		AvgRating   float64
+	}
+	err := r.db.DB.WithContext(ctx).
+		Table("shipments s").
+		Select(`s.shipper_id,
+			MAX(u.full_name) AS shipper_name,
+			COUNT(*) AS assigned,
+			COUNT(*) FILTER (WHERE s.status IN ('completed', 'partially_completed')) AS delivered,
+			COUNT(*) FILTER (WHERE s.status IN ('completed', 'partially_completed')
+				AND s.actual_delivery_at <= COALESCE(s.delivery_due_at, s.estimated_delivery_at)) AS on_time,
+			COUNT(*) FILTER (WHERE s.status = 'partially_completed') AS partial,
+			COUNT(*) FILTER (WHERE s.status = 'cancelled') AS cancelled,
+			COUNT(*) FILTER (WHERE s.status = 'issue_reported') AS open_issues,
+			COUNT(s.customer_rating) AS rated,
+			COALESCE(AVG(s.customer_rating), 0) AS avg_rating`).
+		Joins("JOIN users u ON u.id = s.shipper_id").
+		Scopes(scope).
+		Group("s.shipper_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare shippers: %w", err)
+	}
+
+	// Agreed prices come from the accepted quote of each shipment
+	var costs []struct {
+		ShipperID uuid.UUID
+		Currency  string
+		Shipments int
+		Total     float64
+	}
+	err = r.db.DB.WithContext(ctx).
+		Table("shipments s").
+		Select("s.shipper_id, q.currency, COUNT(*) AS shipments, SUM(q.price) AS total").
+		Joins("JOIN quote_requests qr ON qr.shipment_id = s.id").
+		Joins("JOIN quotes q ON q.request_id = qr.id AND q.status = 'accepted' AND q.provider_id = s.provider_id").
+		Scopes(scope).
+		Where("q.price IS NOT NULL").
+		Group("s.shipper_id, q.currency").
+		Order("q.currency").
+		Scan(&costs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipper costs: %w", err)
+	}
+
+	performances := make([]*shipment.ShipperPerformance, len(rows))
+	byShipper := make(map[uuid.UUID]*shipment.ShipperPerformance, len(rows))
+	for i, row := range rows {
+		performances[i] = &shipment.ShipperPerformance{
+			ShipperID:   row.ShipperID,
+			ShipperName: row.ShipperName,
+			Assigned:    row.Assigned,
+			Delivered:   row.Delivered,
+			OnTime:      row.OnTime,
+			Partial:     row.Partial,
+			Cancelled:   row.Cancelled,
+			OpenIssues:  row.OpenIssues,
+			Rated:       row.Rated,
+			AvgRating:   row.AvgRating,
+		}
+		byShipper[row.ShipperID] = performances[i]
+	}
+	for _, c := range costs {
+		if p, ok := byShipper[c.ShipperID]; ok {
+			p.Costs = append(p.Costs, shipment.ShipperCost{
+				Currency:  c.Currency,
+				Shipments: c.Shipments,
+				Total:     c.Total,
+			})
+		}
+	}
+
+	sort.SliceStable(performances, func(i, j int) bool {
+		return onTimeShare(performances[i]) > onTimeShare(performances[j])
+	})
+	return performances, nil
+}
+
+func (r *ShipmentRepository) GetLaneStatistics(ctx context.Context, query *shipment.LaneQuery) (*shipment.LaneStatistics, error) {
+	var row struct {
+		Shipments  int
+		Delivered  int
+		OnTime     int
+		Partial    int
+		Cancelled  int
+		Timed      int
+		TransitAvg *float64
+		TransitP50 *float64
+		TransitP75 *float64
+		TransitP90 *float64
+		TransitP95 *float64
+	}
+	err := r.db.DB.WithContext(ctx).Raw(`
+		WITH lane AS (
+			SELECT status, actual_delivery_at, delivery_due_at, estimated_delivery_at,
+				CASE WHEN status IN ('completed', 'partially_completed') AND actual_pickup_at IS NOT NULL
+					AND actual_delivery_at >= actual_pickup_at
+				THEN EXTRACT(EPOCH FROM actual_delivery_at - actual_pickup_at) / 60 END AS transit
+			FROM shipments
+			WHERE shipper_id IS NOT NULL AND is_sandbox = ?
+				AND created_at >= ? AND created_at < ?
+				AND search_normalize(pickup_address) LIKE ?
+				AND search_normalize(delivery_address) LIKE ?
+		)
+		SELECT
+			COUNT(*) AS shipments,
+			COUNT(*) FILTER (WHERE status IN ('completed', 'partially_completed')) AS delivered,
+			COUNT(*) FILTER (WHERE status IN ('completed', 'partially_completed')
+				AND actual_delivery_at <= COALESCE(delivery_due_at, estimated_delivery_at)) AS on_time,
+			COUNT(*) FILTER (WHERE status = 'partially_completed') AS partial,
+			COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled,
+			COUNT(transit) AS timed,
+			AVG(transit) AS transit_avg,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY transit) AS transit_p50,
+			percentile_cont(0.75) WITHIN GROUP (ORDER BY transit) AS transit_p75,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY transit) AS transit_p90,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY transit) AS transit_p95
+		FROM lane
+	`, query.Sandbox, query.From, query.To,
+		"%"+escapeLike(query.PickupArea)+"%",
+		"%"+escapeLike(query.DeliveryArea)+"%",
+	).Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lane statistics: %w", err)
+	}
+
+	return &shipment.LaneStatistics{
+		Shipments:  row.Shipments,
+		Delivered:  row.Delivered,
+		OnTime:     row.OnTime,
+		Partial:    row.Partial,
+		Cancelled:  row.Cancelled,
+		Timed:      row.Timed,
+		TransitAvg: row.TransitAvg,
+		TransitP50: row.TransitP50,
+		TransitP75: row.TransitP75,
+		TransitP90: row.TransitP90,
+		TransitP95: row.TransitP95,
+	}, nil
+}
+
+func onTimeShare(p *shipment.ShipperPerformance) float64 {
+	if p.Delivered == 0 {
+		return -1
+	}
+	return float64(p.OnTime) / float64(p.Delivered)
+}
+
+func (r *ShipmentRepository) SetDeliveryDueAt(ctx context.Context, shipmentID uuid.UUID, dueAt *time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.ShipmentModel{}).
+		Where("id = ?", shipmentID).
+		Updates(map[string]interface{}{
+			"delivery_due_at": dueAt,
+			"updated_at":      time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to set delivery due time: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrShipmentNotFound
+	}
+
+	return nil
+}
+
 func (r *ShipmentRepository) SetActualPickup(ctx context.Context, shipmentID uuid.UUID, pickupTime time.Time) error {
 	result := r.db.DB.WithContext(ctx).
 		Model(&models.ShipmentModel{}).
@@ -383,7 +903,7 @@ func (r *ShipmentRepository) SetCustomerRating(ctx context.Context, shipmentID u
 	if feedback != nil {
 		result := r.db.DB.WithContext(ctx).
 			Model(&models.ShipmentModel{}).
-			Where("id = ? AND status = ?", shipmentID, "completed").
+			Where("id = ? AND status IN ?", shipmentID, []string{"completed", "partially_completed"}).
 			Update("completion_notes", gorm.Expr("COALESCE(completion_notes, '') || ?", "\nCustomer Feedback: "+*feedback)).
 			Update("customer_rating", rating).
 			Update("updated_at", time.Now())
@@ -401,7 +921,7 @@ func (r *ShipmentRepository) SetCustomerRating(ctx context.Context, shipmentID u
 
 	result := r.db.DB.WithContext(ctx).
 		Model(&models.ShipmentModel{}).
-		Where("id = ? AND status = ?", shipmentID, "completed").
+		Where("id = ? AND status IN ?", shipmentID, []string{"completed", "partially_completed"}).
 		Updates(updates)
 
 	if result.Error != nil {
@@ -414,10 +934,11 @@ func (r *ShipmentRepository) SetCustomerRating(ctx context.Context, shipmentID u
 	return nil
 }
 
-func (r *ShipmentRepository) GetMarketplaceListings(ctx context.Context, page, pageSize int) ([]*shipment.Shipment, int64, error) {
+func (r *ShipmentRepository) GetMarketplaceListings(ctx context.Context, sandbox bool, page, pageSize int) ([]*shipment.Shipment, int64, error) {
 	status := shipment.StatusOrderPosted
 	filter := &shipment.Filter{
 		Status:    &status,
+		Sandbox:   &sandbox,
 		Page:      page,
 		PageSize:  pageSize,
 		SortBy:    "created_at",
@@ -483,17 +1004,19 @@ func (r *ShipmentRepository) CreateRules(ctx context.Context, rules *shipment.Sh
 	rules.SetAt = time.Now()
 
 	dbModel := toShippingRulesModel(rules)
-	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
-			return fmt.Errorf("rules already exist for this shipment")
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dbModel).Error; err != nil {
+			if strings.Contains(err.Error(), "duplicate key") {
+				return fmt.Errorf("rules already exist for this shipment")
+			}
+			return fmt.Errorf("failed to create shipping rules: %w", err)
 		}
-		return fmt.Errorf("failed to create shipping rules: %w", err)
-	}
 
-	rules.ID = dbModel.ID
-	rules.SetAt = dbModel.SetAt
+		rules.ID = dbModel.ID
+		rules.SetAt = dbModel.SetAt
 
-	return nil
+		return addRulesVersion(tx, rules, 1, rules.SetByProviderID, rules.SetAt)
+	})
 }
 
 func (r *ShipmentRepository) ConfirmRules(ctx context.Context, shipmentID, shipperID uuid.UUID) error {
@@ -517,30 +1040,86 @@ func (r *ShipmentRepository) ConfirmRules(ctx context.Context, shipmentID, shipp
 	return nil
 }
 
-func (r *ShipmentRepository) UpdateRules(ctx context.Context, rules *shipment.ShippingRules) error {
-	result := r.db.DB.WithContext(ctx).
-		Model(&models.ShippingRulesModel{}).
-		Where("id = ?", rules.ID).
-		Updates(map[string]interface{}{
-			"report_cycle_sec":        rules.ReportCycleSec,
-			"temp_min":                rules.TempMin,
-			"temp_max":                rules.TempMax,
-			"humidity_min":            rules.HumidityMin,
-			"humidity_max":            rules.HumidityMax,
-			"light_max":               rules.LightMax,
-			"tilt_max_angle":          rules.TiltMaxAngle,
-			"impact_threshold_g":      rules.ImpactThresholdG,
-			"enable_predictive_alert": rules.EnablePredictiveAlert,
-			"alert_buffer_time_min":   rules.AlertBufferTimeMin,
-		})
+func (r *ShipmentRepository) UpdateRules(ctx context.Context, rules *shipment.ShippingRules, editedBy uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.ShippingRulesModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&current, "id = ?", rules.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("shipping rules not found")
+			}
+			return fmt.Errorf("failed to lock shipping rules: %w", err)
+		}
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to update shipping rules: %w", result.Error)
+		var latest int
+		if err := tx.Model(&models.ShippingRulesVersionModel{}).
+			Where("shipment_id = ?", current.ShipmentID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return fmt.Errorf("failed to get rules version: %w", err)
+		}
+		// Rules set before versioning become the first version now
+		if latest == 0 {
+			latest = 1
+			if err := addRulesVersion(tx, toShippingRulesEntity(&current), latest, current.SetByProviderID, current.SetAt); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.ShippingRulesModel{}).
+			Where("id = ?", rules.ID).
+			Updates(map[string]interface{}{
+				"report_cycle_sec":        rules.ReportCycleSec,
+				"temp_min":                rules.TempMin,
+				"temp_max":                rules.TempMax,
+				"humidity_min":            rules.HumidityMin,
+				"humidity_max":            rules.HumidityMax,
+				"light_max":               rules.LightMax,
+				"tilt_max_angle":          rules.TiltMaxAngle,
+				"impact_threshold_g":      rules.ImpactThresholdG,
+				"enable_predictive_alert": rules.EnablePredictiveAlert,
+				"alert_buffer_time_min":   rules.AlertBufferTimeMin,
+				"set_at":                  now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update shipping rules: %w", err)
+		}
+		rules.SetAt = now
+
+		return addRulesVersion(tx, rules, latest+1, editedBy, now)
+	})
+}
+
+func (r *ShipmentRepository) ListRulesVersions(ctx context.Context, shipmentID uuid.UUID) ([]*shipment.RulesVersion, error) {
+	var dbModels []models.ShippingRulesVersionModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Order("version ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules versions: %w", err)
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("shipping rules not found")
+
+	versions := make([]*shipment.RulesVersion, len(dbModels))
+	for i := range dbModels {
+		versions[i] = toRulesVersionEntity(&dbModels[i])
+	}
+	return versions, nil
+}
+
+// addRulesVersion records rules as the given version and makes it the one
+// the shipment is held to
+func addRulesVersion(tx *gorm.DB, rules *shipment.ShippingRules, version int, setBy uuid.UUID, setAt time.Time) error {
+	dbModel := toRulesVersionModel(rules, version, setBy, setAt)
+	if err := tx.Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to save rules version: %w", err)
 	}
 
+	if err := tx.Model(&models.ShipmentModel{}).
+		Where("id = ?", rules.ShipmentID).
+		Update("rules_version_id", dbModel.ID).Error; err != nil {
+		return fmt.Errorf("failed to activate rules version: %w", err)
+	}
 	return nil
 }
 
@@ -560,27 +1139,454 @@ func (r *ShipmentRepository) GetRulesByShipmentID(ctx context.Context, shipmentI
 	return toShippingRulesEntity(&dbModel), nil
 }
 
+func (r *ShipmentRepository) CreatePackage(ctx context.Context, pkg *shipment.Package) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the parent row so concurrent creates get distinct sequence numbers
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&models.ShipmentModel{}, "id = ?", pkg.ShipmentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shipment.ErrShipmentNotFound
+			}
+			return fmt.Errorf("failed to lock shipment: %w", err)
+		}
+
+		var maxSequence int
+		if err := tx.Model(&models.PackageModel{}).
+			Where("shipment_id = ?", pkg.ShipmentID).
+			Select("COALESCE(MAX(sequence), 0)").
+			Scan(&maxSequence).Error; err != nil {
+			return fmt.Errorf("failed to get package sequence: %w", err)
+		}
+
+		now := time.Now()
+		if pkg.ID == uuid.Nil {
+			pkg.ID = uuid.New()
+		}
+		pkg.Sequence = maxSequence + 1
+		if pkg.Outcome == "" {
+			pkg.Outcome = shipment.OutcomePending
+		}
+		pkg.CreatedAt = now
+		pkg.UpdatedAt = now
+
+		if err := tx.Create(toPackageModel(pkg)).Error; err != nil {
+			return fmt.Errorf("failed to create package: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *ShipmentRepository) GetPackageByID(ctx context.Context, packageID uuid.UUID) (*shipment.Package, error) {
+	var dbModel models.PackageModel
+	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", packageID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrPackageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package: %w", err)
+	}
+
+	return toPackageEntity(&dbModel), nil
+}
+
+func (r *ShipmentRepository) ListPackages(ctx context.Context, shipmentID uuid.UUID) ([]*shipment.Package, error) {
+	var dbModels []models.PackageModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Order("sequence ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages: %w", err)
+	}
+
+	packages := make([]*shipment.Package, len(dbModels))
+	for i := range dbModels {
+		packages[i] = toPackageEntity(&dbModels[i])
+	}
+	return packages, nil
+}
+
+func (r *ShipmentRepository) UpdatePackage(ctx context.Context, pkg *shipment.Package) error {
+	pkg.UpdatedAt = time.Now()
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.PackageModel{}).
+		Where("id = ?", pkg.ID).
+		Updates(map[string]interface{}{
+			"description": pkg.Description,
+			"weight":      pkg.Weight,
+			"updated_at":  pkg.UpdatedAt,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update package: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrPackageNotFound
+	}
+	return nil
+}
+
+func (r *ShipmentRepository) DeletePackage(ctx context.Context, packageID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("id = ? AND device_id IS NULL", packageID).
+		Delete(&models.PackageModel{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete package: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return appErrors.NewAppError("DELETE_FAILED", "Package not found or already has a device", nil)
+	}
+	return nil
+}
+
+func (r *ShipmentRepository) AssignPackageDevice(ctx context.Context, packageID, deviceID uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pkg models.PackageModel
+		if err := tx.First(&pkg, "id = ?", packageID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shipment.ErrPackageNotFound
+			}
+			return fmt.Errorf("failed to get package: %w", err)
+		}
+
+		result := tx.Model(&models.PackageModel{}).
+			Where("id = ? AND device_id IS NULL", packageID).
+			Updates(map[string]interface{}{
+				"device_id":  deviceID,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to assign device to package: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return appErrors.NewAppError("ASSIGNMENT_FAILED", "Package already has a device", nil)
+		}
+
+		result = tx.Model(&models.DeviceModel{}).
+			Where("id = ? AND current_shipment_id IS NULL", deviceID).
+			Updates(map[string]interface{}{
+				"current_shipment_id": pkg.ShipmentID,
+				"status":              "in_transit",
+				"updated_at":          time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update device: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return shipment.ErrDeviceUnavailable
+		}
+
+		return nil
+	})
+}
+
+func (r *ShipmentRepository) RecordPackageOutcome(ctx context.Context, pkg *shipment.Package) error {
+	pkg.UpdatedAt = time.Now()
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.PackageModel{}).
+		Where("id = ?", pkg.ID).
+		Updates(map[string]interface{}{
+			"outcome":             string(pkg.Outcome),
+			"outcome_note":        pkg.OutcomeNote,
+			"outcome_recorded_by": pkg.OutcomeRecordedBy,
+			"outcome_at":          pkg.OutcomeAt,
+			"updated_at":          pkg.UpdatedAt,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to record package outcome: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrPackageNotFound
+	}
+	return nil
+}
+
+func (r *ShipmentRepository) ConfirmPendingPackages(ctx context.Context, shipmentID, recordedBy uuid.UUID, at time.Time) error {
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.PackageModel{}).
+		Where("shipment_id = ? AND outcome = ?", shipmentID, string(shipment.OutcomePending)).
+		Updates(map[string]interface{}{
+			"outcome":             string(shipment.OutcomeDelivered),
+			"outcome_recorded_by": recordedBy,
+			"outcome_at":          at,
+			"updated_at":          time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to confirm pending packages: %w", err)
+	}
+	return nil
+}
+
+func (r *ShipmentRepository) ResolveDevice(ctx context.Context, deviceID uuid.UUID) (*shipment.DeviceAssignment, error) {
+	active := []string{
+		string(shipment.StatusShippingAssigned),
+		string(shipment.StatusInTransit),
+		string(shipment.StatusIssueReported),
+	}
+
+	// A package tracker is more specific than the shipment-level one
+	var pkg struct {
+		ID         uuid.UUID
+		ShipmentID uuid.UUID
+		Status     string
+	}
+	err := r.db.DB.WithContext(ctx).
+		Table("shipment_packages AS p").
+		Select("p.id, p.shipment_id, s.status").
+		Joins("JOIN shipments s ON s.id = p.shipment_id").
+		Where("p.device_id = ? AND s.status IN ?", deviceID, active).
+		Order("s.created_at DESC").
+		Limit(1).
+		Scan(&pkg).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve device package: %w", err)
+	}
+	if pkg.ID != uuid.Nil {
+		return &shipment.DeviceAssignment{
+			DeviceID:   deviceID,
+			ShipmentID: pkg.ShipmentID,
+			PackageID:  &pkg.ID,
+			Status:     shipment.ShipmentStatus(pkg.Status),
+		}, nil
+	}
+
+	var dbModel models.ShipmentModel
+	err = r.db.DB.WithContext(ctx).
+		Select("id", "status").
+		Where("linked_device_id = ? AND status IN ?", deviceID, active).
+		Order("created_at DESC").
+		First(&dbModel).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrDeviceNotAssigned
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve device shipment: %w", err)
+	}
+
+	return &shipment.DeviceAssignment{
+		DeviceID:   deviceID,
+		ShipmentID: dbModel.ID,
+		Status:     shipment.ShipmentStatus(dbModel.Status),
+	}, nil
+}
+
+func (r *ShipmentRepository) DeleteSandboxData(ctx context.Context, userID uuid.UUID) (int64, error) {
+	// Packages, rules and documents cascade with the shipment
+	result := r.db.DB.WithContext(ctx).
+		Where("is_sandbox AND (customer_id = ? OR provider_id = ? OR shipper_id = ?)", userID, userID, userID).
+		Delete(&models.ShipmentModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete sandbox shipments: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *ShipmentRepository) ListStuck(ctx context.Context, query *shipment.StuckQuery) ([]*shipment.Shipment, error) {
+	db := r.db.DB.WithContext(ctx).
+		Where("status = ? AND status_changed_at < ?", string(query.Status), query.ChangedBefore).
+		Where(`NOT EXISTS (SELECT 1 FROM shipment_watchdog_stages w
+			WHERE w.shipment_id = shipments.id AND w.status_changed_at = shipments.status_changed_at AND w.stage = ?)`,
+			string(query.Stage))
+	if query.Previous != "" {
+		db = db.Where(`EXISTS (SELECT 1 FROM shipment_watchdog_stages w
+			WHERE w.shipment_id = shipments.id AND w.status_changed_at = shipments.status_changed_at
+			AND w.stage = ? AND w.created_at < ?)`,
+			string(query.Previous), query.PreviousBefore)
+	}
+
+	var dbModels []models.ShipmentModel
+	if err := db.Order("status_changed_at ASC").Limit(query.Limit).Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stuck shipments: %w", err)
+	}
+
+	shipments := make([]*shipment.Shipment, len(dbModels))
+	for i := range dbModels {
+		shipments[i] = toShipmentEntity(&dbModels[i])
+	}
+	return shipments, nil
+}
+
+func (r *ShipmentRepository) RecordWatchdogStage(ctx context.Context, s *shipment.Shipment, stage shipment.WatchdogStage) (bool, error) {
+	result := r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.WatchdogStageModel{
+			ShipmentID:      s.ID,
+			Status:          string(s.Status),
+			StatusChangedAt: s.StatusChangedAt,
+			Stage:           string(stage),
+			CreatedAt:       time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record watchdog stage: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *ShipmentRepository) RevertAssignment(ctx context.Context, shipmentID uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dbModel models.ShipmentModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", shipmentID, string(shipment.StatusShippingAssigned)).
+			First(&dbModel).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return shipment.ErrShipmentNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock shipment: %w", err)
+		}
+
+		if err := tx.Model(&models.ShipmentModel{}).
+			Where("id = ?", shipmentID).
+			Updates(map[string]interface{}{
+				"status":           string(shipment.StatusOrderPosted),
+				"shipper_id":       nil,
+				"linked_device_id": nil,
+				"updated_at":       time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to revert shipment: %w", err)
+		}
+
+		if dbModel.LinkedDeviceID != nil {
+			if err := tx.Model(&models.DeviceModel{}).
+				Where("id = ? AND current_shipment_id = ?", *dbModel.LinkedDeviceID, shipmentID).
+				Updates(map[string]interface{}{
+					"current_shipment_id": nil,
+					"status":              "available",
+					"updated_at":          time.Now(),
+				}).Error; err != nil {
+				return fmt.Errorf("failed to release device: %w", err)
+			}
+		}
+
+		// The next shipper confirms the rules and signs the terms again
+		if err := tx.Model(&models.ShippingRulesModel{}).
+			Where("shipment_id = ?", shipmentID).
+			Updates(map[string]interface{}{
+				"confirmed_by_shipper_id": nil,
+				"confirmed_at":            nil,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to reset rules confirmation: %w", err)
+		}
+		if err := tx.Where("shipment_id = ?", shipmentID).
+			Delete(&models.TermsAcceptanceModel{}).Error; err != nil {
+			return fmt.Errorf("failed to reset terms acceptance: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *ShipmentRepository) ReleaseStaleDevices(ctx context.Context, limit int) ([]*shipment.StaleDevice, error) {
+	// A device is stale when it points at a finished or deleted shipment, or
+	// is in transit for no active shipment, package or trip. Locked devices
+	// are skipped so concurrent watchdogs and transitions do not wait.
+	var rows []struct {
+		DeviceID       uuid.UUID
+		ShipmentID     *uuid.UUID
+		ShipmentStatus *string
+		DeviceStatus   string
+	}
+	err := r.db.DB.WithContext(ctx).Raw(`
+		WITH stale AS (
+			SELECT d.id, d.current_shipment_id, s.status AS shipment_status
+			FROM devices d
+			LEFT JOIN shipments s ON s.id = d.current_shipment_id
+			WHERE (
+				(d.current_shipment_id IS NOT NULL AND (s.id IS NULL OR s.status IN ?))
+				OR (d.current_shipment_id IS NULL AND d.status = 'in_transit'
+					AND NOT EXISTS (
+						SELECT 1 FROM shipments a
+						WHERE a.linked_device_id = d.id AND a.status NOT IN ?)
+					AND NOT EXISTS (
+						SELECT 1 FROM shipment_packages p
+						JOIN shipments a ON a.id = p.shipment_id
+						WHERE p.device_id = d.id AND a.status NOT IN ?))
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM trips t
+				JOIN trip_stops ts ON ts.trip_id = t.id
+				JOIN shipments m ON m.id = ts.shipment_id
+				WHERE t.device_id = d.id AND t.status = 'active'
+					AND ts.completed_at IS NULL AND m.status NOT IN ?
+			)
+			ORDER BY d.updated_at
+			LIMIT ?
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE devices
+		SET current_shipment_id = NULL,
+			status = CASE WHEN devices.status = 'in_transit' THEN 'available' ELSE devices.status END,
+			updated_at = ?
+		FROM stale
+		WHERE devices.id = stale.id
+		RETURNING devices.id AS device_id, stale.current_shipment_id AS shipment_id,
+			stale.shipment_status, devices.status AS device_status`,
+		finishedShipmentStatuses, finishedShipmentStatuses, finishedShipmentStatuses,
+		finishedShipmentStatuses, limit, time.Now(),
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to release stale devices: %w", err)
+	}
+
+	released := make([]*shipment.StaleDevice, len(rows))
+	for i, row := range rows {
+		released[i] = &shipment.StaleDevice{
+			DeviceID:     row.DeviceID,
+			ShipmentID:   row.ShipmentID,
+			DeviceStatus: row.DeviceStatus,
+		}
+		if row.ShipmentStatus != nil {
+			status := shipment.ShipmentStatus(*row.ShipmentStatus)
+			released[i].ShipmentStatus = &status
+		}
+	}
+	return released, nil
+}
+
 // Helper functions to convert between domain entities and database models
 func toShipmentModel(s *shipment.Shipment) *models.ShipmentModel {
+	score, factors, assessedAt := toRiskColumns(s.Risk)
+	rate, rateCurrency, ratedAt := toExchangeColumns(s.GoodsValueRate)
 	return &models.ShipmentModel{
 		ID:                  s.ID,
 		CustomerID:          s.CustomerID,
 		ProviderID:          s.ProviderID,
 		ShipperID:           s.ShipperID,
+		BookedBy:            s.BookedBy,
 		LinkedDeviceID:      s.LinkedDeviceID,
+		RulesVersionID:      s.RulesVersionID,
 		Status:              string(s.Status),
 		GoodsDescription:    s.GoodsDescription,
+		GoodsCategory:       s.GoodsCategory,
 		GoodsValue:          s.GoodsValue,
+		GoodsCurrency:       s.GoodsCurrency,
+		ExchangeRate:        rate,
+		ExchangeCurrency:    rateCurrency,
+		ExchangeRatedAt:     ratedAt,
 		GoodsWeight:         s.GoodsWeight,
 		PickupAddress:       s.PickupAddress,
 		DeliveryAddress:     s.DeliveryAddress,
+		PickupAddressID:     s.PickupAddressID,
+		DeliveryAddressID:   s.DeliveryAddressID,
 		EstimatedPickupAt:   s.EstimatedPickupAt,
 		EstimatedDeliveryAt: s.EstimatedDeliveryAt,
+		DeliveryDueAt:       s.DeliveryDueAt,
 		ActualPickupAt:      s.ActualPickupAt,
 		ActualDeliveryAt:    s.ActualDeliveryAt,
 		CustomerNotes:       s.CustomerNotes,
 		CompletionNotes:     s.CompletionNotes,
 		CustomerRating:      s.CustomerRating,
+		ProofOfDeliveryURL:  s.ProofOfDeliveryURL,
+		RiskScore:           score,
+		RiskFactors:         factors,
+		RiskAssessedAt:      assessedAt,
+		IsSandbox:           s.IsSandbox,
 		CreatedAt:           s.CreatedAt,
 		UpdatedAt:           s.UpdatedAt,
 	}
@@ -593,25 +1599,84 @@ func toShipmentEntity(m *models.ShipmentModel) *shipment.Shipment {
 		CustomerID:          m.CustomerID,
 		ProviderID:          m.ProviderID,
 		ShipperID:           m.ShipperID,
+		BookedBy:            m.BookedBy,
 		LinkedDeviceID:      m.LinkedDeviceID,
+		RulesVersionID:      m.RulesVersionID,
 		Status:              status,
 		GoodsDescription:    m.GoodsDescription,
+		GoodsCategory:       m.GoodsCategory,
 		GoodsValue:          m.GoodsValue,
+		GoodsCurrency:       m.GoodsCurrency,
+		GoodsValueRate:      toExchangeRate(m),
 		GoodsWeight:         m.GoodsWeight,
 		PickupAddress:       m.PickupAddress,
 		DeliveryAddress:     m.DeliveryAddress,
+		PickupAddressID:     m.PickupAddressID,
+		DeliveryAddressID:   m.DeliveryAddressID,
 		EstimatedPickupAt:   m.EstimatedPickupAt,
 		EstimatedDeliveryAt: m.EstimatedDeliveryAt,
+		DeliveryDueAt:       m.DeliveryDueAt,
 		ActualPickupAt:      m.ActualPickupAt,
 		ActualDeliveryAt:    m.ActualDeliveryAt,
 		CustomerNotes:       m.CustomerNotes,
 		CompletionNotes:     m.CompletionNotes,
 		CustomerRating:      m.CustomerRating,
+		ProofOfDeliveryURL:  m.ProofOfDeliveryURL,
+		Risk:                toRiskAssessment(m),
+		IsSandbox:           m.IsSandbox,
 		CreatedAt:           m.CreatedAt,
 		UpdatedAt:           m.UpdatedAt,
+		StatusChangedAt:     m.StatusChangedAt,
 	}
 }
 
+func toExchangeColumns(rate *shipment.ExchangeRate) (*float64, *string, *time.Time) {
+	if rate == nil {
+		return nil, nil, nil
+	}
+	value, currency, ratedAt := rate.Rate, rate.Currency, rate.RatedAt
+	return &value, &currency, &ratedAt
+}
+
+func toExchangeRate(m *models.ShipmentModel) *shipment.ExchangeRate {
+	if m.ExchangeRate == nil || m.ExchangeCurrency == nil {
+		return nil
+	}
+	rate := &shipment.ExchangeRate{Currency: *m.ExchangeCurrency, Rate: *m.ExchangeRate}
+	if m.ExchangeRatedAt != nil {
+		rate.RatedAt = *m.ExchangeRatedAt
+	}
+	return rate
+}
+
+func toRiskColumns(risk *shipment.RiskAssessment) (*int, []models.ShipmentRiskFactor, *time.Time) {
+	if risk == nil {
+		return nil, nil, nil
+	}
+
+	factors := make([]models.ShipmentRiskFactor, len(risk.Factors))
+	for i, f := range risk.Factors {
+		factors[i] = models.ShipmentRiskFactor{Name: f.Name, Score: f.Score, Weight: f.Weight, Detail: f.Detail}
+	}
+	score, assessedAt := risk.Score, risk.AssessedAt
+	return &score, factors, &assessedAt
+}
+
+func toRiskAssessment(m *models.ShipmentModel) *shipment.RiskAssessment {
+	if m.RiskScore == nil {
+		return nil
+	}
+
+	risk := &shipment.RiskAssessment{Score: *m.RiskScore}
+	if m.RiskAssessedAt != nil {
+		risk.AssessedAt = *m.RiskAssessedAt
+	}
+	for _, f := range m.RiskFactors {
+		risk.Factors = append(risk.Factors, shipment.RiskFactor{Name: f.Name, Score: f.Score, Weight: f.Weight, Detail: f.Detail})
+	}
+	return risk
+}
+
 func toShippingRulesModel(r *shipment.ShippingRules) *models.ShippingRulesModel {
 	return &models.ShippingRulesModel{
 		ID:                    r.ID,
@@ -653,3 +1718,86 @@ func toShippingRulesEntity(m *models.ShippingRulesModel) *shipment.ShippingRules
 		ConfirmedAt:           m.ConfirmedAt,
 	}
 }
+
+func toRulesVersionModel(r *shipment.ShippingRules, version int, setBy uuid.UUID, setAt time.Time) *models.ShippingRulesVersionModel {
+	return &models.ShippingRulesVersionModel{
+		ID:                    uuid.New(),
+		ShipmentID:            r.ShipmentID,
+		Version:               version,
+		ReportCycleSec:        r.ReportCycleSec,
+		TempMin:               r.TempMin,
+		TempMax:               r.TempMax,
+		HumidityMin:           r.HumidityMin,
+		HumidityMax:           r.HumidityMax,
+		LightMax:              r.LightMax,
+		TiltMaxAngle:          r.TiltMaxAngle,
+		ImpactThresholdG:      r.ImpactThresholdG,
+		EnablePredictiveAlert: r.EnablePredictiveAlert,
+		AlertBufferTimeMin:    r.AlertBufferTimeMin,
+		SetBy:                 setBy,
+		SetAt:                 setAt,
+	}
+}
+
+func toRulesVersionEntity(m *models.ShippingRulesVersionModel) *shipment.RulesVersion {
+	return &shipment.RulesVersion{
+		ID:         m.ID,
+		ShipmentID: m.ShipmentID,
+		Version:    m.Version,
+		Rules: shipment.ShippingRules{
+			ShipmentID:            m.ShipmentID,
+			ReportCycleSec:        m.ReportCycleSec,
+			TempMin:               m.TempMin,
+			TempMax:               m.TempMax,
+			HumidityMin:           m.HumidityMin,
+			HumidityMax:           m.HumidityMax,
+			LightMax:              m.LightMax,
+			TiltMaxAngle:          m.TiltMaxAngle,
+			ImpactThresholdG:      m.ImpactThresholdG,
+			EnablePredictiveAlert: m.EnablePredictiveAlert,
+			AlertBufferTimeMin:    m.AlertBufferTimeMin,
+			SetByProviderID:       m.SetBy,
+			SetAt:                 m.SetAt,
+		},
+		SetBy: m.SetBy,
+		SetAt: m.SetAt,
+	}
+}
+
+func toPackageModel(p *shipment.Package) *models.PackageModel {
+	return &models.PackageModel{
+		ID:          p.ID,
+		ShipmentID:  p.ShipmentID,
+		Sequence:    p.Sequence,
+		Description: p.Description,
+		Weight:      p.Weight,
+		DeviceID:    p.DeviceID,
+
+		Outcome:           string(p.Outcome),
+		OutcomeNote:       p.OutcomeNote,
+		OutcomeRecordedBy: p.OutcomeRecordedBy,
+		OutcomeAt:         p.OutcomeAt,
+
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func toPackageEntity(m *models.PackageModel) *shipment.Package {
+	return &shipment.Package{
+		ID:          m.ID,
+		ShipmentID:  m.ShipmentID,
+		Sequence:    m.Sequence,
+		Description: m.Description,
+		Weight:      m.Weight,
+		DeviceID:    m.DeviceID,
+
+		Outcome:           shipment.PackageOutcome(m.Outcome),
+		OutcomeNote:       m.OutcomeNote,
+		OutcomeRecordedBy: m.OutcomeRecordedBy,
+		OutcomeAt:         m.OutcomeAt,
+
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
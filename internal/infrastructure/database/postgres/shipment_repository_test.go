@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Shipment list benchmarks. Run them with `make bench`. Baseline on one
+// Intel Xeon core, linux/amd64, against sqlmock:
+//
+//	BenchmarkShipmentListQuery     46000 ns/op    28900 B/op    319 allocs/op
+//	BenchmarkShipmentList50Rows   900000 ns/op   303000 B/op   2091 allocs/op
+//
+// Timings vary with the machine; allocations do not, so
+// TestShipmentListQueryAllocationBudget fails when building the query
+// allocates well past this baseline.
+
+// listFilter is a shipment list request with every filter set
+func listFilter() *shipment.Filter {
+	status := shipment.StatusInTransit
+	customerID, providerID, shipperID, deviceID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	sandbox, delayed, hasDevice := false, true, true
+	from, to := time.Now().Add(-30*24*time.Hour), time.Now()
+	return &shipment.Filter{
+		Status: &status, CustomerID: &customerID, ProviderID: &providerID, ShipperID: &shipperID, DeviceID: &deviceID,
+		Sandbox: &sandbox, CreatedAfter: &from, CreatedBefore: &to, DeliveryAfter: &from, DeliveryBefore: &to,
+		IsDelayed: &delayed, HasDevice: &hasDevice, Search: "ho chi minh quan 1",
+		Page: 2, PageSize: 50, SortBy: "created_at", SortOrder: "desc",
+	}
+}
+
+// newDryRunShipmentRepository builds statements without sending them, so
+// only query building is measured
+func newDryRunShipmentRepository(tb testing.TB) *ShipmentRepository {
+	db, _ := newMockDB(tb)
+	return NewShipmentRepository(&DB{DB: db.DB.Session(&gorm.Session{DryRun: true})})
+}
+
+func BenchmarkShipmentListQuery(b *testing.B) {
+	repo := newDryRunShipmentRepository(b)
+	ctx := context.Background()
+	filter := listFilter()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, _, err := repo.List(ctx, filter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// shipmentListAllocBudget is about 1.5 times the allocations of
+// BenchmarkShipmentListQuery
+const shipmentListAllocBudget = 480
+
+func TestShipmentListQueryAllocationBudget(t *testing.T) {
+	repo := newDryRunShipmentRepository(t)
+	ctx := context.Background()
+	filter := listFilter()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, _, err := repo.List(ctx, filter); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > shipmentListAllocBudget {
+		t.Errorf("building the shipment list query takes %.0f allocations, budget is %d", allocs, shipmentListAllocBudget)
+	}
+}
+
+// BenchmarkShipmentList50Rows measures a full page: the count, the page query,
+// scanning 50 rows, the customer and provider preloads and the conversion to
+// domain entities
+func BenchmarkShipmentList50Rows(b *testing.B) {
+	db, mock := newMockDB(b)
+	repo := NewShipmentRepository(db)
+	ctx := context.Background()
+	filter := &shipment.Filter{PageSize: 50}
+
+	customerID, providerID := uuid.New(), uuid.New()
+	now := time.Now()
+	shipmentRows := func() *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"id", "customer_id", "provider_id", "status", "goods_description",
+			"pickup_address", "delivery_address", "created_at", "updated_at"})
+		for range 50 {
+			rows.AddRow(uuid.New(), customerID, providerID, "in_transit", "Vaccines",
+				"1 Le Loi, Quan 1, Ho Chi Minh", "2 Tran Phu, Hai Chau, Da Nang", now, now)
+		}
+		return rows
+	}
+	userRows := func(id uuid.UUID) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "full_name", "role"}).
+			AddRow(id, "user", "user@example.com", "User", "customer")
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		b.StopTimer()
+		mock.ExpectQuery(`SELECT count`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(50))
+		mock.ExpectQuery(`SELECT \* FROM "shipments"`).WillReturnRows(shipmentRows())
+		mock.ExpectQuery(`FROM "users"`).WillReturnRows(userRows(customerID))
+		mock.ExpectQuery(`FROM "users"`).WillReturnRows(userRows(providerID))
+		b.StartTimer()
+
+		found, _, err := repo.List(ctx, filter)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(found) != 50 {
+			b.Fatalf("List returned %d shipments, want 50", len(found))
+		}
+	}
+}
+
+// detailRow is a shipment detail row as GetDetail's single query returns it
+func detailRow(shipmentID uuid.UUID) *sqlmock.Rows {
+	now := time.Now()
+	packages := `[{"id":"` + uuid.NewString() + `","shipment_id":"` + shipmentID.String() + `","sequence":1,"description":"Box 1","outcome":"pending"},` +
+		`{"id":"` + uuid.NewString() + `","shipment_id":"` + shipmentID.String() + `","sequence":2,"description":"Box 2","outcome":"pending"}]`
+	return sqlmock.NewRows([]string{"id", "customer_id", "provider_id", "status", "goods_description", "created_at", "updated_at",
+		"packages", "Rules__id", "Rules__shipment_id", "Rules__report_cycle_sec"}).
+		AddRow(shipmentID, uuid.New(), uuid.New(), "in_transit", "Vaccines", now, now,
+			packages, uuid.New(), shipmentID, 60)
+}
+
+func TestGetDetailReadsEverythingInOneQuery(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewShipmentRepository(db)
+	shipmentID := uuid.New()
+
+	mock.ExpectQuery(`SELECT shipments\.\*, \(SELECT COALESCE\(json_agg`).
+		WithArgs(shipmentID, 1).
+		WillReturnRows(detailRow(shipmentID))
+
+	detail, err := repo.GetDetail(context.Background(), shipmentID)
+	if err != nil {
+		t.Fatalf("GetDetail: %v", err)
+	}
+	// sqlmock fails any statement past the expected one
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	if detail.Shipment.ID != shipmentID || detail.Shipment.GoodsDescription != "Vaccines" {
+		t.Errorf("shipment = %s %q, want %s Vaccines", detail.Shipment.ID, detail.Shipment.GoodsDescription, shipmentID)
+	}
+	if detail.Rules == nil || detail.Rules.ReportCycleSec != 60 {
+		t.Errorf("rules = %+v, want the joined rules", detail.Rules)
+	}
+	if len(detail.Packages) != 2 || detail.Packages[0].Description != "Box 1" || detail.Packages[1].Sequence != 2 {
+		t.Errorf("packages = %d, want Box 1 and Box 2 in order", len(detail.Packages))
+	}
+	if detail.TermsAcceptance != nil {
+		t.Errorf("terms acceptance = %+v, want nil when not accepted", detail.TermsAcceptance)
+	}
+}
+
+// roundTrip is the simulated network latency of one query, so the GetDetail
+// benchmarks compare round trips rather than sqlmock overhead. Baseline on
+// the machine above:
+//
+//	BenchmarkGetDetail             1 query    1.6 ms/op   85600 B/op   430 allocs/op
+//	BenchmarkGetDetailSequential   3 queries  3.7 ms/op   40500 B/op   386 allocs/op
+const roundTrip = 400 * time.Microsecond
+
+func BenchmarkGetDetail(b *testing.B) {
+	db, mock := newMockDB(b)
+	repo := NewShipmentRepository(db)
+	ctx := context.Background()
+	shipmentID := uuid.New()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		b.StopTimer()
+		mock.ExpectQuery(`SELECT shipments\.\*`).WillDelayFor(roundTrip).WillReturnRows(detailRow(shipmentID))
+		b.StartTimer()
+
+		if _, err := repo.GetDetail(ctx, shipmentID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetDetailSequential reads the same detail the way it was read
+// before GetDetail: the shipment, then its rules, then its packages
+func BenchmarkGetDetailSequential(b *testing.B) {
+	db, mock := newMockDB(b)
+	repo := NewShipmentRepository(db)
+	ctx := context.Background()
+	shipmentID := uuid.New()
+	now := time.Now()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		b.StopTimer()
+		mock.ExpectQuery(`FROM "shipments"`).WillDelayFor(roundTrip).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "status", "goods_description", "created_at", "updated_at"}).
+				AddRow(shipmentID, "in_transit", "Vaccines", now, now))
+		mock.ExpectQuery(`FROM "shipping_rules"`).WillDelayFor(roundTrip).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "shipment_id", "report_cycle_sec"}).AddRow(uuid.New(), shipmentID, 60))
+		mock.ExpectQuery(`FROM "shipment_packages"`).WillDelayFor(roundTrip).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "shipment_id", "sequence", "description", "outcome"}).
+				AddRow(uuid.New(), shipmentID, 1, "Box 1", "pending").
+				AddRow(uuid.New(), shipmentID, 2, "Box 2", "pending"))
+		b.StartTimer()
+
+		if _, err := repo.GetByID(ctx, shipmentID); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.GetRulesByShipmentID(ctx, shipmentID); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.ListPackages(ctx, shipmentID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TermsRepository implements domain.Shipment.TermsRepository interface
+type TermsRepository struct {
+	db *DB
+}
+
+// NewTermsRepository creates a new terms of carriage repository
+func NewTermsRepository(db *DB) shipment.TermsRepository {
+	return &TermsRepository{db: db}
+}
+
+func (r *TermsRepository) CreateTerms(ctx context.Context, terms *shipment.CarriageTerms) error {
+	if terms.ID == uuid.Nil {
+		terms.ID = uuid.New()
+	}
+	terms.CreatedAt = time.Now()
+
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialise publishing per provider so versions stay gapless
+		var latest models.CarriageTermsModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider_id = ?", terms.ProviderID).
+			Order("version DESC").
+			First(&latest).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			terms.Version = 1
+		case err != nil:
+			return fmt.Errorf("failed to get latest terms: %w", err)
+		default:
+			terms.Version = latest.Version + 1
+		}
+
+		if err := tx.Create(toCarriageTermsModel(terms)).Error; err != nil {
+			return fmt.Errorf("failed to create terms: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *TermsRepository) GetTerms(ctx context.Context, termsID uuid.UUID) (*shipment.CarriageTerms, error) {
+	var dbModel models.CarriageTermsModel
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ?", termsID).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrTermsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get terms: %w", err)
+	}
+
+	return toCarriageTermsEntity(&dbModel), nil
+}
+
+func (r *TermsRepository) GetLatestTerms(ctx context.Context, providerID uuid.UUID) (*shipment.CarriageTerms, error) {
+	var dbModel models.CarriageTermsModel
+	err := r.db.DB.WithContext(ctx).
+		Where("provider_id = ?", providerID).
+		Order("version DESC").
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrTermsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest terms: %w", err)
+	}
+
+	return toCarriageTermsEntity(&dbModel), nil
+}
+
+func (r *TermsRepository) ListTerms(ctx context.Context, providerID uuid.UUID) ([]*shipment.CarriageTerms, error) {
+	var dbModels []models.CarriageTermsModel
+	err := r.db.DB.WithContext(ctx).
+		Where("provider_id = ?", providerID).
+		Order("version DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list terms: %w", err)
+	}
+
+	terms := make([]*shipment.CarriageTerms, len(dbModels))
+	for i := range dbModels {
+		terms[i] = toCarriageTermsEntity(&dbModels[i])
+	}
+	return terms, nil
+}
+
+func (r *TermsRepository) CreateAcceptance(ctx context.Context, acceptance *shipment.TermsAcceptance) error {
+	if acceptance.ID == uuid.Nil {
+		acceptance.ID = uuid.New()
+	}
+
+	dbModel := toTermsAcceptanceModel(acceptance)
+	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to create terms acceptance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TermsRepository) GetAcceptance(ctx context.Context, shipmentID uuid.UUID) (*shipment.TermsAcceptance, error) {
+	var dbModel models.TermsAcceptanceModel
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrTermsAcceptanceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get terms acceptance: %w", err)
+	}
+
+	return toTermsAcceptanceEntity(&dbModel), nil
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toCarriageTermsModel(t *shipment.CarriageTerms) *models.CarriageTermsModel {
+	return &models.CarriageTermsModel{
+		ID:         t.ID,
+		ProviderID: t.ProviderID,
+		Version:    t.Version,
+		Title:      t.Title,
+		Body:       t.Body,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+func toCarriageTermsEntity(m *models.CarriageTermsModel) *shipment.CarriageTerms {
+	return &shipment.CarriageTerms{
+		ID:         m.ID,
+		ProviderID: m.ProviderID,
+		Version:    m.Version,
+		Title:      m.Title,
+		Body:       m.Body,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func toTermsAcceptanceModel(a *shipment.TermsAcceptance) *models.TermsAcceptanceModel {
+	return &models.TermsAcceptanceModel{
+		ID:                   a.ID,
+		ShipmentID:           a.ShipmentID,
+		TermsID:              a.TermsID,
+		TermsVersion:         a.TermsVersion,
+		ShipperID:            a.ShipperID,
+		IPAddress:            a.IPAddress,
+		UserAgent:            a.UserAgent,
+		SignatureKey:         a.SignatureKey,
+		SignatureContentType: a.SignatureContentType,
+		AcceptedAt:           a.AcceptedAt,
+	}
+}
+
+func toTermsAcceptanceEntity(m *models.TermsAcceptanceModel) *shipment.TermsAcceptance {
+	return &shipment.TermsAcceptance{
+		ID:                   m.ID,
+		ShipmentID:           m.ShipmentID,
+		TermsID:              m.TermsID,
+		TermsVersion:         m.TermsVersion,
+		ShipperID:            m.ShipperID,
+		IPAddress:            m.IPAddress,
+		UserAgent:            m.UserAgent,
+		SignatureKey:         m.SignatureKey,
+		SignatureContentType: m.SignatureContentType,
+		AcceptedAt:           m.AcceptedAt,
+	}
+}
@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TripRepository implements domain.Shipment.TripRepository interface
+type TripRepository struct {
+	db *DB
+}
+
+// NewTripRepository creates a new trip repository
+func NewTripRepository(db *DB) shipment.TripRepository {
+	return &TripRepository{db: db}
+}
+
+func (r *TripRepository) Create(ctx context.Context, trip *shipment.Trip) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if trip.ID == uuid.Nil {
+			trip.ID = uuid.New()
+		}
+		trip.CreatedAt = now
+		trip.UpdatedAt = now
+
+		if err := tx.Omit("Stops").Create(toTripModel(trip)).Error; err != nil {
+			return fmt.Errorf("failed to create trip: %w", err)
+		}
+
+		shipmentIDs := make([]uuid.UUID, len(trip.Stops))
+		for i := range trip.Stops {
+			stop := &trip.Stops[i]
+			if stop.ID == uuid.Nil {
+				stop.ID = uuid.New()
+			}
+			stop.TripID = trip.ID
+			if err := tx.Create(toTripStopModel(stop)).Error; err != nil {
+				return fmt.Errorf("failed to create trip stop: %w", err)
+			}
+			shipmentIDs[i] = stop.ShipmentID
+		}
+
+		// Move the member shipments onto the trip device and release the
+		// devices they had
+		var previous []uuid.UUID
+		if err := tx.Model(&models.ShipmentModel{}).
+			Where("id IN ? AND linked_device_id IS NOT NULL AND linked_device_id <> ?", shipmentIDs, trip.DeviceID).
+			Distinct().
+			Pluck("linked_device_id", &previous).Error; err != nil {
+			return fmt.Errorf("failed to read member devices: %w", err)
+		}
+
+		if err := tx.Model(&models.ShipmentModel{}).
+			Where("id IN ? AND linked_device_id IS DISTINCT FROM ?", shipmentIDs, trip.DeviceID).
+			Updates(map[string]interface{}{
+				"linked_device_id": trip.DeviceID,
+				"updated_at":       now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to link trip device: %w", err)
+		}
+
+		if len(previous) > 0 {
+			if err := tx.Model(&models.DeviceModel{}).
+				Where("id IN ?", previous).
+				Updates(map[string]interface{}{
+					"status":     "available",
+					"updated_at": now,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to release member devices: %w", err)
+			}
+		}
+
+		if err := tx.Model(&models.DeviceModel{}).
+			Where("id = ?", trip.DeviceID).
+			Updates(map[string]interface{}{
+				"status":     "in_transit",
+				"updated_at": now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update trip device: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *TripRepository) GetByID(ctx context.Context, tripID uuid.UUID) (*shipment.Trip, error) {
+	var dbModel models.TripModel
+	err := r.withStops(r.db.DB.WithContext(ctx)).
+		First(&dbModel, "id = ?", tripID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrTripNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	return toTripEntity(&dbModel), nil
+}
+
+func (r *TripRepository) ListByShipper(ctx context.Context, shipperID uuid.UUID, status *shipment.TripStatus) ([]*shipment.Trip, error) {
+	query := r.withStops(r.db.DB.WithContext(ctx)).
+		Where("shipper_id = ?", shipperID)
+	if status != nil {
+		query = query.Where("status = ?", string(*status))
+	}
+
+	var dbModels []models.TripModel
+	if err := query.Order("created_at DESC").Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list trips: %w", err)
+	}
+
+	trips := make([]*shipment.Trip, len(dbModels))
+	for i := range dbModels {
+		trips[i] = toTripEntity(&dbModels[i])
+	}
+	return trips, nil
+}
+
+func (r *TripRepository) FindActiveByShipment(ctx context.Context, shipmentID uuid.UUID) (*shipment.Trip, error) {
+	var dbModel models.TripModel
+	err := r.withStops(r.db.DB.WithContext(ctx)).
+		Where("status = ?", string(shipment.TripActive)).
+		Where("id IN (SELECT trip_id FROM trip_stops WHERE shipment_id = ?)", shipmentID).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrTripNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trip: %w", err)
+	}
+
+	return toTripEntity(&dbModel), nil
+}
+
+func (r *TripRepository) FindActiveByDevice(ctx context.Context, deviceID uuid.UUID) (*shipment.Trip, error) {
+	var dbModel models.TripModel
+	err := r.withStops(r.db.DB.WithContext(ctx)).
+		Where("device_id = ? AND status = ?", deviceID, string(shipment.TripActive)).
+		First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, shipment.ErrTripNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trip: %w", err)
+	}
+
+	return toTripEntity(&dbModel), nil
+}
+
+func (r *TripRepository) CompleteStop(ctx context.Context, stopID uuid.UUID, at time.Time) error {
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.TripStopModel{}).
+		Where("id = ? AND completed_at IS NULL", stopID).
+		Update("completed_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete trip stop: %w", err)
+	}
+	return nil
+}
+
+func (r *TripRepository) UpdateStatus(ctx context.Context, tripID uuid.UUID, status shipment.TripStatus, at time.Time) error {
+	updates := map[string]interface{}{
+		"status":     string(status),
+		"updated_at": at,
+	}
+	if status != shipment.TripActive {
+		updates["completed_at"] = at
+	}
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.TripModel{}).
+		Where("id = ?", tripID).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update trip status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shipment.ErrTripNotFound
+	}
+	return nil
+}
+
+func (r *TripRepository) withStops(db *gorm.DB) *gorm.DB {
+	return db.Preload("Stops", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sequence ASC")
+	})
+}
+
+// Helper functions to convert between domain entities and database models
+func toTripModel(t *shipment.Trip) *models.TripModel {
+	return &models.TripModel{
+		ID:          t.ID,
+		ShipperID:   t.ShipperID,
+		DeviceID:    t.DeviceID,
+		Name:        t.Name,
+		Status:      string(t.Status),
+		CompletedAt: t.CompletedAt,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func toTripStopModel(s *shipment.TripStop) *models.TripStopModel {
+	return &models.TripStopModel{
+		ID:          s.ID,
+		TripID:      s.TripID,
+		ShipmentID:  s.ShipmentID,
+		Sequence:    s.Sequence,
+		CompletedAt: s.CompletedAt,
+	}
+}
+
+func toTripEntity(m *models.TripModel) *shipment.Trip {
+	trip := &shipment.Trip{
+		ID:          m.ID,
+		ShipperID:   m.ShipperID,
+		DeviceID:    m.DeviceID,
+		Name:        m.Name,
+		Status:      shipment.TripStatus(m.Status),
+		CompletedAt: m.CompletedAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+		Stops:       make([]shipment.TripStop, len(m.Stops)),
+	}
+	for i, s := range m.Stops {
+		trip.Stops[i] = shipment.TripStop{
+			ID:          s.ID,
+			TripID:      s.TripID,
+			ShipmentID:  s.ShipmentID,
+			Sequence:    s.Sequence,
+			CompletedAt: s.CompletedAt,
+		}
+	}
+	return trip
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"cargo-tracker/internal/domain/shipment"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+// expectTripInsert expects the trip and its stops to be inserted
+func expectTripInsert(mock sqlmock.Sqlmock, trip *shipment.Trip) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "trips"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	for range trip.Stops {
+		mock.ExpectQuery(`INSERT INTO "trip_stops"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	}
+}
+
+func newTrip() *shipment.Trip {
+	return &shipment.Trip{
+		ShipperID: uuid.New(),
+		DeviceID:  uuid.New(),
+		Status:    shipment.TripActive,
+		Stops: []shipment.TripStop{
+			{ShipmentID: uuid.New(), Sequence: 1},
+			{ShipmentID: uuid.New(), Sequence: 2},
+		},
+	}
+}
+
+func TestTripCreateMovesMembersInOneTransaction(t *testing.T) {
+	db, mock := newMockDB(t)
+	trip := newTrip()
+	previous := uuid.New()
+
+	expectTripInsert(mock, trip)
+	mock.ExpectQuery(`SELECT DISTINCT "linked_device_id" FROM "shipments"`).
+		WithArgs(trip.Stops[0].ShipmentID, trip.Stops[1].ShipmentID, trip.DeviceID).
+		WillReturnRows(sqlmock.NewRows([]string{"linked_device_id"}).AddRow(previous))
+	mock.ExpectExec(`UPDATE "shipments" SET "linked_device_id"=\$1,"updated_at"=\$2 WHERE id IN \(\$3,\$4\) AND linked_device_id IS DISTINCT FROM \$5`).
+		WithArgs(trip.DeviceID, sqlmock.AnyArg(), trip.Stops[0].ShipmentID, trip.Stops[1].ShipmentID, trip.DeviceID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE "devices" SET "status"=\$1,"updated_at"=\$2 WHERE id IN \(\$3\)`).
+		WithArgs("available", sqlmock.AnyArg(), previous).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "devices" SET "status"=\$1,"updated_at"=\$2 WHERE id = \$3`).
+		WithArgs("in_transit", sqlmock.AnyArg(), trip.DeviceID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := NewTripRepository(db).Create(context.Background(), trip); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTripCreateRollsBackWhenLinkingFails(t *testing.T) {
+	db, mock := newMockDB(t)
+	trip := newTrip()
+
+	expectTripInsert(mock, trip)
+	mock.ExpectQuery(`SELECT DISTINCT "linked_device_id" FROM "shipments"`).
+		WillReturnRows(sqlmock.NewRows([]string{"linked_device_id"}))
+	mock.ExpectExec(`UPDATE "shipments"`).WillReturnError(errors.New("deadlock detected"))
+	// Neither the trip nor its stops are kept, and no device changes status
+	mock.ExpectRollback()
+
+	if err := NewTripRepository(db).Create(context.Background(), trip); err == nil {
+		t.Fatal("Create succeeded although linking the trip device failed")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
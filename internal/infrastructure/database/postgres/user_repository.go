@@ -3,6 +3,7 @@ package postgres
 import (
 	"cargo-tracker/internal/domain/user"
 	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"cargo-tracker/internal/infrastructure/encryption"
 	"context"
 	"errors"
 	"fmt"
@@ -35,6 +36,9 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 		if strings.Contains(errStr, "duplicate key") && strings.Contains(errStr, "email") {
 			return user.ErrUserAlreadyExists
 		}
+		if strings.Contains(errStr, "duplicate key") && strings.Contains(errStr, "phone_number") {
+			return user.ErrPhoneAlreadyExists
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -60,6 +64,29 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 	return toUserEntity(&dbModel), nil
 }
 
+func (r *UserRepository) GetByPhone(ctx context.Context, phoneNumber string) (*user.User, error) {
+	query := r.db.DB.WithContext(ctx)
+	// Encrypted numbers are found through their blind index, plaintext ones
+	// stored before encryption was enabled by value
+	if hash := encryption.BlindIndexOptional(&phoneNumber); hash != nil {
+		query = query.Where("phone_number_hash = ? OR (phone_number_hash IS NULL AND phone_number = ?)", *hash, phoneNumber)
+	} else {
+		query = query.Where("phone_number = ?", phoneNumber)
+	}
+
+	var dbModel models.UserModel
+	err := query.First(&dbModel).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, user.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return toUserEntity(&dbModel), nil
+}
+
 func (r *UserRepository) GetByID(ctx context.Context, userID uuid.UUID) (*user.User, error) {
 	var dbModel models.UserModel
 	err := r.db.DB.WithContext(ctx).First(&dbModel, "id = ?", userID).Error
@@ -74,11 +101,42 @@ func (r *UserRepository) GetByID(ctx context.Context, userID uuid.UUID) (*user.U
 	return toUserEntity(&dbModel), nil
 }
 
-func (r *UserRepository) GetAll(ctx context.Context) ([]*user.User, error) {
+func (r *UserRepository) List(ctx context.Context, filter *user.Filter) ([]*user.User, int64, error) {
+	db := applyUserFilter(r.db.DB.WithContext(ctx).Model(&models.UserModel{}), filter)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	// Apply sorting
+	sortBy := "created_at"
+	if filter.SortBy != "" {
+		sortBy = filter.SortBy
+	}
+	sortOrder := "DESC"
+	if strings.ToLower(filter.SortOrder) == "asc" {
+		sortOrder = "ASC"
+	}
+
+	// Apply pagination
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+
 	var dbModels []models.UserModel
-	err := r.db.DB.WithContext(ctx).Find(&dbModels).Error
+	err := db.Order(fmt.Sprintf("%s %s, id", sortBy, sortOrder)).
+		Limit(pageSize).
+		Offset(offset).
+		Find(&dbModels).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to get users: %w", err)
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
 	}
 
 	users := make([]*user.User, len(dbModels))
@@ -86,22 +144,69 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]*user.User, error) {
 		users[i] = toUserEntity(&dbModel)
 	}
 
-	return users, nil
+	return users, total, nil
+}
+
+func (r *UserRepository) Count(ctx context.Context, filter *user.Filter) (int64, error) {
+	var total int64
+	if err := applyUserFilter(r.db.DB.WithContext(ctx).Model(&models.UserModel{}), filter).
+		Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return total, nil
+}
+
+func applyUserFilter(db *gorm.DB, filter *user.Filter) *gorm.DB {
+	if filter.Role != "" {
+		db = db.Where("role = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		db = db.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.RegisteredAfter != nil {
+		db = db.Where("created_at >= ?", filter.RegisteredAfter)
+	}
+	if filter.RegisteredBefore != nil {
+		db = db.Where("created_at <= ?", filter.RegisteredBefore)
+	}
+	if filter.Search != "" {
+		// search_text holds the normalized name and email
+		db = db.Where("search_text LIKE ?", "%"+escapeLike(filter.Search)+"%")
+	}
+	return db
 }
 
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	u.UpdatedAt = time.Now()
 
+	// Map updates bypass GORM serializers, so encrypt explicitly
+	phoneNumber, err := encryption.EncryptOptional(u.PhoneNumber,
+		encryption.Binding{Table: "users", Column: "phone_number", RowID: u.ID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to encrypt phone number: %w", err)
+	}
+	address, err := encryption.EncryptOptional(u.Address,
+		encryption.Binding{Table: "users", Column: "address", RowID: u.ID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to encrypt address: %w", err)
+	}
+
 	result := r.db.DB.WithContext(ctx).Model(&models.UserModel{}).
 		Where("id = ?", u.ID).
 		Updates(map[string]interface{}{
-			"full_name":    u.FullName,
-			"phone_number": u.PhoneNumber,
-			"address":      u.Address,
-			"updated_at":   u.UpdatedAt,
+			"full_name":         u.FullName,
+			"phone_number":      phoneNumber,
+			"phone_number_hash": encryption.BlindIndexOptional(u.PhoneNumber),
+			"phone_verified_at": u.PhoneVerified,
+			"address":           address,
+			"default_currency":  u.DefaultCurrency,
+			"updated_at":        u.UpdatedAt,
 		})
 
 	if result.Error != nil {
+		if strings.Contains(strings.ToLower(result.Error.Error()), "duplicate key") {
+			return user.ErrPhoneAlreadyExists
+		}
 		return fmt.Errorf("failed to update user: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
@@ -111,6 +216,24 @@ func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	return nil
 }
 
+func (r *UserRepository) SetPhoneVerified(ctx context.Context, userID uuid.UUID, at *time.Time) error {
+	result := r.db.DB.WithContext(ctx).Model(&models.UserModel{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"phone_verified_at": at,
+			"updated_at":        time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update phone verification: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
 	result := r.db.DB.WithContext(ctx).Model(&models.UserModel{}).
 		Where("id = ?", userID).
@@ -129,6 +252,42 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, p
 	return nil
 }
 
+func (r *UserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role string) error {
+	result := r.db.DB.WithContext(ctx).Model(&models.UserModel{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"role":       role,
+			"updated_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update role: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdatePlan(ctx context.Context, userID uuid.UUID, plan string) error {
+	result := r.db.DB.WithContext(ctx).Model(&models.UserModel{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"plan":       plan,
+			"updated_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update plan: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepository) Delete(ctx context.Context, userID uuid.UUID) error {
 	result := r.db.DB.WithContext(ctx).Delete(&models.UserModel{}, "id = ?", userID)
 	if result.Error != nil {
@@ -187,33 +346,42 @@ func (r *UserRepository) MarkTokenAsUsed(ctx context.Context, tokenID uuid.UUID)
 
 func toUserModel(u *user.User) *models.UserModel {
 	return &models.UserModel{
-		ID:             u.ID,
-		Username:       u.Username,
-		Email:          u.Email,
-		PasswordHashed: u.PasswordHashed,
-		FullName:       u.FullName,
-		PhoneNumber:    u.PhoneNumber,
-		Role:           u.Role,
-		Address:        u.Address,
-		IsActive:       u.IsActive,
-		CreatedAt:      u.CreatedAt,
-		UpdatedAt:      u.UpdatedAt,
+		ID:              u.ID,
+		Username:        u.Username,
+		Email:           u.Email,
+		PasswordHashed:  u.PasswordHashed,
+		FullName:        u.FullName,
+		PhoneNumber:     u.PhoneNumber,
+		PhoneHash:       encryption.BlindIndexOptional(u.PhoneNumber),
+		PhoneVerified:   u.PhoneVerified,
+		Role:            u.Role,
+		Address:         u.Address,
+		IsActive:        u.IsActive,
+		IsSandbox:       u.IsSandbox,
+		Plan:            u.Plan,
+		DefaultCurrency: u.DefaultCurrency,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
 	}
 }
 
 func toUserEntity(m *models.UserModel) *user.User {
 	return &user.User{
-		ID:             m.ID,
-		Username:       m.Username,
-		Email:          m.Email,
-		PasswordHashed: m.PasswordHashed,
-		FullName:       m.FullName,
-		PhoneNumber:    m.PhoneNumber,
-		Role:           m.Role,
-		Address:        m.Address,
-		IsActive:       m.IsActive,
-		CreatedAt:      m.CreatedAt,
-		UpdatedAt:      m.UpdatedAt,
+		ID:              m.ID,
+		Username:        m.Username,
+		Email:           m.Email,
+		PasswordHashed:  m.PasswordHashed,
+		FullName:        m.FullName,
+		PhoneNumber:     m.PhoneNumber,
+		PhoneVerified:   m.PhoneVerified,
+		Role:            m.Role,
+		Address:         m.Address,
+		IsActive:        m.IsActive,
+		IsSandbox:       m.IsSandbox,
+		Plan:            m.Plan,
+		DefaultCurrency: m.DefaultCurrency,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
 	}
 }
 
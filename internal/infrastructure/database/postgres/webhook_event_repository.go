@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/infrastructure/database/postgres/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookEventRepository implements domain.Notification.WebhookEventRepository interface
+type WebhookEventRepository struct {
+	db *DB
+}
+
+// NewWebhookEventRepository creates a new webhook event archive repository
+func NewWebhookEventRepository(db *DB) domainNotification.WebhookEventRepository {
+	return &WebhookEventRepository{db: db}
+}
+
+func (r *WebhookEventRepository) Create(ctx context.Context, event *domainNotification.WebhookEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+
+	data := event.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+	dbModel := &models.WebhookEventModel{
+		ID:        event.ID,
+		WebhookID: event.WebhookID,
+		OwnerID:   event.OwnerID,
+		Event:     event.Event,
+		Severity:  string(event.Severity),
+		Subject:   event.Subject,
+		Body:      event.Body,
+		Link:      event.Link,
+		Data:      data,
+		CreatedAt: event.CreatedAt,
+	}
+	if err := r.db.DB.WithContext(ctx).Create(dbModel).Error; err != nil {
+		return fmt.Errorf("failed to archive webhook event: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookEventRepository) RecordDelivery(ctx context.Context, eventID uuid.UUID, deliveryErr error) error {
+	updates := map[string]interface{}{
+		"delivered":  deliveryErr == nil,
+		"last_error": deliveryErrorMessage(deliveryErr),
+	}
+
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.WebhookEventModel{}).
+		Where("id = ?", eventID).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to record webhook event delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookEventRepository) RecordReplay(ctx context.Context, eventID uuid.UUID, at time.Time, deliveryErr error) error {
+	updates := map[string]interface{}{
+		"replay_count":     gorm.Expr("replay_count + 1"),
+		"last_replayed_at": at,
+		"last_error":       deliveryErrorMessage(deliveryErr),
+	}
+	// A failed replay does not undo an earlier successful delivery
+	if deliveryErr == nil {
+		updates["delivered"] = true
+	}
+
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.WebhookEventModel{}).
+		Where("id = ?", eventID).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to record webhook event replay: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookEventRepository) List(ctx context.Context, filter *domainNotification.WebhookEventFilter) ([]*domainNotification.WebhookEvent, error) {
+	db := r.db.DB.WithContext(ctx).
+		Model(&models.WebhookEventModel{}).
+		Where("owner_id = ?", filter.OwnerID)
+
+	if filter.WebhookID != nil {
+		db = db.Where("webhook_id = ?", *filter.WebhookID)
+	}
+	if filter.Event != "" {
+		db = db.Where("event = ?", filter.Event)
+	}
+	if filter.Delivered != nil {
+		db = db.Where("delivered = ?", *filter.Delivered)
+	}
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("created_at < ?", *filter.To)
+	}
+	if len(filter.IDs) > 0 {
+		db = db.Where("id IN ?", filter.IDs)
+	}
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+
+	var dbModels []models.WebhookEventModel
+	if err := db.Order("created_at ASC, id ASC").Find(&dbModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook events: %w", err)
+	}
+
+	events := make([]*domainNotification.WebhookEvent, len(dbModels))
+	for i := range dbModels {
+		events[i] = toWebhookEventEntity(&dbModels[i])
+	}
+	return events, nil
+}
+
+func (r *WebhookEventRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.DB.WithContext(ctx).
+		Where("created_at < ?", before).
+		Delete(&models.WebhookEventModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge webhook events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// deliveryErrorMessage returns the stored form of a delivery error, nil on success
+func deliveryErrorMessage(deliveryErr error) interface{} {
+	if deliveryErr == nil {
+		return nil
+	}
+	message := deliveryErr.Error()
+	if len(message) > maxWebhookErrorLength {
+		message = message[:maxWebhookErrorLength]
+	}
+	return message
+}
+
+// Helper functions to convert between domain entities and database models
+
+func toWebhookEventEntity(m *models.WebhookEventModel) *domainNotification.WebhookEvent {
+	return &domainNotification.WebhookEvent{
+		ID:             m.ID,
+		WebhookID:      m.WebhookID,
+		OwnerID:        m.OwnerID,
+		Event:          m.Event,
+		Severity:       domainNotification.Severity(m.Severity),
+		Subject:        m.Subject,
+		Body:           m.Body,
+		Link:           m.Link,
+		Data:           m.Data,
+		Delivered:      m.Delivered,
+		LastError:      m.LastError,
+		ReplayCount:    m.ReplayCount,
+		LastReplayedAt: m.LastReplayedAt,
+		CreatedAt:      m.CreatedAt,
+	}
+}
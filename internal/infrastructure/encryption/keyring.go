@@ -0,0 +1,302 @@
+package encryption
+
+import (
+	"cargo-tracker/internal/config"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// envelopePrefix marks an encrypted value. The full format is
+// enc:v2:<key id>:<wrapped data key>:<ciphertext>, where the data key is a
+// random AES-256 key sealed with the named key-encryption key and the
+// ciphertext is bound to the cell it is stored in. enc:v1: values carry no
+// binding; they can still be read and are rewritten by key rotation.
+const (
+	envelopePrefix = "enc:v2:"
+	legacyPrefix   = "enc:v1:"
+)
+
+const keySize = 32
+
+var (
+	ErrNoKeyring     = errors.New("encryption keyring is not configured")
+	ErrUnknownKey    = errors.New("unknown encryption key")
+	ErrMalformed     = errors.New("malformed encrypted value")
+	ErrInvalidKey    = errors.New("encryption keys must be 32 bytes, base64 encoded")
+	ErrNoActiveKey   = errors.New("active encryption key is not in the keyring")
+	ErrDecryptFailed = errors.New("failed to decrypt value")
+	ErrNoIndexKey    = errors.New("ENCRYPTION_INDEX_KEY is required when ENCRYPTION_KEYS is set")
+)
+
+// Keyring holds the key-encryption keys used for column encryption. New values
+// are always sealed with the active key; older keys are kept for decryption
+// until every row has been rotated.
+type Keyring struct {
+	keys     map[string][]byte
+	activeID string
+	indexKey []byte
+}
+
+// NewKeyring creates a keyring. indexKey is used for blind indexes and may be
+// nil when no encrypted column needs to be searchable.
+func NewKeyring(activeID string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	for id, key := range keys {
+		if len(key) != keySize {
+			return nil, fmt.Errorf("%w: key %q", ErrInvalidKey, id)
+		}
+	}
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoActiveKey, activeID)
+	}
+
+	return &Keyring{
+		keys:     keys,
+		activeID: activeID,
+		indexKey: indexKey,
+	}, nil
+}
+
+// ParseKeys parses a comma separated list of id:base64key pairs.
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("%w: expected id:base64key", ErrInvalidKey)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("%w: key %q", ErrInvalidKey, id)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// Binding names the cell a value is stored in. It is sealed into the
+// ciphertext as additional data, so a value copied to another row or column
+// fails to decrypt.
+type Binding struct {
+	Table  string
+	Column string
+	RowID  string
+}
+
+func (b Binding) additionalData() []byte {
+	return []byte(b.Table + "\x00" + b.Column + "\x00" + b.RowID)
+}
+
+// ActiveKeyID returns the id of the key used for new encryptions.
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt seals plaintext with a fresh data key wrapped by the active key and
+// binds it to the cell it will be stored in.
+func (k *Keyring) Encrypt(plaintext string, b Binding) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := seal(k.keys[k.activeID], dataKey, []byte(k.activeID))
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext), b.additionalData())
+	if err != nil {
+		return "", err
+	}
+
+	return envelopePrefix + k.activeID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt opens a value produced by Encrypt for the same binding. Values
+// without an envelope prefix are legacy plaintext and are returned unchanged.
+func (k *Keyring) Decrypt(value string, b Binding) (string, error) {
+	var additionalData []byte
+	switch {
+	case strings.HasPrefix(value, envelopePrefix):
+		additionalData = b.additionalData()
+	case strings.HasPrefix(value, legacyPrefix):
+		// v1 values were sealed before cells were bound
+	default:
+		return value, nil
+	}
+
+	// Both prefixes have the same length
+	parts := strings.Split(value[len(envelopePrefix):], ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	keyID := parts[0]
+	kek, ok := k.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := open(kek, wrapped, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, ciphertext, additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// ActivePrefix returns the prefix shared by every value sealed with the
+// active key in the current envelope format.
+func (k *Keyring) ActivePrefix() string {
+	return envelopePrefix + k.activeID + ":"
+}
+
+// NeedsRotation reports whether value is plaintext, in the legacy envelope or
+// sealed with a key other than the active one.
+func (k *Keyring) NeedsRotation(value string) bool {
+	return !strings.HasPrefix(value, k.ActivePrefix())
+}
+
+// BlindIndex returns a deterministic keyed hash of value so that encrypted
+// columns can still be matched for equality and uniqueness.
+func (k *Keyring) BlindIndex(value string) string {
+	if k.indexKey == nil {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether value is an encryption envelope.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix) || strings.HasPrefix(value, legacyPrefix)
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault installs the keyring used by the GORM serializer and helpers.
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default returns the installed keyring, or nil when encryption is disabled.
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
+
+// EncryptOptional encrypts an optional value for the given cell with the
+// default keyring. It is meant for map based updates, which bypass GORM
+// serializers. Without a keyring the value is returned as is.
+func EncryptOptional(value *string, b Binding) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	k := Default()
+	if k == nil {
+		return value, nil
+	}
+	encrypted, err := k.Encrypt(*value, b)
+	if err != nil {
+		return nil, err
+	}
+	return &encrypted, nil
+}
+
+// BlindIndexOptional returns the blind index of an optional value using the
+// default keyring, or nil when the value or the index key is missing.
+func BlindIndexOptional(value *string) *string {
+	k := Default()
+	if value == nil || k == nil || k.indexKey == nil {
+		return nil
+	}
+	index := k.BlindIndex(*value)
+	return &index
+}
+
+// LoadKeyring builds a keyring from configuration. It returns nil when no keys
+// are configured, leaving encryption disabled. Encrypted phone numbers can only
+// be looked up and kept unique through their blind index, so keys without an
+// index key are rejected.
+func LoadKeyring(cfg *config.EncryptionConfig) (*Keyring, error) {
+	if strings.TrimSpace(cfg.Keys) == "" {
+		return nil, nil
+	}
+
+	keys, err := ParseKeys(cfg.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(cfg.IndexKey) == "" {
+		return nil, ErrNoIndexKey
+	}
+	indexKey, err := base64.StdEncoding.DecodeString(cfg.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index key: %w", err)
+	}
+
+	return NewKeyring(cfg.ActiveKeyID, keys, indexKey)
+}
@@ -0,0 +1,218 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+)
+
+func testKey(b byte) []byte {
+	key := make([]byte, keySize)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
+
+func newTestKeyring(t *testing.T, activeID string, keys map[string][]byte) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(activeID, keys, testKey(9))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return k
+}
+
+var phoneCell = Binding{Table: "users", Column: "phone_number", RowID: "3f1c2a4e-0000-4000-8000-000000000001"}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string][]byte{"k1": testKey(1)})
+
+	sealed, err := k.Encrypt("+84901234567", phoneCell)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(sealed, "enc:v2:k1:") || strings.Contains(sealed, "84901234567") {
+		t.Fatalf("sealed value %q is not an enc:v2 envelope hiding the plaintext", sealed)
+	}
+
+	plaintext, err := k.Decrypt(sealed, phoneCell)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if plaintext != "+84901234567" {
+		t.Errorf("Decrypt = %q, want the original phone number", plaintext)
+	}
+
+	// Values written before encryption was enabled are passed through
+	if plaintext, err := k.Decrypt("+84900000000", phoneCell); err != nil || plaintext != "+84900000000" {
+		t.Errorf("Decrypt(plaintext) = %q, %v, want it unchanged", plaintext, err)
+	}
+}
+
+func TestDecryptRejectsTamperedValue(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string][]byte{"k1": testKey(1)})
+	sealed, err := k.Encrypt("+84901234567", phoneCell)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	parts := strings.Split(sealed, ":")
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		t.Fatalf("decode ciphertext: %v", err)
+	}
+	ciphertext[len(ciphertext)-1] ^= 0x01
+	parts[len(parts)-1] = base64.RawStdEncoding.EncodeToString(ciphertext)
+
+	if _, err := k.Decrypt(strings.Join(parts, ":"), phoneCell); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("Decrypt(tampered) err = %v, want ErrDecryptFailed", err)
+	}
+}
+
+func TestDecryptRejectsValueMovedToAnotherCell(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string][]byte{"k1": testKey(1)})
+	sealed, err := k.Encrypt("+84901234567", phoneCell)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	otherRow := phoneCell
+	otherRow.RowID = "3f1c2a4e-0000-4000-8000-000000000002"
+	otherColumn := phoneCell
+	otherColumn.Column = "address"
+	otherTable := phoneCell
+	otherTable.Table = "saved_addresses"
+
+	for name, b := range map[string]Binding{"row": otherRow, "column": otherColumn, "table": otherTable} {
+		if _, err := k.Decrypt(sealed, b); !errors.Is(err, ErrDecryptFailed) {
+			t.Errorf("value copied to another %s: err = %v, want ErrDecryptFailed", name, err)
+		}
+	}
+}
+
+// sealLegacy builds an enc:v1 value, which carries no cell binding
+func sealLegacy(t *testing.T, keyID string, kek []byte, plaintext string) string {
+	t.Helper()
+	dataKey := testKey(7)
+	wrapped, err := seal(kek, dataKey, []byte(keyID))
+	if err != nil {
+		t.Fatalf("seal data key: %v", err)
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		t.Fatalf("seal value: %v", err)
+	}
+	return legacyPrefix + keyID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext)
+}
+
+func TestRotationReadsOldKeysAndLegacyValues(t *testing.T) {
+	old := newTestKeyring(t, "k1", map[string][]byte{"k1": testKey(1)})
+	sealedWithOld, err := old.Encrypt("12 Ly Thuong Kiet", phoneCell)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	legacy := sealLegacy(t, "k1", testKey(1), "+84901234567")
+
+	rotated := newTestKeyring(t, "k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+
+	for value, want := range map[string]string{sealedWithOld: "12 Ly Thuong Kiet", legacy: "+84901234567"} {
+		if !rotated.NeedsRotation(value) {
+			t.Errorf("NeedsRotation(%.14s...) = false, want true", value)
+		}
+		plaintext, err := rotated.Decrypt(value, phoneCell)
+		if err != nil || plaintext != want {
+			t.Errorf("Decrypt(%.14s...) = %q, %v, want %q", value, plaintext, err, want)
+		}
+	}
+
+	resealed, err := rotated.Encrypt("+84901234567", phoneCell)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if rotated.NeedsRotation(resealed) {
+		t.Error("a value sealed with the active key still needs rotation")
+	}
+	if !rotated.NeedsRotation("+84901234567") {
+		t.Error("plaintext does not need rotation")
+	}
+
+	// A key that was dropped from the keyring cannot be read any more
+	retired := newTestKeyring(t, "k2", map[string][]byte{"k2": testKey(2)})
+	if _, err := retired.Decrypt(sealedWithOld, phoneCell); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt with a dropped key err = %v, want ErrUnknownKey", err)
+	}
+}
+
+type encryptedModel struct {
+	ID    uuid.UUID
+	Phone *string `gorm:"serializer:encrypted"`
+}
+
+func (encryptedModel) TableName() string { return "contacts" }
+
+func parseField(t *testing.T) *schema.Field {
+	t.Helper()
+	s, err := schema.Parse(&encryptedModel{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("schema.Parse: %v", err)
+	}
+	return s.LookUpField("Phone")
+}
+
+func TestSerializerBindsValueToRow(t *testing.T) {
+	k := newTestKeyring(t, "k1", map[string][]byte{"k1": testKey(1)})
+	SetDefault(k)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	ctx := context.Background()
+	field := parseField(t)
+	phone := "+84901234567"
+	written := &encryptedModel{ID: uuid.New(), Phone: &phone}
+
+	stored, err := Serializer{}.Value(ctx, field, reflect.ValueOf(written), written.Phone)
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	sealed := stored.(string)
+
+	read := &encryptedModel{ID: written.ID}
+	if err := (Serializer{}).Scan(ctx, field, reflect.ValueOf(read), sealed); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if read.Phone == nil || *read.Phone != phone {
+		t.Errorf("Scan read %v, want %q", read.Phone, phone)
+	}
+
+	other := &encryptedModel{ID: uuid.New()}
+	if err := (Serializer{}).Scan(ctx, field, reflect.ValueOf(other), sealed); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("Scan into another row err = %v, want ErrDecryptFailed", err)
+	}
+
+	if _, err := (Serializer{}).Value(ctx, field, reflect.ValueOf(&encryptedModel{}), &phone); err == nil {
+		t.Error("Value sealed a row without a primary key")
+	}
+}
+
+func TestSerializerWithoutKeyringReturnsStoredValue(t *testing.T) {
+	SetDefault(nil)
+
+	// Plaintext written before encryption was enabled may look like an envelope
+	const stored = "enc:v1:not-really-encrypted"
+	read := &encryptedModel{ID: uuid.New()}
+	if err := (Serializer{}).Scan(context.Background(), parseField(t), reflect.ValueOf(read), stored); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if read.Phone == nil || *read.Phone != stored {
+		t.Errorf("Scan read %v, want the stored value %q", read.Phone, stored)
+	}
+}
@@ -0,0 +1,100 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the name to use in struct tags: `gorm:"serializer:encrypted"`.
+const SerializerName = "encrypted"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Serializer transparently encrypts string and *string fields on write and
+// decrypts them on read using the default keyring. Values are bound to their
+// table, column and primary key, so the primary key must be set before a row
+// is written and selected ahead of the encrypted columns when it is read.
+type Serializer struct{}
+
+// Scan implements schema.SerializerInterface
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var stored string
+		switch v := dbValue.(type) {
+		case string:
+			stored = v
+		case []byte:
+			stored = string(v)
+		default:
+			return fmt.Errorf("failed to decrypt %s: unsupported value %#v", field.Name, dbValue)
+		}
+
+		// Without a keyring nothing is decrypted: the value is either legacy
+		// plaintext that happens to look like an envelope or unreadable anyway
+		plaintext := stored
+		if k := Default(); k != nil && IsEncrypted(stored) {
+			b, err := bindingOf(ctx, field, dst)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+			}
+			if plaintext, err = k.Decrypt(stored, b); err != nil {
+				return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+			}
+		}
+
+		if field.FieldType.Kind() == reflect.Ptr {
+			fieldValue.Elem().Set(reflect.ValueOf(&plaintext))
+		} else {
+			fieldValue.Elem().SetString(plaintext)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var plaintext string
+	switch v := fieldValue.(type) {
+	case string:
+		plaintext = v
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		plaintext = *v
+	default:
+		return nil, fmt.Errorf("failed to encrypt %s: unsupported type %T", field.Name, fieldValue)
+	}
+
+	k := Default()
+	if k == nil {
+		return plaintext, nil
+	}
+	b, err := bindingOf(ctx, field, dst)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s: %w", field.Name, err)
+	}
+	return k.Encrypt(plaintext, b)
+}
+
+// bindingOf names the cell field is stored in for the model in dst.
+func bindingOf(ctx context.Context, field *schema.Field, dst reflect.Value) (Binding, error) {
+	pk := field.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return Binding{}, fmt.Errorf("%s has no primary key", field.Schema.Table)
+	}
+	id, zero := pk.ValueOf(ctx, dst)
+	if zero {
+		return Binding{}, fmt.Errorf("%s.%s is not set", field.Schema.Table, pk.DBName)
+	}
+	return Binding{Table: field.Schema.Table, Column: field.DBName, RowID: fmt.Sprint(id)}, nil
+}
@@ -0,0 +1,170 @@
+package notification
+
+import (
+	"bytes"
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProductionHost = "https://api.push.apple.com"
+	apnsSandboxHost    = "https://api.sandbox.push.apple.com"
+
+	// Apple rejects provider tokens older than an hour and throttles ones
+	// refreshed more often than every 20 minutes
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsSender sends push notifications through Apple Push Notification service
+// using token based authentication. Requests go over HTTP/2, which net/http
+// negotiates automatically for TLS connections.
+type APNsSender struct {
+	client   *http.Client
+	host     string
+	keyID    string
+	teamID   string
+	bundleID string
+	key      *ecdsa.PrivateKey
+
+	mu       sync.Mutex
+	jwt      string
+	issuedAt time.Time
+}
+
+// NewAPNsSender loads the .p8 signing key from configuration
+func NewAPNsSender(cfg *config.PushConfig) (*APNsSender, error) {
+	raw, err := os.ReadFile(cfg.APNsKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read APNs key: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs key: %w", err)
+	}
+	if cfg.APNsKeyID == "" || cfg.APNsTeamID == "" || cfg.APNsBundleID == "" {
+		return nil, fmt.Errorf("APNS_KEY_ID, APNS_TEAM_ID and APNS_BUNDLE_ID are required")
+	}
+
+	host := apnsSandboxHost
+	if cfg.APNsProduction {
+		host = apnsProductionHost
+	}
+
+	return &APNsSender{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		host:     host,
+		keyID:    cfg.APNsKeyID,
+		teamID:   cfg.APNsTeamID,
+		bundleID: cfg.APNsBundleID,
+		key:      key,
+	}, nil
+}
+
+func (s *APNsSender) Provider() domainNotification.PushProvider {
+	return domainNotification.PushAPNs
+}
+
+func (s *APNsSender) Send(ctx context.Context, token string, msg *domainNotification.PushMessage) error {
+	providerToken, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+
+	aps := map[string]interface{}{
+		"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+		"sound": "default",
+	}
+	if msg.ThreadID != "" {
+		aps["thread-id"] = msg.ThreadID
+	}
+	if msg.Critical {
+		aps["interruption-level"] = "time-sensitive"
+	}
+	body := map[string]interface{}{"aps": aps}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			body[k] = v
+		}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.host+"/3/device/"+token, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", s.bundleID)
+	req.Header.Set("apns-push-type", "alert")
+	priority := "5"
+	if msg.Critical {
+		priority = "10"
+	}
+	req.Header.Set("apns-priority", priority)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&result)
+
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		result.Reason == "BadDeviceToken",
+		result.Reason == "Unregistered",
+		result.Reason == "DeviceTokenNotForTopic":
+		return fmt.Errorf("%w: %s", domainNotification.ErrPushTokenInvalid, result.Reason)
+	case result.Reason == "ExpiredProviderToken":
+		s.mu.Lock()
+		s.jwt = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("apns returned status %d: %s", resp.StatusCode, result.Reason)
+}
+
+func (s *APNsSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jwt != "" && time.Since(s.issuedAt) < apnsTokenLifetime {
+		return s.jwt, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.keyID
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign APNs token: %w", err)
+	}
+	s.jwt = signed
+	s.issuedAt = now
+	return signed, nil
+}
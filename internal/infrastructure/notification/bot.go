@@ -0,0 +1,161 @@
+package notification
+
+import (
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxChatTextLength keeps bot messages within the limits of every provider
+const maxChatTextLength = 2000
+
+// NewChatBots returns the messaging bots enabled in configuration
+func NewChatBots(cfg *config.ChatBotConfig) []domainNotification.ChatBot {
+	var bots []domainNotification.ChatBot
+	if cfg.TelegramBotToken != "" {
+		bots = append(bots, NewTelegramBot(cfg.TelegramBotToken, cfg.TelegramBotUsername, cfg.TelegramWebhookSecret))
+	}
+	if cfg.ZaloAccessToken != "" {
+		bots = append(bots, NewZaloBot(cfg.ZaloAppID, cfg.ZaloOAID, cfg.ZaloAccessToken, cfg.ZaloSecretKey))
+	}
+	return bots
+}
+
+// BotNotifier pushes notifications to the Telegram and Zalo chats users have
+// linked, recording every message for delivery receipts.
+type BotNotifier struct {
+	repo   domainNotification.ChatLinkRepository
+	bots   map[domainNotification.ChatProvider]domainNotification.ChatBot
+	appURL string
+}
+
+// NewBotNotifier creates a notifier delivering through bots
+func NewBotNotifier(repo domainNotification.ChatLinkRepository, bots []domainNotification.ChatBot, appURL string) *BotNotifier {
+	byProvider := make(map[domainNotification.ChatProvider]domainNotification.ChatBot, len(bots))
+	for _, b := range bots {
+		byProvider[b.Provider()] = b
+	}
+	return &BotNotifier{
+		repo:   repo,
+		bots:   byProvider,
+		appURL: strings.TrimRight(appURL, "/"),
+	}
+}
+
+func (n *BotNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	if msg.UserID == uuid.Nil || len(n.bots) == 0 {
+		return nil
+	}
+
+	links, err := n.repo.ListActiveLinks(ctx, msg.UserID)
+	if err != nil {
+		return err
+	}
+
+	text := formatChatText(msg, n.appURL)
+	var errs []error
+	for _, link := range links {
+		bot, ok := n.bots[link.Provider]
+		if !ok {
+			continue
+		}
+		if err := n.send(ctx, bot, link, msg.Event, text); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *BotNotifier) send(ctx context.Context, bot domainNotification.ChatBot, link *domainNotification.ChatLink, event, text string) error {
+	messageID, sendErr := bot.Send(ctx, link.ChatID, text)
+
+	delivery := &domainNotification.ChatDelivery{
+		LinkID:   link.ID,
+		UserID:   link.UserID,
+		Provider: link.Provider,
+		Event:    event,
+		Status:   domainNotification.ChatDeliverySent,
+		SentAt:   time.Now(),
+	}
+	if sendErr != nil {
+		message := sendErr.Error()
+		delivery.Status = domainNotification.ChatDeliveryFailed
+		delivery.Error = &message
+	} else if messageID != "" {
+		delivery.ProviderMessageID = &messageID
+	}
+
+	// Record with a fresh context so a timed out send is still visible
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := n.repo.CreateDelivery(recordCtx, delivery); err != nil {
+		logger.Warn("Failed to record chat delivery",
+			zap.String("user_id", link.UserID.String()),
+			zap.Error(err),
+		)
+	}
+
+	if errors.Is(sendErr, domainNotification.ErrChatUnreachable) {
+		if err := n.repo.DeactivateChat(recordCtx, link.Provider, link.ChatID); err != nil {
+			logger.Warn("Failed to deactivate unreachable chat", zap.Error(err))
+		}
+		logger.Info("Chat link deactivated, bot can no longer reach the user",
+			zap.String("user_id", link.UserID.String()),
+			zap.String("provider", string(link.Provider)),
+			zap.String("event", "chat_link_deactivated"),
+		)
+	}
+	return sendErr
+}
+
+// formatChatText renders a notification as a plain text chat message
+func formatChatText(msg *domainNotification.Message, appURL string) string {
+	var b strings.Builder
+	if msg.Severity == domainNotification.SeverityCritical {
+		b.WriteString("[URGENT] ")
+	}
+	b.WriteString(msg.Subject)
+	if msg.Body != "" {
+		b.WriteString("\n\n")
+		b.WriteString(msg.Body)
+	}
+
+	text := b.String()
+	link := absoluteLink(appURL, msg.Link)
+	if len(text)+len(link)+2 > maxChatTextLength {
+		text = truncateUTF8(text, maxChatTextLength-len(link)-5) + "..."
+	}
+	if link != "" {
+		text += "\n\n" + link
+	}
+	return text
+}
+
+// absoluteLink joins an application path onto the public app URL
+func absoluteLink(appURL, path string) string {
+	if path == "" || appURL == "" {
+		return ""
+	}
+	return strings.TrimRight(appURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+func truncateUTF8(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"bytes"
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailNotifier sends notifications as plain text email over SMTP
+type EmailNotifier struct {
+	cfg *config.SMTPConfig
+}
+
+// NewEmailNotifier creates a new SMTP email notifier
+func NewEmailNotifier(cfg *config.SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	if msg.Email == "" {
+		return nil
+	}
+
+	return n.send(ctx, []string{msg.Email}, n.buildMessage(msg))
+}
+
+// SendMail sends a plain text email with optional attachments
+func (n *EmailNotifier) SendMail(ctx context.Context, mail *domainNotification.Mail) error {
+	if len(mail.To) == 0 {
+		return nil
+	}
+	return n.send(ctx, mail.To, n.buildMail(mail))
+}
+
+func (n *EmailNotifier) send(ctx context.Context, to []string, message []byte) error {
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	var auth smtp.Auth
+	if n.cfg.User != "" {
+		auth = smtp.PlainAuth("", n.cfg.User, n.cfg.Password, n.cfg.Host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, n.cfg.From, to, message)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *EmailNotifier) buildMessage(msg *domainNotification.Message) []byte {
+	to := msg.Email
+	if msg.Name != "" {
+		to = fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", msg.Name), msg.Email)
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + n.cfg.From + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// buildMail renders a Mail as a plain text message, or as multipart/mixed
+// when it has attachments
+func (n *EmailNotifier) buildMail(mail *domainNotification.Mail) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + n.cfg.From + "\r\n")
+	b.WriteString("To: " + strings.Join(mail.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", mail.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	body := strings.ReplaceAll(mail.Body, "\n", "\r\n")
+	if len(mail.Attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+		b.WriteString("\r\n")
+		b.WriteString(body)
+		return []byte(b.String())
+	}
+
+	var parts bytes.Buffer
+	w := multipart.NewWriter(&parts)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "8bit")
+	if part, err := w.CreatePart(header); err == nil {
+		part.Write([]byte(body))
+	}
+
+	for _, a := range mail.Attachments {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", a.ContentType)
+		header.Set("Content-Transfer-Encoding", "base64")
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Name}))
+		part, err := w.CreatePart(header)
+		if err != nil {
+			continue
+		}
+		part.Write(wrapBase64(a.Data))
+	}
+	w.Close()
+
+	b.WriteString("Content-Type: multipart/mixed; boundary=\"" + w.Boundary() + "\"\r\n")
+	b.WriteString("\r\n")
+	b.Write(parts.Bytes())
+	return []byte(b.String())
+}
+
+// wrapBase64 encodes data as base64 in lines of 76 characters (RFC 2045)
+func wrapBase64(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var out bytes.Buffer
+	for len(encoded) > 76 {
+		out.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	out.WriteString(encoded)
+	return out.Bytes()
+}
@@ -0,0 +1,205 @@
+package notification
+
+import (
+	"bytes"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmSendURL  = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	googleToken = "https://oauth2.googleapis.com/token"
+)
+
+// FCMSender sends push notifications through the Firebase Cloud Messaging
+// HTTP v1 API, authenticating with a service account.
+type FCMSender struct {
+	client      *http.Client
+	projectID   string
+	clientEmail string
+	tokenURI    string
+	key         *rsa.PrivateKey
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMSender loads a Firebase service account JSON file
+func NewFCMSender(credentialsFile string) (*FCMSender, error) {
+	raw, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+	}
+
+	var account struct {
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("invalid FCM credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" {
+		return nil, fmt.Errorf("FCM credentials are missing project_id or client_email")
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM private key: %w", err)
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = googleToken
+	}
+
+	return &FCMSender{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		projectID:   account.ProjectID,
+		clientEmail: account.ClientEmail,
+		tokenURI:    account.TokenURI,
+		key:         key,
+	}, nil
+}
+
+func (s *FCMSender) Provider() domainNotification.PushProvider {
+	return domainNotification.PushFCM
+}
+
+func (s *FCMSender) Send(ctx context.Context, token string, msg *domainNotification.PushMessage) error {
+	accessToken, err := s.authorize(ctx)
+	if err != nil {
+		return err
+	}
+
+	priority := "NORMAL"
+	if msg.Critical {
+		priority = "HIGH"
+	}
+	message := map[string]interface{}{
+		"token":        token,
+		"notification": map[string]string{"title": msg.Title, "body": msg.Body},
+		"android": map[string]interface{}{
+			"priority": priority,
+			"notification": map[string]string{
+				"tag": msg.ThreadID,
+			},
+		},
+	}
+	if len(msg.Data) > 0 {
+		message["data"] = msg.Data
+	}
+	payload, err := json.Marshal(map[string]interface{}{"message": message})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmSendURL, s.projectID), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var result struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result)
+
+	for _, d := range result.Error.Details {
+		invalidToken := d.ErrorCode == "INVALID_ARGUMENT" && strings.Contains(result.Error.Message, "registration token")
+		if d.ErrorCode == "UNREGISTERED" || invalidToken {
+			return fmt.Errorf("%w: %s", domainNotification.ErrPushTokenInvalid, d.ErrorCode)
+		}
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		s.mu.Lock()
+		s.accessToken = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("fcm returned status %d: %s", resp.StatusCode, result.Error.Message)
+}
+
+// authorize returns a cached OAuth access token, exchanging a signed service
+// account assertion for a new one shortly before it expires
+func (s *FCMSender) authorize(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && time.Now().Before(s.expiresAt.Add(-time.Minute)) {
+		return s.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.clientEmail,
+		"scope": fcmScope,
+		"aud":   s.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign FCM assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fcm token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fcm token request returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode fcm token response: %w", err)
+	}
+
+	s.accessToken = result.AccessToken
+	s.expiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return s.accessToken, nil
+}
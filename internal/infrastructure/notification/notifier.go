@@ -0,0 +1,155 @@
+package notification
+
+import (
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LogNotifier only logs notifications; used when no delivery channel is configured
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	logger.Info("Notification",
+		zap.String("user_id", msg.UserID.String()),
+		zap.String("notification_event", msg.Event),
+		zap.String("subject", msg.Subject),
+		zap.Bool("sandbox", msg.Sandbox),
+		zap.String("event", "notification_logged"),
+	)
+	return nil
+}
+
+// MultiNotifier fans a notification out to several channels
+type MultiNotifier []domainNotification.Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SandboxNotifier keeps sandbox traffic away from real endpoints: messages
+// raised by sandbox data go to the sandbox channel only.
+type SandboxNotifier struct {
+	Live    domainNotification.Notifier
+	Sandbox domainNotification.Notifier
+}
+
+func (n SandboxNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	if msg.Sandbox {
+		return n.Sandbox.Notify(ctx, msg)
+	}
+	return n.Live.Notify(ctx, msg)
+}
+
+// AsyncNotifier queues notifications and delivers them in the background so
+// request handlers never wait on SMTP or third party APIs.
+type AsyncNotifier struct {
+	next    domainNotification.Notifier
+	queue   chan *domainNotification.Message
+	timeout time.Duration
+	wg      sync.WaitGroup
+}
+
+// NewAsyncNotifier starts workers delivering through next
+func NewAsyncNotifier(next domainNotification.Notifier, queueSize, workers int) *AsyncNotifier {
+	if queueSize <= 0 {
+		queueSize = 256
+	}
+	if workers <= 0 {
+		workers = 2
+	}
+
+	a := &AsyncNotifier{
+		next:    next,
+		queue:   make(chan *domainNotification.Message, queueSize),
+		timeout: 30 * time.Second,
+	}
+	for i := 0; i < workers; i++ {
+		a.wg.Add(1)
+		go a.worker()
+	}
+	return a
+}
+
+// Notify enqueues msg; it drops the notification when the queue is full.
+func (a *AsyncNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	select {
+	case a.queue <- msg:
+		return nil
+	default:
+		logger.Warn("Notification queue full, dropping notification",
+			zap.String("user_id", msg.UserID.String()),
+			zap.String("notification_event", msg.Event),
+			zap.String("event", "notification_dropped"),
+		)
+		return nil
+	}
+}
+
+// Close stops accepting notifications and waits for queued ones to be sent
+func (a *AsyncNotifier) Close() {
+	close(a.queue)
+	a.wg.Wait()
+}
+
+func (a *AsyncNotifier) worker() {
+	defer a.wg.Done()
+	for msg := range a.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+		if err := a.next.Notify(ctx, msg); err != nil {
+			logger.Error("Failed to deliver notification",
+				zap.String("user_id", msg.UserID.String()),
+				zap.String("notification_event", msg.Event),
+				zap.Error(err),
+				zap.String("event", "notification_failed"),
+			)
+		}
+		cancel()
+	}
+}
+
+// New builds the notifier chain from configuration: email when SMTP is
+// configured, chat webhooks, messaging bots and mobile push, always logged,
+// delivered asynchronously. Channel wording comes from the admin-managed
+// templates when one is active for the event.
+func New(cfg *config.Config, templates domainNotification.TemplateRepository, webhooks domainNotification.WebhookRepository, webhookEvents domainNotification.WebhookEventRepository, chatLinks domainNotification.ChatLinkRepository, push domainNotification.PushRepository) *AsyncNotifier {
+	lang := cfg.Notification.DefaultLanguage
+
+	channels := MultiNotifier{LogNotifier{}}
+	if cfg.SMTP.Host != "" {
+		channels = append(channels, NewTemplatedNotifier(domainNotification.ChannelEmail, NewEmailNotifier(&cfg.SMTP), templates, lang))
+	}
+
+	sender := NewWebhookDelivery(&cfg.Notification, webhooks, webhookEvents)
+	chat := MultiNotifier{
+		NewWebhookNotifier(webhooks, sender),
+		NewBotNotifier(chatLinks, NewChatBots(&cfg.ChatBot), cfg.Notification.AppURL),
+	}
+	channels = append(channels, NewTemplatedNotifier(domainNotification.ChannelChat, chat, templates, lang))
+
+	if senders := NewPushSenders(&cfg.Push); len(senders) > 0 {
+		channels = append(channels, NewTemplatedNotifier(domainNotification.ChannelPush, NewPushNotifier(push, senders), templates, lang))
+	}
+
+	return NewAsyncNotifier(SandboxNotifier{Live: channels, Sandbox: LogNotifier{}}, 256, 2)
+}
+
+// NewMailer returns the SMTP mailer, or nil when no mail server is configured
+func NewMailer(cfg *config.Config) domainNotification.Mailer {
+	if cfg.SMTP.Host == "" {
+		return nil
+	}
+	return NewEmailNotifier(&cfg.SMTP)
+}
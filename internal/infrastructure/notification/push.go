@@ -0,0 +1,131 @@
+package notification
+
+import (
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// NewPushSenders returns the push services enabled in configuration. A
+// service whose credentials cannot be loaded is logged and left disabled.
+func NewPushSenders(cfg *config.PushConfig) []domainNotification.PushSender {
+	var senders []domainNotification.PushSender
+	if cfg.FCMCredentialsFile != "" {
+		if fcm, err := NewFCMSender(cfg.FCMCredentialsFile); err != nil {
+			logger.Error("FCM disabled", zap.Error(err))
+		} else {
+			senders = append(senders, fcm)
+		}
+	}
+	if cfg.APNsKeyFile != "" {
+		if apns, err := NewAPNsSender(cfg); err != nil {
+			logger.Error("APNs disabled", zap.Error(err))
+		} else {
+			senders = append(senders, apns)
+		}
+	}
+	return senders
+}
+
+// PushNotifier delivers notifications to the registered mobile devices of
+// their recipient. Operational alerts that carry a shipment_id go to the
+// users subscribed to that shipment's topic. Tokens the push service
+// reports as invalid are pruned.
+type PushNotifier struct {
+	repo    domainNotification.PushRepository
+	senders map[domainNotification.PushProvider]domainNotification.PushSender
+}
+
+// NewPushNotifier creates a push notifier for the enabled senders
+func NewPushNotifier(repo domainNotification.PushRepository, senders []domainNotification.PushSender) *PushNotifier {
+	byProvider := make(map[domainNotification.PushProvider]domainNotification.PushSender, len(senders))
+	for _, s := range senders {
+		byProvider[s.Provider()] = s
+	}
+	return &PushNotifier{repo: repo, senders: byProvider}
+}
+
+func (n *PushNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	if len(n.senders) == 0 {
+		return nil
+	}
+
+	recipients, topic, err := n.recipients(ctx, msg)
+	if err != nil || len(recipients) == 0 {
+		return err
+	}
+
+	tokens, err := n.repo.ListTokensForUsers(ctx, recipients)
+	if err != nil {
+		return err
+	}
+
+	push := &domainNotification.PushMessage{
+		Title:    msg.Subject,
+		Body:     msg.Body,
+		Data:     pushData(msg),
+		ThreadID: topic,
+		Critical: msg.Severity == domainNotification.SeverityCritical,
+	}
+
+	var errs []error
+	for _, t := range tokens {
+		sender, ok := n.senders[t.Provider]
+		if !ok {
+			continue
+		}
+
+		err := sender.Send(ctx, t.Token, push)
+		if errors.Is(err, domainNotification.ErrPushTokenInvalid) {
+			if delErr := n.repo.DeleteByValue(ctx, t.Provider, t.Token); delErr != nil {
+				logger.Warn("Failed to prune invalid push token", zap.Error(delErr))
+			}
+			logger.Info("Invalid push token pruned",
+				zap.String("user_id", t.UserID.String()),
+				zap.String("provider", string(t.Provider)),
+				zap.String("event", "push_token_pruned"),
+			)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recipients resolves who receives msg and the topic it belongs to
+func (n *PushNotifier) recipients(ctx context.Context, msg *domainNotification.Message) ([]uuid.UUID, string, error) {
+	var topic string
+	if id, err := uuid.Parse(msg.Data["shipment_id"]); err == nil {
+		topic = domainNotification.ShipmentTopic(id)
+	}
+
+	if msg.UserID != uuid.Nil {
+		return []uuid.UUID{msg.UserID}, topic, nil
+	}
+	if topic == "" {
+		return nil, "", nil
+	}
+
+	subscribers, err := n.repo.ListSubscribers(ctx, topic)
+	return subscribers, topic, err
+}
+
+// pushData is the data payload the mobile app uses to route a tap
+func pushData(msg *domainNotification.Message) map[string]string {
+	data := make(map[string]string, len(msg.Data)+2)
+	for k, v := range msg.Data {
+		data[k] = v
+	}
+	data["event"] = msg.Event
+	if msg.Link != "" {
+		data["link"] = msg.Link
+	}
+	return data
+}
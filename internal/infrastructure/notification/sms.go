@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"bytes"
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SMSGateway sends text messages through an HTTP SMS gateway. The gateway
+// receives a JSON body {"from", "to", "text"} with the API key as a bearer
+// token and answers 2xx on acceptance.
+type SMSGateway struct {
+	client   *http.Client
+	url      string
+	apiKey   string
+	senderID string
+}
+
+// NewSMSSender creates an SMS sender from configuration. Without a gateway
+// URL messages are only logged, which keeps development setups working.
+func NewSMSSender(cfg *config.SMSConfig) domainNotification.SMSSender {
+	if cfg.GatewayURL == "" {
+		return logSMSSender{}
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &SMSGateway{
+		client:   &http.Client{Timeout: timeout},
+		url:      cfg.GatewayURL,
+		apiKey:   cfg.APIKey,
+		senderID: cfg.SenderID,
+	}
+}
+
+func (g *SMSGateway) SendSMS(ctx context.Context, phoneNumber, text string) error {
+	payload, err := json.Marshal(map[string]string{
+		"from": g.senderID,
+		"to":   phoneNumber,
+		"text": text,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build sms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sms gateway returned status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// logSMSSender logs messages instead of sending them
+type logSMSSender struct{}
+
+func (logSMSSender) SendSMS(_ context.Context, phoneNumber, text string) error {
+	logger.Debug("SMS gateway not configured, message not sent",
+		zap.String("to", phoneNumber),
+		zap.String("text", text),
+		zap.String("event", "sms_not_sent"),
+	)
+	return nil
+}
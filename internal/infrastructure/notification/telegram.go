@@ -0,0 +1,147 @@
+package notification
+
+import (
+	"bytes"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const telegramAPIBase = "https://api.telegram.org"
+
+// TelegramBot sends messages through the Telegram Bot API
+type TelegramBot struct {
+	client        *http.Client
+	token         string
+	username      string
+	webhookSecret string
+}
+
+// NewTelegramBot creates a Telegram bot client
+func NewTelegramBot(token, username, webhookSecret string) *TelegramBot {
+	return &TelegramBot{
+		client:        &http.Client{Timeout: 10 * time.Second},
+		token:         token,
+		username:      username,
+		webhookSecret: webhookSecret,
+	}
+}
+
+func (b *TelegramBot) Provider() domainNotification.ChatProvider {
+	return domainNotification.ChatTelegram
+}
+
+func (b *TelegramBot) Send(ctx context.Context, chatID, text string) (string, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, telegramAPIBase+"/bot"+b.token+"/sendMessage", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build telegram request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		// The request URL embeds the bot token, never surface it
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("telegram request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		ErrorCode   int    `json:"error_code"`
+		Description string `json:"description"`
+		Result      struct {
+			MessageID int64 `json:"message_id"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode telegram response (status %d): %w", resp.StatusCode, err)
+	}
+	if !result.OK {
+		// 403: blocked by the user or kicked; 400 "chat not found": chat deleted
+		if result.ErrorCode == http.StatusForbidden || (result.ErrorCode == http.StatusBadRequest && result.Description == "Bad Request: chat not found") {
+			return "", fmt.Errorf("%w: %s", domainNotification.ErrChatUnreachable, result.Description)
+		}
+		return "", fmt.Errorf("telegram error %d: %s", result.ErrorCode, result.Description)
+	}
+
+	return strconv.FormatInt(result.Result.MessageID, 10), nil
+}
+
+func (b *TelegramBot) DeepLink(code string) string {
+	if b.username == "" {
+		return ""
+	}
+	return "https://t.me/" + b.username + "?start=" + url.QueryEscape(code)
+}
+
+// VerifyWebhook compares the secret token Telegram echoes in the
+// X-Telegram-Bot-Api-Secret-Token header
+func (b *TelegramBot) VerifyWebhook(signature string, body []byte) bool {
+	if b.webhookSecret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(signature), []byte(b.webhookSecret)) == 1
+}
+
+func (b *TelegramBot) ParseWebhook(body []byte) ([]domainNotification.ChatEvent, error) {
+	var update struct {
+		Message *struct {
+			Date int64  `json:"date"`
+			Text string `json:"text"`
+			Chat struct {
+				ID int64 `json:"id"`
+			} `json:"chat"`
+		} `json:"message"`
+		MyChatMember *struct {
+			Date int64 `json:"date"`
+			Chat struct {
+				ID int64 `json:"id"`
+			} `json:"chat"`
+			NewChatMember struct {
+				Status string `json:"status"`
+			} `json:"new_chat_member"`
+		} `json:"my_chat_member"`
+	}
+	if err := json.Unmarshal(body, &update); err != nil {
+		return nil, fmt.Errorf("invalid telegram update: %w", err)
+	}
+
+	var events []domainNotification.ChatEvent
+	if m := update.Message; m != nil && m.Text != "" {
+		events = append(events, domainNotification.ChatEvent{
+			Kind:   domainNotification.ChatEventMessage,
+			ChatID: strconv.FormatInt(m.Chat.ID, 10),
+			Text:   m.Text,
+			At:     time.Unix(m.Date, 0),
+		})
+	}
+	if m := update.MyChatMember; m != nil && (m.NewChatMember.Status == "kicked" || m.NewChatMember.Status == "left") {
+		events = append(events, domainNotification.ChatEvent{
+			Kind:   domainNotification.ChatEventBlocked,
+			ChatID: strconv.FormatInt(m.Chat.ID, 10),
+			At:     time.Unix(m.Date, 0),
+		})
+	}
+	return events, nil
+}
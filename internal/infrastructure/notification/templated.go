@@ -0,0 +1,69 @@
+package notification
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// TemplatedNotifier rewrites a message with the active template for its
+// event before handing it to a channel. Messages without a template are sent
+// with their built-in wording.
+type TemplatedNotifier struct {
+	channel         domainNotification.Channel
+	next            domainNotification.Notifier
+	templates       domainNotification.TemplateRepository
+	defaultLanguage string
+}
+
+// NewTemplatedNotifier wraps the notifier of a single channel
+func NewTemplatedNotifier(channel domainNotification.Channel, next domainNotification.Notifier, templates domainNotification.TemplateRepository, defaultLanguage string) *TemplatedNotifier {
+	return &TemplatedNotifier{
+		channel:         channel,
+		next:            next,
+		templates:       templates,
+		defaultLanguage: domainNotification.NormalizeLanguage(defaultLanguage),
+	}
+}
+
+func (n *TemplatedNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	tmpl, err := n.lookup(ctx, msg)
+	if err != nil {
+		if !errors.Is(err, domainNotification.ErrTemplateNotFound) {
+			logger.Warn("Failed to load notification template, using built-in wording",
+				zap.String("notification_event", msg.Event),
+				zap.String("channel", string(n.channel)),
+				zap.Error(err),
+			)
+		}
+		return n.next.Notify(ctx, msg)
+	}
+
+	subject, body, missing := tmpl.Render(domainNotification.MessageVariables(msg))
+	if len(missing) > 0 {
+		logger.Warn("Notification template references unknown variables",
+			zap.String("template_id", tmpl.ID.String()),
+			zap.Strings("variables", missing),
+		)
+	}
+
+	rendered := *msg
+	rendered.Subject = subject
+	rendered.Body = body
+	return n.next.Notify(ctx, &rendered)
+}
+
+// lookup finds the template in the recipient's language, falling back to the default language
+func (n *TemplatedNotifier) lookup(ctx context.Context, msg *domainNotification.Message) (*domainNotification.Template, error) {
+	lang := domainNotification.NormalizeLanguage(msg.Language)
+	if lang != "" && lang != n.defaultLanguage {
+		tmpl, err := n.templates.GetActive(ctx, msg.Event, n.channel, lang)
+		if err == nil || !errors.Is(err, domainNotification.ErrTemplateNotFound) {
+			return tmpl, err
+		}
+	}
+	return n.templates.GetActive(ctx, msg.Event, n.channel, n.defaultLanguage)
+}
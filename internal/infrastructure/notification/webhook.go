@@ -0,0 +1,415 @@
+package notification
+
+import (
+	"bytes"
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/pkg/utils"
+	"cargo-tracker/pkg/webhooksig"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxRetryAfter = 30 * time.Second
+
+	// webhookFailureAlertThreshold is the number of consecutive failures after
+	// which a failing webhook is logged as an error instead of a warning
+	webhookFailureAlertThreshold = 5
+)
+
+// errNonPublicAddress is returned when a webhook host resolves to an internal address
+var errNonPublicAddress = errors.New("webhook host resolves to a non-public address")
+
+// WebhookSender posts notifications to Slack and Microsoft Teams incoming
+// webhooks and to generic endpoints, retrying transient failures with
+// exponential backoff. Every request is signed, see package webhooksig.
+type WebhookSender struct {
+	client      *http.Client
+	appURL      string
+	maxAttempts int
+}
+
+// NewWebhookSender creates a webhook sender from the notification configuration
+func NewWebhookSender(cfg *config.NotificationConfig) *WebhookSender {
+	timeout := cfg.WebhookTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	attempts := cfg.WebhookMaxAttempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+
+	// Refuse to connect to internal addresses, whatever the host resolved to
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !utils.IsPublicIP(ip) {
+				return errNonPublicAddress
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Connect directly so the address check applies to the webhook host itself
+	transport.Proxy = nil
+
+	return &WebhookSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// Redirects could lead to a host the URL validation never saw
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		appURL:      strings.TrimRight(cfg.AppURL, "/"),
+		maxAttempts: attempts,
+	}
+}
+
+// Deliver posts msg to hook. The event ID stays the same across retries, and
+// replays of archived events reuse their original ID, so receivers can drop
+// duplicates; each attempt is signed with a fresh timestamp. The payload
+// only holds what the webhook's payload filters let through.
+func (s *WebhookSender) Deliver(ctx context.Context, hook *domainNotification.Webhook, msg *domainNotification.Message) error {
+	eventID := msg.EventID
+	if eventID == uuid.Nil {
+		eventID = uuid.New()
+	}
+	msg = hook.FilterPayload(msg)
+	payload, err := json.Marshal(s.buildPayload(hook.Kind, eventID, msg))
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := s.post(ctx, hook, eventID, msg.Replay, payload)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= s.maxAttempts {
+			return err
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		}
+		backoff *= 2
+	}
+}
+
+// permanentError marks failures that will not succeed on retry
+type permanentError struct {
+	reason string
+}
+
+func (e *permanentError) Error() string {
+	return e.reason
+}
+
+func (s *WebhookSender) post(ctx context.Context, hook *domainNotification.Webhook, eventID uuid.UUID, replay bool, payload []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, &permanentError{reason: "invalid webhook URL"}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	now := time.Now()
+	req.Header.Set(webhooksig.HeaderEventID, eventID.String())
+	req.Header.Set(webhooksig.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(webhooksig.HeaderSignature, webhooksig.SignatureHeader(hook.SigningSecrets(now), now, payload))
+	if replay {
+		req.Header.Set(webhooksig.HeaderReplay, "true")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Never surface the URL itself, it carries the webhook secret
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		if errors.Is(err, errNonPublicAddress) {
+			return 0, &permanentError{reason: errNonPublicAddress.Error()}
+		}
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return 0, &permanentError{reason: fmt.Sprintf("webhook redirected with status %d; redirects are not followed", resp.StatusCode)}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("webhook rate limited")
+	case resp.StatusCode >= 500:
+		return 0, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	default:
+		return 0, &permanentError{reason: fmt.Sprintf("webhook rejected the request with status %d", resp.StatusCode)}
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	wait := time.Duration(seconds) * time.Second
+	if wait > maxRetryAfter {
+		wait = maxRetryAfter
+	}
+	return wait
+}
+
+// genericEvent is the JSON body posted to generic webhooks
+type genericEvent struct {
+	ID        uuid.UUID         `json:"id"`
+	Event     string            `json:"event"`
+	Severity  string            `json:"severity"`
+	Subject   string            `json:"subject"`
+	Body      string            `json:"body"`
+	Link      string            `json:"link,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	// Set when an archived event is delivered again
+	Replay bool `json:"replay,omitempty"`
+}
+
+func (s *WebhookSender) buildPayload(kind domainNotification.WebhookKind, eventID uuid.UUID, msg *domainNotification.Message) interface{} {
+	link := absoluteLink(s.appURL, msg.Link)
+	critical := msg.Severity == domainNotification.SeverityCritical
+
+	if kind == domainNotification.WebhookGeneric {
+		createdAt := msg.OccurredAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		return genericEvent{
+			ID:        eventID,
+			Event:     msg.Event,
+			Severity:  string(msg.Severity),
+			Subject:   msg.Subject,
+			Body:      msg.Body,
+			Link:      link,
+			Data:      msg.Data,
+			CreatedAt: createdAt.UTC(),
+			Replay:    msg.Replay,
+		}
+	}
+
+	if kind == domainNotification.WebhookTeams {
+		color := "0076D7"
+		if critical {
+			color = "D70000"
+		}
+		card := map[string]interface{}{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    msg.Subject,
+			"themeColor": color,
+			"title":      msg.Subject,
+			"text":       strings.ReplaceAll(msg.Body, "\n", "<br>"),
+		}
+		if link != "" {
+			card["potentialAction"] = []map[string]interface{}{{
+				"@type":   "OpenUri",
+				"name":    "Open in Cargo Tracker",
+				"targets": []map[string]string{{"os": "default", "uri": link}},
+			}}
+		}
+		return card
+	}
+
+	title := msg.Subject
+	if critical {
+		title = ":rotating_light: " + title
+	}
+	blocks := []map[string]interface{}{
+		{"type": "header", "text": map[string]string{"type": "plain_text", "text": title}},
+		{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": msg.Body}},
+	}
+	if link != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []map[string]interface{}{{
+				"type": "button",
+				"text": map[string]string{"type": "plain_text", "text": "Open in Cargo Tracker"},
+				"url":  link,
+			}},
+		})
+	}
+	return map[string]interface{}{
+		"text":   title + "\n" + msg.Body,
+		"blocks": blocks,
+	}
+}
+
+// TrackedWebhookSender records the delivery health of every webhook it posts
+// to, so failing integrations are visible to their owners.
+type TrackedWebhookSender struct {
+	repo domainNotification.WebhookRepository
+	next domainNotification.WebhookSender
+}
+
+// NewTrackedWebhookSender wraps next with delivery tracking
+func NewTrackedWebhookSender(repo domainNotification.WebhookRepository, next domainNotification.WebhookSender) *TrackedWebhookSender {
+	return &TrackedWebhookSender{repo: repo, next: next}
+}
+
+func (s *TrackedWebhookSender) Deliver(ctx context.Context, hook *domainNotification.Webhook, msg *domainNotification.Message) error {
+	deliveryErr := s.next.Deliver(ctx, hook, msg)
+
+	// Record with a fresh context so a timed out delivery is still visible
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.repo.RecordDelivery(recordCtx, hook.ID, time.Now(), deliveryErr); err != nil {
+		logger.Warn("Failed to record webhook delivery",
+			zap.String("webhook_id", hook.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	if deliveryErr == nil {
+		return nil
+	}
+
+	fields := []zap.Field{
+		zap.String("webhook_id", hook.ID.String()),
+		zap.String("owner_id", hook.OwnerID.String()),
+		zap.String("kind", string(hook.Kind)),
+		zap.String("notification_event", msg.Event),
+		zap.Int("consecutive_failures", hook.ConsecutiveFailures+1),
+		zap.Error(deliveryErr),
+	}
+	if hook.ConsecutiveFailures+1 >= webhookFailureAlertThreshold {
+		logger.Error("Webhook keeps failing", append(fields, zap.String("event", "webhook_failing"))...)
+	} else {
+		logger.Warn("Webhook delivery failed", append(fields, zap.String("event", "webhook_delivery_failed"))...)
+	}
+	return fmt.Errorf("webhook %s: %w", hook.ID, deliveryErr)
+}
+
+// ArchivingWebhookSender archives every event before delivering it, so
+// owners can replay what their endpoint missed. Replays are delivered
+// without being archived again.
+type ArchivingWebhookSender struct {
+	events domainNotification.WebhookEventRepository
+	next   domainNotification.WebhookSender
+}
+
+// NewArchivingWebhookSender wraps next with the event archive
+func NewArchivingWebhookSender(events domainNotification.WebhookEventRepository, next domainNotification.WebhookSender) *ArchivingWebhookSender {
+	return &ArchivingWebhookSender{events: events, next: next}
+}
+
+func (s *ArchivingWebhookSender) Deliver(ctx context.Context, hook *domainNotification.Webhook, msg *domainNotification.Message) error {
+	if msg.Replay {
+		return s.next.Deliver(ctx, hook, msg)
+	}
+
+	// Each webhook gets its own copy of the event, with its own ID
+	archived := *msg
+	archived.EventID = uuid.New()
+	archived.OccurredAt = time.Now()
+
+	event := &domainNotification.WebhookEvent{
+		ID:        archived.EventID,
+		WebhookID: hook.ID,
+		OwnerID:   hook.OwnerID,
+		Event:     msg.Event,
+		Severity:  msg.Severity,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+		Link:      msg.Link,
+		Data:      msg.Data,
+		CreatedAt: archived.OccurredAt,
+	}
+	if err := s.events.Create(ctx, event); err != nil {
+		// Delivering matters more than being able to replay
+		logger.Warn("Failed to archive webhook event",
+			zap.String("webhook_id", hook.ID.String()),
+			zap.Error(err),
+		)
+		return s.next.Deliver(ctx, hook, &archived)
+	}
+
+	deliveryErr := s.next.Deliver(ctx, hook, &archived)
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.events.RecordDelivery(recordCtx, event.ID, deliveryErr); err != nil {
+		logger.Warn("Failed to record webhook event delivery",
+			zap.String("webhook_id", hook.ID.String()),
+			zap.Error(err),
+		)
+	}
+	return deliveryErr
+}
+
+// NewWebhookDelivery builds the webhook sender used for notifications:
+// signed posts with retries, delivery health tracking and the event archive
+func NewWebhookDelivery(cfg *config.NotificationConfig, webhooks domainNotification.WebhookRepository, events domainNotification.WebhookEventRepository) domainNotification.WebhookSender {
+	return NewArchivingWebhookSender(events, NewTrackedWebhookSender(webhooks, NewWebhookSender(cfg)))
+}
+
+// WebhookNotifier routes notifications to the chat webhooks of their
+// recipient, or to platform webhooks for operational alerts.
+type WebhookNotifier struct {
+	repo   domainNotification.WebhookRepository
+	sender domainNotification.WebhookSender
+}
+
+// NewWebhookNotifier creates a notifier delivering through sender
+func NewWebhookNotifier(repo domainNotification.WebhookRepository, sender domainNotification.WebhookSender) *WebhookNotifier {
+	return &WebhookNotifier{repo: repo, sender: sender}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	hooks, err := n.repo.ListForRecipient(ctx, msg.UserID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		if !hook.Accepts(msg) {
+			continue
+		}
+		if err := n.sender.Deliver(ctx, hook, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
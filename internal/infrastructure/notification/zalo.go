@@ -0,0 +1,178 @@
+package notification
+
+import (
+	"bytes"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	zaloMessageURL = "https://openapi.zalo.me/v3.0/oa/message/cs"
+
+	// zaloErrNotFollower is returned when the user does not follow the OA
+	zaloErrNotFollower = -213
+)
+
+// ZaloBot sends messages through a Zalo Official Account
+type ZaloBot struct {
+	client      *http.Client
+	appID       string
+	oaID        string
+	accessToken string
+	secretKey   string
+}
+
+// NewZaloBot creates a Zalo Official Account client
+func NewZaloBot(appID, oaID, accessToken, secretKey string) *ZaloBot {
+	return &ZaloBot{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		appID:       appID,
+		oaID:        oaID,
+		accessToken: accessToken,
+		secretKey:   secretKey,
+	}
+}
+
+func (b *ZaloBot) Provider() domainNotification.ChatProvider {
+	return domainNotification.ChatZalo
+}
+
+func (b *ZaloBot) Send(ctx context.Context, chatID, text string) (string, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"recipient": map[string]string{"user_id": chatID},
+		"message":   map[string]string{"text": text},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, zaloMessageURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build zalo request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("access_token", b.accessToken)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("zalo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+		Data    struct {
+			MessageID string `json:"message_id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode zalo response (status %d): %w", resp.StatusCode, err)
+	}
+	if result.Error != 0 {
+		if result.Error == zaloErrNotFollower {
+			return "", fmt.Errorf("%w: %s", domainNotification.ErrChatUnreachable, result.Message)
+		}
+		return "", fmt.Errorf("zalo error %d: %s", result.Error, result.Message)
+	}
+
+	return result.Data.MessageID, nil
+}
+
+// DeepLink opens the Official Account; Zalo cannot prefill the code, the
+// user sends it as a message.
+func (b *ZaloBot) DeepLink(code string) string {
+	if b.oaID == "" {
+		return ""
+	}
+	return "https://zalo.me/" + b.oaID
+}
+
+// VerifyWebhook checks the X-ZEvent-Signature header, which is
+// "mac=" + sha256(app_id + body + timestamp + OA secret key)
+func (b *ZaloBot) VerifyWebhook(signature string, body []byte) bool {
+	if b.secretKey == "" {
+		return false
+	}
+
+	var event struct {
+		Timestamp json.RawMessage `json:"timestamp"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return false
+	}
+	timestamp := strings.Trim(string(event.Timestamp), `"`)
+
+	sum := sha256.Sum256([]byte(b.appID + string(body) + timestamp + b.secretKey))
+	expected := "mac=" + hex.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(signature), []byte(expected)) == 1
+}
+
+func (b *ZaloBot) ParseWebhook(body []byte) ([]domainNotification.ChatEvent, error) {
+	var event struct {
+		EventName string          `json:"event_name"`
+		Timestamp json.RawMessage `json:"timestamp"`
+		Sender    struct {
+			ID string `json:"id"`
+		} `json:"sender"`
+		Recipient struct {
+			ID string `json:"id"`
+		} `json:"recipient"`
+		Follower struct {
+			ID string `json:"id"`
+		} `json:"follower"`
+		Message struct {
+			Text   string   `json:"text"`
+			MsgIDs []string `json:"msg_ids"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid zalo event: %w", err)
+	}
+
+	at := time.Now()
+	if ms, err := strconv.ParseInt(strings.Trim(string(event.Timestamp), `"`), 10, 64); err == nil {
+		at = time.UnixMilli(ms)
+	}
+
+	switch event.EventName {
+	case "user_send_text":
+		return []domainNotification.ChatEvent{{
+			Kind:   domainNotification.ChatEventMessage,
+			ChatID: event.Sender.ID,
+			Text:   event.Message.Text,
+			At:     at,
+		}}, nil
+	case "user_received_message":
+		return []domainNotification.ChatEvent{{
+			Kind:       domainNotification.ChatEventDelivered,
+			ChatID:     event.Recipient.ID,
+			MessageIDs: event.Message.MsgIDs,
+			At:         at,
+		}}, nil
+	case "user_seen_message":
+		return []domainNotification.ChatEvent{{
+			Kind:       domainNotification.ChatEventSeen,
+			ChatID:     event.Recipient.ID,
+			MessageIDs: event.Message.MsgIDs,
+			At:         at,
+		}}, nil
+	case "unfollow":
+		return []domainNotification.ChatEvent{{
+			Kind:   domainNotification.ChatEventBlocked,
+			ChatID: event.Follower.ID,
+			At:     at,
+		}}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ReferencePrefix marks a configuration value that must be fetched from a
+// secrets backend, e.g. secret://vault/secret/data/cargo-tracker#jwt_secret
+// or secret://aws/prod/cargo-tracker/db#password.
+const ReferencePrefix = "secret://"
+
+var (
+	ErrUnknownProvider = errors.New("unknown secrets provider")
+	ErrSecretNotFound  = errors.New("secret not found")
+	ErrInvalidRef      = errors.New("invalid secret reference")
+)
+
+// Provider fetches secrets from an external secrets backend.
+type Provider interface {
+	// Name is the provider segment used in secret:// references.
+	Name() string
+	// Fetch returns the key/value pairs stored at path. Backends holding a
+	// plain string store it under the empty key.
+	Fetch(ctx context.Context, path string) (map[string]string, error)
+}
+
+// Reference is a parsed secret:// URI.
+type Reference struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+// IsReference reports whether value is a secret:// reference.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, ReferencePrefix)
+}
+
+// ParseReference parses a secret://<provider>/<path>[#key] URI.
+func ParseReference(value string) (*Reference, error) {
+	if !IsReference(value) {
+		return nil, fmt.Errorf("%w: missing %s prefix", ErrInvalidRef, ReferencePrefix)
+	}
+
+	rest := strings.TrimPrefix(value, ReferencePrefix)
+	key := ""
+	if idx := strings.LastIndex(rest, "#"); idx >= 0 {
+		key = rest[idx+1:]
+		rest = rest[:idx]
+	}
+
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("%w: expected secret://<provider>/<path>[#key]", ErrInvalidRef)
+	}
+
+	return &Reference{
+		Provider: parts[0],
+		Path:     parts[1],
+		Key:      key,
+	}, nil
+}
+
+func (r *Reference) String() string {
+	s := ReferencePrefix + r.Provider + "/" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// pick selects the requested key from a fetched secret. When no key is given
+// the secret must hold exactly one value.
+func (r *Reference) pick(values map[string]string) (string, error) {
+	if r.Key != "" {
+		v, ok := values[r.Key]
+		if !ok {
+			return "", fmt.Errorf("%w: key %q at %s", ErrSecretNotFound, r.Key, r.Path)
+		}
+		return v, nil
+	}
+
+	if v, ok := values[""]; ok {
+		return v, nil
+	}
+	if len(values) == 1 {
+		for _, v := range values {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s holds %d values, specify one with #key", ErrInvalidRef, r.Path, len(values))
+}
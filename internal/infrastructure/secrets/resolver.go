@@ -0,0 +1,121 @@
+package secrets
+
+import (
+	"cargo-tracker/internal/config"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Resolver resolves secret:// references against the registered providers.
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// NewResolver creates a resolver backed by the given providers. Nil providers
+// are skipped so optional backends can be passed unconditionally.
+func NewResolver(providers ...Provider) *Resolver {
+	r := &Resolver{providers: make(map[string]Provider)}
+	for _, p := range providers {
+		if p != nil {
+			r.providers[p.Name()] = p
+		}
+	}
+	return r
+}
+
+// NewResolverFromConfig registers the secrets backends that are configured.
+func NewResolverFromConfig(cfg *config.SecretsConfig) *Resolver {
+	var providers []Provider
+	if cfg.VaultAddress != "" {
+		providers = append(providers, NewVaultProvider(cfg.VaultAddress, cfg.VaultToken, cfg.VaultNamespace))
+	}
+	if cfg.AWSRegion != "" {
+		providers = append(providers, NewAWSSecretsManagerProvider(cfg.AWSRegion, AWSCredentials{
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		}))
+	}
+	return NewResolver(providers...)
+}
+
+// Resolve returns value unchanged unless it is a secret:// reference, in which
+// case the referenced secret is fetched from its provider.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+
+	ref, err := ParseReference(value)
+	if err != nil {
+		return "", err
+	}
+
+	provider, ok := r.providers[ref.Provider]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownProvider, ref.Provider)
+	}
+
+	values, err := provider.Fetch(ctx, ref.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", ref, err)
+	}
+
+	return ref.pick(values)
+}
+
+// Watch re-resolves ref every interval and calls onChange whenever the value
+// differs from the previous one. Fetch errors are passed to onError and the
+// last known value is kept. Watch blocks until ctx is cancelled.
+func (r *Resolver) Watch(ctx context.Context, ref string, current string, interval time.Duration, onChange func(string), onError func(error)) {
+	if interval <= 0 || !IsReference(ref) {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			value, err := r.Resolve(fetchCtx, ref)
+			cancel()
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+			if value != current {
+				current = value
+				onChange(value)
+			}
+		}
+	}
+}
+
+// Value is a concurrency-safe holder for a secret that may be rotated at runtime.
+type Value struct {
+	v atomic.Value
+}
+
+// NewValue creates a holder initialised with value
+func NewValue(value string) *Value {
+	sv := &Value{}
+	sv.Set(value)
+	return sv
+}
+
+func (s *Value) Get() string {
+	v, _ := s.v.Load().(string)
+	return v
+}
+
+func (s *Value) Set(value string) {
+	s.v.Store(value)
+}
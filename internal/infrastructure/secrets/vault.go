@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultProvider reads secrets from HashiCorp Vault's KV engine over the HTTP API.
+// Both KV v1 and KV v2 (paths containing /data/) are supported.
+type VaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// NewVaultProvider creates a new Vault provider
+func NewVaultProvider(address, token, namespace string) *VaultProvider {
+	return &VaultProvider{
+		address:   strings.TrimRight(address, "/"),
+		token:     token,
+		namespace: namespace,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+func (p *VaultProvider) Fetch(ctx context.Context, path string) (map[string]string, error) {
+	url := fmt.Sprintf("%s/v1/%s", p.address, strings.TrimLeft(path, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: vault path %s", ErrSecretNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	data := payload.Data
+	// KV v2 nests the secret under data.data alongside metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, hasMeta := data["metadata"]; hasMeta {
+			data = nested
+		}
+	}
+
+	values := make(map[string]string, len(data))
+	for k, v := range data {
+		switch val := v.(type) {
+		case string:
+			values[k] = val
+		default:
+			encoded, _ := json.Marshal(val)
+			values[k] = string(encoded)
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"cargo-tracker/internal/config"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	"fmt"
+)
+
+// New creates the store selected by configuration
+func New(cfg *config.StorageConfig) (domainStorage.Store, error) {
+	switch cfg.Backend {
+	case "", "local":
+		path := cfg.LocalPath
+		if path == "" {
+			path = "./data/uploads"
+		}
+		return NewLocalStore(path)
+	default:
+		return nil, fmt.Errorf("unsupported storage backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"cargo-tracker/pkg/utils"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Availability reports whether a backing store can currently serve requests
+type Availability interface {
+	Available() bool
+	RetryAfter() time.Duration
+}
+
+// AvailabilityMiddleware sheds requests with 503 while store is unavailable,
+// telling clients when to retry instead of letting every handler run into
+// the outage and fail with a 500.
+func AvailabilityMiddleware(store Availability) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if store.Available() {
+			c.Next()
+			return
+		}
+
+		seconds := int(math.Ceil(store.RetryAfter().Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "Service temporarily unavailable, please retry later")
+		c.Abort()
+	}
+}
@@ -0,0 +1,309 @@
+package middleware
+
+import (
+	"bytes"
+	"cargo-tracker/internal/config"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultCompressionMinSize = 1024
+
+	encodingBrotli  = "br"
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// compressibleTypes lists the content types worth compressing. Binary formats
+// such as PDF, ZIP and images are already compressed and are passed through.
+var compressibleTypes = []string{
+	"application/json",
+	"application/x-ndjson",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/",
+}
+
+// CompressionMiddleware compresses responses with brotli, gzip or deflate
+// depending on the client's Accept-Encoding header. Responses smaller than MinSize are sent
+// uncompressed since the framing overhead outweighs the savings.
+func CompressionMiddleware(cfg *config.CompressionConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = DefaultCompressionMinSize
+	}
+	level := cfg.Level
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression || level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	brotliLevel := cfg.BrotliLevel
+	if brotliLevel < brotli.BestSpeed || brotliLevel > brotli.BestCompression {
+		brotliLevel = brotli.DefaultCompression
+	}
+
+	brotliPool := sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotliLevel)
+	}}
+	gzipPool := sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, level)
+		return w
+	}}
+	deflatePool := sync.Pool{New: func() interface{} {
+		w, _ := zlib.NewWriterLevel(io.Discard, level)
+		return w
+	}}
+
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Encoding")
+
+		if c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			minSize:        minSize,
+			status:         http.StatusOK,
+		}
+		switch encoding {
+		case encodingBrotli:
+			cw.newEncoder = func(w io.Writer) encoder {
+				bw := brotliPool.Get().(*brotli.Writer)
+				bw.Reset(w)
+				return bw
+			}
+			cw.release = func(e encoder) { brotliPool.Put(e) }
+		case encodingGzip:
+			cw.newEncoder = func(w io.Writer) encoder {
+				gz := gzipPool.Get().(*gzip.Writer)
+				gz.Reset(w)
+				return gz
+			}
+			cw.release = func(e encoder) { gzipPool.Put(e) }
+		case encodingDeflate:
+			cw.newEncoder = func(w io.Writer) encoder {
+				zw := deflatePool.Get().(*zlib.Writer)
+				zw.Reset(w)
+				return zw
+			}
+			cw.release = func(e encoder) { deflatePool.Put(e) }
+		}
+
+		original := c.Writer
+		c.Writer = cw
+		defer func() {
+			cw.finish()
+			c.Writer = original
+		}()
+
+		c.Next()
+	}
+}
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressWriter buffers the response until it is large enough to be worth
+// compressing, then switches to streaming through the encoder.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding   string
+	minSize    int
+	status     int
+	buf        bytes.Buffer
+	enc        encoder
+	decided    bool
+	newEncoder func(io.Writer) encoder
+	release    func(encoder)
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		return
+	}
+	w.status = code
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	// Headers are written once the compression decision has been made.
+}
+
+func (w *compressWriter) Status() int {
+	if w.decided {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *compressWriter) Written() bool {
+	return w.decided || w.buf.Len() > 0
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide commits the response headers and drains the buffer, compressing when
+// allowed and the response qualifies.
+func (w *compressWriter) decide(allowCompression bool) error {
+	w.decided = true
+	header := w.ResponseWriter.Header()
+
+	compress := allowCompression &&
+		bodyAllowedForStatus(w.status) &&
+		header.Get("Content-Encoding") == "" &&
+		isCompressible(header.Get("Content-Type"))
+
+	if compress {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(w.status)
+		w.enc = w.newEncoder(w.ResponseWriter)
+	} else {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	data := w.buf.Bytes()
+	w.buf.Reset()
+	if w.enc != nil {
+		_, err := w.enc.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+func (w *compressWriter) finish() {
+	if !w.decided {
+		// The whole body fit in the buffer and stayed below the threshold.
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.release(w.enc)
+		w.enc = nil
+	}
+}
+
+// negotiateEncoding picks the best supported content coding from an
+// Accept-Encoding header, honouring q-values and the "*" wildcard. On equal
+// weights brotli is preferred for its better ratio on JSON.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	weights := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if coding == "*" {
+			wildcard = q
+			continue
+		}
+		weights[coding] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, coding := range []string{encodingBrotli, encodingGzip, encodingDeflate} {
+		q, ok := weights[coding]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = coding, q
+		}
+	}
+	return best
+}
+
+func isCompressible(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	contentType = strings.ToLower(contentType)
+	for _, t := range compressibleTypes {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
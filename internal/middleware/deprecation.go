@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationMiddleware announces that an API version is deprecated. Its
+// responses carry a Deprecation header (RFC 9745) from deprecatedAt on, a
+// Sunset header (RFC 8594) with the date the version may be removed, and a
+// Link to the successor version. Nothing is added while deprecatedAt is nil.
+func DeprecationMiddleware(deprecatedAt, sunsetAt *time.Time, successor string) gin.HandlerFunc {
+	if deprecatedAt == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	var sunset string
+	if sunsetAt != nil {
+		sunset = sunsetAt.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		headers := c.Writer.Header()
+		headers.Set("Deprecation", deprecation)
+		if sunset != "" {
+			headers.Set("Sunset", sunset)
+		}
+		if successor != "" {
+			headers.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"cargo-tracker/pkg/utils"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DeviceAuthenticator resolves a device API credential to its device
+type DeviceAuthenticator interface {
+	AuthenticateDevice(ctx context.Context, credential string) (uuid.UUID, error)
+}
+
+// DeviceAuthMiddleware authenticates trackers calling the device API with
+// an "Authorization: Device <credential>" header and sets deviceID. Device
+// routes must not be mixed with user routes, which expect userID.
+func DeviceAuthMiddleware(auth DeviceAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) != 2 || parts[0] != "Device" {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Device credential required")
+			c.Abort()
+			return
+		}
+
+		deviceID, err := auth.AuthenticateDevice(c.Request.Context(), parts[1])
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid device credential")
+			c.Abort()
+			return
+		}
+
+		c.Set("deviceID", deviceID)
+		c.Next()
+	}
+}
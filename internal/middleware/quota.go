@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"cargo-tracker/pkg/utils"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// quotaExemptPaths stay reachable once the API call quota is used up, so a
+// user can still see their usage and sign out
+var quotaExemptPaths = map[string]bool{
+	"/api/v1/profile/quota": true,
+	"/api/v1/revoke":        true,
+}
+
+// APICallCounter counts authenticated API calls against the caller's quota
+type APICallCounter interface {
+	// AllowAPICall counts a call and reports whether it is within the quota
+	AllowAPICall(ctx context.Context, userID uuid.UUID) bool
+}
+
+// QuotaMiddleware rejects requests with 429 once the caller has used up the
+// API calls of their plan for the month. It must run after AuthMiddleware.
+func QuotaMiddleware(counter APICallCounter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if quotaExemptPaths[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		userID, ok := c.Get("userID")
+		if !ok || counter.AllowAPICall(c.Request.Context(), userID.(uuid.UUID)) {
+			c.Next()
+			return
+		}
+
+		utils.ErrorResponse(c, http.StatusTooManyRequests, "Monthly API call quota exceeded")
+		c.Abort()
+	}
+}
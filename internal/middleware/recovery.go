@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/pkg/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RecoveryMiddleware turns a panic in a handler into a 500 response and
+// logs it with its stack trace. Panics caused by the client hanging up
+// are only logged, as there is nobody left to answer.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				// Deliberate abort; let net/http handle it
+				panic(recovered)
+			}
+
+			log := logger.WithRequestID(GetRequestID(c))
+			fields := []zap.Field{
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("panic", fmt.Sprint(recovered)),
+			}
+
+			if err, ok := recovered.(error); ok && isBrokenConnection(err) {
+				log.Warn("Client connection lost while writing response", fields...)
+				c.Abort()
+				return
+			}
+
+			fields = append(fields,
+				zap.ByteString("stack", debug.Stack()),
+				zap.String("event", "handler_panic"),
+			)
+			log.Error("Recovered from panic", fields...)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Internal server error")
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
+
+func isBrokenConnection(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
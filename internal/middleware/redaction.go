@@ -0,0 +1,224 @@
+package middleware
+
+import (
+	"bytes"
+	"cargo-tracker/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const redactedValue = "[redacted]"
+
+// redactedKeys are address parts below city level that are dropped outright
+var redactedKeys = map[string]bool{
+	"line1":       true,
+	"line2":       true,
+	"ward":        true,
+	"postal_code": true,
+	"formatted":   true,
+}
+
+// readOnlyAllowedPaths are the writes a read-only user still needs to manage
+// their own session
+var readOnlyAllowedPaths = map[string]bool{
+	"/api/v1/revoke":                  true,
+	"/api/v1/profile/change-password": true,
+}
+
+// ReadOnlyMiddleware rejects every non-read request made by one of roles. It
+// must run after AuthMiddleware.
+func ReadOnlyMiddleware(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasRole(c, roles) {
+			c.Next()
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if readOnlyAllowedPaths[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		utils.ErrorResponse(c, http.StatusForbidden, "This account has read-only access")
+		c.Abort()
+	}
+}
+
+// RedactionMiddleware strips personal data from JSON responses sent to one of
+// roles: emails and phone numbers are masked, addresses are cut down to the
+// city and coordinates are removed. Other content types are passed through.
+// It must run after AuthMiddleware.
+func RedactionMiddleware(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasRole(c, roles) {
+			c.Next()
+			return
+		}
+
+		rw := &redactWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		original := c.Writer
+		c.Writer = rw
+		defer func() {
+			rw.finish()
+			c.Writer = original
+		}()
+
+		c.Next()
+	}
+}
+
+func hasRole(c *gin.Context, roles []string) bool {
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	for _, r := range roles {
+		if userRole == r {
+			return true
+		}
+	}
+	return false
+}
+
+// redactWriter holds back JSON bodies until the handler is done so they can be
+// rewritten as a whole. Anything else is streamed as soon as it is written.
+type redactWriter struct {
+	gin.ResponseWriter
+	status      int
+	buf         bytes.Buffer
+	decided     bool
+	passthrough bool
+}
+
+func (w *redactWriter) WriteHeader(code int) {
+	if w.decided {
+		return
+	}
+	w.status = code
+}
+
+func (w *redactWriter) WriteHeaderNow() {
+	// Headers are written once the body has been redacted.
+}
+
+func (w *redactWriter) Status() int {
+	if w.decided {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *redactWriter) Written() bool {
+	return w.decided || w.buf.Len() > 0
+}
+
+func (w *redactWriter) Write(data []byte) (int, error) {
+	if !w.decided && !isJSON(w.Header().Get("Content-Type")) {
+		w.decided = true
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+func (w *redactWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *redactWriter) Flush() {
+	// JSON bodies can only be redacted once complete; other bodies are
+	// written straight through already.
+	if w.passthrough {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *redactWriter) finish() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	body := w.buf.Bytes()
+	if len(body) > 0 {
+		var payload interface{}
+		if err := json.Unmarshal(body, &payload); err == nil {
+			if redacted, err := json.Marshal(redactValue("", payload)); err == nil {
+				body = redacted
+			}
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(body) > 0 {
+		_, _ = w.ResponseWriter.Write(body)
+	}
+}
+
+func isJSON(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "application/json")
+}
+
+// redactValue rewrites value found under key, walking into objects and arrays
+func redactValue(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = redactValue(k, child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(key, child)
+		}
+		return v
+	case string:
+		return redactString(key, v)
+	case float64:
+		if key == "latitude" || key == "longitude" {
+			return nil
+		}
+	}
+	return value
+}
+
+func redactString(key, value string) string {
+	if value == "" {
+		return value
+	}
+
+	key = strings.ToLower(key)
+	switch {
+	case strings.Contains(key, "email"), strings.Contains(key, "phone"), redactedKeys[key]:
+		return redactedValue
+	case key == "address" || strings.HasSuffix(key, "_address"):
+		return cityOnly(value)
+	}
+	return value
+}
+
+// cityOnly keeps the last comma separated part of a free-form address, which
+// for the addresses we store is the city. Addresses without a comma cannot be
+// split safely and are redacted in full.
+func cityOnly(address string) string {
+	idx := strings.LastIndex(address, ",")
+	if idx < 0 {
+		return redactedValue
+	}
+	city := strings.TrimSpace(address[idx+1:])
+	if city == "" {
+		return redactedValue
+	}
+	return city
+}
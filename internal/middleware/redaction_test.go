@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	usecaseShipment "cargo-tracker/internal/usecase/shipment"
+	usecaseUser "cargo-tracker/internal/usecase/user"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// redactedResponses are the shipment and user responses analysts read
+var redactedResponses = []interface{}{
+	usecaseShipment.ShipmentDetailResponse{},
+	usecaseShipment.ShipmentListResponse{},
+	usecaseShipment.PackageListResponse{},
+	usecaseShipment.MarketplaceListingResponse{},
+	usecaseUser.UserListResponse{},
+	usecaseUser.AuthResponse{},
+	usecaseUser.AddressBookResponse{},
+}
+
+// personalFields are the text and number fields of redactedResponses that
+// RedactionMiddleware must rewrite
+var personalFields = map[string]bool{
+	"email":            true,
+	"phone":            true,
+	"phone_number":     true,
+	"support_email":    true,
+	"support_phone":    true,
+	"contact_phone":    true,
+	"pickup_address":   true,
+	"delivery_address": true,
+	"default_address":  true,
+	"ip_address":       true,
+	"line1":            true,
+	"line2":            true,
+	"ward":             true,
+	"postal_code":      true,
+	"formatted":        true,
+	"latitude":         true,
+	"longitude":        true,
+}
+
+// visibleFields are the text and number fields of redactedResponses that
+// analysts see as they are
+var visibleFields = map[string]bool{
+	// Parties and accounts
+	"full_name":        true,
+	"username":         true,
+	"role":             true,
+	"plan":             true,
+	"default_currency": true,
+	"contact_name":     true,
+	"label":            true,
+	"district":         true,
+	"city":             true,
+	"province":         true,
+	"country_code":     true,
+	"status":           true,
+	"access_token":     true,
+	"refresh_token":    true,
+
+	// Goods and packages
+	"goods_description": true,
+	"goods_category":    true,
+	"goods_currency":    true,
+	"goods_value":       true,
+	"goods_weight":      true,
+	"description":       true,
+	"weight":            true,
+	"total_weight":      true,
+	"distance":          true,
+	"outcome":           true,
+	"outcome_note":      true,
+
+	// Devices and rules
+	"hardware_uid":          true,
+	"device_name":           true,
+	"temp_min":              true,
+	"temp_max":              true,
+	"humidity_min":          true,
+	"humidity_max":          true,
+	"light_max":             true,
+	"tilt_max_angle":        true,
+	"impact_threshold_g":    true,
+	"required_capabilities": true,
+
+	// Risk, history and alerts
+	"name":           true,
+	"detail":         true,
+	"code":           true,
+	"field":          true,
+	"message":        true,
+	"from_status":    true,
+	"to_status":      true,
+	"notes":          true,
+	"alert_type":     true,
+	"severity":       true,
+	"violation_type": true,
+
+	// Notes written on the shipment
+	"customer_notes":   true,
+	"completion_notes": true,
+
+	// Branding and terms
+	"display_name":  true,
+	"primary_color": true,
+	"accent_color":  true,
+	"support_url":   true,
+	"logo_url":      true,
+	"user_agent":    true,
+	"signature_url": true,
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*interface{ MarshalText() ([]byte, error) })(nil)).Elem()
+)
+
+// isValue reports whether t marshals itself, as times and IDs do
+func isValue(t reflect.Type) bool {
+	return t.Implements(jsonMarshaler) || t.Implements(textMarshaler) ||
+		reflect.PointerTo(t).Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(textMarshaler)
+}
+
+// textFields collects the JSON keys of every string and float field of t,
+// following pointers, slices and embedded structs
+func textFields(t reflect.Type, keys map[string]bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isValue(t) {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			textFields(field.Type, keys)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		switch {
+		case isValue(ft):
+		case ft.Kind() == reflect.String, ft.Kind() == reflect.Float32, ft.Kind() == reflect.Float64:
+			keys[name] = true
+		case ft.Kind() == reflect.Struct:
+			textFields(ft, keys)
+		}
+	}
+}
+
+// sampleAddress is a value every kind of redaction changes
+const sampleAddress = "12 Ly Thuong Kiet, Hoan Kiem, Ha Noi"
+
+// fill sets every field reachable from v, so each one appears in the JSON
+func fill(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if isValue(v.Type().Elem()) {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0))
+	case reflect.Struct:
+		if isValue(v.Type()) {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	case reflect.String:
+		v.SetString(sampleAddress)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(21.0285)
+	}
+}
+
+func decode(t *testing.T, body []byte) interface{} {
+	t.Helper()
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return payload
+}
+
+// compareLeaves checks every string and number under key in the redacted
+// payload against the original
+func compareLeaves(t *testing.T, typeName, key string, original, redacted interface{}) {
+	t.Helper()
+	switch o := original.(type) {
+	case map[string]interface{}:
+		r := redacted.(map[string]interface{})
+		for k, child := range o {
+			compareLeaves(t, typeName, k, child, r[k])
+		}
+	case []interface{}:
+		r := redacted.([]interface{})
+		for i, child := range o {
+			compareLeaves(t, typeName, key, child, r[i])
+		}
+	case string, float64:
+		switch {
+		case personalFields[key] && reflect.DeepEqual(o, redacted):
+			t.Errorf("%s: %q is personal but was sent unredacted as %v", typeName, key, redacted)
+		case visibleFields[key] && !reflect.DeepEqual(o, redacted):
+			t.Errorf("%s: %q is visible but was redacted from %v to %v", typeName, key, o, redacted)
+		}
+	}
+}
+
+// TestRedactionCoversResponseFields fails when a response gains a text or
+// number field nobody has decided about. Personal data belongs in
+// personalFields, with redaction matching it; anything else in visibleFields.
+func TestRedactionCoversResponseFields(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, dto := range redactedResponses {
+		typ := reflect.TypeOf(dto)
+
+		keys := make(map[string]bool)
+		textFields(typ, keys)
+		var unclassified []string
+		for key := range keys {
+			seen[key] = true
+			if personalFields[key] == visibleFields[key] {
+				unclassified = append(unclassified, key)
+			}
+		}
+		sort.Strings(unclassified)
+		for _, key := range unclassified {
+			if personalFields[key] {
+				t.Errorf("%s: %q is listed as both personal and visible", typ.Name(), key)
+				continue
+			}
+			t.Errorf("%s: add the new %q field to personalFields or visibleFields", typ.Name(), key)
+		}
+
+		sample := reflect.New(typ)
+		fill(sample.Elem())
+		body, err := json.Marshal(sample.Interface())
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", typ.Name(), err)
+		}
+		compareLeaves(t, typ.Name(), "", decode(t, body), redactValue("", decode(t, body)))
+	}
+
+	// Entries for fields that no longer exist would hide a reused name
+	for _, classified := range []map[string]bool{personalFields, visibleFields} {
+		for key := range classified {
+			if !seen[key] {
+				t.Errorf("%q is classified but no response has it", key)
+			}
+		}
+	}
+}
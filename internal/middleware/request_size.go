@@ -9,15 +9,61 @@ import (
 
 const (
 	DefaultMaxRequestSize = 10 << 20
+	DefaultMaxUploadSize  = 50 << 20
+	DefaultMaxBulkSize    = 20 << 20
 )
 
-// RequestSizeLimitMiddleware limits the size of incoming requests to maxSize bytes.
-func RequestSizeLimitMiddleware(maxSize int64) gin.HandlerFunc {
+// BodyLimits holds the request body limit of every route. Routes without a
+// limit of their own get the default.
+type BodyLimits struct {
+	defaultMax int64
+	routes     map[string]int64
+}
+
+// NewBodyLimits creates the limits with defaultMax for every route
+func NewBodyLimits(defaultMax int64) *BodyLimits {
+	if defaultMax <= 0 {
+		defaultMax = DefaultMaxRequestSize
+	}
+	return &BodyLimits{defaultMax: defaultMax, routes: make(map[string]int64)}
+}
+
+// Set gives a route, by method and registered path, its own limit. It must
+// be called while routes are set up, before the server starts.
+func (l *BodyLimits) Set(method, path string, maxSize int64) {
+	l.routes[method+" "+path] = maxSize
+}
+
+// For returns the limit of a route
+func (l *BodyLimits) For(method, path string) int64 {
+	if maxSize, ok := l.routes[method+" "+path]; ok {
+		return maxSize
+	}
+	return l.defaultMax
+}
+
+// UploadLimit returns the limit for multipart file upload endpoints
+func UploadLimit(maxSize int64) int64 {
 	if maxSize <= 0 {
-		maxSize = DefaultMaxRequestSize
+		return DefaultMaxUploadSize
 	}
+	return maxSize
+}
+
+// BulkLimit returns the limit for bulk import and batch endpoints
+func BulkLimit(maxSize int64) int64 {
+	if maxSize <= 0 {
+		return DefaultMaxBulkSize
+	}
+	return maxSize
+}
 
+// RequestSizeLimitMiddleware limits the size of incoming requests to the
+// limit of the matched route. The route is resolved before any middleware
+// runs, so a route with a larger limit is not rejected by the default.
+func RequestSizeLimitMiddleware(limits *BodyLimits) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		maxSize := limits.For(c.Request.Method, c.FullPath())
 		if c.Request.ContentLength > maxSize {
 			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body too large")
 			c.Abort()
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"cargo-tracker/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// limitBodies gives the routes added by register their own request body limit
+func limitBodies(engine *gin.Engine, limits *middleware.BodyLimits, maxSize int64, register func()) {
+	existing := make(map[string]bool)
+	for _, route := range engine.Routes() {
+		existing[route.Method+" "+route.Path] = true
+	}
+
+	register()
+
+	for _, route := range engine.Routes() {
+		if !existing[route.Method+" "+route.Path] {
+			limits.Set(route.Method, route.Path, maxSize)
+		}
+	}
+}
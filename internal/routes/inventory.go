@@ -0,0 +1,154 @@
+package routes
+
+import (
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/pkg/utils"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// publicMutations are the mutating routes that are meant to be reachable
+// without a session. Any other mutating route without authentication is
+// reported at startup.
+var publicMutations = map[string]bool{
+	"POST /api/v1/user/register":                  true,
+	"POST /api/v1/user/login":                     true,
+	"POST /api/v1/user/refresh":                   true,
+	"POST /api/v1/user/revoke":                    true,
+	"POST /api/v1/user/forgot-password":           true,
+	"POST /api/v1/user/forgot-password/phone":     true,
+	"POST /api/v1/user/reset-password":            true,
+	"POST /api/v1/user/reset-password/phone":      true,
+	"POST /api/v1/integrations/:provider/webhook": true, // Verified with the bot's webhook secret
+}
+
+// selfServiceMutations are the mutating routes any signed-in user may call
+// without a role guard: they only touch the caller's own profile,
+// subscriptions and sandbox, or the service checks access to the shipment.
+// Any other authenticated mutating route without a role guard is reported
+// at startup.
+var selfServiceMutations = map[string]bool{
+	"POST /api/v1/revoke":                                        true,
+	"PUT /api/v1/profile":                                        true,
+	"POST /api/v1/profile/change-password":                       true,
+	"POST /api/v1/profile/phone/verification":                    true,
+	"POST /api/v1/profile/phone/verify":                          true,
+	"POST /api/v1/profile/addresses":                             true,
+	"PUT /api/v1/profile/addresses/:id":                          true,
+	"DELETE /api/v1/profile/addresses/:id":                       true,
+	"POST /api/v1/push/tokens":                                   true,
+	"DELETE /api/v1/push/tokens/:id":                             true,
+	"POST /api/v1/push/subscriptions/shipments/:shipmentId":      true,
+	"DELETE /api/v1/push/subscriptions/shipments/:shipmentId":    true,
+	"POST /api/v1/reports/subscriptions":                         true,
+	"PUT /api/v1/reports/subscriptions/:id":                      true,
+	"DELETE /api/v1/reports/subscriptions/:id":                   true,
+	"POST /api/v1/chat-links/code":                               true,
+	"DELETE /api/v1/chat-links/:provider":                        true,
+	"POST /api/v1/sandbox/reset":                                 true,
+	"POST /api/v1/shipments/:id/documents":                       true,
+	"PUT /api/v1/shipments/:id/documents/:documentId/visibility": true,
+	"POST /api/v1/shipments/:id/notes":                           true,
+	"PUT /api/v1/shipments/:id/notes/:noteId":                    true,
+	"DELETE /api/v1/shipments/:id/notes/:noteId":                 true,
+}
+
+// RouteEntry describes a registered route and the guard in front of it
+type RouteEntry struct {
+	Method        string   `json:"method"`
+	Path          string   `json:"path"`
+	Handler       string   `json:"handler"`
+	Authenticated bool     `json:"authenticated"`
+	Roles         []string `json:"roles,omitempty"` // Empty means any authenticated role
+}
+
+// routeInventory records which guard each route was registered behind. Gin
+// does not expose the middleware chain of a route, so SetupRoutes calls
+// record after registering each group and every route not seen before is
+// attributed to that group's guard. The routes tests compare this with the
+// real handler chains.
+type routeInventory struct {
+	engine  *gin.Engine
+	entries map[string]*RouteEntry
+}
+
+func newRouteInventory(engine *gin.Engine) *routeInventory {
+	return &routeInventory{engine: engine, entries: make(map[string]*RouteEntry)}
+}
+
+// record attributes the routes registered since the last call to a guard
+func (inv *routeInventory) record(authenticated bool, roles ...string) {
+	for _, route := range inv.engine.Routes() {
+		key := route.Method + " " + route.Path
+		if _, ok := inv.entries[key]; ok {
+			continue
+		}
+		inv.entries[key] = &RouteEntry{
+			Method:        route.Method,
+			Path:          route.Path,
+			Handler:       handlerName(route.Handler),
+			Authenticated: authenticated,
+			Roles:         roles,
+		}
+	}
+}
+
+// routes returns the inventory sorted by path and method
+func (inv *routeInventory) routes() []RouteEntry {
+	routes := make([]RouteEntry, 0, len(inv.entries))
+	for _, entry := range inv.entries {
+		routes = append(routes, *entry)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// checkGuards logs every mutating route that is reachable without a session
+// and not listed in publicMutations, or open to every role and not listed in
+// selfServiceMutations
+func (inv *routeInventory) checkGuards() {
+	for _, entry := range inv.routes() {
+		if entry.Method == http.MethodGet || entry.Method == http.MethodHead {
+			continue
+		}
+		key := entry.Method + " " + entry.Path
+		switch {
+		case !entry.Authenticated && !publicMutations[key]:
+			logger.Warn("Mutating route has no authentication guard",
+				zap.String("method", entry.Method),
+				zap.String("path", entry.Path),
+				zap.String("handler", entry.Handler),
+				zap.String("event", "route_unguarded"),
+			)
+		case entry.Authenticated && len(entry.Roles) == 0 && !selfServiceMutations[key]:
+			logger.Warn("Mutating route has no role guard",
+				zap.String("method", entry.Method),
+				zap.String("path", entry.Path),
+				zap.String("handler", entry.Handler),
+				zap.String("event", "route_unguarded"),
+			)
+		}
+	}
+}
+
+// list serves the route inventory to admins
+func (inv *routeInventory) list(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Routes retrieved successfully", inv.routes())
+}
+
+func handlerName(name string) string {
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,137 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const probeHeader = "X-Route-Probe"
+
+// chainProbe records the full handler chain of every request carrying
+// probeHeader and stops it before any guard or handler runs
+type chainProbe map[string][]string
+
+func (p chainProbe) middleware(c *gin.Context) {
+	if c.GetHeader(probeHeader) == "" {
+		c.Next()
+		return
+	}
+	p[c.Request.Method+" "+c.FullPath()] = c.HandlerNames()
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// guards is what a route's handler chain checks before its handler runs
+type guards struct {
+	authenticated bool
+	roleChecked   bool
+}
+
+func guardsOf(chain []string) guards {
+	var g guards
+	for _, name := range chain {
+		switch {
+		case strings.Contains(name, "/middleware.AuthMiddleware."):
+			g.authenticated = true
+		case strings.Contains(name, "/middleware.DeviceAuthMiddleware."):
+			// Only devices get through, which is a role of its own
+			g.authenticated = true
+			g.roleChecked = true
+		case strings.Contains(name, "/middleware.RoleMiddleware."):
+			g.roleChecked = true
+		}
+	}
+	return g
+}
+
+// routeGuards builds the full router and returns the guards of every route
+// as its real handler chain has them, keyed by "METHOD /path"
+func routeGuards(t *testing.T) (map[string]guards, *routeInventory) {
+	t.Helper()
+	probe := chainProbe{}
+	router, inventory, _ := testRouter(t, func(engine *gin.Engine) {
+		engine.Use(probe.middleware)
+	})
+
+	byRoute := make(map[string]guards)
+	for _, route := range router.Routes() {
+		key := route.Method + " " + route.Path
+		req := httptest.NewRequest(route.Method, probePath(route.Path), nil)
+		req.Header.Set(probeHeader, "1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		chain, ok := probe[key]
+		if !ok {
+			t.Fatalf("%s: the probe request was not routed to it", key)
+		}
+		byRoute[key] = guardsOf(chain)
+	}
+	return byRoute, inventory
+}
+
+// probePath fills in the parameters of a route path
+func probePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "probe"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestMutatingRoutesHaveAuthAndRoleGuards(t *testing.T) {
+	byRoute, _ := routeGuards(t)
+
+	for key, g := range byRoute {
+		method, _, _ := strings.Cut(key, " ")
+		if method == http.MethodGet || method == http.MethodHead {
+			continue
+		}
+		switch {
+		case publicMutations[key]:
+			if g.authenticated {
+				t.Errorf("%s is listed in publicMutations but requires a session", key)
+			}
+		case !g.authenticated:
+			t.Errorf("%s has no authentication guard; add one or list it in publicMutations", key)
+		case selfServiceMutations[key]:
+			if g.roleChecked {
+				t.Errorf("%s is listed in selfServiceMutations but has a role guard", key)
+			}
+		case !g.roleChecked:
+			t.Errorf("%s has no role guard; add one or list it in selfServiceMutations", key)
+		}
+	}
+
+	// Allowlist entries for routes that no longer exist hide future gaps
+	for _, allowlist := range []map[string]bool{publicMutations, selfServiceMutations} {
+		for key := range allowlist {
+			if _, ok := byRoute[key]; !ok {
+				t.Errorf("%s is allowlisted but not registered", key)
+			}
+		}
+	}
+}
+
+func TestRouteInventoryMatchesHandlerChains(t *testing.T) {
+	byRoute, inventory := routeGuards(t)
+
+	if len(inventory.entries) != len(byRoute) {
+		t.Errorf("inventory lists %d routes, the router has %d", len(inventory.entries), len(byRoute))
+	}
+	for key, g := range byRoute {
+		entry, ok := inventory.entries[key]
+		if !ok {
+			t.Errorf("%s is missing from the inventory", key)
+			continue
+		}
+		if entry.Authenticated != g.authenticated || (len(entry.Roles) > 0) != g.roleChecked {
+			t.Errorf("%s: inventory says authenticated=%v roles=%v, handler chain has authentication=%v role guard=%v",
+				key, entry.Authenticated, entry.Roles, g.authenticated, g.roleChecked)
+		}
+	}
+}
@@ -3,34 +3,52 @@ package routes
 import (
 	"cargo-tracker/internal/config"
 	"cargo-tracker/internal/delivery/http/handler"
+	"cargo-tracker/internal/delivery/http/server"
+	domainCurrency "cargo-tracker/internal/domain/currency"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainStorage "cargo-tracker/internal/domain/storage"
 	"cargo-tracker/internal/infrastructure/database/postgres"
+	infraNotification "cargo-tracker/internal/infrastructure/notification"
 	"cargo-tracker/internal/logger"
 	"cargo-tracker/internal/middleware"
+	"cargo-tracker/internal/usecase/announcement"
+	"cargo-tracker/internal/usecase/cleanup"
 	"cargo-tracker/internal/usecase/device"
+	"cargo-tracker/internal/usecase/document"
+	"cargo-tracker/internal/usecase/interop"
+	"cargo-tracker/internal/usecase/invoice"
+	"cargo-tracker/internal/usecase/job"
+	"cargo-tracker/internal/usecase/notification"
+	"cargo-tracker/internal/usecase/quota"
+	"cargo-tracker/internal/usecase/quotation"
+	"cargo-tracker/internal/usecase/reference"
+	"cargo-tracker/internal/usecase/report"
 	"cargo-tracker/internal/usecase/shipment"
 	"cargo-tracker/internal/usecase/user"
-	_ "context"
+	"cargo-tracker/pkg/utils"
+	"context"
 	"net/http"
-	_ "time"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(cfg *config.Config, db *postgres.DB) *gin.Engine {
-	if cfg.Server.Environment == "production" {
-		gin.SetMode(gin.ReleaseMode)
-	}
-
-	router := gin.Default()
+// SetupRoutes wires the services and their handlers. Background jobs run until
+// ctx is cancelled and are tracked by workers, so shutdown can wait for them
+// before closing the database.
+func SetupRoutes(ctx context.Context, workers *sync.WaitGroup, cfg *config.Config, db *postgres.DB, store domainStorage.Store, notifier domainNotification.Notifier, rates domainCurrency.RateSource) *gin.Engine {
+	bodyLimits := middleware.NewBodyLimits(cfg.Request.MaxBodyBytes)
+	router := server.NewEngine(cfg, bodyLimits)
+	registerRoutes(ctx, workers, router, bodyLimits, cfg, db, store, notifier, rates)
+	return router
+}
 
-	// Add middleware in order: request ID, logging, security headers, CORS, request size limit, general rate limit
-	router.Use(gin.Recovery())
-	router.Use(middleware.RequestIDMiddleware())
-	router.Use(middleware.LoggingMiddleware())
-	router.Use(middleware.SecurityHeadersMiddleware())
-	router.Use(middleware.CORSMiddleware(&cfg.CORS))
-	router.Use(middleware.RequestSizeLimitMiddleware(10 << 20))
-	router.Use(middleware.RateLimitMiddleware(cfg.RateLimit.GeneralRPS, cfg.RateLimit.GeneralBurst))
+// registerRoutes registers every route on router, which was built by
+// server.NewEngine with bodyLimits, and returns the route inventory
+func registerRoutes(ctx context.Context, workers *sync.WaitGroup, router *gin.Engine, bodyLimits *middleware.BodyLimits, cfg *config.Config, db *postgres.DB, store domainStorage.Store, notifier domainNotification.Notifier, rates domainCurrency.RateSource) *routeInventory {
+	router.Use(middleware.AvailabilityMiddleware(db.Breaker))
+	inventory := newRouteInventory(router)
 
 	router.GET("/health", func(c *gin.Context) {
 		if err := db.Health(); err != nil {
@@ -47,66 +65,340 @@ func SetupRoutes(cfg *config.Config, db *postgres.DB) *gin.Engine {
 		})
 	})
 
+	// The ref validation tag looks values up in the reference tables
+	referenceService := reference.NewService(postgres.NewReferenceRepository(db))
+	utils.SetReferenceLookup(referenceService.IsValid)
+	referenceHandler := handler.NewReferenceHandler(referenceService)
+
 	userRepository := postgres.NewUserRepository(db)
 	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
-	userService := user.NewService(userRepository, refreshTokenRepo, cfg)
+	invitationRepository := postgres.NewInvitationRepository(db)
+	userService := user.NewService(userRepository, refreshTokenRepo, postgres.NewOTPRepository(db), invitationRepository, infraNotification.NewSMSSender(&cfg.SMS), cfg)
 	userHandler := handler.NewUserHandler(userService)
+	if cfg.Session.CleanupInterval > 0 {
+		RunInBackground(ctx, workers, func(ctx context.Context) {
+			userService.StartTokenCleanupJob(ctx, cfg.Session.CleanupInterval)
+		})
+	}
+
+	quotaService := quota.NewService(postgres.NewQuotaRepository(db), userRepository, cfg.Quota)
+	quotaHandler := handler.NewQuotaHandler(quotaService)
 
 	deviceRepository := postgres.NewDeviceRepository(db)
-	deviceService := device.NewService(deviceRepository, userRepository)
+	deviceService := device.NewService(deviceRepository, userRepository, cfg.StatsCache, cfg.Fleet)
+	deviceService.UseQuotas(quotaService)
 	deviceHandler := handler.NewDeviceHandler(deviceService)
 
+	brandingService := user.NewBrandingService(postgres.NewBrandingRepository(db), userRepository, store)
+	brandingHandler := handler.NewBrandingHandler(brandingService)
+
+	addressBookService := user.NewAddressBookService(postgres.NewAddressRepository(db))
+	addressBookHandler := handler.NewAddressBookHandler(addressBookService)
+
+	invitationHandler := handler.NewInvitationHandler(user.NewInvitationService(invitationRepository, userRepository, infraNotification.NewMailer(cfg), cfg))
+
+	accountMergeHandler := handler.NewAccountMergeHandler(user.NewAccountMergeService(userRepository, postgres.NewAccountMergeRepository(db)))
+
+	documentRepository := postgres.NewDocumentRepository(db)
+
 	shipmentRepository := postgres.NewShipmentRepository(db)
-	shipmentService := shipment.NewService(shipmentRepository, userRepository, deviceRepository)
+	accessGrantRepository := postgres.NewAccessGrantRepository(db)
+	tripRepository := postgres.NewTripRepository(db)
+	riskScorer := shipment.NewRiskScorer(shipment.RiskWeights{
+		Reputation:  cfg.Risk.ReputationWeight,
+		Device:      cfg.Risk.DeviceWeight,
+		Route:       cfg.Risk.RouteWeight,
+		Goods:       cfg.Risk.GoodsWeight,
+		Seasonality: cfg.Risk.SeasonalityWeight,
+	})
+	shipmentService := shipment.NewService(shipmentRepository, userRepository, deviceRepository, documentRepository, notifier, brandingService, store, accessGrantRepository, postgres.NewDelegationRepository(db), postgres.NewNoteRepository(db), postgres.NewTermsRepository(db), postgres.NewCalendarRepository(db), tripRepository, postgres.NewOverrideRepository(db), addressBookService, referenceService, riskScorer, rates, cfg.StatsCache)
+	if cfg.Events.Enabled {
+		shipmentService.UseEventLog(postgres.NewShipmentEventRepository(db))
+	}
+	shipmentService.UseQuotas(quotaService)
+	selfTestRepository := postgres.NewDeviceSelfTestRepository(db)
+	if cfg.SelfTest.RequiredForAccept {
+		shipmentService.RequireSelfTest(selfTestRepository, cfg.SelfTest.MaxAge)
+	}
 	shipmentHandler := handler.NewShipmentHandler(shipmentService)
+	shipmentV2Handler := handler.NewShipmentV2Handler(shipmentService)
+
+	// Dashboards poll the statistics endpoints; the refreshers keep their
+	// cached results warm until shutdown
+	RunInBackground(ctx, workers, deviceService.StartStatisticsRefresher)
+	RunInBackground(ctx, workers, shipmentService.StartStatisticsRefresher)
+	RunInBackground(ctx, workers, func(ctx context.Context) {
+		shipmentService.StartWatchdog(ctx, cfg.Watchdog)
+	})
+
+	decommissionService := device.NewDecommissionService(deviceRepository, postgres.NewDeviceDecommissionRepository(db), shipmentRepository, tripRepository, store)
+	decommissionHandler := handler.NewDeviceDecommissionHandler(decommissionService)
+
+	transferService := device.NewTransferService(deviceRepository, postgres.NewDeviceTransferRepository(db), userRepository)
+	transferService.UseQuotas(quotaService)
+	transferHandler := handler.NewDeviceTransferHandler(transferService)
+
+	selfTestService := device.NewSelfTestService(deviceRepository, selfTestRepository, cfg.SelfTest, cfg.Fleet)
+	selfTestHandler := handler.NewDeviceSelfTestHandler(selfTestService)
+
+	modelProfileService := device.NewModelProfileService(postgres.NewDeviceModelProfileRepository(db))
+	modelProfileHandler := handler.NewDeviceModelProfileHandler(modelProfileService)
 
-	//// Start token cleanup job
-	//cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
-	//defer cleanupCancel()
-	//go userSvc.StartTokenCleanupJob(cleanupCtx, 1*time.Hour)
+	cleanupService := cleanup.NewService(postgres.NewCleanupRepository(db), store, cfg.Cleanup, cfg.Server.Environment)
+	cleanupService.RecoverInterruptedJobs(ctx)
+	cleanupHandler := handler.NewCleanupHandler(cleanupService)
+
+	documentService := document.NewService(documentRepository, shipmentRepository, userRepository, store, accessGrantRepository)
+	documentHandler := handler.NewDocumentHandler(documentService)
+
+	quotationRepository := postgres.NewQuotationRepository(db)
+	quotationService := quotation.NewService(quotationRepository, userRepository, shipmentService, notifier)
+	quotationHandler := handler.NewQuotationHandler(quotationService)
+
+	// Close the previous billing month into invoices
+	invoiceService := invoice.NewService(postgres.NewInvoiceRepository(db), userRepository, cfg.Invoicing)
+	RunInBackground(ctx, workers, func(ctx context.Context) {
+		invoiceService.StartPeriodCloseJob(ctx, 24*time.Hour)
+	})
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
 
+	interopService := interop.NewService(postgres.NewPartnerMappingRepository(db), shipmentRepository, shipmentService)
+	interopHandler := handler.NewInteropHandler(interopService)
+
+	// Heavy endpoints queue their work as jobs that clients poll
+	jobService := job.NewService(postgres.NewJobRepository(db), store, cfg.Jobs)
+	invoiceService.RegisterJobs(jobService)
+	interopService.RegisterJobs(jobService)
+	shipmentService.RegisterJobs(jobService)
+
+	// Scheduled report emails are delivered by jobs the scheduler queues
+	reportService := report.NewService(postgres.NewReportRepository(db), userRepository, infraNotification.NewMailer(cfg), cfg.Reports)
+	reportService.RegisterJobs(jobService)
+	RunInBackground(ctx, workers, reportService.StartScheduler)
+	reportHandler := handler.NewReportHandler(reportService)
+
+	announcementHandler := handler.NewAnnouncementHandler(announcement.NewService(postgres.NewAnnouncementRepository(db)))
+
+	jobHandler := handler.NewJobHandler(jobService)
+
+	notificationTemplateRepository := postgres.NewNotificationTemplateRepository(db)
+	notificationTemplateService := notification.NewService(notificationTemplateRepository)
+	notificationTemplateHandler := handler.NewNotificationTemplateHandler(notificationTemplateService)
+
+	webhookRepository := postgres.NewNotificationWebhookRepository(db)
+	webhookEventRepository := postgres.NewWebhookEventRepository(db)
+	webhookSender := infraNotification.NewWebhookDelivery(&cfg.Notification, webhookRepository, webhookEventRepository)
+	webhookService := notification.NewWebhookService(webhookRepository, webhookEventRepository, userRepository, shipmentRepository, webhookSender)
+	webhookService.RegisterJobs(jobService)
+	webhookService.UseQuotas(quotaService)
+	// Post the daily digest to subscribed Slack and Teams channels and keep
+	// the webhook event archive within its retention
+	if cfg.Notification.DigestHour >= 0 && cfg.Notification.DigestHour < 24 {
+		RunInBackground(ctx, workers, func(ctx context.Context) {
+			webhookService.StartDailyDigestJob(ctx, cfg.Notification.DigestHour)
+		})
+	}
+	RunInBackground(ctx, workers, func(ctx context.Context) {
+		webhookService.StartEventArchivePurgeJob(ctx, cfg.Notification.WebhookEventRetention, time.Hour)
+	})
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+
+	// Every job kind is registered by now
+	RunInBackground(ctx, workers, jobService.StartWorkers)
+
+	chatLinkRepository := postgres.NewChatLinkRepository(db)
+	chatLinkService := notification.NewChatLinkService(chatLinkRepository, infraNotification.NewChatBots(&cfg.ChatBot), cfg.ChatBot.LinkCodeTTL)
+	chatLinkHandler := handler.NewChatLinkHandler(chatLinkService)
+
+	// Prune push tokens the mobile app stopped refreshing
+	pushService := notification.NewPushService(postgres.NewPushRepository(db), shipmentRepository, cfg.Push.TokenTTL)
+	RunInBackground(ctx, workers, func(ctx context.Context) {
+		pushService.StartTokenHousekeepingJob(ctx, 24*time.Hour)
+	})
+	pushHandler := handler.NewPushHandler(pushService)
+
+	// v1 is frozen: new response shapes go to v2, which shares the usecases
+	// and only maps their results differently
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.DeprecationMiddleware(cfg.APIVersions.V1DeprecatedAt, cfg.APIVersions.V1SunsetAt, "/api/v2"))
 	{
 		userHandler.RegisterRoutes(v1)
 		deviceHandler.RegisterRoutes(v1)
 		shipmentHandler.RegisterRoutes(v1)
+		shipmentHandler.RegisterCalendarRoutes(v1)
+		chatLinkHandler.RegisterRoutes(v1)
+		brandingHandler.RegisterRoutes(v1)
+		announcementHandler.RegisterRoutes(v1)
+		referenceHandler.RegisterRoutes(v1)
+		inventory.record(false)
+
+		devices := v1.Group("")
+		devices.Use(middleware.DeviceAuthMiddleware(deviceService))
+		{
+			shipmentHandler.RegisterDeviceRoutes(devices)
+			selfTestHandler.RegisterDeviceRoutes(devices)
+		}
+		inventory.record(true, "device")
 
 		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware(cfg))
+		protected.Use(
+			middleware.AuthMiddleware(cfg),
+			middleware.ReadOnlyMiddleware("analyst"),
+			middleware.RedactionMiddleware("analyst"),
+			middleware.QuotaMiddleware(quotaService),
+		)
 		{
 			userHandler.RegisterProfileRoutes(protected)
+			quotaHandler.RegisterRoutes(protected)
+			shipmentHandler.RegisterReadRoutes(protected)
+			shipmentHandler.RegisterEventRoutes(protected)
+			shipmentHandler.RegisterNoteRoutes(protected)
+			addressBookHandler.RegisterRoutes(protected)
+			modelProfileHandler.RegisterRoutes(protected)
 			protected.POST("/revoke", userHandler.RevokeToken)
+			documentHandler.RegisterRoutes(protected)
+			shipmentHandler.RegisterPackageRoutes(protected)
+			shipmentHandler.RegisterSandboxRoutes(protected)
+			shipmentHandler.RegisterReceivedAccessGrantRoutes(protected)
+			shipmentHandler.RegisterShipmentTermsRoutes(protected)
+			chatLinkHandler.RegisterProtectedRoutes(protected)
+			pushHandler.RegisterRoutes(protected)
+			jobHandler.RegisterRoutes(protected)
+			reportHandler.RegisterRoutes(protected)
+
+			limitBodies(router, bodyLimits, middleware.UploadLimit(cfg.Request.MaxUploadBytes), func() {
+				documentHandler.RegisterUploadRoutes(protected)
+			})
+			inventory.record(true)
 
 			// Customer routes
 			customer := protected.Group("")
 			customer.Use(middleware.RoleMiddleware("customer"))
 			{
 				shipmentHandler.RegisterCustomerRoutes(customer)
+				shipmentHandler.RegisterDelegationRoutes(customer)
+				quotationHandler.RegisterCustomerRoutes(customer)
+
+				limitBodies(router, bodyLimits, middleware.BulkLimit(cfg.Request.MaxBulkBytes), func() {
+					interopHandler.RegisterImportRoutes(customer)
+				})
 			}
+			inventory.record(true, "customer")
 
 			// Provider routes
 			provider := protected.Group("")
 			provider.Use(middleware.RoleMiddleware("provider"))
 			{
 				shipmentHandler.RegisterProviderRoutes(provider)
+				shipmentHandler.RegisterProviderAnalyticsRoutes(provider)
+				documentHandler.RegisterProviderRoutes(provider)
+				quotationHandler.RegisterProviderRoutes(provider)
+				brandingHandler.RegisterProviderRoutes(provider)
+				shipmentHandler.RegisterTermsRoutes(provider)
+				shipmentHandler.RegisterProviderCalendarRoutes(provider)
+				invoiceHandler.RegisterProviderRoutes(provider)
 			}
+			inventory.record(true, "provider")
 
 			// Shipper routes
 			shipper := protected.Group("")
 			shipper.Use(middleware.RoleMiddleware("shipper"))
 			{
 				shipmentHandler.RegisterShipperRoutes(shipper)
+				shipmentHandler.RegisterTripRoutes(shipper)
+				invoiceHandler.RegisterShipperRoutes(shipper)
+				transferHandler.RegisterShipperRoutes(shipper)
+				deviceHandler.RegisterFleetRoutes(shipper)
+				selfTestHandler.RegisterRoutes(shipper)
+			}
+			inventory.record(true, "shipper")
+
+			// Customer and provider routes
+			partners := protected.Group("")
+			partners.Use(middleware.RoleMiddleware("customer", "provider"))
+			{
+				interopHandler.RegisterRoutes(partners)
+				shipmentHandler.RegisterAccessGrantRoutes(partners)
+				invoiceHandler.RegisterRoutes(partners)
 			}
+			inventory.record(true, "customer", "provider")
+
+			// Provider and admin routes
+			integrations := protected.Group("")
+			integrations.Use(middleware.RoleMiddleware("provider", "admin"))
+			{
+				webhookHandler.RegisterRoutes(integrations)
+			}
+			inventory.record(true, "provider", "admin")
+
+			// Provider and shipper routes
+			logistics := protected.Group("")
+			logistics.Use(middleware.RoleMiddleware("provider", "shipper"))
+			{
+				shipmentHandler.RegisterDocumentRoutes(logistics)
+			}
+			inventory.record(true, "provider", "shipper")
+
+			analytics := protected.Group("")
+			analytics.Use(middleware.RoleMiddleware("analyst", "admin"))
+			{
+				userHandler.RegisterAnalyticsRoutes(analytics)
+			}
+			inventory.record(true, "analyst", "admin")
 
 			admin := protected.Group("/admin")
 			admin.Use(middleware.AdminOnly())
 			{
 				userHandler.RegisterAdminRoutes(admin)
+				quotaHandler.RegisterAdminRoutes(admin)
+				invitationHandler.RegisterAdminRoutes(admin)
+				accountMergeHandler.RegisterAdminRoutes(admin)
 				deviceHandler.RegisterAdminRoutes(admin)
+				decommissionHandler.RegisterAdminRoutes(admin)
+				shipmentHandler.RegisterAdminRoutes(admin)
+				deviceHandler.RegisterFleetRoutes(admin)
+				selfTestHandler.RegisterRoutes(admin)
+				modelProfileHandler.RegisterAdminRoutes(admin)
+				invoiceHandler.RegisterAdminRoutes(admin)
+				notificationTemplateHandler.RegisterAdminRoutes(admin)
+				cleanupHandler.RegisterAdminRoutes(admin)
+				announcementHandler.RegisterAdminRoutes(admin)
+				admin.GET("/routes", inventory.list)
+
+				limitBodies(router, bodyLimits, middleware.BulkLimit(cfg.Request.MaxBulkBytes), func() {
+					deviceHandler.RegisterAdminBulkRoutes(admin)
+				})
 			}
+			inventory.record(true, "admin")
 		}
 	}
 
+	v2 := router.Group("/api/v2")
+	{
+		protected := v2.Group("")
+		protected.Use(
+			middleware.AuthMiddleware(cfg),
+			middleware.ReadOnlyMiddleware("analyst"),
+			middleware.RedactionMiddleware("analyst"),
+			middleware.QuotaMiddleware(quotaService),
+		)
+		{
+			shipmentV2Handler.RegisterReadRoutes(protected)
+		}
+		inventory.record(true)
+	}
+
+	inventory.checkGuards()
 	logger.Info("All routes initialized")
-	return router
+	return inventory
+}
+
+// RunInBackground runs fn on its own goroutine, counted in workers until it
+// returns
+func RunInBackground(ctx context.Context, workers *sync.WaitGroup, fn func(context.Context)) {
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		fn(ctx)
+	}()
 }
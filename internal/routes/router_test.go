@@ -0,0 +1,147 @@
+package routes
+
+import (
+	"cargo-tracker/internal/config"
+	"cargo-tracker/internal/delivery/http/server"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/infrastructure/currency"
+	"cargo-tracker/internal/infrastructure/database/postgres"
+	"cargo-tracker/internal/infrastructure/storage"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/middleware"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	gormPostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// discardNotifier drops every notification
+type discardNotifier struct{}
+
+func (discardNotifier) Notify(ctx context.Context, msg *domainNotification.Message) error {
+	return nil
+}
+
+// testConfig loads the configuration the way the application does, from a
+// .env file, here one with only what has no default on top of the defaults
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	env := "ENVIRONMENT=test\n" +
+		"JWT_SECRET=test-secret\n" +
+		"CORS_ALLOWED_ORIGINS=http://localhost:3000\n" +
+		// Route tests send a request to every route
+		"RATE_LIMIT_GENERAL_BURST=10000\n" +
+		"STORAGE_LOCAL_PATH=" + filepath.Join(dir, "uploads") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(env), 0o600); err != nil {
+		t.Fatalf("write .env: %v", err)
+	}
+	t.Chdir(dir)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	return cfg
+}
+
+// testRouter builds the full router on a sqlmock database. Pings are
+// expected explicitly; any other statement fails, which background jobs
+// only log. setup runs on the engine before any route is registered.
+func testRouter(t *testing.T, setup func(*gin.Engine)) (*gin.Engine, *routeInventory, sqlmock.Sqlmock) {
+	t.Helper()
+	logger.Logger = zap.NewNop()
+	cfg := testConfig(t)
+
+	conn, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	// gorm pings once when it opens the connection
+	mock.ExpectPing()
+	gormDB, err := gorm.Open(gormPostgres.New(gormPostgres.Config{Conn: conn}), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	store, err := storage.New(&cfg.Storage)
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	rates, err := currency.New(&cfg.Currency)
+	if err != nil {
+		t.Fatalf("currency.New: %v", err)
+	}
+
+	// Background jobs return as soon as they see the cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var workers sync.WaitGroup
+	t.Cleanup(func() {
+		workers.Wait()
+		conn.Close()
+	})
+
+	bodyLimits := middleware.NewBodyLimits(cfg.Request.MaxBodyBytes)
+	engine := server.NewEngine(cfg, bodyLimits)
+	if setup != nil {
+		setup(engine)
+	}
+	inventory := registerRoutes(ctx, &workers, engine, bodyLimits, cfg, &postgres.DB{DB: gormDB}, store, discardNotifier{}, rates)
+	return engine, inventory, mock
+}
+
+func TestHealthReportsDatabaseState(t *testing.T) {
+	router, _, mock := testRouter(t, nil)
+	if gin.Mode() != gin.TestMode {
+		t.Errorf("gin mode = %s for ENVIRONMENT=test, want %s", gin.Mode(), gin.TestMode)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		pingErr    error
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "database up", wantCode: http.StatusOK, wantStatus: "healthy"},
+		{name: "database down", pingErr: errors.New("connection refused"), wantCode: http.StatusServiceUnavailable, wantStatus: "unhealthy"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock.ExpectPing().WillReturnError(tc.pingErr)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			if w.Code != tc.wantCode {
+				t.Errorf("GET /health = %d, want %d", w.Code, tc.wantCode)
+			}
+			var body struct {
+				Status string `json:"status"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Status != tc.wantStatus {
+				t.Errorf("GET /health status = %q (%v), want %q", body.Status, err, tc.wantStatus)
+			}
+			// The global middleware stack ran in front of the handler
+			if w.Header().Get("X-Request-ID") == "" {
+				t.Error("GET /health has no X-Request-ID header")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package announcement
+
+import (
+	domainAnnouncement "cargo-tracker/internal/domain/announcement"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+type CreateAnnouncementRequest struct {
+	Title    string `json:"title" validate:"required,max=200"`
+	Message  string `json:"message" validate:"required,max=2000"`
+	Severity string `json:"severity" validate:"omitempty,oneof=info warning critical"`
+	// Defaults to now
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at" validate:"required"`
+	// Planned downtime the announcement is about, if any
+	MaintenanceStartsAt *time.Time `json:"maintenance_starts_at"`
+	MaintenanceEndsAt   *time.Time `json:"maintenance_ends_at"`
+}
+
+type UpdateAnnouncementRequest struct {
+	Title               *string    `json:"title" validate:"omitempty,max=200"`
+	Message             *string    `json:"message" validate:"omitempty,max=2000"`
+	Severity            *string    `json:"severity" validate:"omitempty,oneof=info warning critical"`
+	StartsAt            *time.Time `json:"starts_at"`
+	EndsAt              *time.Time `json:"ends_at"`
+	MaintenanceStartsAt *time.Time `json:"maintenance_starts_at"`
+	MaintenanceEndsAt   *time.Time `json:"maintenance_ends_at"`
+}
+
+type AnnouncementQuery struct {
+	IncludeExpired bool `form:"include_expired"`
+}
+
+// Response DTOs
+
+// BannerResponse is what clients show; it carries nothing but the message
+type BannerResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	Title               string     `json:"title"`
+	Message             string     `json:"message"`
+	Severity            string     `json:"severity"`
+	StartsAt            time.Time  `json:"starts_at"`
+	EndsAt              time.Time  `json:"ends_at"`
+	MaintenanceStartsAt *time.Time `json:"maintenance_starts_at,omitempty"`
+	MaintenanceEndsAt   *time.Time `json:"maintenance_ends_at,omitempty"`
+}
+
+type AnnouncementResponse struct {
+	BannerResponse
+	Status    string    `json:"status"`
+	CreatedBy uuid.UUID `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToBannerResponse(a *domainAnnouncement.Announcement) BannerResponse {
+	return BannerResponse{
+		ID:                  a.ID,
+		Title:               a.Title,
+		Message:             a.Message,
+		Severity:            string(a.Severity),
+		StartsAt:            a.StartsAt,
+		EndsAt:              a.EndsAt,
+		MaintenanceStartsAt: a.MaintenanceStartsAt,
+		MaintenanceEndsAt:   a.MaintenanceEndsAt,
+	}
+}
+
+func ToAnnouncementResponse(a *domainAnnouncement.Announcement, now time.Time) *AnnouncementResponse {
+	return &AnnouncementResponse{
+		BannerResponse: ToBannerResponse(a),
+		Status:         string(a.Status(now)),
+		CreatedBy:      a.CreatedBy,
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+	}
+}
@@ -0,0 +1,234 @@
+package announcement
+
+import (
+	domainAnnouncement "cargo-tracker/internal/domain/announcement"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/pkg/cache"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// bannerCacheTTL is how long the unexpired announcements are served from
+// memory; every client polls the banner endpoint
+const bannerCacheTTL = 30 * time.Second
+
+// Service lets admins announce planned maintenance and other operational
+// news. Announcements are shown during their window and expire on their own
+// once it has passed.
+type Service struct {
+	repo    domainAnnouncement.Repository
+	pending *cache.Value[[]*domainAnnouncement.Announcement]
+}
+
+// NewService creates a new announcement service
+func NewService(repo domainAnnouncement.Repository) *Service {
+	s := &Service{repo: repo}
+	s.pending = cache.NewValue(func(ctx context.Context) ([]*domainAnnouncement.Announcement, error) {
+		return repo.ListUnexpired(ctx, time.Now())
+	}, bannerCacheTTL, 0)
+	return s
+}
+
+// ActiveBanners returns the announcements to show right now. Scheduled ones
+// are cached along with them and picked up as soon as their window opens.
+func (s *Service) ActiveBanners(ctx context.Context) ([]BannerResponse, error) {
+	announcements, err := s.pending.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	banners := make([]BannerResponse, 0, len(announcements))
+	for _, a := range announcements {
+		if a.Status(now) == domainAnnouncement.StatusActive {
+			banners = append(banners, ToBannerResponse(a))
+		}
+	}
+	return banners, nil
+}
+
+func (s *Service) CreateAnnouncement(ctx context.Context, adminID uuid.UUID, req *CreateAnnouncementRequest) (*AnnouncementResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	now := time.Now()
+	announcement := &domainAnnouncement.Announcement{
+		ID:                  uuid.New(),
+		Title:               req.Title,
+		Message:             req.Message,
+		Severity:            domainAnnouncement.SeverityInfo,
+		StartsAt:            now,
+		EndsAt:              req.EndsAt,
+		MaintenanceStartsAt: req.MaintenanceStartsAt,
+		MaintenanceEndsAt:   req.MaintenanceEndsAt,
+		CreatedBy:           adminID,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if req.Severity != "" {
+		announcement.Severity = domainAnnouncement.Severity(req.Severity)
+	}
+	if req.StartsAt != nil {
+		announcement.StartsAt = *req.StartsAt
+	}
+	if err := validateWindows(announcement, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, announcement); err != nil {
+		return nil, err
+	}
+	s.pending.Invalidate()
+
+	logger.Info("Announcement created",
+		zap.String("announcement_id", announcement.ID.String()),
+		zap.String("severity", string(announcement.Severity)),
+		zap.Time("starts_at", announcement.StartsAt),
+		zap.Time("ends_at", announcement.EndsAt),
+		zap.String("created_by", adminID.String()),
+		zap.String("event", "announcement_created"),
+	)
+
+	return ToAnnouncementResponse(announcement, now), nil
+}
+
+// ListAnnouncements returns announcements newest first, expired ones only
+// when asked for
+func (s *Service) ListAnnouncements(ctx context.Context, query *AnnouncementQuery) ([]*AnnouncementResponse, error) {
+	now := time.Now()
+	announcements, err := s.repo.List(ctx, now, query.IncludeExpired)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*AnnouncementResponse, len(announcements))
+	for i, a := range announcements {
+		responses[i] = ToAnnouncementResponse(a, now)
+	}
+	return responses, nil
+}
+
+func (s *Service) UpdateAnnouncement(ctx context.Context, adminID, announcementID uuid.UUID, req *UpdateAnnouncementRequest) (*AnnouncementResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	announcement, err := s.repo.GetByID(ctx, announcementID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if announcement.Status(now) == domainAnnouncement.StatusExpired {
+		return nil, appErrors.NewAppError("ANNOUNCEMENT_EXPIRED", "Expired announcements cannot be changed", nil)
+	}
+
+	if req.Title != nil {
+		announcement.Title = *req.Title
+	}
+	if req.Message != nil {
+		announcement.Message = *req.Message
+	}
+	if req.Severity != nil {
+		announcement.Severity = domainAnnouncement.Severity(*req.Severity)
+	}
+	if req.StartsAt != nil {
+		announcement.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		announcement.EndsAt = *req.EndsAt
+	}
+	if req.MaintenanceStartsAt != nil {
+		announcement.MaintenanceStartsAt = req.MaintenanceStartsAt
+	}
+	if req.MaintenanceEndsAt != nil {
+		announcement.MaintenanceEndsAt = req.MaintenanceEndsAt
+	}
+	if err := validateWindows(announcement, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, announcement); err != nil {
+		return nil, err
+	}
+	s.pending.Invalidate()
+	announcement.UpdatedAt = now
+
+	logger.Info("Announcement updated",
+		zap.String("announcement_id", announcementID.String()),
+		zap.String("updated_by", adminID.String()),
+		zap.String("event", "announcement_updated"),
+	)
+
+	return ToAnnouncementResponse(announcement, now), nil
+}
+
+// EndAnnouncement takes an announcement down now, keeping it in the history
+func (s *Service) EndAnnouncement(ctx context.Context, adminID, announcementID uuid.UUID) (*AnnouncementResponse, error) {
+	announcement, err := s.repo.GetByID(ctx, announcementID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch announcement.Status(now) {
+	case domainAnnouncement.StatusExpired:
+		return nil, appErrors.NewAppError("ANNOUNCEMENT_EXPIRED", "Announcement has already ended", nil)
+	case domainAnnouncement.StatusScheduled:
+		// Never shown; collapse the window so it never will be
+		announcement.StartsAt = now.Add(-time.Second)
+	}
+	announcement.EndsAt = now
+
+	if err := s.repo.Update(ctx, announcement); err != nil {
+		return nil, err
+	}
+	s.pending.Invalidate()
+	announcement.UpdatedAt = now
+
+	logger.Info("Announcement ended",
+		zap.String("announcement_id", announcementID.String()),
+		zap.String("ended_by", adminID.String()),
+		zap.String("event", "announcement_ended"),
+	)
+
+	return ToAnnouncementResponse(announcement, now), nil
+}
+
+func (s *Service) DeleteAnnouncement(ctx context.Context, adminID, announcementID uuid.UUID) error {
+	if err := s.repo.Delete(ctx, announcementID); err != nil {
+		return err
+	}
+	s.pending.Invalidate()
+
+	logger.Info("Announcement deleted",
+		zap.String("announcement_id", announcementID.String()),
+		zap.String("deleted_by", adminID.String()),
+		zap.String("event", "announcement_deleted"),
+	)
+	return nil
+}
+
+// validateWindows checks the display and maintenance windows of a new or
+// changed announcement
+func validateWindows(a *domainAnnouncement.Announcement, now time.Time) error {
+	if !a.EndsAt.After(a.StartsAt) {
+		return appErrors.NewAppError("INVALID_WINDOW", "ends_at must be after starts_at", nil)
+	}
+	if !a.EndsAt.After(now) {
+		return appErrors.NewAppError("INVALID_WINDOW", "ends_at must be in the future", nil)
+	}
+	if (a.MaintenanceStartsAt == nil) != (a.MaintenanceEndsAt == nil) {
+		return appErrors.NewAppError("INVALID_WINDOW", "maintenance_starts_at and maintenance_ends_at must be set together", nil)
+	}
+	if a.MaintenanceStartsAt != nil && !a.MaintenanceEndsAt.After(*a.MaintenanceStartsAt) {
+		return appErrors.NewAppError("INVALID_WINDOW", "maintenance_ends_at must be after maintenance_starts_at", nil)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cleanup
+
+import (
+	domainCleanup "cargo-tracker/internal/domain/cleanup"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type StartJobRequest struct {
+	Target        domainCleanup.Target `json:"target" validate:"required,oneof=shipments devices users"`
+	CreatedAfter  *time.Time           `json:"created_after"`
+	CreatedBefore *time.Time           `json:"created_before"`
+	SandboxOnly   bool                 `json:"sandbox_only"`
+	Status        string               `json:"status" validate:"omitempty,max=50"`
+	Role          string               `json:"role" validate:"omitempty,oneof=customer provider shipper"`
+	Search        string               `json:"search" validate:"omitempty,max=100"`
+	// Only count the matching records
+	DryRun bool `json:"dry_run"`
+}
+
+// Response DTOs
+type JobResponse struct {
+	ID          uuid.UUID               `json:"id"`
+	Target      domainCleanup.Target    `json:"target"`
+	Filter      domainCleanup.Filter    `json:"filter"`
+	DryRun      bool                    `json:"dry_run"`
+	Status      domainCleanup.JobStatus `json:"status"`
+	Matched     int64                   `json:"matched"`
+	Deleted     int64                   `json:"deleted"`
+	Skipped     int64                   `json:"skipped"`
+	LastError   *string                 `json:"last_error,omitempty"`
+	RequestedBy uuid.UUID               `json:"requested_by"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+	FinishedAt  *time.Time              `json:"finished_at,omitempty"`
+}
+
+func ToJobResponse(j *domainCleanup.Job) *JobResponse {
+	return &JobResponse{
+		ID:          j.ID,
+		Target:      j.Target,
+		Filter:      j.Filter,
+		DryRun:      j.DryRun,
+		Status:      j.Status,
+		Matched:     j.Matched,
+		Deleted:     j.Deleted,
+		Skipped:     j.Skipped,
+		LastError:   j.LastError,
+		RequestedBy: j.RequestedBy,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+		FinishedAt:  j.FinishedAt,
+	}
+}
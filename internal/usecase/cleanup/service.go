@@ -0,0 +1,282 @@
+package cleanup
+
+import (
+	"cargo-tracker/internal/config"
+	domainCleanup "cargo-tracker/internal/domain/cleanup"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxListedJobs bounds the job history returned to admins
+const maxListedJobs = 50
+
+// Service deletes test data in bulk. Jobs run in the background in batches
+// with a pause in between, and admins poll them until they finish. Only one
+// job runs at a time.
+type Service struct {
+	repo        domainCleanup.Repository
+	store       domainStorage.Store
+	config      config.CleanupConfig
+	environment string
+
+	mu      sync.Mutex
+	running map[uuid.UUID]context.CancelFunc
+}
+
+// NewService creates a new cleanup service
+func NewService(repo domainCleanup.Repository, store domainStorage.Store, cfg config.CleanupConfig, environment string) *Service {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &Service{
+		repo:        repo,
+		store:       store,
+		config:      cfg,
+		environment: environment,
+		running:     make(map[uuid.UUID]context.CancelFunc),
+	}
+}
+
+// Enabled reports whether bulk cleanup may run. It never may in production.
+func (s *Service) Enabled() bool {
+	return s.config.Enabled && s.environment != "production"
+}
+
+// StartJob counts the records matching the request and starts deleting them
+// in the background. A dry run only counts them.
+func (s *Service) StartJob(ctx context.Context, adminID uuid.UUID, req *StartJobRequest) (*JobResponse, error) {
+	if !s.Enabled() {
+		return nil, domainCleanup.ErrDisabled
+	}
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedBefore.After(*req.CreatedAfter) {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "created_before must be after created_after", nil)
+	}
+
+	now := time.Now()
+	job := &domainCleanup.Job{
+		ID:     uuid.New(),
+		Target: req.Target,
+		Filter: domainCleanup.Filter{
+			CreatedAfter:  req.CreatedAfter,
+			CreatedBefore: req.CreatedBefore,
+			SandboxOnly:   req.SandboxOnly,
+			Status:        req.Status,
+			Role:          req.Role,
+			Search:        utils.NormalizeSearch(req.Search),
+		},
+		DryRun:      req.DryRun,
+		Status:      domainCleanup.JobRunning,
+		RequestedBy: adminID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	// The requesting admin never deletes their own account
+	exclude := []uuid.UUID{adminID}
+	matched, err := s.repo.Count(ctx, job.Target, &job.Filter, exclude)
+	if err != nil {
+		return nil, err
+	}
+	job.Matched = matched
+
+	if job.DryRun {
+		job.Status = domainCleanup.JobCompleted
+		job.FinishedAt = &now
+		if err := s.repo.CreateJob(ctx, job); err != nil {
+			return nil, err
+		}
+		return ToJobResponse(job), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.running) > 0 {
+		return nil, domainCleanup.ErrJobRunning
+	}
+	if err := s.repo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// Detached from the request, which ends as soon as the job is queued
+	jobCtx, cancel := context.WithCancel(context.Background())
+	s.running[job.ID] = cancel
+	go s.run(jobCtx, job, exclude)
+
+	logger.Info("Cleanup job started",
+		zap.String("job_id", job.ID.String()),
+		zap.String("target", string(job.Target)),
+		zap.Int64("matched", job.Matched),
+		zap.String("admin_id", adminID.String()),
+		zap.String("event", "cleanup_job_started"),
+	)
+
+	return ToJobResponse(job), nil
+}
+
+// GetJob returns the progress of a job
+func (s *Service) GetJob(ctx context.Context, jobID uuid.UUID) (*JobResponse, error) {
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return ToJobResponse(job), nil
+}
+
+// ListJobs returns the most recent jobs
+func (s *Service) ListJobs(ctx context.Context) ([]*JobResponse, error) {
+	jobs, err := s.repo.ListJobs(ctx, maxListedJobs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*JobResponse, len(jobs))
+	for i, j := range jobs {
+		responses[i] = ToJobResponse(j)
+	}
+	return responses, nil
+}
+
+// CancelJob stops a running job after its current batch. Deleted records
+// are not restored.
+func (s *Service) CancelJob(ctx context.Context, jobID uuid.UUID) (*JobResponse, error) {
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	cancel, ok := s.running[jobID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, appErrors.NewAppError("JOB_FINISHED", "Cleanup job is not running", nil)
+	}
+	cancel()
+
+	return ToJobResponse(job), nil
+}
+
+// RecoverInterruptedJobs fails jobs that were running when the process
+// stopped, so they do not appear to run forever
+func (s *Service) RecoverInterruptedJobs(ctx context.Context) {
+	if !s.Enabled() {
+		return
+	}
+
+	failed, err := s.repo.FailRunning(ctx, "interrupted by a server restart")
+	if err != nil {
+		logger.Error("Failed to recover interrupted cleanup jobs", zap.Error(err))
+		return
+	}
+	if failed > 0 {
+		logger.Warn("Interrupted cleanup jobs marked as failed",
+			zap.Int64("count", failed),
+			zap.String("event", "cleanup_jobs_interrupted"),
+		)
+	}
+}
+
+// run deletes the matching records batch by batch until none are left, the
+// job is cancelled or a batch fails
+func (s *Service) run(ctx context.Context, job *domainCleanup.Job, exclude []uuid.UUID) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.mu.Unlock()
+	}()
+
+	err := s.deleteBatches(ctx, job, exclude)
+	switch {
+	case ctx.Err() != nil:
+		job.Status = domainCleanup.JobCancelled
+	case err != nil:
+		job.Status = domainCleanup.JobFailed
+		message := err.Error()
+		job.LastError = &message
+	default:
+		job.Status = domainCleanup.JobCompleted
+	}
+
+	now := time.Now()
+	job.FinishedAt = &now
+	s.saveProgress(job)
+
+	logger.Info("Cleanup job finished",
+		zap.String("job_id", job.ID.String()),
+		zap.String("target", string(job.Target)),
+		zap.String("status", string(job.Status)),
+		zap.Int64("deleted", job.Deleted),
+		zap.Int64("skipped", job.Skipped),
+		zap.String("event", "cleanup_job_finished"),
+	)
+}
+
+func (s *Service) deleteBatches(ctx context.Context, job *domainCleanup.Job, exclude []uuid.UUID) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Skipped records still match the filter and are excluded from
+		// later batches
+		ids, err := s.repo.NextBatch(ctx, job.Target, &job.Filter, exclude, s.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		result, err := s.repo.DeleteBatch(ctx, job.Target, ids)
+		if err != nil {
+			return err
+		}
+		exclude = append(exclude, result.Skipped...)
+		s.deleteFiles(result.StorageKeys)
+
+		job.Deleted += result.Deleted
+		job.Skipped += int64(len(result.Skipped))
+		job.UpdatedAt = time.Now()
+		s.saveProgress(job)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.config.BatchPause):
+		}
+	}
+}
+
+// deleteFiles removes stored attachments of deleted shipments. Files that
+// cannot be removed are orphaned but no longer reachable.
+func (s *Service) deleteFiles(keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(context.Background(), key); err != nil {
+			logger.Warn("Failed to delete stored file of cleaned up shipment",
+				zap.String("storage_key", key),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// saveProgress records the job state. It runs detached from the job context
+// so the final state is stored even after a cancellation.
+func (s *Service) saveProgress(job *domainCleanup.Job) {
+	if err := s.repo.UpdateJob(context.Background(), job); err != nil {
+		logger.Error("Failed to save cleanup job progress",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
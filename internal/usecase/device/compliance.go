@@ -0,0 +1,213 @@
+package device
+
+import (
+	"bytes"
+	domainDevice "cargo-tracker/internal/domain/device"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FleetComplianceRequest struct {
+	// Overrides the configured target firmware for this report
+	TargetFirmware string `form:"target_firmware" validate:"omitempty,max=100"`
+}
+
+type FleetComplianceResponse struct {
+	TargetFirmware   string    `json:"target_firmware"`
+	GeneratedAt      time.Time `json:"generated_at"`
+	TotalDevices     int       `json:"total_devices"`
+	CompliantDevices int       `json:"compliant_devices"`
+	ComplianceRate   float64   `json:"compliance_rate"`
+
+	// ByFirmware has one group per reported version, newest first, with
+	// devices that never reported one last
+	ByFirmware []FirmwareGroup                    `json:"by_firmware"`
+	ByBattery  map[domainDevice.BatteryHealth]int `json:"by_battery"`
+
+	// UpgradeTargets are the devices behind the target firmware, the ones an
+	// OTA rollout to it has to reach
+	UpgradeTargets []uuid.UUID `json:"upgrade_targets"`
+}
+
+type FirmwareGroup struct {
+	FirmwareVersion *string                     `json:"firmware_version"`
+	Status          domainDevice.FirmwareStatus `json:"status"`
+	DeviceCount     int                         `json:"device_count"`
+	Devices         []FleetDevice               `json:"devices"`
+}
+
+type FleetDevice struct {
+	ID             uuid.UUID                   `json:"id"`
+	HardwareUID    string                      `json:"hardware_uid"`
+	Model          *string                     `json:"model"`
+	OwnerShipperID *uuid.UUID                  `json:"owner_shipper_id"`
+	Status         domainDevice.DeviceStatus   `json:"status"`
+	FirmwareStatus domainDevice.FirmwareStatus `json:"firmware_status"`
+	BatteryLevel   *int                        `json:"battery_level"`
+	BatteryHealth  domainDevice.BatteryHealth  `json:"battery_health"`
+	LastSeenAt     *time.Time                  `json:"last_seen_at"`
+	// Compliant devices run the target firmware or newer on a good battery
+	Compliant bool `json:"compliant"`
+}
+
+// GetFleetCompliance reports the firmware and battery health of the fleet
+// against the target firmware: every active device for admins, their own
+// devices for shippers (ownerID set)
+func (s *Service) GetFleetCompliance(ctx context.Context, ownerID *uuid.UUID, req *FleetComplianceRequest) (*FleetComplianceResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	target := strings.TrimSpace(req.TargetFirmware)
+	if target == "" {
+		target = s.fleet.TargetFirmware
+	}
+
+	devices, err := s.deviceRepo.ListFleet(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &FleetComplianceResponse{
+		TargetFirmware: target,
+		GeneratedAt:    time.Now(),
+		TotalDevices:   len(devices),
+		ByFirmware:     []FirmwareGroup{},
+		ByBattery:      map[domainDevice.BatteryHealth]int{},
+		UpgradeTargets: []uuid.UUID{},
+	}
+
+	groups := map[string]*FirmwareGroup{}
+	for _, d := range devices {
+		fd := s.toFleetDevice(d, target)
+		resp.ByBattery[fd.BatteryHealth]++
+		if fd.Compliant {
+			resp.CompliantDevices++
+		}
+		if fd.FirmwareStatus == domainDevice.FirmwareOutdated {
+			resp.UpgradeTargets = append(resp.UpgradeTargets, d.ID)
+		}
+
+		key := ""
+		if fd.FirmwareStatus != domainDevice.FirmwareUnknown {
+			key = strings.TrimSpace(*d.FirmwareVersion)
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &FirmwareGroup{Status: fd.FirmwareStatus, Devices: []FleetDevice{}}
+			if key != "" {
+				version := key
+				group.FirmwareVersion = &version
+			}
+			groups[key] = group
+		}
+		group.Devices = append(group.Devices, fd)
+		group.DeviceCount++
+	}
+
+	for _, group := range groups {
+		resp.ByFirmware = append(resp.ByFirmware, *group)
+	}
+	sort.Slice(resp.ByFirmware, func(i, j int) bool {
+		a, b := resp.ByFirmware[i].FirmwareVersion, resp.ByFirmware[j].FirmwareVersion
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return domainDevice.CompareFirmware(*a, *b) > 0
+	})
+	if resp.TotalDevices > 0 {
+		resp.ComplianceRate = float64(resp.CompliantDevices) / float64(resp.TotalDevices) * 100
+	}
+	return resp, nil
+}
+
+// ExportFleetComplianceCSV returns the compliance report as CSV, one row per
+// device
+func (s *Service) ExportFleetComplianceCSV(ctx context.Context, ownerID *uuid.UUID, req *FleetComplianceRequest) ([]byte, error) {
+	report, err := s.GetFleetCompliance(ctx, ownerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"device_id", "hardware_uid", "model", "owner_shipper_id", "status",
+		"firmware_version", "target_firmware", "firmware_status", "battery_level", "battery_health",
+		"last_seen_at", "compliant"})
+	for _, group := range report.ByFirmware {
+		for _, d := range group.Devices {
+			_ = w.Write([]string{
+				d.ID.String(),
+				d.HardwareUID,
+				stringOrEmpty(d.Model),
+				uuidOrEmpty(d.OwnerShipperID),
+				string(d.Status),
+				stringOrEmpty(group.FirmwareVersion),
+				report.TargetFirmware,
+				string(d.FirmwareStatus),
+				intOrEmpty(d.BatteryLevel),
+				string(d.BatteryHealth),
+				timeOrEmpty(d.LastSeenAt),
+				strconv.FormatBool(d.Compliant),
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Service) toFleetDevice(d *domainDevice.Device, target string) FleetDevice {
+	firmware := domainDevice.ClassifyFirmware(d.FirmwareVersion, target)
+	battery := domainDevice.ClassifyBattery(d.BatteryLevel, s.fleet.LowBatteryPercent, s.fleet.CriticalBatteryPercent)
+	return FleetDevice{
+		ID:             d.ID,
+		HardwareUID:    d.HardwareUID,
+		Model:          d.Model,
+		OwnerShipperID: d.OwnerShipperID,
+		Status:         d.Status,
+		FirmwareStatus: firmware,
+		BatteryLevel:   d.BatteryLevel,
+		BatteryHealth:  battery,
+		LastSeenAt:     d.LastSeenAt,
+		Compliant:      firmware == domainDevice.FirmwareCurrent && battery == domainDevice.BatteryGood,
+	}
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func uuidOrEmpty(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func intOrEmpty(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func timeOrEmpty(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,97 @@
+package device
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/logger"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type DeviceCredentialResponse struct {
+	DeviceID    uuid.UUID `json:"device_id"`
+	HardwareUID string    `json:"hardware_uid"`
+	// Credential is only ever returned here; flash it onto the device
+	Credential string    `json:"credential"`
+	IssuedAt   time.Time `json:"issued_at"`
+}
+
+// IssueCredential generates the secret a device authenticates to the device
+// API with. Issuing a new one revokes the previous one.
+func (s *Service) IssueCredential(ctx context.Context, deviceID uuid.UUID) (*DeviceCredentialResponse, error) {
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.Status == domainDevice.StatusRetired {
+		return nil, domainDevice.ErrDeviceRetired
+	}
+
+	credential, err := generateDeviceCredential()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate device credential: %w", err)
+	}
+	if err := s.deviceRepo.SetCredentialHash(ctx, deviceID, hashDeviceCredential(credential)); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Device credential issued",
+		zap.String("device_id", deviceID.String()),
+		zap.String("event", "device_credential_issued"),
+	)
+
+	return &DeviceCredentialResponse{
+		DeviceID:    deviceID,
+		HardwareUID: device.HardwareUID,
+		Credential:  credential,
+		IssuedAt:    time.Now(),
+	}, nil
+}
+
+// AuthenticateDevice returns the device holding credential. Retired devices
+// are refused. A successful call counts as the device being seen.
+func (s *Service) AuthenticateDevice(ctx context.Context, credential string) (uuid.UUID, error) {
+	if credential == "" {
+		return uuid.Nil, domainDevice.ErrInvalidCredential
+	}
+
+	device, err := s.deviceRepo.GetByCredentialHash(ctx, hashDeviceCredential(credential))
+	if errors.Is(err, domainDevice.ErrDeviceNotFound) {
+		return uuid.Nil, domainDevice.ErrInvalidCredential
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if device.Status == domainDevice.StatusRetired {
+		return uuid.Nil, domainDevice.ErrInvalidCredential
+	}
+
+	if err := s.deviceRepo.UpdateLastSeen(ctx, device.ID); err != nil {
+		logger.Warn("Failed to update device last seen",
+			zap.String("device_id", device.ID.String()),
+			zap.Error(err),
+		)
+	}
+	return device.ID, nil
+}
+
+func generateDeviceCredential() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashDeviceCredential(credential string) string {
+	sum := sha256.Sum256([]byte(credential))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,246 @@
+package device
+
+import (
+	"bytes"
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// decommissionArchiveVersion is bumped when the archive layout changes
+const decommissionArchiveVersion = 1
+
+// DecommissionService retires devices for good. Before a device is
+// anonymized its history is written to object storage and a certificate
+// pointing at the archive is recorded.
+type DecommissionService struct {
+	deviceRepo       domainDevice.Repository
+	decommissionRepo domainDevice.DecommissionRepository
+	shipmentRepo     domainShipment.Repository
+	tripRepo         domainShipment.TripRepository
+	store            domainStorage.Store
+}
+
+// NewDecommissionService creates a new decommission service
+func NewDecommissionService(
+	deviceRepo domainDevice.Repository,
+	decommissionRepo domainDevice.DecommissionRepository,
+	shipmentRepo domainShipment.Repository,
+	tripRepo domainShipment.TripRepository,
+	store domainStorage.Store,
+) *DecommissionService {
+	return &DecommissionService{
+		deviceRepo:       deviceRepo,
+		decommissionRepo: decommissionRepo,
+		shipmentRepo:     shipmentRepo,
+		tripRepo:         tripRepo,
+		store:            store,
+	}
+}
+
+// decommissionArchive is the JSON document stored for a retired device
+type decommissionArchive struct {
+	Version     int                       `json:"version"`
+	GeneratedAt time.Time                 `json:"generated_at"`
+	Reason      string                    `json:"reason"`
+	Device      *DeviceResponse           `json:"device"`
+	Shipments   []archivedShipment        `json:"shipments"`
+	Telemetry   decommissionTelemetryNote `json:"telemetry"`
+}
+
+type archivedShipment struct {
+	ID               uuid.UUID                     `json:"id"`
+	Status           domainShipment.ShipmentStatus `json:"status"`
+	CustomerID       uuid.UUID                     `json:"customer_id"`
+	ProviderID       uuid.UUID                     `json:"provider_id"`
+	ShipperID        *uuid.UUID                    `json:"shipper_id,omitempty"`
+	PackageTracker   bool                          `json:"package_tracker"`
+	ActualPickupAt   *time.Time                    `json:"actual_pickup_at,omitempty"`
+	ActualDeliveryAt *time.Time                    `json:"actual_delivery_at,omitempty"`
+	CreatedAt        time.Time                     `json:"created_at"`
+}
+
+// decommissionTelemetryNote records that no readings are stored by the
+// platform, so consumers of the archive do not mistake the absence for loss
+type decommissionTelemetryNote struct {
+	Records int    `json:"records"`
+	Note    string `json:"note"`
+}
+
+// Decommission checks that a device is not in use, exports its history,
+// anonymizes it and records the certificate
+func (s *DecommissionService) Decommission(ctx context.Context, deviceID, adminID uuid.UUID, req *DecommissionRequest) (*DecommissionResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.decommissionRepo.GetByDevice(ctx, deviceID); err == nil {
+		return nil, domainDevice.ErrAlreadyDecommissioned
+	} else if !errors.Is(err, domainDevice.ErrDecommissionNotFound) {
+		return nil, err
+	}
+
+	if err := s.checkNotInUse(ctx, device); err != nil {
+		return nil, err
+	}
+
+	shipments, err := s.shipmentHistory(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	archive := decommissionArchive{
+		Version:     decommissionArchiveVersion,
+		GeneratedAt: now,
+		Reason:      req.Reason,
+		Device:      ToDeviceResponse(device),
+		Shipments:   shipments,
+		Telemetry: decommissionTelemetryNote{
+			Note: "sensor readings are not retained by the platform",
+		},
+	}
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode decommission archive: %w", err)
+	}
+	sum := sha256.Sum256(data)
+
+	key := fmt.Sprintf("devices/%s/decommission-%d.json", deviceID, now.Unix())
+	size, err := s.store.Put(ctx, key, bytes.NewReader(data), "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to store decommission archive: %w", err)
+	}
+
+	// Only anonymize once the history is safely stored
+	if err := s.deviceRepo.Anonymize(ctx, deviceID); err != nil {
+		return nil, err
+	}
+
+	certificate := &domainDevice.Decommission{
+		DeviceID:         deviceID,
+		HardwareUID:      device.HardwareUID,
+		Reason:           req.Reason,
+		DecommissionedBy: adminID,
+		OwnerUnlinked:    device.OwnerShipperID != nil,
+		ShipmentCount:    len(shipments),
+		ArchiveKey:       key,
+		ArchiveSHA256:    hex.EncodeToString(sum[:]),
+		ArchiveSize:      size,
+	}
+	if err := s.decommissionRepo.Create(ctx, certificate); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Device decommissioned",
+		zap.String("device_id", deviceID.String()),
+		zap.String("hardware_uid", device.HardwareUID),
+		zap.String("admin_id", adminID.String()),
+		zap.Int("shipments", len(shipments)),
+		zap.String("archive_key", key),
+		zap.String("event", "device_decommissioned"),
+	)
+
+	return ToDecommissionResponse(certificate), nil
+}
+
+// GetCertificate returns the decommission certificate of a device
+func (s *DecommissionService) GetCertificate(ctx context.Context, deviceID uuid.UUID) (*DecommissionResponse, error) {
+	certificate, err := s.decommissionRepo.GetByDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return ToDecommissionResponse(certificate), nil
+}
+
+// OpenArchive opens the history archive of a decommissioned device
+func (s *DecommissionService) OpenArchive(ctx context.Context, deviceID uuid.UUID) (io.ReadCloser, *DecommissionResponse, error) {
+	certificate, err := s.decommissionRepo.GetByDevice(ctx, deviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Open(ctx, certificate.ArchiveKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, ToDecommissionResponse(certificate), nil
+}
+
+// checkNotInUse refuses devices that still track a shipment or trip
+func (s *DecommissionService) checkNotInUse(ctx context.Context, device *domainDevice.Device) error {
+	if device.Status == domainDevice.StatusInTransit {
+		return appErrors.NewAppError("DEVICE_IN_USE", "Cannot decommission a device in transit", nil)
+	}
+
+	if _, err := s.tripRepo.FindActiveByDevice(ctx, device.ID); err == nil {
+		return appErrors.NewAppError("DEVICE_IN_USE", "Device is assigned to an active trip", nil)
+	} else if !errors.Is(err, domainShipment.ErrTripNotFound) {
+		return err
+	}
+
+	if _, err := s.shipmentRepo.ResolveDevice(ctx, device.ID); err == nil {
+		return appErrors.NewAppError("DEVICE_IN_USE", "Device is assigned to an active shipment", nil)
+	} else if !errors.Is(err, domainShipment.ErrDeviceNotAssigned) {
+		return err
+	}
+
+	return nil
+}
+
+// shipmentHistory lists every shipment the device tracked, either as the
+// shipment tracker or as a package tracker
+func (s *DecommissionService) shipmentHistory(ctx context.Context, deviceID uuid.UUID) ([]archivedShipment, error) {
+	const pageSize = 100
+
+	var history []archivedShipment
+	for page := 1; ; page++ {
+		shipments, total, err := s.shipmentRepo.List(ctx, &domainShipment.Filter{
+			DeviceID:  &deviceID,
+			Page:      page,
+			PageSize:  pageSize,
+			SortBy:    "created_at",
+			SortOrder: "asc",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list device shipments: %w", err)
+		}
+
+		for _, sh := range shipments {
+			history = append(history, archivedShipment{
+				ID:               sh.ID,
+				Status:           sh.Status,
+				CustomerID:       sh.CustomerID,
+				ProviderID:       sh.ProviderID,
+				ShipperID:        sh.ShipperID,
+				PackageTracker:   sh.LinkedDeviceID == nil || *sh.LinkedDeviceID != deviceID,
+				ActualPickupAt:   sh.ActualPickupAt,
+				ActualDeliveryAt: sh.ActualDeliveryAt,
+				CreatedAt:        sh.CreatedAt,
+			})
+		}
+
+		if len(shipments) < pageSize || int64(len(history)) >= total {
+			return history, nil
+		}
+	}
+}
@@ -70,8 +70,12 @@ type DeviceResponse struct {
 	TotalTrips        int                       `json:"total_trips"`
 	LastSeenAt        *time.Time                `json:"last_seen_at"`
 	IsOnline          bool                      `json:"is_online"`
-	CreatedAt         time.Time                 `json:"created_at"`
-	UpdatedAt         time.Time                 `json:"updated_at"`
+	// When the device API credential was issued; null if it has none
+	CredentialIssuedAt *time.Time `json:"credential_issued_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	// Sensors of the model; null when the model has no profile
+	Capabilities []domainDevice.Capability `json:"capabilities"`
 }
 
 type DeviceListResponse struct {
@@ -115,20 +119,22 @@ func ToDeviceResponse(d *domainDevice.Device) *DeviceResponse {
 		return nil
 	}
 	return &DeviceResponse{
-		ID:                d.ID,
-		HardwareUID:       d.HardwareUID,
-		DeviceName:        d.DeviceName,
-		Model:             d.Model,
-		OwnerShipperID:    d.OwnerShipperID,
-		CurrentShipmentID: d.CurrentShipmentID,
-		Status:            d.Status,
-		FirmwareVersion:   d.FirmwareVersion,
-		BatteryLevel:      d.BatteryLevel,
-		TotalTrips:        d.TotalTrips,
-		LastSeenAt:        d.LastSeenAt,
-		IsOnline:          d.IsOnline(),
-		CreatedAt:         d.CreatedAt,
-		UpdatedAt:         d.UpdatedAt,
+		ID:                 d.ID,
+		HardwareUID:        d.HardwareUID,
+		DeviceName:         d.DeviceName,
+		Model:              d.Model,
+		OwnerShipperID:     d.OwnerShipperID,
+		CurrentShipmentID:  d.CurrentShipmentID,
+		Status:             d.Status,
+		FirmwareVersion:    d.FirmwareVersion,
+		BatteryLevel:       d.BatteryLevel,
+		TotalTrips:         d.TotalTrips,
+		LastSeenAt:         d.LastSeenAt,
+		IsOnline:           d.IsOnline(),
+		CredentialIssuedAt: d.CredentialIssuedAt,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
+		Capabilities:       d.Capabilities,
 	}
 }
 
@@ -173,3 +179,87 @@ func ToStatisticsResponse(s *domainDevice.Statistics) *DeviceStatisticsResponse
 		OfflineDevices:     s.OfflineDevices,
 	}
 }
+
+// Decommission DTOs
+type DecommissionRequest struct {
+	Reason string `json:"reason" validate:"required,min=5,max=500"`
+}
+
+type DecommissionResponse struct {
+	ID               uuid.UUID `json:"id"`
+	DeviceID         uuid.UUID `json:"device_id"`
+	HardwareUID      string    `json:"hardware_uid"`
+	Reason           string    `json:"reason"`
+	DecommissionedBy uuid.UUID `json:"decommissioned_by"`
+	OwnerUnlinked    bool      `json:"owner_unlinked"`
+	ShipmentCount    int       `json:"shipment_count"`
+	ArchiveSHA256    string    `json:"archive_sha256"`
+	ArchiveSize      int64     `json:"archive_size"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func ToDecommissionResponse(d *domainDevice.Decommission) *DecommissionResponse {
+	return &DecommissionResponse{
+		ID:               d.ID,
+		DeviceID:         d.DeviceID,
+		HardwareUID:      d.HardwareUID,
+		Reason:           d.Reason,
+		DecommissionedBy: d.DecommissionedBy,
+		OwnerUnlinked:    d.OwnerUnlinked,
+		ShipmentCount:    d.ShipmentCount,
+		ArchiveSHA256:    d.ArchiveSHA256,
+		ArchiveSize:      d.ArchiveSize,
+		CreatedAt:        d.CreatedAt,
+	}
+}
+
+// Transfer DTOs
+type InitiateTransferRequest struct {
+	ToShipperID uuid.UUID `json:"to_shipper_id" validate:"required"`
+	Note        string    `json:"note" validate:"omitempty,max=500"`
+}
+
+type TransferResponse struct {
+	ID            uuid.UUID                   `json:"id"`
+	DeviceID      uuid.UUID                   `json:"device_id"`
+	FromShipperID uuid.UUID                   `json:"from_shipper_id"`
+	ToShipperID   uuid.UUID                   `json:"to_shipper_id"`
+	Status        domainDevice.TransferStatus `json:"status"`
+	Note          *string                     `json:"note,omitempty"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	RespondedAt   *time.Time                  `json:"responded_at,omitempty"`
+}
+
+func ToTransferResponse(t *domainDevice.Transfer) *TransferResponse {
+	return &TransferResponse{
+		ID:            t.ID,
+		DeviceID:      t.DeviceID,
+		FromShipperID: t.FromShipperID,
+		ToShipperID:   t.ToShipperID,
+		Status:        t.Status,
+		Note:          t.Note,
+		CreatedAt:     t.CreatedAt,
+		RespondedAt:   t.RespondedAt,
+	}
+}
+
+// Model profile DTOs
+type UpsertModelProfileRequest struct {
+	Capabilities []domainDevice.Capability `json:"capabilities" validate:"required,dive,oneof=temperature humidity light tilt impact gps"`
+}
+
+type ModelProfileResponse struct {
+	Model        string                    `json:"model"`
+	Capabilities []domainDevice.Capability `json:"capabilities"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+func ToModelProfileResponse(p *domainDevice.ModelProfile) *ModelProfileResponse {
+	return &ModelProfileResponse{
+		Model:        p.Model,
+		Capabilities: p.Capabilities,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
@@ -0,0 +1,104 @@
+package device
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ModelProfileService keeps the sensors each tracker model carries, which
+// decide whether a device can monitor a shipment's rules
+type ModelProfileService struct {
+	profileRepo domainDevice.ModelProfileRepository
+}
+
+// NewModelProfileService creates a new model profile service
+func NewModelProfileService(profileRepo domainDevice.ModelProfileRepository) *ModelProfileService {
+	return &ModelProfileService{profileRepo: profileRepo}
+}
+
+func (s *ModelProfileService) ListProfiles(ctx context.Context) ([]*ModelProfileResponse, error) {
+	profiles, err := s.profileRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ModelProfileResponse, len(profiles))
+	for i, p := range profiles {
+		responses[i] = ToModelProfileResponse(p)
+	}
+	return responses, nil
+}
+
+// SaveProfile sets the capabilities of a model, replacing any it had
+func (s *ModelProfileService) SaveProfile(ctx context.Context, adminID uuid.UUID, model string, req *UpsertModelProfileRequest) (*ModelProfileResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	model = strings.TrimSpace(model)
+	if model == "" || len(model) > 50 {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Model must be 1 to 50 characters", nil)
+	}
+
+	profile := &domainDevice.ModelProfile{
+		Model:        model,
+		Capabilities: uniqueCapabilities(req.Capabilities),
+	}
+	if err := s.profileRepo.Upsert(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Device model profile saved",
+		zap.String("model", model),
+		zap.Int("capabilities", len(profile.Capabilities)),
+		zap.String("saved_by", adminID.String()),
+		zap.String("event", "device_model_profile_saved"),
+	)
+
+	return ToModelProfileResponse(profile), nil
+}
+
+func (s *ModelProfileService) DeleteProfile(ctx context.Context, adminID uuid.UUID, model string) error {
+	if err := s.profileRepo.Delete(ctx, model); err != nil {
+		return err
+	}
+
+	logger.Info("Device model profile deleted",
+		zap.String("model", model),
+		zap.String("deleted_by", adminID.String()),
+		zap.String("event", "device_model_profile_deleted"),
+	)
+	return nil
+}
+
+// ParseCapabilities reads a comma separated capability list
+func ParseCapabilities(raw string) ([]domainDevice.Capability, error) {
+	var capabilities []domainDevice.Capability
+	for _, part := range strings.Split(raw, ",") {
+		c := domainDevice.Capability(strings.ToLower(strings.TrimSpace(part)))
+		if c == "" {
+			continue
+		}
+		if !c.IsValid() {
+			return nil, appErrors.NewAppError("VALIDATION_ERROR", "Unknown capability: "+string(c), nil)
+		}
+		capabilities = append(capabilities, c)
+	}
+	return uniqueCapabilities(capabilities), nil
+}
+
+func uniqueCapabilities(capabilities []domainDevice.Capability) []domainDevice.Capability {
+	unique := make([]domainDevice.Capability, 0, len(capabilities))
+	for _, c := range capabilities {
+		if len(domainDevice.Missing(unique, []domainDevice.Capability{c})) > 0 {
+			unique = append(unique, c)
+		}
+	}
+	return unique
+}
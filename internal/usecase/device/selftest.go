@@ -0,0 +1,228 @@
+package device
+
+import (
+	"cargo-tracker/internal/config"
+	domainDevice "cargo-tracker/internal/domain/device"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// selfTestHistory is how many self-tests of a device are listed
+const selfTestHistory = 20
+
+type SelfTestReportRequest struct {
+	SensorsOK *bool `json:"sensors_ok" validate:"required"`
+	// Readings are the sanity readings taken during the test, by sensor
+	Readings     map[string]float64 `json:"readings" validate:"omitempty,max=20"`
+	GPSFix       *bool              `json:"gps_fix" validate:"required"`
+	BatteryLevel *int               `json:"battery_level" validate:"required,min=0,max=100"`
+	SignalDBm    *int               `json:"signal_dbm" validate:"required,min=-150,max=0"`
+}
+
+type SelfTestResponse struct {
+	ID           uuid.UUID                   `json:"id"`
+	DeviceID     uuid.UUID                   `json:"device_id"`
+	RequestedBy  uuid.UUID                   `json:"requested_by"`
+	Status       domainDevice.SelfTestStatus `json:"status"`
+	RequestedAt  time.Time                   `json:"requested_at"`
+	CompletedAt  *time.Time                  `json:"completed_at"`
+	SensorsOK    *bool                       `json:"sensors_ok"`
+	Readings     map[string]float64          `json:"readings"`
+	GPSFix       *bool                       `json:"gps_fix"`
+	BatteryLevel *int                        `json:"battery_level"`
+	SignalDBm    *int                        `json:"signal_dbm"`
+	Failures     []string                    `json:"failures"`
+}
+
+func ToSelfTestResponse(t *domainDevice.SelfTest) *SelfTestResponse {
+	resp := &SelfTestResponse{
+		ID:           t.ID,
+		DeviceID:     t.DeviceID,
+		RequestedBy:  t.RequestedBy,
+		Status:       t.Status,
+		RequestedAt:  t.RequestedAt,
+		CompletedAt:  t.CompletedAt,
+		SensorsOK:    t.SensorsOK,
+		Readings:     t.Readings,
+		GPSFix:       t.GPSFix,
+		BatteryLevel: t.BatteryLevel,
+		SignalDBm:    t.SignalDBm,
+		Failures:     t.Failures,
+	}
+	if resp.Failures == nil {
+		resp.Failures = []string{}
+	}
+	return resp
+}
+
+// SelfTestService runs device self-tests. An admin or the owning shipper
+// requests one, the device picks it up from the device API, runs it and
+// reports the results, which pass or fail the test.
+type SelfTestService struct {
+	deviceRepo domainDevice.Repository
+	testRepo   domainDevice.SelfTestRepository
+	selfTest   config.SelfTestConfig
+	fleet      config.FleetConfig
+}
+
+// NewSelfTestService creates a new device self-test service
+func NewSelfTestService(
+	deviceRepo domainDevice.Repository,
+	testRepo domainDevice.SelfTestRepository,
+	selfTest config.SelfTestConfig,
+	fleet config.FleetConfig,
+) *SelfTestService {
+	return &SelfTestService{
+		deviceRepo: deviceRepo,
+		testRepo:   testRepo,
+		selfTest:   selfTest,
+		fleet:      fleet,
+	}
+}
+
+// RequestSelfTest asks a device to test itself. ownerID limits it to the
+// caller's own devices; it is nil for admins.
+func (s *SelfTestService) RequestSelfTest(ctx context.Context, requestedBy uuid.UUID, ownerID *uuid.UUID, deviceID uuid.UUID) (*SelfTestResponse, error) {
+	device, err := s.getDevice(ctx, ownerID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.Status == domainDevice.StatusRetired {
+		return nil, domainDevice.ErrDeviceRetired
+	}
+
+	test := &domainDevice.SelfTest{
+		DeviceID:    deviceID,
+		RequestedBy: requestedBy,
+		Status:      domainDevice.SelfTestPending,
+		RequestedAt: time.Now(),
+	}
+	if err := s.testRepo.Create(ctx, test); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Device self-test requested",
+		zap.String("self_test_id", test.ID.String()),
+		zap.String("device_id", deviceID.String()),
+		zap.String("requested_by", requestedBy.String()),
+		zap.String("event", "device_self_test_requested"),
+	)
+
+	return ToSelfTestResponse(test), nil
+}
+
+// ListSelfTests returns the recent self-tests of a device, newest first
+func (s *SelfTestService) ListSelfTests(ctx context.Context, ownerID *uuid.UUID, deviceID uuid.UUID) ([]SelfTestResponse, error) {
+	if _, err := s.getDevice(ctx, ownerID, deviceID); err != nil {
+		return nil, err
+	}
+
+	tests, err := s.testRepo.ListByDevice(ctx, deviceID, selfTestHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]SelfTestResponse, len(tests))
+	for i, t := range tests {
+		resp[i] = *ToSelfTestResponse(t)
+	}
+	return resp, nil
+}
+
+// GetPendingSelfTest returns the self-test the device should run, nil when
+// there is none
+func (s *SelfTestService) GetPendingSelfTest(ctx context.Context, deviceID uuid.UUID) (*SelfTestResponse, error) {
+	test, err := s.testRepo.GetPending(ctx, deviceID)
+	if errors.Is(err, domainDevice.ErrSelfTestNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ToSelfTestResponse(test), nil
+}
+
+// ReportSelfTest records the results of the device's pending self-test and
+// decides whether it passed. The reported battery level is also kept as the
+// device's current one.
+func (s *SelfTestService) ReportSelfTest(ctx context.Context, deviceID uuid.UUID, req *SelfTestReportRequest) (*SelfTestResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	test, err := s.testRepo.GetPending(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	test.CompletedAt = &now
+	test.SensorsOK = req.SensorsOK
+	test.Readings = req.Readings
+	test.GPSFix = req.GPSFix
+	test.BatteryLevel = req.BatteryLevel
+	test.SignalDBm = req.SignalDBm
+	test.Failures = s.evaluate(req)
+	test.Status = domainDevice.SelfTestPassed
+	if len(test.Failures) > 0 {
+		test.Status = domainDevice.SelfTestFailed
+	}
+
+	if err := s.testRepo.Complete(ctx, test); err != nil {
+		return nil, err
+	}
+	if err := s.deviceRepo.UpdateBattery(ctx, deviceID, *req.BatteryLevel); err != nil {
+		logger.Warn("Failed to update battery from self-test",
+			zap.String("device_id", deviceID.String()),
+			zap.Error(err),
+		)
+	}
+
+	logger.Info("Device self-test completed",
+		zap.String("self_test_id", test.ID.String()),
+		zap.String("device_id", deviceID.String()),
+		zap.String("status", string(test.Status)),
+		zap.Strings("failures", test.Failures),
+		zap.String("event", "device_self_test_completed"),
+	)
+
+	return ToSelfTestResponse(test), nil
+}
+
+// evaluate returns why the reported results fail the test, empty when they
+// pass
+func (s *SelfTestService) evaluate(req *SelfTestReportRequest) []string {
+	failures := []string{}
+	if !*req.SensorsOK {
+		failures = append(failures, "sensor readings are not sane")
+	}
+	if !*req.GPSFix {
+		failures = append(failures, "no GPS fix")
+	}
+	if domainDevice.ClassifyBattery(req.BatteryLevel, s.fleet.LowBatteryPercent, s.fleet.CriticalBatteryPercent) == domainDevice.BatteryCritical {
+		failures = append(failures, fmt.Sprintf("battery at %d%% is critical", *req.BatteryLevel))
+	}
+	if *req.SignalDBm < s.selfTest.MinSignalDBm {
+		failures = append(failures, fmt.Sprintf("signal of %d dBm is below %d dBm", *req.SignalDBm, s.selfTest.MinSignalDBm))
+	}
+	return failures
+}
+
+func (s *SelfTestService) getDevice(ctx context.Context, ownerID *uuid.UUID, deviceID uuid.UUID) (*domainDevice.Device, error) {
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != nil && (device.OwnerShipperID == nil || *device.OwnerShipperID != *ownerID) {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Shipper does not own this device", nil)
+	}
+	return device, nil
+}
@@ -1,9 +1,13 @@
 package device
 
 import (
+	"cargo-tracker/internal/config"
 	domainDevice "cargo-tracker/internal/domain/device"
+	domainQuota "cargo-tracker/internal/domain/quota"
 	domainUser "cargo-tracker/internal/domain/user"
 	"cargo-tracker/internal/logger"
+	usecaseQuota "cargo-tracker/internal/usecase/quota"
+	"cargo-tracker/pkg/cache"
 	appErrors "cargo-tracker/pkg/errors"
 	"cargo-tracker/pkg/utils"
 	"context"
@@ -17,14 +21,31 @@ import (
 type Service struct {
 	deviceRepo domainDevice.Repository
 	userRepo   domainUser.Repository
+
+	statistics   *cache.Value[*DeviceStatisticsResponse]
+	statsRefresh time.Duration
+	fleet        config.FleetConfig
+
+	// quotas caps the devices a shipper owns; nil disables it
+	quotas *usecaseQuota.Service
 }
 
 // NewService creates a new device service
-func NewService(deviceRepo domainDevice.Repository, userRepo domainUser.Repository) *Service {
-	return &Service{
-		deviceRepo: deviceRepo,
-		userRepo:   userRepo,
-	}
+func NewService(deviceRepo domainDevice.Repository, userRepo domainUser.Repository, statsCache config.StatsCacheConfig, fleet config.FleetConfig) *Service {
+	s := &Service{
+		deviceRepo:   deviceRepo,
+		userRepo:     userRepo,
+		statsRefresh: statsCache.RefreshInterval,
+		fleet:        fleet,
+	}
+	s.statistics = cache.NewValue(s.loadStatistics, statsCache.TTL, statsCache.Stale)
+	return s
+}
+
+// UseQuotas makes the service refuse to give a device to a shipper that is
+// already at the devices limit of their plan
+func (s *Service) UseQuotas(quotas *usecaseQuota.Service) {
+	s.quotas = quotas
 }
 
 func (s *Service) CreateDevice(ctx context.Context, req *CreateDeviceRequest) (*DeviceResponse, error) {
@@ -43,6 +64,9 @@ func (s *Service) CreateDevice(ctx context.Context, req *CreateDeviceRequest) (*
 		if err := ValidateShipperOwner(ctx, s.userRepo, *req.OwnerShipperID); err != nil {
 			return nil, err
 		}
+		if err := s.quotas.Check(ctx, *req.OwnerShipperID, domainQuota.MetricDevices); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create domain entity
@@ -214,6 +238,12 @@ func (s *Service) AssignOwner(ctx context.Context, deviceID uuid.UUID, req *Assi
 		return nil, appErrors.NewAppError("DEVICE_IN_USE", "Cannot assign owner while device is in transit", nil)
 	}
 
+	if !ownedBy(device, req.OwnerShipperID) {
+		if err := s.quotas.Check(ctx, req.OwnerShipperID, domainQuota.MetricDevices); err != nil {
+			return nil, err
+		}
+	}
+
 	// Assign owner
 	if err := s.deviceRepo.AssignOwner(ctx, deviceID, req.OwnerShipperID); err != nil {
 		return nil, err
@@ -399,6 +429,17 @@ func (s *Service) BulkAssignOwner(ctx context.Context, req *BulkAssignRequest) (
 			continue
 		}
 
+		if !ownedBy(device, req.OwnerShipperID) {
+			if err := s.quotas.Check(ctx, req.OwnerShipperID, domainQuota.MetricDevices); err != nil {
+				response.FailedCount++
+				response.Errors = append(response.Errors, BulkError{
+					DeviceID: deviceID,
+					Error:    err.Error(),
+				})
+				continue
+			}
+		}
+
 		// Assign owner
 		err = s.deviceRepo.AssignOwner(ctx, deviceID, req.OwnerShipperID)
 		if err != nil {
@@ -421,7 +462,19 @@ func (s *Service) BulkAssignOwner(ctx context.Context, req *BulkAssignRequest) (
 	return response, nil
 }
 
+// GetStatistics returns the fleet statistics, cached like the shipment
+// statistics since dashboards poll both
 func (s *Service) GetStatistics(ctx context.Context) (*DeviceStatisticsResponse, error) {
+	return s.statistics.Get(ctx)
+}
+
+// StartStatisticsRefresher keeps the cached statistics fresh while they are
+// being polled
+func (s *Service) StartStatisticsRefresher(ctx context.Context) {
+	s.statistics.StartRefresher(ctx, s.statsRefresh)
+}
+
+func (s *Service) loadStatistics(ctx context.Context) (*DeviceStatisticsResponse, error) {
 	stats, err := s.deviceRepo.GetStatistics(ctx)
 	if err != nil {
 		return nil, err
@@ -430,7 +483,9 @@ func (s *Service) GetStatistics(ctx context.Context) (*DeviceStatisticsResponse,
 	return ToStatisticsResponse(stats), nil
 }
 
-func (s *Service) GetAvailableDevices(ctx context.Context, shipperID *uuid.UUID) ([]DeviceResponse, error) {
+// GetAvailableDevices lists available devices, optionally only those whose
+// model can measure all of the given capabilities
+func (s *Service) GetAvailableDevices(ctx context.Context, shipperID *uuid.UUID, capabilities []domainDevice.Capability) ([]DeviceResponse, error) {
 	filter := &DeviceFilterRequest{
 		Status:   (*domainDevice.DeviceStatus)(utils.StringPtr(string(domainDevice.StatusAvailable))),
 		PageSize: 100,
@@ -440,7 +495,9 @@ func (s *Service) GetAvailableDevices(ctx context.Context, shipperID *uuid.UUID)
 		filter.OwnerShipperID = shipperID
 	}
 
-	devices, _, err := s.deviceRepo.List(ctx, ToDomainFilter(filter))
+	domainFilter := ToDomainFilter(filter)
+	domainFilter.Capabilities = capabilities
+	devices, _, err := s.deviceRepo.List(ctx, domainFilter)
 	if err != nil {
 		return nil, err
 	}
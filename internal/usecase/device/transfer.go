@@ -0,0 +1,214 @@
+package device
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainQuota "cargo-tracker/internal/domain/quota"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	usecaseQuota "cargo-tracker/internal/usecase/quota"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TransferService moves devices between shippers. The owner offers a
+// device, the recipient accepts it, and the device must not be tracking
+// anything while it changes hands. Past trips and shipments stay with the
+// shipper who ran them.
+type TransferService struct {
+	deviceRepo   domainDevice.Repository
+	transferRepo domainDevice.TransferRepository
+	userRepo     domainUser.Repository
+
+	// quotas caps the devices a shipper owns; nil disables it
+	quotas *usecaseQuota.Service
+}
+
+// NewTransferService creates a new device transfer service
+func NewTransferService(
+	deviceRepo domainDevice.Repository,
+	transferRepo domainDevice.TransferRepository,
+	userRepo domainUser.Repository,
+) *TransferService {
+	return &TransferService{
+		deviceRepo:   deviceRepo,
+		transferRepo: transferRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// UseQuotas makes the service refuse transfers to a shipper that is already at
+// the devices limit of their plan
+func (s *TransferService) UseQuotas(quotas *usecaseQuota.Service) {
+	s.quotas = quotas
+}
+
+// InitiateTransfer offers a device of the shipper to another shipper
+func (s *TransferService) InitiateTransfer(ctx context.Context, shipperID, deviceID uuid.UUID, req *InitiateTransferRequest) (*TransferResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.OwnerShipperID == nil || *device.OwnerShipperID != shipperID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Shipper does not own this device", nil)
+	}
+	if device.Status == domainDevice.StatusRetired {
+		return nil, appErrors.NewAppError("INVALID_STATUS", "Retired devices cannot be transferred", nil)
+	}
+	if req.ToShipperID == shipperID {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Device is already owned by this shipper", nil)
+	}
+	if err := ValidateShipperOwner(ctx, s.userRepo, req.ToShipperID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureIdle(ctx, device); err != nil {
+		return nil, err
+	}
+
+	transfer := &domainDevice.Transfer{
+		DeviceID:      deviceID,
+		FromShipperID: shipperID,
+		ToShipperID:   req.ToShipperID,
+		Status:        domainDevice.TransferPending,
+		CreatedAt:     time.Now(),
+	}
+	if req.Note != "" {
+		transfer.Note = &req.Note
+	}
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Device transfer initiated",
+		zap.String("transfer_id", transfer.ID.String()),
+		zap.String("device_id", deviceID.String()),
+		zap.String("from_shipper_id", shipperID.String()),
+		zap.String("to_shipper_id", req.ToShipperID.String()),
+		zap.String("event", "device_transfer_initiated"),
+	)
+
+	return ToTransferResponse(transfer), nil
+}
+
+// ListTransfers returns the transfers the shipper sent or received
+func (s *TransferService) ListTransfers(ctx context.Context, shipperID uuid.UUID) ([]TransferResponse, error) {
+	transfers, err := s.transferRepo.ListByShipper(ctx, shipperID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]TransferResponse, len(transfers))
+	for i, t := range transfers {
+		resp[i] = *ToTransferResponse(t)
+	}
+	return resp, nil
+}
+
+// AcceptTransfer makes the recipient the owner of the device
+func (s *TransferService) AcceptTransfer(ctx context.Context, shipperID, transferID uuid.UUID) (*TransferResponse, error) {
+	transfer, err := s.getTransfer(ctx, shipperID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToShipperID != shipperID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only the recipient can accept a transfer", nil)
+	}
+	if err := s.quotas.Check(ctx, shipperID, domainQuota.MetricDevices); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.transferRepo.Accept(ctx, transferID, now); err != nil {
+		return nil, err
+	}
+	transfer.Status = domainDevice.TransferAccepted
+	transfer.RespondedAt = &now
+
+	logger.Info("Device transfer accepted",
+		zap.String("transfer_id", transferID.String()),
+		zap.String("device_id", transfer.DeviceID.String()),
+		zap.String("from_shipper_id", transfer.FromShipperID.String()),
+		zap.String("to_shipper_id", transfer.ToShipperID.String()),
+		zap.String("event", "device_transfer_accepted"),
+	)
+
+	return ToTransferResponse(transfer), nil
+}
+
+// DeclineTransfer lets the recipient refuse a device
+func (s *TransferService) DeclineTransfer(ctx context.Context, shipperID, transferID uuid.UUID) (*TransferResponse, error) {
+	transfer, err := s.getTransfer(ctx, shipperID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToShipperID != shipperID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only the recipient can decline a transfer", nil)
+	}
+	return s.close(ctx, transfer, domainDevice.TransferDeclined)
+}
+
+// CancelTransfer lets the owner withdraw an offer
+func (s *TransferService) CancelTransfer(ctx context.Context, shipperID, transferID uuid.UUID) (*TransferResponse, error) {
+	transfer, err := s.getTransfer(ctx, shipperID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.FromShipperID != shipperID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only the owner can cancel a transfer", nil)
+	}
+	return s.close(ctx, transfer, domainDevice.TransferCancelled)
+}
+
+func (s *TransferService) close(ctx context.Context, transfer *domainDevice.Transfer, status domainDevice.TransferStatus) (*TransferResponse, error) {
+	now := time.Now()
+	if err := s.transferRepo.Close(ctx, transfer.ID, status, now); err != nil {
+		return nil, err
+	}
+	transfer.Status = status
+	transfer.RespondedAt = &now
+
+	logger.Info("Device transfer closed",
+		zap.String("transfer_id", transfer.ID.String()),
+		zap.String("device_id", transfer.DeviceID.String()),
+		zap.String("status", string(status)),
+		zap.String("event", "device_transfer_closed"),
+	)
+
+	return ToTransferResponse(transfer), nil
+}
+
+// getTransfer loads a transfer the shipper is a party to. Other transfers
+// are reported as missing.
+func (s *TransferService) getTransfer(ctx context.Context, shipperID, transferID uuid.UUID) (*domainDevice.Transfer, error) {
+	transfer, err := s.transferRepo.GetByID(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.FromShipperID != shipperID && transfer.ToShipperID != shipperID {
+		return nil, domainDevice.ErrTransferNotFound
+	}
+	return transfer, nil
+}
+
+// ensureIdle rejects devices that are tracking a shipment or trip
+func (s *TransferService) ensureIdle(ctx context.Context, device *domainDevice.Device) error {
+	if device.Status == domainDevice.StatusInTransit || device.CurrentShipmentID != nil {
+		return domainDevice.ErrDeviceInUse
+	}
+	busy, err := s.transferRepo.HasOpenWork(ctx, device.ID)
+	if err != nil {
+		return err
+	}
+	if busy {
+		return domainDevice.ErrDeviceInUse
+	}
+	return nil
+}
@@ -28,6 +28,12 @@ func ValidateShipperOwner(ctx context.Context, userRepo domainUser.Repository, s
 	return nil
 }
 
+// ownedBy reports whether the device already belongs to the shipper, in which
+// case assigning it again adds nothing to their devices quota
+func ownedBy(device *domainDevice.Device, shipperID uuid.UUID) bool {
+	return device.OwnerShipperID != nil && *device.OwnerShipperID == shipperID
+}
+
 // ValidateDeviceStatus validates device status transitions
 func ValidateDeviceStatus(currentStatus, newStatus domainDevice.DeviceStatus) error {
 	validTransitions := map[domainDevice.DeviceStatus][]domainDevice.DeviceStatus{
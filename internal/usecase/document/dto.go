@@ -0,0 +1,129 @@
+package document
+
+import (
+	"time"
+
+	domainDocument "cargo-tracker/internal/domain/document"
+	"github.com/google/uuid"
+)
+
+type ChecklistItemRequest struct {
+	DocumentType domainDocument.DocumentType `json:"document_type" validate:"required,oneof=commercial_invoice packing_list certificate_of_origin customs_declaration bill_of_lading health_certificate dangerous_goods_declaration other"`
+	Label        string                      `json:"label" validate:"omitempty,max=255"`
+	Required     *bool                       `json:"required"`
+}
+
+type SetChecklistRequest struct {
+	Items []ChecklistItemRequest `json:"items" validate:"max=20,dive"`
+}
+
+type UploadDocumentRequest struct {
+	DocumentType    domainDocument.DocumentType `form:"document_type" validate:"required,oneof=commercial_invoice packing_list certificate_of_origin customs_declaration bill_of_lading health_certificate dangerous_goods_declaration other"`
+	ChecklistItemID string                      `form:"checklist_item_id" validate:"omitempty,uuid"`
+	// Overrides of the default visibility of the document type
+	VisibilityRequest
+}
+
+// VisibilityRequest sets which parties see an attachment; unset parties keep
+// their current or default visibility
+type VisibilityRequest struct {
+	Customer *bool `json:"customer" form:"visible_to_customer"`
+	Provider *bool `json:"provider" form:"visible_to_provider"`
+	Shipper  *bool `json:"shipper" form:"visible_to_shipper"`
+	Admin    *bool `json:"admin" form:"visible_to_admin"`
+}
+
+type ReviewChecklistItemRequest struct {
+	Status domainDocument.ChecklistStatus `json:"status" validate:"required,oneof=verified rejected"`
+	Note   *string                        `json:"note" validate:"omitempty,max=1000"`
+}
+
+type ChecklistItemResponse struct {
+	ID           uuid.UUID                      `json:"id"`
+	DocumentType domainDocument.DocumentType    `json:"document_type"`
+	Label        string                         `json:"label"`
+	Required     bool                           `json:"required"`
+	Status       domainDocument.ChecklistStatus `json:"status"`
+	AttachmentID *uuid.UUID                     `json:"attachment_id,omitempty"`
+	ReviewedBy   *uuid.UUID                     `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time                     `json:"reviewed_at,omitempty"`
+	ReviewNote   *string                        `json:"review_note,omitempty"`
+	UpdatedAt    time.Time                      `json:"updated_at"`
+}
+
+type ChecklistResponse struct {
+	ShipmentID      uuid.UUID               `json:"shipment_id"`
+	Items           []ChecklistItemResponse `json:"items"`
+	Complete        bool                    `json:"complete"`
+	PendingRequired int                     `json:"pending_required"`
+}
+
+type AttachmentResponse struct {
+	ID              uuid.UUID                   `json:"id"`
+	ShipmentID      uuid.UUID                   `json:"shipment_id"`
+	ChecklistItemID *uuid.UUID                  `json:"checklist_item_id,omitempty"`
+	DocumentType    domainDocument.DocumentType `json:"document_type"`
+	FileName        string                      `json:"file_name"`
+	ContentType     string                      `json:"content_type"`
+	SizeBytes       int64                       `json:"size_bytes"`
+	UploadedBy      uuid.UUID                   `json:"uploaded_by"`
+	Visibility      VisibilityResponse          `json:"visibility"`
+	CreatedAt       time.Time                   `json:"created_at"`
+}
+
+type VisibilityResponse struct {
+	Customer bool `json:"customer"`
+	Provider bool `json:"provider"`
+	Shipper  bool `json:"shipper"`
+	Admin    bool `json:"admin"`
+}
+
+// Conversion functions
+func ToChecklistResponse(shipmentID uuid.UUID, items []*domainDocument.ChecklistItem) *ChecklistResponse {
+	resp := &ChecklistResponse{
+		ShipmentID: shipmentID,
+		Items:      make([]ChecklistItemResponse, len(items)),
+	}
+	for i, item := range items {
+		resp.Items[i] = ChecklistItemResponse{
+			ID:           item.ID,
+			DocumentType: item.DocumentType,
+			Label:        item.Label,
+			Required:     item.Required,
+			Status:       item.Status,
+			AttachmentID: item.AttachmentID,
+			ReviewedBy:   item.ReviewedBy,
+			ReviewedAt:   item.ReviewedAt,
+			ReviewNote:   item.ReviewNote,
+			UpdatedAt:    item.UpdatedAt,
+		}
+		if !item.IsComplete() {
+			resp.PendingRequired++
+		}
+	}
+	resp.Complete = resp.PendingRequired == 0
+	return resp
+}
+
+func ToAttachmentResponse(a *domainDocument.Attachment) *AttachmentResponse {
+	if a == nil {
+		return nil
+	}
+	return &AttachmentResponse{
+		ID:              a.ID,
+		ShipmentID:      a.ShipmentID,
+		ChecklistItemID: a.ChecklistItemID,
+		DocumentType:    a.DocumentType,
+		FileName:        a.FileName,
+		ContentType:     a.ContentType,
+		SizeBytes:       a.SizeBytes,
+		UploadedBy:      a.UploadedBy,
+		Visibility: VisibilityResponse{
+			Customer: a.Visibility.Customer,
+			Provider: a.Visibility.Provider,
+			Shipper:  a.Visibility.Shipper,
+			Admin:    a.Visibility.Admin,
+		},
+		CreatedAt: a.CreatedAt,
+	}
+}
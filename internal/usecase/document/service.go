@@ -0,0 +1,434 @@
+package document
+
+import (
+	"bytes"
+	domainDocument "cargo-tracker/internal/domain/document"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	usecaseShipment "cargo-tracker/internal/usecase/shipment"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service implements shipment document use cases
+type Service struct {
+	documentRepo domainDocument.Repository
+	shipmentRepo domainShipment.Repository
+	userRepo     domainUser.Repository
+	store        domainStorage.Store
+
+	accessGrantRepo domainShipment.AccessGrantRepository
+}
+
+// NewService creates a new document service
+func NewService(
+	documentRepo domainDocument.Repository,
+	shipmentRepo domainShipment.Repository,
+	userRepo domainUser.Repository,
+	store domainStorage.Store,
+	accessGrantRepo domainShipment.AccessGrantRepository,
+) *Service {
+	return &Service{
+		documentRepo: documentRepo,
+		shipmentRepo: shipmentRepo,
+		userRepo:     userRepo,
+		store:        store,
+
+		accessGrantRepo: accessGrantRepo,
+	}
+}
+
+// SetChecklist replaces the required-document checklist of a shipment
+func (s *Service) SetChecklist(ctx context.Context, providerID, shipmentID uuid.UUID, req *SetChecklistRequest) (*ChecklistResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify provider owns this shipment
+	if shipment.ProviderID != providerID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Provider does not own this shipment", nil)
+	}
+	if !CanEditChecklist(shipment.Status) {
+		return nil, appErrors.NewAppError("INVALID_STATUS", "Checklist cannot be changed once shipping has started", nil)
+	}
+
+	seen := make(map[domainDocument.DocumentType]bool)
+	items := make([]*domainDocument.ChecklistItem, 0, len(req.Items))
+	for _, r := range req.Items {
+		if seen[r.DocumentType] && r.DocumentType != domainDocument.TypeOther {
+			return nil, appErrors.NewAppError("VALIDATION_ERROR", fmt.Sprintf("Duplicate document type %s", r.DocumentType), nil)
+		}
+		seen[r.DocumentType] = true
+
+		label := r.Label
+		if label == "" {
+			label = DefaultLabel(r.DocumentType)
+		}
+		required := true
+		if r.Required != nil {
+			required = *r.Required
+		}
+
+		items = append(items, &domainDocument.ChecklistItem{
+			DocumentType: r.DocumentType,
+			Label:        label,
+			Required:     required,
+			Status:       domainDocument.ChecklistPending,
+		})
+	}
+
+	if err := s.documentRepo.ReplaceChecklist(ctx, shipmentID, items); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Document checklist updated",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("provider_id", providerID.String()),
+		zap.Int("items", len(items)),
+		zap.String("event", "document_checklist_updated"),
+	)
+
+	return s.checklistResponse(ctx, shipmentID)
+}
+
+func (s *Service) GetChecklist(ctx context.Context, userID, shipmentID uuid.UUID) (*ChecklistResponse, error) {
+	if _, _, err := s.authorizeViewer(ctx, userID, shipmentID); err != nil {
+		return nil, err
+	}
+	return s.checklistResponse(ctx, shipmentID)
+}
+
+// UploadDocument stores a file for a shipment, optionally filling a checklist slot
+func (s *Service) UploadDocument(ctx context.Context, userID, shipmentID uuid.UUID, req *UploadDocumentRequest, fileName string, file io.Reader) (*AttachmentResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	_, party, err := s.authorizeParty(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item *domainDocument.ChecklistItem
+	if req.ChecklistItemID != "" {
+		var itemID uuid.UUID
+		itemID, err = uuid.Parse(req.ChecklistItemID)
+		if err != nil {
+			return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid checklist item ID", err)
+		}
+		item, err = s.documentRepo.GetChecklistItem(ctx, itemID)
+		if err != nil {
+			return nil, err
+		}
+		if item.ShipmentID != shipmentID {
+			return nil, domainDocument.ErrChecklistItemNotFound
+		}
+		if item.Status == domainDocument.ChecklistVerified {
+			return nil, appErrors.NewAppError("INVALID_STATUS", "Document has already been verified", nil)
+		}
+		req.DocumentType = item.DocumentType
+	}
+
+	// Sniff the real content type from the file header
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	contentType, ext, err := DetectContentType(header[:n])
+	if err != nil {
+		return nil, appErrors.NewAppError("UNSUPPORTED_FILE_TYPE", "Only PDF, JPEG and PNG files are accepted", err)
+	}
+
+	attachment := &domainDocument.Attachment{
+		ID:           uuid.New(),
+		ShipmentID:   shipmentID,
+		DocumentType: req.DocumentType,
+		FileName:     SanitizeFileName(fileName),
+		ContentType:  contentType,
+		UploadedBy:   userID,
+		// Uploaders can never hide a file from themselves
+		Visibility: applyVisibility(domainDocument.DefaultVisibility(req.DocumentType), &req.VisibilityRequest).With(party),
+	}
+	if item != nil {
+		attachment.ChecklistItemID = &item.ID
+	}
+	attachment.StorageKey = fmt.Sprintf("shipments/%s/documents/%s%s", shipmentID, attachment.ID, ext)
+
+	size, err := s.store.Put(ctx, attachment.StorageKey, io.MultiReader(bytes.NewReader(header[:n]), file), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+	attachment.SizeBytes = size
+
+	if err := s.documentRepo.CreateAttachment(ctx, attachment); err != nil {
+		_ = s.store.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	if item != nil {
+		item.AttachmentID = &attachment.ID
+		item.Status = domainDocument.ChecklistSubmitted
+		item.ReviewedBy = nil
+		item.ReviewedAt = nil
+		item.ReviewNote = nil
+		if err := s.documentRepo.UpdateChecklistItem(ctx, item); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("Shipment document uploaded",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("attachment_id", attachment.ID.String()),
+		zap.String("document_type", string(attachment.DocumentType)),
+		zap.Int64("size_bytes", size),
+		zap.String("event", "document_uploaded"),
+	)
+
+	return ToAttachmentResponse(attachment), nil
+}
+
+// ListAttachments returns the attachments of a shipment the user may see
+func (s *Service) ListAttachments(ctx context.Context, userID, shipmentID uuid.UUID) ([]*AttachmentResponse, error) {
+	_, party, err := s.authorizeViewer(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.documentRepo.ListAttachments(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*AttachmentResponse, 0, len(attachments))
+	for _, a := range attachments {
+		if canSee(a, userID, party) {
+			responses = append(responses, ToAttachmentResponse(a))
+		}
+	}
+	return responses, nil
+}
+
+// OpenAttachment returns the attachment metadata and its content. The caller
+// must close the reader.
+func (s *Service) OpenAttachment(ctx context.Context, userID, shipmentID, attachmentID uuid.UUID) (*AttachmentResponse, io.ReadCloser, error) {
+	_, party, err := s.authorizeViewer(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.documentRepo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Hidden attachments are reported as missing so their existence does not leak
+	if attachment.ShipmentID != shipmentID || !canSee(attachment, userID, party) {
+		return nil, nil, domainDocument.ErrAttachmentNotFound
+	}
+
+	content, err := s.store.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ToAttachmentResponse(attachment), content, nil
+}
+
+// UpdateVisibility changes which parties see an attachment. The uploader,
+// the shipment's provider and admins may change it; the uploader keeps
+// access either way.
+func (s *Service) UpdateVisibility(ctx context.Context, userID, shipmentID, attachmentID uuid.UUID, req *VisibilityRequest) (*AttachmentResponse, error) {
+	_, party, err := s.authorizeParty(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.documentRepo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.ShipmentID != shipmentID || !canSee(attachment, userID, party) {
+		return nil, domainDocument.ErrAttachmentNotFound
+	}
+	if attachment.UploadedBy != userID && party != domainDocument.PartyProvider && party != domainDocument.PartyAdmin {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only the uploader or the provider can change who sees this document", nil)
+	}
+
+	uploader := s.uploaderParty(ctx, attachment)
+	attachment.Visibility = applyVisibility(attachment.Visibility, req).With(uploader)
+	if err := s.documentRepo.UpdateAttachmentVisibility(ctx, attachment); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Shipment document visibility changed",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("attachment_id", attachmentID.String()),
+		zap.String("user_id", userID.String()),
+		zap.Bool("customer", attachment.Visibility.Customer),
+		zap.Bool("provider", attachment.Visibility.Provider),
+		zap.Bool("shipper", attachment.Visibility.Shipper),
+		zap.Bool("admin", attachment.Visibility.Admin),
+		zap.String("event", "document_visibility_changed"),
+	)
+
+	return ToAttachmentResponse(attachment), nil
+}
+
+// ReviewChecklistItem lets the provider verify or reject a submitted document
+func (s *Service) ReviewChecklistItem(ctx context.Context, providerID, shipmentID, itemID uuid.UUID, req *ReviewChecklistItemRequest) (*ChecklistResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.ProviderID != providerID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Provider does not own this shipment", nil)
+	}
+
+	item, err := s.documentRepo.GetChecklistItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.ShipmentID != shipmentID {
+		return nil, domainDocument.ErrChecklistItemNotFound
+	}
+	if item.AttachmentID == nil {
+		return nil, appErrors.NewAppError("INVALID_STATUS", "No document has been uploaded for this item", nil)
+	}
+
+	now := time.Now()
+	item.Status = req.Status
+	item.ReviewedBy = &providerID
+	item.ReviewedAt = &now
+	item.ReviewNote = req.Note
+	if err := s.documentRepo.UpdateChecklistItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Shipment document reviewed",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("checklist_item_id", itemID.String()),
+		zap.String("status", string(req.Status)),
+		zap.String("event", "document_reviewed"),
+	)
+
+	return s.checklistResponse(ctx, shipmentID)
+}
+
+// Helper functions
+
+func (s *Service) checklistResponse(ctx context.Context, shipmentID uuid.UUID) (*ChecklistResponse, error) {
+	items, err := s.documentRepo.GetChecklist(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	return ToChecklistResponse(shipmentID, items), nil
+}
+
+// authorizeParty allows the shipment's customer, provider, assigned shipper
+// and admins, and tells which of them the user is
+func (s *Service) authorizeParty(ctx context.Context, userID, shipmentID uuid.UUID) (*domainShipment.Shipment, domainDocument.Party, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if party := partyOf(shipment, userID); party != "" {
+		return shipment, party, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user.Role != "admin" {
+		return nil, "", appErrors.NewAppError("UNAUTHORIZED", "User is not a party to this shipment", nil)
+	}
+	return shipment, domainDocument.PartyAdmin, nil
+}
+
+// authorizeViewer extends authorizeParty to third parties holding an active
+// documents access grant on the shipment. Grants are read-only.
+func (s *Service) authorizeViewer(ctx context.Context, userID, shipmentID uuid.UUID) (*domainShipment.Shipment, domainDocument.Party, error) {
+	shipment, party, err := s.authorizeParty(ctx, userID, shipmentID)
+	if err == nil {
+		return shipment, party, nil
+	}
+
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "UNAUTHORIZED" {
+		return nil, "", err
+	}
+	if !usecaseShipment.HasAccessGrant(ctx, s.accessGrantRepo, shipmentID, userID, domainShipment.ScopeDocuments) {
+		return nil, "", err
+	}
+
+	shipment, err = s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, "", err
+	}
+	return shipment, domainDocument.PartyGrantee, nil
+}
+
+// uploaderParty returns the role the uploader of an attachment holds on its
+// shipment, falling back to admin for uploaders who are no longer a party
+func (s *Service) uploaderParty(ctx context.Context, a *domainDocument.Attachment) domainDocument.Party {
+	shipment, err := s.shipmentRepo.GetByID(ctx, a.ShipmentID)
+	if err == nil {
+		if party := partyOf(shipment, a.UploadedBy); party != "" {
+			return party
+		}
+	}
+	return domainDocument.PartyAdmin
+}
+
+func partyOf(shipment *domainShipment.Shipment, userID uuid.UUID) domainDocument.Party {
+	switch {
+	case shipment.CustomerID == userID:
+		return domainDocument.PartyCustomer
+	case shipment.ProviderID == userID:
+		return domainDocument.PartyProvider
+	case shipment.ShipperID != nil && *shipment.ShipperID == userID:
+		return domainDocument.PartyShipper
+	default:
+		return ""
+	}
+}
+
+// canSee reports whether a user holding party on the shipment may see the
+// attachment; uploaders always see their own files
+func canSee(a *domainDocument.Attachment, userID uuid.UUID, party domainDocument.Party) bool {
+	return a.UploadedBy == userID || a.Visibility.Allows(party)
+}
+
+// applyVisibility overrides v with the parties set in req
+func applyVisibility(v domainDocument.Visibility, req *VisibilityRequest) domainDocument.Visibility {
+	if req.Customer != nil {
+		v.Customer = *req.Customer
+	}
+	if req.Provider != nil {
+		v.Provider = *req.Provider
+	}
+	if req.Shipper != nil {
+		v.Shipper = *req.Shipper
+	}
+	if req.Admin != nil {
+		v.Admin = *req.Admin
+	}
+	return v
+}
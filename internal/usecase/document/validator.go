@@ -0,0 +1,73 @@
+package document
+
+import (
+	domainDocument "cargo-tracker/internal/domain/document"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// allowedContentTypes maps the sniffed content type of an upload to the file
+// extension it is stored with.
+var allowedContentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// DetectContentType sniffs the content type from the first bytes of a file,
+// ignoring whatever the client claimed.
+func DetectContentType(header []byte) (string, string, error) {
+	contentType := http.DetectContentType(header)
+	if idx := strings.Index(contentType, ";"); idx >= 0 {
+		contentType = contentType[:idx]
+	}
+	ext, ok := allowedContentTypes[contentType]
+	if !ok {
+		return "", "", domainDocument.ErrUnsupportedFileType
+	}
+	return contentType, ext, nil
+}
+
+// SanitizeFileName keeps only the base name and strips characters that are
+// unsafe in Content-Disposition headers.
+func SanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' || r == '/' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." {
+		return "document"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
+
+// DefaultLabel returns a readable label for a document type
+func DefaultLabel(t domainDocument.DocumentType) string {
+	words := strings.Split(string(t), "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// CanEditChecklist reports whether the checklist may still be changed: once a
+// shipment is in transit the requirements are frozen.
+func CanEditChecklist(status domainShipment.ShipmentStatus) bool {
+	switch status {
+	case domainShipment.StatusDemandCreated,
+		domainShipment.StatusOrderPosted,
+		domainShipment.StatusShippingAssigned:
+		return true
+	}
+	return false
+}
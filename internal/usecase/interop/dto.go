@@ -0,0 +1,62 @@
+package interop
+
+import (
+	domainInterop "cargo-tracker/internal/domain/interop"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type SavePartnerMappingRequest struct {
+	Name              string               `json:"name" validate:"required,min=2,max=100"`
+	Format            domainInterop.Format `json:"format" validate:"required,oneof=epcis json"`
+	RecordsPath       string               `json:"records_path" validate:"omitempty,max=255"`
+	ImportFields      map[string]string    `json:"import_fields" validate:"omitempty,dive,keys,required,endkeys,required,max=255"`
+	DefaultProviderID *uuid.UUID           `json:"default_provider_id"`
+	ExportFields      map[string]string    `json:"export_fields" validate:"omitempty,dive,keys,required,max=100,endkeys,required"`
+	IsActive          *bool                `json:"is_active"`
+}
+
+// Response DTOs
+type PartnerMappingResponse struct {
+	ID                uuid.UUID            `json:"id"`
+	Name              string               `json:"name"`
+	Format            domainInterop.Format `json:"format"`
+	RecordsPath       string               `json:"records_path"`
+	ImportFields      map[string]string    `json:"import_fields"`
+	DefaultProviderID *uuid.UUID           `json:"default_provider_id,omitempty"`
+	ExportFields      map[string]string    `json:"export_fields"`
+	IsActive          bool                 `json:"is_active"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// ImportRecordResult reports the outcome of one record of an import message
+type ImportRecordResult struct {
+	Index      int        `json:"index"`
+	ShipmentID *uuid.UUID `json:"shipment_id,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type ImportResponse struct {
+	Total    int                  `json:"total"`
+	Imported int                  `json:"imported"`
+	Failed   int                  `json:"failed"`
+	Records  []ImportRecordResult `json:"records"`
+}
+
+func ToPartnerMappingResponse(m *domainInterop.PartnerMapping) *PartnerMappingResponse {
+	return &PartnerMappingResponse{
+		ID:                m.ID,
+		Name:              m.Name,
+		Format:            m.Format,
+		RecordsPath:       m.RecordsPath,
+		ImportFields:      m.ImportFields,
+		DefaultProviderID: m.DefaultProviderID,
+		ExportFields:      m.ExportFields,
+		IsActive:          m.IsActive,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
@@ -0,0 +1,180 @@
+package interop
+
+import (
+	domainInterop "cargo-tracker/internal/domain/interop"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"sort"
+	"time"
+)
+
+// epcisContext is the JSON-LD context of EPCIS 2.0 documents
+const epcisContext = "https://ref.gs1.org/standards/epcis/2.0.0/epcis-context.jsonld"
+
+// BuildMilestones derives the lifecycle events of a shipment, oldest first
+func BuildMilestones(shipment *domainShipment.Shipment, packages []*domainShipment.Package) []domainInterop.Milestone {
+	milestones := []domainInterop.Milestone{{
+		Type:       domainInterop.MilestoneCreated,
+		ShipmentID: shipment.ID,
+		Status:     string(domainShipment.StatusDemandCreated),
+		Location:   shipment.PickupAddress,
+		OccurredAt: shipment.CreatedAt,
+	}}
+
+	if shipment.ActualPickupAt != nil {
+		milestones = append(milestones, domainInterop.Milestone{
+			Type:       domainInterop.MilestonePickedUp,
+			ShipmentID: shipment.ID,
+			Status:     string(domainShipment.StatusInTransit),
+			Location:   shipment.PickupAddress,
+			OccurredAt: *shipment.ActualPickupAt,
+		})
+	}
+
+	for _, pkg := range packages {
+		if pkg.OutcomeAt == nil || pkg.Outcome == domainShipment.OutcomePending {
+			continue
+		}
+		packageID := pkg.ID
+		milestones = append(milestones, domainInterop.Milestone{
+			Type:       domainInterop.MilestonePackageOutcome,
+			ShipmentID: shipment.ID,
+			PackageID:  &packageID,
+			Status:     string(pkg.Outcome),
+			Location:   shipment.DeliveryAddress,
+			Note:       pkg.OutcomeNote,
+			OccurredAt: *pkg.OutcomeAt,
+		})
+	}
+
+	if shipment.ActualDeliveryAt != nil {
+		milestones = append(milestones, domainInterop.Milestone{
+			Type:       domainInterop.MilestoneDelivered,
+			ShipmentID: shipment.ID,
+			Status:     string(shipment.Status),
+			Location:   shipment.DeliveryAddress,
+			Note:       shipment.CompletionNotes,
+			OccurredAt: *shipment.ActualDeliveryAt,
+		})
+	}
+
+	switch shipment.Status {
+	case domainShipment.StatusIssueReported:
+		milestones = append(milestones, domainInterop.Milestone{
+			Type:       domainInterop.MilestoneIssueReported,
+			ShipmentID: shipment.ID,
+			Status:     string(shipment.Status),
+			OccurredAt: shipment.UpdatedAt,
+		})
+	case domainShipment.StatusCancelled:
+		milestones = append(milestones, domainInterop.Milestone{
+			Type:       domainInterop.MilestoneCancelled,
+			ShipmentID: shipment.ID,
+			Status:     string(shipment.Status),
+			OccurredAt: shipment.UpdatedAt,
+		})
+	}
+
+	sort.SliceStable(milestones, func(i, j int) bool {
+		return milestones[i].OccurredAt.Before(milestones[j].OccurredAt)
+	})
+	return milestones
+}
+
+// epcisVocabulary maps milestones to CBV business steps and dispositions
+func epcisVocabulary(m domainInterop.Milestone) (bizStep, disposition string) {
+	switch m.Type {
+	case domainInterop.MilestoneCreated:
+		return "commissioning", "active"
+	case domainInterop.MilestonePickedUp:
+		return "departing", "in_transit"
+	case domainInterop.MilestoneDelivered:
+		return "receiving", "in_progress"
+	case domainInterop.MilestoneIssueReported:
+		return "inspecting", "non_conformant"
+	case domainInterop.MilestoneCancelled:
+		return "void_shipping", "inactive"
+	case domainInterop.MilestonePackageOutcome:
+		switch domainShipment.PackageOutcome(m.Status) {
+		case domainShipment.OutcomeDamaged:
+			return "receiving", "damaged"
+		case domainShipment.OutcomeLost:
+			return "receiving", "unknown"
+		case domainShipment.OutcomeRefused:
+			return "receiving", "returned"
+		}
+		return "receiving", "in_progress"
+	}
+	return "other", "unknown"
+}
+
+func epcURN(m domainInterop.Milestone) string {
+	if m.PackageID != nil {
+		return "urn:cargo-tracker:package:" + m.PackageID.String()
+	}
+	return "urn:cargo-tracker:shipment:" + m.ShipmentID.String()
+}
+
+// RenderEPCIS renders milestones as an EPCIS 2.0 document of ObjectEvents
+func RenderEPCIS(milestones []domainInterop.Milestone, createdAt time.Time) map[string]interface{} {
+	events := make([]map[string]interface{}, 0, len(milestones))
+	for _, m := range milestones {
+		bizStep, disposition := epcisVocabulary(m)
+		event := map[string]interface{}{
+			"type":                "ObjectEvent",
+			"eventTime":           m.OccurredAt.UTC().Format(time.RFC3339),
+			"eventTimeZoneOffset": "+00:00",
+			"epcList":             []string{epcURN(m)},
+			"action":              "OBSERVE",
+			"bizStep":             bizStep,
+			"disposition":         disposition,
+			"bizTransactionList": []map[string]string{{
+				"type":           "desadv",
+				"bizTransaction": "urn:cargo-tracker:shipment:" + m.ShipmentID.String(),
+			}},
+		}
+		if m.Location != "" {
+			event["readPoint"] = map[string]string{"id": "urn:cargo-tracker:address:" + m.Location}
+		}
+		events = append(events, event)
+	}
+
+	return map[string]interface{}{
+		"@context":      []string{epcisContext},
+		"type":          "EPCISDocument",
+		"schemaVersion": "2.0",
+		"creationDate":  createdAt.UTC().Format(time.RFC3339),
+		"epcisBody": map[string]interface{}{
+			"eventList": events,
+		},
+	}
+}
+
+// RenderJSON renders milestones as flat records keyed by the export mapping
+func RenderJSON(shipment *domainShipment.Shipment, milestones []domainInterop.Milestone, fields map[string]string) []map[string]interface{} {
+	if len(fields) == 0 {
+		fields = defaultExportFields()
+	}
+
+	records := make([]map[string]interface{}, 0, len(milestones))
+	for _, m := range milestones {
+		values := map[string]interface{}{
+			"event":             m.Type,
+			"shipment_id":       m.ShipmentID,
+			"package_id":        m.PackageID,
+			"status":            m.Status,
+			"location":          m.Location,
+			"note":              m.Note,
+			"occurred_at":       m.OccurredAt.UTC().Format(time.RFC3339),
+			"goods_description": shipment.GoodsDescription,
+			"pickup_address":    shipment.PickupAddress,
+			"delivery_address":  shipment.DeliveryAddress,
+		}
+
+		record := make(map[string]interface{}, len(fields))
+		for key, source := range fields {
+			record[key] = values[source]
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,55 @@
+package interop
+
+import (
+	"cargo-tracker/internal/usecase/job"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// JobKindImport imports a partner message in the background
+const JobKindImport = "interop.import"
+
+type importJobPayload struct {
+	MappingID uuid.UUID       `json:"mapping_id"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// RegisterJobs registers the background jobs of the service
+func (s *Service) RegisterJobs(jobs *job.Service) {
+	s.jobs = jobs
+	// Imports create shipments and are not safe to repeat
+	jobs.Register(JobKindImport, s.runImportJob, job.RetryPolicy{MaxAttempts: 1})
+}
+
+// QueueImport validates a partner message and queues its import. The job
+// result is the ImportResponse of a synchronous import.
+func (s *Service) QueueImport(ctx context.Context, customerID, mappingID uuid.UUID, message []byte) (*job.JobResponse, error) {
+	if _, err := s.prepareImport(ctx, customerID, mappingID, message); err != nil {
+		return nil, err
+	}
+	return s.jobs.Enqueue(ctx, customerID, JobKindImport, importJobPayload{
+		MappingID: mappingID,
+		Message:   message,
+	})
+}
+
+func (s *Service) runImportJob(ctx context.Context, run *job.Run) (*job.Output, error) {
+	var payload importJobPayload
+	if err := run.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	customerID := run.Job.OwnerID
+	pending, err := s.prepareImport(ctx, customerID, payload.MappingID, payload.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.importRecords(ctx, customerID, pending, func(done, total int) {
+		run.SetProgress(done, total, fmt.Sprintf("Processed %d of %d records", done, total))
+	})
+	return &job.Output{Result: resp}, nil
+}
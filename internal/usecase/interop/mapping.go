@@ -0,0 +1,220 @@
+package interop
+
+import (
+	"cargo-tracker/internal/usecase/shipment"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shipment fields an import mapping can fill
+const (
+	FieldProviderID          = "provider_id"
+	FieldGoodsDescription    = "goods_description"
+	FieldGoodsValue          = "goods_value"
+	FieldGoodsCurrency       = "goods_currency"
+	FieldGoodsWeight         = "goods_weight"
+	FieldPickupAddress       = "pickup_address"
+	FieldDeliveryAddress     = "delivery_address"
+	FieldEstimatedPickupAt   = "estimated_pickup_at"
+	FieldEstimatedDeliveryAt = "estimated_delivery_at"
+	FieldCustomerNotes       = "customer_notes"
+)
+
+var importFieldNames = []string{
+	FieldProviderID, FieldGoodsDescription, FieldGoodsValue, FieldGoodsCurrency, FieldGoodsWeight,
+	FieldPickupAddress, FieldDeliveryAddress, FieldEstimatedPickupAt,
+	FieldEstimatedDeliveryAt, FieldCustomerNotes,
+}
+
+// defaultImportFields reads messages that already use the shipment field names
+func defaultImportFields() map[string]string {
+	fields := make(map[string]string, len(importFieldNames))
+	for _, name := range importFieldNames {
+		fields[name] = name
+	}
+	return fields
+}
+
+// Milestone fields an export mapping can emit
+var exportFieldNames = []string{
+	"event", "shipment_id", "package_id", "status", "location", "note",
+	"occurred_at", "goods_description", "pickup_address", "delivery_address",
+}
+
+// defaultExportFields emits every milestone field under its own name
+func defaultExportFields() map[string]string {
+	fields := make(map[string]string, len(exportFieldNames))
+	for _, name := range exportFieldNames {
+		fields[name] = name
+	}
+	return fields
+}
+
+// decodeMessage parses a partner message keeping numbers exact
+func decodeMessage(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// lookupPath resolves a dotted path such as "consignment.items.0.weight".
+// Numeric segments index arrays.
+func lookupPath(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, segment := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			v = node[idx]
+		default:
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+// extractRecords returns the shipment records of a message
+func extractRecords(message interface{}, recordsPath string) ([]interface{}, error) {
+	v, ok := lookupPath(message, recordsPath)
+	if !ok {
+		return nil, fmt.Errorf("records path %q not found in message", recordsPath)
+	}
+	if records, ok := v.([]interface{}); ok {
+		return records, nil
+	}
+	if _, ok := v.(map[string]interface{}); ok {
+		return []interface{}{v}, nil
+	}
+	return nil, fmt.Errorf("records path %q is neither an object nor an array", recordsPath)
+}
+
+// toDemandRequest builds a shipment demand from one record using the mapping
+func toDemandRequest(record interface{}, fields map[string]string, defaultProvider *uuid.UUID) (*shipment.CreateDemandRequest, error) {
+	req := &shipment.CreateDemandRequest{}
+	if defaultProvider != nil {
+		req.ProviderID = *defaultProvider
+	}
+
+	// Sorted for deterministic error messages
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		raw, ok := lookupPath(record, fields[name])
+		if !ok {
+			continue
+		}
+
+		var err error
+		switch name {
+		case FieldProviderID:
+			var s string
+			if s, err = asString(raw); err == nil {
+				req.ProviderID, err = uuid.Parse(s)
+			}
+		case FieldGoodsDescription:
+			req.GoodsDescription, err = asString(raw)
+		case FieldGoodsValue:
+			req.GoodsValue, err = asFloat(raw)
+		case FieldGoodsCurrency:
+			req.GoodsCurrency, err = asString(raw)
+		case FieldGoodsWeight:
+			req.GoodsWeight, err = asFloat(raw)
+		case FieldPickupAddress:
+			req.PickupAddress, err = asString(raw)
+		case FieldDeliveryAddress:
+			req.DeliveryAddress, err = asString(raw)
+		case FieldEstimatedPickupAt:
+			req.EstimatedPickupAt, err = asTime(raw)
+		case FieldEstimatedDeliveryAt:
+			req.EstimatedDeliveryAt, err = asTime(raw)
+		case FieldCustomerNotes:
+			var s string
+			if s, err = asString(raw); err == nil {
+				req.CustomerNotes = &s
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return req, nil
+}
+
+func asString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t), nil
+	case json.Number:
+		return t.String(), nil
+	case []interface{}:
+		// Address lines and similar multi-part values are joined
+		parts := make([]string, 0, len(t))
+		for _, item := range t {
+			s, err := asString(item)
+			if err != nil {
+				return "", err
+			}
+			if s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", "), nil
+	}
+	return "", fmt.Errorf("expected text")
+}
+
+func asFloat(v interface{}) (*float64, error) {
+	var s string
+	switch t := v.(type) {
+	case json.Number:
+		s = t.String()
+	case string:
+		s = strings.TrimSpace(t)
+	default:
+		return nil, fmt.Errorf("expected a number")
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a number")
+	}
+	return &f, nil
+}
+
+func asTime(v interface{}) (*time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected an RFC 3339 timestamp or a date")
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("expected an RFC 3339 timestamp or a date")
+}
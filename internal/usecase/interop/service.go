@@ -0,0 +1,275 @@
+package interop
+
+import (
+	domainInterop "cargo-tracker/internal/domain/interop"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/job"
+	"cargo-tracker/internal/usecase/shipment"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxImportRecords bounds the number of shipments in one import message
+const MaxImportRecords = 500
+
+// Service implements partner data exchange use cases
+type Service struct {
+	mappingRepo     domainInterop.Repository
+	shipmentRepo    domainShipment.Repository
+	shipmentService *shipment.Service
+
+	jobs *job.Service
+}
+
+// NewService creates a new interop service
+func NewService(
+	mappingRepo domainInterop.Repository,
+	shipmentRepo domainShipment.Repository,
+	shipmentService *shipment.Service,
+) *Service {
+	return &Service{
+		mappingRepo:     mappingRepo,
+		shipmentRepo:    shipmentRepo,
+		shipmentService: shipmentService,
+	}
+}
+
+func (s *Service) ListMappings(ctx context.Context, ownerID uuid.UUID) ([]PartnerMappingResponse, error) {
+	mappings, err := s.mappingRepo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]PartnerMappingResponse, len(mappings))
+	for i, m := range mappings {
+		resp[i] = *ToPartnerMappingResponse(m)
+	}
+	return resp, nil
+}
+
+func (s *Service) GetMapping(ctx context.Context, ownerID, mappingID uuid.UUID) (*PartnerMappingResponse, error) {
+	mapping, err := s.getOwnedMapping(ctx, ownerID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+	return ToPartnerMappingResponse(mapping), nil
+}
+
+func (s *Service) CreateMapping(ctx context.Context, ownerID uuid.UUID, req *SavePartnerMappingRequest) (*PartnerMappingResponse, error) {
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+
+	mapping := &domainInterop.PartnerMapping{OwnerID: ownerID, IsActive: true}
+	applyMapping(mapping, req)
+	if err := s.mappingRepo.Create(ctx, mapping); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Partner mapping created",
+		zap.String("mapping_id", mapping.ID.String()),
+		zap.String("owner_id", ownerID.String()),
+		zap.String("format", string(mapping.Format)),
+		zap.String("event", "partner_mapping_created"),
+	)
+
+	return ToPartnerMappingResponse(mapping), nil
+}
+
+func (s *Service) UpdateMapping(ctx context.Context, ownerID, mappingID uuid.UUID, req *SavePartnerMappingRequest) (*PartnerMappingResponse, error) {
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+
+	mapping, err := s.getOwnedMapping(ctx, ownerID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyMapping(mapping, req)
+	if err := s.mappingRepo.Update(ctx, mapping); err != nil {
+		return nil, err
+	}
+
+	return ToPartnerMappingResponse(mapping), nil
+}
+
+func (s *Service) DeleteMapping(ctx context.Context, ownerID, mappingID uuid.UUID) error {
+	if _, err := s.getOwnedMapping(ctx, ownerID, mappingID); err != nil {
+		return err
+	}
+	return s.mappingRepo.Delete(ctx, mappingID)
+}
+
+// Import creates a shipment demand for every record of a partner message.
+// Records are independent: a failing record does not stop the others.
+func (s *Service) Import(ctx context.Context, customerID, mappingID uuid.UUID, message []byte) (*ImportResponse, error) {
+	pending, err := s.prepareImport(ctx, customerID, mappingID, message)
+	if err != nil {
+		return nil, err
+	}
+	return s.importRecords(ctx, customerID, pending, nil), nil
+}
+
+// pendingImport is a validated partner message ready to be imported
+type pendingImport struct {
+	mapping *domainInterop.PartnerMapping
+	fields  map[string]string
+	records []interface{}
+}
+
+// prepareImport checks the mapping and extracts the records of a message
+func (s *Service) prepareImport(ctx context.Context, customerID, mappingID uuid.UUID, message []byte) (*pendingImport, error) {
+	mapping, err := s.getActiveMapping(ctx, customerID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := decodeMessage(message)
+	if err != nil {
+		return nil, appErrors.NewAppError("INVALID_MESSAGE", "Message is not valid JSON", err)
+	}
+	records, err := extractRecords(parsed, mapping.RecordsPath)
+	if err != nil {
+		return nil, appErrors.NewAppError("INVALID_MESSAGE", err.Error(), err)
+	}
+	if len(records) > MaxImportRecords {
+		return nil, appErrors.NewAppError("INVALID_MESSAGE", "Message contains too many shipments", nil)
+	}
+
+	fields := mapping.ImportFields
+	if len(fields) == 0 {
+		fields = defaultImportFields()
+	}
+	return &pendingImport{mapping: mapping, fields: fields, records: records}, nil
+}
+
+// importRecords creates the demands of a prepared message, reporting
+// progress after each record when progress is not nil
+func (s *Service) importRecords(ctx context.Context, customerID uuid.UUID, pending *pendingImport, progress func(done, total int)) *ImportResponse {
+	records := pending.records
+	resp := &ImportResponse{Total: len(records), Records: make([]ImportRecordResult, 0, len(records))}
+	for i, record := range records {
+		result := ImportRecordResult{Index: i}
+
+		req, err := toDemandRequest(record, pending.fields, pending.mapping.DefaultProviderID)
+		if err == nil {
+			var created *shipment.ShipmentResponse
+			if created, err = s.shipmentService.CreateDemand(ctx, customerID, req); err == nil {
+				result.ShipmentID = &created.ID
+			}
+		}
+
+		if err != nil {
+			result.Error = importErrorMessage(err)
+			resp.Failed++
+		} else {
+			resp.Imported++
+		}
+		resp.Records = append(resp.Records, result)
+
+		if progress != nil {
+			progress(i+1, len(records))
+		}
+	}
+
+	logger.Info("Partner shipments imported",
+		zap.String("mapping_id", pending.mapping.ID.String()),
+		zap.String("customer_id", customerID.String()),
+		zap.Int("imported", resp.Imported),
+		zap.Int("failed", resp.Failed),
+		zap.String("event", "partner_shipments_imported"),
+	)
+
+	return resp
+}
+
+// ExportMilestones renders the milestones of a shipment in the partner's format
+func (s *Service) ExportMilestones(ctx context.Context, userID, mappingID, shipmentID uuid.UUID) (interface{}, error) {
+	mapping, err := s.getActiveMapping(ctx, userID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipmentEntity, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	isInvolved := shipmentEntity.CustomerID == userID ||
+		shipmentEntity.ProviderID == userID ||
+		(shipmentEntity.ShipperID != nil && *shipmentEntity.ShipperID == userID)
+	if !isInvolved {
+		return nil, appErrors.ErrUnauthorized
+	}
+
+	packages, err := s.shipmentRepo.ListPackages(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	milestones := BuildMilestones(shipmentEntity, packages)
+	if mapping.Format == domainInterop.FormatEPCIS {
+		return RenderEPCIS(milestones, time.Now()), nil
+	}
+	return RenderJSON(shipmentEntity, milestones, mapping.ExportFields), nil
+}
+
+func (s *Service) validate(req *SavePartnerMappingRequest) error {
+	if err := utils.ValidateStruct(req); err != nil {
+		return appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	return ValidateMapping(req)
+}
+
+func (s *Service) getOwnedMapping(ctx context.Context, ownerID, mappingID uuid.UUID) (*domainInterop.PartnerMapping, error) {
+	mapping, err := s.mappingRepo.GetByID(ctx, mappingID)
+	if err != nil {
+		return nil, err
+	}
+	// Other owners' mappings are reported as missing
+	if mapping.OwnerID != ownerID {
+		return nil, domainInterop.ErrPartnerNotFound
+	}
+	return mapping, nil
+}
+
+func (s *Service) getActiveMapping(ctx context.Context, ownerID, mappingID uuid.UUID) (*domainInterop.PartnerMapping, error) {
+	mapping, err := s.getOwnedMapping(ctx, ownerID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+	if !mapping.IsActive {
+		return nil, domainInterop.ErrPartnerInactive
+	}
+	return mapping, nil
+}
+
+func applyMapping(mapping *domainInterop.PartnerMapping, req *SavePartnerMappingRequest) {
+	mapping.Name = req.Name
+	mapping.Format = req.Format
+	mapping.RecordsPath = req.RecordsPath
+	mapping.ImportFields = req.ImportFields
+	mapping.DefaultProviderID = req.DefaultProviderID
+	mapping.ExportFields = req.ExportFields
+	if req.IsActive != nil {
+		mapping.IsActive = *req.IsActive
+	}
+}
+
+func importErrorMessage(err error) string {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		if appErr.Err != nil {
+			return appErr.Message + ": " + appErr.Err.Error()
+		}
+		return appErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,44 @@
+package interop
+
+import (
+	appErrors "cargo-tracker/pkg/errors"
+	"fmt"
+	"regexp"
+)
+
+// pathPattern accepts dotted paths of JSON keys and array indexes
+var pathPattern = regexp.MustCompile(`^[A-Za-z0-9_@$:-]+(\.[A-Za-z0-9_@$:-]+)*$`)
+
+// ValidateMapping checks that a mapping only references known fields and
+// well-formed message paths
+func ValidateMapping(req *SavePartnerMappingRequest) error {
+	if req.RecordsPath != "" && !pathPattern.MatchString(req.RecordsPath) {
+		return appErrors.NewAppError("INVALID_MAPPING", "Records path must be a dotted path such as \"data.shipments\"", nil)
+	}
+
+	for field, path := range req.ImportFields {
+		if !contains(importFieldNames, field) {
+			return appErrors.NewAppError("INVALID_MAPPING", fmt.Sprintf("Unknown import field %q", field), nil)
+		}
+		if !pathPattern.MatchString(path) {
+			return appErrors.NewAppError("INVALID_MAPPING", fmt.Sprintf("Invalid path %q for import field %q", path, field), nil)
+		}
+	}
+
+	for key, field := range req.ExportFields {
+		if !contains(exportFieldNames, field) {
+			return appErrors.NewAppError("INVALID_MAPPING", fmt.Sprintf("Unknown milestone field %q for export key %q", field, key), nil)
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
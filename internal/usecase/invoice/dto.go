@@ -0,0 +1,176 @@
+package invoice
+
+import (
+	"time"
+
+	domainInvoice "cargo-tracker/internal/domain/invoice"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type ClosePeriodRequest struct {
+	// Billing month in YYYY-MM form
+	Period string `json:"period" validate:"required,datetime=2006-01"`
+}
+
+type AdjustmentRequest struct {
+	Amount      float64    `json:"amount" validate:"required,ne=0"`
+	Description string     `json:"description" validate:"required,min=3,max=500"`
+	ShipmentID  *uuid.UUID `json:"shipment_id" validate:"omitempty"`
+}
+
+type PaymentRequest struct {
+	Paid      bool       `json:"paid"`
+	Reference *string    `json:"reference" validate:"omitempty,max=255"`
+	PaidAt    *time.Time `json:"paid_at" validate:"omitempty"`
+}
+
+type InvoiceFilterRequest struct {
+	Status   *domainInvoice.Status `form:"status" validate:"omitempty,oneof=issued paid void"`
+	Period   string                `form:"period" validate:"omitempty,datetime=2006-01"`
+	Page     int                   `form:"page,default=1" validate:"min=1"`
+	PageSize int                   `form:"page_size,default=20" validate:"min=1,max=100"`
+}
+
+type EarningsRequest struct {
+	// Settlement month in YYYY-MM form, the current month when empty
+	Period string `form:"period" validate:"omitempty,datetime=2006-01"`
+}
+
+type EarningsSummaryRequest struct {
+	Months int `form:"months,default=6" validate:"min=1,max=24"`
+}
+
+// Response DTOs
+type InvoiceLineResponse struct {
+	ID          uuid.UUID              `json:"id"`
+	ShipmentID  *uuid.UUID             `json:"shipment_id,omitempty"`
+	Kind        domainInvoice.LineKind `json:"kind"`
+	Description string                 `json:"description"`
+	Amount      float64                `json:"amount"`
+	Priced      bool                   `json:"priced"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+type InvoiceResponse struct {
+	ID               uuid.UUID             `json:"id"`
+	Number           string                `json:"number"`
+	ProviderID       uuid.UUID             `json:"provider_id"`
+	CustomerID       uuid.UUID             `json:"customer_id"`
+	PeriodStart      time.Time             `json:"period_start"`
+	PeriodEnd        time.Time             `json:"period_end"`
+	Currency         string                `json:"currency"`
+	Subtotal         float64               `json:"subtotal"`
+	Adjustments      float64               `json:"adjustments"`
+	Total            float64               `json:"total"`
+	Status           domainInvoice.Status  `json:"status"`
+	DueAt            time.Time             `json:"due_at"`
+	PaidAt           *time.Time            `json:"paid_at,omitempty"`
+	PaymentReference *string               `json:"payment_reference,omitempty"`
+	UnpricedLines    int                   `json:"unpriced_lines"`
+	Lines            []InvoiceLineResponse `json:"lines,omitempty"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+type InvoiceListResponse struct {
+	Invoices   []InvoiceResponse `json:"invoices"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+}
+
+type ClosePeriodResponse struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Shipments   int       `json:"shipments"`
+	Invoices    int       `json:"invoices"`
+	// Invoices that already existed for the period and were left unchanged
+	Skipped  int `json:"skipped"`
+	Unpriced int `json:"unpriced"`
+}
+
+type EarningLineResponse struct {
+	ShipmentID     uuid.UUID `json:"shipment_id"`
+	DeliveredAt    time.Time `json:"delivered_at"`
+	Description    string    `json:"description"`
+	Currency       string    `json:"currency"`
+	Fee            float64   `json:"fee"`
+	Penalty        float64   `json:"penalty"`
+	Bonus          float64   `json:"bonus"`
+	Net            float64   `json:"net"`
+	DaysLate       int       `json:"days_late,omitempty"`
+	CustomerRating *int      `json:"customer_rating,omitempty"`
+	Priced         bool      `json:"priced"`
+}
+
+type EarningsTotalResponse struct {
+	Currency   string  `json:"currency"`
+	Deliveries int     `json:"deliveries"`
+	Fees       float64 `json:"fees"`
+	Penalties  float64 `json:"penalties"`
+	Bonuses    float64 `json:"bonuses"`
+	Net        float64 `json:"net"`
+}
+
+type EarningsReportResponse struct {
+	ShipperID   uuid.UUID `json:"shipper_id"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// False while the period is still running and figures can change
+	Final    bool                    `json:"final"`
+	Totals   []EarningsTotalResponse `json:"totals"`
+	Unpriced int                     `json:"unpriced"`
+	Lines    []EarningLineResponse   `json:"lines"`
+}
+
+type EarningsMonthResponse struct {
+	Period     string                  `json:"period"`
+	Deliveries int                     `json:"deliveries"`
+	Totals     []EarningsTotalResponse `json:"totals"`
+}
+
+type EarningsSummaryResponse struct {
+	ShipperID uuid.UUID               `json:"shipper_id"`
+	Months    []EarningsMonthResponse `json:"months"`
+}
+
+func ToInvoiceResponse(i *domainInvoice.Invoice, withLines bool) *InvoiceResponse {
+	resp := &InvoiceResponse{
+		ID:               i.ID,
+		Number:           i.Number,
+		ProviderID:       i.ProviderID,
+		CustomerID:       i.CustomerID,
+		PeriodStart:      i.PeriodStart,
+		PeriodEnd:        i.PeriodEnd,
+		Currency:         i.Currency,
+		Subtotal:         i.Subtotal,
+		Adjustments:      i.Adjustments,
+		Total:            i.Total,
+		Status:           i.Status,
+		DueAt:            i.DueAt,
+		PaidAt:           i.PaidAt,
+		PaymentReference: i.PaymentReference,
+		CreatedAt:        i.CreatedAt,
+		UpdatedAt:        i.UpdatedAt,
+	}
+	for _, line := range i.Lines {
+		if !line.Priced {
+			resp.UnpricedLines++
+		}
+		if withLines {
+			resp.Lines = append(resp.Lines, InvoiceLineResponse{
+				ID:          line.ID,
+				ShipmentID:  line.ShipmentID,
+				Kind:        line.Kind,
+				Description: line.Description,
+				Amount:      line.Amount,
+				Priced:      line.Priced,
+				CreatedAt:   line.CreatedAt,
+			})
+		}
+	}
+	return resp
+}
@@ -0,0 +1,233 @@
+package invoice
+
+import (
+	"bytes"
+	domainInvoice "cargo-tracker/internal/domain/invoice"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Shipper earnings are derived from delivered shipments rather than stored:
+// the shipper earns ShipperFeePercent of the agreed price, loses the same
+// share of the late delivery credit the customer is given, and earns
+// ShipperBonusPercent of the fee for on-time deliveries rated 5 stars.
+
+// GetEarnings returns the settlement report of a shipper for one month,
+// the current month when no period is given
+func (s *Service) GetEarnings(ctx context.Context, shipperID uuid.UUID, req *EarningsRequest) (*EarningsReportResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	start := monthStart(time.Now())
+	if req.Period != "" {
+		start, _ = time.Parse("2006-01", req.Period)
+	}
+	end := start.AddDate(0, 1, 0)
+
+	deliveries, err := s.invoiceRepo.ListShipperDeliveries(ctx, shipperID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &EarningsReportResponse{
+		ShipperID:   shipperID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Final:       !end.After(time.Now()),
+		Lines:       make([]EarningLineResponse, 0, len(deliveries)),
+	}
+	for _, d := range deliveries {
+		line := s.earnShipment(d)
+		if !line.Priced {
+			report.Unpriced++
+		}
+		report.Lines = append(report.Lines, line)
+	}
+	report.Totals = earningTotals(report.Lines)
+
+	return report, nil
+}
+
+// GetShipperEarnings returns the settlement report of any shipper for admins
+func (s *Service) GetShipperEarnings(ctx context.Context, shipperID uuid.UUID, req *EarningsRequest) (*EarningsReportResponse, error) {
+	shipper, err := s.userRepo.GetByID(ctx, shipperID)
+	if err != nil {
+		if errors.Is(err, domainUser.ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to load shipper: %w", err)
+	}
+	if shipper.Role != "shipper" {
+		return nil, appErrors.NewAppError("NOT_A_SHIPPER", "User is not a shipper", nil)
+	}
+	return s.GetEarnings(ctx, shipperID, req)
+}
+
+// GetEarningsSummary returns the monthly totals of a shipper for the
+// dashboard, most recent month first
+func (s *Service) GetEarningsSummary(ctx context.Context, shipperID uuid.UUID, req *EarningsSummaryRequest) (*EarningsSummaryResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	end := monthStart(time.Now()).AddDate(0, 1, 0)
+	start := end.AddDate(0, -req.Months, 0)
+
+	deliveries, err := s.invoiceRepo.ListShipperDeliveries(ctx, shipperID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	byMonth := make(map[string][]EarningLineResponse)
+	for _, d := range deliveries {
+		period := d.ActualDeliveryAt.UTC().Format("2006-01")
+		byMonth[period] = append(byMonth[period], s.earnShipment(d))
+	}
+
+	summary := &EarningsSummaryResponse{
+		ShipperID: shipperID,
+		Months:    make([]EarningsMonthResponse, 0, req.Months),
+	}
+	for month := end.AddDate(0, -1, 0); !month.Before(start); month = month.AddDate(0, -1, 0) {
+		period := month.Format("2006-01")
+		lines := byMonth[period]
+		summary.Months = append(summary.Months, EarningsMonthResponse{
+			Period:     period,
+			Deliveries: len(lines),
+			Totals:     earningTotals(lines),
+		})
+	}
+
+	return summary, nil
+}
+
+// ExportEarningsCSV returns the settlement report of a shipper as CSV, one
+// row per delivery followed by a total row per currency. The file name is
+// returned alongside.
+func (s *Service) ExportEarningsCSV(ctx context.Context, shipperID uuid.UUID, req *EarningsRequest) ([]byte, string, error) {
+	report, err := s.GetEarnings(ctx, shipperID, req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"period_start", "period_end", "shipper_id", "shipment_id", "delivered_at",
+		"description", "currency", "fee", "penalty", "bonus", "net", "priced"})
+
+	periodStart := report.PeriodStart.Format(time.DateOnly)
+	periodEnd := report.PeriodEnd.Format(time.DateOnly)
+	for _, line := range report.Lines {
+		_ = w.Write([]string{
+			periodStart,
+			periodEnd,
+			shipperID.String(),
+			line.ShipmentID.String(),
+			line.DeliveredAt.UTC().Format(time.RFC3339),
+			line.Description,
+			line.Currency,
+			formatAmount(line.Fee),
+			formatAmount(line.Penalty),
+			formatAmount(line.Bonus),
+			formatAmount(line.Net),
+			strconv.FormatBool(line.Priced),
+		})
+	}
+	for _, total := range report.Totals {
+		_ = w.Write([]string{
+			periodStart, periodEnd, shipperID.String(), "", "", "Total", total.Currency,
+			formatAmount(total.Fees),
+			formatAmount(total.Penalties),
+			formatAmount(total.Bonuses),
+			formatAmount(total.Net),
+			"true",
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, "", fmt.Errorf("failed to write earnings CSV: %w", err)
+	}
+
+	logger.Info("Shipper earnings exported",
+		zap.String("shipper_id", shipperID.String()),
+		zap.String("period", report.PeriodStart.Format("2006-01")),
+		zap.String("event", "earnings_exported"),
+	)
+
+	name := "earnings-" + report.PeriodStart.Format("2006-01") + "-" + strings.ToUpper(shipperID.String()[:8])
+	return buf.Bytes(), name, nil
+}
+
+func (s *Service) earnShipment(d *domainInvoice.ShipperDelivery) EarningLineResponse {
+	description := "Shipment " + strings.ToUpper(d.ShipmentID.String()[:8]) + " - " + truncate(d.GoodsDescription, 80)
+	if d.Status == "partially_completed" {
+		description += " (partial delivery)"
+	}
+
+	line := EarningLineResponse{
+		ShipmentID:     d.ShipmentID,
+		DeliveredAt:    d.ActualDeliveryAt,
+		Description:    description,
+		Currency:       s.cfg.DefaultCurrency,
+		CustomerRating: d.CustomerRating,
+		Priced:         d.Price != nil,
+	}
+	if d.Currency != nil {
+		line.Currency = *d.Currency
+	}
+	if !line.Priced {
+		return line
+	}
+
+	line.Fee = roundAmount(*d.Price * s.cfg.ShipperFeePercent / 100)
+	line.DaysLate = daysLate(d.DeliveryDueAt, &d.ActualDeliveryAt)
+	if line.DaysLate > 0 {
+		percent := math.Min(float64(line.DaysLate)*s.cfg.SLAPenaltyPercentPerDay, s.cfg.SLAPenaltyMaxPercent)
+		line.Penalty = -roundAmount(line.Fee * percent / 100)
+	} else if d.CustomerRating != nil && *d.CustomerRating == 5 {
+		line.Bonus = roundAmount(line.Fee * s.cfg.ShipperBonusPercent / 100)
+	}
+	line.Net = roundAmount(line.Fee + line.Penalty + line.Bonus)
+
+	return line
+}
+
+// earningTotals sums earning lines per currency, sorted by currency
+func earningTotals(lines []EarningLineResponse) []EarningsTotalResponse {
+	byCurrency := make(map[string]*EarningsTotalResponse)
+	for _, line := range lines {
+		total, ok := byCurrency[line.Currency]
+		if !ok {
+			total = &EarningsTotalResponse{Currency: line.Currency}
+			byCurrency[line.Currency] = total
+		}
+		total.Deliveries++
+		total.Fees = roundAmount(total.Fees + line.Fee)
+		total.Penalties = roundAmount(total.Penalties + line.Penalty)
+		total.Bonuses = roundAmount(total.Bonuses + line.Bonus)
+		total.Net = roundAmount(total.Net + line.Net)
+	}
+
+	totals := make([]EarningsTotalResponse, 0, len(byCurrency))
+	for _, total := range byCurrency {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+	return totals
+}
@@ -0,0 +1,174 @@
+package invoice
+
+import (
+	"bytes"
+	domainInvoice "cargo-tracker/internal/domain/invoice"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/pkg/pdf"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ExportPDF renders an invoice for its provider or customer. The invoice
+// number is returned for the file name.
+func (s *Service) ExportPDF(ctx context.Context, userID, invoiceID uuid.UUID) ([]byte, string, error) {
+	inv, err := s.getAccessible(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.SetTitle("Invoice " + inv.Number)
+	page := doc.AddPage()
+
+	const margin = 48.0
+	width := pdf.A4Width - 2*margin
+	amountX := margin + width - 90
+
+	page.Text(margin, 64, 20, true, "Invoice "+inv.Number)
+	page.Text(margin, 84, 9, false, "Billing period: "+formatPeriod(inv))
+	page.Text(margin, 98, 9, false, "Issued: "+inv.CreatedAt.UTC().Format("2006-01-02"))
+	page.Text(margin, 112, 9, false, "Due: "+inv.DueAt.UTC().Format("2006-01-02"))
+	page.Text(margin, 126, 9, true, "Status: "+string(inv.Status))
+
+	y := 160.0
+	page.Text(margin, y, 10, true, "From")
+	page.Text(margin+60, y, 10, false, s.partyName(ctx, inv.ProviderID))
+	page.Text(margin, y+16, 10, true, "Bill to")
+	page.Text(margin+60, y+16, 10, false, s.partyName(ctx, inv.CustomerID))
+
+	y += 52
+	drawHeader := func(y float64) float64 {
+		page.Text(margin, y, 9, true, "Description")
+		page.Text(amountX, y, 9, true, "Amount ("+inv.Currency+")")
+		page.Line(margin, y+5, margin+width, y+5, 0.75)
+		return y + 20
+	}
+	y = drawHeader(y)
+
+	for _, line := range inv.Lines {
+		description := line.Description
+		if !line.Priced {
+			description += " [no agreed price]"
+		}
+		wrapped := pdf.Wrap(description, 9, amountX-margin-12)
+		if y+float64(len(wrapped))*12 > pdf.A4Height-120 {
+			page = doc.AddPage()
+			y = drawHeader(64)
+		}
+		page.Text(amountX, y, 9, false, formatAmount(line.Amount))
+		for _, text := range wrapped {
+			page.Text(margin, y, 9, false, text)
+			y += 12
+		}
+		y += 4
+	}
+
+	y += 8
+	page.Line(margin, y, margin+width, y, 0.75)
+	y += 16
+	for _, total := range []struct {
+		label  string
+		amount float64
+		bold   bool
+	}{
+		{"Subtotal", inv.Subtotal, false},
+		{"Adjustments", inv.Adjustments, false},
+		{"Total", inv.Total, true},
+	} {
+		page.Text(amountX-100, y, 10, total.bold, total.label)
+		page.Text(amountX, y, 10, total.bold, formatAmount(total.amount))
+		y += 16
+	}
+
+	if inv.PaidAt != nil {
+		paid := "Paid " + inv.PaidAt.UTC().Format("2006-01-02")
+		if inv.PaymentReference != nil {
+			paid += ", reference " + *inv.PaymentReference
+		}
+		page.Text(margin, y+12, 9, false, paid)
+	}
+
+	logger.Info("Invoice exported",
+		zap.String("invoice_id", inv.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("format", "pdf"),
+		zap.String("event", "invoice_exported"),
+	)
+
+	return doc.Bytes(), inv.Number, nil
+}
+
+// ExportCSV returns the lines of an invoice as CSV for accounting imports,
+// one row per line followed by the totals
+func (s *Service) ExportCSV(ctx context.Context, userID, invoiceID uuid.UUID) ([]byte, string, error) {
+	inv, err := s.getAccessible(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"invoice_number", "period_start", "period_end", "provider_id", "customer_id",
+		"shipment_id", "kind", "description", "amount", "currency", "priced"})
+
+	row := func(shipmentID, kind, description string, amount float64, priced bool) {
+		_ = w.Write([]string{
+			inv.Number,
+			inv.PeriodStart.UTC().Format(time.DateOnly),
+			inv.PeriodEnd.UTC().Format(time.DateOnly),
+			inv.ProviderID.String(),
+			inv.CustomerID.String(),
+			shipmentID,
+			kind,
+			description,
+			formatAmount(amount),
+			inv.Currency,
+			strconv.FormatBool(priced),
+		})
+	}
+	for _, line := range inv.Lines {
+		shipmentID := ""
+		if line.ShipmentID != nil {
+			shipmentID = line.ShipmentID.String()
+		}
+		row(shipmentID, string(line.Kind), line.Description, line.Amount, line.Priced)
+	}
+	row("", "total", "Invoice total", inv.Total, true)
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, "", fmt.Errorf("failed to write invoice CSV: %w", err)
+	}
+
+	logger.Info("Invoice exported",
+		zap.String("invoice_id", inv.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("format", "csv"),
+		zap.String("event", "invoice_exported"),
+	)
+
+	return buf.Bytes(), inv.Number, nil
+}
+
+func (s *Service) partyName(ctx context.Context, userID uuid.UUID) string {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return userID.String()
+	}
+	return u.FullName + " <" + u.Email + ">"
+}
+
+func formatPeriod(inv *domainInvoice.Invoice) string {
+	return inv.PeriodStart.UTC().Format("January 2006")
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
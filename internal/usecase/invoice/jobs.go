@@ -0,0 +1,74 @@
+package invoice
+
+import (
+	"cargo-tracker/internal/usecase/job"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of background job run by the service
+const (
+	JobKindClosePeriod    = "invoice.close_period"
+	JobKindEarningsExport = "invoice.earnings_csv"
+)
+
+// RegisterJobs registers the background jobs of the service
+func (s *Service) RegisterJobs(jobs *job.Service) {
+	s.jobs = jobs
+	// Closing a period only bills shipments that are not invoiced yet, and
+	// exports only read, so both are safe to retry
+	jobs.Register(JobKindClosePeriod, s.runClosePeriodJob, job.RetryPolicy{MaxAttempts: 3, Backoff: time.Minute})
+	jobs.Register(JobKindEarningsExport, s.runEarningsExportJob, job.RetryPolicy{MaxAttempts: 3, Backoff: 30 * time.Second})
+}
+
+// QueueClosePeriod queues closing a billing month. The job result is the
+// ClosePeriodResponse of a synchronous close.
+func (s *Service) QueueClosePeriod(ctx context.Context, adminID uuid.UUID, req *ClosePeriodRequest) (*job.JobResponse, error) {
+	if _, err := closablePeriod(req); err != nil {
+		return nil, err
+	}
+	return s.jobs.Enqueue(ctx, adminID, JobKindClosePeriod, req)
+}
+
+// QueueEarningsCSV queues the settlement report of a shipper. The job
+// result is a CSV file.
+func (s *Service) QueueEarningsCSV(ctx context.Context, shipperID uuid.UUID, req *EarningsRequest) (*job.JobResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	return s.jobs.Enqueue(ctx, shipperID, JobKindEarningsExport, req)
+}
+
+func (s *Service) runClosePeriodJob(ctx context.Context, run *job.Run) (*job.Output, error) {
+	var req ClosePeriodRequest
+	if err := run.Decode(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.ClosePeriodByName(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	return &job.Output{Result: resp}, nil
+}
+
+func (s *Service) runEarningsExportJob(ctx context.Context, run *job.Run) (*job.Output, error) {
+	var req EarningsRequest
+	if err := run.Decode(&req); err != nil {
+		return nil, err
+	}
+
+	content, name, err := s.ExportEarningsCSV(ctx, run.Job.OwnerID, &req)
+	if err != nil {
+		return nil, err
+	}
+	return &job.Output{File: &job.OutputFile{
+		Name:        name + ".csv",
+		ContentType: "text/csv; charset=utf-8",
+		Data:        content,
+	}}, nil
+}
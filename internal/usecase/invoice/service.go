@@ -0,0 +1,474 @@
+package invoice
+
+import (
+	"cargo-tracker/internal/config"
+	domainInvoice "cargo-tracker/internal/domain/invoice"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/job"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service closes monthly billing periods into invoices and manages them
+// afterwards. Shipment fees come from the quote the customer accepted;
+// shipments created without a quote are billed at zero and flagged so the
+// provider can price them with an adjustment.
+type Service struct {
+	invoiceRepo domainInvoice.Repository
+	userRepo    domainUser.Repository
+	cfg         config.InvoicingConfig
+
+	jobs *job.Service
+}
+
+// NewService creates a new invoice service
+func NewService(invoiceRepo domainInvoice.Repository, userRepo domainUser.Repository, cfg config.InvoicingConfig) *Service {
+	if cfg.DefaultCurrency == "" {
+		cfg.DefaultCurrency = "VND"
+	}
+	return &Service{
+		invoiceRepo: invoiceRepo,
+		userRepo:    userRepo,
+		cfg:         cfg,
+	}
+}
+
+// StartPeriodCloseJob closes the previous month once a day. Closing is
+// idempotent, so runs after the first only pick up shipments that were not
+// billed yet.
+func (s *Service) StartPeriodCloseJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger.Info("Invoice period close job started",
+		zap.Duration("interval", interval),
+	)
+
+	s.closePreviousPeriod(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Invoice period close job stopped")
+			return
+		case <-ticker.C:
+			s.closePreviousPeriod(ctx)
+		}
+	}
+}
+
+func (s *Service) closePreviousPeriod(ctx context.Context) {
+	start := monthStart(time.Now()).AddDate(0, -1, 0)
+	if _, err := s.ClosePeriod(ctx, start); err != nil {
+		logger.Error("Failed to close billing period",
+			zap.Time("period_start", start),
+			zap.Error(err),
+		)
+	}
+}
+
+// ClosePeriodByName closes the billing month given as YYYY-MM
+func (s *Service) ClosePeriodByName(ctx context.Context, req *ClosePeriodRequest) (*ClosePeriodResponse, error) {
+	start, err := closablePeriod(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.ClosePeriod(ctx, start)
+}
+
+// closablePeriod returns the start of the requested billing month, which
+// must have ended
+func closablePeriod(req *ClosePeriodRequest) (time.Time, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return time.Time{}, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	start, _ := time.Parse("2006-01", req.Period)
+	if !start.Before(monthStart(time.Now())) {
+		return time.Time{}, appErrors.NewAppError("PERIOD_NOT_ENDED", "Only past billing periods can be closed", nil)
+	}
+	return start, nil
+}
+
+// ClosePeriod invoices every shipment finished in the month starting at
+// periodStart that is not on an invoice yet, one invoice per provider,
+// customer and currency
+func (s *Service) ClosePeriod(ctx context.Context, periodStart time.Time) (*ClosePeriodResponse, error) {
+	start := monthStart(periodStart)
+	end := start.AddDate(0, 1, 0)
+
+	billable, err := s.invoiceRepo.ListBillable(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct {
+		providerID uuid.UUID
+		customerID uuid.UUID
+		currency   string
+	}
+	groups := make(map[groupKey]*domainInvoice.Invoice)
+	var order []groupKey
+
+	resp := &ClosePeriodResponse{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Shipments:   len(billable),
+	}
+
+	for _, b := range billable {
+		currency := s.cfg.DefaultCurrency
+		if b.Currency != nil && *b.Currency != "" {
+			currency = *b.Currency
+		}
+		key := groupKey{providerID: b.ProviderID, customerID: b.CustomerID, currency: currency}
+
+		inv, ok := groups[key]
+		if !ok {
+			inv = &domainInvoice.Invoice{
+				ID:          uuid.New(),
+				ProviderID:  b.ProviderID,
+				CustomerID:  b.CustomerID,
+				PeriodStart: start,
+				PeriodEnd:   end,
+				Currency:    currency,
+				Status:      domainInvoice.StatusIssued,
+				DueAt:       end.AddDate(0, 0, s.cfg.PaymentTermsDays),
+			}
+			inv.Number = invoiceNumber(start, inv.ID)
+			groups[key] = inv
+			order = append(order, key)
+		}
+
+		lines := s.billShipment(b)
+		for _, line := range lines {
+			if !line.Priced {
+				resp.Unpriced++
+			}
+		}
+		inv.Lines = append(inv.Lines, lines...)
+	}
+
+	for _, key := range order {
+		inv := groups[key]
+		inv.Recalculate()
+
+		if err := s.invoiceRepo.Create(ctx, inv); err != nil {
+			if errors.Is(err, domainInvoice.ErrInvoiceExists) {
+				logger.Warn("Invoice already exists for period, shipments left unbilled",
+					zap.String("provider_id", inv.ProviderID.String()),
+					zap.String("customer_id", inv.CustomerID.String()),
+					zap.Time("period_start", start),
+					zap.Int("lines", len(inv.Lines)),
+				)
+				resp.Skipped++
+				continue
+			}
+			return nil, err
+		}
+		resp.Invoices++
+
+		logger.Info("Invoice issued",
+			zap.String("invoice_id", inv.ID.String()),
+			zap.String("number", inv.Number),
+			zap.String("provider_id", inv.ProviderID.String()),
+			zap.String("customer_id", inv.CustomerID.String()),
+			zap.Float64("total", inv.Total),
+			zap.String("event", "invoice_issued"),
+		)
+	}
+
+	logger.Info("Billing period closed",
+		zap.Time("period_start", start),
+		zap.Int("shipments", resp.Shipments),
+		zap.Int("invoices", resp.Invoices),
+		zap.Int("skipped", resp.Skipped),
+		zap.Int("unpriced", resp.Unpriced),
+		zap.String("event", "billing_period_closed"),
+	)
+
+	return resp, nil
+}
+
+// billShipment returns the invoice lines of a finished shipment: its fee, or
+// the cancellation fee, and a penalty credit when it was delivered late
+func (s *Service) billShipment(b *domainInvoice.BillableShipment) []domainInvoice.InvoiceLine {
+	shipmentID := b.ShipmentID
+	ref := "Shipment " + strings.ToUpper(shipmentID.String()[:8])
+	priced := b.Price != nil
+	price := 0.0
+	if priced {
+		price = *b.Price
+	}
+
+	if b.Status == "cancelled" {
+		return []domainInvoice.InvoiceLine{{
+			ShipmentID:  &shipmentID,
+			Kind:        domainInvoice.LineCancellationFee,
+			Description: fmt.Sprintf("%s cancelled after acceptance (%.0f%% fee)", ref, s.cfg.CancellationFeePercent),
+			Amount:      roundAmount(price * s.cfg.CancellationFeePercent / 100),
+			Priced:      priced,
+		}}
+	}
+
+	description := ref + " - " + truncate(b.GoodsDescription, 80)
+	if b.Status == "partially_completed" {
+		description += " (partial delivery)"
+	}
+	lines := []domainInvoice.InvoiceLine{{
+		ShipmentID:  &shipmentID,
+		Kind:        domainInvoice.LineShipmentFee,
+		Description: description,
+		Amount:      roundAmount(price),
+		Priced:      priced,
+	}}
+
+	if days := daysLate(b.DeliveryDueAt, b.ActualDeliveryAt); days > 0 && priced {
+		percent := math.Min(float64(days)*s.cfg.SLAPenaltyPercentPerDay, s.cfg.SLAPenaltyMaxPercent)
+		if penalty := roundAmount(price * percent / 100); penalty > 0 {
+			lines = append(lines, domainInvoice.InvoiceLine{
+				ShipmentID:  &shipmentID,
+				Kind:        domainInvoice.LineSLAPenalty,
+				Description: fmt.Sprintf("%s delivered %d day(s) late (%.0f%% credit)", ref, days, percent),
+				Amount:      -penalty,
+				Priced:      true,
+			})
+		}
+	}
+
+	return lines
+}
+
+// ListInvoices returns the invoices a provider issued or a customer received
+func (s *Service) ListInvoices(ctx context.Context, userID uuid.UUID, role string, req *InvoiceFilterRequest) (*InvoiceListResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	filter := &domainInvoice.Filter{
+		Status:   req.Status,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	switch role {
+	case "provider":
+		filter.ProviderID = &userID
+	case "customer":
+		filter.CustomerID = &userID
+	default:
+		return nil, appErrors.ErrUnauthorized
+	}
+	if req.Period != "" {
+		start, _ := time.Parse("2006-01", req.Period)
+		filter.PeriodStart = &start
+	}
+
+	invoices, total, err := s.invoiceRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &InvoiceListResponse{
+		Invoices:   make([]InvoiceResponse, len(invoices)),
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int((total + int64(req.PageSize) - 1) / int64(req.PageSize)),
+	}
+	for i, inv := range invoices {
+		resp.Invoices[i] = *ToInvoiceResponse(inv, false)
+	}
+	return resp, nil
+}
+
+// GetInvoice returns an invoice with its lines to its provider or customer
+func (s *Service) GetInvoice(ctx context.Context, userID, invoiceID uuid.UUID) (*InvoiceResponse, error) {
+	inv, err := s.getAccessible(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return ToInvoiceResponse(inv, true), nil
+}
+
+// AddAdjustment adds a manual charge or credit to an open invoice. Referencing
+// a shipment on the invoice with an unpriced fee line is how such shipments
+// are priced after the fact.
+func (s *Service) AddAdjustment(ctx context.Context, providerID, invoiceID uuid.UUID, req *AdjustmentRequest) (*InvoiceResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	inv, err := s.getOwned(ctx, providerID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status != domainInvoice.StatusIssued {
+		return nil, domainInvoice.ErrInvoiceClosed
+	}
+
+	if req.ShipmentID != nil && !invoiceCovers(inv, *req.ShipmentID) {
+		return nil, appErrors.NewAppError("SHIPMENT_NOT_ON_INVOICE", "Shipment is not billed on this invoice", nil)
+	}
+
+	line := domainInvoice.InvoiceLine{
+		ShipmentID:  req.ShipmentID,
+		Kind:        domainInvoice.LineAdjustment,
+		Description: strings.TrimSpace(req.Description),
+		Amount:      roundAmount(req.Amount),
+		Priced:      true,
+	}
+	inv.Lines = append(inv.Lines, line)
+	inv.Recalculate()
+	if inv.Total < 0 {
+		return nil, appErrors.NewAppError("NEGATIVE_TOTAL", "Adjustment would make the invoice total negative", nil)
+	}
+
+	if err := s.invoiceRepo.AddLine(ctx, inv, &inv.Lines[len(inv.Lines)-1]); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Invoice adjusted",
+		zap.String("invoice_id", inv.ID.String()),
+		zap.Float64("amount", line.Amount),
+		zap.String("event", "invoice_adjusted"),
+	)
+
+	return ToInvoiceResponse(inv, true), nil
+}
+
+// RecordPayment marks an invoice paid, or back to issued when a payment was
+// recorded by mistake
+func (s *Service) RecordPayment(ctx context.Context, providerID, invoiceID uuid.UUID, req *PaymentRequest) (*InvoiceResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	inv, err := s.getOwned(ctx, providerID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status == domainInvoice.StatusVoid {
+		return nil, domainInvoice.ErrInvoiceClosed
+	}
+
+	if req.Paid {
+		paidAt := time.Now()
+		if req.PaidAt != nil {
+			paidAt = *req.PaidAt
+		}
+		inv.Status = domainInvoice.StatusPaid
+		inv.PaidAt = &paidAt
+		inv.PaymentReference = req.Reference
+	} else {
+		inv.Status = domainInvoice.StatusIssued
+		inv.PaidAt = nil
+		inv.PaymentReference = nil
+	}
+
+	if err := s.invoiceRepo.UpdateStatus(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Invoice payment recorded",
+		zap.String("invoice_id", inv.ID.String()),
+		zap.Bool("paid", req.Paid),
+		zap.String("event", "invoice_payment_recorded"),
+	)
+
+	return ToInvoiceResponse(inv, true), nil
+}
+
+// VoidInvoice cancels an unpaid invoice. Its shipments stay billed and are
+// not picked up by later period closes.
+func (s *Service) VoidInvoice(ctx context.Context, providerID, invoiceID uuid.UUID) (*InvoiceResponse, error) {
+	inv, err := s.getOwned(ctx, providerID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status != domainInvoice.StatusIssued {
+		return nil, domainInvoice.ErrInvoiceClosed
+	}
+
+	inv.Status = domainInvoice.StatusVoid
+	if err := s.invoiceRepo.UpdateStatus(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Invoice voided",
+		zap.String("invoice_id", inv.ID.String()),
+		zap.String("event", "invoice_voided"),
+	)
+
+	return ToInvoiceResponse(inv, true), nil
+}
+
+// getAccessible returns an invoice to its provider or customer
+func (s *Service) getAccessible(ctx context.Context, userID, invoiceID uuid.UUID) (*domainInvoice.Invoice, error) {
+	inv, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.ProviderID != userID && inv.CustomerID != userID {
+		// Do not reveal invoices of other users
+		return nil, domainInvoice.ErrInvoiceNotFound
+	}
+	return inv, nil
+}
+
+// getOwned returns an invoice to the provider that issued it
+func (s *Service) getOwned(ctx context.Context, providerID, invoiceID uuid.UUID) (*domainInvoice.Invoice, error) {
+	inv, err := s.getAccessible(ctx, providerID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.ProviderID != providerID {
+		return nil, appErrors.ErrUnauthorized
+	}
+	return inv, nil
+}
+
+func invoiceCovers(inv *domainInvoice.Invoice, shipmentID uuid.UUID) bool {
+	for _, line := range inv.Lines {
+		if line.ShipmentID != nil && *line.ShipmentID == shipmentID {
+			return true
+		}
+	}
+	return false
+}
+
+// daysLate counts started days between the due time and the delivery
+func daysLate(dueAt, deliveredAt *time.Time) int {
+	if dueAt == nil || deliveredAt == nil || !deliveredAt.After(*dueAt) {
+		return 0
+	}
+	return int(math.Ceil(deliveredAt.Sub(*dueAt).Hours() / 24))
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func invoiceNumber(periodStart time.Time, id uuid.UUID) string {
+	return "INV-" + periodStart.Format("200601") + "-" + strings.ToUpper(id.String()[:8])
+}
+
+func roundAmount(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -0,0 +1,64 @@
+package job
+
+import (
+	domainJob "cargo-tracker/internal/domain/job"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Response DTOs
+type JobResponse struct {
+	ID              uuid.UUID        `json:"id"`
+	Kind            string           `json:"kind"`
+	Status          domainJob.Status `json:"status"`
+	Progress        int              `json:"progress"`
+	ProgressMessage *string          `json:"progress_message,omitempty"`
+	Result          json.RawMessage  `json:"result,omitempty"`
+	ResultFile      *JobFileResponse `json:"result_file,omitempty"`
+	Error           *string          `json:"error,omitempty"`
+	Attempts        int              `json:"attempts"`
+	MaxAttempts     int              `json:"max_attempts"`
+	NextAttemptAt   *time.Time       `json:"next_attempt_at,omitempty"` // Set while a failed attempt waits for its retry
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	StartedAt       *time.Time       `json:"started_at,omitempty"`
+	FinishedAt      *time.Time       `json:"finished_at,omitempty"`
+}
+
+type JobFileResponse struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+func ToJobResponse(j *domainJob.Job) *JobResponse {
+	resp := &JobResponse{
+		ID:              j.ID,
+		Kind:            j.Kind,
+		Status:          j.Status,
+		Progress:        j.Progress,
+		ProgressMessage: j.ProgressMessage,
+		Result:          j.Result,
+		Error:           j.Error,
+		Attempts:        j.Attempts,
+		MaxAttempts:     j.MaxAttempts,
+		CreatedAt:       j.CreatedAt,
+		UpdatedAt:       j.UpdatedAt,
+		StartedAt:       j.StartedAt,
+		FinishedAt:      j.FinishedAt,
+	}
+	if j.Status == domainJob.StatusQueued && j.Attempts > 0 {
+		runAt := j.RunAt
+		resp.NextAttemptAt = &runAt
+	}
+	if f := j.ResultFile; f != nil {
+		resp.ResultFile = &JobFileResponse{
+			FileName:    f.FileName,
+			ContentType: f.ContentType,
+			SizeBytes:   f.SizeBytes,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,226 @@
+package job
+
+import (
+	"cargo-tracker/internal/config"
+	domainJob "cargo-tracker/internal/domain/job"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxListedJobs bounds the job history returned to a user
+const maxListedJobs = 50
+
+// Handler runs one attempt of a job. Application errors are reported to the
+// owner as-is and never retried; other errors are retried per the kind's
+// RetryPolicy.
+type Handler func(ctx context.Context, run *Run) (*Output, error)
+
+// Output is what a successful attempt produces: a JSON encodable result, a
+// file to download, or both
+type Output struct {
+	Result interface{}
+	File   *OutputFile
+}
+
+// OutputFile is a generated file kept as the result of a job
+type OutputFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// RetryPolicy controls how often a failing kind of job is attempted. The
+// wait before attempt n+1 is Backoff doubled n-1 times. Work that is not
+// safe to repeat must keep MaxAttempts at 1.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+type registration struct {
+	handler Handler
+	policy  RetryPolicy
+}
+
+// Service queues long-running work and runs it on a pool of workers.
+// Requests enqueue a job and return its handle; clients poll the job until
+// it has finished and then fetch its result.
+type Service struct {
+	repo   domainJob.Repository
+	store  domainStorage.Store
+	config config.JobsConfig
+
+	mu       sync.RWMutex
+	handlers map[string]registration
+
+	// Nudges an idle worker when a job is enqueued
+	wake chan struct{}
+}
+
+// NewService creates a new job service
+func NewService(repo domainJob.Repository, store domainStorage.Store, cfg config.JobsConfig) *Service {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 5 * time.Minute
+	}
+	return &Service{
+		repo:     repo,
+		store:    store,
+		config:   cfg,
+		handlers: make(map[string]registration),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register makes a kind of job runnable. Kinds are registered at startup,
+// before the workers start.
+func (s *Service) Register(kind string, handler Handler, policy RetryPolicy) {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Backoff <= 0 {
+		policy.Backoff = 30 * time.Second
+	}
+
+	s.mu.Lock()
+	s.handlers[kind] = registration{handler: handler, policy: policy}
+	s.mu.Unlock()
+}
+
+// Enqueue queues a job for ownerID. The payload is stored as JSON and
+// handed to the kind's handler.
+func (s *Service) Enqueue(ctx context.Context, ownerID uuid.UUID, kind string, payload interface{}) (*JobResponse, error) {
+	s.mu.RLock()
+	reg, ok := s.handlers[kind]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("job kind %q is not registered", kind)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	now := time.Now()
+	job := &domainJob.Job{
+		ID:          uuid.New(),
+		Kind:        kind,
+		OwnerID:     ownerID,
+		Status:      domainJob.StatusQueued,
+		Payload:     data,
+		MaxAttempts: reg.policy.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	logger.Info("Job queued",
+		zap.String("job_id", job.ID.String()),
+		zap.String("kind", kind),
+		zap.String("owner_id", ownerID.String()),
+		zap.String("event", "job_queued"),
+	)
+
+	return ToJobResponse(job), nil
+}
+
+// GetJob returns a job of the user. Admins see every job.
+func (s *Service) GetJob(ctx context.Context, userID uuid.UUID, role string, jobID uuid.UUID) (*JobResponse, error) {
+	job, err := s.visibleJob(ctx, userID, role, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return ToJobResponse(job), nil
+}
+
+// ListJobs returns the most recent jobs of the user
+func (s *Service) ListJobs(ctx context.Context, userID uuid.UUID) ([]*JobResponse, error) {
+	jobs, err := s.repo.ListByOwner(ctx, userID, maxListedJobs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*JobResponse, len(jobs))
+	for i, j := range jobs {
+		responses[i] = ToJobResponse(j)
+	}
+	return responses, nil
+}
+
+// OpenResult opens the file produced by a finished job. The caller must
+// close the returned reader.
+func (s *Service) OpenResult(ctx context.Context, userID uuid.UUID, role string, jobID uuid.UUID) (io.ReadCloser, *domainJob.File, error) {
+	job, err := s.visibleJob(ctx, userID, role, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != domainJob.StatusSucceeded {
+		return nil, nil, appErrors.NewAppError("JOB_NOT_FINISHED", "Job has not finished successfully", nil)
+	}
+	if job.ResultFile == nil {
+		return nil, nil, appErrors.NewAppError("NO_RESULT_FILE", "Job did not produce a file", nil)
+	}
+
+	content, err := s.store.Open(ctx, job.ResultFile.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, job.ResultFile, nil
+}
+
+// visibleJob loads a job the user may see. Other users' jobs are reported
+// as missing so their IDs cannot be probed.
+func (s *Service) visibleJob(ctx context.Context, userID uuid.UUID, role string, jobID uuid.UUID) (*domainJob.Job, error) {
+	job, err := s.repo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.OwnerID != userID && role != "admin" {
+		return nil, domainJob.ErrJobNotFound
+	}
+	return job, nil
+}
+
+// kinds returns the registered kinds of job
+func (s *Service) kinds() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	kinds := make([]string, 0, len(s.handlers))
+	for kind := range s.handlers {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+func (s *Service) registration(kind string) (registration, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reg, ok := s.handlers[kind]
+	return reg, ok
+}
@@ -0,0 +1,316 @@
+package job
+
+import (
+	"bytes"
+	domainJob "cargo-tracker/internal/domain/job"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// progressInterval is how often reported progress is stored
+	progressInterval = 5 * time.Second
+	// purgeInterval is how often finished jobs past their retention are removed
+	purgeInterval = time.Hour
+)
+
+// Run is the attempt of a job handed to its handler
+type Run struct {
+	Job *domainJob.Job
+
+	mu       sync.Mutex
+	progress int
+	message  string
+	changed  bool
+}
+
+// Decode unmarshals the job payload into v
+func (r *Run) Decode(v interface{}) error {
+	if err := json.Unmarshal(r.Job.Payload, v); err != nil {
+		return appErrors.NewAppError("INVALID_JOB_PAYLOAD", "Job payload is invalid", err)
+	}
+	return nil
+}
+
+// SetProgress reports how far the job is, as done out of total steps. It is
+// cheap to call often; the worker stores it with its next heartbeat.
+func (r *Run) SetProgress(done, total int, message string) {
+	percent := 0
+	if total > 0 {
+		percent = done * 100 / total
+	}
+	if percent > 99 {
+		// 100 is reserved for finished jobs
+		percent = 99
+	}
+
+	r.mu.Lock()
+	r.progress = percent
+	r.message = message
+	r.changed = true
+	r.mu.Unlock()
+}
+
+func (r *Run) takeProgress() (int, string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := r.changed
+	r.changed = false
+	return r.progress, r.message, changed
+}
+
+// StartWorkers runs the worker pool until ctx is cancelled. Jobs that are
+// running when ctx ends are picked up again once their lease expires.
+func (s *Service) StartWorkers(ctx context.Context) {
+	logger.Info("Job workers started",
+		zap.Int("workers", s.config.Workers),
+		zap.Duration("poll_interval", s.config.PollInterval),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.purge(ctx)
+	}()
+
+	wg.Wait()
+	logger.Info("Job workers stopped")
+}
+
+// work claims and runs jobs until ctx is cancelled, sleeping between polls
+// while the queue is empty
+func (s *Service) work(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			job, err := s.repo.Claim(ctx, s.kinds(), s.config.Lease)
+			if errors.Is(err, domainJob.ErrNoJobAvailable) {
+				break
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("Failed to claim job", zap.Error(err))
+				}
+				break
+			}
+			s.runJob(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// runJob runs one attempt of job and stores its outcome
+func (s *Service) runJob(ctx context.Context, job *domainJob.Job) {
+	reg, ok := s.registration(job.Kind)
+	if !ok {
+		// Claim only returns registered kinds
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	run := &Run{Job: job}
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		s.heartbeat(runCtx, cancel, run)
+	}()
+
+	started := time.Now()
+	output, err := s.callHandler(runCtx, reg.handler, run)
+	cancel()
+	<-heartbeatDone
+
+	if ctx.Err() != nil {
+		// Shutting down; the lease expires and another worker retries
+		return
+	}
+	if err == nil {
+		err = s.storeOutput(ctx, job, output)
+	}
+
+	now := time.Now()
+	if err != nil {
+		s.failAttempt(job, reg.policy, err, now)
+	} else {
+		job.Status = domainJob.StatusSucceeded
+		job.Progress = 100
+		job.Error = nil
+		job.FinishedAt = &now
+	}
+
+	if err := s.repo.Finish(context.Background(), job); err != nil {
+		logger.Error("Failed to store job outcome",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	logger.Info("Job attempt finished",
+		zap.String("job_id", job.ID.String()),
+		zap.String("kind", job.Kind),
+		zap.String("status", string(job.Status)),
+		zap.Int("attempt", job.Attempts),
+		zap.Duration("duration", now.Sub(started)),
+		zap.String("event", "job_attempt_finished"),
+	)
+}
+
+// callHandler runs the handler, turning a panic into a failed attempt
+func (s *Service) callHandler(ctx context.Context, handler Handler, run *Run) (output *Output, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job handler panicked: %v", p)
+		}
+	}()
+	return handler(ctx, run)
+}
+
+// heartbeat stores progress and extends the lease while the handler runs.
+// Losing the lease cancels the handler, since another worker now owns the
+// job.
+func (s *Service) heartbeat(ctx context.Context, cancel context.CancelFunc, run *Run) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		progress, message, changed := run.takeProgress()
+		if !changed && time.Since(renewed) < s.config.Lease/3 {
+			continue
+		}
+		renewed = time.Now()
+
+		err := s.repo.UpdateProgress(ctx, run.Job.ID, progress, message, s.config.Lease)
+		if errors.Is(err, domainJob.ErrLeaseLost) {
+			logger.Warn("Job lease lost, stopping attempt", zap.String("job_id", run.Job.ID.String()))
+			cancel()
+			return
+		}
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Failed to extend job lease",
+				zap.String("job_id", run.Job.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// storeOutput encodes the result and saves the result file of a successful
+// attempt
+func (s *Service) storeOutput(ctx context.Context, job *domainJob.Job, output *Output) error {
+	if output == nil {
+		return nil
+	}
+
+	if output.Result != nil {
+		data, err := json.Marshal(output.Result)
+		if err != nil {
+			return fmt.Errorf("failed to encode job result: %w", err)
+		}
+		job.Result = data
+	}
+
+	if f := output.File; f != nil {
+		key := fmt.Sprintf("jobs/%s/%s", job.ID, path.Base(f.Name))
+		size, err := s.store.Put(ctx, key, bytes.NewReader(f.Data), f.ContentType)
+		if err != nil {
+			return fmt.Errorf("failed to store job result: %w", err)
+		}
+		job.ResultFile = &domainJob.File{
+			StorageKey:  key,
+			FileName:    f.Name,
+			ContentType: f.ContentType,
+			SizeBytes:   size,
+		}
+	}
+	return nil
+}
+
+// failAttempt records a failed attempt, queueing a retry when the policy
+// allows one. Application errors are the caller's to fix and not retried.
+func (s *Service) failAttempt(job *domainJob.Job, policy RetryPolicy, err error, now time.Time) {
+	message := err.Error()
+	var appErr *appErrors.AppError
+	permanent := errors.As(err, &appErr)
+	if permanent {
+		message = appErr.Message
+	}
+	job.Error = &message
+
+	if permanent || !job.CanRetry() {
+		job.Status = domainJob.StatusFailed
+		job.FinishedAt = &now
+		return
+	}
+
+	job.Status = domainJob.StatusQueued
+	job.RunAt = now.Add(policy.Backoff << (job.Attempts - 1))
+}
+
+// purge deletes finished jobs past their retention together with their
+// result files
+func (s *Service) purge(ctx context.Context) {
+	if s.config.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		keys, err := s.repo.DeleteFinishedBefore(ctx, time.Now().Add(-s.config.Retention))
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Failed to delete expired jobs", zap.Error(err))
+		}
+		for _, key := range keys {
+			if err := s.store.Delete(ctx, key); err != nil {
+				logger.Warn("Failed to delete job result file",
+					zap.String("storage_key", key),
+					zap.Error(err),
+				)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package notification
+
+import "regexp"
+
+// commonVariables are available to every template, see domain MessageVariables
+var commonVariables = []string{"recipient_name", "recipient_email", "event", "default_subject", "default_body"}
+
+// eventVariables lists the events the application emits and the data each
+// one carries, so template authors know what they can reference.
+var eventVariables = []EventCatalogEntry{
+	{Event: "quote_requested", Variables: []string{"request_id"}},
+	{Event: "quote_submitted", Variables: []string{"request_id", "quote_id"}},
+	{Event: "quote_declined", Variables: []string{"request_id", "quote_id"}},
+	{Event: "quote_accepted", Variables: []string{"request_id", "quote_id", "shipment_id"}},
+	{Event: "quote_rejected", Variables: []string{"request_id", "quote_id"}},
+	{Event: "quote_request_cancelled", Variables: []string{"request_id", "quote_id"}},
+	{Event: "shipment_issue_reported", Variables: []string{"shipment_id", "issue_type", "issue_severity"}},
+	{Event: "shipment_assigned", Variables: []string{"shipment_id", "pickup_address", "delivery_address"}},
+	{Event: "shipment_shipper_assigned", Variables: []string{"shipment_id", "pickup_address", "delivery_address"}},
+}
+
+var eventNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_.]*$`)
+
+// EventCatalog returns the known events with all variables usable in their templates
+func EventCatalog() []EventCatalogEntry {
+	catalog := make([]EventCatalogEntry, len(eventVariables))
+	for i, e := range eventVariables {
+		vars := append(append([]string{}, e.Variables...), commonVariables...)
+		catalog[i] = EventCatalogEntry{Event: e.Event, Variables: vars}
+	}
+	return catalog
+}
@@ -0,0 +1,233 @@
+package notification
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// linkCodeAlphabet leaves out characters that are easy to confuse when typed
+	linkCodeAlphabet   = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	linkCodeLength     = 8
+	DefaultLinkCodeTTL = 15 * time.Minute
+
+	maxDeliveriesListed = 100
+)
+
+var linkCodePattern = regexp.MustCompile(`^[A-Z0-9]{8}$`)
+
+// ChatLinkService links user accounts to Telegram and Zalo chats and handles
+// the bots' inbound webhooks.
+type ChatLinkService struct {
+	repo    domainNotification.ChatLinkRepository
+	bots    map[domainNotification.ChatProvider]domainNotification.ChatBot
+	codeTTL time.Duration
+}
+
+// NewChatLinkService creates a new chat link service for the enabled bots
+func NewChatLinkService(repo domainNotification.ChatLinkRepository, bots []domainNotification.ChatBot, codeTTL time.Duration) *ChatLinkService {
+	if codeTTL <= 0 {
+		codeTTL = DefaultLinkCodeTTL
+	}
+	byProvider := make(map[domainNotification.ChatProvider]domainNotification.ChatBot, len(bots))
+	for _, b := range bots {
+		byProvider[b.Provider()] = b
+	}
+	return &ChatLinkService{repo: repo, bots: byProvider, codeTTL: codeTTL}
+}
+
+// CreateLinkCode issues a one-time code the user sends to the bot to link
+// their chat. Earlier unused codes stay valid until they expire.
+func (s *ChatLinkService) CreateLinkCode(ctx context.Context, userID uuid.UUID, req *CreateLinkCodeRequest) (*LinkCodeResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	bot, ok := s.bots[req.Provider]
+	if !ok {
+		return nil, appErrors.NewAppError("PROVIDER_DISABLED", "This messaging app is not enabled", nil)
+	}
+
+	code, err := generateLinkCode()
+	if err != nil {
+		return nil, err
+	}
+
+	linkCode := &domainNotification.ChatLinkCode{
+		UserID:    userID,
+		Provider:  req.Provider,
+		CodeHash:  hashLinkCode(code),
+		ExpiresAt: time.Now().Add(s.codeTTL),
+	}
+	if err := s.repo.CreateCode(ctx, linkCode); err != nil {
+		return nil, err
+	}
+
+	deepLink := bot.DeepLink(code)
+	instructions := "Send this code to the Cargo Tracker bot to link your chat."
+	if req.Provider == domainNotification.ChatTelegram && deepLink != "" {
+		instructions = "Open the link and press Start, or send this code to the Cargo Tracker bot."
+	}
+
+	return &LinkCodeResponse{
+		Provider:     req.Provider,
+		Code:         code,
+		ExpiresAt:    linkCode.ExpiresAt,
+		DeepLink:     deepLink,
+		Instructions: instructions,
+	}, nil
+}
+
+// ListLinks returns the chats linked to a user
+func (s *ChatLinkService) ListLinks(ctx context.Context, userID uuid.UUID) ([]ChatLinkResponse, error) {
+	links, err := s.repo.ListLinks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return ToChatLinkResponses(links), nil
+}
+
+// Unlink removes the chat of a provider from a user
+func (s *ChatLinkService) Unlink(ctx context.Context, userID uuid.UUID, provider domainNotification.ChatProvider) error {
+	if err := s.repo.DeleteLink(ctx, userID, provider); err != nil {
+		return err
+	}
+
+	logger.Info("Chat unlinked",
+		zap.String("user_id", userID.String()),
+		zap.String("provider", string(provider)),
+		zap.String("event", "chat_unlinked"),
+	)
+	return nil
+}
+
+// ListDeliveries returns the latest bot messages sent to a user with their receipts
+func (s *ChatLinkService) ListDeliveries(ctx context.Context, userID uuid.UUID, limit int) ([]ChatDeliveryResponse, error) {
+	if limit <= 0 || limit > maxDeliveriesListed {
+		limit = maxDeliveriesListed
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return ToChatDeliveryResponses(deliveries), nil
+}
+
+// HandleWebhook authenticates and processes an inbound bot webhook. Only
+// authentication failures are returned; processing problems are logged so
+// the provider does not keep redelivering the event.
+func (s *ChatLinkService) HandleWebhook(ctx context.Context, provider domainNotification.ChatProvider, signature string, body []byte) error {
+	bot, ok := s.bots[provider]
+	if !ok {
+		return domainNotification.ErrChatLinkNotFound
+	}
+	if !bot.VerifyWebhook(signature, body) {
+		return appErrors.ErrUnauthorized
+	}
+
+	events, err := bot.ParseWebhook(body)
+	if err != nil {
+		logger.Warn("Failed to parse bot webhook",
+			zap.String("provider", string(provider)),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	for _, event := range events {
+		if err := s.handleEvent(ctx, bot, event); err != nil {
+			logger.Warn("Failed to handle bot event",
+				zap.String("provider", string(provider)),
+				zap.String("kind", string(event.Kind)),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *ChatLinkService) handleEvent(ctx context.Context, bot domainNotification.ChatBot, event domainNotification.ChatEvent) error {
+	switch event.Kind {
+	case domainNotification.ChatEventMessage:
+		return s.handleMessage(ctx, bot, event)
+	case domainNotification.ChatEventDelivered:
+		return s.repo.UpdateDeliveryStatus(ctx, bot.Provider(), event.MessageIDs, domainNotification.ChatDeliveryDelivered, event.At)
+	case domainNotification.ChatEventSeen:
+		return s.repo.UpdateDeliveryStatus(ctx, bot.Provider(), event.MessageIDs, domainNotification.ChatDeliverySeen, event.At)
+	case domainNotification.ChatEventBlocked:
+		return s.repo.DeactivateChat(ctx, bot.Provider(), event.ChatID)
+	}
+	return nil
+}
+
+// handleMessage links the chat when the message carries a link code, e.g.
+// "ABCD2345" or Telegram's "/start ABCD2345"
+func (s *ChatLinkService) handleMessage(ctx context.Context, bot domainNotification.ChatBot, event domainNotification.ChatEvent) error {
+	code := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(event.Text), "/start")))
+	if !linkCodePattern.MatchString(code) {
+		return s.reply(ctx, bot, event.ChatID, "Send the 8-character code shown in the Cargo Tracker app to receive notifications here.")
+	}
+
+	linkCode, err := s.repo.ConsumeCode(ctx, bot.Provider(), hashLinkCode(code), time.Now())
+	if errors.Is(err, domainNotification.ErrInvalidLinkCode) {
+		return s.reply(ctx, bot, event.ChatID, "This code is invalid or has expired. Generate a new one in the Cargo Tracker app.")
+	}
+	if err != nil {
+		return err
+	}
+
+	link := &domainNotification.ChatLink{
+		UserID:   linkCode.UserID,
+		Provider: bot.Provider(),
+		ChatID:   event.ChatID,
+	}
+	if err := s.repo.SaveLink(ctx, link); err != nil {
+		return err
+	}
+
+	logger.Info("Chat linked",
+		zap.String("user_id", link.UserID.String()),
+		zap.String("provider", string(link.Provider)),
+		zap.String("event", "chat_linked"),
+	)
+
+	return s.reply(ctx, bot, event.ChatID, "Your chat is now linked. You will receive Cargo Tracker alerts and assignments here.")
+}
+
+func (s *ChatLinkService) reply(ctx context.Context, bot domainNotification.ChatBot, chatID, text string) error {
+	_, err := bot.Send(ctx, chatID, text)
+	return err
+}
+
+func generateLinkCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(linkCodeAlphabet)))
+	code := make([]byte, linkCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = linkCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func hashLinkCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
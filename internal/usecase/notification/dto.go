@@ -0,0 +1,355 @@
+package notification
+
+import (
+	"time"
+
+	domainNotification "cargo-tracker/internal/domain/notification"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type CreateTemplateRequest struct {
+	Event    string                     `json:"event" validate:"required,max=100"`
+	Channel  domainNotification.Channel `json:"channel" validate:"required,oneof=email chat push"`
+	Language string                     `json:"language" validate:"required,min=2,max=10"`
+	Subject  string                     `json:"subject" validate:"required,max=255"`
+	Body     string                     `json:"body" validate:"required,max=20000"`
+}
+
+type TemplateVersionsRequest struct {
+	Event    string                     `form:"event" validate:"required,max=100"`
+	Channel  domainNotification.Channel `form:"channel" validate:"required,oneof=email chat push"`
+	Language string                     `form:"language" validate:"required,min=2,max=10"`
+}
+
+// PreviewTemplateRequest renders either a stored template version or an
+// unsaved draft with sample variables.
+type PreviewTemplateRequest struct {
+	TemplateID string            `json:"template_id" validate:"omitempty,uuid"`
+	Subject    string            `json:"subject" validate:"required_without=TemplateID,max=255"`
+	Body       string            `json:"body" validate:"required_without=TemplateID,max=20000"`
+	Variables  map[string]string `json:"variables"`
+}
+
+// Response DTOs
+type TemplateResponse struct {
+	ID        uuid.UUID                  `json:"id"`
+	Event     string                     `json:"event"`
+	Channel   domainNotification.Channel `json:"channel"`
+	Language  string                     `json:"language"`
+	Version   int                        `json:"version"`
+	Subject   string                     `json:"subject"`
+	Body      string                     `json:"body"`
+	IsActive  bool                       `json:"is_active"`
+	Variables []string                   `json:"variables"`
+	CreatedBy *uuid.UUID                 `json:"created_by,omitempty"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+type PreviewTemplateResponse struct {
+	Subject          string   `json:"subject"`
+	Body             string   `json:"body"`
+	MissingVariables []string `json:"missing_variables"`
+}
+
+type EventCatalogEntry struct {
+	Event     string   `json:"event"`
+	Variables []string `json:"variables"`
+}
+
+// Conversion functions
+func ToTemplateResponse(t *domainNotification.Template) *TemplateResponse {
+	return &TemplateResponse{
+		ID:        t.ID,
+		Event:     t.Event,
+		Channel:   t.Channel,
+		Language:  t.Language,
+		Version:   t.Version,
+		Subject:   t.Subject,
+		Body:      t.Body,
+		IsActive:  t.IsActive,
+		Variables: t.Variables(),
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func ToTemplateResponses(templates []*domainNotification.Template) []TemplateResponse {
+	resp := make([]TemplateResponse, len(templates))
+	for i, t := range templates {
+		resp[i] = *ToTemplateResponse(t)
+	}
+	return resp
+}
+
+// Webhook request DTOs
+type CreateWebhookRequest struct {
+	Name        string                          `json:"name" validate:"required,min=2,max=100"`
+	Kind        domainNotification.WebhookKind  `json:"kind" validate:"required,oneof=slack teams generic"`
+	URL         string                          `json:"url" validate:"required,url,max=2000"`
+	Scope       domainNotification.WebhookScope `json:"scope" validate:"omitempty,oneof=owner platform"`
+	MinSeverity domainNotification.Severity     `json:"min_severity" validate:"omitempty,oneof=info critical"`
+	DailyDigest bool                            `json:"daily_digest"`
+	// PayloadFilters by event name, "*" for every event without its own
+	PayloadFilters map[string]WebhookPayloadFilter `json:"payload_filters" validate:"omitempty,max=50,dive"`
+}
+
+type UpdateWebhookRequest struct {
+	Name        *string                      `json:"name" validate:"omitempty,min=2,max=100"`
+	URL         *string                      `json:"url" validate:"omitempty,url,max=2000"`
+	MinSeverity *domainNotification.Severity `json:"min_severity" validate:"omitempty,oneof=info critical"`
+	DailyDigest *bool                        `json:"daily_digest"`
+	IsActive    *bool                        `json:"is_active"`
+	// PayloadFilters replaces all filters; an empty object removes them
+	PayloadFilters *map[string]WebhookPayloadFilter `json:"payload_filters"`
+}
+
+// WebhookPayloadFilter limits what a webhook receives for an event. Fields
+// are subject, body, link, data.* or data.<key>; the event ID, name,
+// severity and time are always sent.
+type WebhookPayloadFilter struct {
+	Preset domainNotification.PayloadPreset `json:"preset" validate:"omitempty,oneof=full no_pii minimal"`
+	Fields []string                         `json:"fields" validate:"omitempty,max=50,dive,max=100"`
+}
+
+// PreviewWebhookPayloadRequest picks the event a sample payload is built for
+type PreviewWebhookPayloadRequest struct {
+	Event string `json:"event" validate:"required,max=100"`
+}
+
+// WebhookPayloadPreview is a sample event as the webhook would receive it,
+// in the shape posted to generic webhooks
+type WebhookPayloadPreview struct {
+	Event    string                      `json:"event"`
+	Severity domainNotification.Severity `json:"severity"`
+	Subject  string                      `json:"subject,omitempty"`
+	Body     string                      `json:"body,omitempty"`
+	Link     string                      `json:"link,omitempty"`
+	Data     map[string]string           `json:"data,omitempty"`
+	// Filter is the filter that was applied, nil when the event is sent in full
+	Filter *WebhookPayloadFilter `json:"filter"`
+}
+
+// WebhookResponse never exposes the full webhook URL, which embeds its secret
+type WebhookResponse struct {
+	ID                  uuid.UUID                       `json:"id"`
+	Name                string                          `json:"name"`
+	Kind                domainNotification.WebhookKind  `json:"kind"`
+	URL                 string                          `json:"url"`
+	Scope               domainNotification.WebhookScope `json:"scope"`
+	MinSeverity         domainNotification.Severity     `json:"min_severity"`
+	DailyDigest         bool                            `json:"daily_digest"`
+	IsActive            bool                            `json:"is_active"`
+	Healthy             bool                            `json:"healthy"`
+	LastDeliveryAt      *time.Time                      `json:"last_delivery_at,omitempty"`
+	LastSuccessAt       *time.Time                      `json:"last_success_at,omitempty"`
+	LastError           *string                         `json:"last_error,omitempty"`
+	ConsecutiveFailures int                             `json:"consecutive_failures"`
+	SecretRotatedAt     *time.Time                      `json:"secret_rotated_at,omitempty"`
+	PayloadFilters      map[string]WebhookPayloadFilter `json:"payload_filters"`
+	CreatedAt           time.Time                       `json:"created_at"`
+	UpdatedAt           time.Time                       `json:"updated_at"`
+
+	// Only set when the webhook is created or its secret rotated
+	SigningSecret string `json:"signing_secret,omitempty"`
+}
+
+func ToWebhookResponse(w *domainNotification.Webhook) *WebhookResponse {
+	return &WebhookResponse{
+		ID:                  w.ID,
+		Name:                w.Name,
+		Kind:                w.Kind,
+		URL:                 w.MaskedURL(),
+		Scope:               w.Scope,
+		MinSeverity:         w.MinSeverity,
+		DailyDigest:         w.DailyDigest,
+		IsActive:            w.IsActive,
+		Healthy:             w.ConsecutiveFailures == 0,
+		LastDeliveryAt:      w.LastDeliveryAt,
+		LastSuccessAt:       w.LastSuccessAt,
+		LastError:           w.LastError,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		SecretRotatedAt:     w.SecretRotatedAt,
+		PayloadFilters:      toPayloadFilterResponses(w.PayloadFilters),
+		CreatedAt:           w.CreatedAt,
+		UpdatedAt:           w.UpdatedAt,
+	}
+}
+
+func toPayloadFilterResponses(filters map[string]domainNotification.PayloadFilter) map[string]WebhookPayloadFilter {
+	resp := make(map[string]WebhookPayloadFilter, len(filters))
+	for event, f := range filters {
+		resp[event] = WebhookPayloadFilter{Preset: f.Preset, Fields: f.Fields}
+	}
+	return resp
+}
+
+func ToWebhookResponses(hooks []*domainNotification.Webhook) []WebhookResponse {
+	resp := make([]WebhookResponse, len(hooks))
+	for i, w := range hooks {
+		resp[i] = *ToWebhookResponse(w)
+	}
+	return resp
+}
+
+// Webhook event archive DTOs
+type WebhookEventQuery struct {
+	WebhookID *uuid.UUID `form:"webhook_id"`
+	Event     string     `form:"event" validate:"omitempty,max=100"`
+	Status    string     `form:"status" validate:"omitempty,oneof=delivered failed"`
+	From      *time.Time `form:"from"`
+	To        *time.Time `form:"to"`
+	Limit     int        `form:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// ReplayWebhookEventsRequest selects archived events to deliver again,
+// either by ID or by time range
+type ReplayWebhookEventsRequest struct {
+	EventIDs []uuid.UUID `json:"event_ids" validate:"omitempty,max=1000"`
+	// Time range selection; From is required when no IDs are given
+	SourceWebhookID *uuid.UUID `json:"source_webhook_id"`
+	Event           string     `json:"event" validate:"omitempty,max=100"`
+	From            *time.Time `json:"from"`
+	To              *time.Time `json:"to"`
+	OnlyFailed      bool       `json:"only_failed"`
+}
+
+type WebhookEventResponse struct {
+	ID             uuid.UUID                   `json:"id"`
+	WebhookID      uuid.UUID                   `json:"webhook_id"`
+	Event          string                      `json:"event"`
+	Severity       domainNotification.Severity `json:"severity"`
+	Subject        string                      `json:"subject"`
+	Body           string                      `json:"body"`
+	Link           string                      `json:"link,omitempty"`
+	Data           map[string]string           `json:"data,omitempty"`
+	Delivered      bool                        `json:"delivered"`
+	LastError      *string                     `json:"last_error,omitempty"`
+	ReplayCount    int                         `json:"replay_count"`
+	LastReplayedAt *time.Time                  `json:"last_replayed_at,omitempty"`
+	CreatedAt      time.Time                   `json:"created_at"`
+}
+
+// ReplayResult is the result of a replay job
+type ReplayResult struct {
+	WebhookID uuid.UUID `json:"webhook_id"`
+	Selected  int       `json:"selected"`
+	Replayed  int       `json:"replayed"`
+	Failed    int       `json:"failed"`
+	// Replay stops at the first failure when the endpoint is unreachable
+	Stopped bool `json:"stopped"`
+}
+
+func ToWebhookEventResponse(e *domainNotification.WebhookEvent) *WebhookEventResponse {
+	return &WebhookEventResponse{
+		ID:             e.ID,
+		WebhookID:      e.WebhookID,
+		Event:          e.Event,
+		Severity:       e.Severity,
+		Subject:        e.Subject,
+		Body:           e.Body,
+		Link:           e.Link,
+		Data:           e.Data,
+		Delivered:      e.Delivered,
+		LastError:      e.LastError,
+		ReplayCount:    e.ReplayCount,
+		LastReplayedAt: e.LastReplayedAt,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+// Chat link DTOs
+type CreateLinkCodeRequest struct {
+	Provider domainNotification.ChatProvider `json:"provider" validate:"required,oneof=telegram zalo"`
+}
+
+type LinkCodeResponse struct {
+	Provider     domainNotification.ChatProvider `json:"provider"`
+	Code         string                          `json:"code"`
+	ExpiresAt    time.Time                       `json:"expires_at"`
+	DeepLink     string                          `json:"deep_link,omitempty"`
+	Instructions string                          `json:"instructions"`
+}
+
+type ChatLinkResponse struct {
+	Provider domainNotification.ChatProvider `json:"provider"`
+	IsActive bool                            `json:"is_active"`
+	LinkedAt time.Time                       `json:"linked_at"`
+}
+
+type ChatDeliveryResponse struct {
+	ID          uuid.UUID                             `json:"id"`
+	Provider    domainNotification.ChatProvider       `json:"provider"`
+	Event       string                                `json:"event"`
+	Status      domainNotification.ChatDeliveryStatus `json:"status"`
+	Error       *string                               `json:"error,omitempty"`
+	SentAt      time.Time                             `json:"sent_at"`
+	DeliveredAt *time.Time                            `json:"delivered_at,omitempty"`
+	SeenAt      *time.Time                            `json:"seen_at,omitempty"`
+}
+
+func ToChatLinkResponses(links []*domainNotification.ChatLink) []ChatLinkResponse {
+	resp := make([]ChatLinkResponse, len(links))
+	for i, l := range links {
+		resp[i] = ChatLinkResponse{
+			Provider: l.Provider,
+			IsActive: l.IsActive,
+			LinkedAt: l.LinkedAt,
+		}
+	}
+	return resp
+}
+
+func ToChatDeliveryResponses(deliveries []*domainNotification.ChatDelivery) []ChatDeliveryResponse {
+	resp := make([]ChatDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = ChatDeliveryResponse{
+			ID:          d.ID,
+			Provider:    d.Provider,
+			Event:       d.Event,
+			Status:      d.Status,
+			Error:       d.Error,
+			SentAt:      d.SentAt,
+			DeliveredAt: d.DeliveredAt,
+			SeenAt:      d.SeenAt,
+		}
+	}
+	return resp
+}
+
+// Push DTOs
+type RegisterPushTokenRequest struct {
+	Provider   domainNotification.PushProvider `json:"provider" validate:"required,oneof=fcm apns"`
+	Token      string                          `json:"token" validate:"required,min=32,max=512"`
+	Platform   string                          `json:"platform" validate:"required,oneof=android ios"`
+	DeviceName *string                         `json:"device_name" validate:"omitempty,max=100"`
+	AppVersion *string                         `json:"app_version" validate:"omitempty,max=50"`
+}
+
+type PushTokenResponse struct {
+	ID         uuid.UUID                       `json:"id"`
+	Provider   domainNotification.PushProvider `json:"provider"`
+	Platform   string                          `json:"platform"`
+	DeviceName *string                         `json:"device_name,omitempty"`
+	AppVersion *string                         `json:"app_version,omitempty"`
+	LastSeenAt time.Time                       `json:"last_seen_at"`
+	CreatedAt  time.Time                       `json:"created_at"`
+}
+
+type PushSubscriptionResponse struct {
+	Topic     string    `json:"topic"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToPushTokenResponse(t *domainNotification.PushToken) *PushTokenResponse {
+	return &PushTokenResponse{
+		ID:         t.ID,
+		Provider:   t.Provider,
+		Platform:   t.Platform,
+		DeviceName: t.DeviceName,
+		AppVersion: t.AppVersion,
+		LastSeenAt: t.LastSeenAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
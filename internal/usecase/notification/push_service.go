@@ -0,0 +1,153 @@
+package notification
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultPushTokenTTL is how long a token may go without being refreshed by
+// the app before it is pruned
+const DefaultPushTokenTTL = 60 * 24 * time.Hour
+
+// PushService manages mobile device tokens and shipment topic subscriptions
+type PushService struct {
+	pushRepo     domainNotification.PushRepository
+	shipmentRepo domainShipment.Repository
+	tokenTTL     time.Duration
+}
+
+// NewPushService creates a new push service
+func NewPushService(pushRepo domainNotification.PushRepository, shipmentRepo domainShipment.Repository, tokenTTL time.Duration) *PushService {
+	if tokenTTL <= 0 {
+		tokenTTL = DefaultPushTokenTTL
+	}
+	return &PushService{pushRepo: pushRepo, shipmentRepo: shipmentRepo, tokenTTL: tokenTTL}
+}
+
+// RegisterToken registers or refreshes the push token of a device. The app
+// calls it on every start so active tokens are never pruned.
+func (s *PushService) RegisterToken(ctx context.Context, userID uuid.UUID, req *RegisterPushTokenRequest) (*PushTokenResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	if req.Provider == domainNotification.PushAPNs && req.Platform != "ios" {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "APNs tokens are only valid for iOS devices", nil)
+	}
+
+	token := &domainNotification.PushToken{
+		UserID:     userID,
+		Provider:   req.Provider,
+		Token:      req.Token,
+		Platform:   req.Platform,
+		DeviceName: req.DeviceName,
+		AppVersion: req.AppVersion,
+	}
+	if err := s.pushRepo.SaveToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return ToPushTokenResponse(token), nil
+}
+
+// ListTokens returns the devices registered by a user
+func (s *PushService) ListTokens(ctx context.Context, userID uuid.UUID) ([]PushTokenResponse, error) {
+	tokens, err := s.pushRepo.ListTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]PushTokenResponse, len(tokens))
+	for i, t := range tokens {
+		resp[i] = *ToPushTokenResponse(t)
+	}
+	return resp, nil
+}
+
+// UnregisterToken removes a device, e.g. on logout
+func (s *PushService) UnregisterToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	return s.pushRepo.DeleteToken(ctx, userID, tokenID)
+}
+
+// SubscribeShipment subscribes a user to the alerts of a shipment. Parties of
+// the shipment and admins can subscribe.
+func (s *PushService) SubscribeShipment(ctx context.Context, userID uuid.UUID, role string, shipmentID uuid.UUID) error {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return err
+	}
+
+	isInvolved := shipment.CustomerID == userID ||
+		shipment.ProviderID == userID ||
+		(shipment.ShipperID != nil && *shipment.ShipperID == userID)
+	if !isInvolved && role != "admin" {
+		return appErrors.ErrUnauthorized
+	}
+
+	return s.pushRepo.Subscribe(ctx, userID, domainNotification.ShipmentTopic(shipmentID))
+}
+
+// UnsubscribeShipment stops the shipment alerts of a user
+func (s *PushService) UnsubscribeShipment(ctx context.Context, userID, shipmentID uuid.UUID) error {
+	return s.pushRepo.Unsubscribe(ctx, userID, domainNotification.ShipmentTopic(shipmentID))
+}
+
+// ListSubscriptions returns the topics a user is subscribed to
+func (s *PushService) ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]PushSubscriptionResponse, error) {
+	subs, err := s.pushRepo.ListSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]PushSubscriptionResponse, len(subs))
+	for i, sub := range subs {
+		resp[i] = PushSubscriptionResponse{Topic: sub.Topic, CreatedAt: sub.CreatedAt}
+	}
+	return resp, nil
+}
+
+// StartTokenHousekeepingJob prunes tokens the app has not refreshed within
+// the token TTL. Tokens rejected by FCM or APNs are pruned on delivery.
+func (s *PushService) StartTokenHousekeepingJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger.Info("Push token housekeeping job started",
+		zap.Duration("interval", interval),
+		zap.Duration("token_ttl", s.tokenTTL),
+	)
+
+	s.pruneStaleTokens(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Push token housekeeping job stopped")
+			return
+		case <-ticker.C:
+			s.pruneStaleTokens(ctx)
+		}
+	}
+}
+
+func (s *PushService) pruneStaleTokens(ctx context.Context) {
+	count, err := s.pushRepo.DeleteStale(ctx, time.Now().Add(-s.tokenTTL))
+	if err != nil {
+		logger.Error("Failed to prune stale push tokens", zap.Error(err))
+		return
+	}
+
+	if count > 0 {
+		logger.Info("Stale push tokens pruned",
+			zap.Int64("count", count),
+			zap.String("event", "push_tokens_pruned"),
+		)
+	}
+}
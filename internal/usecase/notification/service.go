@@ -0,0 +1,141 @@
+package notification
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service implements notification template management for admins
+type Service struct {
+	templateRepo domainNotification.TemplateRepository
+}
+
+// NewService creates a new notification template service
+func NewService(templateRepo domainNotification.TemplateRepository) *Service {
+	return &Service{templateRepo: templateRepo}
+}
+
+// ListTemplates returns the active version of every template
+func (s *Service) ListTemplates(ctx context.Context) ([]TemplateResponse, error) {
+	templates, err := s.templateRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ToTemplateResponses(templates), nil
+}
+
+// GetTemplate returns a single template version
+func (s *Service) GetTemplate(ctx context.Context, templateID uuid.UUID) (*TemplateResponse, error) {
+	t, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return ToTemplateResponse(t), nil
+}
+
+// ListVersions returns the version history of a template, newest first
+func (s *Service) ListVersions(ctx context.Context, req *TemplateVersionsRequest) ([]TemplateResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	templates, err := s.templateRepo.ListVersions(ctx, req.Event, req.Channel, domainNotification.NormalizeLanguage(req.Language))
+	if err != nil {
+		return nil, err
+	}
+	return ToTemplateResponses(templates), nil
+}
+
+// SaveTemplate stores a new version of a template and activates it
+func (s *Service) SaveTemplate(ctx context.Context, adminID uuid.UUID, req *CreateTemplateRequest) (*TemplateResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	event := strings.TrimSpace(req.Event)
+	if !eventNamePattern.MatchString(event) {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Event must be lowercase letters, digits, dots or underscores", nil)
+	}
+	if strings.ContainsAny(req.Subject, "\r\n") {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Subject must be a single line", nil)
+	}
+
+	t := &domainNotification.Template{
+		Event:     event,
+		Channel:   req.Channel,
+		Language:  domainNotification.NormalizeLanguage(req.Language),
+		Subject:   req.Subject,
+		Body:      req.Body,
+		CreatedBy: &adminID,
+	}
+	if err := s.templateRepo.CreateVersion(ctx, t); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Notification template saved",
+		zap.String("template_id", t.ID.String()),
+		zap.String("notification_event", t.Event),
+		zap.String("channel", string(t.Channel)),
+		zap.String("language", t.Language),
+		zap.Int("version", t.Version),
+		zap.String("admin_id", adminID.String()),
+		zap.String("event", "notification_template_saved"),
+	)
+
+	return ToTemplateResponse(t), nil
+}
+
+// ActivateVersion rolls a template back (or forward) to the given version
+func (s *Service) ActivateVersion(ctx context.Context, adminID, templateID uuid.UUID) (*TemplateResponse, error) {
+	if err := s.templateRepo.Activate(ctx, templateID); err != nil {
+		return nil, err
+	}
+
+	t, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Notification template version activated",
+		zap.String("template_id", templateID.String()),
+		zap.String("notification_event", t.Event),
+		zap.Int("version", t.Version),
+		zap.String("admin_id", adminID.String()),
+		zap.String("event", "notification_template_activated"),
+	)
+
+	return ToTemplateResponse(t), nil
+}
+
+// Preview renders a stored template or a draft with the given variables
+func (s *Service) Preview(ctx context.Context, req *PreviewTemplateRequest) (*PreviewTemplateResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	t := &domainNotification.Template{Subject: req.Subject, Body: req.Body}
+	if req.TemplateID != "" {
+		stored, err := s.templateRepo.GetByID(ctx, uuid.MustParse(req.TemplateID))
+		if err != nil {
+			return nil, err
+		}
+		t = stored
+	}
+
+	subject, body, missing := t.Render(req.Variables)
+	if missing == nil {
+		missing = []string{}
+	}
+	return &PreviewTemplateResponse{
+		Subject:          subject,
+		Body:             body,
+		MissingVariables: missing,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package notification
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// webhookHosts lists the hosts each kind of webhook may point to. Restricting
+// them keeps the server from being used to post to arbitrary internal URLs.
+var webhookHosts = map[domainNotification.WebhookKind][]string{
+	domainNotification.WebhookSlack: {"hooks.slack.com"},
+	domainNotification.WebhookTeams: {".webhook.office.com", ".logic.azure.com"},
+}
+
+// internalHostSuffixes are host names that never resolve to a public endpoint
+var internalHostSuffixes = []string{".local", ".internal", ".localhost", ".lan"}
+
+// ValidateWebhookURL checks that rawURL is an HTTPS endpoint of the chat
+// service, or any public HTTPS endpoint for generic webhooks. Generic
+// endpoints are checked again when connecting, see the webhook sender.
+func ValidateWebhookURL(kind domainNotification.WebhookKind, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return appErrors.NewAppError("INVALID_WEBHOOK_URL", "Webhook URL must be a valid https URL", err)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if kind == domainNotification.WebhookGeneric {
+		return validatePublicHost(host)
+	}
+	for _, allowed := range webhookHosts[kind] {
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return nil
+		}
+	}
+	return appErrors.NewAppError("INVALID_WEBHOOK_URL", "Webhook URL does not belong to "+string(kind), nil)
+}
+
+func validatePublicHost(host string) error {
+	invalid := appErrors.NewAppError("INVALID_WEBHOOK_URL", "Webhook URL must point to a public host", nil)
+
+	if ip := net.ParseIP(host); ip != nil {
+		if !utils.IsPublicIP(ip) {
+			return invalid
+		}
+		return nil
+	}
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return invalid
+	}
+	for _, suffix := range internalHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return invalid
+		}
+	}
+	return nil
+}
+
+// ValidatePayloadFilters checks the event names and fields of webhook payload
+// filters and converts them
+func ValidatePayloadFilters(filters map[string]WebhookPayloadFilter) (map[string]domainNotification.PayloadFilter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]domainNotification.PayloadFilter, len(filters))
+	for event, f := range filters {
+		if event != domainNotification.AllEvents && !eventNamePattern.MatchString(event) {
+			return nil, appErrors.NewAppError("INVALID_PAYLOAD_FILTER", "Invalid event name in payload filters: "+event, nil)
+		}
+		if err := utils.ValidateStruct(&f); err != nil {
+			return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid payload filter for "+event, err)
+		}
+		for _, field := range f.Fields {
+			if !domainNotification.IsValidPayloadField(field) {
+				return nil, appErrors.NewAppError("INVALID_PAYLOAD_FILTER", "Unknown payload field: "+field, nil)
+			}
+		}
+		result[event] = domainNotification.PayloadFilter{Preset: f.Preset, Fields: f.Fields}
+	}
+	return result, nil
+}
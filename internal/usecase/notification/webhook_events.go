@@ -0,0 +1,204 @@
+package notification
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/job"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// JobKindWebhookReplay delivers archived events to a webhook again
+	JobKindWebhookReplay = "notification.webhook_replay"
+
+	// defaultListedEvents is the page size of the event archive
+	defaultListedEvents = 100
+	// maxReplayedEvents bounds a single replay
+	maxReplayedEvents = 1000
+	// replayFailureLimit stops a replay once the endpoint keeps failing
+	replayFailureLimit = 5
+)
+
+type replayPayload struct {
+	WebhookID uuid.UUID   `json:"webhook_id"`
+	EventIDs  []uuid.UUID `json:"event_ids"`
+}
+
+// RegisterJobs registers the background jobs of the service
+func (s *WebhookService) RegisterJobs(jobs *job.Service) {
+	s.jobs = jobs
+	// Events already delivered are marked as replays, so receivers can drop
+	// what a retried attempt sends again
+	jobs.Register(JobKindWebhookReplay, s.runReplayJob, job.RetryPolicy{MaxAttempts: 1})
+}
+
+// ListEvents returns archived events of the user's webhooks, oldest first
+func (s *WebhookService) ListEvents(ctx context.Context, ownerID uuid.UUID, query *WebhookEventQuery) ([]*WebhookEventResponse, error) {
+	if err := utils.ValidateStruct(query); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	filter := &domainNotification.WebhookEventFilter{
+		OwnerID:   ownerID,
+		WebhookID: query.WebhookID,
+		Event:     query.Event,
+		From:      query.From,
+		To:        query.To,
+		Limit:     query.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultListedEvents
+	}
+	if query.Status != "" {
+		delivered := query.Status == "delivered"
+		filter.Delivered = &delivered
+	}
+
+	events, err := s.eventRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*WebhookEventResponse, len(events))
+	for i, e := range events {
+		responses[i] = ToWebhookEventResponse(e)
+	}
+	return responses, nil
+}
+
+// QueueReplay queues delivering the selected archived events of the user to
+// one of their webhooks, oldest first. The job result is a ReplayResult.
+func (s *WebhookService) QueueReplay(ctx context.Context, ownerID, webhookID uuid.UUID, req *ReplayWebhookEventsRequest) (*job.JobResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	if len(req.EventIDs) == 0 && req.From == nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Select events by event_ids or by a time range starting at from", nil)
+	}
+	if _, err := s.getOwnedWebhook(ctx, ownerID, webhookID); err != nil {
+		return nil, err
+	}
+
+	filter := &domainNotification.WebhookEventFilter{
+		OwnerID:   ownerID,
+		WebhookID: req.SourceWebhookID,
+		Event:     req.Event,
+		From:      req.From,
+		To:        req.To,
+		IDs:       req.EventIDs,
+		Limit:     maxReplayedEvents + 1,
+	}
+	if req.OnlyFailed {
+		delivered := false
+		filter.Delivered = &delivered
+	}
+
+	events, err := s.eventRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, domainNotification.ErrNoWebhookEvents
+	}
+	if len(events) > maxReplayedEvents {
+		return nil, appErrors.NewAppError("TOO_MANY_EVENTS", fmt.Sprintf("At most %d events can be replayed at once; narrow the time range", maxReplayedEvents), nil)
+	}
+
+	payload := &replayPayload{WebhookID: webhookID, EventIDs: make([]uuid.UUID, len(events))}
+	for i, e := range events {
+		payload.EventIDs[i] = e.ID
+	}
+	return s.jobs.Enqueue(ctx, ownerID, JobKindWebhookReplay, payload)
+}
+
+func (s *WebhookService) runReplayJob(ctx context.Context, run *job.Run) (*job.Output, error) {
+	var payload replayPayload
+	if err := run.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	hook, err := s.getOwnedWebhook(ctx, run.Job.OwnerID, payload.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.List(ctx, &domainNotification.WebhookEventFilter{
+		OwnerID: run.Job.OwnerID,
+		IDs:     payload.EventIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{WebhookID: hook.ID, Selected: len(events)}
+	consecutiveFailures := 0
+	for i, event := range events {
+		deliveryErr := s.sender.Deliver(ctx, hook, event.Message())
+		if err := s.eventRepo.RecordReplay(ctx, event.ID, time.Now(), deliveryErr); err != nil {
+			logger.Warn("Failed to record webhook replay",
+				zap.String("webhook_event_id", event.ID.String()),
+				zap.Error(err),
+			)
+		}
+
+		if deliveryErr != nil {
+			result.Failed++
+			consecutiveFailures++
+			if consecutiveFailures >= replayFailureLimit {
+				result.Stopped = true
+				break
+			}
+		} else {
+			result.Replayed++
+			consecutiveFailures = 0
+		}
+		run.SetProgress(i+1, len(events), "Replaying events")
+	}
+
+	logger.Info("Webhook events replayed",
+		zap.String("webhook_id", hook.ID.String()),
+		zap.String("owner_id", hook.OwnerID.String()),
+		zap.Int("replayed", result.Replayed),
+		zap.Int("failed", result.Failed),
+		zap.Bool("stopped", result.Stopped),
+		zap.String("event", "webhook_events_replayed"),
+	)
+
+	return &job.Output{Result: result}, nil
+}
+
+// StartEventArchivePurgeJob deletes archived events older than retention
+// every interval until ctx is cancelled
+func (s *WebhookService) StartEventArchivePurgeJob(ctx context.Context, retention, interval time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.eventRepo.DeleteBefore(ctx, time.Now().Add(-retention))
+			if err != nil {
+				logger.Error("Failed to purge webhook events", zap.Error(err))
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("Webhook events purged",
+					zap.Int64("deleted", deleted),
+					zap.String("event", "webhook_events_purged"),
+				)
+			}
+		}
+	}
+}
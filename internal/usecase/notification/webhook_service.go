@@ -0,0 +1,490 @@
+package notification
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainQuota "cargo-tracker/internal/domain/quota"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/job"
+	usecaseQuota "cargo-tracker/internal/usecase/quota"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxWebhooksPerOwner bounds how many chat integrations a user can configure
+const maxWebhooksPerOwner = 10
+
+// signingSecretPrefix makes webhook secrets recognisable, e.g. to secret scanners
+const signingSecretPrefix = "whsec_"
+
+// digestStatuses are the shipment states summarised in an owner's daily digest
+var digestStatuses = []domainShipment.ShipmentStatus{
+	domainShipment.StatusOrderPosted,
+	domainShipment.StatusShippingAssigned,
+	domainShipment.StatusInTransit,
+	domainShipment.StatusIssueReported,
+}
+
+// WebhookService manages Slack, Microsoft Teams and generic webhook
+// integrations, posts the daily digest to them and replays archived events.
+type WebhookService struct {
+	webhookRepo  domainNotification.WebhookRepository
+	eventRepo    domainNotification.WebhookEventRepository
+	userRepo     domainUser.Repository
+	shipmentRepo domainShipment.Repository
+	sender       domainNotification.WebhookSender
+	jobs         *job.Service
+
+	// quotas gates webhooks by plan; nil disables it
+	quotas *usecaseQuota.Service
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(
+	webhookRepo domainNotification.WebhookRepository,
+	eventRepo domainNotification.WebhookEventRepository,
+	userRepo domainUser.Repository,
+	shipmentRepo domainShipment.Repository,
+	sender domainNotification.WebhookSender,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo:  webhookRepo,
+		eventRepo:    eventRepo,
+		userRepo:     userRepo,
+		shipmentRepo: shipmentRepo,
+		sender:       sender,
+	}
+}
+
+// UseQuotas makes the service refuse to create or re-activate webhooks of
+// owners whose plan does not include them
+func (s *WebhookService) UseQuotas(quotas *usecaseQuota.Service) {
+	s.quotas = quotas
+}
+
+// ListWebhooks returns the webhooks configured by a user
+func (s *WebhookService) ListWebhooks(ctx context.Context, ownerID uuid.UUID) ([]WebhookResponse, error) {
+	hooks, err := s.webhookRepo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return ToWebhookResponses(hooks), nil
+}
+
+// CreateWebhook configures a new chat integration. Platform scope, which
+// receives operational alerts for every shipment, is reserved for admins.
+func (s *WebhookService) CreateWebhook(ctx context.Context, ownerID uuid.UUID, role string, req *CreateWebhookRequest) (*WebhookResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	req.URL = strings.TrimSpace(req.URL)
+	if err := ValidateWebhookURL(req.Kind, req.URL); err != nil {
+		return nil, err
+	}
+
+	scope := req.Scope
+	if scope == "" {
+		scope = domainNotification.WebhookScopeOwner
+	}
+	if scope == domainNotification.WebhookScopePlatform && role != "admin" {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only admins can create platform webhooks", nil)
+	}
+	if err := s.quotas.RequireFeature(ctx, ownerID, domainQuota.FeatureWebhooks); err != nil {
+		return nil, err
+	}
+
+	filters, err := ValidatePayloadFilters(req.PayloadFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	minSeverity := req.MinSeverity
+	if minSeverity == "" {
+		minSeverity = domainNotification.SeverityCritical
+	}
+
+	existing, err := s.webhookRepo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerOwner {
+		return nil, appErrors.NewAppError("WEBHOOK_LIMIT_REACHED", fmt.Sprintf("At most %d webhooks can be configured", maxWebhooksPerOwner), nil)
+	}
+
+	secret, err := generateSigningSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing secret: %w", err)
+	}
+
+	hook := &domainNotification.Webhook{
+		OwnerID:        ownerID,
+		Name:           req.Name,
+		Kind:           req.Kind,
+		URL:            req.URL,
+		Scope:          scope,
+		MinSeverity:    minSeverity,
+		DailyDigest:    req.DailyDigest,
+		IsActive:       true,
+		PayloadFilters: filters,
+		SigningSecret:  secret,
+	}
+	if err := s.webhookRepo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Webhook created",
+		zap.String("webhook_id", hook.ID.String()),
+		zap.String("owner_id", ownerID.String()),
+		zap.String("kind", string(hook.Kind)),
+		zap.String("scope", string(hook.Scope)),
+		zap.String("event", "webhook_created"),
+	)
+
+	// The secret is shown once; it cannot be read back later
+	resp := ToWebhookResponse(hook)
+	resp.SigningSecret = secret
+	return resp, nil
+}
+
+// UpdateWebhook changes the settings of a webhook. Re-activating a webhook
+// clears its failure count.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, ownerID, webhookID uuid.UUID, req *UpdateWebhookRequest) (*WebhookResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	hook, err := s.getOwnedWebhook(ctx, ownerID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		hook.Name = *req.Name
+	}
+	if req.URL != nil {
+		newURL := strings.TrimSpace(*req.URL)
+		if err := ValidateWebhookURL(hook.Kind, newURL); err != nil {
+			return nil, err
+		}
+		hook.URL = newURL
+		hook.ConsecutiveFailures = 0
+	}
+	if req.MinSeverity != nil {
+		hook.MinSeverity = *req.MinSeverity
+	}
+	if req.DailyDigest != nil {
+		hook.DailyDigest = *req.DailyDigest
+	}
+	if req.PayloadFilters != nil {
+		filters, err := ValidatePayloadFilters(*req.PayloadFilters)
+		if err != nil {
+			return nil, err
+		}
+		hook.PayloadFilters = filters
+	}
+	if req.IsActive != nil {
+		if *req.IsActive && !hook.IsActive {
+			if err := s.quotas.RequireFeature(ctx, ownerID, domainQuota.FeatureWebhooks); err != nil {
+				return nil, err
+			}
+			hook.ConsecutiveFailures = 0
+		}
+		hook.IsActive = *req.IsActive
+	}
+
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	return ToWebhookResponse(hook), nil
+}
+
+// DeleteWebhook removes a webhook
+func (s *WebhookService) DeleteWebhook(ctx context.Context, ownerID, webhookID uuid.UUID) error {
+	if _, err := s.getOwnedWebhook(ctx, ownerID, webhookID); err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+
+	logger.Info("Webhook deleted",
+		zap.String("webhook_id", webhookID.String()),
+		zap.String("owner_id", ownerID.String()),
+		zap.String("event", "webhook_deleted"),
+	)
+	return nil
+}
+
+// RotateSigningSecret replaces the signing secret of a webhook. Deliveries are
+// signed with both the new and the previous secret for
+// SigningSecretGracePeriod so the receiver can switch over without downtime.
+// The new secret is returned once.
+func (s *WebhookService) RotateSigningSecret(ctx context.Context, ownerID, webhookID uuid.UUID) (*WebhookResponse, error) {
+	hook, err := s.getOwnedWebhook(ctx, ownerID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSigningSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing secret: %w", err)
+	}
+
+	now := time.Now()
+	previous := hook.SigningSecret
+	hook.PreviousSigningSecret = &previous
+	hook.SigningSecret = secret
+	hook.SecretRotatedAt = &now
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Webhook signing secret rotated",
+		zap.String("webhook_id", hook.ID.String()),
+		zap.String("owner_id", ownerID.String()),
+		zap.String("event", "webhook_secret_rotated"),
+	)
+
+	resp := ToWebhookResponse(hook)
+	resp.SigningSecret = secret
+	return resp, nil
+}
+
+// TestWebhook posts a signed test event and returns the webhook with its updated
+// delivery health. A failed delivery is reported in the response rather than
+// as an error.
+func (s *WebhookService) TestWebhook(ctx context.Context, ownerID, webhookID uuid.UUID) (*WebhookResponse, error) {
+	hook, err := s.getOwnedWebhook(ctx, ownerID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.sender.Deliver(ctx, hook, &domainNotification.Message{
+		UserID:   ownerID,
+		Event:    "webhook_test",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "Cargo Tracker test message",
+		Body:     fmt.Sprintf("The \"%s\" integration is set up correctly.", hook.Name),
+		Link:     "/shipments",
+		Data: map[string]string{
+			"webhook_id": hook.ID.String(),
+			"sample":     "true",
+		},
+	})
+
+	updated, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return ToWebhookResponse(updated), nil
+}
+
+// PreviewPayload shows what the webhook would receive for event, built from
+// sample data and filtered by the webhook's payload filters
+func (s *WebhookService) PreviewPayload(ctx context.Context, ownerID, webhookID uuid.UUID, req *PreviewWebhookPayloadRequest) (*WebhookPayloadPreview, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	hook, err := s.getOwnedWebhook(ctx, ownerID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := hook.FilterPayload(sampleMessage(req.Event))
+	preview := &WebhookPayloadPreview{
+		Event:    msg.Event,
+		Severity: msg.Severity,
+		Subject:  msg.Subject,
+		Body:     msg.Body,
+		Link:     msg.Link,
+		Data:     msg.Data,
+	}
+	if f := hook.PayloadFilterFor(req.Event); f != nil {
+		preview.Filter = &WebhookPayloadFilter{Preset: f.Preset, Fields: f.Fields}
+	}
+	return preview, nil
+}
+
+// sampleMessage builds a notification for event with placeholder values
+// for the data the event carries
+func sampleMessage(event string) *domainNotification.Message {
+	msg := &domainNotification.Message{
+		Event:    event,
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "Sample " + strings.ReplaceAll(event, "_", " ") + " notification",
+		Body:     "Shipment \"Sample goods\" for Jane Doe (jane.doe@example.com), 1 Sample Street.",
+		Link:     "/shipments/" + uuid.Nil.String(),
+		Data:     map[string]string{},
+	}
+	for _, entry := range eventVariables {
+		if entry.Event != event {
+			continue
+		}
+		for _, variable := range entry.Variables {
+			if strings.HasSuffix(variable, "_id") {
+				msg.Data[variable] = uuid.Nil.String()
+			} else {
+				msg.Data[variable] = "sample " + strings.ReplaceAll(variable, "_", " ")
+			}
+		}
+	}
+	return msg
+}
+
+// StartDailyDigestJob posts the daily digest every day at the given local
+// hour until ctx is cancelled.
+func (s *WebhookService) StartDailyDigestJob(ctx context.Context, hour int) {
+	logger.Info("Daily digest job started",
+		zap.Int("hour", hour),
+	)
+
+	for {
+		timer := time.NewTimer(time.Until(nextDigestTime(time.Now(), hour)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("Daily digest job stopped")
+			return
+		case <-timer.C:
+			s.SendDailyDigest(ctx)
+		}
+	}
+}
+
+func nextDigestTime(now time.Time, hour int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// SendDailyDigest posts a summary to every webhook subscribed to the digest
+func (s *WebhookService) SendDailyDigest(ctx context.Context) {
+	hooks, err := s.webhookRepo.ListDigestSubscribers(ctx)
+	if err != nil {
+		logger.Error("Failed to list digest webhooks", zap.Error(err))
+		return
+	}
+
+	sent := 0
+	for _, hook := range hooks {
+		msg, err := s.buildDigest(ctx, hook)
+		if err != nil {
+			logger.Warn("Failed to build daily digest",
+				zap.String("webhook_id", hook.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if err := s.sender.Deliver(ctx, hook, msg); err == nil {
+			sent++
+		}
+	}
+
+	logger.Info("Daily digest sent",
+		zap.Int("webhooks", len(hooks)),
+		zap.Int("delivered", sent),
+		zap.String("event", "daily_digest_sent"),
+	)
+}
+
+func (s *WebhookService) buildDigest(ctx context.Context, hook *domainNotification.Webhook) (*domainNotification.Message, error) {
+	owner, err := s.userRepo.GetByID(ctx, hook.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+
+	if hook.Scope == domainNotification.WebhookScopePlatform || owner.Role == "admin" {
+		stats, err := s.shipmentRepo.GetStatistics(ctx)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "Active shipments: %d\n", stats.ActiveShipments)
+		fmt.Fprintf(&b, "Completed today: %d\n", stats.CompletedToday)
+		fmt.Fprintf(&b, "Open issues: %d\n", stats.ByStatus[string(domainShipment.StatusIssueReported)])
+		fmt.Fprintf(&b, "Issue rate: %.1f%%\n", stats.IssueRate)
+		fmt.Fprintf(&b, "On-time delivery rate: %.1f%%", stats.OnTimeDeliveryRate)
+	} else {
+		for _, status := range digestStatuses {
+			live := false
+			filter := &domainShipment.Filter{Sandbox: &live, Page: 1, PageSize: 1}
+			switch owner.Role {
+			case "customer":
+				filter.CustomerID = &owner.ID
+			case "shipper":
+				filter.ShipperID = &owner.ID
+			default:
+				filter.ProviderID = &owner.ID
+			}
+			st := status
+			filter.Status = &st
+
+			_, total, err := s.shipmentRepo.List(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "%s: %d\n", digestStatusLabel(status), total)
+		}
+	}
+
+	return &domainNotification.Message{
+		UserID:   hook.OwnerID,
+		Event:    "daily_digest",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "Daily shipment digest for " + time.Now().Format("2006-01-02"),
+		Body:     strings.TrimRight(b.String(), "\n"),
+		Link:     "/shipments",
+	}, nil
+}
+
+func digestStatusLabel(status domainShipment.ShipmentStatus) string {
+	switch status {
+	case domainShipment.StatusOrderPosted:
+		return "Waiting for a shipper"
+	case domainShipment.StatusShippingAssigned:
+		return "Awaiting pick-up"
+	case domainShipment.StatusInTransit:
+		return "In transit"
+	case domainShipment.StatusIssueReported:
+		return "Open issues"
+	default:
+		return string(status)
+	}
+}
+
+func (s *WebhookService) getOwnedWebhook(ctx context.Context, ownerID, webhookID uuid.UUID) (*domainNotification.Webhook, error) {
+	hook, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if hook.OwnerID != ownerID {
+		// Do not reveal webhooks of other users
+		return nil, domainNotification.ErrWebhookNotFound
+	}
+	return hook, nil
+}
+
+func generateSigningSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return signingSecretPrefix + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,73 @@
+package quota
+
+import (
+	"cargo-tracker/internal/config"
+	"time"
+)
+
+type ChangePlanRequest struct {
+	Plan string `json:"plan" validate:"required,oneof=free pro enterprise"`
+}
+
+type PlanResponse struct {
+	Name     string     `json:"name"`
+	Features []string   `json:"features"`
+	Limits   PlanLimits `json:"limits"`
+}
+
+// PlanLimits are nil where the plan does not cap usage
+type PlanLimits struct {
+	ActiveShipments *int64 `json:"active_shipments"`
+	Devices         *int64 `json:"devices"`
+	APICalls        *int64 `json:"api_calls"`
+}
+
+type UsageResponse struct {
+	Plan     string   `json:"plan"`
+	Features []string `json:"features"`
+	// False while quotas are disabled; usage is shown but not capped
+	Enforced    bool          `json:"enforced"`
+	PeriodStart time.Time     `json:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end"`
+	Metrics     []MetricUsage `json:"metrics"`
+}
+
+type MetricUsage struct {
+	Metric string `json:"metric"`
+	Used   int64  `json:"used"`
+	// Nil when the plan does not cap the metric
+	Limit     *int64 `json:"limit"`
+	Remaining *int64 `json:"remaining"`
+}
+
+func toMetricUsage(metric string, used, limit int64) MetricUsage {
+	usage := MetricUsage{Metric: metric, Used: used}
+	if limit > 0 {
+		remaining := limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		usage.Limit = &limit
+		usage.Remaining = &remaining
+	}
+	return usage
+}
+
+func toPlanResponse(name string, p config.PlanConfig) PlanResponse {
+	return PlanResponse{
+		Name:     name,
+		Features: planFeatures(p),
+		Limits: PlanLimits{
+			ActiveShipments: limitPtr(p.ActiveShipments),
+			Devices:         limitPtr(p.Devices),
+			APICalls:        limitPtr(p.APICalls),
+		},
+	}
+}
+
+func limitPtr(limit int64) *int64 {
+	if limit == 0 {
+		return nil
+	}
+	return &limit
+}
@@ -0,0 +1,345 @@
+package quota
+
+import (
+	"cargo-tracker/internal/config"
+	domainQuota "cargo-tracker/internal/domain/quota"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// accountCacheTTL is how long the API call counter trusts a cached plan, and
+// so how long a plan or role change takes to reach it
+const accountCacheTTL = time.Minute
+
+// maxCachedAccounts is the cache size past which expired plans are pruned
+const maxCachedAccounts = 10000
+
+// metricLabels name the metrics in QUOTA_EXCEEDED messages
+var metricLabels = map[domainQuota.Metric]string{
+	domainQuota.MetricActiveShipments: "active shipments",
+	domainQuota.MetricDevices:         "devices",
+	domainQuota.MetricAPICalls:        "API calls per month",
+}
+
+// featureLabels name the features in PLAN_UPGRADE_REQUIRED messages
+var featureLabels = map[domainQuota.Feature]string{
+	domainQuota.FeaturePredictiveAlerts: "Predictive alerts",
+	domainQuota.FeatureWebhooks:         "Webhooks",
+}
+
+type cachedAccount struct {
+	plan      string
+	expiresAt time.Time
+}
+
+// Service caps account usage and gates features by plan. The caps are soft: usage is counted
+// before the write that adds to it, so concurrent requests can overshoot a
+// limit by the few that raced past the check.
+type Service struct {
+	repo     domainQuota.Repository
+	userRepo domainUser.Repository
+	cfg      config.QuotaConfig
+
+	mu       sync.Mutex
+	accounts map[uuid.UUID]cachedAccount
+}
+
+// NewService creates a new quota service
+func NewService(repo domainQuota.Repository, userRepo domainUser.Repository, cfg config.QuotaConfig) *Service {
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		cfg:      cfg,
+		accounts: make(map[uuid.UUID]cachedAccount),
+	}
+}
+
+// Check returns a QUOTA_EXCEEDED error when the user is already at the limit
+// of their plan for metric. Services call it before the write that adds to
+// the usage; a nil service checks nothing.
+func (s *Service) Check(ctx context.Context, userID uuid.UUID, metric domainQuota.Metric) error {
+	if s == nil || !s.cfg.Enabled {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	limit := s.limit(user.Plan, metric)
+	if limit == 0 {
+		return nil
+	}
+
+	used, err := s.usage(ctx, user, metric, domainQuota.PeriodStart(time.Now()))
+	if err != nil {
+		return err
+	}
+	if used < limit {
+		return nil
+	}
+
+	logger.Warn("Quota exceeded",
+		zap.String("user_id", userID.String()),
+		zap.String("plan", user.Plan),
+		zap.String("metric", string(metric)),
+		zap.Int64("limit", limit),
+		zap.String("event", "quota_exceeded"),
+	)
+
+	message := fmt.Sprintf("The %s plan allows at most %d %s", user.Plan, limit, metricLabels[metric])
+	upgrade := s.upgradeFor(user.Plan, func(p config.PlanConfig) bool {
+		higher := planLimit(p, metric)
+		return higher == 0 || higher > limit
+	})
+	if upgrade != "" {
+		message += fmt.Sprintf("; upgrade to the %s plan for more", upgrade)
+	}
+	return appErrors.NewAppError("QUOTA_EXCEEDED", message, nil)
+}
+
+// RequireFeature returns a PLAN_UPGRADE_REQUIRED error naming the plan to
+// upgrade to when the user's plan does not include feature. Admins are never
+// gated; a nil service gates nothing.
+func (s *Service) RequireFeature(ctx context.Context, userID uuid.UUID, feature domainQuota.Feature) error {
+	if s == nil || !s.cfg.Enabled {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role == "admin" || planHas(s.cfg.Plan(user.Plan), feature) {
+		return nil
+	}
+
+	logger.Info("Feature not included in plan",
+		zap.String("user_id", userID.String()),
+		zap.String("plan", user.Plan),
+		zap.String("feature", string(feature)),
+		zap.String("event", "plan_feature_denied"),
+	)
+
+	message := fmt.Sprintf("%s are not included in the %s plan", featureLabels[feature], user.Plan)
+	upgrade := s.upgradeFor(user.Plan, func(p config.PlanConfig) bool {
+		return planHas(p, feature)
+	})
+	if upgrade != "" {
+		message += fmt.Sprintf("; upgrade to the %s plan to use them", upgrade)
+	}
+	return appErrors.NewAppError("PLAN_UPGRADE_REQUIRED", message, nil)
+}
+
+// ListPlans returns every plan with its limits and features, cheapest first
+func (s *Service) ListPlans() []PlanResponse {
+	plans := make([]PlanResponse, len(domainUser.Plans))
+	for i, name := range domainUser.Plans {
+		plans[i] = toPlanResponse(name, s.cfg.Plan(name))
+	}
+	return plans
+}
+
+// AllowAPICall counts an API call of the user and reports whether it is within
+// their monthly quota. Counting failures let the call through rather than
+// locking users out.
+func (s *Service) AllowAPICall(ctx context.Context, userID uuid.UUID) bool {
+	if !s.cfg.Enabled {
+		return true
+	}
+
+	plan, err := s.plan(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to look up plan for API call quota",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return true
+	}
+
+	count, err := s.repo.IncrementAPICalls(ctx, userID, domainQuota.PeriodStart(time.Now()))
+	if err != nil {
+		logger.Error("Failed to count API call",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return true
+	}
+
+	limit := s.limit(plan, domainQuota.MetricAPICalls)
+	return limit == 0 || count <= limit
+}
+
+// GetUsage returns the user's current usage against the limits of their plan
+func (s *Service) GetUsage(ctx context.Context, userID uuid.UUID) (*UsageResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	periodStart := domainQuota.PeriodStart(time.Now())
+	resp := &UsageResponse{
+		Plan:        user.Plan,
+		Features:    planFeatures(s.cfg.Plan(user.Plan)),
+		Enforced:    s.cfg.Enabled,
+		PeriodStart: periodStart,
+		PeriodEnd:   domainQuota.PeriodEnd(periodStart),
+		Metrics:     []MetricUsage{},
+	}
+	for _, metric := range metricsOf(user.Role) {
+		used, err := s.usage(ctx, user, metric, periodStart)
+		if err != nil {
+			return nil, err
+		}
+		resp.Metrics = append(resp.Metrics, toMetricUsage(string(metric), used, s.limit(user.Plan, metric)))
+	}
+	return resp, nil
+}
+
+// ChangePlan moves an account to another plan. Usage over the new limits is
+// kept; it only blocks further growth.
+func (s *Service) ChangePlan(ctx context.Context, adminID, userID uuid.UUID, req *ChangePlanRequest) (*UsageResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Plan != req.Plan {
+		if err := s.userRepo.UpdatePlan(ctx, userID, req.Plan); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		delete(s.accounts, userID)
+		s.mu.Unlock()
+
+		logger.Info("User plan changed",
+			zap.String("user_id", userID.String()),
+			zap.String("from_plan", user.Plan),
+			zap.String("to_plan", req.Plan),
+			zap.String("changed_by", adminID.String()),
+			zap.String("event", "user_plan_changed"),
+		)
+	}
+
+	return s.GetUsage(ctx, userID)
+}
+
+// plan returns the user's plan, cached for the API call counter which runs on
+// every request
+func (s *Service) plan(ctx context.Context, userID uuid.UUID) (string, error) {
+	now := time.Now()
+	s.mu.Lock()
+	cached, ok := s.accounts[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.plan, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.accounts) >= maxCachedAccounts {
+		for id, account := range s.accounts {
+			if !now.Before(account.expiresAt) {
+				delete(s.accounts, id)
+			}
+		}
+	}
+	s.accounts[userID] = cachedAccount{plan: user.Plan, expiresAt: now.Add(accountCacheTTL)}
+	return user.Plan, nil
+}
+
+func (s *Service) usage(ctx context.Context, user *domainUser.User, metric domainQuota.Metric, periodStart time.Time) (int64, error) {
+	switch metric {
+	case domainQuota.MetricActiveShipments:
+		return s.repo.CountActiveShipments(ctx, user.ID, user.Role)
+	case domainQuota.MetricDevices:
+		if user.Role != "shipper" {
+			return 0, nil
+		}
+		return s.repo.CountDevices(ctx, user.ID)
+	case domainQuota.MetricAPICalls:
+		return s.repo.GetAPICalls(ctx, user.ID, periodStart)
+	}
+	return 0, nil
+}
+
+// limit returns the cap of plan on metric; 0 is unlimited
+func (s *Service) limit(plan string, metric domainQuota.Metric) int64 {
+	return planLimit(s.cfg.Plan(plan), metric)
+}
+
+// upgradeFor returns the cheapest plan above plan that satisfies ok, or ""
+// when there is none
+func (s *Service) upgradeFor(plan string, ok func(config.PlanConfig) bool) string {
+	above := false
+	for _, name := range domainUser.Plans {
+		if above && ok(s.cfg.Plan(name)) {
+			return name
+		}
+		if name == plan {
+			above = true
+		}
+	}
+	return ""
+}
+
+func planLimit(p config.PlanConfig, metric domainQuota.Metric) int64 {
+	switch metric {
+	case domainQuota.MetricActiveShipments:
+		return p.ActiveShipments
+	case domainQuota.MetricDevices:
+		return p.Devices
+	case domainQuota.MetricAPICalls:
+		return p.APICalls
+	}
+	return 0
+}
+
+func planHas(p config.PlanConfig, feature domainQuota.Feature) bool {
+	switch feature {
+	case domainQuota.FeaturePredictiveAlerts:
+		return p.PredictiveAlerts
+	case domainQuota.FeatureWebhooks:
+		return p.Webhooks
+	}
+	return false
+}
+
+// planFeatures lists the features p includes
+func planFeatures(p config.PlanConfig) []string {
+	features := []string{}
+	for _, feature := range []domainQuota.Feature{domainQuota.FeaturePredictiveAlerts, domainQuota.FeatureWebhooks} {
+		if planHas(p, feature) {
+			features = append(features, string(feature))
+		}
+	}
+	return features
+}
+
+// metricsOf returns the metrics that apply to an account of role
+func metricsOf(role string) []domainQuota.Metric {
+	switch role {
+	case "shipper":
+		return []domainQuota.Metric{domainQuota.MetricActiveShipments, domainQuota.MetricDevices, domainQuota.MetricAPICalls}
+	case "customer", "provider":
+		return []domainQuota.Metric{domainQuota.MetricActiveShipments, domainQuota.MetricAPICalls}
+	}
+	return []domainQuota.Metric{domainQuota.MetricAPICalls}
+}
@@ -0,0 +1,175 @@
+package quotation
+
+import (
+	"time"
+
+	domainQuotation "cargo-tracker/internal/domain/quotation"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type CreateQuoteRequest struct {
+	ProviderIDs         []uuid.UUID `json:"provider_ids" validate:"required,min=1,max=10,dive,required"`
+	GoodsDescription    string      `json:"goods_description" validate:"required,min=10,max=1000"`
+	GoodsValue          *float64    `json:"goods_value" validate:"omitempty,min=0"`
+	GoodsCurrency       *string     `json:"goods_currency" validate:"omitempty,len=3,alpha"`
+	GoodsWeight         *float64    `json:"goods_weight" validate:"omitempty,min=0"`
+	PickupAddress       string      `json:"pickup_address" validate:"required,min=10"`
+	DeliveryAddress     string      `json:"delivery_address" validate:"required,min=10"`
+	EstimatedPickupAt   *time.Time  `json:"estimated_pickup_at" validate:"omitempty"`
+	EstimatedDeliveryAt *time.Time  `json:"estimated_delivery_at" validate:"omitempty"`
+	CustomerNotes       *string     `json:"customer_notes" validate:"omitempty,max=500"`
+	// How long providers have to respond, defaults to 72 hours
+	ResponseWindowHours int `json:"response_window_hours" validate:"omitempty,min=1,max=720"`
+}
+
+type SubmitQuoteRequest struct {
+	Price         float64    `json:"price" validate:"required,gt=0"`
+	Currency      string     `json:"currency" validate:"omitempty,len=3,alpha"`
+	LeadTimeHours int        `json:"lead_time_hours" validate:"required,min=1,max=8760"`
+	ValidUntil    *time.Time `json:"valid_until" validate:"omitempty"`
+	Notes         *string    `json:"notes" validate:"omitempty,max=1000"`
+}
+
+type DeclineQuoteRequest struct {
+	Reason *string `json:"reason" validate:"omitempty,max=500"`
+}
+
+type QuoteFilterRequest struct {
+	Status   *domainQuotation.QuoteStatus `form:"status"`
+	Page     int                          `form:"page,default=1" validate:"min=1"`
+	PageSize int                          `form:"page_size,default=20" validate:"min=1,max=100"`
+}
+
+// Response DTOs
+type QuoteResponse struct {
+	ID            uuid.UUID                   `json:"id"`
+	RequestID     uuid.UUID                   `json:"request_id"`
+	ProviderID    uuid.UUID                   `json:"provider_id"`
+	Status        domainQuotation.QuoteStatus `json:"status"`
+	Price         *float64                    `json:"price,omitempty"`
+	Currency      string                      `json:"currency"`
+	LeadTimeHours *int                        `json:"lead_time_hours,omitempty"`
+	ValidUntil    *time.Time                  `json:"valid_until,omitempty"`
+	Notes         *string                     `json:"notes,omitempty"`
+	RespondedAt   *time.Time                  `json:"responded_at,omitempty"`
+	CreatedAt     time.Time                   `json:"created_at"`
+}
+
+type QuoteRequestResponse struct {
+	ID                  uuid.UUID                     `json:"id"`
+	CustomerID          uuid.UUID                     `json:"customer_id"`
+	Status              domainQuotation.RequestStatus `json:"status"`
+	GoodsDescription    string                        `json:"goods_description"`
+	GoodsValue          *float64                      `json:"goods_value,omitempty"`
+	GoodsCurrency       *string                       `json:"goods_currency,omitempty"`
+	GoodsWeight         *float64                      `json:"goods_weight,omitempty"`
+	PickupAddress       string                        `json:"pickup_address"`
+	DeliveryAddress     string                        `json:"delivery_address"`
+	EstimatedPickupAt   *time.Time                    `json:"estimated_pickup_at,omitempty"`
+	EstimatedDeliveryAt *time.Time                    `json:"estimated_delivery_at,omitempty"`
+	CustomerNotes       *string                       `json:"customer_notes,omitempty"`
+	ExpiresAt           time.Time                     `json:"expires_at"`
+	ShipmentID          *uuid.UUID                    `json:"shipment_id,omitempty"`
+	Quotes              []QuoteResponse               `json:"quotes"`
+	CreatedAt           time.Time                     `json:"created_at"`
+	UpdatedAt           time.Time                     `json:"updated_at"`
+}
+
+// IncomingQuoteResponse is what a provider sees: its own quote plus the
+// goods and route it is being asked to price.
+type IncomingQuoteResponse struct {
+	QuoteResponse
+	Request *QuoteRequestSummary `json:"request,omitempty"`
+}
+
+type QuoteRequestSummary struct {
+	CustomerID          uuid.UUID                     `json:"customer_id"`
+	Status              domainQuotation.RequestStatus `json:"status"`
+	GoodsDescription    string                        `json:"goods_description"`
+	GoodsValue          *float64                      `json:"goods_value,omitempty"`
+	GoodsCurrency       *string                       `json:"goods_currency,omitempty"`
+	GoodsWeight         *float64                      `json:"goods_weight,omitempty"`
+	PickupAddress       string                        `json:"pickup_address"`
+	DeliveryAddress     string                        `json:"delivery_address"`
+	EstimatedPickupAt   *time.Time                    `json:"estimated_pickup_at,omitempty"`
+	EstimatedDeliveryAt *time.Time                    `json:"estimated_delivery_at,omitempty"`
+	ExpiresAt           time.Time                     `json:"expires_at"`
+}
+
+type QuoteRequestListResponse struct {
+	Requests   []QuoteRequestResponse `json:"requests"`
+	Total      int64                  `json:"total"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"page_size"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+type IncomingQuoteListResponse struct {
+	Quotes     []IncomingQuoteResponse `json:"quotes"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	PageSize   int                     `json:"page_size"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// Conversion functions
+func ToQuoteResponse(q *domainQuotation.Quote) QuoteResponse {
+	return QuoteResponse{
+		ID:            q.ID,
+		RequestID:     q.RequestID,
+		ProviderID:    q.ProviderID,
+		Status:        q.Status,
+		Price:         q.Price,
+		Currency:      q.Currency,
+		LeadTimeHours: q.LeadTimeHours,
+		ValidUntil:    q.ValidUntil,
+		Notes:         q.Notes,
+		RespondedAt:   q.RespondedAt,
+		CreatedAt:     q.CreatedAt,
+	}
+}
+
+func ToQuoteRequestResponse(r *domainQuotation.QuoteRequest) *QuoteRequestResponse {
+	quotes := make([]QuoteResponse, len(r.Quotes))
+	for i, q := range r.Quotes {
+		quotes[i] = ToQuoteResponse(q)
+	}
+
+	return &QuoteRequestResponse{
+		ID:                  r.ID,
+		CustomerID:          r.CustomerID,
+		Status:              r.Status,
+		GoodsDescription:    r.GoodsDescription,
+		GoodsValue:          r.GoodsValue,
+		GoodsCurrency:       r.GoodsCurrency,
+		GoodsWeight:         r.GoodsWeight,
+		PickupAddress:       r.PickupAddress,
+		DeliveryAddress:     r.DeliveryAddress,
+		EstimatedPickupAt:   r.EstimatedPickupAt,
+		EstimatedDeliveryAt: r.EstimatedDeliveryAt,
+		CustomerNotes:       r.CustomerNotes,
+		ExpiresAt:           r.ExpiresAt,
+		ShipmentID:          r.ShipmentID,
+		Quotes:              quotes,
+		CreatedAt:           r.CreatedAt,
+		UpdatedAt:           r.UpdatedAt,
+	}
+}
+
+func ToQuoteRequestSummary(r *domainQuotation.QuoteRequest) *QuoteRequestSummary {
+	return &QuoteRequestSummary{
+		CustomerID:          r.CustomerID,
+		Status:              r.Status,
+		GoodsDescription:    r.GoodsDescription,
+		GoodsValue:          r.GoodsValue,
+		GoodsCurrency:       r.GoodsCurrency,
+		GoodsWeight:         r.GoodsWeight,
+		PickupAddress:       r.PickupAddress,
+		DeliveryAddress:     r.DeliveryAddress,
+		EstimatedPickupAt:   r.EstimatedPickupAt,
+		EstimatedDeliveryAt: r.EstimatedDeliveryAt,
+		ExpiresAt:           r.ExpiresAt,
+	}
+}
@@ -0,0 +1,512 @@
+package quotation
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainQuotation "cargo-tracker/internal/domain/quotation"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/shipment"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service implements the quotation flow that precedes a shipment demand
+type Service struct {
+	quotationRepo   domainQuotation.Repository
+	userRepo        domainUser.Repository
+	shipmentService *shipment.Service
+	notifier        domainNotification.Notifier
+}
+
+// NewService creates a new quotation service
+func NewService(
+	quotationRepo domainQuotation.Repository,
+	userRepo domainUser.Repository,
+	shipmentService *shipment.Service,
+	notifier domainNotification.Notifier,
+) *Service {
+	return &Service{
+		quotationRepo:   quotationRepo,
+		userRepo:        userRepo,
+		shipmentService: shipmentService,
+		notifier:        notifier,
+	}
+}
+
+// RequestQuotes sends a customer's demand to several providers for pricing
+func (s *Service) RequestQuotes(ctx context.Context, customerID uuid.UUID, req *CreateQuoteRequest) (*QuoteRequestResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	customer, err := s.userRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, appErrors.ErrUserNotFound
+	}
+	if customer.Role != "customer" {
+		return nil, appErrors.NewAppError("INVALID_ROLE", "Customer must have 'customer' role", nil)
+	}
+
+	providers, err := ValidateProviders(ctx, s.userRepo, customerID, req.ProviderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := shipment.ValidateTimeRange(req.EstimatedPickupAt, req.EstimatedDeliveryAt); err != nil {
+		return nil, err
+	}
+
+	if req.GoodsCurrency != nil {
+		currency := strings.ToUpper(*req.GoodsCurrency)
+		req.GoodsCurrency = &currency
+	}
+
+	window := DefaultResponseWindow
+	if req.ResponseWindowHours > 0 {
+		window = time.Duration(req.ResponseWindowHours) * time.Hour
+	}
+
+	request := &domainQuotation.QuoteRequest{
+		CustomerID:          customerID,
+		Status:              domainQuotation.RequestOpen,
+		GoodsDescription:    req.GoodsDescription,
+		GoodsValue:          req.GoodsValue,
+		GoodsCurrency:       req.GoodsCurrency,
+		GoodsWeight:         req.GoodsWeight,
+		PickupAddress:       req.PickupAddress,
+		DeliveryAddress:     req.DeliveryAddress,
+		EstimatedPickupAt:   req.EstimatedPickupAt,
+		EstimatedDeliveryAt: req.EstimatedDeliveryAt,
+		CustomerNotes:       req.CustomerNotes,
+		ExpiresAt:           time.Now().Add(window),
+	}
+
+	providerIDs := make([]uuid.UUID, len(providers))
+	for i, p := range providers {
+		providerIDs[i] = p.ID
+	}
+
+	if err := s.quotationRepo.CreateRequest(ctx, request, providerIDs); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Quote request created",
+		zap.String("request_id", request.ID.String()),
+		zap.String("customer_id", customerID.String()),
+		zap.Int("providers", len(providerIDs)),
+		zap.String("event", "quote_request_created"),
+	)
+
+	for _, p := range providers {
+		s.notify(ctx, p, "quote_requested",
+			"New quote request",
+			fmt.Sprintf("%s has asked for a quote to ship \"%s\" from %s to %s. Please respond before %s.",
+				customer.FullName, request.GoodsDescription, request.PickupAddress, request.DeliveryAddress,
+				request.ExpiresAt.Format(time.RFC1123)),
+			map[string]string{"request_id": request.ID.String()},
+		)
+	}
+
+	return ToQuoteRequestResponse(request), nil
+}
+
+// GetRequest returns a quote request with all its quotes to the customer who created it
+func (s *Service) GetRequest(ctx context.Context, customerID, requestID uuid.UUID) (*QuoteRequestResponse, error) {
+	request, err := s.getOwnedRequest(ctx, customerID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return ToQuoteRequestResponse(request), nil
+}
+
+// ListRequests lists a customer's quote requests, newest first
+func (s *Service) ListRequests(ctx context.Context, customerID uuid.UUID, page, pageSize int) (*QuoteRequestListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	requests, total, err := s.quotationRepo.ListRequestsByCustomer(ctx, customerID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &QuoteRequestListResponse{
+		Requests:   make([]QuoteRequestResponse, len(requests)),
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}
+	for i, r := range requests {
+		s.expireIfNeeded(ctx, r)
+		resp.Requests[i] = *ToQuoteRequestResponse(r)
+	}
+	return resp, nil
+}
+
+// CancelRequest withdraws an open quote request and rejects all its quotes
+func (s *Service) CancelRequest(ctx context.Context, customerID, requestID uuid.UUID) (*QuoteRequestResponse, error) {
+	request, err := s.getOwnedRequest(ctx, customerID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != domainQuotation.RequestOpen {
+		return nil, domainQuotation.ErrRequestClosed
+	}
+
+	if err := s.quotationRepo.CloseRequest(ctx, requestID, domainQuotation.RequestCancelled); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Quote request cancelled",
+		zap.String("request_id", requestID.String()),
+		zap.String("customer_id", customerID.String()),
+		zap.String("event", "quote_request_cancelled"),
+	)
+
+	for _, q := range request.Quotes {
+		if q.Status == domainQuotation.QuoteRequested || q.Status == domainQuotation.QuoteSubmitted {
+			s.notifyUser(ctx, q.ProviderID, "quote_request_cancelled",
+				"Quote request cancelled",
+				fmt.Sprintf("The customer has cancelled the quote request for \"%s\".", request.GoodsDescription),
+				map[string]string{"request_id": requestID.String(), "quote_id": q.ID.String()},
+			)
+		}
+	}
+
+	return s.GetRequest(ctx, customerID, requestID)
+}
+
+// AcceptQuote accepts a provider's quote and turns the request into a shipment
+// demand with that provider. All other quotes are rejected.
+func (s *Service) AcceptQuote(ctx context.Context, customerID, requestID, quoteID uuid.UUID) (*shipment.ShipmentResponse, error) {
+	request, err := s.getOwnedRequest(ctx, customerID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := FindQuote(request, quoteID)
+	if quote == nil {
+		return nil, domainQuotation.ErrQuoteNotFound
+	}
+	if err := CanAccept(request, quote, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Keep the agreed terms on the shipment for later reference
+	notes := fmt.Sprintf("Quoted %.2f %s, lead time %dh.", *quote.Price, quote.Currency, *quote.LeadTimeHours)
+	if request.CustomerNotes != nil {
+		notes = *request.CustomerNotes + "\n" + notes
+	}
+	if len(notes) > 500 {
+		notes = notes[:500]
+	}
+
+	goodsCurrency := ""
+	if request.GoodsCurrency != nil {
+		goodsCurrency = *request.GoodsCurrency
+	}
+
+	created, err := s.shipmentService.CreateDemand(ctx, customerID, &shipment.CreateDemandRequest{
+		ProviderID:          quote.ProviderID,
+		GoodsDescription:    request.GoodsDescription,
+		GoodsValue:          request.GoodsValue,
+		GoodsCurrency:       goodsCurrency,
+		GoodsWeight:         request.GoodsWeight,
+		PickupAddress:       request.PickupAddress,
+		DeliveryAddress:     request.DeliveryAddress,
+		EstimatedPickupAt:   request.EstimatedPickupAt,
+		EstimatedDeliveryAt: request.EstimatedDeliveryAt,
+		CustomerNotes:       &notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.quotationRepo.AcceptQuote(ctx, requestID, quoteID, created.ID); err != nil {
+		// Another accept won the race; withdraw the demand we just created
+		if _, cancelErr := s.shipmentService.CancelShipment(ctx, customerID, created.ID, &shipment.CancelShipmentRequest{
+			Reason: "Quote acceptance failed, demand withdrawn automatically",
+		}); cancelErr != nil {
+			logger.Error("Failed to withdraw shipment after quote acceptance failed",
+				zap.String("shipment_id", created.ID.String()),
+				zap.String("request_id", requestID.String()),
+				zap.Error(cancelErr),
+				zap.String("event", "quote_accept_rollback_failed"),
+			)
+		}
+		return nil, err
+	}
+
+	logger.Info("Quote accepted",
+		zap.String("request_id", requestID.String()),
+		zap.String("quote_id", quoteID.String()),
+		zap.String("shipment_id", created.ID.String()),
+		zap.String("provider_id", quote.ProviderID.String()),
+		zap.String("event", "quote_accepted"),
+	)
+
+	for _, q := range request.Quotes {
+		data := map[string]string{
+			"request_id": requestID.String(),
+			"quote_id":   q.ID.String(),
+		}
+		switch {
+		case q.ID == quoteID:
+			data["shipment_id"] = created.ID.String()
+			s.notifyUser(ctx, q.ProviderID, "quote_accepted",
+				"Your quote was accepted",
+				fmt.Sprintf("Your quote for \"%s\" was accepted. Shipment %s has been created.", request.GoodsDescription, created.ID),
+				data,
+			)
+		case q.Status == domainQuotation.QuoteRequested || q.Status == domainQuotation.QuoteSubmitted:
+			s.notifyUser(ctx, q.ProviderID, "quote_rejected",
+				"Quote not selected",
+				fmt.Sprintf("The customer chose another provider for \"%s\".", request.GoodsDescription),
+				data,
+			)
+		}
+	}
+
+	return created, nil
+}
+
+// ListIncomingQuotes lists the quotes a provider has been asked for
+func (s *Service) ListIncomingQuotes(ctx context.Context, providerID uuid.UUID, filter *QuoteFilterRequest) (*IncomingQuoteListResponse, error) {
+	if err := utils.ValidateStruct(filter); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	quotes, total, err := s.quotationRepo.ListQuotesByProvider(ctx, providerID, filter.Status, filter.Page, filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &IncomingQuoteListResponse{
+		Quotes:     make([]IncomingQuoteResponse, len(quotes)),
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize)),
+	}
+
+	requests := make(map[uuid.UUID]*domainQuotation.QuoteRequest)
+	for i, q := range quotes {
+		resp.Quotes[i] = IncomingQuoteResponse{QuoteResponse: ToQuoteResponse(q)}
+
+		request, ok := requests[q.RequestID]
+		if !ok {
+			request, err = s.quotationRepo.GetRequestByID(ctx, q.RequestID)
+			if err != nil {
+				return nil, err
+			}
+			s.expireIfNeeded(ctx, request)
+			requests[q.RequestID] = request
+		}
+		resp.Quotes[i].Request = ToQuoteRequestSummary(request)
+	}
+	return resp, nil
+}
+
+// SubmitQuote records a provider's price and lead time. A provider may revise
+// its quote until the customer accepts one.
+func (s *Service) SubmitQuote(ctx context.Context, providerID, quoteID uuid.UUID, req *SubmitQuoteRequest) (*QuoteResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	quote, request, err := s.getProviderQuote(ctx, providerID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := CanRespond(request, quote, now); err != nil {
+		return nil, err
+	}
+	if req.ValidUntil != nil && !req.ValidUntil.After(now) {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "valid_until must be in the future", nil)
+	}
+
+	currency := DefaultCurrency
+	if req.Currency != "" {
+		currency = strings.ToUpper(req.Currency)
+	}
+
+	quote.Status = domainQuotation.QuoteSubmitted
+	quote.Price = &req.Price
+	quote.Currency = currency
+	quote.LeadTimeHours = &req.LeadTimeHours
+	quote.ValidUntil = req.ValidUntil
+	quote.Notes = req.Notes
+	quote.RespondedAt = &now
+
+	if err := s.quotationRepo.UpdateQuote(ctx, quote); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Quote submitted",
+		zap.String("quote_id", quoteID.String()),
+		zap.String("request_id", request.ID.String()),
+		zap.String("provider_id", providerID.String()),
+		zap.String("event", "quote_submitted"),
+	)
+
+	s.notifyUser(ctx, request.CustomerID, "quote_submitted",
+		"New quote received",
+		fmt.Sprintf("A provider quoted %.2f %s with a lead time of %d hours for \"%s\".",
+			req.Price, currency, req.LeadTimeHours, request.GoodsDescription),
+		map[string]string{"request_id": request.ID.String(), "quote_id": quoteID.String()},
+	)
+
+	resp := ToQuoteResponse(quote)
+	return &resp, nil
+}
+
+// DeclineQuote records that a provider will not serve the request
+func (s *Service) DeclineQuote(ctx context.Context, providerID, quoteID uuid.UUID, req *DeclineQuoteRequest) (*QuoteResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	quote, request, err := s.getProviderQuote(ctx, providerID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := CanRespond(request, quote, now); err != nil {
+		return nil, err
+	}
+
+	quote.Status = domainQuotation.QuoteDeclined
+	quote.Price = nil
+	quote.LeadTimeHours = nil
+	quote.ValidUntil = nil
+	quote.Notes = req.Reason
+	quote.RespondedAt = &now
+
+	if err := s.quotationRepo.UpdateQuote(ctx, quote); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Quote declined",
+		zap.String("quote_id", quoteID.String()),
+		zap.String("request_id", request.ID.String()),
+		zap.String("provider_id", providerID.String()),
+		zap.String("event", "quote_declined"),
+	)
+
+	s.notifyUser(ctx, request.CustomerID, "quote_declined",
+		"Provider declined your quote request",
+		fmt.Sprintf("A provider declined to quote for \"%s\".", request.GoodsDescription),
+		map[string]string{"request_id": request.ID.String(), "quote_id": quoteID.String()},
+	)
+
+	resp := ToQuoteResponse(quote)
+	return &resp, nil
+}
+
+func (s *Service) getOwnedRequest(ctx context.Context, customerID, requestID uuid.UUID) (*domainQuotation.QuoteRequest, error) {
+	request, err := s.quotationRepo.GetRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.CustomerID != customerID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Customer does not own this quote request", nil)
+	}
+	s.expireIfNeeded(ctx, request)
+	return request, nil
+}
+
+func (s *Service) getProviderQuote(ctx context.Context, providerID, quoteID uuid.UUID) (*domainQuotation.Quote, *domainQuotation.QuoteRequest, error) {
+	quote, err := s.quotationRepo.GetQuoteByID(ctx, quoteID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if quote.ProviderID != providerID {
+		return nil, nil, appErrors.NewAppError("UNAUTHORIZED", "Quote was not requested from this provider", nil)
+	}
+
+	request, err := s.quotationRepo.GetRequestByID(ctx, quote.RequestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.expireIfNeeded(ctx, request)
+	return quote, request, nil
+}
+
+// expireIfNeeded closes a request whose response window has passed. Requests
+// are expired lazily on access rather than by a background sweep.
+func (s *Service) expireIfNeeded(ctx context.Context, request *domainQuotation.QuoteRequest) {
+	if !request.IsExpired(time.Now()) {
+		return
+	}
+
+	if err := s.quotationRepo.CloseRequest(ctx, request.ID, domainQuotation.RequestExpired); err != nil {
+		logger.Warn("Failed to expire quote request",
+			zap.String("request_id", request.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	request.Status = domainQuotation.RequestExpired
+	for _, q := range request.Quotes {
+		if q.Status == domainQuotation.QuoteRequested || q.Status == domainQuotation.QuoteSubmitted {
+			q.Status = domainQuotation.QuoteRejected
+		}
+	}
+
+	logger.Info("Quote request expired",
+		zap.String("request_id", request.ID.String()),
+		zap.String("event", "quote_request_expired"),
+	)
+}
+
+func (s *Service) notifyUser(ctx context.Context, userID uuid.UUID, event, subject, body string, data map[string]string) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Warn("Failed to resolve notification recipient",
+			zap.String("user_id", userID.String()),
+			zap.String("notification_event", event),
+			zap.Error(err),
+		)
+		return
+	}
+	s.notify(ctx, u, event, subject, body, data)
+}
+
+func (s *Service) notify(ctx context.Context, u *domainUser.User, event, subject, body string, data map[string]string) {
+	if s.notifier == nil {
+		return
+	}
+
+	err := s.notifier.Notify(ctx, &domainNotification.Message{
+		UserID:  u.ID,
+		Email:   u.Email,
+		Name:    u.FullName,
+		Event:   event,
+		Subject: subject,
+		Body:    body,
+		Data:    data,
+		Sandbox: u.IsSandbox,
+	})
+	if err != nil {
+		logger.Warn("Failed to send notification",
+			zap.String("user_id", u.ID.String()),
+			zap.String("notification_event", event),
+			zap.Error(err),
+		)
+	}
+}
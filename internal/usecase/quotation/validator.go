@@ -0,0 +1,82 @@
+package quotation
+
+import (
+	domainQuotation "cargo-tracker/internal/domain/quotation"
+	domainUser "cargo-tracker/internal/domain/user"
+	appErrors "cargo-tracker/pkg/errors"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultResponseWindow = 72 * time.Hour
+	DefaultCurrency       = "VND"
+)
+
+// ValidateProviders checks that every invited user is an active provider and
+// returns the list with duplicates removed.
+func ValidateProviders(ctx context.Context, userRepo domainUser.Repository, customerID uuid.UUID, providerIDs []uuid.UUID) ([]*domainUser.User, error) {
+	seen := make(map[uuid.UUID]bool, len(providerIDs))
+	providers := make([]*domainUser.User, 0, len(providerIDs))
+
+	for _, id := range providerIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if id == customerID {
+			return nil, appErrors.NewAppError("SAME_PARTY", "Customer and provider must be different users", nil)
+		}
+
+		provider, err := userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, appErrors.ErrUserNotFound
+		}
+		if provider.Role != "provider" {
+			return nil, appErrors.NewAppError("INVALID_ROLE", "Quotes can only be requested from users with 'provider' role", nil)
+		}
+		if !provider.IsActive {
+			return nil, appErrors.ErrUserInactive
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// CanRespond checks that a provider may still submit or decline a quote
+func CanRespond(request *domainQuotation.QuoteRequest, quote *domainQuotation.Quote, now time.Time) error {
+	if request.Status != domainQuotation.RequestOpen || request.IsExpired(now) {
+		return domainQuotation.ErrRequestClosed
+	}
+	if quote.Status != domainQuotation.QuoteRequested && quote.Status != domainQuotation.QuoteSubmitted {
+		return appErrors.NewAppError("INVALID_STATUS", "Quote can no longer be changed", nil)
+	}
+	return nil
+}
+
+// CanAccept checks that a customer may accept a submitted quote
+func CanAccept(request *domainQuotation.QuoteRequest, quote *domainQuotation.Quote, now time.Time) error {
+	if request.Status != domainQuotation.RequestOpen || request.IsExpired(now) {
+		return domainQuotation.ErrRequestClosed
+	}
+	if quote.Status != domainQuotation.QuoteSubmitted {
+		return domainQuotation.ErrQuoteNotSubmitted
+	}
+	if quote.ValidUntil != nil && now.After(*quote.ValidUntil) {
+		return domainQuotation.ErrQuoteExpired
+	}
+	return nil
+}
+
+// FindQuote returns the quote of the request with the given ID
+func FindQuote(request *domainQuotation.QuoteRequest, quoteID uuid.UUID) *domainQuotation.Quote {
+	for _, q := range request.Quotes {
+		if q.ID == quoteID {
+			return q
+		}
+	}
+	return nil
+}
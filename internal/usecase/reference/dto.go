@@ -0,0 +1,58 @@
+package reference
+
+import domainReference "cargo-tracker/internal/domain/reference"
+
+type ReferenceDataResponse struct {
+	Roles           []EntryResponse         `json:"roles"`
+	IssueTypes      []EntryResponse         `json:"issue_types"`
+	ViolationTypes  []ViolationTypeResponse `json:"violation_types"`
+	GoodsCategories []GoodsCategoryResponse `json:"goods_categories"`
+}
+
+type EntryResponse struct {
+	ID          int    `json:"id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ViolationTypeResponse struct {
+	EntryResponse
+	Unit string `json:"unit"`
+}
+
+type GoodsCategoryResponse struct {
+	EntryResponse
+	TempMin *float64 `json:"temp_min"`
+	TempMax *float64 `json:"temp_max"`
+}
+
+func ToReferenceDataResponse(d *domainReference.Data) *ReferenceDataResponse {
+	resp := &ReferenceDataResponse{
+		Roles:           make([]EntryResponse, len(d.Roles)),
+		IssueTypes:      make([]EntryResponse, len(d.IssueTypes)),
+		ViolationTypes:  make([]ViolationTypeResponse, len(d.ViolationTypes)),
+		GoodsCategories: make([]GoodsCategoryResponse, len(d.GoodsCategories)),
+	}
+	for i, e := range d.Roles {
+		resp.Roles[i] = toEntryResponse(e)
+	}
+	for i, e := range d.IssueTypes {
+		resp.IssueTypes[i] = toEntryResponse(e)
+	}
+	for i, v := range d.ViolationTypes {
+		resp.ViolationTypes[i] = ViolationTypeResponse{EntryResponse: toEntryResponse(v.Entry), Unit: v.Unit}
+	}
+	for i, c := range d.GoodsCategories {
+		resp.GoodsCategories[i] = GoodsCategoryResponse{
+			EntryResponse: toEntryResponse(c.Entry),
+			TempMin:       c.TempMin,
+			TempMax:       c.TempMax,
+		}
+	}
+	return resp
+}
+
+func toEntryResponse(e domainReference.Entry) EntryResponse {
+	return EntryResponse{ID: e.ID, Code: e.Code, Name: e.Name, Description: e.Description}
+}
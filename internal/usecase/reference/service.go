@@ -0,0 +1,58 @@
+package reference
+
+import (
+	domainReference "cargo-tracker/internal/domain/reference"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/pkg/cache"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reference data only changes with a migration, so it is kept in memory and
+// served stale while a reload runs
+const (
+	dataCacheTTL   = 10 * time.Minute
+	dataCacheStale = time.Hour
+)
+
+// Service serves the reference tables: roles, issue types, violation types
+// and goods categories
+type Service struct {
+	data *cache.Value[*domainReference.Data]
+}
+
+// NewService creates a new reference data service
+func NewService(repo domainReference.Repository) *Service {
+	return &Service{
+		data: cache.NewValue(repo.Load, dataCacheTTL, dataCacheStale),
+	}
+}
+
+func (s *Service) GetReferenceData(ctx context.Context) (*ReferenceDataResponse, error) {
+	data, err := s.data.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ToReferenceDataResponse(data), nil
+}
+
+// Data returns the cached reference tables
+func (s *Service) Data(ctx context.Context) (*domainReference.Data, error) {
+	return s.data.Get(ctx)
+}
+
+// IsValid reports whether code is a value of kind. It backs the ref
+// validation tag; values are rejected while the tables cannot be loaded.
+func (s *Service) IsValid(kind, code string) bool {
+	data, err := s.data.Get(context.Background())
+	if err != nil {
+		logger.Error("Failed to load reference data for validation",
+			zap.String("kind", kind),
+			zap.Error(err),
+		)
+		return false
+	}
+	return data.Has(kind, code)
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	domainReport "cargo-tracker/internal/domain/report"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+
+type CreateSubscriptionRequest struct {
+	ReportType string   `json:"report_type" validate:"required,oneof=shipment_summary"`
+	Cadence    string   `json:"cadence" validate:"required,oneof=daily weekly monthly"`
+	Format     string   `json:"format" validate:"omitempty,oneof=text csv"`
+	Recipients []string `json:"recipients" validate:"required,min=1,max=10,dive,email"`
+	// IANA name; defaults to UTC
+	Timezone string `json:"timezone" validate:"omitempty,max=64"`
+}
+
+type UpdateSubscriptionRequest struct {
+	Cadence    *string  `json:"cadence" validate:"omitempty,oneof=daily weekly monthly"`
+	Format     *string  `json:"format" validate:"omitempty,oneof=text csv"`
+	Recipients []string `json:"recipients" validate:"omitempty,min=1,max=10,dive,email"`
+	Timezone   *string  `json:"timezone" validate:"omitempty,max=64"`
+	Active     *bool    `json:"active"`
+}
+
+// Response DTOs
+
+type SubscriptionResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	ReportType string     `json:"report_type"`
+	Cadence    string     `json:"cadence"`
+	Format     string     `json:"format"`
+	Recipients []string   `json:"recipients"`
+	Timezone   string     `json:"timezone"`
+	Active     bool       `json:"active"`
+	NextRunAt  time.Time  `json:"next_run_at"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type DeliveryResponse struct {
+	ID          uuid.UUID `json:"id"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Status      string    `json:"status"`
+	Recipients  []string  `json:"recipients"`
+	Error       *string   `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func ToSubscriptionResponse(s *domainReport.Subscription) *SubscriptionResponse {
+	return &SubscriptionResponse{
+		ID:         s.ID,
+		ReportType: string(s.ReportType),
+		Cadence:    string(s.Cadence),
+		Format:     string(s.Format),
+		Recipients: s.Recipients,
+		Timezone:   s.Timezone,
+		Active:     s.Active,
+		NextRunAt:  s.NextRunAt,
+		LastRunAt:  s.LastRunAt,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+func ToDeliveryResponse(d *domainReport.Delivery) *DeliveryResponse {
+	return &DeliveryResponse{
+		ID:          d.ID,
+		PeriodStart: d.PeriodStart,
+		PeriodEnd:   d.PeriodEnd,
+		Status:      string(d.Status),
+		Recipients:  d.Recipients,
+		Error:       d.Error,
+		CreatedAt:   d.CreatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	domainReport "cargo-tracker/internal/domain/report"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/job"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// JobKindDeliver renders and emails one report of a subscription
+const JobKindDeliver = "report.deliver"
+
+type deliveryPayload struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"`
+}
+
+// RegisterJobs registers the background jobs of the service
+func (s *Service) RegisterJobs(jobs *job.Service) {
+	s.jobs = jobs
+	// A failed attempt has not sent anything, so retrying does not email
+	// recipients twice
+	jobs.Register(JobKindDeliver, s.runDeliverJob, job.RetryPolicy{MaxAttempts: 3, Backoff: 5 * time.Minute})
+}
+
+func (s *Service) runDeliverJob(ctx context.Context, run *job.Run) (*job.Output, error) {
+	var payload deliveryPayload
+	if err := run.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	sub, err := s.repo.GetSubscription(ctx, payload.SubscriptionID)
+	if errors.Is(err, domainReport.ErrSubscriptionNotFound) {
+		// Deleted after the run was queued
+		return &job.Output{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Active {
+		return &job.Output{}, nil
+	}
+
+	sendErr := s.deliver(ctx, sub, payload.PeriodStart, payload.PeriodEnd)
+
+	delivery := &domainReport.Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		PeriodStart:    payload.PeriodStart,
+		PeriodEnd:      payload.PeriodEnd,
+		Status:         domainReport.DeliverySent,
+		Recipients:     sub.Recipients,
+		CreatedAt:      time.Now(),
+	}
+	if sendErr != nil {
+		message := sendErr.Error()
+		delivery.Status = domainReport.DeliveryFailed
+		delivery.Error = &message
+	}
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		logger.Error("Failed to record report delivery",
+			zap.String("subscription_id", sub.ID.String()),
+			zap.Error(err),
+			zap.String("event", "report_delivery_record_failed"),
+		)
+	}
+
+	if sendErr != nil {
+		logger.Warn("Report delivery failed",
+			zap.String("subscription_id", sub.ID.String()),
+			zap.Error(sendErr),
+			zap.String("event", "report_delivery_failed"),
+		)
+		return nil, sendErr
+	}
+
+	logger.Info("Report delivered",
+		zap.String("subscription_id", sub.ID.String()),
+		zap.Int("recipients", len(sub.Recipients)),
+		zap.String("event", "report_delivered"),
+	)
+	return &job.Output{Result: delivery.ID}, nil
+}
+
+// deliver renders the subscription's report for the period and emails it
+func (s *Service) deliver(ctx context.Context, sub *domainReport.Subscription, from, to time.Time) error {
+	if s.mailer == nil {
+		return domainReport.ErrDeliveryUnavailable
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, sub.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	// Periods are computed in the subscription's timezone; render them there
+	if loc, err := time.LoadLocation(sub.Timezone); err == nil {
+		from, to = from.In(loc), to.In(loc)
+	}
+
+	summary, err := s.repo.ShipmentSummary(ctx, owner.ID, owner.Role, from, to)
+	if err != nil {
+		return err
+	}
+
+	mail, err := renderShipmentSummary(sub, summary)
+	if err != nil {
+		return err
+	}
+	return s.mailer.SendMail(ctx, mail)
+}
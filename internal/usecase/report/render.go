@@ -0,0 +1,88 @@
+package report
+
+import (
+	"bytes"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainReport "cargo-tracker/internal/domain/report"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// summaryRow is one line of a shipment summary
+type summaryRow struct {
+	label string
+	value int
+}
+
+func summaryRows(s *domainReport.ShipmentSummary) []summaryRow {
+	return []summaryRow{
+		{"Shipments created", s.Created},
+		{"Deliveries completed", s.Completed},
+		{"Delivered on time", s.OnTime},
+		{"Delivered late", s.Late},
+		{"Cancelled", s.Cancelled},
+		{"Issues reported", s.IssuesReported},
+		{"In transit at period end", s.InTransit},
+		{"Overdue at period end", s.Overdue},
+	}
+}
+
+// renderShipmentSummary builds the email of a shipment summary. The CSV
+// attachment is added for subscriptions in the csv format.
+func renderShipmentSummary(sub *domainReport.Subscription, s *domainReport.ShipmentSummary) (*domainNotification.Mail, error) {
+	period := formatPeriod(s.PeriodStart, s.PeriodEnd)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Shipment summary for %s (%s)\n\n", period, sub.Timezone)
+	for _, row := range summaryRows(s) {
+		fmt.Fprintf(&body, "%-26s %d\n", row.label+":", row.value)
+	}
+	if s.Completed > 0 {
+		fmt.Fprintf(&body, "\nOn-time delivery rate: %.1f%%\n", float64(s.OnTime)/float64(s.Completed)*100)
+	}
+	body.WriteString("\nSandbox shipments are not included.\n")
+
+	mail := &domainNotification.Mail{
+		To:      sub.Recipients,
+		Subject: "Shipment summary " + period,
+		Body:    body.String(),
+	}
+
+	if sub.Format == domainReport.FormatCSV {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.Write([]string{"period_start", "period_end", "metric", "value"})
+		for _, row := range summaryRows(s) {
+			w.Write([]string{
+				s.PeriodStart.Format(time.RFC3339),
+				s.PeriodEnd.Format(time.RFC3339),
+				row.label,
+				strconv.Itoa(row.value),
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write report CSV: %w", err)
+		}
+
+		mail.Attachments = []domainNotification.Attachment{{
+			Name:        "shipment-summary-" + s.PeriodStart.Format("2006-01-02") + ".csv",
+			ContentType: "text/csv; charset=utf-8",
+			Data:        buf.Bytes(),
+		}}
+	}
+
+	return mail, nil
+}
+
+// formatPeriod prints a half-open period as the inclusive dates it covers
+func formatPeriod(start, end time.Time) string {
+	last := end.AddDate(0, 0, -1)
+	if !last.After(start) {
+		return start.Format("2006-01-02")
+	}
+	return start.Format("2006-01-02") + " to " + last.Format("2006-01-02")
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	domainReport "cargo-tracker/internal/domain/report"
+	"time"
+)
+
+// periodStart returns the start of the cadence period containing t, at
+// midnight in t's location
+func periodStart(cadence domainReport.Cadence, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch cadence {
+	case domainReport.CadenceWeekly:
+		// Weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case domainReport.CadenceMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// addPeriod moves a period start by n periods
+func addPeriod(cadence domainReport.Cadence, start time.Time, n int) time.Time {
+	switch cadence {
+	case domainReport.CadenceWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case domainReport.CadenceMonthly:
+		return start.AddDate(0, n, 0)
+	default:
+		return start.AddDate(0, 0, n)
+	}
+}
+
+// nextRun returns the first send time after t: sendHour on the first day of
+// a period, in loc
+func nextRun(cadence domainReport.Cadence, loc *time.Location, sendHour int, t time.Time) time.Time {
+	start := periodStart(cadence, t.In(loc))
+	for {
+		run := start.Add(time.Duration(sendHour) * time.Hour)
+		if run.After(t) {
+			return run
+		}
+		start = addPeriod(cadence, start, 1)
+	}
+}
+
+// reportedPeriod returns the period a run at t reports on: the full period
+// before the one t falls in
+func reportedPeriod(cadence domainReport.Cadence, loc *time.Location, t time.Time) (time.Time, time.Time) {
+	end := periodStart(cadence, t.In(loc))
+	return addPeriod(cadence, end, -1), end
+}
@@ -0,0 +1,265 @@
+package report
+
+import (
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainReport "cargo-tracker/internal/domain/report"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/job"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxListedDeliveries bounds the delivery history returned per subscription
+	maxListedDeliveries = 50
+	// dueBatchSize bounds the subscriptions queued per scheduler tick
+	dueBatchSize = 100
+)
+
+// Service manages report subscriptions. A scheduler queues a background job
+// for every subscription that is due; the job renders the report and emails
+// it to the recipients, recording each attempt in the delivery history.
+type Service struct {
+	repo     domainReport.Repository
+	userRepo domainUser.Repository
+	mailer   domainNotification.Mailer
+	jobs     *job.Service
+	config   config.ReportsConfig
+}
+
+// NewService creates a new report service. mailer is nil when no mail
+// server is configured; subscriptions then cannot be created.
+func NewService(repo domainReport.Repository, userRepo domainUser.Repository, mailer domainNotification.Mailer, cfg config.ReportsConfig) *Service {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	if cfg.SendHour < 0 || cfg.SendHour > 23 {
+		cfg.SendHour = 7
+	}
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		mailer:   mailer,
+		config:   cfg,
+	}
+}
+
+// reportRoles are the roles whose shipments can be summarised
+var reportRoles = map[string]bool{"customer": true, "provider": true, "shipper": true}
+
+func (s *Service) CreateSubscription(ctx context.Context, ownerID uuid.UUID, role string, req *CreateSubscriptionRequest) (*SubscriptionResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	if !reportRoles[role] {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Reports are available to customers, providers and shippers", nil)
+	}
+	if s.mailer == nil {
+		return nil, domainReport.ErrDeliveryUnavailable
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, appErrors.NewAppError("INVALID_TIMEZONE", "Unknown timezone", err)
+	}
+
+	format := domainReport.FormatText
+	if req.Format != "" {
+		format = domainReport.Format(req.Format)
+	}
+
+	now := time.Now()
+	cadence := domainReport.Cadence(req.Cadence)
+	sub := &domainReport.Subscription{
+		ID:         uuid.New(),
+		OwnerID:    ownerID,
+		ReportType: domainReport.Type(req.ReportType),
+		Cadence:    cadence,
+		Format:     format,
+		Recipients: req.Recipients,
+		Timezone:   timezone,
+		Active:     true,
+		NextRunAt:  nextRun(cadence, loc, s.config.SendHour, now),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Report subscription created",
+		zap.String("subscription_id", sub.ID.String()),
+		zap.String("owner_id", ownerID.String()),
+		zap.String("cadence", string(cadence)),
+		zap.String("event", "report_subscription_created"),
+	)
+
+	return ToSubscriptionResponse(sub), nil
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context, ownerID uuid.UUID) ([]*SubscriptionResponse, error) {
+	subs, err := s.repo.ListSubscriptions(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*SubscriptionResponse, len(subs))
+	for i, sub := range subs {
+		responses[i] = ToSubscriptionResponse(sub)
+	}
+	return responses, nil
+}
+
+func (s *Service) UpdateSubscription(ctx context.Context, ownerID, subscriptionID uuid.UUID, req *UpdateSubscriptionRequest) (*SubscriptionResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	sub, err := s.getOwnedSubscription(ctx, ownerID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	reschedule := false
+	if req.Cadence != nil && domainReport.Cadence(*req.Cadence) != sub.Cadence {
+		sub.Cadence = domainReport.Cadence(*req.Cadence)
+		reschedule = true
+	}
+	if req.Timezone != nil && *req.Timezone != sub.Timezone {
+		sub.Timezone = *req.Timezone
+		reschedule = true
+	}
+	if req.Active != nil && *req.Active != sub.Active {
+		sub.Active = *req.Active
+		// Resumed subscriptions continue with the next period rather than
+		// catching up on the ones missed while paused
+		reschedule = reschedule || sub.Active
+	}
+	if req.Format != nil {
+		sub.Format = domainReport.Format(*req.Format)
+	}
+	if req.Recipients != nil {
+		sub.Recipients = req.Recipients
+	}
+
+	if reschedule {
+		loc, err := time.LoadLocation(sub.Timezone)
+		if err != nil {
+			return nil, appErrors.NewAppError("INVALID_TIMEZONE", "Unknown timezone", err)
+		}
+		sub.NextRunAt = nextRun(sub.Cadence, loc, s.config.SendHour, time.Now())
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	sub.UpdatedAt = time.Now()
+
+	return ToSubscriptionResponse(sub), nil
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, ownerID, subscriptionID uuid.UUID) error {
+	if _, err := s.getOwnedSubscription(ctx, ownerID, subscriptionID); err != nil {
+		return err
+	}
+	return s.repo.DeleteSubscription(ctx, subscriptionID)
+}
+
+// ListDeliveries returns the most recent deliveries of a subscription
+func (s *Service) ListDeliveries(ctx context.Context, ownerID, subscriptionID uuid.UUID) ([]*DeliveryResponse, error) {
+	if _, err := s.getOwnedSubscription(ctx, ownerID, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, subscriptionID, maxListedDeliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*DeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = ToDeliveryResponse(d)
+	}
+	return responses, nil
+}
+
+// StartScheduler queues the deliveries of due subscriptions every poll
+// interval until ctx is cancelled. Each run is claimed by advancing the
+// subscription's next run, so several instances never send it twice.
+func (s *Service) StartScheduler(ctx context.Context) {
+	if s.mailer == nil || s.jobs == nil {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.queueDue(ctx)
+		}
+	}
+}
+
+func (s *Service) queueDue(ctx context.Context) {
+	now := time.Now()
+	subs, err := s.repo.ListDue(ctx, now, dueBatchSize)
+	if err != nil {
+		logger.Error("Failed to list due report subscriptions",
+			zap.Error(err),
+			zap.String("event", "report_schedule_failed"),
+		)
+		return
+	}
+
+	for _, sub := range subs {
+		loc, err := time.LoadLocation(sub.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		// A run missed during downtime reports on the period it was due
+		// for, and the schedule skips ahead to the next future run
+		from, to := reportedPeriod(sub.Cadence, loc, sub.NextRunAt)
+		claimed, err := s.repo.Advance(ctx, sub.ID, sub.NextRunAt, nextRun(sub.Cadence, loc, s.config.SendHour, now))
+		if err != nil || !claimed {
+			continue
+		}
+
+		payload := &deliveryPayload{SubscriptionID: sub.ID, PeriodStart: from, PeriodEnd: to}
+		if _, err := s.jobs.Enqueue(ctx, sub.OwnerID, JobKindDeliver, payload); err != nil {
+			logger.Error("Failed to queue report delivery",
+				zap.String("subscription_id", sub.ID.String()),
+				zap.Error(err),
+				zap.String("event", "report_schedule_failed"),
+			)
+		}
+	}
+}
+
+func (s *Service) getOwnedSubscription(ctx context.Context, ownerID, subscriptionID uuid.UUID) (*domainReport.Subscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	// Other users' subscriptions are reported as missing so their IDs
+	// cannot be probed
+	if sub.OwnerID != ownerID {
+		return nil, domainReport.ErrSubscriptionNotFound
+	}
+	return sub, nil
+}
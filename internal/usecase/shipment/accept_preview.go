@@ -0,0 +1,244 @@
+package shipment
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainQuota "cargo-tracker/internal/domain/quota"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outcomes of one accept preview check
+const (
+	CheckPass    = "pass"
+	CheckWarn    = "warn"
+	CheckFail    = "fail"
+	CheckUnknown = "unknown"
+)
+
+// Names of the accept preview checks
+const (
+	CheckShipmentOpen   = "shipment_open"
+	CheckAccount        = "account"
+	CheckQuota          = "quota"
+	CheckDeviceOwner    = "device_owner"
+	CheckDeviceStatus   = "device_status"
+	CheckDeviceOnline   = "device_online"
+	CheckBattery        = "battery"
+	CheckSensors        = "sensors"
+	CheckPickupDistance = "pickup_distance"
+)
+
+// Battery drain estimate used for the projection. Trackers spend most of
+// their energy on sending, so drain is counted per report: at the default
+// 60 second cycle a full battery lasts about three and a half days.
+const (
+	batteryPercentPerReport = 0.02
+	// Projected levels below this at delivery are flagged
+	batteryReserve = 20
+)
+
+type AcceptPreviewQuery struct {
+	DeviceID uuid.UUID `form:"device_id" validate:"required"`
+}
+
+// AcceptPreviewResponse tells a shipper whether accepting the order with the
+// device would succeed, and what to look out for if it would
+type AcceptPreviewResponse struct {
+	ShipmentID uuid.UUID `json:"shipment_id"`
+	DeviceID   uuid.UUID `json:"device_id"`
+	// False when any check failed; accepting now would be rejected
+	Eligible          bool                     `json:"eligible"`
+	Checks            []AcceptPreviewCheck     `json:"checks"`
+	BatteryProjection *BatteryProjectionReport `json:"battery_projection,omitempty"`
+}
+
+type AcceptPreviewCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// BatteryProjectionReport estimates the device battery over the planned
+// transit at the shipment's report cycle
+type BatteryProjectionReport struct {
+	CurrentLevel   int     `json:"current_level"`
+	TransitHours   float64 `json:"transit_hours"`
+	ReportCycleSec int     `json:"report_cycle_sec"`
+	ProjectedDrain float64 `json:"projected_drain"`
+	ProjectedLevel float64 `json:"projected_level"`
+}
+
+// PreviewAcceptOrder runs the checks AcceptOrder makes, and a few it cannot
+// enforce, without changing anything
+func (s *Service) PreviewAcceptOrder(ctx context.Context, shipmentID, shipperID uuid.UUID, query *AcceptPreviewQuery) (*AcceptPreviewResponse, error) {
+	if err := utils.ValidateStruct(query); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid query", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	shipper, err := s.userRepo.GetByID(ctx, shipperID)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &AcceptPreviewResponse{
+		ShipmentID: shipmentID,
+		DeviceID:   query.DeviceID,
+		Eligible:   true,
+	}
+	check := func(name, status, detail string) {
+		if status == CheckFail {
+			resp.Eligible = false
+		}
+		resp.Checks = append(resp.Checks, AcceptPreviewCheck{Name: name, Status: status, Detail: detail})
+	}
+
+	if err := ValidateStatusTransition(shipment.Status, domainShipment.StatusShippingAssigned); err != nil {
+		check(CheckShipmentOpen, CheckFail, fmt.Sprintf("shipment is %s and no longer open for acceptance", shipment.Status))
+	} else {
+		check(CheckShipmentOpen, CheckPass, "shipment is open for acceptance")
+	}
+
+	if shipper.IsSandbox != shipment.IsSandbox {
+		check(CheckAccount, CheckFail, errSandboxMismatch.Message)
+	} else {
+		check(CheckAccount, CheckPass, "account can take this shipment")
+	}
+
+	var appErr *appErrors.AppError
+	switch err := s.quotas.Check(ctx, shipperID, domainQuota.MetricActiveShipments); {
+	case err == nil:
+		check(CheckQuota, CheckPass, "within the active shipment limit of your plan")
+	case errors.As(err, &appErr):
+		check(CheckQuota, CheckFail, appErr.Message)
+	default:
+		return nil, err
+	}
+
+	device, err := s.deviceRepo.GetByID(ctx, query.DeviceID)
+	if errors.Is(err, domainDevice.ErrDeviceNotFound) {
+		check(CheckDeviceStatus, CheckFail, "device not found")
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Nothing more is told about another shipper's device
+	if device.OwnerShipperID != nil && *device.OwnerShipperID != shipperID {
+		check(CheckDeviceOwner, CheckFail, "device belongs to another shipper")
+		return resp, nil
+	}
+	check(CheckDeviceOwner, CheckPass, "device can be used by you")
+	checkDevice(device, check)
+
+	if rules == nil {
+		check(CheckBattery, CheckUnknown, "shipping rules are not set, so the report cycle is unknown")
+		check(CheckSensors, CheckUnknown, "shipping rules are not set")
+	} else {
+		resp.BatteryProjection = projectBattery(shipment, rules, device, check)
+		checkSensors(rules, device, check)
+	}
+
+	// Devices do not report a position, so there is nothing to measure from
+	check(CheckPickupDistance, CheckUnknown, "device location is not tracked")
+
+	return resp, nil
+}
+
+// checkDevice mirrors the status check of ValidateDevice and adds whether
+// the device is reachable
+func checkDevice(device *domainDevice.Device, check func(name, status, detail string)) {
+	if device.Status != domainDevice.StatusAvailable {
+		check(CheckDeviceStatus, CheckFail, fmt.Sprintf("device is %s", device.Status))
+	} else {
+		check(CheckDeviceStatus, CheckPass, "device is available")
+	}
+
+	switch {
+	case device.LastSeenAt == nil:
+		check(CheckDeviceOnline, CheckWarn, "device has never reported")
+	case device.IsOnline():
+		check(CheckDeviceOnline, CheckPass, "device is online")
+	default:
+		check(CheckDeviceOnline, CheckWarn,
+			fmt.Sprintf("device last reported %s ago", time.Since(*device.LastSeenAt).Round(time.Minute)))
+	}
+}
+
+// projectBattery estimates the battery left at delivery. The transit is the
+// planned pickup to delivery time, or from now to the delivery deadline when
+// no estimates were given.
+func projectBattery(shipment *domainShipment.Shipment, rules *domainShipment.ShippingRules, device *domainDevice.Device, check func(name, status, detail string)) *BatteryProjectionReport {
+	if device.BatteryLevel == nil {
+		check(CheckBattery, CheckWarn, "device has not reported its battery level")
+		return nil
+	}
+
+	var transit time.Duration
+	switch {
+	case shipment.EstimatedPickupAt != nil && shipment.EstimatedDeliveryAt != nil:
+		transit = shipment.EstimatedDeliveryAt.Sub(*shipment.EstimatedPickupAt)
+	case shipment.DeliveryDueAt != nil:
+		transit = time.Until(*shipment.DeliveryDueAt)
+	default:
+		check(CheckBattery, CheckUnknown,
+			fmt.Sprintf("battery at %d%%; the shipment has no planned transit to project over", *device.BatteryLevel))
+		return nil
+	}
+	if transit < 0 {
+		transit = 0
+	}
+
+	reports := transit.Seconds() / float64(rules.ReportCycleSec)
+	drain := math.Round(reports*batteryPercentPerReport*10) / 10
+	projection := &BatteryProjectionReport{
+		CurrentLevel:   *device.BatteryLevel,
+		TransitHours:   math.Round(transit.Hours()*10) / 10,
+		ReportCycleSec: rules.ReportCycleSec,
+		ProjectedDrain: drain,
+		ProjectedLevel: math.Max(float64(*device.BatteryLevel)-drain, 0),
+	}
+
+	detail := fmt.Sprintf("about %.0f%% left at delivery", projection.ProjectedLevel)
+	switch {
+	case projection.ProjectedLevel <= 0:
+		check(CheckBattery, CheckWarn, "battery is projected to run out before delivery")
+	case projection.ProjectedLevel < batteryReserve:
+		check(CheckBattery, CheckWarn, detail)
+	default:
+		check(CheckBattery, CheckPass, detail)
+	}
+	return projection
+}
+
+// checkSensors matches the sensors of the device's model against what the
+// rules monitor, as AcceptOrder does
+func checkSensors(rules *domainShipment.ShippingRules, device *domainDevice.Device, check func(name, status, detail string)) {
+	required := RequiredCapabilities(rules)
+	switch missing := domainDevice.Missing(device.Capabilities, required); {
+	case len(required) == 0:
+		check(CheckSensors, CheckPass, "rules do not monitor any sensor")
+	case device.Capabilities == nil:
+		check(CheckSensors, CheckUnknown,
+			fmt.Sprintf("the device model has no sensor profile; make sure it measures %s", joinCapabilities(required)))
+	case len(missing) > 0:
+		check(CheckSensors, CheckFail, fmt.Sprintf("device cannot measure %s", joinCapabilities(missing)))
+	default:
+		check(CheckSensors, CheckPass, fmt.Sprintf("device measures %s", joinCapabilities(required)))
+	}
+}
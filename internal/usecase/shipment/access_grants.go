@@ -0,0 +1,282 @@
+package shipment
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxAccessGrantDuration bounds how long a third party can be given access
+const MaxAccessGrantDuration = 90 * 24 * time.Hour
+
+type CreateAccessGrantRequest struct {
+	GranteeID    *uuid.UUID `json:"grantee_id" validate:"required_without=GranteeEmail"`
+	GranteeEmail *string    `json:"grantee_email" validate:"required_without=GranteeID,omitempty,email"`
+	Scopes       []string   `json:"scopes" validate:"required,min=1,dive,oneof=details documents"`
+	ExpiresAt    time.Time  `json:"expires_at" validate:"required"`
+	Note         *string    `json:"note" validate:"omitempty,max=500"`
+}
+
+type AccessGrantResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	ShipmentID uuid.UUID  `json:"shipment_id"`
+	GrantedBy  uuid.UUID  `json:"granted_by"`
+	GranteeID  uuid.UUID  `json:"grantee_id"`
+	Scopes     []string   `json:"scopes"`
+	Note       *string    `json:"note,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func ToAccessGrantResponse(g *domainShipment.AccessGrant) *AccessGrantResponse {
+	scopes := make([]string, len(g.Scopes))
+	for i, s := range g.Scopes {
+		scopes[i] = string(s)
+	}
+	return &AccessGrantResponse{
+		ID:         g.ID,
+		ShipmentID: g.ShipmentID,
+		GrantedBy:  g.GrantedBy,
+		GranteeID:  g.GranteeID,
+		Scopes:     scopes,
+		Note:       g.Note,
+		ExpiresAt:  g.ExpiresAt,
+		RevokedAt:  g.RevokedAt,
+		Active:     g.IsActive(time.Now()),
+		CreatedAt:  g.CreatedAt,
+	}
+}
+
+func toAccessGrantResponses(grants []*domainShipment.AccessGrant) []*AccessGrantResponse {
+	responses := make([]*AccessGrantResponse, len(grants))
+	for i, g := range grants {
+		responses[i] = ToAccessGrantResponse(g)
+	}
+	return responses
+}
+
+// CreateAccessGrant lets the shipment's customer or provider give another
+// user temporary read access to it
+func (s *Service) CreateAccessGrant(ctx context.Context, ownerID, shipmentID uuid.UUID, req *CreateAccessGrantRequest) (*AccessGrantResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.getGrantableShipment(ctx, ownerID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !req.ExpiresAt.After(now) {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Expiry must be in the future", nil)
+	}
+	if req.ExpiresAt.Sub(now) > MaxAccessGrantDuration {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Access can be granted for at most 90 days", nil)
+	}
+
+	granteeUser, err := s.resolveGrantee(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if isShipmentParty(shipment, granteeUser.ID) {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Grantee is already a party to this shipment", nil)
+	}
+	if granteeUser.IsSandbox != shipment.IsSandbox {
+		return nil, errSandboxMismatch
+	}
+
+	scopes := make([]domainShipment.AccessScope, 0, len(req.Scopes))
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, domainShipment.AccessScope(scope))
+		}
+	}
+
+	grant := &domainShipment.AccessGrant{
+		ShipmentID: shipment.ID,
+		GrantedBy:  ownerID,
+		GranteeID:  granteeUser.ID,
+		Scopes:     scopes,
+		Note:       req.Note,
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if err := s.accessGrantRepo.Create(ctx, grant); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Shipment access granted",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("grant_id", grant.ID.String()),
+		zap.String("granted_by", ownerID.String()),
+		zap.String("grantee_id", granteeUser.ID.String()),
+		zap.Time("expires_at", grant.ExpiresAt),
+		zap.String("event", "shipment_access_granted"),
+	)
+
+	s.notifyUser(ctx, granteeUser.ID, domainNotification.Message{
+		Event:    "shipment_access_granted",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "You have been given access to a shipment",
+		Body: fmt.Sprintf("You can view shipment \"%s\" until %s.",
+			shipment.GoodsDescription, grant.ExpiresAt.Format("2006-01-02 15:04")),
+		Link: "/shipments/" + shipment.ID.String(),
+		Data: map[string]string{
+			"shipment_id": shipment.ID.String(),
+			"grant_id":    grant.ID.String(),
+		},
+		Sandbox: shipment.IsSandbox,
+	})
+
+	return ToAccessGrantResponse(grant), nil
+}
+
+// ListAccessGrants returns every grant issued on a shipment, including expired
+// and revoked ones, to its customer and provider
+func (s *Service) ListAccessGrants(ctx context.Context, ownerID, shipmentID uuid.UUID) ([]*AccessGrantResponse, error) {
+	if _, err := s.getGrantableShipment(ctx, ownerID, shipmentID); err != nil {
+		return nil, err
+	}
+
+	grants, err := s.accessGrantRepo.ListByShipment(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	return toAccessGrantResponses(grants), nil
+}
+
+// ListReceivedAccessGrants returns the active grants a user holds on other
+// parties' shipments
+func (s *Service) ListReceivedAccessGrants(ctx context.Context, userID uuid.UUID) ([]*AccessGrantResponse, error) {
+	grants, err := s.accessGrantRepo.ListActiveForGrantee(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return toAccessGrantResponses(grants), nil
+}
+
+// RevokeAccessGrant ends a grant immediately
+func (s *Service) RevokeAccessGrant(ctx context.Context, ownerID, shipmentID, grantID uuid.UUID) error {
+	if _, err := s.getGrantableShipment(ctx, ownerID, shipmentID); err != nil {
+		return err
+	}
+
+	grant, err := s.accessGrantRepo.GetByID(ctx, grantID)
+	if err != nil {
+		return err
+	}
+	if grant.ShipmentID != shipmentID {
+		return domainShipment.ErrAccessGrantNotFound
+	}
+	if grant.RevokedAt != nil {
+		return appErrors.NewAppError("ALREADY_REVOKED", "Access grant has already been revoked", nil)
+	}
+
+	if err := s.accessGrantRepo.Revoke(ctx, grantID, time.Now()); err != nil {
+		return err
+	}
+
+	logger.Info("Shipment access revoked",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("grant_id", grantID.String()),
+		zap.String("revoked_by", ownerID.String()),
+		zap.String("event", "shipment_access_revoked"),
+	)
+
+	return nil
+}
+
+// authorizeViewer allows the shipment's parties, the customer's delegates,
+// admins, analysts, and holders of an active grant covering scope to read
+// the shipment
+func (s *Service) authorizeViewer(ctx context.Context, shipment *domainShipment.Shipment, userID uuid.UUID, scope domainShipment.AccessScope) error {
+	if isShipmentParty(shipment, userID) || s.isDelegateFor(ctx, shipment.CustomerID, userID) {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err == nil && (user.Role == "admin" || user.Role == "analyst") {
+		return nil
+	}
+
+	if HasAccessGrant(ctx, s.accessGrantRepo, shipment.ID, userID, scope) {
+		return nil
+	}
+	return appErrors.ErrUnauthorized
+}
+
+// HasAccessGrant reports whether the user holds an active grant on the
+// shipment covering scope. Lookup failures deny access.
+func HasAccessGrant(ctx context.Context, repo domainShipment.AccessGrantRepository, shipmentID, userID uuid.UUID, scope domainShipment.AccessScope) bool {
+	grants, err := repo.FindActive(ctx, shipmentID, userID, time.Now())
+	if err != nil {
+		logger.Warn("Failed to look up shipment access grants",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	for _, g := range grants {
+		if g.Allows(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// getGrantableShipment fetches a shipment its customer or provider may share
+func (s *Service) getGrantableShipment(ctx context.Context, ownerID, shipmentID uuid.UUID) (*domainShipment.Shipment, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if shipment.CustomerID != ownerID && shipment.ProviderID != ownerID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only the customer or provider can manage access to this shipment", nil)
+	}
+	return shipment, nil
+}
+
+func (s *Service) resolveGrantee(ctx context.Context, req *CreateAccessGrantRequest) (*domainUser.User, error) {
+	var (
+		grantee *domainUser.User
+		err     error
+	)
+	if req.GranteeID != nil {
+		grantee, err = s.userRepo.GetByID(ctx, *req.GranteeID)
+	} else {
+		grantee, err = s.userRepo.GetByEmail(ctx, *req.GranteeEmail)
+	}
+	if err != nil {
+		if errors.Is(err, domainUser.ErrUserNotFound) {
+			return nil, appErrors.NewAppError("GRANTEE_NOT_FOUND", "Grantee must have an account", err)
+		}
+		return nil, err
+	}
+	if !grantee.IsActive {
+		return nil, appErrors.NewAppError("GRANTEE_INACTIVE", "Grantee account is inactive", nil)
+	}
+	return grantee, nil
+}
+
+func isShipmentParty(shipment *domainShipment.Shipment, userID uuid.UUID) bool {
+	return shipment.CustomerID == userID ||
+		shipment.ProviderID == userID ||
+		(shipment.ShipperID != nil && *shipment.ShipperID == userID)
+}
@@ -0,0 +1,313 @@
+package shipment
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxTransitDays bounds delivery estimates
+const MaxTransitDays = 60
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+type HolidayRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+	Name string `json:"name" validate:"omitempty,max=100"`
+}
+
+type UpdateCalendarRequest struct {
+	Timezone    string           `json:"timezone" validate:"required,max=64"`
+	WorkingDays []string         `json:"working_days" validate:"required,min=1,max=7,dive,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	OpenTime    string           `json:"open_time" validate:"required,datetime=15:04"`
+	CloseTime   string           `json:"close_time" validate:"required,datetime=15:04"`
+	CutoffTime  *string          `json:"cutoff_time" validate:"omitempty,datetime=15:04"`
+	Holidays    []HolidayRequest `json:"holidays" validate:"max=366,dive"`
+}
+
+type CalendarResponse struct {
+	ProviderID  uuid.UUID                `json:"provider_id"`
+	Timezone    string                   `json:"timezone"`
+	WorkingDays []string                 `json:"working_days"`
+	OpenTime    string                   `json:"open_time"`
+	CloseTime   string                   `json:"close_time"`
+	CutoffTime  *string                  `json:"cutoff_time,omitempty"`
+	Holidays    []domainShipment.Holiday `json:"holidays"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+type DeliveryEstimateResponse struct {
+	ProviderID          uuid.UUID `json:"provider_id"`
+	From                time.Time `json:"from"`
+	TransitDays         int       `json:"transit_days"`
+	EstimatedDeliveryAt time.Time `json:"estimated_delivery_at"`
+	// False when the provider has no calendar and calendar days were counted
+	BusinessCalendar bool `json:"business_calendar"`
+}
+
+func ToCalendarResponse(c *domainShipment.BusinessCalendar) *CalendarResponse {
+	days := make([]string, len(c.WorkingDays))
+	for i, d := range c.WorkingDays {
+		days[i] = strings.ToLower(d.String())
+	}
+
+	resp := &CalendarResponse{
+		ProviderID:  c.ProviderID,
+		Timezone:    c.Timezone,
+		WorkingDays: days,
+		OpenTime:    formatMinute(c.OpenMinute),
+		CloseTime:   formatMinute(c.CloseMinute),
+		Holidays:    c.Holidays,
+		UpdatedAt:   c.UpdatedAt,
+	}
+	if c.CutoffMinute != nil {
+		cutoff := formatMinute(*c.CutoffMinute)
+		resp.CutoffTime = &cutoff
+	}
+	if resp.Holidays == nil {
+		resp.Holidays = []domainShipment.Holiday{}
+	}
+	return resp
+}
+
+// GetCalendar returns a provider's business calendar
+func (s *Service) GetCalendar(ctx context.Context, providerID uuid.UUID) (*CalendarResponse, error) {
+	calendar, err := s.calendarRepo.Get(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	return ToCalendarResponse(calendar), nil
+}
+
+// UpdateCalendar replaces the provider's business calendar and moves the
+// delivery deadlines of its open shipments accordingly
+func (s *Service) UpdateCalendar(ctx context.Context, providerID uuid.UUID, req *UpdateCalendarRequest) (*CalendarResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Unknown time zone", err)
+	}
+	openMinute, _ := parseMinute(req.OpenTime)
+	closeMinute, _ := parseMinute(req.CloseTime)
+	if closeMinute == 0 {
+		closeMinute = 24 * 60 // 00:00 closing means midnight
+	}
+	if openMinute >= closeMinute {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Opening time must be before closing time", nil)
+	}
+
+	calendar := &domainShipment.BusinessCalendar{
+		ProviderID:  providerID,
+		Timezone:    req.Timezone,
+		OpenMinute:  openMinute,
+		CloseMinute: closeMinute,
+	}
+	if req.CutoffTime != nil {
+		cutoff, _ := parseMinute(*req.CutoffTime)
+		if cutoff < openMinute || cutoff > closeMinute {
+			return nil, appErrors.NewAppError("VALIDATION_ERROR", "Cutoff time must be within working hours", nil)
+		}
+		calendar.CutoffMinute = &cutoff
+	}
+
+	seenDays := make(map[time.Weekday]bool)
+	for _, name := range req.WorkingDays {
+		day := weekdayNames[name]
+		if !seenDays[day] {
+			seenDays[day] = true
+			calendar.WorkingDays = append(calendar.WorkingDays, day)
+		}
+	}
+	sort.Slice(calendar.WorkingDays, func(i, j int) bool { return calendar.WorkingDays[i] < calendar.WorkingDays[j] })
+
+	seenDates := make(map[string]bool)
+	for _, h := range req.Holidays {
+		if !seenDates[h.Date] {
+			seenDates[h.Date] = true
+			calendar.Holidays = append(calendar.Holidays, domainShipment.Holiday{Date: h.Date, Name: h.Name})
+		}
+	}
+	sort.Slice(calendar.Holidays, func(i, j int) bool { return calendar.Holidays[i].Date < calendar.Holidays[j].Date })
+
+	if existing, err := s.calendarRepo.Get(ctx, providerID); err == nil {
+		calendar.CreatedAt = existing.CreatedAt
+	}
+	if err := s.calendarRepo.Save(ctx, calendar); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Business calendar updated",
+		zap.String("provider_id", providerID.String()),
+		zap.String("timezone", calendar.Timezone),
+		zap.Int("holidays", len(calendar.Holidays)),
+		zap.String("event", "business_calendar_updated"),
+	)
+
+	s.refreshDeliveryDeadlines(ctx, providerID, calendar)
+	return ToCalendarResponse(calendar), nil
+}
+
+// DeleteCalendar removes the provider's calendar; deadlines fall back to the
+// estimated delivery times
+func (s *Service) DeleteCalendar(ctx context.Context, providerID uuid.UUID) error {
+	if err := s.calendarRepo.Delete(ctx, providerID); err != nil {
+		return err
+	}
+
+	logger.Info("Business calendar deleted",
+		zap.String("provider_id", providerID.String()),
+		zap.String("event", "business_calendar_deleted"),
+	)
+
+	s.refreshDeliveryDeadlines(ctx, providerID, nil)
+	return nil
+}
+
+// EstimateDelivery estimates when an order placed with the provider at from
+// arrives after transitDays business days
+func (s *Service) EstimateDelivery(ctx context.Context, providerID uuid.UUID, from time.Time, transitDays int) (*DeliveryEstimateResponse, error) {
+	if transitDays < 0 || transitDays > MaxTransitDays {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", fmt.Sprintf("Transit days must be between 0 and %d", MaxTransitDays), nil)
+	}
+
+	resp := &DeliveryEstimateResponse{
+		ProviderID:  providerID,
+		From:        from,
+		TransitDays: transitDays,
+	}
+
+	calendar, err := s.calendarRepo.Get(ctx, providerID)
+	if errors.Is(err, domainShipment.ErrCalendarNotFound) {
+		resp.EstimatedDeliveryAt = from.AddDate(0, 0, transitDays)
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, ok := calendar.EstimateDelivery(from, transitDays)
+	if !ok {
+		return nil, appErrors.NewAppError("NO_BUSINESS_DAYS", "Provider has no working days in the coming year", nil)
+	}
+	resp.EstimatedDeliveryAt = estimate
+	resp.BusinessCalendar = true
+	return resp, nil
+}
+
+// deliveryDueAt moves an estimated delivery time into the provider's working
+// hours. Without a calendar the estimate itself is the deadline.
+func (s *Service) deliveryDueAt(ctx context.Context, providerID uuid.UUID, estimated *time.Time) *time.Time {
+	if estimated == nil {
+		return nil
+	}
+
+	calendar, err := s.calendarRepo.Get(ctx, providerID)
+	if err != nil {
+		if !errors.Is(err, domainShipment.ErrCalendarNotFound) {
+			logger.Warn("Failed to load business calendar",
+				zap.String("provider_id", providerID.String()),
+				zap.Error(err),
+			)
+		}
+		due := *estimated
+		return &due
+	}
+
+	due := calendar.Deadline(*estimated)
+	return &due
+}
+
+// refreshDeliveryDeadlines recomputes the deadlines of the provider's
+// unfinished shipments after its calendar changed
+func (s *Service) refreshDeliveryDeadlines(ctx context.Context, providerID uuid.UUID, calendar *domainShipment.BusinessCalendar) {
+	const pageSize = 100
+	updated := 0
+
+	for page := 1; ; page++ {
+		shipments, _, err := s.shipmentRepo.List(ctx, &domainShipment.Filter{
+			ProviderID: &providerID,
+			Page:       page,
+			PageSize:   pageSize,
+			SortBy:     "created_at",
+			SortOrder:  "asc",
+		})
+		if err != nil {
+			logger.Warn("Failed to list shipments for deadline refresh",
+				zap.String("provider_id", providerID.String()),
+				zap.Error(err),
+			)
+			return
+		}
+
+		for _, sh := range shipments {
+			if sh.EstimatedDeliveryAt == nil || isTerminalStatus(sh.Status) {
+				continue
+			}
+
+			due := *sh.EstimatedDeliveryAt
+			if calendar != nil {
+				due = calendar.Deadline(due)
+			}
+			if sh.DeliveryDueAt != nil && sh.DeliveryDueAt.Equal(due) {
+				continue
+			}
+			if err := s.shipmentRepo.SetDeliveryDueAt(ctx, sh.ID, &due); err != nil {
+				logger.Warn("Failed to update delivery deadline",
+					zap.String("shipment_id", sh.ID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			updated++
+		}
+
+		if len(shipments) < pageSize {
+			break
+		}
+	}
+
+	logger.Info("Delivery deadlines refreshed",
+		zap.String("provider_id", providerID.String()),
+		zap.Int("updated", updated),
+		zap.String("event", "delivery_deadlines_refreshed"),
+	)
+}
+
+func isTerminalStatus(status domainShipment.ShipmentStatus) bool {
+	return len(GetAllowedTransitions(status)) == 0
+}
+
+func parseMinute(hhmm string) (int, error) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatMinute(minute int) string {
+	if minute >= 24*60 {
+		return "24:00"
+	}
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
@@ -0,0 +1,150 @@
+package shipment
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultComparisonPeriod is compared when the query leaves out from
+	DefaultComparisonPeriod = 90 * 24 * time.Hour
+	// MaxComparisonPeriod bounds the period a comparison can cover
+	MaxComparisonPeriod = 366 * 24 * time.Hour
+)
+
+type ShipperComparisonQuery struct {
+	From         *time.Time `form:"from"`
+	To           *time.Time `form:"to"`
+	PickupCity   string     `form:"pickup_city" validate:"omitempty,max=100"`
+	DeliveryCity string     `form:"delivery_city" validate:"omitempty,max=100"`
+}
+
+type ShipperComparisonResponse struct {
+	From         time.Time                `json:"from"`
+	To           time.Time                `json:"to"`
+	PickupCity   string                   `json:"pickup_city,omitempty"`
+	DeliveryCity string                   `json:"delivery_city,omitempty"`
+	Shippers     []ShipperComparisonEntry `json:"shippers"`
+}
+
+type ShipperComparisonEntry struct {
+	ShipperID   uuid.UUID `json:"shipper_id"`
+	ShipperName string    `json:"shipper_name"`
+
+	Shipments  int `json:"shipments"`
+	Delivered  int `json:"delivered"`
+	Cancelled  int `json:"cancelled"`
+	OpenIssues int `json:"open_issues"`
+
+	// Percentages of delivered shipments; nil until the shipper delivered one
+	OnTimeRate *float64 `json:"on_time_rate"`
+	// Deliveries that arrived with a damaged, missing or refused package
+	ViolationRate *float64 `json:"violation_rate"`
+	// Percentage of accepted shipments that were cancelled
+	CancellationRate float64 `json:"cancellation_rate"`
+
+	Rated     int      `json:"rated"`
+	AvgRating *float64 `json:"avg_rating"`
+
+	Costs []ShipperCostResponse `json:"costs"`
+}
+
+type ShipperCostResponse struct {
+	Currency string `json:"currency"`
+	// Shipments with an agreed price in this currency
+	Shipments int     `json:"shipments"`
+	Total     float64 `json:"total"`
+	Average   float64 `json:"average"`
+}
+
+func ToShipperComparisonEntry(p *domainShipment.ShipperPerformance) ShipperComparisonEntry {
+	entry := ShipperComparisonEntry{
+		ShipperID:   p.ShipperID,
+		ShipperName: p.ShipperName,
+		Shipments:   p.Assigned,
+		Delivered:   p.Delivered,
+		Cancelled:   p.Cancelled,
+		OpenIssues:  p.OpenIssues,
+		Rated:       p.Rated,
+		Costs:       make([]ShipperCostResponse, len(p.Costs)),
+	}
+	if p.Delivered > 0 {
+		onTime := float64(p.OnTime) / float64(p.Delivered) * 100
+		violations := float64(p.Partial) / float64(p.Delivered) * 100
+		entry.OnTimeRate = &onTime
+		entry.ViolationRate = &violations
+	}
+	if p.Assigned > 0 {
+		entry.CancellationRate = float64(p.Cancelled) / float64(p.Assigned) * 100
+	}
+	if p.Rated > 0 {
+		rating := p.AvgRating
+		entry.AvgRating = &rating
+	}
+	for i, c := range p.Costs {
+		entry.Costs[i] = ShipperCostResponse{
+			Currency:  c.Currency,
+			Shipments: c.Shipments,
+			Total:     c.Total,
+			Average:   c.Total / float64(c.Shipments),
+		}
+	}
+	return entry
+}
+
+// CompareShippers compares the shippers that handled the provider's
+// shipments created in the queried period, optionally on one lane
+func (s *Service) CompareShippers(ctx context.Context, providerID uuid.UUID, req *ShipperComparisonQuery) (*ShipperComparisonResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid query", err)
+	}
+
+	to := time.Now()
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.Add(-DefaultComparisonPeriod)
+	if req.From != nil {
+		from = *req.From
+	}
+	if !from.Before(to) {
+		return nil, appErrors.NewAppError("INVALID_PERIOD", "from must be before to", nil)
+	}
+	if to.Sub(from) > MaxComparisonPeriod {
+		return nil, appErrors.NewAppError("INVALID_PERIOD", "Period cannot be longer than 366 days", nil)
+	}
+
+	provider, err := s.userRepo.GetByID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	performances, err := s.shipmentRepo.CompareShippers(ctx, &domainShipment.ComparisonQuery{
+		ProviderID:   providerID,
+		Sandbox:      provider.IsSandbox,
+		From:         from,
+		To:           to,
+		PickupCity:   utils.NormalizeSearch(req.PickupCity),
+		DeliveryCity: utils.NormalizeSearch(req.DeliveryCity),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ShipperComparisonResponse{
+		From:         from,
+		To:           to,
+		PickupCity:   req.PickupCity,
+		DeliveryCity: req.DeliveryCity,
+		Shippers:     make([]ShipperComparisonEntry, len(performances)),
+	}
+	for i, p := range performances {
+		resp.Shippers[i] = ToShipperComparisonEntry(p)
+	}
+	return resp, nil
+}
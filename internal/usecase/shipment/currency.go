@@ -0,0 +1,54 @@
+package shipment
+
+import (
+	domainCurrency "cargo-tracker/internal/domain/currency"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// goodsCurrency picks the currency of a new shipment's goods value: the one
+// the customer named, else the provider's default, else the reporting
+// currency. When there is a value its exchange rate is captured so reports
+// do not move with the market. An unreachable rate source does not block
+// the demand; the value is then left out of converted statistics.
+func (s *Service) goodsCurrency(ctx context.Context, providerID uuid.UUID, requested string, value *float64) (string, *domainShipment.ExchangeRate, error) {
+	currency := strings.ToUpper(requested)
+	if currency == "" {
+		if provider, err := s.userRepo.GetByID(ctx, providerID); err == nil && provider.DefaultCurrency != nil {
+			currency = *provider.DefaultCurrency
+		}
+	}
+	if currency == "" {
+		currency = s.rates.ReportingCurrency()
+	}
+	if value == nil {
+		return currency, nil, nil
+	}
+
+	rate, err := s.rates.Rate(ctx, currency)
+	if errors.Is(err, domainCurrency.ErrUnknownCurrency) {
+		return "", nil, appErrors.NewAppError("UNSUPPORTED_CURRENCY", "Goods currency "+currency+" is not supported", err)
+	}
+	if err != nil {
+		logger.Warn("No exchange rate captured for goods value",
+			zap.String("currency", currency),
+			zap.Error(err),
+			zap.String("event", "exchange_rate_missing"),
+		)
+		return currency, nil, nil
+	}
+
+	return currency, &domainShipment.ExchangeRate{
+		Currency: s.rates.ReportingCurrency(),
+		Rate:     rate,
+		RatedAt:  time.Now(),
+	}, nil
+}
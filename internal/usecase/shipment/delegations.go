@@ -0,0 +1,232 @@
+package shipment
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CreateDelegationRequest struct {
+	DelegateID    *uuid.UUID `json:"delegate_id" validate:"required_without=DelegateEmail"`
+	DelegateEmail *string    `json:"delegate_email" validate:"required_without=DelegateID,omitempty,email"`
+	Note          *string    `json:"note" validate:"omitempty,max=500"`
+}
+
+type DelegationResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	CustomerID uuid.UUID  `json:"customer_id"`
+	DelegateID uuid.UUID  `json:"delegate_id"`
+	Note       *string    `json:"note,omitempty"`
+	Active     bool       `json:"active"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// DelegationsResponse lists both sides of a user's delegations
+type DelegationsResponse struct {
+	// Delegations the user granted as a customer, revoked ones included
+	Granted []*DelegationResponse `json:"granted"`
+	// Customers the user can currently book for
+	Received []*DelegationResponse `json:"received"`
+}
+
+func ToDelegationResponse(d *domainShipment.Delegation) *DelegationResponse {
+	return &DelegationResponse{
+		ID:         d.ID,
+		CustomerID: d.CustomerID,
+		DelegateID: d.DelegateID,
+		Note:       d.Note,
+		Active:     d.IsActive(),
+		RevokedAt:  d.RevokedAt,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+func toDelegationResponses(delegations []*domainShipment.Delegation) []*DelegationResponse {
+	responses := make([]*DelegationResponse, len(delegations))
+	for i, d := range delegations {
+		responses[i] = ToDelegationResponse(d)
+	}
+	return responses
+}
+
+// CreateDelegation lets a customer allow another customer account, such as
+// their freight forwarder, to create and cancel shipments in their name
+func (s *Service) CreateDelegation(ctx context.Context, customerID uuid.UUID, req *CreateDelegationRequest) (*DelegationResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	customer, err := s.userRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	delegate, err := s.resolveDelegate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if delegate.ID == customerID {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "You cannot delegate to yourself", nil)
+	}
+	// Delegates book through the customer endpoints
+	if delegate.Role != "customer" {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Delegate must have a customer account", nil)
+	}
+	if delegate.IsSandbox != customer.IsSandbox {
+		return nil, errSandboxMismatch
+	}
+
+	_, err = s.delegationRepo.FindActive(ctx, customerID, delegate.ID)
+	if err == nil {
+		return nil, appErrors.NewAppError("ALREADY_DELEGATED", "Delegate can already book for you", nil)
+	}
+	if !errors.Is(err, domainShipment.ErrDelegationNotFound) {
+		return nil, err
+	}
+
+	delegation := &domainShipment.Delegation{
+		CustomerID: customerID,
+		DelegateID: delegate.ID,
+		Note:       req.Note,
+	}
+	if err := s.delegationRepo.Create(ctx, delegation); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Booking delegated",
+		zap.String("delegation_id", delegation.ID.String()),
+		zap.String("customer_id", customerID.String()),
+		zap.String("delegate_id", delegate.ID.String()),
+		zap.String("event", "delegation_created"),
+	)
+
+	s.notifyUser(ctx, delegate.ID, domainNotification.Message{
+		Event:    "delegation_created",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "You can now book shipments for a client",
+		Body:     fmt.Sprintf("%s has allowed you to create and manage shipments in their name.", customer.FullName),
+		Link:     "/delegations",
+		Data: map[string]string{
+			"delegation_id": delegation.ID.String(),
+			"customer_id":   customerID.String(),
+		},
+		Sandbox: customer.IsSandbox,
+	})
+
+	return ToDelegationResponse(delegation), nil
+}
+
+// ListDelegations returns the delegations the user granted and the ones they
+// can act on
+func (s *Service) ListDelegations(ctx context.Context, userID uuid.UUID) (*DelegationsResponse, error) {
+	granted, err := s.delegationRepo.ListByCustomer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	received, err := s.delegationRepo.ListActiveForDelegate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DelegationsResponse{
+		Granted:  toDelegationResponses(granted),
+		Received: toDelegationResponses(received),
+	}, nil
+}
+
+// RevokeDelegation ends a delegation immediately. Either side can end it.
+// Shipments already booked stay with the customer, but the delegate can no
+// longer see or cancel them.
+func (s *Service) RevokeDelegation(ctx context.Context, userID, delegationID uuid.UUID) error {
+	delegation, err := s.delegationRepo.GetByID(ctx, delegationID)
+	if err != nil {
+		return err
+	}
+	if delegation.CustomerID != userID && delegation.DelegateID != userID {
+		return domainShipment.ErrDelegationNotFound
+	}
+	if !delegation.IsActive() {
+		return appErrors.NewAppError("ALREADY_REVOKED", "Delegation has already been revoked", nil)
+	}
+
+	if err := s.delegationRepo.Revoke(ctx, delegationID, time.Now()); err != nil {
+		return err
+	}
+
+	logger.Info("Booking delegation revoked",
+		zap.String("delegation_id", delegationID.String()),
+		zap.String("customer_id", delegation.CustomerID.String()),
+		zap.String("delegate_id", delegation.DelegateID.String()),
+		zap.String("revoked_by", userID.String()),
+		zap.String("event", "delegation_revoked"),
+	)
+
+	return nil
+}
+
+// bookingCustomer returns the customer a demand is created for, and the
+// delegate booking it when that is not the caller's own account
+func (s *Service) bookingCustomer(ctx context.Context, userID uuid.UUID, onBehalfOf *uuid.UUID) (uuid.UUID, *uuid.UUID, error) {
+	if onBehalfOf == nil || *onBehalfOf == userID {
+		return userID, nil, nil
+	}
+
+	_, err := s.delegationRepo.FindActive(ctx, *onBehalfOf, userID)
+	if errors.Is(err, domainShipment.ErrDelegationNotFound) {
+		return uuid.Nil, nil, appErrors.NewAppError("NOT_DELEGATED", "You are not allowed to book for this customer", nil)
+	}
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return *onBehalfOf, &userID, nil
+}
+
+// isDelegateFor reports whether the user can currently act for the customer.
+// Lookup failures deny access.
+func (s *Service) isDelegateFor(ctx context.Context, customerID, userID uuid.UUID) bool {
+	if customerID == userID {
+		return false
+	}
+
+	_, err := s.delegationRepo.FindActive(ctx, customerID, userID)
+	if err != nil && !errors.Is(err, domainShipment.ErrDelegationNotFound) {
+		logger.Warn("Failed to look up delegation",
+			zap.String("customer_id", customerID.String()),
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+	}
+	return err == nil
+}
+
+func (s *Service) resolveDelegate(ctx context.Context, req *CreateDelegationRequest) (*domainUser.User, error) {
+	var (
+		delegate *domainUser.User
+		err      error
+	)
+	if req.DelegateID != nil {
+		delegate, err = s.userRepo.GetByID(ctx, *req.DelegateID)
+	} else {
+		delegate, err = s.userRepo.GetByEmail(ctx, *req.DelegateEmail)
+	}
+	if err != nil {
+		if errors.Is(err, domainUser.ErrUserNotFound) {
+			return nil, appErrors.NewAppError("DELEGATE_NOT_FOUND", "Delegate must have an account", err)
+		}
+		return nil, err
+	}
+	if !delegate.IsActive {
+		return nil, appErrors.NewAppError("DELEGATE_INACTIVE", "Delegate account is inactive", nil)
+	}
+	return delegate, nil
+}
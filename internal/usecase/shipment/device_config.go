@@ -0,0 +1,92 @@
+package shipment
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceConfigResponse is what a tracker needs to pick up its job again, for
+// example after a reboot
+type DeviceConfigResponse struct {
+	DeviceID    uuid.UUID `json:"device_id"`
+	HardwareUID string    `json:"hardware_uid"`
+	// Assigned is false while the device has no active shipment; the
+	// shipment fields and thresholds are then null
+	Assigned       bool                           `json:"assigned"`
+	ShipmentID     *uuid.UUID                     `json:"shipment_id"`
+	PackageID      *uuid.UUID                     `json:"package_id"`
+	ShipmentStatus *domainShipment.ShipmentStatus `json:"shipment_status"`
+	// RulesVersionID changes whenever the thresholds do
+	RulesVersionID *uuid.UUID        `json:"rules_version_id"`
+	ReportCycleSec *int              `json:"report_cycle_sec"`
+	Thresholds     *DeviceThresholds `json:"thresholds"`
+	// ServerTime lets the device correct its clock
+	ServerTime time.Time `json:"server_time"`
+}
+
+// DeviceThresholds are the limits of the shipping rules a device checks its
+// readings against
+type DeviceThresholds struct {
+	TempMin          *float64 `json:"temp_min"`
+	TempMax          *float64 `json:"temp_max"`
+	HumidityMin      *float64 `json:"humidity_min"`
+	HumidityMax      *float64 `json:"humidity_max"`
+	LightMax         *float64 `json:"light_max"`
+	TiltMaxAngle     *float64 `json:"tilt_max_angle"`
+	ImpactThresholdG *float64 `json:"impact_threshold_g"`
+}
+
+// GetDeviceConfig returns the active assignment and thresholds of an
+// authenticated device
+func (s *Service) GetDeviceConfig(ctx context.Context, deviceID uuid.UUID) (*DeviceConfigResponse, error) {
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DeviceConfigResponse{
+		DeviceID:    device.ID,
+		HardwareUID: device.HardwareUID,
+		ServerTime:  time.Now().UTC(),
+	}
+
+	assignment, err := s.ResolveDevice(ctx, deviceID)
+	if errors.Is(err, domainShipment.ErrDeviceNotAssigned) {
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Assigned = true
+	resp.ShipmentID = &assignment.ShipmentID
+	resp.PackageID = assignment.PackageID
+	resp.ShipmentStatus = &assignment.Status
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, assignment.ShipmentID)
+	if err != nil {
+		return nil, err
+	}
+	resp.RulesVersionID = shipment.RulesVersionID
+
+	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, assignment.ShipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if rules != nil {
+		resp.ReportCycleSec = &rules.ReportCycleSec
+		resp.Thresholds = &DeviceThresholds{
+			TempMin:          rules.TempMin,
+			TempMax:          rules.TempMax,
+			HumidityMin:      rules.HumidityMin,
+			HumidityMax:      rules.HumidityMax,
+			LightMax:         rules.LightMax,
+			TiltMaxAngle:     rules.TiltMaxAngle,
+			ImpactThresholdG: rules.ImpactThresholdG,
+		}
+	}
+	return resp, nil
+}
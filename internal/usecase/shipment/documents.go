@@ -0,0 +1,408 @@
+package shipment
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/pdf"
+	"cargo-tracker/pkg/qrcode"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// documentParties holds the resolved users printed on warehouse documents.
+type documentParties struct {
+	customer *domainUser.User
+	provider *domainUser.User
+	shipper  *domainUser.User
+}
+
+// documentBranding is the provider look applied to printed documents.
+type documentBranding struct {
+	name    string
+	logo    *pdf.Image
+	primary pdf.Color
+	support string
+}
+
+// loadDocumentBranding resolves the branding of a provider and embeds its logo
+// in doc. Documents fall back to the platform look when it cannot be loaded.
+func (s *Service) loadDocumentBranding(ctx context.Context, doc *pdf.Document, providerID uuid.UUID) *documentBranding {
+	brand := &documentBranding{name: "CARGO TRACKER", primary: pdf.Black}
+
+	branding, err := s.branding.GetBranding(ctx, providerID)
+	if err != nil {
+		return brand
+	}
+
+	brand.name = branding.DisplayName
+	if branding.PrimaryColor != nil {
+		if c, err := pdf.ParseHexColor(*branding.PrimaryColor); err == nil {
+			brand.primary = c
+		}
+	}
+
+	var contacts []string
+	for _, contact := range []*string{branding.SupportEmail, branding.SupportPhone, branding.SupportURL} {
+		if contact != nil && *contact != "" {
+			contacts = append(contacts, *contact)
+		}
+	}
+	if len(contacts) > 0 {
+		brand.support = "Support: " + strings.Join(contacts, "  •  ")
+	}
+
+	if branding.LogoURL != nil {
+		data, err := s.branding.LoadLogo(ctx, providerID)
+		if err == nil && data != nil {
+			brand.logo, err = doc.AddImage(data)
+		}
+		if err != nil {
+			logger.Warn("Failed to embed provider logo",
+				zap.String("provider_id", providerID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return brand
+}
+
+// GenerateLabel renders the 4x6" package label for a shipment.
+func (s *Service) GenerateLabel(ctx context.Context, userID, shipmentID uuid.UUID) ([]byte, error) {
+	shipment, rules, parties, err := s.loadDocumentData(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	qr, err := qrcode.Encode(ShipmentQRPayload(shipment.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	doc := pdf.New(pdf.LabelWidth, pdf.LabelHeight)
+	doc.SetTitle("Label " + shipment.ID.String())
+	brand := s.loadDocumentBranding(ctx, doc, shipment.ProviderID)
+	page := doc.AddPage()
+
+	const margin = 14.0
+	width := float64(pdf.LabelWidth) - 2*margin
+	headerWidth := width - 104
+
+	y := 42.0
+	if brand.logo != nil {
+		page.Image(brand.logo, margin, 12, headerWidth, 32)
+		y = 56
+	} else {
+		page.SetColor(brand.primary)
+		page.Text(margin, 28, 14, true, truncate(brand.name, 22))
+		page.SetColor(pdf.Black)
+	}
+	page.Text(margin, y, 8, false, "Package label")
+	if brand.support != "" {
+		for _, line := range pdf.Wrap(brand.support, 7, headerWidth) {
+			y += 10
+			if y > 106 {
+				break
+			}
+			page.Text(margin, y, 7, false, line)
+		}
+	}
+	drawQR(page, qr, margin+width-96, margin, 96)
+	page.SetColor(brand.primary)
+	page.Line(margin, 116, margin+width, 116, 1)
+	page.SetColor(pdf.Black)
+
+	y = 132.0
+	y = drawBlock(page, margin, y, width, "FROM", userName(parties.provider), shipment.PickupAddress)
+	y = drawBlock(page, margin, y, width, "TO", userName(parties.customer), shipment.DeliveryAddress)
+	y = drawBlock(page, margin, y, width, "CARRIER", userName(parties.shipper), "")
+
+	page.Line(margin, y, margin+width, y, 1)
+	y += 16
+	page.Text(margin, y, 9, true, "HANDLING")
+	y += 14
+	instructions := HandlingInstructions(rules)
+	if len(instructions) == 0 {
+		instructions = []string{"No special handling"}
+	}
+	for _, instruction := range instructions {
+		page.Text(margin, y, 11, true, instruction)
+		y += 14
+	}
+
+	page.Line(margin, float64(pdf.LabelHeight)-40, margin+width, float64(pdf.LabelHeight)-40, 1)
+	page.Text(margin, float64(pdf.LabelHeight)-26, 7, false, "Shipment "+shipment.ID.String())
+	page.Text(margin, float64(pdf.LabelHeight)-16, 7, false, "Goods: "+truncate(shipment.GoodsDescription, 60))
+
+	logger.Info("Shipment label generated",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("event", "shipment_label_generated"),
+	)
+
+	return doc.Bytes(), nil
+}
+
+// GenerateManifest renders the A4 pick-up manifest handed over at the warehouse.
+func (s *Service) GenerateManifest(ctx context.Context, userID, shipmentID uuid.UUID) ([]byte, error) {
+	shipment, rules, parties, err := s.loadDocumentData(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	qr, err := qrcode.Encode(ShipmentQRPayload(shipment.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.SetTitle("Manifest " + shipment.ID.String())
+	brand := s.loadDocumentBranding(ctx, doc, shipment.ProviderID)
+	page := doc.AddPage()
+
+	const margin = 48.0
+	width := pdf.A4Width - 2*margin
+
+	if brand.logo != nil {
+		page.Image(brand.logo, margin, 14, 160, 28)
+	}
+	page.SetColor(brand.primary)
+	page.Text(margin, 64, 20, true, "Pick-up Manifest")
+	page.SetColor(pdf.Black)
+	page.Text(margin, 84, 9, false, "Shipment ID: "+shipment.ID.String())
+	page.Text(margin, 98, 9, false, "Status: "+string(shipment.Status))
+	page.Text(margin, 112, 9, false, "Generated: "+time.Now().UTC().Format("2006-01-02 15:04 MST"))
+	drawQR(page, qr, margin+width-96, 40, 96)
+
+	y := 156.0
+	y = drawSection(page, margin, y, width, "Parties", brand.primary)
+	for _, p := range []struct {
+		role string
+		user *domainUser.User
+	}{
+		{"Customer", parties.customer},
+		{"Provider", parties.provider},
+		{"Shipper", parties.shipper},
+	} {
+		page.Text(margin, y, 10, true, p.role)
+		page.Text(margin+80, y, 10, false, userName(p.user))
+		if p.user != nil {
+			contact := p.user.Email
+			if p.user.PhoneNumber != nil && *p.user.PhoneNumber != "" {
+				contact += "  •  " + *p.user.PhoneNumber
+			}
+			page.Text(margin+260, y, 9, false, contact)
+		}
+		y += 16
+	}
+
+	y = drawSection(page, margin, y+8, width, "Route", brand.primary)
+	y = drawField(page, margin, y, width, "Pick-up", shipment.PickupAddress)
+	y = drawField(page, margin, y, width, "Delivery", shipment.DeliveryAddress)
+	y = drawField(page, margin, y, width, "Est. pick-up", formatTime(shipment.EstimatedPickupAt))
+	y = drawField(page, margin, y, width, "Est. delivery", formatTime(shipment.EstimatedDeliveryAt))
+
+	y = drawSection(page, margin, y+8, width, "Goods", brand.primary)
+	y = drawField(page, margin, y, width, "Description", shipment.GoodsDescription)
+	y = drawField(page, margin, y, width, "Weight", formatOptional(shipment.GoodsWeight, " kg"))
+	y = drawField(page, margin, y, width, "Declared value", formatOptional(shipment.GoodsValue, " "+shipment.GoodsCurrency))
+
+	y = drawSection(page, margin, y+8, width, "Handling & monitoring", brand.primary)
+	instructions := HandlingInstructions(rules)
+	if len(instructions) == 0 {
+		instructions = []string{"No special handling"}
+	}
+	for _, instruction := range instructions {
+		page.Text(margin, y, 10, true, "•  "+instruction)
+		y += 14
+	}
+	if rules != nil {
+		y = drawField(page, margin, y+4, width, "Report cycle", strconv.Itoa(rules.ReportCycleSec)+" s")
+	}
+	if shipment.LinkedDeviceID != nil {
+		y = drawField(page, margin, y, width, "Tracking device", shipment.LinkedDeviceID.String())
+	}
+
+	// Hand-over signatures
+	y += 48
+	half := width/2 - 12
+	page.Line(margin, y, margin+half, y, 0.75)
+	page.Line(margin+width-half, y, margin+width, y, 0.75)
+	page.Text(margin, y+12, 8, false, "Released by (provider) - name, signature, date")
+	page.Text(margin+width-half, y+12, 8, false, "Received by (shipper) - name, signature, date")
+
+	footer := pdf.A4Height - 40
+	page.SetColor(brand.primary)
+	page.Line(margin, footer, margin+width, footer, 0.75)
+	page.SetColor(pdf.Black)
+	page.Text(margin, footer+14, 8, true, brand.name)
+	if brand.support != "" {
+		page.Text(margin+pdf.TextWidth(brand.name, 8)+16, footer+14, 8, false, brand.support)
+	}
+
+	logger.Info("Shipment manifest generated",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("event", "shipment_manifest_generated"),
+	)
+
+	return doc.Bytes(), nil
+}
+
+// loadDocumentData fetches a shipment for document generation, allowing only
+// the provider and the assigned shipper.
+func (s *Service) loadDocumentData(ctx context.Context, userID, shipmentID uuid.UUID) (*domainShipment.Shipment, *domainShipment.ShippingRules, *documentParties, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	isAuthorized := shipment.ProviderID == userID ||
+		(shipment.ShipperID != nil && *shipment.ShipperID == userID)
+	if !isAuthorized {
+		return nil, nil, nil, appErrors.NewAppError("UNAUTHORIZED", "Only the provider or assigned shipper can download shipment documents", nil)
+	}
+
+	if shipment.Status == domainShipment.StatusDemandCreated || shipment.Status == domainShipment.StatusCancelled {
+		return nil, nil, nil, appErrors.NewAppError("INVALID_STATUS", "Documents are available once the order has been posted", nil)
+	}
+
+	rules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+
+	parties := &documentParties{}
+	parties.customer, _ = s.userRepo.GetByID(ctx, shipment.CustomerID)
+	parties.provider, _ = s.userRepo.GetByID(ctx, shipment.ProviderID)
+	if shipment.ShipperID != nil {
+		parties.shipper, _ = s.userRepo.GetByID(ctx, *shipment.ShipperID)
+	}
+
+	return shipment, rules, parties, nil
+}
+
+// ShipmentQRPayload is the content encoded in label QR codes; scanners resolve
+// it to the shipment.
+func ShipmentQRPayload(shipmentID uuid.UUID) string {
+	return "cargo-tracker:shipment:" + shipmentID.String()
+}
+
+// HandlingInstructions turns quality rules into short human readable
+// instructions such as "Keep 2–8°C".
+func HandlingInstructions(rules *domainShipment.ShippingRules) []string {
+	if rules == nil {
+		return nil
+	}
+
+	var instructions []string
+	switch {
+	case rules.TempMin != nil && rules.TempMax != nil:
+		instructions = append(instructions, fmt.Sprintf("Keep %s–%s°C", formatNumber(*rules.TempMin), formatNumber(*rules.TempMax)))
+	case rules.TempMin != nil:
+		instructions = append(instructions, fmt.Sprintf("Keep above %s°C", formatNumber(*rules.TempMin)))
+	case rules.TempMax != nil:
+		instructions = append(instructions, fmt.Sprintf("Keep below %s°C", formatNumber(*rules.TempMax)))
+	}
+
+	switch {
+	case rules.HumidityMin != nil && rules.HumidityMax != nil:
+		instructions = append(instructions, fmt.Sprintf("Humidity %s–%s%% RH", formatNumber(*rules.HumidityMin), formatNumber(*rules.HumidityMax)))
+	case rules.HumidityMin != nil:
+		instructions = append(instructions, fmt.Sprintf("Humidity above %s%% RH", formatNumber(*rules.HumidityMin)))
+	case rules.HumidityMax != nil:
+		instructions = append(instructions, fmt.Sprintf("Keep dry (below %s%% RH)", formatNumber(*rules.HumidityMax)))
+	}
+
+	if rules.LightMax != nil {
+		instructions = append(instructions, "Protect from light")
+	}
+	if rules.TiltMaxAngle != nil {
+		instructions = append(instructions, fmt.Sprintf("This side up – max tilt %s°", formatNumber(*rules.TiltMaxAngle)))
+	}
+	if rules.ImpactThresholdG != nil {
+		instructions = append(instructions, fmt.Sprintf("Fragile – max impact %s g", formatNumber(*rules.ImpactThresholdG)))
+	}
+
+	return instructions
+}
+
+// Drawing helpers
+
+func drawQR(page *pdf.Page, qr *qrcode.Code, x, y, size float64) {
+	const quietZone = 2
+	module := size / float64(qr.Size+2*quietZone)
+	for row := 0; row < qr.Size; row++ {
+		for col := 0; col < qr.Size; col++ {
+			if qr.Dark(col, row) {
+				page.Rect(x+float64(col+quietZone)*module, y+float64(row+quietZone)*module, module, module, true)
+			}
+		}
+	}
+}
+
+func drawBlock(page *pdf.Page, x, y, width float64, label, name, address string) float64 {
+	page.Text(x, y, 7, true, label)
+	page.Text(x+52, y, 11, true, name)
+	y += 13
+	for _, line := range pdf.Wrap(address, 9, width-52) {
+		page.Text(x+52, y, 9, false, line)
+		y += 11
+	}
+	return y + 6
+}
+
+func drawSection(page *pdf.Page, x, y, width float64, title string, color pdf.Color) float64 {
+	page.Text(x, y, 12, true, title)
+	page.SetColor(color)
+	page.Line(x, y+5, x+width, y+5, 0.75)
+	page.SetColor(pdf.Black)
+	return y + 22
+}
+
+func drawField(page *pdf.Page, x, y, width float64, label, value string) float64 {
+	page.Text(x, y, 9, true, label)
+	for _, line := range pdf.Wrap(value, 10, width-110) {
+		page.Text(x+110, y, 10, false, line)
+		y += 14
+	}
+	return y
+}
+
+func userName(u *domainUser.User) string {
+	if u == nil {
+		return "Unassigned"
+	}
+	return u.FullName
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
+
+func formatOptional(v *float64, unit string) string {
+	if v == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*v, 'f', 2, 64) + unit
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
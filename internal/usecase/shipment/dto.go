@@ -3,22 +3,35 @@ package shipment
 import (
 	"time"
 
+	domainDevice "cargo-tracker/internal/domain/device"
 	domainShipment "cargo-tracker/internal/domain/shipment"
+	usecaseUser "cargo-tracker/internal/usecase/user"
+	"cargo-tracker/pkg/utils"
 
 	"github.com/google/uuid"
 )
 
 // Request DTOs
 type CreateDemandRequest struct {
-	ProviderID          uuid.UUID  `json:"provider_id" validate:"required,uuid"`
-	GoodsDescription    string     `json:"goods_description" validate:"required,min=10,max=1000"`
-	GoodsValue          *float64   `json:"goods_value" validate:"omitempty,min=0"`
-	GoodsWeight         *float64   `json:"goods_weight" validate:"omitempty,min=0"`
-	PickupAddress       string     `json:"pickup_address" validate:"required,min=10"`
-	DeliveryAddress     string     `json:"delivery_address" validate:"required,min=10"`
+	ProviderID       uuid.UUID `json:"provider_id" validate:"required,uuid"`
+	GoodsDescription string    `json:"goods_description" validate:"required,min=10,max=1000"`
+	// Code of a goods category; recognised from the description when empty
+	GoodsCategory *string  `json:"goods_category" validate:"omitempty,ref=goods_category"`
+	GoodsValue    *float64 `json:"goods_value" validate:"omitempty,min=0"`
+	// ISO 4217 code of goods_value, the provider's default currency when empty
+	GoodsCurrency   string   `json:"goods_currency" validate:"omitempty,len=3,alpha"`
+	GoodsWeight     *float64 `json:"goods_weight" validate:"omitempty,min=0"`
+	PickupAddress   string   `json:"pickup_address" validate:"required_without=PickupAddressID,omitempty,min=10"`
+	DeliveryAddress string   `json:"delivery_address" validate:"required_without=DeliveryAddressID,omitempty,min=10"`
+	// Address book entries of the customer, used instead of the free-text addresses
+	PickupAddressID     *uuid.UUID `json:"pickup_address_id" validate:"omitempty"`
+	DeliveryAddressID   *uuid.UUID `json:"delivery_address_id" validate:"omitempty"`
 	EstimatedPickupAt   *time.Time `json:"estimated_pickup_at" validate:"omitempty"`
 	EstimatedDeliveryAt *time.Time `json:"estimated_delivery_at" validate:"omitempty"`
 	CustomerNotes       *string    `json:"customer_notes" validate:"omitempty,max=500"`
+	// Customer the demand is booked for, who must have delegated booking to
+	// the caller; the caller's own account when empty
+	OnBehalfOf *uuid.UUID `json:"on_behalf_of" validate:"omitempty"`
 }
 
 type PostOrderRequest struct {
@@ -56,7 +69,7 @@ type RateDeliveryRequest struct {
 }
 
 type ReportIssueRequest struct {
-	IssueType   string  `json:"issue_type" validate:"required,oneof=quality_violation accident theft delay other"`
+	IssueType   string  `json:"issue_type" validate:"required,ref=issue_type"`
 	Description string  `json:"description" validate:"required,min=10,max=1000"`
 	Severity    string  `json:"severity" validate:"required,oneof=low medium high critical"`
 	Evidence    *string `json:"evidence" validate:"omitempty"`
@@ -71,6 +84,25 @@ type UpdateShipmentRequest struct {
 	CustomerNotes       *string    `json:"customer_notes" validate:"omitempty,max=500"`
 }
 
+type CreatePackageRequest struct {
+	Description string   `json:"description" validate:"required,min=3,max=500"`
+	Weight      *float64 `json:"weight" validate:"omitempty,min=0"`
+}
+
+type UpdatePackageRequest struct {
+	Description *string  `json:"description" validate:"omitempty,min=3,max=500"`
+	Weight      *float64 `json:"weight" validate:"omitempty,min=0"`
+}
+
+type RecordPackageOutcomeRequest struct {
+	Outcome domainShipment.PackageOutcome `json:"outcome" validate:"required,oneof=delivered lost damaged refused"`
+	Note    *string                       `json:"note" validate:"omitempty,max=1000"`
+}
+
+type AssignPackageDeviceRequest struct {
+	DeviceID uuid.UUID `json:"device_id" validate:"required,uuid"`
+}
+
 type CancelShipmentRequest struct {
 	Reason string `json:"reason" validate:"required,min=10,max=500"`
 }
@@ -112,22 +144,29 @@ type ShipmentResponse struct {
 	Customer *PartyInfo `json:"customer"`
 	Provider *PartyInfo `json:"provider"`
 	Shipper  *PartyInfo `json:"shipper,omitempty"`
+	// Delegate who booked the shipment in the customer's name
+	BookedBy *uuid.UUID `json:"booked_by,omitempty"`
 
 	// Device
 	Device *DeviceInfo `json:"device,omitempty"`
 
 	// Goods
 	GoodsDescription string   `json:"goods_description"`
+	GoodsCategory    *string  `json:"goods_category,omitempty"`
 	GoodsValue       *float64 `json:"goods_value"`
+	GoodsCurrency    string   `json:"goods_currency"`
 	GoodsWeight      *float64 `json:"goods_weight"`
 
 	// Addresses
-	PickupAddress   string `json:"pickup_address"`
-	DeliveryAddress string `json:"delivery_address"`
+	PickupAddress     string     `json:"pickup_address"`
+	DeliveryAddress   string     `json:"delivery_address"`
+	PickupAddressID   *uuid.UUID `json:"pickup_address_id,omitempty"`
+	DeliveryAddressID *uuid.UUID `json:"delivery_address_id,omitempty"`
 
 	// Timing
 	EstimatedPickupAt   *time.Time `json:"estimated_pickup_at"`
 	EstimatedDeliveryAt *time.Time `json:"estimated_delivery_at"`
+	DeliveryDueAt       *time.Time `json:"delivery_due_at,omitempty"`
 	ActualPickupAt      *time.Time `json:"actual_pickup_at"`
 	ActualDeliveryAt    *time.Time `json:"actual_delivery_at"`
 	DurationMinutes     *int       `json:"duration_minutes"`
@@ -138,6 +177,13 @@ type ShipmentResponse struct {
 	RulesConfirmed bool `json:"rules_confirmed"`
 	AlertsCount    int  `json:"alerts_count"`
 
+	// Risk
+	RiskScore   *int                        `json:"risk_score,omitempty"`
+	RiskFactors []domainShipment.RiskFactor `json:"risk_factors,omitempty"`
+
+	// Advisories about the shipping rules, only set when rules are posted
+	Warnings []RuleWarning `json:"warnings,omitempty"`
+
 	// Notes
 	CustomerNotes   *string `json:"customer_notes"`
 	CompletionNotes *string `json:"completion_notes"`
@@ -150,9 +196,14 @@ type ShipmentResponse struct {
 
 type ShipmentDetailResponse struct {
 	*ShipmentResponse
-	Rules         *ShippingRulesResponse `json:"rules,omitempty"`
-	StatusHistory []StatusHistory        `json:"status_history"`
-	RecentAlerts  []AlertSummary         `json:"recent_alerts"`
+	Rules         *ShippingRulesResponse        `json:"rules,omitempty"`
+	StatusHistory []StatusHistory               `json:"status_history"`
+	RecentAlerts  []AlertSummary                `json:"recent_alerts"`
+	Packages      []PackageResponse             `json:"packages"`
+	Branding      *usecaseUser.BrandingResponse `json:"branding,omitempty"`
+
+	// Signed acceptance of the provider's terms of carriage
+	TermsAcceptance *TermsAcceptanceResponse `json:"terms_acceptance,omitempty"`
 }
 
 type StatusHistory struct {
@@ -169,6 +220,38 @@ type AlertSummary struct {
 	Severity      string    `json:"severity"`
 	ViolationType string    `json:"violation_type"`
 	Message       string    `json:"message"`
+	// Set when the alert came from a package tracker rather than the shipment-level device
+	PackageID *uuid.UUID `json:"package_id,omitempty"`
+}
+
+type PackageResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	ShipmentID  uuid.UUID  `json:"shipment_id"`
+	Sequence    int        `json:"sequence"`
+	Description string     `json:"description"`
+	Weight      *float64   `json:"weight"`
+	DeviceID    *uuid.UUID `json:"device_id"`
+
+	Outcome     domainShipment.PackageOutcome `json:"outcome"`
+	OutcomeNote *string                       `json:"outcome_note,omitempty"`
+	OutcomeAt   *time.Time                    `json:"outcome_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PackageListResponse struct {
+	ShipmentID     uuid.UUID         `json:"shipment_id"`
+	Packages       []PackageResponse `json:"packages"`
+	TotalWeight    float64           `json:"total_weight"`
+	TrackedCount   int               `json:"tracked_count"`
+	UntrackedCount int               `json:"untracked_count"`
+
+	// Delivery outcome aggregation
+	PendingCount   int            `json:"pending_count"`
+	DeliveredCount int            `json:"delivered_count"`
+	ExceptionCount int            `json:"exception_count"`
+	Outcomes       map[string]int `json:"outcomes"`
 }
 
 type ShipmentListResponse struct {
@@ -184,6 +267,7 @@ type MarketplaceListingResponse struct {
 	Provider            *PartyInfo `json:"provider"`
 	GoodsDescription    string     `json:"goods_description"`
 	GoodsValue          *float64   `json:"goods_value"`
+	GoodsCurrency       string     `json:"goods_currency"`
 	GoodsWeight         *float64   `json:"goods_weight"`
 	PickupAddress       string     `json:"pickup_address"`
 	DeliveryAddress     string     `json:"delivery_address"`
@@ -226,6 +310,8 @@ type ShippingRulesResponse struct {
 	ConfirmedByShipperID  *uuid.UUID `json:"confirmed_by_shipper_id"`
 	SetAt                 time.Time  `json:"set_at"`
 	ConfirmedAt           *time.Time `json:"confirmed_at"`
+	// What a device must measure to monitor these rules
+	RequiredCapabilities []domainDevice.Capability `json:"required_capabilities"`
 }
 
 type ShipmentStatisticsResponse struct {
@@ -238,6 +324,14 @@ type ShipmentStatisticsResponse struct {
 	IssueRate           float64           `json:"issue_rate"`
 	TopShippers         []TopShipperStats `json:"top_shippers"`
 	RevenueToday        float64           `json:"revenue_today"`
+	// Currency revenue_today is reported in
+	RevenueCurrency        string             `json:"revenue_currency"`
+	RevenueTodayByCurrency map[string]float64 `json:"revenue_today_by_currency"`
+	UnconvertedToday       int                `json:"unconverted_today,omitempty"`
+
+	PartialCompletionRate float64        `json:"partial_completion_rate"`
+	PackageOutcomes       map[string]int `json:"package_outcomes"`
+	PackageExceptionRate  float64        `json:"package_exception_rate"`
 }
 
 type TopShipperStats struct {
@@ -257,13 +351,19 @@ func ToShipmentResponse(s *domainShipment.Shipment, rules *domainShipment.Shippi
 	resp := &ShipmentResponse{
 		ID:                  s.ID,
 		Status:              s.Status,
+		BookedBy:            s.BookedBy,
 		GoodsDescription:    s.GoodsDescription,
+		GoodsCategory:       s.GoodsCategory,
 		GoodsValue:          s.GoodsValue,
+		GoodsCurrency:       s.GoodsCurrency,
 		GoodsWeight:         s.GoodsWeight,
 		PickupAddress:       s.PickupAddress,
 		DeliveryAddress:     s.DeliveryAddress,
+		PickupAddressID:     s.PickupAddressID,
+		DeliveryAddressID:   s.DeliveryAddressID,
 		EstimatedPickupAt:   s.EstimatedPickupAt,
 		EstimatedDeliveryAt: s.EstimatedDeliveryAt,
+		DeliveryDueAt:       s.DeliveryDueAt,
 		ActualPickupAt:      s.ActualPickupAt,
 		ActualDeliveryAt:    s.ActualDeliveryAt,
 		CustomerNotes:       s.CustomerNotes,
@@ -275,19 +375,29 @@ func ToShipmentResponse(s *domainShipment.Shipment, rules *domainShipment.Shippi
 		RulesConfirmed:      rules != nil && rules.ConfirmedByShipperID != nil,
 	}
 
+	if s.Risk != nil {
+		score := s.Risk.Score
+		resp.RiskScore = &score
+		resp.RiskFactors = s.Risk.Factors
+	}
+
 	// Calculate duration if both pickup and delivery are set
 	if s.ActualPickupAt != nil && s.ActualDeliveryAt != nil {
 		duration := int(s.ActualDeliveryAt.Sub(*s.ActualPickupAt).Minutes())
 		resp.DurationMinutes = &duration
 	}
 
-	// Check if delayed
-	if s.EstimatedDeliveryAt != nil {
+	// Check if delayed, against the deadline in the provider's working hours
+	dueAt := s.DeliveryDueAt
+	if dueAt == nil {
+		dueAt = s.EstimatedDeliveryAt
+	}
+	if dueAt != nil {
 		if s.ActualDeliveryAt != nil {
-			resp.IsDelayed = s.ActualDeliveryAt.After(*s.EstimatedDeliveryAt)
+			resp.IsDelayed = s.ActualDeliveryAt.After(*dueAt)
 		} else if s.Status == domainShipment.StatusInTransit {
 			now := time.Now()
-			resp.IsDelayed = now.After(*s.EstimatedDeliveryAt)
+			resp.IsDelayed = now.After(*dueAt)
 		}
 	}
 
@@ -311,7 +421,7 @@ func ToDomainFilter(req *ShipmentFilterRequest) *domainShipment.Filter {
 		HasIssues:      req.HasIssues,
 		IsDelayed:      req.IsDelayed,
 		HasDevice:      req.HasDevice,
-		Search:         req.Search,
+		Search:         utils.NormalizeSearch(req.Search),
 		Page:           req.Page,
 		PageSize:       req.PageSize,
 		SortBy:         req.SortBy,
@@ -343,5 +453,32 @@ func ToStatisticsResponse(s *domainShipment.Statistics) *ShipmentStatisticsRespo
 		IssueRate:           s.IssueRate,
 		TopShippers:         topShippers,
 		RevenueToday:        s.RevenueToday,
+
+		RevenueTodayByCurrency: s.RevenueTodayByCurrency,
+		UnconvertedToday:       s.UnconvertedToday,
+
+		PartialCompletionRate: s.PartialCompletionRate,
+		PackageOutcomes:       s.PackageOutcomes,
+		PackageExceptionRate:  s.PackageExceptionRate,
 	}
 }
+
+func ToPackageResponses(packages []*domainShipment.Package) []PackageResponse {
+	resp := make([]PackageResponse, len(packages))
+	for i, p := range packages {
+		resp[i] = PackageResponse{
+			ID:          p.ID,
+			ShipmentID:  p.ShipmentID,
+			Sequence:    p.Sequence,
+			Description: p.Description,
+			Weight:      p.Weight,
+			DeviceID:    p.DeviceID,
+			Outcome:     p.Outcome,
+			OutcomeNote: p.OutcomeNote,
+			OutcomeAt:   p.OutcomeAt,
+			CreatedAt:   p.CreatedAt,
+			UpdatedAt:   p.UpdatedAt,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,38 @@
+package shipment
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BenchmarkToDomainFilter converts a fully populated list query, as every
+// shipment list request does
+func BenchmarkToDomainFilter(b *testing.B) {
+	status := domainShipment.StatusInTransit
+	customerID := uuid.New()
+	providerID := uuid.New()
+	createdAfter := time.Now().Add(-30 * 24 * time.Hour)
+	createdBefore := time.Now()
+	delayed := true
+	req := &ShipmentFilterRequest{
+		Status:        &status,
+		CustomerID:    &customerID,
+		ProviderID:    &providerID,
+		CreatedAfter:  &createdAfter,
+		CreatedBefore: &createdBefore,
+		IsDelayed:     &delayed,
+		Search:        "Hồ Chí Minh, Quận 1",
+		Page:          2,
+		PageSize:      50,
+		SortBy:        "created_at",
+		SortOrder:     "desc",
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = ToDomainFilter(req)
+	}
+}
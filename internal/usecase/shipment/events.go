@@ -0,0 +1,207 @@
+package shipment
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ShipmentEventResponse struct {
+	ID         int64                    `json:"id"`
+	Type       domainShipment.EventType `json:"type"`
+	ActorID    *uuid.UUID               `json:"actor_id"`
+	Data       domainShipment.EventData `json:"data"`
+	OccurredAt time.Time                `json:"occurred_at"`
+	// Set on TimelineNote entries, which are not part of the event log
+	Note *NoteResponse `json:"note,omitempty"`
+}
+
+// ShipmentStateResponse is a shipment as it stood at a point in time, rebuilt
+// from its event log
+type ShipmentStateResponse struct {
+	ShipmentID uuid.UUID `json:"shipment_id"`
+	At         time.Time `json:"at"`
+	// Version is the number of events that had happened by At
+	Version int `json:"version"`
+	// LastEventAt is when the newest of those events happened
+	LastEventAt *time.Time `json:"last_event_at"`
+
+	Status           domainShipment.ShipmentStatus `json:"status"`
+	CustomerID       uuid.UUID                     `json:"customer_id"`
+	ProviderID       uuid.UUID                     `json:"provider_id"`
+	BookedBy         *uuid.UUID                    `json:"booked_by,omitempty"`
+	ShipperID        *uuid.UUID                    `json:"shipper_id"`
+	LinkedDeviceID   *uuid.UUID                    `json:"linked_device_id"`
+	GoodsDescription string                        `json:"goods_description"`
+	PickupAddress    string                        `json:"pickup_address"`
+	DeliveryAddress  string                        `json:"delivery_address"`
+
+	EstimatedPickupAt   *time.Time `json:"estimated_pickup_at"`
+	EstimatedDeliveryAt *time.Time `json:"estimated_delivery_at"`
+	ActualPickupAt      *time.Time `json:"actual_pickup_at"`
+	ActualDeliveryAt    *time.Time `json:"actual_delivery_at"`
+
+	Rules          *ShippingRulesResponse `json:"rules,omitempty"`
+	RulesConfirmed bool                   `json:"rules_confirmed"`
+
+	CompletionNotes *string `json:"completion_notes"`
+	CustomerRating  *int    `json:"customer_rating"`
+}
+
+func ToShipmentEventResponse(e *domainShipment.Event) ShipmentEventResponse {
+	return ShipmentEventResponse{
+		ID:         e.ID,
+		Type:       e.Type,
+		ActorID:    e.ActorID,
+		Data:       e.Data,
+		OccurredAt: e.OccurredAt,
+	}
+}
+
+func ToShipmentStateResponse(snap *domainShipment.Snapshot, at time.Time) *ShipmentStateResponse {
+	resp := &ShipmentStateResponse{
+		ShipmentID:          snap.ShipmentID,
+		At:                  at,
+		Version:             snap.Version,
+		Status:              snap.Status,
+		CustomerID:          snap.CustomerID,
+		ProviderID:          snap.ProviderID,
+		BookedBy:            snap.BookedBy,
+		ShipperID:           snap.ShipperID,
+		LinkedDeviceID:      snap.DeviceID,
+		GoodsDescription:    snap.GoodsDescription,
+		PickupAddress:       snap.PickupAddress,
+		DeliveryAddress:     snap.DeliveryAddress,
+		EstimatedPickupAt:   snap.EstimatedPickupAt,
+		EstimatedDeliveryAt: snap.EstimatedDeliveryAt,
+		ActualPickupAt:      snap.ActualPickupAt,
+		ActualDeliveryAt:    snap.ActualDeliveryAt,
+		Rules:               toShippingRulesResponse(snap.Rules),
+		RulesConfirmed:      snap.RulesConfirmed,
+		CompletionNotes:     snap.CompletionNotes,
+		CustomerRating:      snap.CustomerRating,
+	}
+	if snap.Version > 0 {
+		asOf := snap.AsOf
+		resp.LastEventAt = &asOf
+	}
+	return resp
+}
+
+// UseEventLog makes the service append every lifecycle change of a shipment
+// to events. Without it no events are written and the event endpoints report
+// the log as disabled.
+func (s *Service) UseEventLog(events domainShipment.EventRepository) {
+	s.events = events
+}
+
+// recordEvent appends an event for a change that has already been saved. The
+// shipment row stays the source of truth, so a failed append is logged and
+// never fails the change itself.
+func (s *Service) recordEvent(ctx context.Context, shipmentID uuid.UUID, eventType domainShipment.EventType, actorID *uuid.UUID, data domainShipment.EventData) {
+	if s.events == nil {
+		return
+	}
+
+	event := &domainShipment.Event{
+		ShipmentID: shipmentID,
+		Type:       eventType,
+		ActorID:    actorID,
+		Data:       data,
+		OccurredAt: time.Now(),
+	}
+	if err := s.events.Append(ctx, event); err != nil {
+		logger.Error("Failed to record shipment event",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.String("type", string(eventType)),
+			zap.Error(err),
+		)
+	}
+}
+
+// ListEvents returns a shipment's event timeline, oldest first, with the
+// notes the user can read interleaved
+func (s *Service) ListEvents(ctx context.Context, userID, shipmentID uuid.UUID) ([]ShipmentEventResponse, error) {
+	events, err := s.viewableEvents(ctx, userID, shipmentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]ShipmentEventResponse, len(events))
+	for i, e := range events {
+		responses[i] = ToShipmentEventResponse(e)
+	}
+	responses = append(responses, s.timelineNotes(ctx, userID, shipmentID)...)
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].OccurredAt.Before(responses[j].OccurredAt)
+	})
+	return responses, nil
+}
+
+// GetStateAt replays a shipment's events up to at to show how it stood then
+func (s *Service) GetStateAt(ctx context.Context, userID, shipmentID uuid.UUID, at time.Time) (*ShipmentStateResponse, error) {
+	events, err := s.viewableEvents(ctx, userID, shipmentID, &at)
+	if err != nil {
+		return nil, err
+	}
+	return ToShipmentStateResponse(domainShipment.Replay(shipmentID, events), at), nil
+}
+
+func (s *Service) viewableEvents(ctx context.Context, userID, shipmentID uuid.UUID, until *time.Time) ([]*domainShipment.Event, error) {
+	if s.events == nil {
+		return nil, domainShipment.ErrEventLogDisabled
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeViewer(ctx, shipment, userID, domainShipment.ScopeDetails); err != nil {
+		return nil, err
+	}
+
+	return s.events.List(ctx, shipmentID, until)
+}
+
+// statusHistory derives the status changes of a shipment from its events.
+// It is empty while the event log is disabled.
+func (s *Service) statusHistory(ctx context.Context, shipmentID uuid.UUID) []StatusHistory {
+	history := []StatusHistory{}
+	if s.events == nil {
+		return history
+	}
+
+	events, err := s.events.List(ctx, shipmentID, nil)
+	if err != nil {
+		logger.Warn("Failed to load shipment events",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.Error(err),
+		)
+		return history
+	}
+
+	var current *domainShipment.ShipmentStatus
+	for _, e := range events {
+		if e.Data.Status == nil || (current != nil && *current == *e.Data.Status) {
+			continue
+		}
+		history = append(history, StatusHistory{
+			FromStatus: current,
+			ToStatus:   *e.Data.Status,
+			ChangedBy:  e.ActorID,
+			ChangedAt:  e.OccurredAt,
+			Notes:      e.Data.Reason,
+		})
+		current = e.Data.Status
+	}
+	return history
+}
+
+func statusPtr(status domainShipment.ShipmentStatus) *domainShipment.ShipmentStatus {
+	return &status
+}
@@ -0,0 +1,204 @@
+package shipment
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// DefaultLanePeriod is aggregated when the query leaves out from
+const DefaultLanePeriod = 180 * 24 * time.Hour
+
+// minLaneSample is how many timed deliveries a lane needs before transit
+// percentiles are reported; fewer would describe single shipments
+const minLaneSample = 5
+
+type LaneStatisticsQuery struct {
+	PickupCity   string     `form:"pickup_city" validate:"required,max=100"`
+	DeliveryCity string     `form:"delivery_city" validate:"required,max=100"`
+	From         *time.Time `form:"from"`
+	To           *time.Time `form:"to"`
+}
+
+// LanePeriodQuery is the period of the lane of a shipment to aggregate
+type LanePeriodQuery struct {
+	From *time.Time `form:"from"`
+	To   *time.Time `form:"to"`
+}
+
+type LaneStatisticsResponse struct {
+	PickupCity   string    `json:"pickup_city"`
+	DeliveryCity string    `json:"delivery_city"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+
+	Shipments int `json:"shipments"`
+	Delivered int `json:"delivered"`
+	Cancelled int `json:"cancelled"`
+
+	// Percentages of delivered shipments; nil until one was delivered
+	OnTimeRate *float64 `json:"on_time_rate"`
+	// Deliveries that arrived with a damaged, missing or refused package
+	ViolationRate *float64 `json:"violation_rate"`
+	// Percentage of accepted shipments that were cancelled
+	CancellationRate float64 `json:"cancellation_rate"`
+
+	// Nil while the lane has too few timed deliveries
+	TransitTime *TransitTimeStats `json:"transit_time"`
+}
+
+// TransitTimeStats describes the pickup to delivery times of a lane, in
+// minutes
+type TransitTimeStats struct {
+	SampleSize int     `json:"sample_size"`
+	Average    float64 `json:"average_minutes"`
+	Median     float64 `json:"median_minutes"`
+	P75        float64 `json:"p75_minutes"`
+	P90        float64 `json:"p90_minutes"`
+	P95        float64 `json:"p95_minutes"`
+}
+
+func ToLaneStatisticsResponse(l *domainShipment.LaneStatistics) *LaneStatisticsResponse {
+	resp := &LaneStatisticsResponse{
+		Shipments: l.Shipments,
+		Delivered: l.Delivered,
+		Cancelled: l.Cancelled,
+	}
+	if l.Delivered > 0 {
+		onTime := float64(l.OnTime) / float64(l.Delivered) * 100
+		violations := float64(l.Partial) / float64(l.Delivered) * 100
+		resp.OnTimeRate = &onTime
+		resp.ViolationRate = &violations
+	}
+	if l.Shipments > 0 {
+		resp.CancellationRate = float64(l.Cancelled) / float64(l.Shipments) * 100
+	}
+	if l.Timed >= minLaneSample && l.TransitP50 != nil {
+		resp.TransitTime = &TransitTimeStats{
+			SampleSize: l.Timed,
+			Average:    *l.TransitAvg,
+			Median:     *l.TransitP50,
+			P75:        *l.TransitP75,
+			P90:        *l.TransitP90,
+			P95:        *l.TransitP95,
+		}
+	}
+	return resp
+}
+
+// GetLaneStatistics aggregates the past shipments between two cities
+func (s *Service) GetLaneStatistics(ctx context.Context, userID uuid.UUID, req *LaneStatisticsQuery) (*LaneStatisticsResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid query", err)
+	}
+
+	from, to, err := lanePeriod(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.laneStatistics(ctx, req.PickupCity, req.DeliveryCity, user.IsSandbox, from, to)
+}
+
+// GetShipmentLaneStatistics aggregates the past shipments on the lane of a
+// shipment, so a shipper can judge a posted order before accepting it. The
+// cities come from the saved addresses the shipment was created from, else
+// from its address text.
+func (s *Service) GetShipmentLaneStatistics(ctx context.Context, shipmentID uuid.UUID, req *LanePeriodQuery) (*LaneStatisticsResponse, error) {
+	from, to, err := lanePeriod(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	pickup := s.laneCity(ctx, shipment.CustomerID, shipment.PickupAddressID, shipment.PickupAddress)
+	delivery := s.laneCity(ctx, shipment.CustomerID, shipment.DeliveryAddressID, shipment.DeliveryAddress)
+	if pickup == "" || delivery == "" {
+		return nil, appErrors.NewAppError("UNKNOWN_LANE", "The cities of this shipment cannot be determined", nil)
+	}
+
+	return s.laneStatistics(ctx, pickup, delivery, shipment.IsSandbox, from, to)
+}
+
+func (s *Service) laneStatistics(ctx context.Context, pickupCity, deliveryCity string, sandbox bool, from, to time.Time) (*LaneStatisticsResponse, error) {
+	stats, err := s.shipmentRepo.GetLaneStatistics(ctx, &domainShipment.LaneQuery{
+		PickupArea:   utils.NormalizeSearch(pickupCity),
+		DeliveryArea: utils.NormalizeSearch(deliveryCity),
+		Sandbox:      sandbox,
+		From:         from,
+		To:           to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := ToLaneStatisticsResponse(stats)
+	resp.PickupCity = pickupCity
+	resp.DeliveryCity = deliveryCity
+	resp.From = from
+	resp.To = to
+	return resp, nil
+}
+
+// lanePeriod resolves the queried period, by default the last
+// DefaultLanePeriod
+func lanePeriod(fromQuery, toQuery *time.Time) (time.Time, time.Time, error) {
+	to := time.Now()
+	if toQuery != nil {
+		to = *toQuery
+	}
+	from := to.Add(-DefaultLanePeriod)
+	if fromQuery != nil {
+		from = *fromQuery
+	}
+	if !from.Before(to) {
+		return from, to, appErrors.NewAppError("INVALID_PERIOD", "from must be before to", nil)
+	}
+	if to.Sub(from) > MaxComparisonPeriod {
+		return from, to, appErrors.NewAppError("INVALID_PERIOD", "Period cannot be longer than 366 days", nil)
+	}
+	return from, to, nil
+}
+
+// laneCity returns the city of a shipment address: the city of the saved
+// address when there is one, else the last part of the address text that is
+// not a postal or country code
+func (s *Service) laneCity(ctx context.Context, customerID uuid.UUID, addressID *uuid.UUID, address string) string {
+	if addressID != nil {
+		if saved, err := s.addressBook.ResolveAddress(ctx, customerID, *addressID); err == nil {
+			return saved.City
+		}
+	}
+
+	parts := strings.Split(address, ",")
+	for i := len(parts) - 1; i >= 0; i-- {
+		part := strings.TrimSpace(parts[i])
+		if part == "" || isCountryCode(part) || !strings.ContainsFunc(part, unicode.IsLetter) {
+			continue
+		}
+		// A single part is the whole street address, not a city
+		if i == 0 {
+			return ""
+		}
+		return part
+	}
+	return ""
+}
+
+func isCountryCode(part string) bool {
+	return len(part) == 2 && strings.ToUpper(part) == part && !strings.ContainsFunc(part, unicode.IsDigit)
+}
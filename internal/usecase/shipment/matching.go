@@ -0,0 +1,235 @@
+package shipment
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Match factor names
+const (
+	MatchFactorReputation = "reputation"
+	MatchFactorCapacity   = "capacity"
+	MatchFactorDevices    = "devices"
+)
+
+// Relative weights of the match factors. Proximity to the pickup is not
+// scored until addresses are geocoded.
+const (
+	matchWeightReputation = 0.4
+	matchWeightCapacity   = 0.3
+	matchWeightDevices    = 0.3
+)
+
+// Each shipment a shipper is already carrying takes this much off capacity
+const capacityPerActiveShipment = 20
+
+const (
+	defaultSuggestionLimit = 5
+	maxSuggestionLimit     = 20
+)
+
+type SuggestedShippersQuery struct {
+	Limit int `form:"limit" validate:"omitempty,min=1,max=20"`
+}
+
+type SuggestedShippersResponse struct {
+	ShipmentID uuid.UUID          `json:"shipment_id"`
+	Shippers   []SuggestedShipper `json:"shippers"`
+	// Shippers told about the order; only set when notifying
+	Notified int `json:"notified,omitempty"`
+}
+
+type SuggestedShipper struct {
+	ShipperID   uuid.UUID     `json:"shipper_id"`
+	ShipperName string        `json:"shipper_name"`
+	Score       int           `json:"score"`
+	Factors     []MatchFactor `json:"factors"`
+}
+
+// MatchFactor is one scored aspect of a suggestion, 0–100 with higher
+// being a better match
+type MatchFactor struct {
+	Name   string  `json:"name"`
+	Score  int     `json:"score"`
+	Weight float64 `json:"weight"`
+	Detail string  `json:"detail"`
+}
+
+// SuggestShippers ranks the shippers that could take a posted order, best
+// match first
+func (s *Service) SuggestShippers(ctx context.Context, providerID, shipmentID uuid.UUID, query *SuggestedShippersQuery) (*SuggestedShippersResponse, error) {
+	if err := utils.ValidateStruct(query); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid query", err)
+	}
+
+	shipment, err := s.getPostedShipment(ctx, providerID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.suggestShippers(ctx, shipment, query.Limit)
+}
+
+// NotifySuggestedShippers tells the top suggested shippers about a posted
+// order
+func (s *Service) NotifySuggestedShippers(ctx context.Context, providerID, shipmentID uuid.UUID, query *SuggestedShippersQuery) (*SuggestedShippersResponse, error) {
+	if err := utils.ValidateStruct(query); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid query", err)
+	}
+
+	shipment, err := s.getPostedShipment(ctx, providerID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.suggestShippers(ctx, shipment, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if s.notifier == nil {
+		return resp, nil
+	}
+
+	msg := domainNotification.Message{
+		Event:    "shipment_suggested",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "A new order matches your fleet",
+		Body: fmt.Sprintf("\"%s\" from %s to %s is open for acceptance.",
+			shipment.GoodsDescription, shipment.PickupAddress, shipment.DeliveryAddress),
+		Link: "/shipments/" + shipment.ID.String(),
+		Data: map[string]string{
+			"shipment_id":      shipment.ID.String(),
+			"pickup_address":   shipment.PickupAddress,
+			"delivery_address": shipment.DeliveryAddress,
+		},
+		Sandbox: shipment.IsSandbox,
+	}
+	for _, shipper := range resp.Shippers {
+		s.notifyUser(ctx, shipper.ShipperID, msg)
+	}
+	resp.Notified = len(resp.Shippers)
+
+	logger.Info("Suggested shippers notified",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("provider_id", providerID.String()),
+		zap.Int("shippers", resp.Notified),
+		zap.String("event", "suggested_shippers_notified"),
+	)
+
+	return resp, nil
+}
+
+// getPostedShipment loads a shipment of the provider that is waiting for a
+// shipper
+func (s *Service) getPostedShipment(ctx context.Context, providerID, shipmentID uuid.UUID) (*domainShipment.Shipment, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.ProviderID != providerID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Provider does not own this shipment", nil)
+	}
+	if shipment.Status != domainShipment.StatusOrderPosted {
+		return nil, appErrors.NewAppError("INVALID_STATUS", "Shippers are only suggested for posted orders", nil)
+	}
+	return shipment, nil
+}
+
+func (s *Service) suggestShippers(ctx context.Context, shipment *domainShipment.Shipment, limit int) (*SuggestedShippersResponse, error) {
+	if limit <= 0 {
+		limit = defaultSuggestionLimit
+	}
+	if limit > maxSuggestionLimit {
+		limit = maxSuggestionLimit
+	}
+
+	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, shipment.ID)
+	if err != nil {
+		return nil, err
+	}
+	required := RequiredCapabilities(rules)
+	capabilities := make([]string, len(required))
+	for i, c := range required {
+		capabilities[i] = string(c)
+	}
+
+	candidates, err := s.shipmentRepo.ListShipperCandidates(ctx, &domainShipment.MatchQuery{
+		Sandbox:      shipment.IsSandbox,
+		Capabilities: capabilities,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]SuggestedShipper, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = scoreCandidate(c, len(required) > 0)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return &SuggestedShippersResponse{
+		ShipmentID: shipment.ID,
+		Shippers:   suggestions,
+	}, nil
+}
+
+// scoreCandidate combines the weighted match factors of a shipper
+func scoreCandidate(c *domainShipment.ShipperCandidate, needsSensors bool) SuggestedShipper {
+	risk, detail := reputationRisk(&c.Record)
+	factors := []MatchFactor{
+		{Name: MatchFactorReputation, Score: clampScore(100 - risk), Weight: matchWeightReputation, Detail: detail},
+		{
+			Name:   MatchFactorCapacity,
+			Score:  clampScore(100 - c.ActiveShipments*capacityPerActiveShipment),
+			Weight: matchWeightCapacity,
+			Detail: fmt.Sprintf("%d shipments in progress", c.ActiveShipments),
+		},
+		deviceMatch(c, needsSensors),
+	}
+
+	var total float64
+	for _, f := range factors {
+		total += float64(f.Score) * f.Weight
+	}
+
+	return SuggestedShipper{
+		ShipperID:   c.ShipperID,
+		ShipperName: c.ShipperName,
+		Score:       clampScore(int(math.Round(total))),
+		Factors:     factors,
+	}
+}
+
+// deviceMatch scores whether the shipper has an available device that can
+// monitor the rules. Devices without a model profile might, so they count
+// for half.
+func deviceMatch(c *domainShipment.ShipperCandidate, needsSensors bool) MatchFactor {
+	factor := MatchFactor{Name: MatchFactorDevices, Weight: matchWeightDevices}
+	switch {
+	case !needsSensors:
+		factor.Score = 100
+		factor.Detail = fmt.Sprintf("%d available devices; the rules monitor no sensor", c.AvailableDevices)
+	case c.CapableDevices > 0:
+		factor.Score = 100
+		factor.Detail = fmt.Sprintf("%d available devices can monitor the rules", c.CapableDevices)
+	case c.UnprofiledDevices > 0:
+		factor.Score = 50
+		factor.Detail = fmt.Sprintf("%d available devices have no sensor profile", c.UnprofiledDevices)
+	default:
+		factor.Detail = "no available device can monitor the rules"
+	}
+	return factor
+}
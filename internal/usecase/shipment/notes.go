@@ -0,0 +1,332 @@
+package shipment
+
+import (
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TimelineNote is the type of the timeline entries that carry a note rather
+// than a logged event
+const TimelineNote domainShipment.EventType = "note"
+
+type CreateNoteRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=5000"`
+	// Defaults to parties; only admins can write internal notes
+	Visibility string `json:"visibility" validate:"omitempty,oneof=internal parties"`
+}
+
+type UpdateNoteRequest struct {
+	Body       *string `json:"body" validate:"omitempty,min=1,max=5000"`
+	Visibility *string `json:"visibility" validate:"omitempty,oneof=internal parties"`
+}
+
+type NoteResponse struct {
+	ID         uuid.UUID                     `json:"id"`
+	ShipmentID uuid.UUID                     `json:"shipment_id"`
+	AuthorID   uuid.UUID                     `json:"author_id"`
+	Visibility domainShipment.NoteVisibility `json:"visibility"`
+	Body       string                        `json:"body"`
+	Edited     bool                          `json:"edited"`
+	CreatedAt  time.Time                     `json:"created_at"`
+	UpdatedAt  time.Time                     `json:"updated_at"`
+	// Earlier versions, oldest first; only returned for a single note
+	History []NoteRevisionResponse `json:"history,omitempty"`
+}
+
+type NoteRevisionResponse struct {
+	Body       string                        `json:"body"`
+	Visibility domainShipment.NoteVisibility `json:"visibility"`
+	EditedBy   uuid.UUID                     `json:"edited_by"`
+	EditedAt   time.Time                     `json:"edited_at"`
+}
+
+func ToNoteResponse(n *domainShipment.Note) *NoteResponse {
+	resp := &NoteResponse{
+		ID:         n.ID,
+		ShipmentID: n.ShipmentID,
+		AuthorID:   n.AuthorID,
+		Visibility: n.Visibility,
+		Body:       n.Body,
+		Edited:     n.IsEdited(),
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+	}
+	for _, rev := range n.Revisions {
+		resp.History = append(resp.History, NoteRevisionResponse(rev))
+	}
+	return resp
+}
+
+// CreateNote attaches a note to a shipment. The shipment's parties and the
+// customer's delegates can write notes for everyone on it to read; admins can
+// also write internal ones.
+func (s *Service) CreateNote(ctx context.Context, userID, shipmentID uuid.UUID, req *CreateNoteRequest) (*NoteResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	isAdmin, err := s.authorizeNoteWriter(ctx, shipment, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	visibility := domainShipment.NoteParties
+	if req.Visibility != "" {
+		visibility = domainShipment.NoteVisibility(req.Visibility)
+	}
+	if visibility == domainShipment.NoteInternal && !isAdmin {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only admins can write internal notes", nil)
+	}
+
+	note := &domainShipment.Note{
+		ShipmentID: shipmentID,
+		AuthorID:   userID,
+		Visibility: visibility,
+		Body:       req.Body,
+	}
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Shipment note added",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("note_id", note.ID.String()),
+		zap.String("author_id", userID.String()),
+		zap.String("visibility", string(visibility)),
+		zap.String("event", "shipment_note_added"),
+	)
+
+	return ToNoteResponse(note), nil
+}
+
+// ListNotes returns the notes of a shipment the user can read, oldest first
+func (s *Service) ListNotes(ctx context.Context, userID, shipmentID uuid.UUID) ([]*NoteResponse, error) {
+	visibilities, err := s.readableNotes(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.ListByShipment(ctx, shipmentID, visibilities)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*NoteResponse, len(notes))
+	for i, n := range notes {
+		responses[i] = ToNoteResponse(n)
+	}
+	return responses, nil
+}
+
+// GetNote returns a note with its edit history
+func (s *Service) GetNote(ctx context.Context, userID, shipmentID, noteID uuid.UUID) (*NoteResponse, error) {
+	visibilities, err := s.readableNotes(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.getShipmentNote(ctx, shipmentID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsVisibility(visibilities, note.Visibility) {
+		return nil, domainShipment.ErrNoteNotFound
+	}
+	return ToNoteResponse(note), nil
+}
+
+// UpdateNote lets the author change a note. The version it replaces is kept
+// in the note's history.
+func (s *Service) UpdateNote(ctx context.Context, userID, shipmentID, noteID uuid.UUID, req *UpdateNoteRequest) (*NoteResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	isAdmin, err := s.authorizeNoteWriter(ctx, shipment, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.getShipmentNote(ctx, shipmentID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.AuthorID != userID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only the author can edit a note", nil)
+	}
+
+	now := time.Now()
+	previous := domainShipment.NoteRevision{
+		Body:       note.Body,
+		Visibility: note.Visibility,
+		EditedBy:   userID,
+		EditedAt:   now,
+	}
+	if req.Body != nil {
+		note.Body = *req.Body
+	}
+	if req.Visibility != nil {
+		note.Visibility = domainShipment.NoteVisibility(*req.Visibility)
+	}
+	if note.Visibility == domainShipment.NoteInternal && !isAdmin {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only admins can write internal notes", nil)
+	}
+	if note.Body == previous.Body && note.Visibility == previous.Visibility {
+		return ToNoteResponse(note), nil
+	}
+	note.UpdatedAt = now
+
+	if err := s.noteRepo.Update(ctx, note, previous); err != nil {
+		return nil, err
+	}
+	note.Revisions = append(note.Revisions, previous)
+
+	logger.Info("Shipment note edited",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("note_id", noteID.String()),
+		zap.String("edited_by", userID.String()),
+		zap.String("event", "shipment_note_edited"),
+	)
+
+	return ToNoteResponse(note), nil
+}
+
+// DeleteNote removes a note and its history. Authors can delete their own
+// notes and admins any note.
+func (s *Service) DeleteNote(ctx context.Context, userID, shipmentID, noteID uuid.UUID) error {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return err
+	}
+	isAdmin, err := s.authorizeNoteWriter(ctx, shipment, userID)
+	if err != nil {
+		return err
+	}
+
+	note, err := s.getShipmentNote(ctx, shipmentID, noteID)
+	if err != nil {
+		return err
+	}
+	if note.AuthorID != userID && !isAdmin {
+		return appErrors.NewAppError("UNAUTHORIZED", "Only the author can delete a note", nil)
+	}
+
+	if err := s.noteRepo.Delete(ctx, noteID); err != nil {
+		return err
+	}
+
+	logger.Info("Shipment note deleted",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("note_id", noteID.String()),
+		zap.String("author_id", note.AuthorID.String()),
+		zap.String("deleted_by", userID.String()),
+		zap.String("event", "shipment_note_deleted"),
+	)
+
+	return nil
+}
+
+// timelineNotes returns the notes the user can read as timeline entries.
+// The timeline still loads when they cannot be fetched.
+func (s *Service) timelineNotes(ctx context.Context, userID, shipmentID uuid.UUID) []ShipmentEventResponse {
+	visibilities := []domainShipment.NoteVisibility{domainShipment.NoteParties}
+	if s.isStaff(ctx, userID) {
+		visibilities = append(visibilities, domainShipment.NoteInternal)
+	}
+
+	notes, err := s.noteRepo.ListByShipment(ctx, shipmentID, visibilities)
+	if err != nil {
+		logger.Warn("Failed to load shipment notes",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	entries := make([]ShipmentEventResponse, 0, len(notes))
+	for _, n := range notes {
+		authorID := n.AuthorID
+		entries = append(entries, ShipmentEventResponse{
+			Type:       TimelineNote,
+			ActorID:    &authorID,
+			Note:       ToNoteResponse(n),
+			OccurredAt: n.CreatedAt,
+		})
+	}
+	return entries
+}
+
+// readableNotes checks the user can view the shipment and returns the note
+// visibilities open to them
+func (s *Service) readableNotes(ctx context.Context, userID, shipmentID uuid.UUID) ([]domainShipment.NoteVisibility, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeViewer(ctx, shipment, userID, domainShipment.ScopeDetails); err != nil {
+		return nil, err
+	}
+
+	if s.isStaff(ctx, userID) {
+		return []domainShipment.NoteVisibility{domainShipment.NoteInternal, domainShipment.NoteParties}, nil
+	}
+	return []domainShipment.NoteVisibility{domainShipment.NoteParties}, nil
+}
+
+// authorizeNoteWriter allows admins, the shipment's parties and the
+// customer's delegates to write notes. Holders of an access grant only read.
+func (s *Service) authorizeNoteWriter(ctx context.Context, shipment *domainShipment.Shipment, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user.Role == "admin" {
+		return true, nil
+	}
+	if isShipmentParty(shipment, userID) || s.isDelegateFor(ctx, shipment.CustomerID, userID) {
+		return false, nil
+	}
+	return false, appErrors.ErrUnauthorized
+}
+
+func (s *Service) getShipmentNote(ctx context.Context, shipmentID, noteID uuid.UUID) (*domainShipment.Note, error) {
+	note, err := s.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.ShipmentID != shipmentID {
+		return nil, domainShipment.ErrNoteNotFound
+	}
+	return note, nil
+}
+
+// isStaff reports whether the user is an admin or analyst. Lookup failures
+// count as not.
+func (s *Service) isStaff(ctx context.Context, userID uuid.UUID) bool {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	return err == nil && (user.Role == "admin" || user.Role == "analyst")
+}
+
+func containsVisibility(visibilities []domainShipment.NoteVisibility, v domainShipment.NoteVisibility) bool {
+	for _, candidate := range visibilities {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
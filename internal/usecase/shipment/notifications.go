@@ -0,0 +1,124 @@
+package shipment
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// issueSeverity maps the severity of a reported issue to a notification severity
+func issueSeverity(severity string) domainNotification.Severity {
+	switch severity {
+	case "high", "critical":
+		return domainNotification.SeverityCritical
+	default:
+		return domainNotification.SeverityInfo
+	}
+}
+
+// notifyIssueReported alerts the parties of a shipment other than the
+// reporter, and the platform operations channels, about a new issue.
+func (s *Service) notifyIssueReported(ctx context.Context, shipment *domainShipment.Shipment, reporterID uuid.UUID, req *ReportIssueRequest) {
+	if s.notifier == nil {
+		return
+	}
+
+	msg := domainNotification.Message{
+		Event:    "shipment_issue_reported",
+		Severity: issueSeverity(req.Severity),
+		Subject:  fmt.Sprintf("Issue reported on shipment %s", shipment.ID),
+		Body: fmt.Sprintf("A %s severity %s issue was reported on \"%s\":\n%s",
+			req.Severity, req.IssueType, shipment.GoodsDescription, req.Description),
+		Link: "/shipments/" + shipment.ID.String(),
+		Data: map[string]string{
+			"shipment_id":    shipment.ID.String(),
+			"issue_type":     req.IssueType,
+			"issue_severity": req.Severity,
+		},
+		Sandbox: shipment.IsSandbox,
+	}
+
+	recipients := []uuid.UUID{shipment.CustomerID, shipment.ProviderID}
+	if shipment.ShipperID != nil {
+		recipients = append(recipients, *shipment.ShipperID)
+	}
+	for _, userID := range recipients {
+		if userID != reporterID {
+			s.notifyUser(ctx, userID, msg)
+		}
+	}
+
+	// Operational alert for platform channels, not addressed to any user
+	s.notify(ctx, &msg)
+}
+
+// notifyShipmentAssigned sends the shipper its assignment with the pick-up
+// details and tells the provider who took the order.
+func (s *Service) notifyShipmentAssigned(ctx context.Context, shipment *domainShipment.Shipment) {
+	if s.notifier == nil || shipment.ShipperID == nil {
+		return
+	}
+
+	pickup := "as soon as possible"
+	if shipment.EstimatedPickupAt != nil {
+		pickup = "by " + shipment.EstimatedPickupAt.Format("2006-01-02 15:04")
+	}
+	data := map[string]string{
+		"shipment_id":      shipment.ID.String(),
+		"pickup_address":   shipment.PickupAddress,
+		"delivery_address": shipment.DeliveryAddress,
+	}
+	link := "/shipments/" + shipment.ID.String()
+
+	s.notifyUser(ctx, *shipment.ShipperID, domainNotification.Message{
+		Event:    "shipment_assigned",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "New shipment assigned to you",
+		Body: fmt.Sprintf("Pick up \"%s\" at %s %s and deliver to %s.",
+			shipment.GoodsDescription, shipment.PickupAddress, pickup, shipment.DeliveryAddress),
+		Link: link,
+		Data: data,
+	})
+	s.notifyUser(ctx, shipment.ProviderID, domainNotification.Message{
+		Event:    "shipment_shipper_assigned",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "A shipper accepted your order",
+		Body:     fmt.Sprintf("Shipment \"%s\" has been accepted by a shipper.", shipment.GoodsDescription),
+		Link:     link,
+		Data:     data,
+	})
+}
+
+// notifyUser addresses msg to a user and sends it
+func (s *Service) notifyUser(ctx context.Context, userID uuid.UUID, msg domainNotification.Message) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Warn("Failed to resolve notification recipient",
+			zap.String("user_id", userID.String()),
+			zap.String("notification_event", msg.Event),
+			zap.Error(err),
+		)
+		return
+	}
+
+	msg.UserID = u.ID
+	msg.Email = u.Email
+	msg.Name = u.FullName
+	msg.Sandbox = msg.Sandbox || u.IsSandbox
+	s.notify(ctx, &msg)
+}
+
+func (s *Service) notify(ctx context.Context, msg *domainNotification.Message) {
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		logger.Warn("Failed to send notification",
+			zap.String("user_id", msg.UserID.String()),
+			zap.String("notification_event", msg.Event),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,281 @@
+package shipment
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OverrideRequest is the body of every admin override. The justification
+// is kept in the audit trail and shown to the affected parties.
+type OverrideRequest struct {
+	Justification string `json:"justification" validate:"required,min=10,max=2000"`
+}
+
+type ForceStatusRequest struct {
+	Status        domainShipment.ShipmentStatus `json:"status" validate:"required"`
+	Justification string                        `json:"justification" validate:"required,min=10,max=2000"`
+}
+
+type OverrideResponse struct {
+	ID            uuid.UUID                      `json:"id"`
+	Action        domainShipment.OverrideAction  `json:"action"`
+	ShipmentID    *uuid.UUID                     `json:"shipment_id"`
+	DeviceID      *uuid.UUID                     `json:"device_id"`
+	AdminID       uuid.UUID                      `json:"admin_id"`
+	Justification string                         `json:"justification"`
+	FromStatus    *domainShipment.ShipmentStatus `json:"from_status,omitempty"`
+	ToStatus      *domainShipment.ShipmentStatus `json:"to_status,omitempty"`
+	CreatedAt     time.Time                      `json:"created_at"`
+}
+
+func ToOverrideResponse(o *domainShipment.Override) OverrideResponse {
+	return OverrideResponse{
+		ID:            o.ID,
+		Action:        o.Action,
+		ShipmentID:    o.ShipmentID,
+		DeviceID:      o.DeviceID,
+		AdminID:       o.AdminID,
+		Justification: o.Justification,
+		FromStatus:    o.FromStatus,
+		ToStatus:      o.ToStatus,
+		CreatedAt:     o.CreatedAt,
+	}
+}
+
+// ForceStatus moves a shipment to any status, skipping the transition and
+// business rule checks. A terminal status also frees its devices.
+func (s *Service) ForceStatus(ctx context.Context, adminID, shipmentID uuid.UUID, req *ForceStatusRequest) (*ShipmentResponse, error) {
+	justification, err := validateOverride(req, req.Justification)
+	if err != nil {
+		return nil, err
+	}
+	if !req.Status.IsValid() {
+		return nil, domainShipment.ErrInvalidStatus
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.Status == req.Status {
+		return nil, appErrors.NewAppError("NO_CHANGE", "Shipment is already in this status", nil)
+	}
+
+	override := &domainShipment.Override{
+		Action:        domainShipment.OverrideForceStatus,
+		ShipmentID:    &shipmentID,
+		AdminID:       adminID,
+		Justification: justification,
+		ToStatus:      &req.Status,
+	}
+	if err := s.overrideRepo.ForceStatus(ctx, override); err != nil {
+		return nil, err
+	}
+
+	s.recordOverride(ctx, override, domainShipment.EventData{Status: statusPtr(req.Status)})
+	s.notifyOverride(ctx, shipment, nil, "Shipment status changed by an administrator",
+		fmt.Sprintf("Shipment \"%s\" was moved from %s to %s. Reason: %s",
+			shipment.GoodsDescription, *override.FromStatus, req.Status, justification))
+
+	return s.overriddenShipment(ctx, shipmentID)
+}
+
+// ForceConfirmRules confirms a shipment's rules for its assigned shipper,
+// without the shipper signing the terms of carriage
+func (s *Service) ForceConfirmRules(ctx context.Context, adminID, shipmentID uuid.UUID, req *OverrideRequest) (*ShipmentResponse, error) {
+	justification, err := validateOverride(req, req.Justification)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.ShipperID == nil {
+		return nil, domainShipment.ErrShipperRequired
+	}
+	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		return nil, domainShipment.ErrRulesRequired
+	}
+	if rules.ConfirmedAt != nil {
+		return nil, appErrors.NewAppError("ALREADY_CONFIRMED", "Shipping rules have already been confirmed", nil)
+	}
+
+	override := &domainShipment.Override{
+		Action:        domainShipment.OverrideConfirmRules,
+		ShipmentID:    &shipmentID,
+		AdminID:       adminID,
+		Justification: justification,
+	}
+	if err := s.overrideRepo.ConfirmRules(ctx, override, *shipment.ShipperID); err != nil {
+		return nil, err
+	}
+
+	confirmed := true
+	s.recordOverride(ctx, override, domainShipment.EventData{RulesConfirmed: &confirmed})
+	s.notifyOverride(ctx, shipment, nil, "Shipping rules confirmed by an administrator",
+		fmt.Sprintf("The shipping rules of \"%s\" were confirmed for the shipper. Reason: %s",
+			shipment.GoodsDescription, justification))
+
+	return s.overriddenShipment(ctx, shipmentID)
+}
+
+// ForceReleaseDevice makes a device available again, whichever shipment
+// still holds it
+func (s *Service) ForceReleaseDevice(ctx context.Context, adminID, deviceID uuid.UUID, req *OverrideRequest) (*OverrideResponse, error) {
+	justification, err := validateOverride(req, req.Justification)
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	override := &domainShipment.Override{
+		Action:        domainShipment.OverrideReleaseDevice,
+		DeviceID:      &deviceID,
+		AdminID:       adminID,
+		Justification: justification,
+	}
+	if err := s.overrideRepo.ReleaseDevice(ctx, override); err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf("Device %s was released by an administrator. Reason: %s", device.HardwareUID, justification)
+	if override.ShipmentID == nil {
+		logOverride(override)
+		if device.OwnerShipperID != nil && s.notifier != nil {
+			s.notifyUser(ctx, *device.OwnerShipperID, domainNotification.Message{
+				Event:    "admin_override",
+				Severity: domainNotification.SeverityInfo,
+				Subject:  "Device released by an administrator",
+				Body:     body,
+				Data:     map[string]string{"device_id": deviceID.String()},
+			})
+		}
+	} else {
+		s.recordOverride(ctx, override, domainShipment.EventData{})
+		if shipment, err := s.shipmentRepo.GetByID(ctx, *override.ShipmentID); err == nil {
+			s.notifyOverride(ctx, shipment, device.OwnerShipperID, "Device released by an administrator", body)
+		}
+	}
+
+	resp := ToOverrideResponse(override)
+	return &resp, nil
+}
+
+// ListOverrides returns the admin overrides of a shipment, newest first
+func (s *Service) ListOverrides(ctx context.Context, shipmentID uuid.UUID) ([]OverrideResponse, error) {
+	if _, err := s.shipmentRepo.GetByID(ctx, shipmentID); err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.overrideRepo.ListByShipment(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]OverrideResponse, len(overrides))
+	for i, o := range overrides {
+		responses[i] = ToOverrideResponse(o)
+	}
+	return responses, nil
+}
+
+// validateOverride checks an override request and returns its trimmed
+// justification, which must not be blank
+func validateOverride(req interface{}, justification string) (string, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return "", appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	justification = strings.TrimSpace(justification)
+	if justification == "" {
+		return "", appErrors.NewAppError("VALIDATION_ERROR", "A justification is required", nil)
+	}
+	return justification, nil
+}
+
+// recordOverride logs an applied override and adds it to the shipment's
+// event log
+func (s *Service) recordOverride(ctx context.Context, o *domainShipment.Override, data domainShipment.EventData) {
+	logOverride(o)
+	data.Reason = &o.Justification
+	s.recordEvent(ctx, *o.ShipmentID, domainShipment.EventOverridden, &o.AdminID, data)
+}
+
+func logOverride(o *domainShipment.Override) {
+	fields := []zap.Field{
+		zap.String("override_id", o.ID.String()),
+		zap.String("action", string(o.Action)),
+		zap.String("admin_id", o.AdminID.String()),
+		zap.String("justification", o.Justification),
+		zap.String("event", "admin_override"),
+	}
+	if o.ShipmentID != nil {
+		fields = append(fields, zap.String("shipment_id", o.ShipmentID.String()))
+	}
+	if o.DeviceID != nil {
+		fields = append(fields, zap.String("device_id", o.DeviceID.String()))
+	}
+	logger.Warn("Admin override applied", fields...)
+}
+
+// notifyOverride tells the shipment's parties, and extra when given, what
+// an admin forced and why
+func (s *Service) notifyOverride(ctx context.Context, shipment *domainShipment.Shipment, extra *uuid.UUID, subject, body string) {
+	if s.notifier == nil {
+		return
+	}
+
+	recipients := []uuid.UUID{shipment.CustomerID, shipment.ProviderID}
+	if shipment.ShipperID != nil {
+		recipients = append(recipients, *shipment.ShipperID)
+	}
+	if extra != nil {
+		recipients = append(recipients, *extra)
+	}
+
+	msg := domainNotification.Message{
+		Event:    "admin_override",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  subject,
+		Body:     body,
+		Link:     "/shipments/" + shipment.ID.String(),
+		Data:     map[string]string{"shipment_id": shipment.ID.String()},
+		Sandbox:  shipment.IsSandbox,
+	}
+	notified := make(map[uuid.UUID]bool, len(recipients))
+	for _, userID := range recipients {
+		if notified[userID] {
+			continue
+		}
+		notified[userID] = true
+		s.notifyUser(ctx, userID, msg)
+	}
+}
+
+func (s *Service) overriddenShipment(ctx context.Context, shipmentID uuid.UUID) (*ShipmentResponse, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	rules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	return ToShipmentResponse(shipment, rules), nil
+}
@@ -0,0 +1,331 @@
+package shipment
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxPackagesPerShipment bounds how many boxes a single order may be split into
+const MaxPackagesPerShipment = 200
+
+// ListPackages returns the packages of a shipment to its parties, admins and
+// holders of a details access grant
+func (s *Service) ListPackages(ctx context.Context, userID, shipmentID uuid.UUID) (*PackageListResponse, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeViewer(ctx, shipment, userID, domainShipment.ScopeDetails); err != nil {
+		return nil, err
+	}
+
+	packages, err := s.shipmentRepo.ListPackages(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &PackageListResponse{
+		ShipmentID: shipmentID,
+		Packages:   ToPackageResponses(packages),
+		Outcomes:   make(map[string]int),
+	}
+	for _, p := range packages {
+		resp.Outcomes[string(p.Outcome)]++
+		switch {
+		case p.Outcome == domainShipment.OutcomePending:
+			resp.PendingCount++
+		case p.Outcome.IsException():
+			resp.ExceptionCount++
+		default:
+			resp.DeliveredCount++
+		}
+
+		if p.Weight != nil {
+			resp.TotalWeight += *p.Weight
+		}
+		if p.DeviceID != nil {
+			resp.TrackedCount++
+		} else {
+			resp.UntrackedCount++
+		}
+	}
+	return resp, nil
+}
+
+// AddPackage splits a shipment into one more package. Packages can be added
+// until shipping starts.
+func (s *Service) AddPackage(ctx context.Context, providerID, shipmentID uuid.UUID, req *CreatePackageRequest) (*PackageResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.getEditableShipment(ctx, providerID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.shipmentRepo.ListPackages(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxPackagesPerShipment {
+		return nil, appErrors.NewAppError("TOO_MANY_PACKAGES", "Shipment already has the maximum number of packages", nil)
+	}
+
+	pkg := &domainShipment.Package{
+		ShipmentID:  shipment.ID,
+		Description: req.Description,
+		Weight:      req.Weight,
+	}
+	if err := s.shipmentRepo.CreatePackage(ctx, pkg); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Package added to shipment",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("package_id", pkg.ID.String()),
+		zap.Int("sequence", pkg.Sequence),
+		zap.String("event", "package_added"),
+	)
+
+	return &ToPackageResponses([]*domainShipment.Package{pkg})[0], nil
+}
+
+// UpdatePackage changes the description or weight of a package
+func (s *Service) UpdatePackage(ctx context.Context, providerID, shipmentID, packageID uuid.UUID, req *UpdatePackageRequest) (*PackageResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	if _, err := s.getEditableShipment(ctx, providerID, shipmentID); err != nil {
+		return nil, err
+	}
+
+	pkg, err := s.getShipmentPackage(ctx, shipmentID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		pkg.Description = *req.Description
+	}
+	if req.Weight != nil {
+		pkg.Weight = req.Weight
+	}
+	if err := s.shipmentRepo.UpdatePackage(ctx, pkg); err != nil {
+		return nil, err
+	}
+
+	return &ToPackageResponses([]*domainShipment.Package{pkg})[0], nil
+}
+
+// RemovePackage deletes a package that has no tracker attached yet
+func (s *Service) RemovePackage(ctx context.Context, providerID, shipmentID, packageID uuid.UUID) error {
+	if _, err := s.getEditableShipment(ctx, providerID, shipmentID); err != nil {
+		return err
+	}
+
+	if _, err := s.getShipmentPackage(ctx, shipmentID, packageID); err != nil {
+		return err
+	}
+
+	if err := s.shipmentRepo.DeletePackage(ctx, packageID); err != nil {
+		return err
+	}
+
+	logger.Info("Package removed from shipment",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("package_id", packageID.String()),
+		zap.String("event", "package_removed"),
+	)
+	return nil
+}
+
+// AssignPackageDevice attaches one of the shipper's trackers to a package.
+// Only the assigned shipper can do this, between accepting the order and
+// starting the trip.
+func (s *Service) AssignPackageDevice(ctx context.Context, shipperID, shipmentID, packageID uuid.UUID, req *AssignPackageDeviceRequest) (*PackageResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify shipper owns this shipment
+	if shipment.ShipperID == nil || *shipment.ShipperID != shipperID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Shipper does not own this shipment", nil)
+	}
+	if shipment.Status != domainShipment.StatusShippingAssigned {
+		return nil, appErrors.NewAppError("INVALID_STATUS", "Package trackers can only be assigned before shipping starts", nil)
+	}
+
+	pkg, err := s.getShipmentPackage(ctx, shipmentID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateDevice(ctx, s.deviceRepo, req.DeviceID, shipperID, RequiredCapabilities(rules)); err != nil {
+		return nil, err
+	}
+
+	if err := s.shipmentRepo.AssignPackageDevice(ctx, packageID, req.DeviceID); err != nil {
+		return nil, err
+	}
+	pkg.DeviceID = &req.DeviceID
+
+	logger.Info("Device assigned to package",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("package_id", packageID.String()),
+		zap.String("device_id", req.DeviceID.String()),
+		zap.String("event", "package_device_assigned"),
+	)
+
+	return &ToPackageResponses([]*domainShipment.Package{pkg})[0], nil
+}
+
+// RecordPackageOutcome confirms delivery of a single package or records an
+// exception (lost, damaged, refused) for it. Outcomes can be corrected until
+// the shipment is completed.
+func (s *Service) RecordPackageOutcome(ctx context.Context, shipperID, shipmentID, packageID uuid.UUID, req *RecordPackageOutcomeRequest) (*PackageResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify shipper owns this shipment
+	if shipment.ShipperID == nil || *shipment.ShipperID != shipperID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Shipper does not own this shipment", nil)
+	}
+	if shipment.Status != domainShipment.StatusInTransit && shipment.Status != domainShipment.StatusIssueReported {
+		return nil, appErrors.NewAppError("INVALID_STATUS", "Package outcomes can only be recorded while the shipment is in transit", nil)
+	}
+
+	pkg, err := s.getShipmentPackage(ctx, shipmentID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pkg.Outcome = req.Outcome
+	pkg.OutcomeNote = req.Note
+	pkg.OutcomeRecordedBy = &shipperID
+	pkg.OutcomeAt = &now
+	if err := s.shipmentRepo.RecordPackageOutcome(ctx, pkg); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Package outcome recorded",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.String("package_id", packageID.String()),
+		zap.String("outcome", string(req.Outcome)),
+		zap.String("event", "package_outcome_recorded"),
+	)
+
+	return &ToPackageResponses([]*domainShipment.Package{pkg})[0], nil
+}
+
+// resolveDeliveryVerdict confirms the packages still pending at completion and
+// derives the final shipment status from the package outcomes.
+func (s *Service) resolveDeliveryVerdict(ctx context.Context, shipperID, shipmentID uuid.UUID, at time.Time) (domainShipment.ShipmentStatus, error) {
+	if err := s.shipmentRepo.ConfirmPendingPackages(ctx, shipmentID, shipperID, at); err != nil {
+		return "", err
+	}
+
+	packages, err := s.shipmentRepo.ListPackages(ctx, shipmentID)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range packages {
+		if p.Outcome.IsException() {
+			return domainShipment.StatusPartiallyCompleted, nil
+		}
+	}
+	return domainShipment.StatusCompleted, nil
+}
+
+// ResolveDevice maps a reporting device to its active shipment and package.
+// Telemetry ingestion uses it to attribute readings and alerts.
+// A trip device resolves to the next stop and lists every pending member.
+func (s *Service) ResolveDevice(ctx context.Context, deviceID uuid.UUID) (*domainShipment.DeviceAssignment, error) {
+	assignment, err := s.resolveTripDevice(ctx, deviceID)
+	if !errors.Is(err, domainShipment.ErrTripNotFound) {
+		return assignment, err
+	}
+	return s.shipmentRepo.ResolveDevice(ctx, deviceID)
+}
+
+// releasePackageDevices marks package trackers available again once the
+// shipment is finished.
+func (s *Service) releasePackageDevices(ctx context.Context, shipmentID uuid.UUID) {
+	packages, err := s.shipmentRepo.ListPackages(ctx, shipmentID)
+	if err != nil {
+		logger.Warn("Failed to list packages for device release",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, p := range packages {
+		if p.DeviceID == nil {
+			continue
+		}
+		if err := s.deviceRepo.UpdateStatus(ctx, *p.DeviceID, domainDevice.StatusAvailable); err != nil {
+			logger.Warn("Failed to update device status",
+				zap.String("device_id", p.DeviceID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (s *Service) getEditableShipment(ctx context.Context, providerID, shipmentID uuid.UUID) (*domainShipment.Shipment, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify provider owns this shipment
+	if shipment.ProviderID != providerID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Provider does not own this shipment", nil)
+	}
+
+	switch shipment.Status {
+	case domainShipment.StatusDemandCreated, domainShipment.StatusOrderPosted, domainShipment.StatusShippingAssigned:
+		return shipment, nil
+	default:
+		return nil, appErrors.NewAppError("INVALID_STATUS", "Packages cannot be changed once shipping has started", nil)
+	}
+}
+
+func (s *Service) getShipmentPackage(ctx context.Context, shipmentID, packageID uuid.UUID) (*domainShipment.Package, error) {
+	pkg, err := s.shipmentRepo.GetPackageByID(ctx, packageID)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.ShipmentID != shipmentID {
+		return nil, domainShipment.ErrPackageNotFound
+	}
+	return pkg, nil
+}
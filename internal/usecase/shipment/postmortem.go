@@ -0,0 +1,289 @@
+package shipment
+
+import (
+	"archive/zip"
+	"bytes"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/job"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// JobKindPostmortem builds the incident postmortem bundle of a shipment
+const JobKindPostmortem = "shipment.postmortem_bundle"
+
+type postmortemPayload struct {
+	ShipmentID uuid.UUID `json:"shipment_id"`
+}
+
+// PostmortemVerdict is how a shipment ended, as written to verdict.json
+type PostmortemVerdict struct {
+	ShipmentID uuid.UUID                     `json:"shipment_id"`
+	Status     domainShipment.ShipmentStatus `json:"status"`
+	Finished   bool                          `json:"finished"`
+
+	EstimatedDeliveryAt *time.Time `json:"estimated_delivery_at"`
+	DeliveryDueAt       *time.Time `json:"delivery_due_at"`
+	ActualPickupAt      *time.Time `json:"actual_pickup_at"`
+	ActualDeliveryAt    *time.Time `json:"actual_delivery_at"`
+	// Nil until the shipment is delivered or when it had no deadline
+	OnTime *bool `json:"on_time"`
+
+	Packages        []PackageResponse `json:"packages"`
+	CompletionNotes *string           `json:"completion_notes"`
+	CustomerRating  *int              `json:"customer_rating"`
+	GeneratedAt     time.Time         `json:"generated_at"`
+}
+
+// RegisterJobs registers the background jobs of the service
+func (s *Service) RegisterJobs(jobs *job.Service) {
+	s.jobs = jobs
+	// Bundles only read, so they are safe to retry
+	jobs.Register(JobKindPostmortem, s.runPostmortemJob, job.RetryPolicy{MaxAttempts: 3, Backoff: time.Minute})
+}
+
+// QueuePostmortemBundle queues the evidence bundle of a shipment for an
+// admin. The job result is a zip file.
+func (s *Service) QueuePostmortemBundle(ctx context.Context, adminID, shipmentID uuid.UUID) (*job.JobResponse, error) {
+	if _, err := s.shipmentRepo.GetByID(ctx, shipmentID); err != nil {
+		return nil, err
+	}
+	return s.jobs.Enqueue(ctx, adminID, JobKindPostmortem, postmortemPayload{ShipmentID: shipmentID})
+}
+
+// runPostmortemJob gathers what the platform knows about a shipment into a
+// zip: the shipment, its verdict, status history, rules versions, event
+// log, admin overrides and attachments
+func (s *Service) runPostmortemJob(ctx context.Context, run *job.Run) (*job.Output, error) {
+	var payload postmortemPayload
+	if err := run.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	detail, err := s.shipmentRepo.GetDetail(ctx, payload.ShipmentID)
+	if err != nil {
+		return nil, err
+	}
+	shipment := detail.Shipment
+	now := time.Now()
+
+	const steps = 6
+	run.SetProgress(0, steps, "Collecting shipment records")
+
+	versions, err := s.shipmentRepo.ListRulesVersions(ctx, shipment.ID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.overrideRepo.ListByShipment(ctx, shipment.ID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.documentRepo.ListAttachments(ctx, shipment.ID)
+	if err != nil {
+		return nil, err
+	}
+	var events []ShipmentEventResponse
+	if s.events != nil {
+		logged, err := s.events.List(ctx, shipment.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		events = make([]ShipmentEventResponse, len(logged))
+		for i, e := range logged {
+			events[i] = ToShipmentEventResponse(e)
+		}
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	// Missing and unavailable evidence is listed in the README
+	var gaps []string
+
+	run.SetProgress(1, steps, "Writing shipment and verdict")
+	if err := writeZipJSON(archive, "shipment.json", now, ToShipmentResponse(shipment, detail.Rules)); err != nil {
+		return nil, err
+	}
+	if err := writeZipJSON(archive, "verdict.json", now, postmortemVerdict(detail, now)); err != nil {
+		return nil, err
+	}
+
+	run.SetProgress(2, steps, "Writing status history")
+	history := s.statusHistory(ctx, shipment.ID)
+	if err := writeStatusHistoryCSV(archive, now, history); err != nil {
+		return nil, err
+	}
+	if s.events == nil {
+		gaps = append(gaps, "status history and event log: the shipment event log is disabled")
+	} else if err := writeZipJSON(archive, "events.json", now, events); err != nil {
+		return nil, err
+	}
+
+	run.SetProgress(3, steps, "Writing rules versions and overrides")
+	if err := writeZipJSON(archive, "rules_versions.json", now, toRulesHistory(shipment, versions)); err != nil {
+		return nil, err
+	}
+	overrideResponses := make([]OverrideResponse, len(overrides))
+	for i, o := range overrides {
+		overrideResponses[i] = ToOverrideResponse(o)
+	}
+	if err := writeZipJSON(archive, "overrides.json", now, overrideResponses); err != nil {
+		return nil, err
+	}
+
+	run.SetProgress(4, steps, "Copying attachments")
+	for i, a := range attachments {
+		run.SetProgress(4, steps, fmt.Sprintf("Copying attachment %d of %d", i+1, len(attachments)))
+		content, err := s.store.Open(ctx, a.StorageKey)
+		if err != nil {
+			logger.Warn("Failed to open attachment for postmortem bundle",
+				zap.String("shipment_id", shipment.ID.String()),
+				zap.String("attachment_id", a.ID.String()),
+				zap.Error(err),
+			)
+			gaps = append(gaps, fmt.Sprintf("attachment %s (%s) could not be read", a.ID, a.FileName))
+			continue
+		}
+		name := fmt.Sprintf("attachments/%s_%s", a.ID, path.Base(a.FileName))
+		err = copyToZip(archive, name, content, a.CreatedAt)
+		content.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	run.SetProgress(5, steps, "Writing README")
+	gaps = append(gaps, "no telemetry or alert log: devices do not report sensor readings to this platform")
+	if err := writeZipFile(archive, "README.txt", now, []byte(postmortemReadme(shipment, now, len(attachments), gaps))); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write postmortem bundle: %w", err)
+	}
+
+	logger.Info("Postmortem bundle built",
+		zap.String("shipment_id", shipment.ID.String()),
+		zap.String("job_id", run.Job.ID.String()),
+		zap.Int("attachments", len(attachments)),
+		zap.Int("size_bytes", buf.Len()),
+		zap.String("event", "postmortem_bundle_built"),
+	)
+
+	return &job.Output{File: &job.OutputFile{
+		Name:        fmt.Sprintf("postmortem-%s.zip", shipment.ID),
+		ContentType: "application/zip",
+		Data:        buf.Bytes(),
+	}}, nil
+}
+
+func postmortemVerdict(detail *domainShipment.ShipmentDetail, now time.Time) *PostmortemVerdict {
+	shipment := detail.Shipment
+	verdict := &PostmortemVerdict{
+		ShipmentID:          shipment.ID,
+		Status:              shipment.Status,
+		Finished:            shipment.Status.IsTerminal(),
+		EstimatedDeliveryAt: shipment.EstimatedDeliveryAt,
+		DeliveryDueAt:       shipment.DeliveryDueAt,
+		ActualPickupAt:      shipment.ActualPickupAt,
+		ActualDeliveryAt:    shipment.ActualDeliveryAt,
+		Packages:            ToPackageResponses(detail.Packages),
+		CompletionNotes:     shipment.CompletionNotes,
+		CustomerRating:      shipment.CustomerRating,
+		GeneratedAt:         now,
+	}
+
+	deadline := shipment.DeliveryDueAt
+	if deadline == nil {
+		deadline = shipment.EstimatedDeliveryAt
+	}
+	if shipment.ActualDeliveryAt != nil && deadline != nil {
+		onTime := !shipment.ActualDeliveryAt.After(*deadline)
+		verdict.OnTime = &onTime
+	}
+	return verdict
+}
+
+func postmortemReadme(shipment *domainShipment.Shipment, now time.Time, attachments int, gaps []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Postmortem bundle of shipment %s\n", shipment.ID)
+	fmt.Fprintf(&b, "Generated at %s; status %s\n\n", now.UTC().Format(time.RFC3339), shipment.Status)
+	b.WriteString("shipment.json        the shipment as it stands\n")
+	b.WriteString("verdict.json         how it ended: status, timing and package outcomes\n")
+	b.WriteString("status_history.csv   every status change, oldest first\n")
+	b.WriteString("events.json          the full shipment event log\n")
+	b.WriteString("rules_versions.json  every version of the shipping rules and what it changed\n")
+	b.WriteString("overrides.json       admin overrides with their justification\n")
+	fmt.Fprintf(&b, "attachments/         the %d uploaded documents\n", attachments)
+	if len(gaps) > 0 {
+		b.WriteString("\nNot included:\n")
+		for _, gap := range gaps {
+			fmt.Fprintf(&b, "- %s\n", gap)
+		}
+	}
+	return b.String()
+}
+
+func writeStatusHistoryCSV(archive *zip.Writer, now time.Time, history []StatusHistory) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"changed_at", "from_status", "to_status", "changed_by", "notes"})
+	for _, h := range history {
+		var from, by, notes string
+		if h.FromStatus != nil {
+			from = string(*h.FromStatus)
+		}
+		if h.ChangedBy != nil {
+			by = h.ChangedBy.String()
+		}
+		if h.Notes != nil {
+			notes = *h.Notes
+		}
+		_ = w.Write([]string{h.ChangedAt.UTC().Format(time.RFC3339), from, string(h.ToStatus), by, notes})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write status history: %w", err)
+	}
+	return writeZipFile(archive, "status_history.csv", now, buf.Bytes())
+}
+
+// copyToZip streams a stored file into the archive
+func copyToZip(archive *zip.Writer, name string, content io.Reader, modified time.Time) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := io.Copy(w, content); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeZipJSON(archive *zip.Writer, name string, modified time.Time, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return writeZipFile(archive, name, modified, data)
+}
+
+func writeZipFile(archive *zip.Writer, name string, modified time.Time, data []byte) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return nil
+}
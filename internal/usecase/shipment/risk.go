@@ -0,0 +1,320 @@
+package shipment
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Risk factor names
+const (
+	RiskFactorReputation  = "shipper_reputation"
+	RiskFactorDevice      = "device_health"
+	RiskFactorRoute       = "route_length"
+	RiskFactorGoods       = "goods_sensitivity"
+	RiskFactorSeasonality = "seasonality"
+)
+
+// Goods value in the reporting currency above which the goods factor is
+// maxed out on value alone
+const highValueGoods = 100_000_000
+
+// RiskWeights are the relative weights of the risk factors
+type RiskWeights struct {
+	Reputation  float64
+	Device      float64
+	Route       float64
+	Goods       float64
+	Seasonality float64
+}
+
+// DefaultRiskWeights is used when no weights are configured
+var DefaultRiskWeights = RiskWeights{
+	Reputation: 0.35,
+	Device:     0.2,
+	Route:      0.15,
+	Goods:      0.3,
+}
+
+// RiskScorer combines weighted factors into a 0–100 risk score. Factors that
+// cannot be scored yet (no shipper or device before acceptance) are left out
+// and the remaining weights are normalised.
+type RiskScorer struct {
+	weights RiskWeights
+}
+
+// NewRiskScorer creates a risk scorer, falling back to the default weights
+// when all given weights are zero or any is negative
+func NewRiskScorer(weights RiskWeights) *RiskScorer {
+	sum := weights.Reputation + weights.Device + weights.Route + weights.Goods + weights.Seasonality
+	if sum <= 0 || weights.Reputation < 0 || weights.Device < 0 || weights.Route < 0 ||
+		weights.Goods < 0 || weights.Seasonality < 0 {
+		weights = DefaultRiskWeights
+	}
+	return &RiskScorer{weights: weights}
+}
+
+// RiskInput is what a shipment is scored on
+type RiskInput struct {
+	Shipment *domainShipment.Shipment
+	Rules    *domainShipment.ShippingRules
+	Shipper  *domainShipment.ShipperRecord // nil before a shipper is assigned
+	Device   *domainDevice.Device          // nil before a device is assigned
+}
+
+// Score computes the risk assessment of a shipment
+func (r *RiskScorer) Score(in RiskInput, now time.Time) *domainShipment.RiskAssessment {
+	var factors []domainShipment.RiskFactor
+	add := func(name string, weight float64, score int, detail string) {
+		if weight <= 0 {
+			return
+		}
+		factors = append(factors, domainShipment.RiskFactor{
+			Name:   name,
+			Score:  clampScore(score),
+			Weight: weight,
+			Detail: detail,
+		})
+	}
+
+	if in.Shipper != nil {
+		score, detail := reputationRisk(in.Shipper)
+		add(RiskFactorReputation, r.weights.Reputation, score, detail)
+	}
+	if in.Device != nil {
+		score, detail := deviceRisk(in.Device, now)
+		add(RiskFactorDevice, r.weights.Device, score, detail)
+	}
+	if score, detail, ok := routeRisk(in.Shipment); ok {
+		add(RiskFactorRoute, r.weights.Route, score, detail)
+	}
+	score, detail := goodsRisk(in.Shipment, in.Rules)
+	add(RiskFactorGoods, r.weights.Goods, score, detail)
+
+	// No weather or seasonal data source yet; scored as neutral
+	add(RiskFactorSeasonality, r.weights.Seasonality, 50, "no seasonal data available")
+
+	assessment := &domainShipment.RiskAssessment{
+		Factors:    factors,
+		AssessedAt: now,
+	}
+
+	var total, weightSum float64
+	for _, f := range factors {
+		total += float64(f.Score) * f.Weight
+		weightSum += f.Weight
+	}
+	if weightSum > 0 {
+		assessment.Score = clampScore(int(math.Round(total / weightSum)))
+	}
+	for i := range assessment.Factors {
+		assessment.Factors[i].Weight = math.Round(assessment.Factors[i].Weight/weightSum*1000) / 1000
+	}
+
+	return assessment
+}
+
+// reputationRisk scores a shipper on cancellations, partial deliveries, open
+// issues and customer ratings. Shippers without history score neutral.
+func reputationRisk(rec *domainShipment.ShipperRecord) (int, string) {
+	if rec.Finished == 0 {
+		return 50, "no finished shipments"
+	}
+
+	finished := float64(rec.Finished)
+	score := 100*float64(rec.Cancelled)/finished +
+		50*float64(rec.Partial)/finished +
+		100*float64(rec.Issues)/finished
+	if rec.Rated > 0 {
+		// 5 stars adds nothing, 1 star adds 40
+		score += (5 - rec.AvgRating) * 10
+	}
+
+	// Thin history is pulled towards neutral
+	if rec.Finished < 5 {
+		weight := finished / 5
+		score = score*weight + 50*(1-weight)
+	}
+
+	return int(math.Round(score)), fmt.Sprintf("%d finished, %d cancelled, %d partial, %d with issues, avg rating %.1f",
+		rec.Finished, rec.Cancelled, rec.Partial, rec.Issues, rec.AvgRating)
+}
+
+// deviceRisk scores a device on battery level, how recently it reported and
+// its status
+func deviceRisk(d *domainDevice.Device, now time.Time) (int, string) {
+	score := 0
+
+	battery := "unknown battery"
+	switch {
+	case d.BatteryLevel == nil:
+		score += 30
+	case *d.BatteryLevel < 20:
+		score += 60
+		battery = fmt.Sprintf("battery %d%%", *d.BatteryLevel)
+	case *d.BatteryLevel < 50:
+		score += 30
+		battery = fmt.Sprintf("battery %d%%", *d.BatteryLevel)
+	default:
+		battery = fmt.Sprintf("battery %d%%", *d.BatteryLevel)
+	}
+
+	seen := "never seen"
+	switch {
+	case d.LastSeenAt == nil:
+		score += 40
+	case now.Sub(*d.LastSeenAt) > 24*time.Hour:
+		score += 40
+		seen = "last seen over a day ago"
+	case now.Sub(*d.LastSeenAt) > time.Hour:
+		score += 20
+		seen = "last seen over an hour ago"
+	default:
+		seen = "recently seen"
+	}
+
+	if d.Status == domainDevice.StatusMaintenance || d.Status == domainDevice.StatusRetired {
+		score = 100
+	}
+
+	return score, fmt.Sprintf("%s, %s, %s", battery, seen, d.Status)
+}
+
+// routeRisk scores route length. There are no route coordinates, so the
+// planned pickup-to-delivery time stands in for distance: up to 4 hours is
+// low risk, 3 days or more is the maximum.
+func routeRisk(s *domainShipment.Shipment) (int, string, bool) {
+	if s.EstimatedPickupAt == nil || s.EstimatedDeliveryAt == nil {
+		return 0, "", false
+	}
+
+	hours := s.EstimatedDeliveryAt.Sub(*s.EstimatedPickupAt).Hours()
+	if hours < 0 {
+		hours = 0
+	}
+
+	const short, long = 4.0, 72.0
+	var score float64
+	switch {
+	case hours <= short:
+		score = 10
+	case hours >= long:
+		score = 100
+	default:
+		score = 10 + 90*(hours-short)/(long-short)
+	}
+
+	return int(math.Round(score)), fmt.Sprintf("planned transit %.1f hours", hours), true
+}
+
+// goodsRisk scores how sensitive the goods are. There is no goods category, so
+// sensitivity is read from how many conditions the quality rules constrain,
+// how narrow the temperature band is, and the declared value.
+func goodsRisk(s *domainShipment.Shipment, rules *domainShipment.ShippingRules) (int, string) {
+	score := 0.0
+	constrained := 0
+
+	if rules != nil {
+		limits := []bool{
+			rules.TempMin != nil || rules.TempMax != nil,
+			rules.HumidityMin != nil || rules.HumidityMax != nil,
+			rules.LightMax != nil,
+			rules.TiltMaxAngle != nil,
+			rules.ImpactThresholdG != nil,
+		}
+		for _, set := range limits {
+			if set {
+				constrained++
+			}
+		}
+		score += float64(constrained) * 10
+
+		if rules.TempMin != nil && rules.TempMax != nil {
+			band := *rules.TempMax - *rules.TempMin
+			switch {
+			case band <= 4:
+				score += 20
+			case band <= 10:
+				score += 10
+			}
+		}
+	}
+
+	value := s.ReportingGoodsValue()
+	if value == nil {
+		value = s.GoodsValue
+	}
+	if value != nil && *value > 0 {
+		score += math.Min(*value/highValueGoods, 1) * 30
+	}
+
+	return int(math.Round(score)), fmt.Sprintf("%d monitored conditions", constrained)
+}
+
+func clampScore(score int) int {
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
+
+// assessRisk scores a shipment and stores the assessment. Failures are logged
+// and do not block the workflow step that triggered the assessment.
+func (s *Service) assessRisk(ctx context.Context, shipment *domainShipment.Shipment, rules *domainShipment.ShippingRules) *domainShipment.RiskAssessment {
+	if s.riskScorer == nil {
+		return nil
+	}
+
+	in := RiskInput{Shipment: shipment, Rules: rules}
+
+	if shipment.ShipperID != nil {
+		record, err := s.shipmentRepo.GetShipperRecord(ctx, *shipment.ShipperID)
+		if err != nil {
+			logger.Warn("Failed to load shipper record for risk scoring",
+				zap.String("shipment_id", shipment.ID.String()),
+				zap.Error(err),
+			)
+		} else {
+			in.Shipper = record
+		}
+	}
+
+	if shipment.LinkedDeviceID != nil {
+		device, err := s.deviceRepo.GetByID(ctx, *shipment.LinkedDeviceID)
+		if err != nil {
+			logger.Warn("Failed to load device for risk scoring",
+				zap.String("shipment_id", shipment.ID.String()),
+				zap.Error(err),
+			)
+		} else {
+			in.Device = device
+		}
+	}
+
+	assessment := s.riskScorer.Score(in, time.Now())
+	if err := s.shipmentRepo.SetRiskAssessment(ctx, shipment.ID, assessment); err != nil {
+		logger.Warn("Failed to store risk assessment",
+			zap.String("shipment_id", shipment.ID.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	logger.Info("Shipment risk assessed",
+		zap.String("shipment_id", shipment.ID.String()),
+		zap.Int("risk_score", assessment.Score),
+		zap.String("event", "risk_assessed"),
+	)
+
+	shipment.Risk = assessment
+	return assessment
+}
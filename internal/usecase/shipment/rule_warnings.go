@@ -0,0 +1,108 @@
+package shipment
+
+import (
+	domainReference "cargo-tracker/internal/domain/reference"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"fmt"
+	"strings"
+)
+
+// Rule warning codes
+const (
+	WarningCategoryMismatch = "CATEGORY_MISMATCH"
+	WarningMissingTempRules = "MISSING_TEMPERATURE_RULES"
+	WarningTightRange       = "TIGHT_RANGE"
+	WarningSensitiveTrigger = "SENSITIVE_TRIGGER"
+	WarningBatteryLife      = "BATTERY_LIFE"
+)
+
+// RuleWarning is a non-blocking advisory about shipping rules that are valid
+// but unusual for the shipment
+type RuleWarning struct {
+	Code    string `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// reportsPerBattery is roughly how many readings a tracker sends on a full
+// battery. Devices do not report their capacity, so this is a conservative
+// figure for the trackers in use.
+const reportsPerBattery = 20000
+
+// Tolerance before a rule is reported as outside a category's profile
+const categoryTolerance = 2.0
+
+func ruleWarnings(rules *PostOrderRequest, shipment *domainShipment.Shipment, categories []domainReference.GoodsCategory) []RuleWarning {
+	var warnings []RuleWarning
+	add := func(code, field, format string, args ...interface{}) {
+		warnings = append(warnings, RuleWarning{Code: code, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	category := shipmentGoodsCategory(shipment, categories)
+	if category != nil && category.TempMin != nil && category.TempMax != nil {
+		name, tempMin, tempMax := strings.ToLower(category.Name), *category.TempMin, *category.TempMax
+		switch {
+		case rules.TempMin == nil && rules.TempMax == nil:
+			add(WarningMissingTempRules, "temp_min", "Goods look like %s, usually kept at %.0f to %.0f °C, but no temperature limits are set",
+				name, tempMin, tempMax)
+		default:
+			if rules.TempMin != nil && *rules.TempMin < tempMin-categoryTolerance {
+				add(WarningCategoryMismatch, "temp_min", "Minimum of %.1f °C is unusually low for %s, usually kept at %.0f to %.0f °C",
+					*rules.TempMin, name, tempMin, tempMax)
+			}
+			if rules.TempMax != nil && *rules.TempMax > tempMax+categoryTolerance {
+				add(WarningCategoryMismatch, "temp_max", "Maximum of %.1f °C is unusually high for %s, usually kept at %.0f to %.0f °C",
+					*rules.TempMax, name, tempMin, tempMax)
+			}
+		}
+	}
+
+	if rules.TempMin != nil && rules.TempMax != nil && *rules.TempMax-*rules.TempMin < 2 {
+		add(WarningTightRange, "temp_max", "Temperature range of %.1f °C is narrower than typical sensor drift and may raise false alerts",
+			*rules.TempMax-*rules.TempMin)
+	}
+	if rules.HumidityMin != nil && rules.HumidityMax != nil && *rules.HumidityMax-*rules.HumidityMin < 5 {
+		add(WarningTightRange, "humidity_max", "Humidity range of %.1f%% is very narrow and may raise false alerts",
+			*rules.HumidityMax-*rules.HumidityMin)
+	}
+	if rules.ImpactThresholdG != nil && *rules.ImpactThresholdG < 0.5 {
+		add(WarningSensitiveTrigger, "impact_threshold_g", "Impact threshold of %.2f G is exceeded by normal handling", *rules.ImpactThresholdG)
+	}
+	if rules.TiltMaxAngle != nil && *rules.TiltMaxAngle < 5 {
+		add(WarningSensitiveTrigger, "tilt_max_angle", "Tilt limit of %.1f° is exceeded by normal road movement", *rules.TiltMaxAngle)
+	}
+
+	if shipment.EstimatedPickupAt != nil && shipment.EstimatedDeliveryAt != nil {
+		transit := shipment.EstimatedDeliveryAt.Sub(*shipment.EstimatedPickupAt).Hours()
+		lifetime := float64(reportsPerBattery*rules.ReportCycleSec) / 3600
+		if transit > lifetime {
+			add(WarningBatteryLife, "report_cycle_sec", "At a %d s report cycle a full battery lasts about %.0f hours, less than the planned transit of %.0f hours",
+				rules.ReportCycleSec, lifetime, transit)
+		}
+	}
+
+	return warnings
+}
+
+// shipmentGoodsCategory returns the category the shipment names or, when it
+// names none, the first one whose keywords appear in the goods description
+func shipmentGoodsCategory(shipment *domainShipment.Shipment, categories []domainReference.GoodsCategory) *domainReference.GoodsCategory {
+	if shipment.GoodsCategory != nil {
+		for i := range categories {
+			if categories[i].Code == *shipment.GoodsCategory {
+				return &categories[i]
+			}
+		}
+		return nil
+	}
+
+	description := strings.ToLower(shipment.GoodsDescription)
+	for i := range categories {
+		for _, keyword := range categories[i].Keywords {
+			if strings.Contains(description, keyword) {
+				return &categories[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package shipment
+
+import (
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainQuota "cargo-tracker/internal/domain/quota"
+	domainReference "cargo-tracker/internal/domain/reference"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RulesVersionResponse struct {
+	ID      uuid.UUID              `json:"id"`
+	Version int                    `json:"version"`
+	Rules   *ShippingRulesResponse `json:"rules"`
+	SetBy   uuid.UUID              `json:"set_by"`
+	SetAt   time.Time              `json:"set_at"`
+	// Active marks the version the shipment is held to
+	Active bool `json:"active"`
+	// Changes are the fields that differ from the previous version; for the
+	// first version, every field that is set
+	Changes []domainShipment.RulesChange `json:"changes"`
+}
+
+// UpdateRules lets the provider change the shipping rules until the shipper
+// confirms them. Each change is kept as a new rules version.
+func (s *Service) UpdateRules(ctx context.Context, shipmentID, providerID uuid.UUID, req *PostOrderRequest) (*ShipmentResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.ProviderID != providerID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Provider does not own this shipment", nil)
+	}
+	if shipment.Status != domainShipment.StatusOrderPosted && shipment.Status != domainShipment.StatusShippingAssigned {
+		return nil, domainShipment.ErrInvalidStatus
+	}
+
+	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		return nil, domainShipment.ErrRulesRequired
+	}
+	if rules.ConfirmedAt != nil {
+		return nil, appErrors.NewAppError("ALREADY_CONFIRMED", "Shipping rules have already been confirmed", nil)
+	}
+
+	var categories []domainReference.GoodsCategory
+	if reference, err := s.reference.Data(ctx); err != nil {
+		logger.Warn("Goods categories unavailable for rule warnings",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.Error(err),
+		)
+	} else {
+		categories = reference.GoodsCategories
+	}
+	warnings, err := ValidateShippingRules(req, shipment, categories)
+	if err != nil {
+		return nil, err
+	}
+	if req.EnablePredictiveAlert && !rules.EnablePredictiveAlert {
+		if err := s.quotas.RequireFeature(ctx, providerID, domainQuota.FeaturePredictiveAlerts); err != nil {
+			return nil, err
+		}
+	}
+
+	updated := *rules
+	updated.ReportCycleSec = req.ReportCycleSec
+	updated.TempMin = req.TempMin
+	updated.TempMax = req.TempMax
+	updated.HumidityMin = req.HumidityMin
+	updated.HumidityMax = req.HumidityMax
+	updated.LightMax = req.LightMax
+	updated.TiltMaxAngle = req.TiltMaxAngle
+	updated.ImpactThresholdG = req.ImpactThresholdG
+	updated.EnablePredictiveAlert = req.EnablePredictiveAlert
+	updated.AlertBufferTimeMin = req.AlertBufferTimeMin
+
+	changes := domainShipment.DiffRules(rules, &updated)
+	if len(changes) > 0 {
+		if err := s.shipmentRepo.UpdateRules(ctx, &updated, providerID); err != nil {
+			return nil, err
+		}
+
+		logger.Info("Shipping rules updated",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.String("provider_id", providerID.String()),
+			zap.Int("changed_fields", len(changes)),
+			zap.String("event", "rules_updated"),
+		)
+
+		s.recordEvent(ctx, shipmentID, domainShipment.EventRulesSet, &providerID, domainShipment.EventData{
+			Rules: &updated,
+		})
+		s.notifyRulesUpdated(ctx, shipment, len(changes))
+	}
+
+	updatedShipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	if len(changes) > 0 {
+		s.assessRisk(ctx, updatedShipment, updatedRules)
+	}
+	resp := ToShipmentResponse(updatedShipment, updatedRules)
+	resp.Warnings = warnings
+	return resp, nil
+}
+
+// GetRulesHistory returns every version of a shipment's rules, oldest first,
+// each with the fields it changed
+func (s *Service) GetRulesHistory(ctx context.Context, userID, shipmentID uuid.UUID) ([]RulesVersionResponse, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeViewer(ctx, shipment, userID, domainShipment.ScopeDetails); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.shipmentRepo.ListRulesVersions(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	return toRulesHistory(shipment, versions), nil
+}
+
+// toRulesHistory maps the rules versions of a shipment, oldest first, with
+// what each changed
+func toRulesHistory(shipment *domainShipment.Shipment, versions []*domainShipment.RulesVersion) []RulesVersionResponse {
+	history := make([]RulesVersionResponse, len(versions))
+	var prev *domainShipment.ShippingRules
+	for i, v := range versions {
+		rules := v.Rules
+		history[i] = RulesVersionResponse{
+			ID:      v.ID,
+			Version: v.Version,
+			Rules:   toShippingRulesResponse(&rules),
+			SetBy:   v.SetBy,
+			SetAt:   v.SetAt,
+			Active:  shipment.RulesVersionID != nil && *shipment.RulesVersionID == v.ID,
+			Changes: domainShipment.DiffRules(prev, &rules),
+		}
+		prev = &rules
+	}
+	return history
+}
+
+// notifyRulesUpdated tells the assigned shipper the rules they are about to
+// confirm have changed
+func (s *Service) notifyRulesUpdated(ctx context.Context, shipment *domainShipment.Shipment, changed int) {
+	if s.notifier == nil || shipment.ShipperID == nil {
+		return
+	}
+
+	s.notifyUser(ctx, *shipment.ShipperID, domainNotification.Message{
+		Event:    "shipment_rules_updated",
+		Severity: domainNotification.SeverityInfo,
+		Subject:  "Shipping rules changed",
+		Body: fmt.Sprintf("The provider changed %d shipping rule(s) of \"%s\". Review them before confirming.",
+			changed, shipment.GoodsDescription),
+		Link: "/shipments/" + shipment.ID.String() + "/rules/history",
+		Data: map[string]string{"shipment_id": shipment.ID.String()},
+	})
+}
@@ -0,0 +1,40 @@
+package shipment
+
+import (
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SandboxResetResponse reports what a sandbox reset removed
+type SandboxResetResponse struct {
+	DeletedShipments int64 `json:"deleted_shipments"`
+}
+
+// ResetSandbox wipes every sandbox shipment the account takes part in, giving
+// integration partners a clean slate. Only sandbox accounts can reset.
+func (s *Service) ResetSandbox(ctx context.Context, userID uuid.UUID) (*SandboxResetResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsSandbox {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Only sandbox accounts can reset sandbox data", nil)
+	}
+
+	deleted, err := s.shipmentRepo.DeleteSandboxData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Sandbox data reset",
+		zap.String("user_id", userID.String()),
+		zap.Int64("deleted_shipments", deleted),
+		zap.String("event", "sandbox_reset"),
+	)
+
+	return &SandboxResetResponse{DeletedShipments: deleted}, nil
+}
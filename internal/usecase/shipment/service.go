@@ -2,10 +2,22 @@ package shipment
 
 //
 import (
+	"cargo-tracker/internal/config"
+	domainCurrency "cargo-tracker/internal/domain/currency"
 	domainDevice "cargo-tracker/internal/domain/device"
+	domainDocument "cargo-tracker/internal/domain/document"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainQuota "cargo-tracker/internal/domain/quota"
+	domainReference "cargo-tracker/internal/domain/reference"
 	domainShipment "cargo-tracker/internal/domain/shipment"
+	domainStorage "cargo-tracker/internal/domain/storage"
 	domainUser "cargo-tracker/internal/domain/user"
 	"cargo-tracker/internal/logger"
+	"cargo-tracker/internal/usecase/job"
+	usecaseQuota "cargo-tracker/internal/usecase/quota"
+	usecaseReference "cargo-tracker/internal/usecase/reference"
+	usecaseUser "cargo-tracker/internal/usecase/user"
+	"cargo-tracker/pkg/cache"
 	appErrors "cargo-tracker/pkg/errors"
 	"cargo-tracker/pkg/utils"
 	"context"
@@ -21,6 +33,39 @@ type Service struct {
 	shipmentRepo domainShipment.Repository
 	userRepo     domainUser.Repository
 	deviceRepo   domainDevice.Repository
+	documentRepo domainDocument.Repository
+	notifier     domainNotification.Notifier
+	branding     *usecaseUser.BrandingService
+	store        domainStorage.Store
+
+	accessGrantRepo domainShipment.AccessGrantRepository
+	delegationRepo  domainShipment.DelegationRepository
+	noteRepo        domainShipment.NoteRepository
+	termsRepo       domainShipment.TermsRepository
+	calendarRepo    domainShipment.CalendarRepository
+	tripRepo        domainShipment.TripRepository
+	overrideRepo    domainShipment.OverrideRepository
+	addressBook     *usecaseUser.AddressBookService
+	reference       *usecaseReference.Service
+
+	riskScorer *RiskScorer
+	rates      domainCurrency.RateSource
+
+	statistics   *cache.Value[*ShipmentStatisticsResponse]
+	statsRefresh time.Duration
+
+	// events is the optional shipment event log; nil disables it
+	events domainShipment.EventRepository
+	// quotas caps the active shipments of each party and gates predictive
+	// alerts; nil disables it
+	quotas *usecaseQuota.Service
+	// selfTests, when set, requires a device to have passed a self-test
+	// within selfTestMaxAge before an order is accepted with it
+	selfTests      domainDevice.SelfTestRepository
+	selfTestMaxAge time.Duration
+
+	// jobs builds postmortem bundles; set by RegisterJobs
+	jobs *job.Service
 }
 
 // NewService creates a new shipment service
@@ -28,21 +73,80 @@ func NewService(
 	shipmentRepo domainShipment.Repository,
 	userRepo domainUser.Repository,
 	deviceRepo domainDevice.Repository,
+	documentRepo domainDocument.Repository,
+	notifier domainNotification.Notifier,
+	branding *usecaseUser.BrandingService,
+	store domainStorage.Store,
+	accessGrantRepo domainShipment.AccessGrantRepository,
+	delegationRepo domainShipment.DelegationRepository,
+	noteRepo domainShipment.NoteRepository,
+	termsRepo domainShipment.TermsRepository,
+	calendarRepo domainShipment.CalendarRepository,
+	tripRepo domainShipment.TripRepository,
+	overrideRepo domainShipment.OverrideRepository,
+	addressBook *usecaseUser.AddressBookService,
+	reference *usecaseReference.Service,
+	riskScorer *RiskScorer,
+	rates domainCurrency.RateSource,
+	statsCache config.StatsCacheConfig,
 ) *Service {
-	return &Service{
+	s := &Service{
 		shipmentRepo: shipmentRepo,
 		userRepo:     userRepo,
 		deviceRepo:   deviceRepo,
-	}
+		documentRepo: documentRepo,
+		notifier:     notifier,
+		branding:     branding,
+		store:        store,
+
+		accessGrantRepo: accessGrantRepo,
+		delegationRepo:  delegationRepo,
+		noteRepo:        noteRepo,
+		termsRepo:       termsRepo,
+		calendarRepo:    calendarRepo,
+		tripRepo:        tripRepo,
+		overrideRepo:    overrideRepo,
+		addressBook:     addressBook,
+		reference:       reference,
+
+		riskScorer: riskScorer,
+		rates:      rates,
+
+		statsRefresh: statsCache.RefreshInterval,
+	}
+	s.statistics = cache.NewValue(s.loadStatistics, statsCache.TTL, statsCache.Stale)
+	return s
+}
+
+// UseQuotas makes the service refuse to add a shipment to a party that is
+// already at the active shipments limit of their plan, and predictive alerts
+// to providers whose plan does not include them
+func (s *Service) UseQuotas(quotas *usecaseQuota.Service) {
+	s.quotas = quotas
+}
+
+// RequireSelfTest makes AcceptOrder refuse devices that have not passed a
+// self-test within maxAge
+func (s *Service) RequireSelfTest(selfTests domainDevice.SelfTestRepository, maxAge time.Duration) {
+	s.selfTests = selfTests
+	s.selfTestMaxAge = maxAge
 }
 
 // Step 1: Customer creates demand
 
-func (s *Service) CreateDemand(ctx context.Context, customerID uuid.UUID, req *CreateDemandRequest) (*ShipmentResponse, error) {
+func (s *Service) CreateDemand(ctx context.Context, userID uuid.UUID, req *CreateDemandRequest) (*ShipmentResponse, error) {
 	if err := utils.ValidateStruct(req); err != nil {
 		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
 	}
 
+	// A delegate books in the customer's name: the shipment, its quota and
+	// address book are the customer's, and the delegate is recorded as the
+	// one who booked it
+	customerID, bookedBy, err := s.bookingCustomer(ctx, userID, req.OnBehalfOf)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate parties
 	if err := ValidateParties(ctx, s.userRepo, customerID, req.ProviderID, nil); err != nil {
 		return nil, err
@@ -53,19 +157,58 @@ func (s *Service) CreateDemand(ctx context.Context, customerID uuid.UUID, req *C
 		return nil, err
 	}
 
+	if err := s.quotas.Check(ctx, customerID, domainQuota.MetricActiveShipments); err != nil {
+		return nil, err
+	}
+
+	customer, err := s.userRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Saved addresses take precedence over the free-text ones
+	pickupAddress, deliveryAddress := req.PickupAddress, req.DeliveryAddress
+	if req.PickupAddressID != nil {
+		saved, err := s.addressBook.ResolveAddress(ctx, customerID, *req.PickupAddressID)
+		if err != nil {
+			return nil, err
+		}
+		pickupAddress = saved.Formatted()
+	}
+	if req.DeliveryAddressID != nil {
+		saved, err := s.addressBook.ResolveAddress(ctx, customerID, *req.DeliveryAddressID)
+		if err != nil {
+			return nil, err
+		}
+		deliveryAddress = saved.Formatted()
+	}
+
+	goodsCurrency, goodsRate, err := s.goodsCurrency(ctx, req.ProviderID, req.GoodsCurrency, req.GoodsValue)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create domain entity
 	shipment := &domainShipment.Shipment{
 		CustomerID:          customerID,
 		ProviderID:          req.ProviderID,
+		BookedBy:            bookedBy,
 		Status:              domainShipment.StatusDemandCreated,
 		GoodsDescription:    req.GoodsDescription,
+		GoodsCategory:       req.GoodsCategory,
 		GoodsValue:          req.GoodsValue,
+		GoodsCurrency:       goodsCurrency,
+		GoodsValueRate:      goodsRate,
 		GoodsWeight:         req.GoodsWeight,
-		PickupAddress:       req.PickupAddress,
-		DeliveryAddress:     req.DeliveryAddress,
+		PickupAddress:       pickupAddress,
+		DeliveryAddress:     deliveryAddress,
+		PickupAddressID:     req.PickupAddressID,
+		DeliveryAddressID:   req.DeliveryAddressID,
 		EstimatedPickupAt:   req.EstimatedPickupAt,
 		EstimatedDeliveryAt: req.EstimatedDeliveryAt,
+		DeliveryDueAt:       s.deliveryDueAt(ctx, req.ProviderID, req.EstimatedDeliveryAt),
 		CustomerNotes:       req.CustomerNotes,
+		IsSandbox:           customer.IsSandbox,
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
@@ -74,6 +217,11 @@ func (s *Service) CreateDemand(ctx context.Context, customerID uuid.UUID, req *C
 	if err := s.shipmentRepo.Create(ctx, shipment); err != nil {
 		return nil, err
 	}
+	for _, addressID := range []*uuid.UUID{req.PickupAddressID, req.DeliveryAddressID} {
+		if addressID != nil {
+			s.addressBook.RecordUse(ctx, *addressID)
+		}
+	}
 
 	// Get created shipment
 	createdShipment, err := s.shipmentRepo.GetByID(ctx, shipment.ID)
@@ -85,9 +233,22 @@ func (s *Service) CreateDemand(ctx context.Context, customerID uuid.UUID, req *C
 		zap.String("shipment_id", createdShipment.ID.String()),
 		zap.String("customer_id", customerID.String()),
 		zap.String("provider_id", req.ProviderID.String()),
+		zap.String("acting_user_id", userID.String()),
 		zap.String("event", "shipment_demand_created"),
 	)
 
+	s.recordEvent(ctx, createdShipment.ID, domainShipment.EventCreated, &userID, domainShipment.EventData{
+		Status:              statusPtr(createdShipment.Status),
+		CustomerID:          &createdShipment.CustomerID,
+		ProviderID:          &createdShipment.ProviderID,
+		BookedBy:            createdShipment.BookedBy,
+		GoodsDescription:    &createdShipment.GoodsDescription,
+		PickupAddress:       &createdShipment.PickupAddress,
+		DeliveryAddress:     &createdShipment.DeliveryAddress,
+		EstimatedPickupAt:   createdShipment.EstimatedPickupAt,
+		EstimatedDeliveryAt: createdShipment.EstimatedDeliveryAt,
+	})
+
 	rules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, createdShipment.ID)
 	return ToShipmentResponse(createdShipment, rules), nil
 }
@@ -99,11 +260,6 @@ func (s *Service) PostOrder(ctx context.Context, shipmentID, providerID uuid.UUI
 		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
 	}
 
-	// Validate shipping rules
-	if err := ValidateShippingRules(req); err != nil {
-		return nil, err
-	}
-
 	// Get shipment
 	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
 	if err != nil {
@@ -115,11 +271,37 @@ func (s *Service) PostOrder(ctx context.Context, shipmentID, providerID uuid.UUI
 		return nil, appErrors.NewAppError("UNAUTHORIZED", "Provider does not own this shipment", nil)
 	}
 
+	// Validate shipping rules; without the goods categories only the
+	// category warnings are skipped
+	var categories []domainReference.GoodsCategory
+	if reference, err := s.reference.Data(ctx); err != nil {
+		logger.Warn("Goods categories unavailable for rule warnings",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.Error(err),
+		)
+	} else {
+		categories = reference.GoodsCategories
+	}
+	warnings, err := ValidateShippingRules(req, shipment, categories)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate status transition
 	if err := ValidateStatusTransition(shipment.Status, domainShipment.StatusOrderPosted); err != nil {
 		return nil, err
 	}
 
+	// Providers are charged for a shipment once they post it
+	if err := s.quotas.Check(ctx, providerID, domainQuota.MetricActiveShipments); err != nil {
+		return nil, err
+	}
+	if req.EnablePredictiveAlert {
+		if err := s.quotas.RequireFeature(ctx, providerID, domainQuota.FeaturePredictiveAlerts); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create shipping rules
 	rules := &domainShipment.ShippingRules{
 		ShipmentID:            shipmentID,
@@ -155,11 +337,19 @@ func (s *Service) PostOrder(ctx context.Context, shipmentID, providerID uuid.UUI
 	logger.Info("Order posted to marketplace",
 		zap.String("shipment_id", shipmentID.String()),
 		zap.String("provider_id", providerID.String()),
+		zap.Int("rule_warnings", len(warnings)),
 		zap.String("event", "order_posted"),
 	)
 
 	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
-	return ToShipmentResponse(updatedShipment, updatedRules), nil
+	s.recordEvent(ctx, shipmentID, domainShipment.EventRulesSet, &providerID, domainShipment.EventData{
+		Status: statusPtr(domainShipment.StatusOrderPosted),
+		Rules:  rules,
+	})
+	s.assessRisk(ctx, updatedShipment, updatedRules)
+	resp := ToShipmentResponse(updatedShipment, updatedRules)
+	resp.Warnings = warnings
+	return resp, nil
 }
 
 // Step 3: Shipper accepts order from marketplace
@@ -180,11 +370,32 @@ func (s *Service) AcceptOrder(ctx context.Context, shipmentID, shipperID uuid.UU
 		return nil, err
 	}
 
-	// Validate device
-	if err := ValidateDevice(ctx, s.deviceRepo, req.DeviceID, shipperID); err != nil {
+	// Get rules
+	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	if err != nil {
+		return nil, appErrors.NewAppError("RULES_NOT_FOUND", "Shipping rules not found", err)
+	}
+
+	// Validate device, including that it can measure what the rules monitor
+	if err := ValidateDevice(ctx, s.deviceRepo, req.DeviceID, shipperID, RequiredCapabilities(rules)); err != nil {
+		return nil, err
+	}
+	if err := s.checkSelfTest(ctx, req.DeviceID); err != nil {
+		return nil, err
+	}
+
+	if err := s.quotas.Check(ctx, shipperID, domainQuota.MetricActiveShipments); err != nil {
 		return nil, err
 	}
 
+	shipper, err := s.userRepo.GetByID(ctx, shipperID)
+	if err != nil {
+		return nil, err
+	}
+	if shipper.IsSandbox != shipment.IsSandbox {
+		return nil, errSandboxMismatch
+	}
+
 	// Assign shipper
 	if err := s.shipmentRepo.AssignShipper(ctx, shipmentID, shipperID); err != nil {
 		return nil, err
@@ -195,12 +406,6 @@ func (s *Service) AcceptOrder(ctx context.Context, shipmentID, shipperID uuid.UU
 		return nil, err
 	}
 
-	// Get rules
-	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
-	if err != nil {
-		return nil, appErrors.NewAppError("RULES_NOT_FOUND", "Shipping rules not found", err)
-	}
-
 	// Validate business rules
 	if err := ValidateBusinessRules(shipment, rules, domainShipment.StatusShippingAssigned); err != nil {
 		return nil, err
@@ -233,13 +438,23 @@ func (s *Service) AcceptOrder(ctx context.Context, shipmentID, shipperID uuid.UU
 		zap.String("event", "order_accepted"),
 	)
 
+	s.recordEvent(ctx, shipmentID, domainShipment.EventAccepted, &shipperID, domainShipment.EventData{
+		Status:    statusPtr(domainShipment.StatusShippingAssigned),
+		ShipperID: &shipperID,
+		DeviceID:  &req.DeviceID,
+	})
+
+	s.notifyShipmentAssigned(ctx, updatedShipment)
+
+	// Rescore now that shipper and device are known
 	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	s.assessRisk(ctx, updatedShipment, updatedRules)
 	return ToShipmentResponse(updatedShipment, updatedRules), nil
 }
 
 // Step 4: Shipper confirms rules
 
-func (s *Service) ConfirmRules(ctx context.Context, shipmentID, shipperID uuid.UUID) (*ShipmentResponse, error) {
+func (s *Service) ConfirmRules(ctx context.Context, shipmentID, shipperID uuid.UUID, terms *AcceptTermsInput) (*ShipmentResponse, error) {
 	// Get shipment
 	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
 	if err != nil {
@@ -251,6 +466,22 @@ func (s *Service) ConfirmRules(ctx context.Context, shipmentID, shipperID uuid.U
 		return nil, appErrors.ErrUnauthorized
 	}
 
+	rules, err := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		return nil, domainShipment.ErrRulesRequired
+	}
+	if rules.ConfirmedAt != nil {
+		return nil, appErrors.NewAppError("ALREADY_CONFIRMED", "Shipping rules have already been confirmed", nil)
+	}
+
+	// The provider's terms of carriage are signed together with the rules
+	if _, err := s.acceptTerms(ctx, shipment, shipperID, terms); err != nil {
+		return nil, err
+	}
+
 	// Confirm rules
 	if err := s.shipmentRepo.ConfirmRules(ctx, shipmentID, shipperID); err != nil {
 		return nil, err
@@ -268,6 +499,11 @@ func (s *Service) ConfirmRules(ctx context.Context, shipmentID, shipperID uuid.U
 		zap.String("event", "rules_confirmed"),
 	)
 
+	confirmed := true
+	s.recordEvent(ctx, shipmentID, domainShipment.EventConfirmed, &shipperID, domainShipment.EventData{
+		RulesConfirmed: &confirmed,
+	})
+
 	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
 	return ToShipmentResponse(updatedShipment, updatedRules), nil
 }
@@ -306,6 +542,16 @@ func (s *Service) StartShipping(ctx context.Context, shipmentID, shipperID uuid.
 		return nil, err
 	}
 
+	// Required documents must be verified before goods leave
+	pending, err := s.documentRepo.CountIncompleteRequired(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, appErrors.NewAppError("DOCUMENTS_INCOMPLETE",
+			fmt.Sprintf("%d required document(s) not yet verified", pending), domainDocument.ErrDocumentsIncomplete)
+	}
+
 	// Update shipment
 	pickupTime := time.Now()
 	if req.ActualPickupAt != nil {
@@ -330,6 +576,11 @@ func (s *Service) StartShipping(ctx context.Context, shipmentID, shipperID uuid.
 		zap.String("event", "shipping_started"),
 	)
 
+	s.recordEvent(ctx, shipmentID, domainShipment.EventStarted, &shipperID, domainShipment.EventData{
+		Status:         statusPtr(domainShipment.StatusInTransit),
+		ActualPickupAt: &pickupTime,
+	})
+
 	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
 	return ToShipmentResponse(updatedShipment, updatedRules), nil
 }
@@ -363,24 +614,28 @@ func (s *Service) CompleteDelivery(ctx context.Context, shipperID, shipmentID uu
 		deliveryTime = *req.ActualDeliveryAt
 	}
 
+	// Packages without a recorded exception count as delivered; any exception
+	// turns the verdict into a partial completion
+	verdict, err := s.resolveDeliveryVerdict(ctx, shipperID, shipmentID, deliveryTime)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := s.shipmentRepo.SetActualDelivery(ctx, shipmentID, deliveryTime, req.CompletionNotes); err != nil {
 		return nil, err
 	}
 
 	// Update status
-	if err := s.shipmentRepo.UpdateStatus(ctx, shipmentID, domainShipment.StatusCompleted); err != nil {
+	if err := s.shipmentRepo.UpdateStatus(ctx, shipmentID, verdict); err != nil {
 		return nil, err
 	}
 
-	// Update device status back to available
-	if shipment.LinkedDeviceID != nil {
-		if err := s.deviceRepo.UpdateStatus(ctx, *shipment.LinkedDeviceID, domainDevice.StatusAvailable); err != nil {
-			logger.Warn("Failed to update device status",
-				zap.String("device_id", shipment.LinkedDeviceID.String()),
-				zap.Error(err),
-			)
-		}
-	}
+	// A completed delivery proves the saved addresses it used
+	s.addressBook.MarkDelivered(ctx, shipment.PickupAddressID, shipment.DeliveryAddressID)
+
+	// The status update released the shipment's devices, except a trip
+	// device other stops still need
+	s.advanceTrip(ctx, shipmentID, deliveryTime)
 
 	// Get updated shipment
 	updatedShipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
@@ -390,9 +645,16 @@ func (s *Service) CompleteDelivery(ctx context.Context, shipperID, shipmentID uu
 
 	logger.Info("Delivery completed",
 		zap.String("shipment_id", shipmentID.String()),
+		zap.String("verdict", string(verdict)),
 		zap.String("event", "delivery_completed"),
 	)
 
+	s.recordEvent(ctx, shipmentID, domainShipment.EventCompleted, &shipperID, domainShipment.EventData{
+		Status:           statusPtr(verdict),
+		ActualDeliveryAt: &deliveryTime,
+		CompletionNotes:  req.CompletionNotes,
+	})
+
 	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
 	return ToShipmentResponse(updatedShipment, updatedRules), nil
 }
@@ -417,7 +679,7 @@ func (s *Service) RateDelivery(ctx context.Context, customerID, shipmentID uuid.
 	}
 
 	// Verify status
-	if shipment.Status != domainShipment.StatusCompleted {
+	if shipment.Status != domainShipment.StatusCompleted && shipment.Status != domainShipment.StatusPartiallyCompleted {
 		return nil, appErrors.NewAppError("INVALID_STATUS", "Can only rate completed deliveries", nil)
 	}
 
@@ -438,6 +700,10 @@ func (s *Service) RateDelivery(ctx context.Context, customerID, shipmentID uuid.
 		zap.Int("rating", req.Rating),
 	)
 
+	s.recordEvent(ctx, shipmentID, domainShipment.EventRated, &customerID, domainShipment.EventData{
+		CustomerRating: &req.Rating,
+	})
+
 	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
 
 	return ToShipmentResponse(updatedShipment, updatedRules), nil
@@ -488,6 +754,13 @@ func (s *Service) ReportIssue(ctx context.Context, reporterID, shipmentID uuid.U
 		zap.String("event", "issue_reported"),
 	)
 
+	s.recordEvent(ctx, shipmentID, domainShipment.EventIssueReported, &reporterID, domainShipment.EventData{
+		Status: statusPtr(domainShipment.StatusIssueReported),
+		Reason: &req.Description,
+	})
+
+	s.notifyIssueReported(ctx, updatedShipment, reporterID, req)
+
 	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
 	return ToShipmentResponse(updatedShipment, updatedRules), nil
 }
@@ -509,7 +782,7 @@ func (s *Service) CancelShipment(ctx context.Context, userID, shipmentID uuid.UU
 		shipment.ProviderID == userID ||
 		(shipment.ShipperID != nil && *shipment.ShipperID == userID)
 
-	if !isInvolved {
+	if !isInvolved && !s.isDelegateFor(ctx, shipment.CustomerID, userID) {
 		return nil, appErrors.ErrUnauthorized
 	}
 
@@ -528,15 +801,9 @@ func (s *Service) CancelShipment(ctx context.Context, userID, shipmentID uuid.UU
 		return nil, err
 	}
 
-	// Update device status back to available if assigned
-	if shipment.LinkedDeviceID != nil {
-		if err := s.deviceRepo.UpdateStatus(ctx, *shipment.LinkedDeviceID, domainDevice.StatusAvailable); err != nil {
-			logger.Warn("Failed to update device status",
-				zap.String("device_id", shipment.LinkedDeviceID.String()),
-				zap.Error(err),
-			)
-		}
-	}
+	// The status update released the shipment's devices, except a trip
+	// device other stops still need
+	s.advanceTrip(ctx, shipmentID, time.Now())
 
 	// Get updated shipment
 	updatedShipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
@@ -546,40 +813,53 @@ func (s *Service) CancelShipment(ctx context.Context, userID, shipmentID uuid.UU
 
 	logger.Info("Shipment cancelled",
 		zap.String("shipment_id", shipmentID.String()),
+		zap.String("customer_id", shipment.CustomerID.String()),
+		zap.String("acting_user_id", userID.String()),
 		zap.String("reason", req.Reason),
 		zap.String("event", "shipment_cancelled"),
 	)
 
+	s.recordEvent(ctx, shipmentID, domainShipment.EventCancelled, &userID, domainShipment.EventData{
+		Status: statusPtr(domainShipment.StatusCancelled),
+		Reason: &req.Reason,
+	})
+
 	updatedRules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
 	return ToShipmentResponse(updatedShipment, updatedRules), nil
 }
 
 func (s *Service) GetShipment(ctx context.Context, userID, shipmentID uuid.UUID) (*ShipmentDetailResponse, error) {
-	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	// Parties, device, rules, packages and terms acceptance in one round trip
+	shipmentDetail, err := s.shipmentRepo.GetDetail(ctx, shipmentID)
 	if err != nil {
 		return nil, err
 	}
+	shipment := shipmentDetail.Shipment
 
 	// Verify user has access
-	isAuthorized := shipment.CustomerID == userID ||
-		shipment.ProviderID == userID ||
-		(shipment.ShipperID != nil && *shipment.ShipperID == userID)
-
-	if !isAuthorized {
-		// Check if user is admin
-		user, err := s.userRepo.GetByID(ctx, userID)
-		if err != nil || user.Role != "admin" {
-			return nil, appErrors.ErrUnauthorized
-		}
+	if err := s.authorizeViewer(ctx, shipment, userID, domainShipment.ScopeDetails); err != nil {
+		return nil, err
 	}
 
-	rules, _ := s.shipmentRepo.GetRulesByShipmentID(ctx, shipmentID)
+	rules := shipmentDetail.Rules
 	response := ToShipmentResponse(shipment, rules)
 
-	return &ShipmentDetailResponse{
+	// Tracking views render in the provider's branding; fall back to the
+	// platform look if it cannot be loaded
+	branding, _ := s.branding.GetBranding(ctx, shipment.ProviderID)
+
+	detail := &ShipmentDetailResponse{
 		ShipmentResponse: response,
 		Rules:            toShippingRulesResponse(rules),
-	}, nil
+		StatusHistory:    s.statusHistory(ctx, shipmentID),
+		Packages:         ToPackageResponses(shipmentDetail.Packages),
+		Branding:         branding,
+	}
+	if shipmentDetail.TermsAcceptance != nil {
+		detail.TermsAcceptance = ToTermsAcceptanceResponse(shipmentDetail.TermsAcceptance)
+	}
+
+	return detail, nil
 }
 
 func (s *Service) ListShipments(ctx context.Context, userID uuid.UUID, userRole string, filter *ShipmentFilterRequest) (*ShipmentListResponse, error) {
@@ -594,10 +874,20 @@ func (s *Service) ListShipments(ctx context.Context, userID uuid.UUID, userRole
 		filter.PageSize = 100
 	}
 
-	if userRole != "admin" {
+	// Sandbox accounts only see sandbox data and everyone else only live data
+	viewer, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admins and analysts see every shipment
+	if userRole != "admin" && userRole != "analyst" {
 		switch userRole {
 		case "customer":
-			filter.CustomerID = &userID
+			// Delegates may list the shipments of a customer they book for
+			if filter.CustomerID == nil || !s.isDelegateFor(ctx, *filter.CustomerID, userID) {
+				filter.CustomerID = &userID
+			}
 		case "provider":
 			filter.ProviderID = &userID
 		case "shipper":
@@ -607,6 +897,7 @@ func (s *Service) ListShipments(ctx context.Context, userID uuid.UUID, userRole
 
 	// Convert to domain filter
 	domainFilter := ToDomainFilter(filter)
+	domainFilter.Sandbox = &viewer.IsSandbox
 
 	// Get shipments from repository
 	shipments, total, err := s.shipmentRepo.List(ctx, domainFilter)
@@ -636,7 +927,7 @@ func (s *Service) ListShipments(ctx context.Context, userID uuid.UUID, userRole
 	}, nil
 }
 
-func (s *Service) GetMarketplaceListings(ctx context.Context, page, pageSize int) (*ShipmentListResponse, error) {
+func (s *Service) GetMarketplaceListings(ctx context.Context, shipperID uuid.UUID, page, pageSize int) (*ShipmentListResponse, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -647,7 +938,12 @@ func (s *Service) GetMarketplaceListings(ctx context.Context, page, pageSize int
 		pageSize = 100
 	}
 
-	shipments, total, err := s.shipmentRepo.GetMarketplaceListings(ctx, page, pageSize)
+	shipper, err := s.userRepo.GetByID(ctx, shipperID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments, total, err := s.shipmentRepo.GetMarketplaceListings(ctx, shipper.IsSandbox, page, pageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -673,13 +969,28 @@ func (s *Service) GetMarketplaceListings(ctx context.Context, page, pageSize int
 	}, nil
 }
 
+// GetStatistics returns the platform-wide shipment statistics. They are
+// cached because every dashboard polls them and each load aggregates the
+// whole shipments table.
 func (s *Service) GetStatistics(ctx context.Context) (*ShipmentStatisticsResponse, error) {
+	return s.statistics.Get(ctx)
+}
+
+// StartStatisticsRefresher keeps the cached statistics fresh while they are
+// being polled
+func (s *Service) StartStatisticsRefresher(ctx context.Context) {
+	s.statistics.StartRefresher(ctx, s.statsRefresh)
+}
+
+func (s *Service) loadStatistics(ctx context.Context) (*ShipmentStatisticsResponse, error) {
 	stats, err := s.shipmentRepo.GetStatistics(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return ToStatisticsResponse(stats), nil
+	resp := ToStatisticsResponse(stats)
+	resp.RevenueCurrency = s.rates.ReportingCurrency()
+	return resp, nil
 }
 
 // Helper function
@@ -704,5 +1015,6 @@ func toShippingRulesResponse(rules *domainShipment.ShippingRules) *ShippingRules
 		ConfirmedByShipperID:  rules.ConfirmedByShipperID,
 		SetAt:                 rules.SetAt,
 		ConfirmedAt:           rules.ConfirmedAt,
+		RequiredCapabilities:  RequiredCapabilities(rules),
 	}
 }
@@ -0,0 +1,261 @@
+package shipment
+
+import (
+	"bytes"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxSignatureBytes bounds uploaded signature images
+const MaxSignatureBytes = 256 << 10
+
+var signatureExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+}
+
+type PublishTermsRequest struct {
+	Title string `json:"title" validate:"required,min=3,max=255"`
+	Body  string `json:"body" validate:"required,min=20,max=100000"`
+}
+
+type CarriageTermsResponse struct {
+	ID         uuid.UUID `json:"id"`
+	ProviderID uuid.UUID `json:"provider_id"`
+	Version    int       `json:"version"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type TermsAcceptanceResponse struct {
+	TermsID      uuid.UUID `json:"terms_id"`
+	TermsVersion int       `json:"terms_version"`
+	ShipperID    uuid.UUID `json:"shipper_id"`
+	IPAddress    string    `json:"ip_address"`
+	UserAgent    *string   `json:"user_agent,omitempty"`
+	SignatureURL string    `json:"signature_url"`
+	AcceptedAt   time.Time `json:"accepted_at"`
+}
+
+// ShipmentTermsResponse shows the terms that apply to a shipment and, once
+// signed, the shipper's acceptance
+type ShipmentTermsResponse struct {
+	Terms      *CarriageTermsResponse   `json:"terms"`
+	Acceptance *TermsAcceptanceResponse `json:"acceptance,omitempty"`
+}
+
+// AcceptTermsInput carries the shipper's signed acceptance of the provider's
+// terms of carriage, captured with the request metadata
+type AcceptTermsInput struct {
+	TermsID   uuid.UUID
+	Signature io.Reader
+	IPAddress string
+	UserAgent string
+}
+
+func ToCarriageTermsResponse(t *domainShipment.CarriageTerms) *CarriageTermsResponse {
+	return &CarriageTermsResponse{
+		ID:         t.ID,
+		ProviderID: t.ProviderID,
+		Version:    t.Version,
+		Title:      t.Title,
+		Body:       t.Body,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+func ToTermsAcceptanceResponse(a *domainShipment.TermsAcceptance) *TermsAcceptanceResponse {
+	return &TermsAcceptanceResponse{
+		TermsID:      a.TermsID,
+		TermsVersion: a.TermsVersion,
+		ShipperID:    a.ShipperID,
+		IPAddress:    a.IPAddress,
+		UserAgent:    a.UserAgent,
+		SignatureURL: fmt.Sprintf("/api/v1/shipments/%s/terms/signature", a.ShipmentID),
+		AcceptedAt:   a.AcceptedAt,
+	}
+}
+
+// PublishTerms adds a new version of the provider's terms of carriage. Orders
+// accepted afterwards must be signed against it.
+func (s *Service) PublishTerms(ctx context.Context, providerID uuid.UUID, req *PublishTermsRequest) (*CarriageTermsResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	terms := &domainShipment.CarriageTerms{
+		ProviderID: providerID,
+		Title:      req.Title,
+		Body:       req.Body,
+	}
+	if err := s.termsRepo.CreateTerms(ctx, terms); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Terms of carriage published",
+		zap.String("provider_id", providerID.String()),
+		zap.String("terms_id", terms.ID.String()),
+		zap.Int("version", terms.Version),
+		zap.String("event", "terms_published"),
+	)
+
+	return ToCarriageTermsResponse(terms), nil
+}
+
+// ListTerms returns every published version of the provider's terms, newest first
+func (s *Service) ListTerms(ctx context.Context, providerID uuid.UUID) ([]*CarriageTermsResponse, error) {
+	terms, err := s.termsRepo.ListTerms(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*CarriageTermsResponse, len(terms))
+	for i, t := range terms {
+		responses[i] = ToCarriageTermsResponse(t)
+	}
+	return responses, nil
+}
+
+// GetShipmentTerms returns the terms the shipper signed for the shipment, or
+// the provider's current terms while it is still unsigned
+func (s *Service) GetShipmentTerms(ctx context.Context, userID, shipmentID uuid.UUID) (*ShipmentTermsResponse, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeViewer(ctx, shipment, userID, domainShipment.ScopeDocuments); err != nil {
+		return nil, err
+	}
+
+	acceptance, err := s.termsRepo.GetAcceptance(ctx, shipmentID)
+	if err != nil && !errors.Is(err, domainShipment.ErrTermsAcceptanceNotFound) {
+		return nil, err
+	}
+
+	var terms *domainShipment.CarriageTerms
+	if acceptance != nil {
+		terms, err = s.termsRepo.GetTerms(ctx, acceptance.TermsID)
+	} else {
+		terms, err = s.termsRepo.GetLatestTerms(ctx, shipment.ProviderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ShipmentTermsResponse{Terms: ToCarriageTermsResponse(terms)}
+	if acceptance != nil {
+		response.Acceptance = ToTermsAcceptanceResponse(acceptance)
+	}
+	return response, nil
+}
+
+// OpenTermsSignature returns the signature image of the shipment's terms
+// acceptance. The caller must close the reader.
+func (s *Service) OpenTermsSignature(ctx context.Context, userID, shipmentID uuid.UUID) (io.ReadCloser, string, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.authorizeViewer(ctx, shipment, userID, domainShipment.ScopeDocuments); err != nil {
+		return nil, "", err
+	}
+
+	acceptance, err := s.termsRepo.GetAcceptance(ctx, shipmentID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := s.store.Open(ctx, acceptance.SignatureKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return content, acceptance.SignatureContentType, nil
+}
+
+// acceptTerms checks the shipper signed the provider's current terms and
+// records the acceptance. Providers without published terms need no signature.
+func (s *Service) acceptTerms(ctx context.Context, shipment *domainShipment.Shipment, shipperID uuid.UUID, input *AcceptTermsInput) (*domainShipment.TermsAcceptance, error) {
+	terms, err := s.termsRepo.GetLatestTerms(ctx, shipment.ProviderID)
+	if errors.Is(err, domainShipment.ErrTermsNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if input == nil || input.Signature == nil {
+		return nil, appErrors.NewAppError("TERMS_NOT_ACCEPTED", "The provider's terms of carriage must be accepted and signed", nil)
+	}
+	if input.TermsID != terms.ID {
+		return nil, appErrors.NewAppError("TERMS_OUTDATED", fmt.Sprintf("Version %d of the terms of carriage must be accepted", terms.Version), nil)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(input.Signature, MaxSignatureBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+	if len(data) > MaxSignatureBytes {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", fmt.Sprintf("Signature must be at most %d KB", MaxSignatureBytes>>10), nil)
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := signatureExtensions[contentType]
+	if !ok {
+		return nil, appErrors.NewAppError("UNSUPPORTED_FILE_TYPE", "Signature must be a PNG or JPEG image", nil)
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Signature image is corrupted", err)
+	}
+
+	key := fmt.Sprintf("shipments/%s/terms/signature-%d%s", shipment.ID, time.Now().Unix(), ext)
+	if _, err := s.store.Put(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		return nil, fmt.Errorf("failed to store signature: %w", err)
+	}
+
+	acceptance := &domainShipment.TermsAcceptance{
+		ShipmentID:           shipment.ID,
+		TermsID:              terms.ID,
+		TermsVersion:         terms.Version,
+		ShipperID:            shipperID,
+		IPAddress:            input.IPAddress,
+		SignatureKey:         key,
+		SignatureContentType: contentType,
+		AcceptedAt:           time.Now(),
+	}
+	if input.UserAgent != "" {
+		userAgent := input.UserAgent
+		if len(userAgent) > 500 {
+			userAgent = userAgent[:500]
+		}
+		acceptance.UserAgent = &userAgent
+	}
+	if err := s.termsRepo.CreateAcceptance(ctx, acceptance); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+
+	logger.Info("Terms of carriage accepted",
+		zap.String("shipment_id", shipment.ID.String()),
+		zap.String("shipper_id", shipperID.String()),
+		zap.String("terms_id", terms.ID.String()),
+		zap.Int("version", terms.Version),
+		zap.String("ip_address", input.IPAddress),
+		zap.String("event", "terms_accepted"),
+	)
+
+	return acceptance, nil
+}
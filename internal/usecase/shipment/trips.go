@@ -0,0 +1,347 @@
+package shipment
+
+import (
+	domainDevice "cargo-tracker/internal/domain/device"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CreateTripRequest struct {
+	Name     *string   `json:"name" validate:"omitempty,max=100"`
+	DeviceID uuid.UUID `json:"device_id" validate:"required"`
+	// Member shipments in delivery order
+	ShipmentIDs []uuid.UUID `json:"shipment_ids" validate:"required,min=2,max=20,dive,required"`
+}
+
+type TripStopResponse struct {
+	ID              uuid.UUID                     `json:"id"`
+	Sequence        int                           `json:"sequence"`
+	ShipmentID      uuid.UUID                     `json:"shipment_id"`
+	ShipmentStatus  domainShipment.ShipmentStatus `json:"shipment_status,omitempty"`
+	DeliveryAddress string                        `json:"delivery_address,omitempty"`
+	CompletedAt     *time.Time                    `json:"completed_at,omitempty"`
+}
+
+type TripResponse struct {
+	ID           uuid.UUID                 `json:"id"`
+	Name         *string                   `json:"name,omitempty"`
+	Status       domainShipment.TripStatus `json:"status"`
+	DeviceID     uuid.UUID                 `json:"device_id"`
+	Stops        []TripStopResponse        `json:"stops"`
+	PendingStops int                       `json:"pending_stops"`
+	CompletedAt  *time.Time                `json:"completed_at,omitempty"`
+	CreatedAt    time.Time                 `json:"created_at"`
+}
+
+// CreateTrip groups assigned shipments of a shipper into a multi-stop trip.
+// The trip device is linked to every member shipment, replacing their own
+// devices, which are released. Either all of it is stored or none of it.
+func (s *Service) CreateTrip(ctx context.Context, shipperID uuid.UUID, req *CreateTripRequest) (*TripResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.ShipmentIDs))
+	members := make([]*domainShipment.Shipment, 0, len(req.ShipmentIDs))
+	for _, shipmentID := range req.ShipmentIDs {
+		if seen[shipmentID] {
+			return nil, appErrors.NewAppError("VALIDATION_ERROR", "A shipment can only be one stop of a trip", nil)
+		}
+		seen[shipmentID] = true
+
+		member, err := s.getTripCandidate(ctx, shipperID, shipmentID)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) > 0 && member.IsSandbox != members[0].IsSandbox {
+			return nil, errSandboxMismatch
+		}
+		members = append(members, member)
+	}
+
+	device, err := s.deviceRepo.GetByID(ctx, req.DeviceID)
+	if err != nil {
+		return nil, appErrors.NewAppError("DEVICE_NOT_FOUND", "Device not found", err)
+	}
+	if err := s.validateTripDevice(ctx, shipperID, device, members); err != nil {
+		return nil, err
+	}
+
+	trip := &domainShipment.Trip{
+		ShipperID: shipperID,
+		DeviceID:  device.ID,
+		Name:      req.Name,
+		Status:    domainShipment.TripActive,
+		Stops:     make([]domainShipment.TripStop, len(members)),
+	}
+	for i, member := range members {
+		trip.Stops[i] = domainShipment.TripStop{ShipmentID: member.ID, Sequence: i + 1}
+	}
+	// The trip, its stops and the device moves are stored in one transaction
+	if err := s.tripRepo.Create(ctx, trip); err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		if member.LinkedDeviceID != nil && *member.LinkedDeviceID == device.ID {
+			continue
+		}
+		s.recordEvent(ctx, member.ID, domainShipment.EventDeviceLinked, &shipperID, domainShipment.EventData{
+			DeviceID: &device.ID,
+		})
+	}
+
+	logger.Info("Trip created",
+		zap.String("trip_id", trip.ID.String()),
+		zap.String("shipper_id", shipperID.String()),
+		zap.String("device_id", device.ID.String()),
+		zap.Int("stops", len(trip.Stops)),
+		zap.String("event", "trip_created"),
+	)
+
+	return s.toTripResponse(ctx, trip), nil
+}
+
+// ListTrips returns the trips of a shipper, optionally filtered by status
+func (s *Service) ListTrips(ctx context.Context, shipperID uuid.UUID, status string) ([]*TripResponse, error) {
+	var filter *domainShipment.TripStatus
+	switch domainShipment.TripStatus(status) {
+	case "":
+	case domainShipment.TripActive, domainShipment.TripCompleted, domainShipment.TripCancelled:
+		st := domainShipment.TripStatus(status)
+		filter = &st
+	default:
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid trip status", nil)
+	}
+
+	trips, err := s.tripRepo.ListByShipper(ctx, shipperID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*TripResponse, len(trips))
+	for i, trip := range trips {
+		resp[i] = s.toTripResponse(ctx, trip)
+	}
+	return resp, nil
+}
+
+// GetTrip returns a trip of the shipper with the state of each stop
+func (s *Service) GetTrip(ctx context.Context, shipperID, tripID uuid.UUID) (*TripResponse, error) {
+	trip, err := s.getOwnedTrip(ctx, shipperID, tripID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toTripResponse(ctx, trip), nil
+}
+
+// CompleteTripStop completes the delivery of one stop. Stops can be completed
+// in any order; the trip completes with its last stop.
+func (s *Service) CompleteTripStop(ctx context.Context, shipperID, tripID, stopID uuid.UUID, req *CompleteDeliveryRequest) (*TripResponse, error) {
+	trip, err := s.getOwnedTrip(ctx, shipperID, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Status != domainShipment.TripActive {
+		return nil, appErrors.NewAppError("INVALID_STATUS", "Trip is no longer active", nil)
+	}
+
+	var stop *domainShipment.TripStop
+	for i := range trip.Stops {
+		if trip.Stops[i].ID == stopID {
+			stop = &trip.Stops[i]
+		}
+	}
+	if stop == nil {
+		return nil, domainShipment.ErrTripNotFound
+	}
+	if stop.CompletedAt != nil {
+		return nil, appErrors.NewAppError("ALREADY_COMPLETED", "Stop is already completed", nil)
+	}
+
+	if _, err := s.CompleteDelivery(ctx, shipperID, stop.ShipmentID, req); err != nil {
+		return nil, err
+	}
+
+	return s.GetTrip(ctx, shipperID, tripID)
+}
+
+// advanceTrip closes the trip stop of a finished shipment, and the trip
+// once no stop is pending
+func (s *Service) advanceTrip(ctx context.Context, shipmentID uuid.UUID, at time.Time) {
+	trip, err := s.tripRepo.FindActiveByShipment(ctx, shipmentID)
+	if errors.Is(err, domainShipment.ErrTripNotFound) {
+		return
+	}
+	if err != nil {
+		logger.Warn("Failed to find trip of shipment",
+			zap.String("shipment_id", shipmentID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	stop, _ := trip.Stop(shipmentID)
+	if stop.CompletedAt == nil {
+		if err := s.tripRepo.CompleteStop(ctx, stop.ID, at); err != nil {
+			logger.Warn("Failed to complete trip stop",
+				zap.String("trip_id", trip.ID.String()),
+				zap.String("shipment_id", shipmentID.String()),
+				zap.Error(err),
+			)
+		}
+		stop.CompletedAt = &at
+	}
+
+	if len(trip.PendingStops()) > 0 {
+		return
+	}
+
+	if err := s.tripRepo.UpdateStatus(ctx, trip.ID, domainShipment.TripCompleted, at); err != nil {
+		logger.Warn("Failed to complete trip",
+			zap.String("trip_id", trip.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	logger.Info("Trip completed",
+		zap.String("trip_id", trip.ID.String()),
+		zap.String("shipper_id", trip.ShipperID.String()),
+		zap.String("event", "trip_completed"),
+	)
+}
+
+// resolveTripDevice maps a trip device to the member shipments it reports for
+func (s *Service) resolveTripDevice(ctx context.Context, deviceID uuid.UUID) (*domainShipment.DeviceAssignment, error) {
+	trip, err := s.tripRepo.FindActiveByDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := trip.PendingStops()
+	if len(pending) == 0 {
+		return nil, domainShipment.ErrDeviceNotAssigned
+	}
+
+	next, err := s.shipmentRepo.GetByID(ctx, pending[0].ShipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &domainShipment.DeviceAssignment{
+		DeviceID:        deviceID,
+		ShipmentID:      next.ID,
+		Status:          next.Status,
+		TripID:          &trip.ID,
+		TripShipmentIDs: make([]uuid.UUID, len(pending)),
+	}
+	for i, stop := range pending {
+		assignment.TripShipmentIDs[i] = stop.ShipmentID
+	}
+	return assignment, nil
+}
+
+func (s *Service) getTripCandidate(ctx context.Context, shipperID, shipmentID uuid.UUID) (*domainShipment.Shipment, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.ShipperID == nil || *shipment.ShipperID != shipperID {
+		return nil, appErrors.NewAppError("UNAUTHORIZED", "Shipper does not own this shipment", nil)
+	}
+
+	switch shipment.Status {
+	case domainShipment.StatusShippingAssigned, domainShipment.StatusInTransit:
+	default:
+		return nil, appErrors.NewAppError("INVALID_STATUS", "Only assigned or in-transit shipments can join a trip", nil)
+	}
+
+	_, err = s.tripRepo.FindActiveByShipment(ctx, shipmentID)
+	if err == nil {
+		return nil, appErrors.NewAppError("ALREADY_IN_TRIP", "Shipment is already part of an active trip", nil)
+	}
+	if !errors.Is(err, domainShipment.ErrTripNotFound) {
+		return nil, err
+	}
+
+	return shipment, nil
+}
+
+// validateTripDevice checks that the shipper may use the device for the trip:
+// it must be free, or already tracking one of the member shipments
+func (s *Service) validateTripDevice(ctx context.Context, shipperID uuid.UUID, device *domainDevice.Device, members []*domainShipment.Shipment) error {
+	if device.OwnerShipperID != nil && *device.OwnerShipperID != shipperID {
+		return appErrors.NewAppError("DEVICE_OWNER_MISMATCH", "Device owner does not match shipper", nil)
+	}
+
+	switch device.Status {
+	case domainDevice.StatusAvailable:
+	case domainDevice.StatusInTransit:
+		tracksMember := false
+		for _, member := range members {
+			if member.LinkedDeviceID != nil && *member.LinkedDeviceID == device.ID {
+				tracksMember = true
+			}
+		}
+		if !tracksMember {
+			return appErrors.NewAppError("DEVICE_UNAVAILABLE", "Device is tracking another shipment", nil)
+		}
+	default:
+		return appErrors.NewAppError("DEVICE_UNAVAILABLE", "Device is not available for assignment", nil)
+	}
+
+	_, err := s.tripRepo.FindActiveByDevice(ctx, device.ID)
+	if err == nil {
+		return appErrors.NewAppError("DEVICE_UNAVAILABLE", "Device is already used by an active trip", nil)
+	}
+	if !errors.Is(err, domainShipment.ErrTripNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *Service) getOwnedTrip(ctx context.Context, shipperID, tripID uuid.UUID) (*domainShipment.Trip, error) {
+	trip, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.ShipperID != shipperID {
+		// Do not reveal trips of other shippers
+		return nil, domainShipment.ErrTripNotFound
+	}
+	return trip, nil
+}
+
+func (s *Service) toTripResponse(ctx context.Context, trip *domainShipment.Trip) *TripResponse {
+	resp := &TripResponse{
+		ID:           trip.ID,
+		Name:         trip.Name,
+		Status:       trip.Status,
+		DeviceID:     trip.DeviceID,
+		Stops:        make([]TripStopResponse, len(trip.Stops)),
+		PendingStops: len(trip.PendingStops()),
+		CompletedAt:  trip.CompletedAt,
+		CreatedAt:    trip.CreatedAt,
+	}
+	for i, stop := range trip.Stops {
+		resp.Stops[i] = TripStopResponse{
+			ID:          stop.ID,
+			Sequence:    stop.Sequence,
+			ShipmentID:  stop.ShipmentID,
+			CompletedAt: stop.CompletedAt,
+		}
+		if shipment, err := s.shipmentRepo.GetByID(ctx, stop.ShipmentID); err == nil {
+			resp.Stops[i].ShipmentStatus = shipment.Status
+			resp.Stops[i].DeliveryAddress = shipment.DeliveryAddress
+		}
+	}
+	return resp
+}
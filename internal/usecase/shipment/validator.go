@@ -2,11 +2,13 @@ package shipment
 
 import (
 	domainDevice "cargo-tracker/internal/domain/device"
+	domainReference "cargo-tracker/internal/domain/reference"
 	domainShipment "cargo-tracker/internal/domain/shipment"
 	domainUser "cargo-tracker/internal/domain/user"
 	appErrors "cargo-tracker/pkg/errors"
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,17 +31,22 @@ func ValidateStatusTransition(currentStatus, newStatus domainShipment.ShipmentSt
 		},
 		domainShipment.StatusInTransit: {
 			domainShipment.StatusCompleted,
+			domainShipment.StatusPartiallyCompleted,
 			domainShipment.StatusIssueReported,
 			domainShipment.StatusCancelled,
 		},
 		domainShipment.StatusIssueReported: {
 			domainShipment.StatusInTransit,
 			domainShipment.StatusCompleted,
+			domainShipment.StatusPartiallyCompleted,
 			domainShipment.StatusCancelled,
 		},
 		domainShipment.StatusCompleted: {
 			// Terminal state - no transitions
 		},
+		domainShipment.StatusPartiallyCompleted: {
+			// Terminal state - no transitions
+		},
 		domainShipment.StatusCancelled: {
 			// Terminal state - no transitions
 		},
@@ -84,16 +91,19 @@ func GetAllowedTransitions(currentStatus domainShipment.ShipmentStatus) []domain
 		},
 		domainShipment.StatusInTransit: {
 			domainShipment.StatusCompleted,
+			domainShipment.StatusPartiallyCompleted,
 			domainShipment.StatusIssueReported,
 			domainShipment.StatusCancelled,
 		},
 		domainShipment.StatusIssueReported: {
 			domainShipment.StatusInTransit,
 			domainShipment.StatusCompleted,
+			domainShipment.StatusPartiallyCompleted,
 			domainShipment.StatusCancelled,
 		},
-		domainShipment.StatusCompleted: {},
-		domainShipment.StatusCancelled: {},
+		domainShipment.StatusCompleted:          {},
+		domainShipment.StatusPartiallyCompleted: {},
+		domainShipment.StatusCancelled:          {},
 	}
 	return validTransitions[currentStatus]
 }
@@ -186,6 +196,9 @@ func ValidateBusinessRules(shipment *domainShipment.Shipment, rules *domainShipm
 	return nil
 }
 
+// errSandboxMismatch keeps sandbox and live accounts from sharing a shipment
+var errSandboxMismatch = appErrors.NewAppError("SANDBOX_MISMATCH", "Sandbox and live accounts cannot share a shipment", nil)
+
 // ValidateParties validates customer, provider, and shipper
 func ValidateParties(ctx context.Context, userRepo domainUser.Repository, customerID, providerID uuid.UUID, shipperID *uuid.UUID) error {
 	// Validate customer
@@ -216,6 +229,9 @@ func ValidateParties(ctx context.Context, userRepo domainUser.Repository, custom
 	if customerID == providerID {
 		return appErrors.NewAppError("SAME_PARTY", "Customer and provider must be different users", nil)
 	}
+	if customer.IsSandbox != provider.IsSandbox {
+		return errSandboxMismatch
+	}
 
 	// Validate shipper if provided
 	if shipperID != nil {
@@ -234,13 +250,16 @@ func ValidateParties(ctx context.Context, userRepo domainUser.Repository, custom
 		if *shipperID == customerID || *shipperID == providerID {
 			return appErrors.NewAppError("SAME_PARTY", "Shipper must be different from customer and provider", nil)
 		}
+		if shipper.IsSandbox != customer.IsSandbox {
+			return errSandboxMismatch
+		}
 	}
 
 	return nil
 }
 
 // ValidateDevice validates device assignment
-func ValidateDevice(ctx context.Context, deviceRepo domainDevice.Repository, deviceID uuid.UUID, shipperID uuid.UUID) error {
+func ValidateDevice(ctx context.Context, deviceRepo domainDevice.Repository, deviceID uuid.UUID, shipperID uuid.UUID, required []domainDevice.Capability) error {
 	device, err := deviceRepo.GetByID(ctx, deviceID)
 	if err != nil {
 		return appErrors.NewAppError("DEVICE_NOT_FOUND", "Device not found", err)
@@ -256,31 +275,93 @@ func ValidateDevice(ctx context.Context, deviceRepo domainDevice.Repository, dev
 		return appErrors.NewAppError("DEVICE_OWNER_MISMATCH", "Device owner does not match shipper", nil)
 	}
 
+	// Devices of a model without a profile are let through; what they can
+	// measure is not known
+	if device.Capabilities != nil {
+		if missing := domainDevice.Missing(device.Capabilities, required); len(missing) > 0 {
+			return appErrors.NewAppError("DEVICE_INCAPABLE",
+				fmt.Sprintf("Device cannot measure %s required by the shipping rules", joinCapabilities(missing)), nil)
+		}
+	}
+
 	return nil
 }
 
-// ValidateShippingRules validates quality control rules
-func ValidateShippingRules(rules *PostOrderRequest) error {
+// checkSelfTest refuses a device without a recent passed self-test, when
+// self-tests are required
+func (s *Service) checkSelfTest(ctx context.Context, deviceID uuid.UUID) error {
+	if s.selfTests == nil {
+		return nil
+	}
+
+	passedAt, err := s.selfTests.LastPassedAt(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if passedAt == nil || time.Since(*passedAt) > s.selfTestMaxAge {
+		return appErrors.NewAppError("SELF_TEST_REQUIRED",
+			fmt.Sprintf("Device must pass a self-test within %s before it can be assigned", s.selfTestMaxAge), nil)
+	}
+	return nil
+}
+
+// RequiredCapabilities returns what a device must measure to monitor the rules
+func RequiredCapabilities(rules *domainShipment.ShippingRules) []domainDevice.Capability {
+	if rules == nil {
+		return nil
+	}
+
+	var required []domainDevice.Capability
+	if rules.TempMin != nil || rules.TempMax != nil {
+		required = append(required, domainDevice.CapabilityTemperature)
+	}
+	if rules.HumidityMin != nil || rules.HumidityMax != nil {
+		required = append(required, domainDevice.CapabilityHumidity)
+	}
+	if rules.LightMax != nil {
+		required = append(required, domainDevice.CapabilityLight)
+	}
+	if rules.TiltMaxAngle != nil {
+		required = append(required, domainDevice.CapabilityTilt)
+	}
+	if rules.ImpactThresholdG != nil {
+		required = append(required, domainDevice.CapabilityImpact)
+	}
+	return required
+}
+
+func joinCapabilities(capabilities []domainDevice.Capability) string {
+	names := make([]string, len(capabilities))
+	for i, c := range capabilities {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ValidateShippingRules validates quality control rules. Rules that are
+// invalid fail with an error; rules that are valid but look unusual for the
+// shipment are returned as warnings and do not block posting.
+func ValidateShippingRules(rules *PostOrderRequest, shipment *domainShipment.Shipment, categories []domainReference.GoodsCategory) ([]RuleWarning, error) {
 	// Temperature range check
 	if rules.TempMin != nil && rules.TempMax != nil {
 		if *rules.TempMin >= *rules.TempMax {
-			return appErrors.NewAppError("INVALID_RULES", "Temperature minimum must be less than maximum", nil)
+			return nil, appErrors.NewAppError("INVALID_RULES", "Temperature minimum must be less than maximum", nil)
 		}
 	}
 
 	// Humidity range check
 	if rules.HumidityMin != nil && rules.HumidityMax != nil {
 		if *rules.HumidityMin >= *rules.HumidityMax {
-			return appErrors.NewAppError("INVALID_RULES", "Humidity minimum must be less than maximum", nil)
+			return nil, appErrors.NewAppError("INVALID_RULES", "Humidity minimum must be less than maximum", nil)
 		}
 	}
 
 	// Report cycle validation
 	if rules.ReportCycleSec < 10 || rules.ReportCycleSec > 300 {
-		return appErrors.NewAppError("INVALID_RULES", "Report cycle must be between 10 and 300 seconds", nil)
+		return nil, appErrors.NewAppError("INVALID_RULES", "Report cycle must be between 10 and 300 seconds", nil)
 	}
 
-	return nil
+	return ruleWarnings(rules, shipment, categories), nil
 }
 
 // ValidateTimeRange validates pickup and delivery times
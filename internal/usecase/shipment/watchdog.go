@@ -0,0 +1,306 @@
+package shipment
+
+import (
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainShipment "cargo-tracker/internal/domain/shipment"
+	"cargo-tracker/internal/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// watchdogBatchSize bounds the shipments handled per status and stage on each
+// check; the rest are picked up by the next one
+const watchdogBatchSize = 100
+
+// stuckStatus describes an intermediate status the watchdog looks after
+type stuckStatus struct {
+	status domainShipment.ShipmentStatus
+	after  time.Duration
+	// pending describes what the responsible party still has to do
+	pending string
+}
+
+// StartWatchdog looks for shipments stuck in an intermediate status every
+// cfg.Interval until ctx is cancelled. The party expected to act is reminded
+// first; if nothing happens the other parties and operations are told, and a
+// stuck shipper assignment can finally be released back to the marketplace.
+// Each stage is recorded before it is acted on, so it happens at most once
+// per stay in a status even with several instances running.
+// When cfg.ReleaseStaleDevices is set it also frees devices still held by
+// shipments that have finished.
+func (s *Service) StartWatchdog(ctx context.Context, cfg config.WatchdogConfig) {
+	if cfg.Interval <= 0 {
+		return
+	}
+
+	statuses := []stuckStatus{
+		{domainShipment.StatusDemandCreated, cfg.DemandCreatedAfter, "post the order to the marketplace"},
+		{domainShipment.StatusOrderPosted, cfg.OrderPostedAfter, "find a shipper, for example by adjusting the order"},
+		{domainShipment.StatusShippingAssigned, cfg.ShippingAssignedAfter, "confirm the shipping rules and start shipping"},
+		{domainShipment.StatusIssueReported, cfg.IssueReportedAfter, "resolve the reported issue"},
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, st := range statuses {
+				if st.after > 0 {
+					s.checkStuck(ctx, st, cfg)
+				}
+			}
+			if cfg.ReleaseStaleDevices {
+				s.releaseStaleDevices(ctx)
+			}
+		}
+	}
+}
+
+// releaseStaleDevices reconciles devices with the shipments they track.
+// Terminal transitions release devices themselves, so every device found
+// here points at a gap and is logged as such.
+func (s *Service) releaseStaleDevices(ctx context.Context) {
+	released, err := s.shipmentRepo.ReleaseStaleDevices(ctx, watchdogBatchSize)
+	if err != nil {
+		logger.Error("Failed to release stale devices", zap.Error(err))
+		return
+	}
+
+	for _, d := range released {
+		fields := []zap.Field{
+			zap.String("device_id", d.DeviceID.String()),
+			zap.String("device_status", d.DeviceStatus),
+			zap.String("event", "stale_device_released"),
+		}
+		if d.ShipmentID != nil {
+			fields = append(fields, zap.String("shipment_id", d.ShipmentID.String()))
+		}
+		if d.ShipmentStatus != nil {
+			fields = append(fields, zap.String("shipment_status", string(*d.ShipmentStatus)))
+		}
+		logger.Warn("Stale device released", fields...)
+	}
+}
+
+func (s *Service) checkStuck(ctx context.Context, st stuckStatus, cfg config.WatchdogConfig) {
+	now := time.Now()
+
+	s.runWatchdogStage(ctx, &domainShipment.StuckQuery{
+		Status:        st.status,
+		ChangedBefore: now.Add(-st.after),
+		Stage:         domainShipment.WatchdogReminded,
+		Limit:         watchdogBatchSize,
+	}, func(shipment *domainShipment.Shipment) {
+		s.remindStuck(ctx, shipment, st)
+	})
+
+	if cfg.EscalateAfter <= 0 {
+		return
+	}
+	s.runWatchdogStage(ctx, &domainShipment.StuckQuery{
+		Status:         st.status,
+		ChangedBefore:  now.Add(-st.after - cfg.EscalateAfter),
+		Stage:          domainShipment.WatchdogEscalated,
+		Previous:       domainShipment.WatchdogReminded,
+		PreviousBefore: now.Add(-cfg.EscalateAfter),
+		Limit:          watchdogBatchSize,
+	}, func(shipment *domainShipment.Shipment) {
+		s.escalateStuck(ctx, shipment, st)
+	})
+
+	if !cfg.RevertAssignments || st.status != domainShipment.StatusShippingAssigned {
+		return
+	}
+	s.runWatchdogStage(ctx, &domainShipment.StuckQuery{
+		Status:         st.status,
+		ChangedBefore:  now.Add(-st.after - 2*cfg.EscalateAfter),
+		Stage:          domainShipment.WatchdogReverted,
+		Previous:       domainShipment.WatchdogEscalated,
+		PreviousBefore: now.Add(-cfg.EscalateAfter),
+		Limit:          watchdogBatchSize,
+	}, func(shipment *domainShipment.Shipment) {
+		s.revertStuckAssignment(ctx, shipment)
+	})
+}
+
+// runWatchdogStage claims the stage for every matching shipment and acts on
+// the ones it claimed
+func (s *Service) runWatchdogStage(ctx context.Context, query *domainShipment.StuckQuery, act func(*domainShipment.Shipment)) {
+	shipments, err := s.shipmentRepo.ListStuck(ctx, query)
+	if err != nil {
+		logger.Error("Failed to list stuck shipments",
+			zap.String("status", string(query.Status)),
+			zap.String("stage", string(query.Stage)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, shipment := range shipments {
+		claimed, err := s.shipmentRepo.RecordWatchdogStage(ctx, shipment, query.Stage)
+		if err != nil {
+			logger.Error("Failed to record watchdog stage",
+				zap.String("shipment_id", shipment.ID.String()),
+				zap.String("stage", string(query.Stage)),
+				zap.Error(err),
+			)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		logger.Info("Shipment stuck in status",
+			zap.String("shipment_id", shipment.ID.String()),
+			zap.String("status", string(shipment.Status)),
+			zap.Duration("stuck_for", time.Since(shipment.StatusChangedAt).Round(time.Minute)),
+			zap.String("stage", string(query.Stage)),
+			zap.String("event", "shipment_stuck"),
+		)
+		act(shipment)
+	}
+}
+
+// remindStuck asks the party the shipment is waiting on to act
+func (s *Service) remindStuck(ctx context.Context, shipment *domainShipment.Shipment, st stuckStatus) {
+	if s.notifier == nil {
+		return
+	}
+
+	msg := stuckMessage(shipment, "shipment_stuck_reminder", domainNotification.SeverityInfo)
+	msg.Subject = fmt.Sprintf("Shipment waiting on you since %s", shipment.StatusChangedAt.Format("2006-01-02 15:04"))
+	msg.Body = fmt.Sprintf("Shipment \"%s\" has been %s for %s. Please %s.",
+		shipment.GoodsDescription, statusLabel(shipment.Status), stuckFor(shipment), st.pending)
+
+	if responsible := stuckResponsible(shipment); responsible != nil {
+		s.notifyUser(ctx, *responsible, msg)
+	}
+}
+
+// escalateStuck tells the other parties and operations that the reminder
+// did not help
+func (s *Service) escalateStuck(ctx context.Context, shipment *domainShipment.Shipment, st stuckStatus) {
+	if s.notifier == nil {
+		return
+	}
+
+	msg := stuckMessage(shipment, "shipment_stuck_escalated", domainNotification.SeverityCritical)
+	msg.Subject = fmt.Sprintf("Shipment stuck as %s", statusLabel(shipment.Status))
+	msg.Body = fmt.Sprintf("Shipment \"%s\" has been %s for %s. The party responsible was reminded but did not %s.",
+		shipment.GoodsDescription, statusLabel(shipment.Status), stuckFor(shipment), st.pending)
+
+	responsible := stuckResponsible(shipment)
+	for _, userID := range []uuid.UUID{shipment.CustomerID, shipment.ProviderID} {
+		if responsible == nil || userID != *responsible {
+			s.notifyUser(ctx, userID, msg)
+		}
+	}
+
+	// Operational alert for platform channels, not addressed to any user
+	s.notify(ctx, &msg)
+}
+
+// revertStuckAssignment releases a shipment its shipper never started back
+// to the marketplace, freeing the device for other shipments
+func (s *Service) revertStuckAssignment(ctx context.Context, shipment *domainShipment.Shipment) {
+	// Trip members share the trip's device, which must not be released here
+	if _, err := s.tripRepo.FindActiveByShipment(ctx, shipment.ID); !errors.Is(err, domainShipment.ErrTripNotFound) {
+		logger.Warn("Stuck shipment not reverted",
+			zap.String("shipment_id", shipment.ID.String()),
+			zap.String("reason", "part of an active trip or trip lookup failed"),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if err := s.shipmentRepo.RevertAssignment(ctx, shipment.ID); err != nil {
+		if !errors.Is(err, domainShipment.ErrShipmentNotFound) {
+			logger.Error("Failed to revert stuck shipment",
+				zap.String("shipment_id", shipment.ID.String()),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+	s.releasePackageDevices(ctx, shipment.ID)
+
+	logger.Info("Stuck shipment returned to the marketplace",
+		zap.String("shipment_id", shipment.ID.String()),
+		zap.Any("shipper_id", shipment.ShipperID),
+		zap.String("event", "shipment_assignment_reverted"),
+	)
+
+	reason := "assignment was not started in time"
+	s.recordEvent(ctx, shipment.ID, domainShipment.EventReverted, nil, domainShipment.EventData{
+		Status:          statusPtr(domainShipment.StatusOrderPosted),
+		ClearAssignment: true,
+		RulesConfirmed:  new(bool),
+		Reason:          &reason,
+	})
+
+	if s.notifier == nil {
+		return
+	}
+	msg := stuckMessage(shipment, "shipment_assignment_reverted", domainNotification.SeverityInfo)
+	msg.Subject = "Shipment returned to the marketplace"
+	msg.Body = fmt.Sprintf("Shipment \"%s\" was not started %s after it was assigned, so it is open to other shippers again.",
+		shipment.GoodsDescription, stuckFor(shipment))
+	if shipment.ShipperID != nil {
+		s.notifyUser(ctx, *shipment.ShipperID, msg)
+	}
+	s.notifyUser(ctx, shipment.ProviderID, msg)
+}
+
+func stuckMessage(shipment *domainShipment.Shipment, event string, severity domainNotification.Severity) domainNotification.Message {
+	return domainNotification.Message{
+		Event:    event,
+		Severity: severity,
+		Link:     "/shipments/" + shipment.ID.String(),
+		Data: map[string]string{
+			"shipment_id":       shipment.ID.String(),
+			"status":            string(shipment.Status),
+			"status_changed_at": shipment.StatusChangedAt.Format(time.RFC3339),
+		},
+		Sandbox: shipment.IsSandbox,
+	}
+}
+
+// stuckResponsible returns who the shipment is waiting on
+func stuckResponsible(shipment *domainShipment.Shipment) *uuid.UUID {
+	if shipment.Status == domainShipment.StatusShippingAssigned {
+		return shipment.ShipperID
+	}
+	return &shipment.ProviderID
+}
+
+func stuckFor(shipment *domainShipment.Shipment) string {
+	hours := int(time.Since(shipment.StatusChangedAt).Hours())
+	if hours < 48 {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d days", hours/24)
+}
+
+func statusLabel(status domainShipment.ShipmentStatus) string {
+	switch status {
+	case domainShipment.StatusDemandCreated:
+		return "waiting to be posted"
+	case domainShipment.StatusOrderPosted:
+		return "waiting for a shipper"
+	case domainShipment.StatusShippingAssigned:
+		return "assigned but not started"
+	case domainShipment.StatusIssueReported:
+		return "on hold with an open issue"
+	}
+	return string(status)
+}
@@ -0,0 +1,232 @@
+package user
+
+import (
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxSavedAddresses bounds the size of a user's address book
+	MaxSavedAddresses = 200
+	// AddressStaleAfter is how long an address can go unused before it is
+	// reported as stale
+	AddressStaleAfter = 180 * 24 * time.Hour
+)
+
+// AddressBookService manages saved addresses that customers and providers
+// reuse when creating shipments
+type AddressBookService struct {
+	addressRepo domainUser.AddressRepository
+}
+
+// NewAddressBookService creates a new address book service
+func NewAddressBookService(addressRepo domainUser.AddressRepository) *AddressBookService {
+	return &AddressBookService{addressRepo: addressRepo}
+}
+
+// ListAddresses returns the address book of a user with reuse figures
+func (s *AddressBookService) ListAddresses(ctx context.Context, ownerID uuid.UUID) (*AddressBookResponse, error) {
+	addresses, err := s.addressRepo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &AddressBookResponse{
+		Addresses: make([]AddressResponse, len(addresses)),
+		Total:     len(addresses),
+	}
+	staleBefore := time.Now().Add(-AddressStaleAfter)
+	for i, a := range addresses {
+		resp.Addresses[i] = *toAddressResponse(a)
+		resp.TotalUses += a.UseCount
+		if a.Status == domainUser.AddressVerified {
+			resp.Verified++
+		}
+		if a.HasCoordinates() {
+			resp.Geocoded++
+		}
+		lastActive := a.CreatedAt
+		if a.LastUsedAt != nil {
+			lastActive = *a.LastUsedAt
+		}
+		if lastActive.Before(staleBefore) {
+			resp.Stale++
+		}
+	}
+	return resp, nil
+}
+
+// GetAddress returns one saved address of a user
+func (s *AddressBookService) GetAddress(ctx context.Context, ownerID, addressID uuid.UUID) (*AddressResponse, error) {
+	address, err := s.ResolveAddress(ctx, ownerID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	return toAddressResponse(address), nil
+}
+
+// CreateAddress adds an address to the book. New addresses are unverified
+// until a shipment to or from them completes.
+func (s *AddressBookService) CreateAddress(ctx context.Context, ownerID uuid.UUID, req *SaveAddressRequest) (*AddressResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	existing, err := s.addressRepo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxSavedAddresses {
+		return nil, appErrors.NewAppError("ADDRESS_LIMIT_REACHED", fmt.Sprintf("At most %d addresses can be saved", MaxSavedAddresses), nil)
+	}
+
+	address := &domainUser.SavedAddress{
+		OwnerID: ownerID,
+		Status:  domainUser.AddressUnverified,
+	}
+	applyAddressRequest(address, req)
+
+	if err := s.addressRepo.Create(ctx, address); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Address saved",
+		zap.String("address_id", address.ID.String()),
+		zap.String("owner_id", ownerID.String()),
+		zap.String("event", "address_saved"),
+	)
+
+	return toAddressResponse(address), nil
+}
+
+// UpdateAddress replaces a saved address. Changing where it is, rather than
+// only its label or contact, makes it unverified again.
+func (s *AddressBookService) UpdateAddress(ctx context.Context, ownerID, addressID uuid.UUID, req *SaveAddressRequest) (*AddressResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	address, err := s.ResolveAddress(ctx, ownerID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := locationKey(address)
+	applyAddressRequest(address, req)
+	if locationKey(address) != before {
+		address.Status = domainUser.AddressUnverified
+		address.VerifiedAt = nil
+	}
+
+	if err := s.addressRepo.Update(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return toAddressResponse(address), nil
+}
+
+// DeleteAddress removes an address from the book. Shipments created from it
+// keep their copy of the address.
+func (s *AddressBookService) DeleteAddress(ctx context.Context, ownerID, addressID uuid.UUID) error {
+	if _, err := s.ResolveAddress(ctx, ownerID, addressID); err != nil {
+		return err
+	}
+	return s.addressRepo.Delete(ctx, addressID)
+}
+
+// ResolveAddress returns a saved address of the owner
+func (s *AddressBookService) ResolveAddress(ctx context.Context, ownerID, addressID uuid.UUID) (*domainUser.SavedAddress, error) {
+	address, err := s.addressRepo.GetByID(ctx, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if address.OwnerID != ownerID {
+		// Do not reveal addresses of other users
+		return nil, domainUser.ErrAddressNotFound
+	}
+	return address, nil
+}
+
+// RecordUse counts a shipment created from a saved address. Failures are
+// logged; analytics must not block shipment creation.
+func (s *AddressBookService) RecordUse(ctx context.Context, addressID uuid.UUID) {
+	if err := s.addressRepo.RecordUse(ctx, addressID, time.Now()); err != nil {
+		logger.Warn("Failed to record address use",
+			zap.String("address_id", addressID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// MarkDelivered verifies the saved addresses of a shipment that completed
+func (s *AddressBookService) MarkDelivered(ctx context.Context, addressIDs ...*uuid.UUID) {
+	ids := make([]uuid.UUID, 0, len(addressIDs))
+	for _, id := range addressIDs {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	if err := s.addressRepo.MarkVerified(ctx, ids, time.Now()); err != nil {
+		logger.Warn("Failed to verify saved addresses", zap.Error(err))
+	}
+}
+
+func applyAddressRequest(a *domainUser.SavedAddress, req *SaveAddressRequest) {
+	a.Label = strings.TrimSpace(req.Label)
+	a.Line1 = strings.TrimSpace(req.Line1)
+	a.Line2 = req.Line2
+	a.Ward = req.Ward
+	a.District = req.District
+	a.City = strings.TrimSpace(req.City)
+	a.Province = req.Province
+	a.PostalCode = req.PostalCode
+	a.CountryCode = strings.ToUpper(req.CountryCode)
+	a.Latitude = req.Latitude
+	a.Longitude = req.Longitude
+	a.ContactName = req.ContactName
+	a.ContactPhone = req.ContactPhone
+}
+
+// locationKey identifies where an address is, ignoring label and contact
+func locationKey(a *domainUser.SavedAddress) string {
+	key := strings.ToLower(a.Formatted())
+	if a.HasCoordinates() {
+		key += fmt.Sprintf("|%.6f,%.6f", *a.Latitude, *a.Longitude)
+	}
+	return key
+}
+
+func toAddressResponse(a *domainUser.SavedAddress) *AddressResponse {
+	return &AddressResponse{
+		ID:           a.ID,
+		Label:        a.Label,
+		Line1:        a.Line1,
+		Line2:        a.Line2,
+		Ward:         a.Ward,
+		District:     a.District,
+		City:         a.City,
+		Province:     a.Province,
+		PostalCode:   a.PostalCode,
+		CountryCode:  a.CountryCode,
+		Formatted:    a.Formatted(),
+		Latitude:     a.Latitude,
+		Longitude:    a.Longitude,
+		ContactName:  a.ContactName,
+		ContactPhone: a.ContactPhone,
+		Status:       a.Status,
+		VerifiedAt:   a.VerifiedAt,
+		UseCount:     a.UseCount,
+		LastUsedAt:   a.LastUsedAt,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
@@ -0,0 +1,281 @@
+package user
+
+import (
+	"bytes"
+	domainStorage "cargo-tracker/internal/domain/storage"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxLogoBytes bounds uploaded logos; they are embedded in every label
+	MaxLogoBytes = 512 << 10
+	// MaxLogoDimension bounds the width and height of uploaded logos in pixels
+	MaxLogoDimension = 1024
+)
+
+var logoExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+}
+
+// BrandingService manages provider branding shown on tracking links and
+// exported documents
+type BrandingService struct {
+	brandingRepo domainUser.BrandingRepository
+	userRepo     domainUser.Repository
+	store        domainStorage.Store
+}
+
+// NewBrandingService creates a new branding service
+func NewBrandingService(
+	brandingRepo domainUser.BrandingRepository,
+	userRepo domainUser.Repository,
+	store domainStorage.Store,
+) *BrandingService {
+	return &BrandingService{
+		brandingRepo: brandingRepo,
+		userRepo:     userRepo,
+		store:        store,
+	}
+}
+
+// GetBranding returns the branding of a provider. Providers that never set
+// one up get their account name and the platform defaults.
+func (s *BrandingService) GetBranding(ctx context.Context, providerID uuid.UUID) (*BrandingResponse, error) {
+	provider, err := s.getProvider(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	branding, err := s.brandingRepo.Get(ctx, providerID)
+	if errors.Is(err, domainUser.ErrBrandingNotFound) {
+		branding = &domainUser.Branding{ProviderID: providerID, UpdatedAt: provider.UpdatedAt}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return toBrandingResponse(branding, provider), nil
+}
+
+// UpdateBranding replaces the colors, display name and support contact of a
+// provider. Fields left empty fall back to the defaults.
+func (s *BrandingService) UpdateBranding(ctx context.Context, providerID uuid.UUID, req *UpdateBrandingRequest) (*BrandingResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	provider, err := s.getProvider(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	branding, err := s.loadOrNew(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	branding.DisplayName = req.DisplayName
+	branding.PrimaryColor = lowerPtr(req.PrimaryColor)
+	branding.AccentColor = lowerPtr(req.AccentColor)
+	branding.SupportEmail = req.SupportEmail
+	branding.SupportPhone = req.SupportPhone
+	branding.SupportURL = req.SupportURL
+
+	if err := s.brandingRepo.Save(ctx, branding); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Provider branding updated",
+		zap.String("provider_id", providerID.String()),
+		zap.String("event", "provider_branding_updated"),
+	)
+
+	return toBrandingResponse(branding, provider), nil
+}
+
+// UploadLogo stores a PNG or JPEG logo in the file storage backend,
+// replacing the previous one
+func (s *BrandingService) UploadLogo(ctx context.Context, providerID uuid.UUID, file io.Reader) (*BrandingResponse, error) {
+	provider, err := s.getProvider(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxLogoBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logo: %w", err)
+	}
+	if len(data) > MaxLogoBytes {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", fmt.Sprintf("Logo must be at most %d KB", MaxLogoBytes>>10), nil)
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := logoExtensions[contentType]
+	if !ok {
+		return nil, appErrors.NewAppError("UNSUPPORTED_FILE_TYPE", "Logo must be a PNG or JPEG image", nil)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Logo image is corrupted", err)
+	}
+	if cfg.Width > MaxLogoDimension || cfg.Height > MaxLogoDimension {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", fmt.Sprintf("Logo must be at most %dx%d pixels", MaxLogoDimension, MaxLogoDimension), nil)
+	}
+
+	branding, err := s.loadOrNew(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A new key per upload keeps cached logo URLs from serving a stale image
+	key := fmt.Sprintf("branding/%s/logo-%d%s", providerID, time.Now().Unix(), ext)
+	if _, err := s.store.Put(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		return nil, fmt.Errorf("failed to store logo: %w", err)
+	}
+
+	previous := branding.LogoKey
+	branding.LogoKey = &key
+	branding.LogoContentType = &contentType
+	if err := s.brandingRepo.Save(ctx, branding); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+	if previous != nil && *previous != key {
+		if err := s.store.Delete(ctx, *previous); err != nil {
+			logger.Warn("Failed to delete previous logo", zap.String("key", *previous), zap.Error(err))
+		}
+	}
+
+	logger.Info("Provider logo uploaded",
+		zap.String("provider_id", providerID.String()),
+		zap.Int("size_bytes", len(data)),
+		zap.String("event", "provider_logo_uploaded"),
+	)
+
+	return toBrandingResponse(branding, provider), nil
+}
+
+// DeleteLogo removes the logo of a provider
+func (s *BrandingService) DeleteLogo(ctx context.Context, providerID uuid.UUID) error {
+	branding, err := s.brandingRepo.Get(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	if !branding.HasLogo() {
+		return domainStorage.ErrObjectNotFound
+	}
+
+	key := *branding.LogoKey
+	branding.LogoKey = nil
+	branding.LogoContentType = nil
+	if err := s.brandingRepo.Save(ctx, branding); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, key); err != nil {
+		logger.Warn("Failed to delete logo", zap.String("key", key), zap.Error(err))
+	}
+
+	return nil
+}
+
+// OpenLogo returns the logo of a provider and its content type. The caller
+// must close the reader.
+func (s *BrandingService) OpenLogo(ctx context.Context, providerID uuid.UUID) (io.ReadCloser, string, error) {
+	branding, err := s.brandingRepo.Get(ctx, providerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !branding.HasLogo() {
+		return nil, "", domainStorage.ErrObjectNotFound
+	}
+
+	content, err := s.store.Open(ctx, *branding.LogoKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := "application/octet-stream"
+	if branding.LogoContentType != nil {
+		contentType = *branding.LogoContentType
+	}
+	return content, contentType, nil
+}
+
+// LoadLogo reads the whole logo of a provider for embedding in documents. It
+// returns nil when the provider has no logo.
+func (s *BrandingService) LoadLogo(ctx context.Context, providerID uuid.UUID) ([]byte, error) {
+	content, _, err := s.OpenLogo(ctx, providerID)
+	if errors.Is(err, domainUser.ErrBrandingNotFound) || errors.Is(err, domainStorage.ErrObjectNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	return io.ReadAll(io.LimitReader(content, MaxLogoBytes))
+}
+
+func (s *BrandingService) getProvider(ctx context.Context, providerID uuid.UUID) (*domainUser.User, error) {
+	provider, err := s.userRepo.GetByID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if provider.Role != "provider" {
+		return nil, domainUser.ErrBrandingNotFound
+	}
+	return provider, nil
+}
+
+func (s *BrandingService) loadOrNew(ctx context.Context, providerID uuid.UUID) (*domainUser.Branding, error) {
+	branding, err := s.brandingRepo.Get(ctx, providerID)
+	if errors.Is(err, domainUser.ErrBrandingNotFound) {
+		return &domainUser.Branding{ProviderID: providerID}, nil
+	}
+	return branding, err
+}
+
+func toBrandingResponse(b *domainUser.Branding, provider *domainUser.User) *BrandingResponse {
+	resp := &BrandingResponse{
+		ProviderID:   b.ProviderID,
+		DisplayName:  provider.FullName,
+		PrimaryColor: b.PrimaryColor,
+		AccentColor:  b.AccentColor,
+		SupportEmail: b.SupportEmail,
+		SupportPhone: b.SupportPhone,
+		SupportURL:   b.SupportURL,
+		UpdatedAt:    b.UpdatedAt,
+	}
+	if b.DisplayName != nil && *b.DisplayName != "" {
+		resp.DisplayName = *b.DisplayName
+	}
+	if b.HasLogo() {
+		logoURL := fmt.Sprintf("/api/v1/branding/%s/logo?v=%d", b.ProviderID, b.UpdatedAt.Unix())
+		resp.LogoURL = &logoURL
+	}
+	return resp
+}
+
+func lowerPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	lower := strings.ToLower(*s)
+	return &lower
+}
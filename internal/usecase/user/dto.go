@@ -14,8 +14,11 @@ type RegisterRequest struct {
 	ConfirmPassword string  `json:"confirm_password" validate:"required,eqfield=Password"`
 	FullName        string  `json:"full_name" validate:"required,min=2,max=255"`
 	PhoneNumber     *string `json:"phone_number" validate:"omitempty,phone"`
-	Role            string  `json:"role" validate:"required,user_role"`
-	Address         *string `json:"address" validate:"omitempty,max=500"`
+	// Self-registration is for customers; other roles come from InviteToken
+	Role        string  `json:"role" validate:"omitempty,ref=role"`
+	Address     *string `json:"address" validate:"omitempty,max=500"`
+	Sandbox     bool    `json:"sandbox"`
+	InviteToken string  `json:"invite_token" validate:"omitempty,max=100"`
 }
 
 type LoginRequest struct {
@@ -33,28 +36,82 @@ type ResetPasswordRequest struct {
 	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
 }
 
+type ForgotPasswordPhoneRequest struct {
+	PhoneNumber string `json:"phone_number" validate:"required,phone"`
+}
+
+type ResetPasswordPhoneRequest struct {
+	PhoneNumber     string `json:"phone_number" validate:"required,phone"`
+	Code            string `json:"code" validate:"required,len=6,numeric"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
+}
+
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
 type ChangePasswordRequest struct {
 	OldPassword     string `json:"old_password" validate:"required"`
 	NewPassword     string `json:"new_password" validate:"required,min=8"`
 	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
 }
 
+// ChangeRoleRequest is used by admins, e.g. to grant the read-only analyst role
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,ref=role"`
+}
+
 type UpdateProfileRequest struct {
 	FullName    *string `json:"full_name" validate:"omitempty,min=2,max=255"`
 	PhoneNumber *string `json:"phone_number" validate:"omitempty,phone"`
 	Address     *string `json:"address" validate:"omitempty,max=500"`
+	// Providers only: ISO 4217 currency of shipments that do not name one
+	DefaultCurrency *string `json:"default_currency" validate:"omitempty,len=3,alpha"`
+}
+
+// UserFilterRequest filters, sorts and pages the admin user listing
+type UserFilterRequest struct {
+	Role             string     `form:"role" validate:"omitempty,ref=role"`
+	IsActive         *bool      `form:"is_active"`
+	RegisteredAfter  *time.Time `form:"registered_after"`
+	RegisteredBefore *time.Time `form:"registered_before"`
+	// Search matches name and email, ignoring case and accents
+	Search string `form:"search" validate:"omitempty,max=255"`
+
+	// Pagination
+	Page      int    `form:"page" validate:"omitempty,min=1"`
+	PageSize  int    `form:"page_size" validate:"omitempty,min=1,max=100"`
+	SortBy    string `form:"sort_by" validate:"omitempty,oneof=created_at full_name email role"`
+	SortOrder string `form:"sort_order" validate:"omitempty,oneof=asc desc"`
+}
+
+type UserListResponse struct {
+	Users      []*UserResponse `json:"users"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"page_size"`
+	TotalPages int             `json:"total_pages"`
+}
+
+type UserCountResponse struct {
+	Total int64 `json:"total"`
 }
 
 type UserResponse struct {
-	ID             uuid.UUID `json:"id"`
-	Username       string    `json:"username"`
-	Email          string    `json:"email"`
-	FullName       string    `json:"full_name"`
-	PhoneNumber    *string   `json:"phone_number"`
-	Role           string    `json:"role"`
-	DefaultAddress *string   `json:"default_address"`
-	IsActive       bool      `json:"is_active"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID              uuid.UUID  `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	FullName        string     `json:"full_name"`
+	PhoneNumber     *string    `json:"phone_number"`
+	PhoneVerified   *time.Time `json:"phone_verified_at,omitempty"`
+	Role            string     `json:"role"`
+	DefaultAddress  *string    `json:"default_address"`
+	IsActive        bool       `json:"is_active"`
+	IsSandbox       bool       `json:"is_sandbox,omitempty"`
+	Plan            string     `json:"plan"`
+	DefaultCurrency *string    `json:"default_currency,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 type AuthResponse struct {
@@ -62,6 +119,8 @@ type AuthResponse struct {
 	AccessToken  string        `json:"access_token"`
 	RefreshToken string        `json:"refresh_token"`
 	ExpiresAt    int64         `json:"expires_at"`
+	// Sessions signed out because the role's concurrent session cap was reached
+	DisplacedSessions int `json:"displaced_sessions,omitempty"`
 }
 
 func ToUserResponse(u *domainUser.User) *UserResponse {
@@ -69,14 +128,173 @@ func ToUserResponse(u *domainUser.User) *UserResponse {
 		return nil
 	}
 	return &UserResponse{
-		ID:             u.ID,
-		Username:       u.Username,
-		Email:          u.Email,
-		FullName:       u.FullName,
-		PhoneNumber:    u.PhoneNumber,
-		Role:           u.Role,
-		DefaultAddress: u.Address,
-		IsActive:       u.IsActive,
-		CreatedAt:      u.CreatedAt,
+		ID:              u.ID,
+		Username:        u.Username,
+		Email:           u.Email,
+		FullName:        u.FullName,
+		PhoneNumber:     u.PhoneNumber,
+		PhoneVerified:   u.PhoneVerified,
+		Role:            u.Role,
+		DefaultAddress:  u.Address,
+		IsActive:        u.IsActive,
+		IsSandbox:       u.IsSandbox,
+		Plan:            u.Plan,
+		DefaultCurrency: u.DefaultCurrency,
+		CreatedAt:       u.CreatedAt,
+	}
+}
+
+// Branding DTOs
+type UpdateBrandingRequest struct {
+	DisplayName  *string `json:"display_name" validate:"omitempty,min=2,max=255"`
+	PrimaryColor *string `json:"primary_color" validate:"omitempty,len=7,hexcolor"`
+	AccentColor  *string `json:"accent_color" validate:"omitempty,len=7,hexcolor"`
+	SupportEmail *string `json:"support_email" validate:"omitempty,email,max=255"`
+	SupportPhone *string `json:"support_phone" validate:"omitempty,phone"`
+	SupportURL   *string `json:"support_url" validate:"omitempty,url,max=500"`
+}
+
+type BrandingResponse struct {
+	ProviderID   uuid.UUID `json:"provider_id"`
+	DisplayName  string    `json:"display_name"`
+	PrimaryColor *string   `json:"primary_color,omitempty"`
+	AccentColor  *string   `json:"accent_color,omitempty"`
+	SupportEmail *string   `json:"support_email,omitempty"`
+	SupportPhone *string   `json:"support_phone,omitempty"`
+	SupportURL   *string   `json:"support_url,omitempty"`
+	LogoURL      *string   `json:"logo_url,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Address book DTOs
+type SaveAddressRequest struct {
+	Label        string   `json:"label" validate:"required,min=2,max=100"`
+	Line1        string   `json:"line1" validate:"required,min=3,max=255"`
+	Line2        *string  `json:"line2" validate:"omitempty,max=255"`
+	Ward         *string  `json:"ward" validate:"omitempty,max=100"`
+	District     *string  `json:"district" validate:"omitempty,max=100"`
+	City         string   `json:"city" validate:"required,max=100"`
+	Province     *string  `json:"province" validate:"omitempty,max=100"`
+	PostalCode   *string  `json:"postal_code" validate:"omitempty,max=20"`
+	CountryCode  string   `json:"country_code" validate:"required,len=2,alpha"`
+	Latitude     *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude    *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	ContactName  *string  `json:"contact_name" validate:"omitempty,max=255"`
+	ContactPhone *string  `json:"contact_phone" validate:"omitempty,phone"`
+}
+
+type AddressResponse struct {
+	ID           uuid.UUID                `json:"id"`
+	Label        string                   `json:"label"`
+	Line1        string                   `json:"line1"`
+	Line2        *string                  `json:"line2,omitempty"`
+	Ward         *string                  `json:"ward,omitempty"`
+	District     *string                  `json:"district,omitempty"`
+	City         string                   `json:"city"`
+	Province     *string                  `json:"province,omitempty"`
+	PostalCode   *string                  `json:"postal_code,omitempty"`
+	CountryCode  string                   `json:"country_code"`
+	Formatted    string                   `json:"formatted"`
+	Latitude     *float64                 `json:"latitude,omitempty"`
+	Longitude    *float64                 `json:"longitude,omitempty"`
+	ContactName  *string                  `json:"contact_name,omitempty"`
+	ContactPhone *string                  `json:"contact_phone,omitempty"`
+	Status       domainUser.AddressStatus `json:"status"`
+	VerifiedAt   *time.Time               `json:"verified_at,omitempty"`
+	UseCount     int                      `json:"use_count"`
+	LastUsedAt   *time.Time               `json:"last_used_at,omitempty"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+}
+
+// AddressBookResponse lists the address book, most used first, with reuse figures
+type AddressBookResponse struct {
+	Addresses []AddressResponse `json:"addresses"`
+	Total     int               `json:"total"`
+	Verified  int               `json:"verified"`
+	Geocoded  int               `json:"geocoded"`
+	// Shipments created from saved addresses
+	TotalUses int `json:"total_uses"`
+	// Addresses not used in the last AddressStaleAfter
+	Stale int `json:"stale"`
+}
+
+// Account merge DTOs
+type MergeAccountsRequest struct {
+	PrimaryUserID   uuid.UUID `json:"primary_user_id" validate:"required"`
+	DuplicateUserID uuid.UUID `json:"duplicate_user_id" validate:"required,nefield=PrimaryUserID"`
+	// Code from the preview; required to run the merge
+	ConfirmationCode string `json:"confirmation_code"`
+}
+
+type MergePreviewResponse struct {
+	Primary          *UserResponse           `json:"primary"`
+	Duplicate        *UserResponse           `json:"duplicate"`
+	Counts           []domainUser.MergeCount `json:"counts"`
+	TotalMoved       int64                   `json:"total_moved"`
+	TotalSkipped     int64                   `json:"total_skipped"`
+	ConfirmationCode string                  `json:"confirmation_code"`
+}
+
+type MergeResponse struct {
+	ID              uuid.UUID               `json:"id"`
+	PrimaryUserID   uuid.UUID               `json:"primary_user_id"`
+	DuplicateUserID uuid.UUID               `json:"duplicate_user_id"`
+	MergedBy        uuid.UUID               `json:"merged_by"`
+	Counts          []domainUser.MergeCount `json:"counts"`
+	CreatedAt       time.Time               `json:"created_at"`
+}
+
+// Invitation DTOs
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	Role  string `json:"role" validate:"required,oneof=provider shipper"`
+	// Defaults to InvitationTTL
+	ExpiresInHours int `json:"expires_in_hours" validate:"omitempty,min=1,max=720"`
+}
+
+type InvitationQuery struct {
+	PendingOnly bool `form:"pending"`
+	Page        int  `form:"page" validate:"omitempty,min=1"`
+	PageSize    int  `form:"page_size" validate:"omitempty,min=1,max=100"`
+}
+
+type InvitationResponse struct {
+	ID         uuid.UUID                   `json:"id"`
+	Email      string                      `json:"email"`
+	Role       string                      `json:"role"`
+	Status     domainUser.InvitationStatus `json:"status"`
+	InvitedBy  uuid.UUID                   `json:"invited_by"`
+	ExpiresAt  time.Time                   `json:"expires_at"`
+	AcceptedAt *time.Time                  `json:"accepted_at,omitempty"`
+	AcceptedBy *uuid.UUID                  `json:"accepted_by,omitempty"`
+	RevokedAt  *time.Time                  `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time                   `json:"created_at"`
+	// Only returned when the invitation is created; it cannot be recovered
+	Token string `json:"token,omitempty"`
+	Link  string `json:"link,omitempty"`
+	// Whether the invitation was emailed to the invitee
+	Emailed bool `json:"emailed,omitempty"`
+}
+
+type InvitationListResponse struct {
+	Invitations []InvitationResponse `json:"invitations"`
+	Total       int64                `json:"total"`
+	Page        int                  `json:"page"`
+	PageSize    int                  `json:"page_size"`
+}
+
+func ToInvitationResponse(i *domainUser.Invitation, now time.Time) InvitationResponse {
+	return InvitationResponse{
+		ID:         i.ID,
+		Email:      i.Email,
+		Role:       i.Role,
+		Status:     i.Status(now),
+		InvitedBy:  i.InvitedBy,
+		ExpiresAt:  i.ExpiresAt,
+		AcceptedAt: i.AcceptedAt,
+		AcceptedBy: i.AcceptedBy,
+		RevokedAt:  i.RevokedAt,
+		CreatedAt:  i.CreatedAt,
 	}
 }
@@ -0,0 +1,244 @@
+package user
+
+import (
+	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// InvitationTTL is how long an invitation stays valid unless the admin
+// chooses otherwise
+const InvitationTTL = 7 * 24 * time.Hour
+
+// InvitationService lets admins invite providers and shippers. Those roles
+// cannot be picked at self-registration; registering with an invitation's
+// token assigns its role instead.
+type InvitationService struct {
+	invitationRepo domainUser.InvitationRepository
+	userRepo       domainUser.Repository
+	mailer         domainNotification.Mailer
+	appURL         string
+}
+
+// NewInvitationService creates a new invitation service. mailer may be nil,
+// in which case the admin shares the returned link themselves.
+func NewInvitationService(invitationRepo domainUser.InvitationRepository, userRepo domainUser.Repository, mailer domainNotification.Mailer, cfg *config.Config) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		mailer:         mailer,
+		appURL:         strings.TrimRight(cfg.Notification.AppURL, "/"),
+	}
+}
+
+// CreateInvitation issues an invitation and emails it when a mailer is
+// configured. The token is only ever returned here.
+func (s *InvitationService) CreateInvitation(ctx context.Context, adminID uuid.UUID, req *CreateInvitationRequest) (*InvitationResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	existing, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, domainUser.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to check existing user: %w", err)
+	}
+	if existing != nil {
+		return nil, appErrors.ErrUserAlreadyExists
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	ttl := InvitationTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	invitation := &domainUser.Invitation{
+		Email:     email,
+		Role:      req.Role,
+		TokenHash: hashInvitationToken(token),
+		InvitedBy: adminID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	resp := ToInvitationResponse(invitation, time.Now())
+	resp.Token = token
+	resp.Link = s.invitationLink(token)
+	resp.Emailed = s.sendInvitation(ctx, invitation, resp.Link, token)
+
+	logger.Info("Invitation created",
+		zap.String("invitation_id", invitation.ID.String()),
+		zap.String("role", invitation.Role),
+		zap.String("invited_by", adminID.String()),
+		zap.Time("expires_at", invitation.ExpiresAt),
+		zap.String("event", "invitation_created"),
+	)
+
+	return &resp, nil
+}
+
+// ListInvitations returns invitations newest first
+func (s *InvitationService) ListInvitations(ctx context.Context, query *InvitationQuery) (*InvitationListResponse, error) {
+	if err := utils.ValidateStruct(query); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid query", err)
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	invitations, total, err := s.invitationRepo.List(ctx, query.PendingOnly, query.PageSize, (query.Page-1)*query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resp := &InvitationListResponse{
+		Invitations: make([]InvitationResponse, len(invitations)),
+		Total:       total,
+		Page:        query.Page,
+		PageSize:    query.PageSize,
+	}
+	for i, invitation := range invitations {
+		resp.Invitations[i] = ToInvitationResponse(invitation, now)
+	}
+	return resp, nil
+}
+
+// RevokeInvitation withdraws an invitation that has not been used yet
+func (s *InvitationService) RevokeInvitation(ctx context.Context, adminID, invitationID uuid.UUID) error {
+	if _, err := s.invitationRepo.GetByID(ctx, invitationID); err != nil {
+		return err
+	}
+	if err := s.invitationRepo.Revoke(ctx, invitationID, time.Now()); err != nil {
+		return err
+	}
+
+	logger.Info("Invitation revoked",
+		zap.String("invitation_id", invitationID.String()),
+		zap.String("revoked_by", adminID.String()),
+		zap.String("event", "invitation_revoked"),
+	)
+	return nil
+}
+
+func (s *InvitationService) invitationLink(token string) string {
+	if s.appURL == "" {
+		return ""
+	}
+	return s.appURL + "/register?invite=" + url.QueryEscape(token)
+}
+
+// sendInvitation emails the invitation and reports whether it went out. A
+// failure is logged only; the admin still has the link to pass on.
+func (s *InvitationService) sendInvitation(ctx context.Context, invitation *domainUser.Invitation, link, token string) bool {
+	if s.mailer == nil {
+		return false
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "You have been invited to join as a %s.\n\n", invitation.Role)
+	if link != "" {
+		fmt.Fprintf(&body, "Create your account here: %s\n\n", link)
+	} else {
+		fmt.Fprintf(&body, "Register with this invitation code: %s\n\n", token)
+	}
+	fmt.Fprintf(&body, "The invitation is valid until %s and can only be used with this email address.\n",
+		invitation.ExpiresAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	err := s.mailer.SendMail(ctx, &domainNotification.Mail{
+		To:      []string{invitation.Email},
+		Subject: "You're invited to Cargo Tracker",
+		Body:    body.String(),
+	})
+	if err != nil {
+		logger.Error("Failed to email invitation",
+			zap.String("invitation_id", invitation.ID.String()),
+			zap.Error(err),
+		)
+		return false
+	}
+	return true
+}
+
+// registrationRole decides the role of a new account. Without an invitation
+// only customers can register, plus any non-admin role for sandbox accounts
+// since those never see live data. With one, the invitation's role applies.
+func (s *Service) registrationRole(ctx context.Context, req *RegisterRequest) (string, *domainUser.Invitation, error) {
+	if req.Role == "admin" {
+		logger.Warn("Registration attempt with admin role",
+			zap.String("email", req.Email),
+			zap.String("event", "registration_rejected_admin_role"),
+		)
+		return "", nil, appErrors.NewAppError("ROLE_NOT_ALLOWED", "Admin accounts cannot be registered", nil)
+	}
+
+	if req.InviteToken == "" {
+		switch {
+		case req.Role == "" || req.Role == "customer":
+			return "customer", nil, nil
+		case req.Sandbox && (req.Role == "provider" || req.Role == "shipper"):
+			return req.Role, nil, nil
+		}
+		return "", nil, appErrors.NewAppError("INVITATION_REQUIRED", "Registering as "+req.Role+" requires an invitation", nil)
+	}
+
+	invitation, err := s.invitationRepo.GetByTokenHash(ctx, hashInvitationToken(req.InviteToken))
+	if errors.Is(err, domainUser.ErrInvitationNotFound) {
+		return "", nil, appErrors.NewAppError("INVALID_INVITATION", "Invitation is invalid", nil)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if invitation.Status(time.Now()) != domainUser.InvitationPending {
+		return "", nil, appErrors.NewAppError("INVALID_INVITATION", "Invitation has expired or was already used", nil)
+	}
+	if !strings.EqualFold(invitation.Email, strings.TrimSpace(req.Email)) {
+		return "", nil, appErrors.NewAppError("INVALID_INVITATION", "Invitation was issued for another email address", nil)
+	}
+	if req.Role != "" && req.Role != invitation.Role {
+		return "", nil, appErrors.NewAppError("INVALID_INVITATION", "Invitation is for the "+invitation.Role+" role", nil)
+	}
+	if req.Sandbox {
+		return "", nil, appErrors.NewAppError("INVALID_INVITATION", "Invitations cannot be used for sandbox accounts", nil)
+	}
+	return invitation.Role, invitation, nil
+}
+
+func generateInvitationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,161 @@
+package user
+
+import (
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AccountMergeService folds duplicate registrations into a primary account.
+// A merge is previewed first; the preview's confirmation code must be sent
+// back to run it, so an admin always confirms the exact diff that is applied.
+type AccountMergeService struct {
+	userRepo  domainUser.Repository
+	mergeRepo domainUser.MergeRepository
+}
+
+// NewAccountMergeService creates a new account merge service
+func NewAccountMergeService(userRepo domainUser.Repository, mergeRepo domainUser.MergeRepository) *AccountMergeService {
+	return &AccountMergeService{userRepo: userRepo, mergeRepo: mergeRepo}
+}
+
+// PreviewMerge returns what merging the duplicate into the primary account
+// would move, without changing anything
+func (s *AccountMergeService) PreviewMerge(ctx context.Context, req *MergeAccountsRequest) (*MergePreviewResponse, error) {
+	primary, duplicate, err := s.loadAccounts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.mergeRepo.Preview(ctx, primary.ID, duplicate.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MergePreviewResponse{
+		Primary:          ToUserResponse(primary),
+		Duplicate:        ToUserResponse(duplicate),
+		Counts:           counts,
+		ConfirmationCode: mergeConfirmationCode(primary.ID, duplicate.ID, counts),
+	}
+	for _, c := range counts {
+		resp.TotalMoved += c.Moved
+		resp.TotalSkipped += c.Skipped
+	}
+	return resp, nil
+}
+
+// MergeAccounts runs a previewed merge. It fails when the data changed since
+// the preview, in which case the admin has to preview again.
+func (s *AccountMergeService) MergeAccounts(ctx context.Context, adminID uuid.UUID, req *MergeAccountsRequest) (*MergeResponse, error) {
+	if req.ConfirmationCode == "" {
+		return nil, appErrors.NewAppError("CONFIRMATION_REQUIRED", "Preview the merge and send its confirmation code", nil)
+	}
+
+	preview, err := s.PreviewMerge(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if preview.ConfirmationCode != req.ConfirmationCode {
+		return nil, appErrors.NewAppError("CONFIRMATION_MISMATCH", "Accounts changed since the preview; preview the merge again", nil)
+	}
+
+	merge := &domainUser.AccountMerge{
+		PrimaryUserID:   req.PrimaryUserID,
+		DuplicateUserID: req.DuplicateUserID,
+		MergedBy:        adminID,
+	}
+	if err := s.mergeRepo.Merge(ctx, merge); err != nil {
+		return nil, err
+	}
+
+	var moved int64
+	for _, c := range merge.Counts {
+		moved += c.Moved
+	}
+	logger.Info("Accounts merged",
+		zap.String("merge_id", merge.ID.String()),
+		zap.String("primary_user_id", merge.PrimaryUserID.String()),
+		zap.String("duplicate_user_id", merge.DuplicateUserID.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.Int64("rows_moved", moved),
+		zap.String("event", "accounts_merged"),
+	)
+
+	return toMergeResponse(merge), nil
+}
+
+// GetMerge returns the merge a duplicate account was folded into
+func (s *AccountMergeService) GetMerge(ctx context.Context, duplicateID uuid.UUID) (*MergeResponse, error) {
+	merge, err := s.mergeRepo.GetByDuplicate(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	return toMergeResponse(merge), nil
+}
+
+func (s *AccountMergeService) loadAccounts(ctx context.Context, req *MergeAccountsRequest) (*domainUser.User, *domainUser.User, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	primary, err := s.userRepo.GetByID(ctx, req.PrimaryUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	duplicate, err := s.userRepo.GetByID(ctx, req.DuplicateUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Shipments reference accounts by role, so only like accounts can merge
+	if primary.Role != duplicate.Role {
+		return nil, nil, appErrors.NewAppError("ROLE_MISMATCH", "Both accounts must have the same role", nil)
+	}
+	if primary.IsSandbox != duplicate.IsSandbox {
+		return nil, nil, appErrors.NewAppError("SANDBOX_MISMATCH", "Sandbox and live accounts cannot be merged", nil)
+	}
+	if !primary.IsActive {
+		return nil, nil, appErrors.NewAppError("PRIMARY_INACTIVE", "The primary account is inactive", nil)
+	}
+
+	for _, id := range []uuid.UUID{primary.ID, duplicate.ID} {
+		if _, err := s.mergeRepo.GetByDuplicate(ctx, id); err == nil {
+			return nil, nil, domainUser.ErrAlreadyMerged
+		} else if !errors.Is(err, domainUser.ErrMergeNotFound) {
+			return nil, nil, err
+		}
+	}
+
+	return primary, duplicate, nil
+}
+
+// mergeConfirmationCode fingerprints the accounts and the diff of a preview
+func mergeConfirmationCode(primaryID, duplicateID uuid.UUID, counts []domainUser.MergeCount) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s>%s", primaryID, duplicateID)
+	for _, c := range counts {
+		fmt.Fprintf(h, "|%s.%s:%d:%d", c.Table, c.Column, c.Moved, c.Skipped)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func toMergeResponse(m *domainUser.AccountMerge) *MergeResponse {
+	return &MergeResponse{
+		ID:              m.ID,
+		PrimaryUserID:   m.PrimaryUserID,
+		DuplicateUserID: m.DuplicateUserID,
+		MergedBy:        m.MergedBy,
+		Counts:          m.Counts,
+		CreatedAt:       m.CreatedAt,
+	}
+}
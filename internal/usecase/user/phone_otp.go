@@ -0,0 +1,259 @@
+package user
+
+import (
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"cargo-tracker/pkg/utils"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// OTPLength is the number of digits of a one-time password
+	OTPLength = 6
+	// OTPTTL is how long a one-time password can be used
+	OTPTTL = 10 * time.Minute
+	// OTPMaxAttempts is how many wrong codes are accepted before a code is dead
+	OTPMaxAttempts = 5
+	// OTPMaxPerHour limits how many codes a user can be sent per purpose
+	OTPMaxPerHour = 3
+)
+
+var errInvalidOTP = appErrors.NewAppError("INVALID_OTP", "Invalid or expired verification code", nil)
+
+// RequestPhoneVerification sends a code to the phone number of the user
+func (s *Service) RequestPhoneVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PhoneNumber == nil || *user.PhoneNumber == "" {
+		return appErrors.NewAppError("PHONE_REQUIRED", "Add a phone number to your profile first", nil)
+	}
+	if user.PhoneVerified != nil {
+		return appErrors.NewAppError("PHONE_ALREADY_VERIFIED", "Phone number is already verified", nil)
+	}
+
+	return s.issueOTP(ctx, user, domainUser.OTPVerifyPhone)
+}
+
+// VerifyPhone confirms the phone number of the user with the code sent to it
+func (s *Service) VerifyPhone(ctx context.Context, userID uuid.UUID, req *VerifyPhoneRequest) (*UserResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.PhoneNumber == nil {
+		return nil, appErrors.NewAppError("PHONE_REQUIRED", "Add a phone number to your profile first", nil)
+	}
+
+	if err := s.checkOTP(ctx, user.ID, domainUser.OTPVerifyPhone, req.Code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.userRepo.SetPhoneVerified(ctx, user.ID, &now); err != nil {
+		return nil, err
+	}
+	user.PhoneVerified = &now
+
+	logger.Info("Phone number verified",
+		zap.String("user_id", user.ID.String()),
+		zap.String("event", "phone_verified"),
+	)
+
+	return ToUserResponse(user), nil
+}
+
+// ForgotPasswordByPhone sends a password reset code by SMS. Like the email
+// flow it never reveals whether the number belongs to an account.
+func (s *Service) ForgotPasswordByPhone(ctx context.Context, req *ForgotPasswordPhoneRequest) error {
+	if err := utils.ValidateStruct(req); err != nil {
+		return appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	user, err := s.userRepo.GetByPhone(ctx, req.PhoneNumber)
+	if err != nil {
+		if errors.Is(err, domainUser.ErrUserNotFound) {
+			logger.Info("Password reset by phone requested for unknown number",
+				zap.String("event", "password_reset_otp_unknown_phone"),
+			)
+			return nil
+		}
+		return fmt.Errorf("failed to retrieve user: %w", err)
+	}
+
+	// Only numbers proven to reach the user can be used to take over the account
+	if user.PhoneVerified == nil || !user.IsActive {
+		logger.Info("Password reset by phone requested for unverified number",
+			zap.String("user_id", user.ID.String()),
+			zap.String("event", "password_reset_otp_unverified_phone"),
+		)
+		return nil
+	}
+
+	if err := s.issueOTP(ctx, user, domainUser.OTPResetPassword); err != nil {
+		logger.Warn("Failed to send password reset code",
+			zap.String("user_id", user.ID.String()),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// ResetPasswordByPhone sets a new password after checking the SMS code
+func (s *Service) ResetPasswordByPhone(ctx context.Context, req *ResetPasswordPhoneRequest) error {
+	if err := utils.ValidateStruct(req); err != nil {
+		return appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	if err := utils.ValidatePassword(req.NewPassword); err != nil {
+		return appErrors.NewAppError("WEAK_PASSWORD", err.Error(), nil)
+	}
+
+	user, err := s.userRepo.GetByPhone(ctx, req.PhoneNumber)
+	if err != nil {
+		if errors.Is(err, domainUser.ErrUserNotFound) {
+			return errInvalidOTP
+		}
+		return fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if user.PhoneVerified == nil {
+		return errInvalidOTP
+	}
+
+	if err := s.checkOTP(ctx, user.ID, domainUser.OTPResetPassword, req.Code); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	logger.Info("Password reset by phone successfully",
+		zap.String("user_id", user.ID.String()),
+		zap.String("event", "password_reset_otp_success"),
+	)
+
+	return nil
+}
+
+// issueOTP creates a code for purpose and sends it to the user's phone
+func (s *Service) issueOTP(ctx context.Context, user *domainUser.User, purpose domainUser.OTPPurpose) error {
+	now := time.Now()
+	sent, err := s.otpRepo.CountSince(ctx, user.ID, purpose, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent >= OTPMaxPerHour {
+		return appErrors.NewAppError("OTP_RATE_LIMITED", "Too many codes requested, try again later", nil)
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		return fmt.Errorf("failed to generate code: %w", err)
+	}
+
+	otp := &domainUser.PhoneOTP{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		CodeHash:  hashOTP(user.ID, purpose, code),
+		ExpiresAt: now.Add(OTPTTL),
+	}
+	if err := s.otpRepo.Create(ctx, otp); err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("Your Cargo Tracker code is %s. It expires in %d minutes. Do not share it with anyone.",
+		code, int(OTPTTL.Minutes()))
+	if err := s.smsSender.SendSMS(ctx, *user.PhoneNumber, text); err != nil {
+		return fmt.Errorf("failed to send code: %w", err)
+	}
+
+	logger.Info("Verification code sent",
+		zap.String("user_id", user.ID.String()),
+		zap.String("purpose", string(purpose)),
+		zap.String("otp_id", otp.ID.String()),
+		zap.String("event", "otp_sent"),
+	)
+
+	return nil
+}
+
+// checkOTP consumes the latest code of purpose if it matches. Wrong codes count
+// towards the attempt limit of that code.
+func (s *Service) checkOTP(ctx context.Context, userID uuid.UUID, purpose domainUser.OTPPurpose, code string) error {
+	otp, err := s.otpRepo.GetLatest(ctx, userID, purpose)
+	if err != nil {
+		if errors.Is(err, domainUser.ErrOTPNotFound) {
+			return errInvalidOTP
+		}
+		return err
+	}
+
+	now := time.Now()
+	if !otp.IsUsable(now, OTPMaxAttempts) {
+		return errInvalidOTP
+	}
+
+	expected := []byte(otp.CodeHash)
+	if subtle.ConstantTimeCompare([]byte(hashOTP(userID, purpose, code)), expected) != 1 {
+		if err := s.otpRepo.IncrementAttempts(ctx, otp.ID); err != nil {
+			return err
+		}
+		logger.Warn("Wrong verification code",
+			zap.String("user_id", userID.String()),
+			zap.String("purpose", string(purpose)),
+			zap.Int("attempts", otp.Attempts+1),
+			zap.String("event", "otp_failed"),
+		)
+		return errInvalidOTP
+	}
+
+	if err := s.otpRepo.Consume(ctx, otp.ID, now); err != nil {
+		if errors.Is(err, domainUser.ErrOTPNotFound) {
+			return errInvalidOTP
+		}
+		return err
+	}
+	return nil
+}
+
+func generateOTP() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < OTPLength; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", OTPLength, n), nil
+}
+
+// hashOTP binds a code to its user and purpose so that a stored hash cannot
+// be reused for another account or flow
+func hashOTP(userID uuid.UUID, purpose domainUser.OTPPurpose, code string) string {
+	sum := sha256.Sum256([]byte(userID.String() + ":" + string(purpose) + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,205 @@
+package user
+
+import (
+	"cargo-tracker/internal/config"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/infrastructure/database/memory"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// capturingSMS records the codes it is asked to send
+type capturingSMS struct {
+	texts []string
+}
+
+func (c *capturingSMS) SendSMS(ctx context.Context, phoneNumber, text string) error {
+	c.texts = append(c.texts, text)
+	return nil
+}
+
+var smsCode = regexp.MustCompile(`\b\d{6}\b`)
+
+// lastCode returns the code of the last SMS sent
+func (c *capturingSMS) lastCode(t *testing.T) string {
+	t.Helper()
+	if len(c.texts) == 0 {
+		t.Fatal("no code was sent")
+	}
+	code := smsCode.FindString(c.texts[len(c.texts)-1])
+	if code == "" {
+		t.Fatalf("no code in %q", c.texts[len(c.texts)-1])
+	}
+	return code
+}
+
+type otpFixture struct {
+	svc  *Service
+	otps domainUser.OTPRepository
+	sms  *capturingSMS
+	user *domainUser.User
+}
+
+// newOTPFixture creates a service on in-memory repositories with one user
+// whose phone number is verified
+func newOTPFixture(t *testing.T) *otpFixture {
+	t.Helper()
+	logger.Logger = zap.NewNop()
+
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	otps := memory.NewOTPRepository(store)
+	sms := &capturingSMS{}
+
+	phone := "+84901234567"
+	verifiedAt := time.Now()
+	u := &domainUser.User{
+		Username: "driver", Email: "driver@example.com", FullName: "Driver",
+		PhoneNumber: &phone, Role: "shipper",
+	}
+	if err := users.Create(context.Background(), u); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+	if err := users.SetPhoneVerified(context.Background(), u.ID, &verifiedAt); err != nil {
+		t.Fatalf("SetPhoneVerified: %v", err)
+	}
+
+	svc := NewService(users, memory.NewRefreshTokenRepository(store), otps, nil, sms, &config.Config{})
+	return &otpFixture{svc: svc, otps: otps, sms: sms, user: u}
+}
+
+func (f *otpFixture) requestReset(t *testing.T) string {
+	t.Helper()
+	if err := f.svc.ForgotPasswordByPhone(context.Background(), &ForgotPasswordPhoneRequest{PhoneNumber: *f.user.PhoneNumber}); err != nil {
+		t.Fatalf("ForgotPasswordByPhone: %v", err)
+	}
+	return f.sms.lastCode(t)
+}
+
+func (f *otpFixture) reset(code string) error {
+	return f.svc.ResetPasswordByPhone(context.Background(), &ResetPasswordPhoneRequest{
+		PhoneNumber:     *f.user.PhoneNumber,
+		Code:            code,
+		NewPassword:     "N3w-Passw0rd!",
+		ConfirmPassword: "N3w-Passw0rd!",
+	})
+}
+
+// wrongCode returns a well-formed code that differs from code
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func assertInvalidOTP(t *testing.T, err error, what string) {
+	t.Helper()
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_OTP" {
+		t.Errorf("%s: err = %v, want INVALID_OTP", what, err)
+	}
+}
+
+func TestResetPasswordByPhoneConsumesCode(t *testing.T) {
+	f := newOTPFixture(t)
+	code := f.requestReset(t)
+
+	if err := f.reset(code); err != nil {
+		t.Fatalf("reset with the sent code: %v", err)
+	}
+	assertInvalidOTP(t, f.reset(code), "reusing the code")
+}
+
+func TestOTPIsDeadAfterMaxAttempts(t *testing.T) {
+	f := newOTPFixture(t)
+	code := f.requestReset(t)
+
+	for i := 0; i < OTPMaxAttempts; i++ {
+		assertInvalidOTP(t, f.reset(wrongCode(code)), "wrong code")
+	}
+	// The right code no longer works once the attempts are used up
+	assertInvalidOTP(t, f.reset(code), "right code after the attempt limit")
+}
+
+func TestOTPExpiresAfterTTL(t *testing.T) {
+	f := newOTPFixture(t)
+	ctx := context.Background()
+	f.requestReset(t)
+
+	issued, err := f.otps.GetLatest(ctx, f.user.ID, domainUser.OTPResetPassword)
+	if err != nil {
+		t.Fatalf("GetLatest: %v", err)
+	}
+	if ttl := issued.ExpiresAt.Sub(issued.CreatedAt); ttl < OTPTTL-time.Second || ttl > OTPTTL+time.Second {
+		t.Errorf("code lives %s, want %s", ttl, OTPTTL)
+	}
+	if OTPTTL != 10*time.Minute {
+		t.Errorf("OTPTTL = %s, want 10m", OTPTTL)
+	}
+
+	// A code whose TTL ran out is refused even when it matches
+	const code = "424242"
+	expired := &domainUser.PhoneOTP{
+		UserID:    f.user.ID,
+		Purpose:   domainUser.OTPResetPassword,
+		CodeHash:  hashOTP(f.user.ID, domainUser.OTPResetPassword, code),
+		ExpiresAt: time.Now().Add(-time.Second),
+	}
+	if err := f.otps.Create(ctx, expired); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	assertInvalidOTP(t, f.reset(code), "expired code")
+}
+
+func TestOTPRequestsAreRateLimited(t *testing.T) {
+	f := newOTPFixture(t)
+
+	for i := 0; i < OTPMaxPerHour+1; i++ {
+		f.requestReset(t)
+	}
+	// The request past the limit is swallowed so it does not reveal the
+	// account, but no code is sent
+	if len(f.sms.texts) != OTPMaxPerHour {
+		t.Errorf("%d codes sent in an hour, want %d", len(f.sms.texts), OTPMaxPerHour)
+	}
+	if OTPMaxPerHour != 3 || OTPMaxAttempts != 5 {
+		t.Errorf("limits are %d per hour and %d attempts, want 3 and 5", OTPMaxPerHour, OTPMaxAttempts)
+	}
+}
+
+// failingTokens fails to delete expired refresh tokens
+type failingTokens struct {
+	domainUser.RefreshTokenRepository
+}
+
+func (failingTokens) DeleteExpired(ctx context.Context, olderThan time.Duration) error {
+	return errors.New("database unavailable")
+}
+
+func TestCleanupDeletesExpiredCodesWhenTokenCleanupFails(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	store := memory.NewStore()
+	otps := memory.NewOTPRepository(store)
+	svc := NewService(memory.NewUserRepository(store), failingTokens{memory.NewRefreshTokenRepository(store)}, otps, nil, nil, &config.Config{})
+
+	ctx := context.Background()
+	stale := &domainUser.PhoneOTP{UserID: uuid.New(), Purpose: domainUser.OTPVerifyPhone, ExpiresAt: time.Now().Add(-48 * time.Hour)}
+	if err := otps.Create(ctx, stale); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	svc.cleanupExpiredTokens(ctx)
+
+	if _, err := otps.GetLatest(ctx, stale.UserID, domainUser.OTPVerifyPhone); !errors.Is(err, domainUser.ErrOTPNotFound) {
+		t.Errorf("expired code still stored: err = %v", err)
+	}
+}
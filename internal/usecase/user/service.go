@@ -2,6 +2,7 @@ package user
 
 import (
 	"cargo-tracker/internal/config"
+	domainNotification "cargo-tracker/internal/domain/notification"
 	domainUser "cargo-tracker/internal/domain/user"
 	"cargo-tracker/internal/logger"
 	appErrors "cargo-tracker/pkg/errors"
@@ -9,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,6 +21,9 @@ import (
 type Service struct {
 	userRepo         domainUser.Repository
 	refreshTokenRepo domainUser.RefreshTokenRepository
+	otpRepo          domainUser.OTPRepository
+	invitationRepo   domainUser.InvitationRepository
+	smsSender        domainNotification.SMSSender
 	config           *config.Config
 }
 
@@ -26,11 +31,17 @@ type Service struct {
 func NewService(
 	userRepo domainUser.Repository,
 	refreshTokenRepo domainUser.RefreshTokenRepository,
+	otpRepo domainUser.OTPRepository,
+	invitationRepo domainUser.InvitationRepository,
+	smsSender domainNotification.SMSSender,
 	cfg *config.Config,
 ) *Service {
 	return &Service{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		otpRepo:          otpRepo,
+		invitationRepo:   invitationRepo,
+		smsSender:        smsSender,
 		config:           cfg,
 	}
 }
@@ -44,6 +55,15 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 		return nil, appErrors.NewAppError("WEAK_PASSWORD", err.Error(), nil)
 	}
 
+	if req.Sandbox && !s.config.Sandbox.Enabled {
+		return nil, appErrors.NewAppError("SANDBOX_DISABLED", "Sandbox accounts are not available", nil)
+	}
+
+	role, invitation, err := s.registrationRole(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if user already exists
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil && !errors.Is(err, domainUser.ErrUserNotFound) {
@@ -70,9 +90,11 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 		PasswordHashed: hashedPassword,
 		FullName:       req.FullName,
 		PhoneNumber:    req.PhoneNumber,
-		Role:           req.Role,
+		Role:           role,
 		Address:        req.Address,
 		IsActive:       true,
+		IsSandbox:      req.Sandbox,
+		Plan:           s.config.Quota.SignupPlan,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
@@ -82,6 +104,17 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 		return nil, err
 	}
 
+	if invitation != nil {
+		if err := s.invitationRepo.Accept(ctx, invitation.ID, user.ID, time.Now()); err != nil {
+			// The email is taken now, so the invitation cannot be reused anyway
+			logger.Error("Failed to mark invitation as accepted",
+				zap.String("invitation_id", invitation.ID.String()),
+				zap.String("user_id", user.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
 	// Generate tokens
 	tokenPair, err := utils.GenerateTokenPair(
 		user.ID,
@@ -96,16 +129,9 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 	}
 
 	// Store refresh token
-	refreshToken := &domainUser.RefreshToken{
-		UserID:    user.ID,
-		Token:     tokenPair.RefreshToken,
-		ExpiresAt: time.Now().Add(time.Duration(s.config.JWT.RefreshExpiryHours) * time.Hour),
-		Revoked:   false,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	if err := s.refreshTokenRepo.Create(ctx, refreshToken); err != nil {
-		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	displaced, err := s.startSession(ctx, user, tokenPair.RefreshToken)
+	if err != nil {
+		return nil, err
 	}
 
 	logger.Info("User registered successfully",
@@ -113,14 +139,16 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 		zap.String("email", user.Email),
 		zap.String("username", user.Username),
 		zap.String("role", user.Role),
+		zap.Bool("sandbox", user.IsSandbox),
 		zap.String("event", "user_registered"),
 	)
 
 	return &AuthResponse{
-		User:         ToUserResponse(user),
-		AccessToken:  tokenPair.AccessToken,
-		RefreshToken: tokenPair.RefreshToken,
-		ExpiresAt:    tokenPair.ExpiresAt,
+		User:              ToUserResponse(user),
+		AccessToken:       tokenPair.AccessToken,
+		RefreshToken:      tokenPair.RefreshToken,
+		ExpiresAt:         tokenPair.ExpiresAt,
+		DisplacedSessions: displaced,
 	}, nil
 }
 
@@ -176,16 +204,9 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*AuthResponse,
 	}
 
 	// Store refresh token
-	refreshToken := &domainUser.RefreshToken{
-		UserID:    user.ID,
-		Token:     tokenPair.RefreshToken,
-		ExpiresAt: time.Now().Add(time.Duration(s.config.JWT.RefreshExpiryHours) * time.Hour),
-		Revoked:   false,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	if err := s.refreshTokenRepo.Create(ctx, refreshToken); err != nil {
-		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	displaced, err := s.startSession(ctx, user, tokenPair.RefreshToken)
+	if err != nil {
+		return nil, err
 	}
 
 	logger.Info("User logged in successfully",
@@ -196,10 +217,11 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*AuthResponse,
 	)
 
 	return &AuthResponse{
-		User:         ToUserResponse(user),
-		AccessToken:  tokenPair.AccessToken,
-		RefreshToken: tokenPair.RefreshToken,
-		ExpiresAt:    tokenPair.ExpiresAt,
+		User:              ToUserResponse(user),
+		AccessToken:       tokenPair.AccessToken,
+		RefreshToken:      tokenPair.RefreshToken,
+		ExpiresAt:         tokenPair.ExpiresAt,
+		DisplacedSessions: displaced,
 	}, nil
 }
 
@@ -366,11 +388,22 @@ func (s *Service) UpdateProfile(ctx context.Context, userID uuid.UUID, req *Upda
 		user.FullName = *req.FullName
 	}
 	if req.PhoneNumber != nil {
+		if user.PhoneNumber == nil || *user.PhoneNumber != *req.PhoneNumber {
+			// A new number has to be verified again
+			user.PhoneVerified = nil
+		}
 		user.PhoneNumber = req.PhoneNumber
 	}
 	if req.Address != nil {
 		user.Address = req.Address
 	}
+	if req.DefaultCurrency != nil {
+		if user.Role != "provider" {
+			return nil, appErrors.NewAppError("VALIDATION_ERROR", "Only providers have a default currency", nil)
+		}
+		currency := strings.ToUpper(*req.DefaultCurrency)
+		user.DefaultCurrency = &currency
+	}
 	user.UpdatedAt = time.Now()
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
@@ -380,18 +413,112 @@ func (s *Service) UpdateProfile(ctx context.Context, userID uuid.UUID, req *Upda
 	return ToUserResponse(user), nil
 }
 
-func (s *Service) GetAllUsers(ctx context.Context) ([]*UserResponse, error) {
-	users, err := s.userRepo.GetAll(ctx)
+// ListUsers returns a page of users matching the filter, newest first by
+// default. The search is accent insensitive on name and email.
+func (s *Service) ListUsers(ctx context.Context, filter *UserFilterRequest) (*UserListResponse, error) {
+	if err := utils.ValidateStruct(filter); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	// Set defaults
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+
+	users, total, err := s.userRepo.List(ctx, toDomainUserFilter(filter))
 	if err != nil {
 		return nil, err
 	}
 
-	var responses []*UserResponse
-	for _, user := range users {
-		responses = append(responses, ToUserResponse(user))
+	responses := make([]*UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = ToUserResponse(user)
 	}
 
-	return responses, nil
+	// Calculate total pages
+	totalPages := int(total) / filter.PageSize
+	if int(total)%filter.PageSize > 0 {
+		totalPages++
+	}
+
+	return &UserListResponse{
+		Users:      responses,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// CountUsers counts the users matching the filter; pagination and sorting
+// are ignored
+func (s *Service) CountUsers(ctx context.Context, filter *UserFilterRequest) (*UserCountResponse, error) {
+	if err := utils.ValidateStruct(filter); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+
+	total, err := s.userRepo.Count(ctx, toDomainUserFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+	return &UserCountResponse{Total: total}, nil
+}
+
+func toDomainUserFilter(filter *UserFilterRequest) *domainUser.Filter {
+	return &domainUser.Filter{
+		Role:             filter.Role,
+		IsActive:         filter.IsActive,
+		RegisteredAfter:  filter.RegisteredAfter,
+		RegisteredBefore: filter.RegisteredBefore,
+		Search:           utils.NormalizeSearch(filter.Search),
+		Page:             filter.Page,
+		PageSize:         filter.PageSize,
+		SortBy:           filter.SortBy,
+		SortOrder:        filter.SortOrder,
+	}
+}
+
+// ChangeRole moves a user to another role. Their sessions are revoked so the
+// new role applies from their next login.
+func (s *Service) ChangeRole(ctx context.Context, adminID, userID uuid.UUID, req *ChangeRoleRequest) (*UserResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Invalid input", err)
+	}
+	if adminID == userID {
+		return nil, appErrors.NewAppError("VALIDATION_ERROR", "Admins cannot change their own role", nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, appErrors.ErrUserNotFound
+	}
+	if user.Role == req.Role {
+		return ToUserResponse(user), nil
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, userID, req.Role); err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.RevokeAllUserTokens(ctx, userID, domainUser.RevokeReasonRoleChanged); err != nil {
+		logger.Error("Failed to revoke sessions after role change",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+	}
+
+	logger.Info("User role changed",
+		zap.String("user_id", userID.String()),
+		zap.String("from_role", user.Role),
+		zap.String("to_role", req.Role),
+		zap.String("changed_by", adminID.String()),
+		zap.String("event", "user_role_changed"),
+	)
+
+	user.Role = req.Role
+	return ToUserResponse(user), nil
 }
 
 func (s *Service) DeleteUser(ctx context.Context, userID uuid.UUID) error {
@@ -425,7 +552,7 @@ func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*utils
 			zap.String("user_id", claims.UserID.String()),
 			zap.String("event", "token_refresh_failed_token_not_found"),
 		)
-		return nil, appErrors.ErrInvalidToken
+		return nil, s.endedSessionError(ctx, refreshToken)
 	}
 
 	// Verify token belongs to the user
@@ -438,8 +565,25 @@ func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*utils
 		return nil, appErrors.ErrInvalidToken
 	}
 
+	now := time.Now()
+	if dbToken.IsIdle(now, s.config.Session.InactivityTimeout) {
+		if err := s.refreshTokenRepo.Revoke(ctx, dbToken.ID, domainUser.RevokeReasonInactivity); err != nil {
+			logger.Error("Failed to revoke idle refresh token",
+				zap.String("token_id", dbToken.ID.String()),
+				zap.Error(err),
+			)
+		}
+		logger.Info("Session expired after inactivity",
+			zap.String("user_id", dbToken.UserID.String()),
+			zap.String("token_id", dbToken.ID.String()),
+			zap.Time("last_activity_at", dbToken.LastActivityAt),
+			zap.String("event", "session_idle_revoked"),
+		)
+		return nil, appErrors.ErrSessionIdle
+	}
+
 	// Revoke the old refresh token
-	if err := s.refreshTokenRepo.Revoke(ctx, dbToken.ID); err != nil {
+	if err := s.refreshTokenRepo.Revoke(ctx, dbToken.ID, domainUser.RevokeReasonRotated); err != nil {
 		logger.Error("Failed to revoke refresh token",
 			zap.String("token_id", dbToken.ID.String()),
 			zap.Error(err),
@@ -459,14 +603,16 @@ func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*utils
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
-	// Store the new refresh token
+	// Store the new refresh token, continuing the same session
 	newRefreshToken := &domainUser.RefreshToken{
-		UserID:    claims.UserID,
-		Token:     tokenPair.RefreshToken,
-		ExpiresAt: time.Now().Add(time.Duration(s.config.JWT.RefreshExpiryHours) * time.Hour),
-		Revoked:   false,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		UserID:           claims.UserID,
+		Token:            tokenPair.RefreshToken,
+		ExpiresAt:        now.Add(time.Duration(s.config.JWT.RefreshExpiryHours) * time.Hour),
+		Revoked:          false,
+		SessionStartedAt: dbToken.SessionStartedAt,
+		LastActivityAt:   now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
 	}
 	if err := s.refreshTokenRepo.Create(ctx, newRefreshToken); err != nil {
 		return nil, fmt.Errorf("failed to store refresh token: %w", err)
@@ -493,7 +639,7 @@ func (s *Service) RevokeToken(ctx context.Context, userID uuid.UUID, refreshToke
 		return appErrors.ErrInvalidToken
 	}
 
-	if err := s.refreshTokenRepo.Revoke(ctx, dbToken.ID); err != nil {
+	if err := s.refreshTokenRepo.Revoke(ctx, dbToken.ID, domainUser.RevokeReasonLogout); err != nil {
 		return fmt.Errorf("failed to revoke token: %w", err)
 	}
 
@@ -507,7 +653,7 @@ func (s *Service) RevokeToken(ctx context.Context, userID uuid.UUID, refreshToke
 }
 
 func (s *Service) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
-	if err := s.refreshTokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+	if err := s.refreshTokenRepo.RevokeAllUserTokens(ctx, userID, domainUser.RevokeReasonRevokedAll); err != nil {
 		return fmt.Errorf("failed to revoke all tokens for user: %w", err)
 	}
 
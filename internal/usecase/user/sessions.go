@@ -0,0 +1,110 @@
+package user
+
+import (
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/logger"
+	appErrors "cargo-tracker/pkg/errors"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// startSession stores the refresh token of a new login and enforces the
+// concurrent session cap of the user's role. It returns how many older
+// sessions were signed out to make room.
+func (s *Service) startSession(ctx context.Context, user *domainUser.User, token string) (int, error) {
+	now := time.Now()
+	refreshToken := &domainUser.RefreshToken{
+		UserID:           user.ID,
+		Token:            token,
+		ExpiresAt:        now.Add(time.Duration(s.config.JWT.RefreshExpiryHours) * time.Hour),
+		Revoked:          false,
+		SessionStartedAt: now,
+		LastActivityAt:   now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.refreshTokenRepo.Create(ctx, refreshToken); err != nil {
+		return 0, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return s.enforceSessionLimit(ctx, user, refreshToken), nil
+}
+
+// enforceSessionLimit revokes the oldest sessions of a user beyond the cap of
+// their role, never the session just started. Failures are logged; the new
+// login still succeeds.
+func (s *Service) enforceSessionLimit(ctx context.Context, user *domainUser.User, current *domainUser.RefreshToken) int {
+	limit := s.config.Session.MaxSessions(user.Role)
+	if limit <= 0 {
+		return 0
+	}
+
+	tokens, err := s.refreshTokenRepo.GetUserTokens(ctx, user.ID)
+	if err != nil {
+		logger.Warn("Failed to load sessions for limit check",
+			zap.String("user_id", user.ID.String()),
+			zap.Error(err),
+		)
+		return 0
+	}
+	if len(tokens) <= limit {
+		return 0
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].SessionStartedAt.Before(tokens[j].SessionStartedAt)
+	})
+
+	displaced := 0
+	for _, t := range tokens {
+		if len(tokens)-displaced <= limit {
+			break
+		}
+		if t.ID == current.ID {
+			continue
+		}
+		if err := s.refreshTokenRepo.Revoke(ctx, t.ID, domainUser.RevokeReasonSessionLimit); err != nil {
+			logger.Warn("Failed to revoke displaced session",
+				zap.String("token_id", t.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		displaced++
+	}
+
+	if displaced > 0 {
+		logger.Info("Sessions displaced by new login",
+			zap.String("user_id", user.ID.String()),
+			zap.String("role", user.Role),
+			zap.Int("limit", limit),
+			zap.Int("displaced", displaced),
+			zap.String("event", "session_limit_displaced"),
+		)
+	}
+
+	return displaced
+}
+
+// endedSessionError explains why a refresh token no longer works, so clients
+// can tell the user their session was displaced or timed out rather than
+// showing a generic sign-in prompt
+func (s *Service) endedSessionError(ctx context.Context, token string) error {
+	dbToken, err := s.refreshTokenRepo.FindByToken(ctx, token)
+	if err != nil || !dbToken.Revoked {
+		return appErrors.ErrInvalidToken
+	}
+
+	switch dbToken.RevokeReason {
+	case domainUser.RevokeReasonSessionLimit:
+		return appErrors.ErrSessionDisplaced
+	case domainUser.RevokeReasonInactivity:
+		return appErrors.ErrSessionIdle
+	default:
+		return appErrors.ErrInvalidToken
+	}
+}
@@ -31,13 +31,44 @@ func (s *Service) StartTokenCleanupJob(ctx context.Context, interval time.Durati
 }
 
 func (s *Service) cleanupExpiredTokens(ctx context.Context) {
+	s.revokeIdleSessions(ctx)
+
+	// A failure of one cleanup must not keep the other from running
 	olderThan := 24 * time.Hour
-	if err := s.refreshTokenRepo.DeleteExpired(ctx, olderThan); err != nil {
-		logger.Error("Failed to delete expired tokens", zap.Error(err))
+	tokensErr := s.refreshTokenRepo.DeleteExpired(ctx, olderThan)
+	if tokensErr != nil {
+		logger.Error("Failed to delete expired tokens", zap.Error(tokensErr))
+	}
+	codesErr := s.otpRepo.DeleteExpired(ctx, olderThan)
+	if codesErr != nil {
+		logger.Error("Failed to delete expired verification codes", zap.Error(codesErr))
+	}
+
+	if tokensErr == nil && codesErr == nil {
+		logger.Debug("Expired tokens cleaned up successfully",
+			zap.Duration("older_than", olderThan),
+		)
+	}
+}
+
+// revokeIdleSessions revokes sessions past the inactivity timeout. Refresh
+// also rejects idle tokens, so this only keeps the session list accurate.
+func (s *Service) revokeIdleSessions(ctx context.Context) {
+	timeout := s.config.Session.InactivityTimeout
+	if timeout <= 0 {
 		return
 	}
 
-	logger.Debug("Expired tokens cleaned up successfully",
-		zap.Duration("older_than", olderThan),
-	)
+	revoked, err := s.refreshTokenRepo.RevokeIdle(ctx, time.Now().Add(-timeout))
+	if err != nil {
+		logger.Error("Failed to revoke idle sessions", zap.Error(err))
+		return
+	}
+	if revoked > 0 {
+		logger.Info("Idle sessions revoked",
+			zap.Int64("count", revoked),
+			zap.Duration("inactivity_timeout", timeout),
+			zap.String("event", "session_idle_revoked"),
+		)
+	}
 }
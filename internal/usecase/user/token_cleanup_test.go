@@ -0,0 +1,72 @@
+package user
+
+import (
+	"cargo-tracker/internal/config"
+	domainUser "cargo-tracker/internal/domain/user"
+	"cargo-tracker/internal/infrastructure/database/memory"
+	"cargo-tracker/internal/logger"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestTokenCleanupJobRevokesIdleAndDeletesExpired(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	store := memory.NewStore()
+	tokens := memory.NewRefreshTokenRepository(store)
+	cfg := &config.Config{Session: config.SessionConfig{InactivityTimeout: time.Hour}}
+	svc := NewService(memory.NewUserRepository(store), tokens, memory.NewOTPRepository(store), nil, nil, cfg)
+
+	ctx := context.Background()
+	now := time.Now()
+	userID := uuid.New()
+	for _, token := range []*domainUser.RefreshToken{
+		{UserID: userID, Token: "expired", ExpiresAt: now.Add(-48 * time.Hour)},
+		{UserID: userID, Token: "idle", ExpiresAt: now.Add(24 * time.Hour), LastActivityAt: now.Add(-2 * time.Hour)},
+		{UserID: userID, Token: "active", ExpiresAt: now.Add(24 * time.Hour)},
+	} {
+		if err := tokens.Create(ctx, token); err != nil {
+			t.Fatalf("Create(%s): %v", token.Token, err)
+		}
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		svc.StartTokenCleanupJob(jobCtx, 10*time.Millisecond)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, err := tokens.FindByToken(ctx, "expired")
+		if errors.Is(err, domainUser.ErrTokenInvalid) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired refresh token was not deleted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// The job must return once its context is cancelled so shutdown can wait for it
+	cancel()
+	workers.Wait()
+
+	idle, err := tokens.FindByToken(ctx, "idle")
+	if err != nil {
+		t.Fatalf("FindByToken(idle): %v", err)
+	}
+	if !idle.Revoked || idle.RevokeReason != domainUser.RevokeReasonInactivity {
+		t.Errorf("idle session revoked = %v (%q), want revoked for inactivity", idle.Revoked, idle.RevokeReason)
+	}
+	if _, err := tokens.GetByToken(ctx, "active"); err != nil {
+		t.Errorf("active session was revoked: %v", err)
+	}
+}
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_announcements_updated_at ON announcements;
+DROP TABLE IF EXISTS announcements;
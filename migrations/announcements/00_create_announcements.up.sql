@@ -0,0 +1,30 @@
+CREATE TABLE announcements
+(
+    id                     UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    title                  VARCHAR(200) NOT NULL,
+    message                TEXT         NOT NULL,
+    severity               VARCHAR(20)  NOT NULL DEFAULT 'info' CHECK (severity IN ('info', 'warning', 'critical')),
+    starts_at              TIMESTAMPTZ  NOT NULL,
+    ends_at                TIMESTAMPTZ  NOT NULL,
+    maintenance_starts_at  TIMESTAMPTZ,
+    maintenance_ends_at    TIMESTAMPTZ,
+    created_by             UUID         NOT NULL REFERENCES users (id),
+    created_at             TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at             TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+    CONSTRAINT chk_announcements_window CHECK (ends_at > starts_at),
+    CONSTRAINT chk_announcements_maintenance CHECK (
+        maintenance_starts_at IS NULL OR maintenance_ends_at IS NULL OR maintenance_ends_at > maintenance_starts_at
+    )
+);
+
+-- Clients poll for the announcements that have not ended yet
+CREATE INDEX idx_announcements_ends_at ON announcements (ends_at);
+
+CREATE TRIGGER update_announcements_updated_at
+    BEFORE UPDATE
+    ON announcements
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE announcements IS 'Operational messages such as planned maintenance, shown to every client until ends_at.';
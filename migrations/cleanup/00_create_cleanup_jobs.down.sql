@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_cleanup_jobs_updated_at ON cleanup_jobs;
+DROP TABLE IF EXISTS cleanup_jobs;
@@ -0,0 +1,26 @@
+CREATE TABLE cleanup_jobs
+(
+    id           UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    target       VARCHAR(20) NOT NULL CHECK (target IN ('shipments', 'devices', 'users')),
+    filter       JSONB       NOT NULL DEFAULT '{}',
+    dry_run      BOOLEAN     NOT NULL DEFAULT FALSE,
+    status       VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'completed', 'failed', 'cancelled')),
+    matched      BIGINT      NOT NULL DEFAULT 0,
+    deleted      BIGINT      NOT NULL DEFAULT 0,
+    skipped      BIGINT      NOT NULL DEFAULT 0,
+    last_error   TEXT,
+    requested_by UUID        REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at  TIMESTAMPTZ
+);
+
+CREATE INDEX idx_cleanup_jobs_created ON cleanup_jobs (created_at DESC);
+
+COMMENT ON TABLE cleanup_jobs IS 'Bulk deletes of test data, polled by admins until they finish.';
+
+CREATE TRIGGER update_cleanup_jobs_updated_at
+    BEFORE UPDATE
+    ON cleanup_jobs
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
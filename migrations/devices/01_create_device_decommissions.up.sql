@@ -0,0 +1,16 @@
+CREATE TABLE device_decommissions
+(
+    id                UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    device_id         UUID         NOT NULL UNIQUE REFERENCES devices (id) ON DELETE RESTRICT,
+    hardware_uid      VARCHAR(255) NOT NULL,
+    reason            TEXT         NOT NULL,
+    decommissioned_by UUID         NOT NULL REFERENCES users (id),
+    owner_unlinked    BOOLEAN      NOT NULL DEFAULT FALSE,
+    shipment_count    INTEGER      NOT NULL DEFAULT 0,
+    archive_key       VARCHAR(500) NOT NULL,
+    archive_sha256    VARCHAR(64)  NOT NULL,
+    archive_size      BIGINT       NOT NULL,
+    created_at        TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE device_decommissions IS 'Certificates of retired devices with the checksum of their history archive.';
@@ -0,0 +1,19 @@
+CREATE TABLE device_transfers
+(
+    id              UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    device_id       UUID        NOT NULL REFERENCES devices (id) ON DELETE CASCADE,
+    from_shipper_id UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    to_shipper_id   UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    note            TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    responded_at    TIMESTAMPTZ,
+    CHECK (from_shipper_id <> to_shipper_id)
+);
+
+-- A device is offered to at most one shipper at a time
+CREATE UNIQUE INDEX idx_device_transfers_pending ON device_transfers (device_id) WHERE status = 'pending';
+CREATE INDEX idx_device_transfers_from ON device_transfers (from_shipper_id, created_at DESC);
+CREATE INDEX idx_device_transfers_to ON device_transfers (to_shipper_id, created_at DESC);
+
+COMMENT ON TABLE device_transfers IS 'Ownership transfers of devices between shippers; history stays with the shipper who recorded it.';
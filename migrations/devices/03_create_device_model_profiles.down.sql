@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS device_model_profiles;
+DROP INDEX IF EXISTS idx_devices_model;
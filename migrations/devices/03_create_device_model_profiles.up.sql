@@ -0,0 +1,19 @@
+CREATE TABLE device_model_profiles
+(
+    model        VARCHAR(50) PRIMARY KEY,
+    capabilities JSONB       NOT NULL DEFAULT '[]',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (jsonb_typeof(capabilities) = 'array')
+);
+
+-- Devices are matched to their profile by devices.model, which stays free text
+CREATE INDEX idx_devices_model ON devices (model);
+
+CREATE TRIGGER update_device_model_profiles_updated_at
+    BEFORE UPDATE
+    ON device_model_profiles
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE device_model_profiles IS 'Sensors each tracker model carries; devices of a model without a profile have unknown capabilities.';
@@ -0,0 +1,3 @@
+ALTER TABLE devices
+    DROP COLUMN IF EXISTS credential_issued_at,
+    DROP COLUMN IF EXISTS credential_hash;
@@ -0,0 +1,5 @@
+ALTER TABLE devices
+    ADD COLUMN credential_hash      VARCHAR(64) UNIQUE,
+    ADD COLUMN credential_issued_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN devices.credential_hash IS 'SHA-256 of the secret the device authenticates to the device API with; NULL until one is issued.';
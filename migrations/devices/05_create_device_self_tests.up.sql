@@ -0,0 +1,21 @@
+CREATE TABLE device_self_tests
+(
+    id            UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    device_id     UUID        NOT NULL REFERENCES devices (id) ON DELETE CASCADE,
+    requested_by  UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'passed', 'failed')),
+    requested_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at  TIMESTAMPTZ,
+    sensors_ok    BOOLEAN,
+    readings      JSONB       NOT NULL DEFAULT '{}',
+    gps_fix       BOOLEAN,
+    battery_level INTEGER CHECK (battery_level BETWEEN 0 AND 100),
+    signal_dbm    INTEGER,
+    failures      JSONB       NOT NULL DEFAULT '[]'
+);
+
+-- A device runs at most one self-test at a time
+CREATE UNIQUE INDEX idx_device_self_tests_pending ON device_self_tests (device_id) WHERE status = 'pending';
+CREATE INDEX idx_device_self_tests_device ON device_self_tests (device_id, requested_at DESC);
+
+COMMENT ON TABLE device_self_tests IS 'Self-tests devices run before assignment; a recent pass can be required to accept an order.';
@@ -0,0 +1,3 @@
+ALTER TABLE shipment_attachments DROP CONSTRAINT IF EXISTS fk_shipment_attachments_checklist_item;
+DROP TABLE IF EXISTS shipment_document_checklist;
+DROP TABLE IF EXISTS shipment_attachments;
@@ -0,0 +1,47 @@
+CREATE TABLE shipment_attachments
+(
+    id                UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    shipment_id       UUID         NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    checklist_item_id UUID,
+    document_type     VARCHAR(50)  NOT NULL,
+    file_name         VARCHAR(255) NOT NULL,
+    content_type      VARCHAR(100) NOT NULL,
+    size_bytes        BIGINT       NOT NULL,
+    storage_key       TEXT         NOT NULL,
+    uploaded_by       UUID         NOT NULL REFERENCES users (id),
+    created_at        TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE TABLE shipment_document_checklist
+(
+    id            UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    shipment_id   UUID         NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    document_type VARCHAR(50)  NOT NULL,
+    label         VARCHAR(255) NOT NULL,
+    required      BOOLEAN      NOT NULL DEFAULT TRUE,
+    status        VARCHAR(20)  NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'submitted', 'verified', 'rejected')),
+    attachment_id UUID REFERENCES shipment_attachments (id) ON DELETE SET NULL,
+    reviewed_by   UUID REFERENCES users (id),
+    reviewed_at   TIMESTAMPTZ,
+    review_note   TEXT,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+ALTER TABLE shipment_attachments
+    ADD CONSTRAINT fk_shipment_attachments_checklist_item
+        FOREIGN KEY (checklist_item_id)
+            REFERENCES shipment_document_checklist (id)
+            ON DELETE SET NULL;
+
+CREATE INDEX idx_shipment_attachments_shipment ON shipment_attachments (shipment_id, created_at DESC);
+CREATE INDEX idx_shipment_document_checklist_shipment ON shipment_document_checklist (shipment_id);
+
+CREATE TRIGGER update_shipment_document_checklist_updated_at
+    BEFORE UPDATE
+    ON shipment_document_checklist
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE shipment_document_checklist IS 'Documents a provider requires before a shipment may start transit.';
@@ -0,0 +1,5 @@
+ALTER TABLE shipment_attachments
+    DROP COLUMN IF EXISTS visible_to_admin,
+    DROP COLUMN IF EXISTS visible_to_shipper,
+    DROP COLUMN IF EXISTS visible_to_provider,
+    DROP COLUMN IF EXISTS visible_to_customer;
@@ -0,0 +1,12 @@
+ALTER TABLE shipment_attachments
+    ADD COLUMN visible_to_customer BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN visible_to_provider BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN visible_to_shipper  BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN visible_to_admin    BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Commercial invoices carry prices and are hidden from shippers by default
+UPDATE shipment_attachments
+SET visible_to_shipper = FALSE
+WHERE document_type = 'commercial_invoice';
+
+COMMENT ON COLUMN shipment_attachments.visible_to_shipper IS 'Whether the assigned shipper may list and download the file; the uploader always can.';
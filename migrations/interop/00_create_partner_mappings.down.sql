@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_interop_partner_mappings_updated_at ON interop_partner_mappings;
+DROP TABLE IF EXISTS interop_partner_mappings;
@@ -0,0 +1,23 @@
+CREATE TABLE interop_partner_mappings
+(
+    id                  UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    owner_id            UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name                VARCHAR(100) NOT NULL,
+    format              VARCHAR(20)  NOT NULL CHECK (format IN ('epcis', 'json')),
+    records_path        VARCHAR(255) NOT NULL DEFAULT '',
+    import_fields       JSONB        NOT NULL DEFAULT '{}',
+    default_provider_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    export_fields       JSONB        NOT NULL DEFAULT '{}',
+    is_active           BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at          TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    UNIQUE (owner_id, name)
+);
+
+CREATE INDEX idx_interop_partner_mappings_owner ON interop_partner_mappings (owner_id);
+
+CREATE TRIGGER update_interop_partner_mappings_updated_at
+    BEFORE UPDATE
+    ON interop_partner_mappings
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
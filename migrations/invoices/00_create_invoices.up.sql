@@ -0,0 +1,49 @@
+CREATE TABLE invoices
+(
+    id                UUID PRIMARY KEY        DEFAULT gen_random_uuid(),
+    number            VARCHAR(30)    NOT NULL UNIQUE,
+    provider_id       UUID           NOT NULL REFERENCES users (id),
+    customer_id       UUID           NOT NULL REFERENCES users (id),
+    period_start      TIMESTAMPTZ    NOT NULL,
+    period_end        TIMESTAMPTZ    NOT NULL,
+    currency          VARCHAR(3)     NOT NULL DEFAULT 'VND',
+    subtotal          DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    adjustments       DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    total             DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    status            VARCHAR(20)    NOT NULL DEFAULT 'issued' CHECK (status IN ('issued', 'paid', 'void')),
+    due_at            TIMESTAMPTZ    NOT NULL,
+    paid_at           TIMESTAMPTZ,
+    payment_reference VARCHAR(255),
+    created_at        TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ    NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_invoices_period UNIQUE (provider_id, customer_id, currency, period_start)
+);
+
+CREATE INDEX idx_invoices_provider ON invoices (provider_id, period_start DESC);
+CREATE INDEX idx_invoices_customer ON invoices (customer_id, period_start DESC);
+
+CREATE TRIGGER update_invoices_updated_at
+    BEFORE UPDATE
+    ON invoices
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE invoice_lines
+(
+    id          UUID PRIMARY KEY        DEFAULT gen_random_uuid(),
+    invoice_id  UUID           NOT NULL REFERENCES invoices (id) ON DELETE CASCADE,
+    shipment_id UUID REFERENCES shipments (id) ON DELETE SET NULL,
+    kind        VARCHAR(30)    NOT NULL CHECK (kind IN ('shipment_fee', 'cancellation_fee', 'sla_penalty', 'adjustment')),
+    description TEXT           NOT NULL,
+    amount      DECIMAL(14, 2) NOT NULL,
+    priced      BOOLEAN        NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_invoice_lines_invoice ON invoice_lines (invoice_id);
+-- A shipment is billed at most once
+CREATE UNIQUE INDEX idx_invoice_lines_shipment_kind ON invoice_lines (shipment_id, kind) WHERE shipment_id IS NOT NULL;
+
+COMMENT ON TABLE invoices IS 'Monthly invoices of a provider to a customer, generated when the billing period closes.';
+COMMENT ON COLUMN invoice_lines.priced IS 'False when the shipment had no accepted quote and was billed at zero.';
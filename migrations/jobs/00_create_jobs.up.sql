@@ -0,0 +1,38 @@
+CREATE TABLE jobs
+(
+    id                UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    kind              VARCHAR(50)  NOT NULL,
+    owner_id          UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status            VARCHAR(20)  NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    payload           JSONB        NOT NULL DEFAULT '{}',
+    progress          INTEGER      NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    progress_message  VARCHAR(255),
+    result            JSONB,
+    result_key        TEXT,
+    result_file_name  VARCHAR(255),
+    result_type       VARCHAR(100),
+    result_size       BIGINT,
+    error             TEXT,
+    attempts          INTEGER      NOT NULL DEFAULT 0,
+    max_attempts      INTEGER      NOT NULL DEFAULT 1,
+    run_at            TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    locked_until      TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    started_at        TIMESTAMPTZ,
+    finished_at       TIMESTAMPTZ
+);
+
+-- Workers poll for due queued jobs and expired leases
+CREATE INDEX idx_jobs_due ON jobs (run_at) WHERE status = 'queued';
+CREATE INDEX idx_jobs_leases ON jobs (locked_until) WHERE status = 'running';
+CREATE INDEX idx_jobs_owner ON jobs (owner_id, created_at DESC);
+CREATE INDEX idx_jobs_finished ON jobs (finished_at) WHERE finished_at IS NOT NULL;
+
+COMMENT ON TABLE jobs IS 'Long-running work queued by API requests; clients poll GET /jobs/:id.';
+
+CREATE TRIGGER update_jobs_updated_at
+    BEFORE UPDATE
+    ON jobs
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,20 @@
+CREATE TABLE notification_templates
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    event      VARCHAR(100) NOT NULL,
+    channel    VARCHAR(20)  NOT NULL,
+    language   VARCHAR(10)  NOT NULL,
+    version    INTEGER      NOT NULL CHECK (version > 0),
+    subject    TEXT         NOT NULL,
+    body       TEXT         NOT NULL,
+    is_active  BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_notification_templates_version UNIQUE (event, channel, language, version)
+);
+
+-- At most one active version per event, channel and language
+CREATE UNIQUE INDEX uq_notification_templates_active
+    ON notification_templates (event, channel, language)
+    WHERE is_active;
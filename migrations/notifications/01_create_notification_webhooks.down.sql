@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_notification_webhooks_updated_at ON notification_webhooks;
+DROP TABLE IF EXISTS notification_webhooks;
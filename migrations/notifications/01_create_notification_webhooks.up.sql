@@ -0,0 +1,27 @@
+CREATE TABLE notification_webhooks
+(
+    id                   UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    owner_id             UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name                 VARCHAR(100) NOT NULL,
+    kind                 VARCHAR(20)  NOT NULL CHECK (kind IN ('slack', 'teams')),
+    url                  TEXT         NOT NULL,
+    scope                VARCHAR(20)  NOT NULL DEFAULT 'owner' CHECK (scope IN ('owner', 'platform')),
+    min_severity         VARCHAR(20)  NOT NULL DEFAULT 'critical' CHECK (min_severity IN ('info', 'critical')),
+    daily_digest         BOOLEAN      NOT NULL DEFAULT FALSE,
+    is_active            BOOLEAN      NOT NULL DEFAULT TRUE,
+    last_delivery_at     TIMESTAMPTZ,
+    last_success_at      TIMESTAMPTZ,
+    last_error           TEXT,
+    consecutive_failures INTEGER      NOT NULL DEFAULT 0,
+    created_at           TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at           TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notification_webhooks_owner ON notification_webhooks (owner_id) WHERE is_active;
+CREATE INDEX idx_notification_webhooks_platform ON notification_webhooks (scope) WHERE is_active AND scope = 'platform';
+
+CREATE TRIGGER update_notification_webhooks_updated_at
+    BEFORE UPDATE
+    ON notification_webhooks
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS chat_deliveries;
+DROP TABLE IF EXISTS chat_link_codes;
+DROP TRIGGER IF EXISTS update_chat_links_updated_at ON chat_links;
+DROP TABLE IF EXISTS chat_links;
@@ -0,0 +1,52 @@
+CREATE TABLE chat_links
+(
+    id         UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    user_id    UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider   VARCHAR(20)  NOT NULL CHECK (provider IN ('telegram', 'zalo')),
+    chat_id    VARCHAR(100) NOT NULL,
+    is_active  BOOLEAN      NOT NULL DEFAULT TRUE,
+    linked_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_chat_links_user_provider UNIQUE (user_id, provider)
+);
+
+CREATE INDEX idx_chat_links_chat ON chat_links (provider, chat_id);
+
+CREATE TRIGGER update_chat_links_updated_at
+    BEFORE UPDATE
+    ON chat_links
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE chat_link_codes
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider   VARCHAR(20) NOT NULL CHECK (provider IN ('telegram', 'zalo')),
+    code_hash  VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX uq_chat_link_codes_hash ON chat_link_codes (provider, code_hash);
+
+CREATE TABLE chat_deliveries
+(
+    id                  UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    link_id             UUID         NOT NULL REFERENCES chat_links (id) ON DELETE CASCADE,
+    user_id             UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider            VARCHAR(20)  NOT NULL,
+    event               VARCHAR(100) NOT NULL,
+    provider_message_id VARCHAR(100),
+    status              VARCHAR(20)  NOT NULL CHECK (status IN ('failed', 'sent', 'delivered', 'seen')),
+    error               TEXT,
+    sent_at             TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    delivered_at        TIMESTAMPTZ,
+    seen_at             TIMESTAMPTZ
+);
+
+CREATE INDEX idx_chat_deliveries_user ON chat_deliveries (user_id, sent_at DESC);
+CREATE INDEX idx_chat_deliveries_message ON chat_deliveries (provider, provider_message_id)
+    WHERE provider_message_id IS NOT NULL;
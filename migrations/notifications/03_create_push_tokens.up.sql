@@ -0,0 +1,28 @@
+CREATE TABLE push_tokens
+(
+    id           UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider     VARCHAR(10)  NOT NULL CHECK (provider IN ('fcm', 'apns')),
+    token        VARCHAR(512) NOT NULL,
+    platform     VARCHAR(10)  NOT NULL CHECK (platform IN ('android', 'ios')),
+    device_name  VARCHAR(100),
+    app_version  VARCHAR(50),
+    last_seen_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_push_tokens_token UNIQUE (provider, token)
+);
+
+CREATE INDEX idx_push_tokens_user ON push_tokens (user_id);
+CREATE INDEX idx_push_tokens_last_seen ON push_tokens (last_seen_at);
+
+CREATE TABLE push_subscriptions
+(
+    user_id    UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    topic      VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (user_id, topic)
+);
+
+CREATE INDEX idx_push_subscriptions_topic ON push_subscriptions (topic);
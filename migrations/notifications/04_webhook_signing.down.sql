@@ -0,0 +1,10 @@
+ALTER TABLE notification_webhooks
+    DROP COLUMN IF EXISTS secret_rotated_at,
+    DROP COLUMN IF EXISTS previous_signing_secret,
+    DROP COLUMN IF EXISTS signing_secret;
+
+DELETE FROM notification_webhooks WHERE kind = 'generic';
+
+ALTER TABLE notification_webhooks
+    DROP CONSTRAINT IF EXISTS notification_webhooks_kind_check,
+    ADD CONSTRAINT notification_webhooks_kind_check CHECK (kind IN ('slack', 'teams'));
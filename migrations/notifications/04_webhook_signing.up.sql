@@ -0,0 +1,19 @@
+ALTER TABLE notification_webhooks
+    DROP CONSTRAINT IF EXISTS notification_webhooks_kind_check,
+    ADD CONSTRAINT notification_webhooks_kind_check CHECK (kind IN ('slack', 'teams', 'generic'));
+
+ALTER TABLE notification_webhooks
+    ADD COLUMN signing_secret          TEXT,
+    ADD COLUMN previous_signing_secret TEXT,
+    ADD COLUMN secret_rotated_at       TIMESTAMPTZ;
+
+-- Existing webhooks get a random secret; owners rotate it to learn its value
+UPDATE notification_webhooks
+SET signing_secret = 'whsec_' || replace(gen_random_uuid()::text, '-', '') || replace(gen_random_uuid()::text, '-', '')
+WHERE signing_secret IS NULL;
+
+ALTER TABLE notification_webhooks
+    ALTER COLUMN signing_secret SET NOT NULL;
+
+COMMENT ON COLUMN notification_webhooks.signing_secret IS 'HMAC-SHA256 key for the X-Cargo-Signature header; encrypted at rest.';
+COMMENT ON COLUMN notification_webhooks.previous_signing_secret IS 'Secret replaced by the last rotation, still signed with during the grace period.';
@@ -0,0 +1,23 @@
+CREATE TABLE webhook_events
+(
+    id               UUID PRIMARY KEY,
+    webhook_id       UUID         NOT NULL REFERENCES notification_webhooks (id) ON DELETE CASCADE,
+    owner_id         UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    event            VARCHAR(100) NOT NULL,
+    severity         VARCHAR(20)  NOT NULL,
+    subject          TEXT         NOT NULL,
+    body             TEXT         NOT NULL,
+    link             TEXT,
+    data             JSONB        NOT NULL DEFAULT '{}',
+    delivered        BOOLEAN      NOT NULL DEFAULT FALSE,
+    last_error       TEXT,
+    replay_count     INTEGER      NOT NULL DEFAULT 0,
+    last_replayed_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_events_owner ON webhook_events (owner_id, created_at);
+CREATE INDEX idx_webhook_events_webhook ON webhook_events (webhook_id, created_at);
+CREATE INDEX idx_webhook_events_created ON webhook_events (created_at);
+
+COMMENT ON TABLE webhook_events IS 'Archive of events delivered to webhooks, kept for replay after receiver outages.';
@@ -0,0 +1,2 @@
+ALTER TABLE notification_webhooks
+    DROP COLUMN IF EXISTS payload_filters;
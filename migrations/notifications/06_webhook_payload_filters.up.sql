@@ -0,0 +1,4 @@
+ALTER TABLE notification_webhooks
+    ADD COLUMN payload_filters JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN notification_webhooks.payload_filters IS 'Redaction preset and selected fields by event name, "*" for all events; events without a filter are sent in full.';
@@ -0,0 +1,12 @@
+CREATE TABLE usage_counters
+(
+    user_id      UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    metric       VARCHAR(30) NOT NULL CHECK (metric IN ('api_calls')),
+    period_start DATE        NOT NULL,
+    count        BIGINT      NOT NULL DEFAULT 0 CHECK (count >= 0),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (user_id, metric, period_start)
+);
+
+COMMENT ON TABLE usage_counters IS 'Monthly usage that cannot be counted from other tables; incremented with an upsert so concurrent requests never lose a count.';
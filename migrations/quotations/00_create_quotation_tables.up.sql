@@ -0,0 +1,54 @@
+CREATE TABLE quote_requests
+(
+    id                    UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    customer_id           UUID        NOT NULL REFERENCES users (id),
+    status                VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'accepted', 'cancelled', 'expired')),
+    goods_description     TEXT        NOT NULL,
+    goods_value           DECIMAL(12, 2),
+    goods_weight          DECIMAL(8, 2),
+    pickup_address        TEXT        NOT NULL,
+    delivery_address      TEXT        NOT NULL,
+    estimated_pickup_at   TIMESTAMPTZ,
+    estimated_delivery_at TIMESTAMPTZ,
+    customer_notes        TEXT,
+    expires_at            TIMESTAMPTZ NOT NULL,
+    shipment_id           UUID REFERENCES shipments (id) ON DELETE SET NULL,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE quotes
+(
+    id              UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    request_id      UUID        NOT NULL REFERENCES quote_requests (id) ON DELETE CASCADE,
+    provider_id     UUID        NOT NULL REFERENCES users (id),
+    status          VARCHAR(20) NOT NULL DEFAULT 'requested'
+        CHECK (status IN ('requested', 'submitted', 'declined', 'accepted', 'rejected')),
+    price           DECIMAL(12, 2) CHECK (price IS NULL OR price >= 0),
+    currency        VARCHAR(3)  NOT NULL DEFAULT 'VND',
+    lead_time_hours INTEGER CHECK (lead_time_hours IS NULL OR lead_time_hours >= 0),
+    valid_until     TIMESTAMPTZ,
+    notes           TEXT,
+    responded_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_quotes_request_provider UNIQUE (request_id, provider_id)
+);
+
+CREATE INDEX idx_quote_requests_customer ON quote_requests (customer_id, created_at DESC);
+CREATE INDEX idx_quote_requests_status ON quote_requests (status);
+CREATE INDEX idx_quotes_provider_status ON quotes (provider_id, status);
+
+CREATE TRIGGER update_quote_requests_updated_at
+    BEFORE UPDATE
+    ON quote_requests
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_quotes_updated_at
+    BEFORE UPDATE
+    ON quotes
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
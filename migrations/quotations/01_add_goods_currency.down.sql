@@ -0,0 +1 @@
+ALTER TABLE quote_requests DROP COLUMN IF EXISTS goods_currency;
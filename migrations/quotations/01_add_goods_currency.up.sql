@@ -0,0 +1,3 @@
+ALTER TABLE quote_requests ADD COLUMN goods_currency VARCHAR(3);
+
+COMMENT ON COLUMN quote_requests.goods_currency IS 'ISO 4217 currency of goods_value; the provider default applies when empty.';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS ref_goods_categories;
+DROP TABLE IF EXISTS ref_violation_types;
+DROP TABLE IF EXISTS ref_issue_types;
+DROP TABLE IF EXISTS ref_roles;
@@ -0,0 +1,92 @@
+-- Reference data clients look up and incoming values are validated against.
+-- IDs are stable: a value keeps its ID for good and retired IDs are never
+-- reused. The seeds upsert by ID, so they can be rerun to correct names.
+
+CREATE TABLE ref_roles
+(
+    id          SMALLINT PRIMARY KEY,
+    code        VARCHAR(30)  NOT NULL UNIQUE,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL DEFAULT ''
+);
+
+CREATE TABLE ref_issue_types
+(
+    id          SMALLINT PRIMARY KEY,
+    code        VARCHAR(50)  NOT NULL UNIQUE,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL DEFAULT ''
+);
+
+CREATE TABLE ref_violation_types
+(
+    id          SMALLINT PRIMARY KEY,
+    code        VARCHAR(50)  NOT NULL UNIQUE,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    unit        VARCHAR(20)  NOT NULL DEFAULT ''
+);
+
+CREATE TABLE ref_goods_categories
+(
+    id          SMALLINT PRIMARY KEY,
+    code        VARCHAR(50)  NOT NULL UNIQUE,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    temp_min    DECIMAL(5, 1),
+    temp_max    DECIMAL(5, 1),
+    -- Lowercase words that recognise the category in a goods description
+    keywords    JSONB        NOT NULL DEFAULT '[]',
+
+    CONSTRAINT chk_ref_goods_categories_temp CHECK (temp_min IS NULL OR temp_max IS NULL OR temp_max >= temp_min)
+);
+
+-- Must match the user_role enum
+INSERT INTO ref_roles (id, code, name, description)
+VALUES (1, 'customer', 'Customer', 'Creates shipment demands'),
+       (2, 'provider', 'Provider', 'Posts orders and sets the shipping rules'),
+       (3, 'shipper', 'Shipper', 'Carries shipments with a tracking device'),
+       (4, 'admin', 'Administrator', 'Manages the platform'),
+       (5, 'analyst', 'Analyst', 'Read-only access with personal data redacted')
+ON CONFLICT (id) DO UPDATE SET code        = EXCLUDED.code,
+                               name        = EXCLUDED.name,
+                               description = EXCLUDED.description;
+
+INSERT INTO ref_issue_types (id, code, name, description)
+VALUES (1, 'quality_violation', 'Quality violation', 'Goods left the conditions set by the shipping rules'),
+       (2, 'accident', 'Accident', 'Vehicle or handling accident'),
+       (3, 'theft', 'Theft', 'Goods or packages stolen'),
+       (4, 'delay', 'Delay', 'Delivery will miss its deadline'),
+       (5, 'other', 'Other', 'Anything else')
+ON CONFLICT (id) DO UPDATE SET code        = EXCLUDED.code,
+                               name        = EXCLUDED.name,
+                               description = EXCLUDED.description;
+
+INSERT INTO ref_violation_types (id, code, name, description, unit)
+VALUES (1, 'temperature', 'Temperature', 'Outside temp_min and temp_max', '°C'),
+       (2, 'humidity', 'Humidity', 'Outside humidity_min and humidity_max', '%'),
+       (3, 'light', 'Light exposure', 'Above light_max, e.g. the container was opened', 'lux'),
+       (4, 'tilt', 'Tilt', 'Tilted past tilt_max_angle', '°'),
+       (5, 'impact', 'Impact', 'Shock above impact_threshold_g', 'G')
+ON CONFLICT (id) DO UPDATE SET code        = EXCLUDED.code,
+                               name        = EXCLUDED.name,
+                               description = EXCLUDED.description,
+                               unit        = EXCLUDED.unit;
+
+-- Keywords are matched in ID order and the first match wins
+INSERT INTO ref_goods_categories (id, code, name, description, temp_min, temp_max, keywords)
+VALUES (1, 'frozen_goods', 'Frozen goods', 'Kept deep frozen', -30, -15,
+        '["frozen", "ice cream", "đông lạnh"]'),
+       (2, 'pharmaceuticals', 'Pharmaceuticals', 'Medicines and vaccines in the cold chain', 2, 8,
+        '["vaccine", "pharma", "insulin", "medicine", "thuốc"]'),
+       (3, 'chilled_food', 'Chilled food', 'Dairy, meat and seafood', 0, 5,
+        '["dairy", "milk", "cheese", "meat", "seafood", "fish", "thịt", "hải sản"]'),
+       (4, 'fresh_produce', 'Fresh produce', 'Fruit, vegetables and flowers', 0, 15,
+        '["fresh", "produce", "vegetable", "fruit", "flower", "rau", "trái cây"]'),
+       (5, 'general', 'General cargo', 'No temperature requirements', NULL, NULL, '[]')
+ON CONFLICT (id) DO UPDATE SET code        = EXCLUDED.code,
+                               name        = EXCLUDED.name,
+                               description = EXCLUDED.description,
+                               temp_min    = EXCLUDED.temp_min,
+                               temp_max    = EXCLUDED.temp_max,
+                               keywords    = EXCLUDED.keywords;
@@ -0,0 +1,41 @@
+CREATE TABLE report_subscriptions
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    owner_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    report_type VARCHAR(50) NOT NULL CHECK (report_type IN ('shipment_summary')),
+    cadence     VARCHAR(20) NOT NULL CHECK (cadence IN ('daily', 'weekly', 'monthly')),
+    format      VARCHAR(20) NOT NULL DEFAULT 'text' CHECK (format IN ('text', 'csv')),
+    recipients  JSONB       NOT NULL DEFAULT '[]',
+    timezone    VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    active      BOOLEAN     NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The scheduler polls for active subscriptions that are due
+CREATE INDEX idx_report_subscriptions_due ON report_subscriptions (next_run_at) WHERE active;
+CREATE INDEX idx_report_subscriptions_owner ON report_subscriptions (owner_id);
+
+CREATE TRIGGER update_report_subscriptions_updated_at
+    BEFORE UPDATE
+    ON report_subscriptions
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE report_deliveries
+(
+    id              UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    subscription_id UUID        NOT NULL REFERENCES report_subscriptions (id) ON DELETE CASCADE,
+    period_start    TIMESTAMPTZ NOT NULL,
+    period_end      TIMESTAMPTZ NOT NULL,
+    status          VARCHAR(20) NOT NULL CHECK (status IN ('sent', 'failed')),
+    recipients      JSONB       NOT NULL DEFAULT '[]',
+    error           TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_report_deliveries_subscription ON report_deliveries (subscription_id, created_at DESC);
+
+COMMENT ON TABLE report_deliveries IS 'History of scheduled report emails, one row per attempt.';
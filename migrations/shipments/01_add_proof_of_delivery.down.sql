@@ -0,0 +1 @@
+ALTER TABLE shipments DROP COLUMN IF EXISTS proof_of_delivery_url;
@@ -0,0 +1,3 @@
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS proof_of_delivery_url TEXT;
+
+COMMENT ON COLUMN shipments.proof_of_delivery_url IS 'Encrypted at rest (enc:v1 envelope).';
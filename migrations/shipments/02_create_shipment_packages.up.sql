@@ -0,0 +1,22 @@
+CREATE TABLE shipment_packages
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    shipment_id UUID        NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    sequence    INTEGER     NOT NULL CHECK (sequence > 0),
+    description TEXT        NOT NULL,
+    weight      DECIMAL(8, 2) CHECK (weight IS NULL OR weight >= 0),
+    device_id   UUID REFERENCES devices (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_shipment_packages_sequence UNIQUE (shipment_id, sequence)
+);
+
+CREATE INDEX idx_shipment_packages_shipment ON shipment_packages (shipment_id);
+CREATE INDEX idx_shipment_packages_device ON shipment_packages (device_id) WHERE device_id IS NOT NULL;
+
+CREATE TRIGGER update_shipment_packages_updated_at
+    BEFORE UPDATE
+    ON shipment_packages
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_shipment_packages_outcome;
+
+ALTER TABLE shipment_packages
+    DROP COLUMN IF EXISTS outcome_at,
+    DROP COLUMN IF EXISTS outcome_recorded_by,
+    DROP COLUMN IF EXISTS outcome_note,
+    DROP COLUMN IF EXISTS outcome;
+
+-- PostgreSQL cannot drop an enum value; move affected rows back to completed.
+UPDATE shipments SET status = 'completed' WHERE status = 'partially_completed';
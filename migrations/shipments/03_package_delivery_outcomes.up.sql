@@ -0,0 +1,10 @@
+ALTER TYPE shipment_status ADD VALUE IF NOT EXISTS 'partially_completed' AFTER 'completed';
+
+ALTER TABLE shipment_packages
+    ADD COLUMN outcome             VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (outcome IN ('pending', 'delivered', 'lost', 'damaged', 'refused')),
+    ADD COLUMN outcome_note        TEXT,
+    ADD COLUMN outcome_recorded_by UUID REFERENCES users (id),
+    ADD COLUMN outcome_at          TIMESTAMPTZ;
+
+CREATE INDEX idx_shipment_packages_outcome ON shipment_packages (outcome);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_shipments_sandbox;
+ALTER TABLE shipments DROP COLUMN IF EXISTS is_sandbox;
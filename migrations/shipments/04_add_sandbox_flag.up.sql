@@ -0,0 +1,5 @@
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS is_sandbox BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_shipments_sandbox ON shipments (is_sandbox) WHERE is_sandbox;
+
+COMMENT ON COLUMN shipments.is_sandbox IS 'Created by a sandbox account; excluded from live listings and metrics.';
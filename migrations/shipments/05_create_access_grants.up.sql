@@ -0,0 +1,17 @@
+CREATE TABLE shipment_access_grants
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    shipment_id UUID        NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    granted_by  UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    grantee_id  UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    scopes      JSONB       NOT NULL DEFAULT '[]',
+    note        TEXT,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    revoked_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_shipment_access_grants_shipment ON shipment_access_grants (shipment_id);
+CREATE INDEX idx_shipment_access_grants_grantee ON shipment_access_grants (grantee_id, expires_at) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE shipment_access_grants IS 'Temporary read access to a single shipment for third parties such as insurers and auditors.';
@@ -0,0 +1,30 @@
+CREATE TABLE carriage_terms
+(
+    id          UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    provider_id UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    version     INTEGER      NOT NULL CHECK (version > 0),
+    title       VARCHAR(255) NOT NULL,
+    body        TEXT         NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_carriage_terms_version UNIQUE (provider_id, version)
+);
+
+CREATE TABLE terms_acceptances
+(
+    id                     UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    shipment_id            UUID         NOT NULL UNIQUE REFERENCES shipments (id) ON DELETE CASCADE,
+    terms_id               UUID         NOT NULL REFERENCES carriage_terms (id),
+    terms_version          INTEGER      NOT NULL,
+    shipper_id             UUID         NOT NULL REFERENCES users (id),
+    ip_address             VARCHAR(45)  NOT NULL,
+    user_agent             VARCHAR(500),
+    signature_key          TEXT         NOT NULL,
+    signature_content_type VARCHAR(100) NOT NULL,
+    accepted_at            TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_terms_acceptances_terms ON terms_acceptances (terms_id);
+
+COMMENT ON TABLE carriage_terms IS 'Versioned terms of carriage published by providers. Rows are never updated.';
+COMMENT ON TABLE terms_acceptances IS 'Signed acceptance of a terms version by the shipper, captured when shipping rules are confirmed.';
@@ -0,0 +1,2 @@
+ALTER TABLE shipments DROP COLUMN IF EXISTS delivery_due_at;
+DROP TABLE IF EXISTS business_calendars;
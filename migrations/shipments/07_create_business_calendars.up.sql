@@ -0,0 +1,30 @@
+CREATE TABLE business_calendars
+(
+    provider_id   UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    timezone      VARCHAR(64) NOT NULL,
+    working_days  JSONB       NOT NULL DEFAULT '[]',
+    open_minute   INTEGER     NOT NULL CHECK (open_minute >= 0 AND open_minute < 1440),
+    close_minute  INTEGER     NOT NULL CHECK (close_minute > 0 AND close_minute <= 1440),
+    cutoff_minute INTEGER CHECK (cutoff_minute IS NULL OR (cutoff_minute >= 0 AND cutoff_minute <= 1440)),
+    holidays      JSONB       NOT NULL DEFAULT '[]',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT chk_business_calendars_hours CHECK (open_minute < close_minute)
+);
+
+CREATE TRIGGER update_business_calendars_updated_at
+    BEFORE UPDATE
+    ON business_calendars
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE shipments
+    ADD COLUMN delivery_due_at TIMESTAMPTZ;
+
+UPDATE shipments
+SET delivery_due_at = estimated_delivery_at
+WHERE estimated_delivery_at IS NOT NULL;
+
+COMMENT ON TABLE business_calendars IS 'Provider working days, hours, order cutoff and holidays used for delivery deadlines and estimates.';
+COMMENT ON COLUMN shipments.delivery_due_at IS 'Estimated delivery moved into the provider''s working hours; delays are measured against it.';
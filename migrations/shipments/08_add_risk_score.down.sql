@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_shipments_risk_score;
+
+ALTER TABLE shipments
+    DROP COLUMN IF EXISTS risk_assessed_at,
+    DROP COLUMN IF EXISTS risk_factors,
+    DROP COLUMN IF EXISTS risk_score;
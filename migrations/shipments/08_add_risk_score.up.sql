@@ -0,0 +1,9 @@
+ALTER TABLE shipments
+    ADD COLUMN risk_score       INTEGER CHECK (risk_score IS NULL OR (risk_score >= 0 AND risk_score <= 100)),
+    ADD COLUMN risk_factors     JSONB,
+    ADD COLUMN risk_assessed_at TIMESTAMPTZ;
+
+CREATE INDEX idx_shipments_risk_score ON shipments (risk_score) WHERE risk_score IS NOT NULL;
+
+COMMENT ON COLUMN shipments.risk_score IS 'Weighted 0-100 risk score, higher is riskier; recomputed when the order is accepted.';
+COMMENT ON COLUMN shipments.risk_factors IS 'Per-factor breakdown (name, score, weight, detail) behind risk_score.';
@@ -0,0 +1,38 @@
+CREATE TABLE trips
+(
+    id           UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    shipper_id   UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    device_id    UUID         NOT NULL REFERENCES devices (id),
+    name         VARCHAR(100),
+    status       VARCHAR(20)  NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'cancelled')),
+    completed_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_trips_shipper ON trips (shipper_id, created_at DESC);
+-- A device reports for at most one active trip
+CREATE UNIQUE INDEX idx_trips_active_device ON trips (device_id) WHERE status = 'active';
+
+CREATE TRIGGER update_trips_updated_at
+    BEFORE UPDATE
+    ON trips
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE trip_stops
+(
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id      UUID    NOT NULL REFERENCES trips (id) ON DELETE CASCADE,
+    shipment_id  UUID    NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    sequence     INTEGER NOT NULL CHECK (sequence > 0),
+    completed_at TIMESTAMPTZ,
+
+    CONSTRAINT uq_trip_stops_sequence UNIQUE (trip_id, sequence),
+    CONSTRAINT uq_trip_stops_shipment UNIQUE (trip_id, shipment_id)
+);
+
+CREATE INDEX idx_trip_stops_shipment ON trip_stops (shipment_id);
+
+COMMENT ON TABLE trips IS 'Shipments a shipper carries together; the trip device is linked to every member shipment.';
+COMMENT ON TABLE trip_stops IS 'Ordered delivery stops of a trip, one per member shipment.';
@@ -0,0 +1,3 @@
+ALTER TABLE shipments
+    DROP COLUMN IF EXISTS delivery_address_id,
+    DROP COLUMN IF EXISTS pickup_address_id;
@@ -0,0 +1,6 @@
+ALTER TABLE shipments
+    ADD COLUMN pickup_address_id   UUID REFERENCES saved_addresses (id) ON DELETE SET NULL,
+    ADD COLUMN delivery_address_id UUID REFERENCES saved_addresses (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN shipments.pickup_address_id IS 'Address book entry the pickup address was taken from, if any.';
+COMMENT ON COLUMN shipments.delivery_address_id IS 'Address book entry the delivery address was taken from, if any.';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_shipments_search_text;
+ALTER TABLE shipments DROP COLUMN IF EXISTS search_text;
+
+-- search_normalize and the extensions are shared with the users migration
+-- and are left in place
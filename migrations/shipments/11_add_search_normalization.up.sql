@@ -0,0 +1,20 @@
+-- Accent and case insensitive search. unaccent() is only STABLE, so it is
+-- wrapped in an IMMUTABLE function that generated columns and indexes accept.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE OR REPLACE FUNCTION search_normalize(value TEXT) RETURNS TEXT AS
+$$
+SELECT regexp_replace(lower(public.unaccent('public.unaccent'::regdictionary, COALESCE(value, ''))), '\s+', ' ', 'g')
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+ALTER TABLE shipments
+    ADD COLUMN search_text TEXT GENERATED ALWAYS AS (
+        search_normalize(goods_description) || ' ' ||
+        search_normalize(pickup_address) || ' ' ||
+        search_normalize(delivery_address)
+    ) STORED;
+
+CREATE INDEX idx_shipments_search_text ON shipments USING gin (search_text gin_trgm_ops);
+
+COMMENT ON COLUMN shipments.search_text IS 'Lowercased, unaccented goods description and addresses for listing search.';
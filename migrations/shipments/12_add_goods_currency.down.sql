@@ -0,0 +1,5 @@
+ALTER TABLE shipments
+    DROP COLUMN IF EXISTS exchange_rated_at,
+    DROP COLUMN IF EXISTS exchange_currency,
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS goods_currency;
@@ -0,0 +1,16 @@
+ALTER TABLE shipments
+    ADD COLUMN goods_currency      VARCHAR(3) NOT NULL DEFAULT 'VND',
+    ADD COLUMN exchange_rate       DECIMAL(20, 8) CHECK (exchange_rate IS NULL OR exchange_rate > 0),
+    ADD COLUMN exchange_currency   VARCHAR(3),
+    ADD COLUMN exchange_rated_at   TIMESTAMPTZ;
+
+-- Existing goods values were all entered in VND, the reporting currency
+UPDATE shipments
+SET exchange_rate     = 1,
+    exchange_currency = 'VND',
+    exchange_rated_at = created_at
+WHERE goods_value IS NOT NULL;
+
+COMMENT ON COLUMN shipments.goods_currency IS 'ISO 4217 currency of goods_value.';
+COMMENT ON COLUMN shipments.exchange_rate IS 'Value of one unit of goods_currency in exchange_currency when the shipment was created.';
+COMMENT ON COLUMN shipments.exchange_currency IS 'Reporting currency exchange_rate converts into.';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS shipment_watchdog_stages;
+DROP INDEX IF EXISTS idx_shipments_status_changed_at;
+DROP TRIGGER IF EXISTS set_shipments_status_changed_at ON shipments;
+DROP FUNCTION IF EXISTS set_shipment_status_changed_at();
+ALTER TABLE shipments DROP COLUMN IF EXISTS status_changed_at;
@@ -0,0 +1,43 @@
+ALTER TABLE shipments
+    ADD COLUMN status_changed_at TIMESTAMPTZ;
+
+-- Best estimate for existing rows; exact from now on
+UPDATE shipments
+SET status_changed_at = COALESCE(updated_at, created_at, now());
+
+ALTER TABLE shipments
+    ALTER COLUMN status_changed_at SET DEFAULT now(),
+    ALTER COLUMN status_changed_at SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION set_shipment_status_changed_at()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.status IS DISTINCT FROM OLD.status THEN
+        NEW.status_changed_at = now();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_shipments_status_changed_at
+    BEFORE UPDATE OF status
+    ON shipments
+    FOR EACH ROW
+EXECUTE FUNCTION set_shipment_status_changed_at();
+
+CREATE INDEX idx_shipments_status_changed_at ON shipments (status, status_changed_at)
+    WHERE status IN ('demand_created', 'order_posted', 'shipping_assigned', 'issue_reported');
+
+CREATE TABLE shipment_watchdog_stages
+(
+    shipment_id       UUID            NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    status            shipment_status NOT NULL,
+    status_changed_at TIMESTAMPTZ     NOT NULL,
+    stage             VARCHAR(20)     NOT NULL CHECK (stage IN ('reminded', 'escalated', 'reverted')),
+    created_at        TIMESTAMPTZ     NOT NULL DEFAULT now(),
+    PRIMARY KEY (shipment_id, status_changed_at, stage)
+);
+
+COMMENT ON COLUMN shipments.status_changed_at IS 'When the shipment entered its current status; set by trigger.';
+COMMENT ON TABLE shipment_watchdog_stages IS 'Watchdog steps taken for a shipment stuck in a status, one row per stay in that status and stage.';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS shipment_events_immutable ON shipment_events;
+DROP FUNCTION IF EXISTS reject_shipment_event_update();
+DROP TABLE IF EXISTS shipment_events;
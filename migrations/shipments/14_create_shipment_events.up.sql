@@ -0,0 +1,35 @@
+CREATE TABLE shipment_events
+(
+    id          BIGSERIAL PRIMARY KEY,
+    shipment_id UUID        NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    type        VARCHAR(30) NOT NULL,
+    actor_id    UUID REFERENCES users (id) ON DELETE SET NULL,
+    data        JSONB       NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_shipment_events_shipment ON shipment_events (shipment_id, id);
+
+-- Events are never edited; they only go away together with their shipment.
+-- The one exception is the actor being cleared when their account is deleted.
+CREATE OR REPLACE FUNCTION reject_shipment_event_update()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.actor_id IS NULL
+        AND (NEW.id, NEW.shipment_id, NEW.type, NEW.data, NEW.occurred_at)
+            IS NOT DISTINCT FROM (OLD.id, OLD.shipment_id, OLD.type, OLD.data, OLD.occurred_at) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'shipment events are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER shipment_events_immutable
+    BEFORE UPDATE
+    ON shipment_events
+    FOR EACH ROW
+EXECUTE FUNCTION reject_shipment_event_update();
+
+COMMENT ON TABLE shipment_events IS 'Append-only log of shipment lifecycle changes; replaying it rebuilds a shipment at any point in time.';
+COMMENT ON COLUMN shipment_events.data IS 'Fields the event changed.';
@@ -0,0 +1 @@
+ALTER TABLE shipments DROP COLUMN IF EXISTS goods_category;
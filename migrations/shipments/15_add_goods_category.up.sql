@@ -0,0 +1,5 @@
+-- Requires reference/00_create_reference_tables
+ALTER TABLE shipments
+    ADD COLUMN goods_category VARCHAR(50) REFERENCES ref_goods_categories (code) ON UPDATE CASCADE;
+
+COMMENT ON COLUMN shipments.goods_category IS 'Optional; when NULL the category is recognised from the goods description.';
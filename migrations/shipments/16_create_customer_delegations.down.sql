@@ -0,0 +1,2 @@
+ALTER TABLE shipments DROP COLUMN IF EXISTS booked_by;
+DROP TABLE IF EXISTS customer_delegations;
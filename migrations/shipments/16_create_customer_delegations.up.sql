@@ -0,0 +1,20 @@
+CREATE TABLE customer_delegations
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    customer_id UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    delegate_id UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    note        TEXT,
+    revoked_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (customer_id <> delegate_id)
+);
+
+CREATE UNIQUE INDEX idx_customer_delegations_active ON customer_delegations (customer_id, delegate_id) WHERE revoked_at IS NULL;
+CREATE INDEX idx_customer_delegations_delegate ON customer_delegations (delegate_id) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE customer_delegations IS 'Forwarders allowed to book and manage shipments in a customer''s name.';
+
+ALTER TABLE shipments
+    ADD COLUMN booked_by UUID REFERENCES users (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN shipments.booked_by IS 'Delegate who created the shipment for the customer; NULL when the customer booked it.';
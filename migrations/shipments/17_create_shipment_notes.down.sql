@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS shipment_note_revisions;
+DROP TABLE IF EXISTS shipment_notes;
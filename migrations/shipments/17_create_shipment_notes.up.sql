@@ -0,0 +1,26 @@
+CREATE TABLE shipment_notes
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    shipment_id UUID        NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    author_id   UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    visibility  VARCHAR(20) NOT NULL CHECK (visibility IN ('internal', 'parties')),
+    body        TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_shipment_notes_shipment ON shipment_notes (shipment_id, created_at);
+
+CREATE TABLE shipment_note_revisions
+(
+    id         BIGSERIAL PRIMARY KEY,
+    note_id    UUID        NOT NULL REFERENCES shipment_notes (id) ON DELETE CASCADE,
+    body       TEXT        NOT NULL,
+    visibility VARCHAR(20) NOT NULL,
+    edited_by  UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    edited_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_shipment_note_revisions_note ON shipment_note_revisions (note_id, id);
+
+COMMENT ON TABLE shipment_note_revisions IS 'Earlier versions of a shipment note, one row per edit.';
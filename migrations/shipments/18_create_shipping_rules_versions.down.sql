@@ -0,0 +1,2 @@
+ALTER TABLE shipments DROP COLUMN IF EXISTS rules_version_id;
+DROP TABLE IF EXISTS shipping_rules_versions;
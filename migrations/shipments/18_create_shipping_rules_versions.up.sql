@@ -0,0 +1,24 @@
+CREATE TABLE shipping_rules_versions
+(
+    id                      UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    shipment_id             UUID        NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    version                 INTEGER     NOT NULL,
+    report_cycle_sec        INTEGER     NOT NULL,
+    temp_min                DECIMAL(5, 2),
+    temp_max                DECIMAL(5, 2),
+    humidity_min            DECIMAL(5, 2),
+    humidity_max            DECIMAL(5, 2),
+    light_max               DECIMAL(10, 2),
+    tilt_max_angle          DECIMAL(5, 2),
+    impact_threshold_g      DECIMAL(5, 2),
+    enable_predictive_alert BOOLEAN     NOT NULL DEFAULT false,
+    alert_buffer_time_min   INTEGER     NOT NULL DEFAULT 0,
+    set_by                  UUID        NOT NULL REFERENCES users (id),
+    set_at                  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (shipment_id, version)
+);
+
+ALTER TABLE shipments
+    ADD COLUMN rules_version_id UUID REFERENCES shipping_rules_versions (id) ON DELETE SET NULL;
+
+COMMENT ON TABLE shipping_rules_versions IS 'Every state the shipping rules of a shipment have been in. Rules set before this table existed get their first version on their first change.';
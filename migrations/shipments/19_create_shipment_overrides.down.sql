@@ -0,0 +1 @@
+DROP TABLE IF EXISTS shipment_overrides;
@@ -0,0 +1,16 @@
+CREATE TABLE shipment_overrides
+(
+    id            UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    action        VARCHAR(30) NOT NULL CHECK (action IN ('force_status', 'release_device', 'confirm_rules')),
+    shipment_id   UUID REFERENCES shipments (id) ON DELETE CASCADE,
+    device_id     UUID REFERENCES devices (id) ON DELETE SET NULL,
+    admin_id      UUID        NOT NULL REFERENCES users (id),
+    justification TEXT        NOT NULL CHECK (length(trim(justification)) > 0),
+    from_status   VARCHAR(30),
+    to_status     VARCHAR(30),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_shipment_overrides_shipment ON shipment_overrides (shipment_id, created_at);
+
+COMMENT ON TABLE shipment_overrides IS 'Audit trail of changes admins forced past the normal shipment and device checks.';
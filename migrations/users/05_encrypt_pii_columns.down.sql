@@ -0,0 +1,9 @@
+-- Rows must be decrypted before rolling back, otherwise ciphertext will not fit
+DROP INDEX IF EXISTS idx_users_phone_number_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_number_hash;
+
+ALTER TABLE users ALTER COLUMN phone_number TYPE VARCHAR(20);
+ALTER TABLE users ADD CONSTRAINT users_phone_number_key UNIQUE (phone_number);
+
+COMMENT ON COLUMN users.phone_number IS NULL;
+COMMENT ON COLUMN users.address IS NULL;
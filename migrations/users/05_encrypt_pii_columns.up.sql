@@ -0,0 +1,12 @@
+-- Phone numbers and addresses are encrypted by the application (AES-GCM
+-- envelopes), so the columns must hold arbitrary length ciphertext.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_phone_number_key;
+ALTER TABLE users ALTER COLUMN phone_number TYPE TEXT;
+
+-- Ciphertext is randomized, so uniqueness is enforced on a keyed blind index
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_number_hash VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number_hash ON users (phone_number_hash);
+
+COMMENT ON COLUMN users.phone_number IS 'Encrypted at rest (enc:v1 envelope).';
+COMMENT ON COLUMN users.address IS 'Encrypted at rest (enc:v1 envelope).';
+COMMENT ON COLUMN users.phone_number_hash IS 'HMAC-SHA256 blind index of the phone number for uniqueness checks.';
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_provider_branding_updated_at ON provider_branding;
+DROP TABLE IF EXISTS provider_branding;
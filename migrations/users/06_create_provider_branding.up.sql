@@ -0,0 +1,22 @@
+CREATE TABLE provider_branding
+(
+    provider_id       UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    display_name      VARCHAR(255),
+    primary_color     VARCHAR(7),
+    accent_color      VARCHAR(7),
+    support_email     VARCHAR(255),
+    support_phone     VARCHAR(50),
+    support_url       VARCHAR(500),
+    logo_key          TEXT,
+    logo_content_type VARCHAR(100),
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_provider_branding_updated_at
+    BEFORE UPDATE
+    ON provider_branding
+    FOR EACH ROW
+EXECUTE PROCEDURE update_updated_at_column();
+
+COMMENT ON TABLE provider_branding IS 'Logo, colors and support contact shown on tracking links and exported documents.';
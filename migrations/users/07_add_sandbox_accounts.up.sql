@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_sandbox BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN users.is_sandbox IS 'Sandbox accounts only see and create sandbox data.';
@@ -0,0 +1,37 @@
+CREATE TABLE saved_addresses
+(
+    id            UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    owner_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    label         VARCHAR(100) NOT NULL,
+    line1         VARCHAR(255) NOT NULL,
+    line2         VARCHAR(255),
+    ward          VARCHAR(100),
+    district      VARCHAR(100),
+    city          VARCHAR(100) NOT NULL,
+    province      VARCHAR(100),
+    postal_code   VARCHAR(20),
+    country_code  CHAR(2)      NOT NULL,
+    latitude      DOUBLE PRECISION CHECK (latitude IS NULL OR (latitude >= -90 AND latitude <= 90)),
+    longitude     DOUBLE PRECISION CHECK (longitude IS NULL OR (longitude >= -180 AND longitude <= 180)),
+    contact_name  VARCHAR(255),
+    contact_phone TEXT,
+    status        VARCHAR(20)  NOT NULL DEFAULT 'unverified' CHECK (status IN ('unverified', 'verified')),
+    verified_at   TIMESTAMPTZ,
+    use_count     INTEGER      NOT NULL DEFAULT 0,
+    last_used_at  TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_saved_addresses_label UNIQUE (owner_id, label)
+);
+
+CREATE INDEX idx_saved_addresses_owner ON saved_addresses (owner_id, use_count DESC);
+
+CREATE TRIGGER update_saved_addresses_updated_at
+    BEFORE UPDATE
+    ON saved_addresses
+    FOR EACH ROW
+EXECUTE PROCEDURE update_updated_at_column();
+
+COMMENT ON TABLE saved_addresses IS 'Address book entries reused when creating shipments; verified once a shipment to or from them completes.';
+COMMENT ON COLUMN saved_addresses.contact_phone IS 'Encrypted at rest.';
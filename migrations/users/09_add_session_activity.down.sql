@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_active_activity;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS last_activity_at,
+    DROP COLUMN IF EXISTS session_started_at,
+    DROP COLUMN IF EXISTS revoke_reason;
@@ -0,0 +1,22 @@
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS revoke_reason      VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS session_started_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS last_activity_at   TIMESTAMP WITH TIME ZONE;
+
+UPDATE refresh_tokens
+SET session_started_at = created_at,
+    last_activity_at   = created_at
+WHERE session_started_at IS NULL;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN session_started_at SET NOT NULL,
+    ALTER COLUMN session_started_at SET DEFAULT NOW(),
+    ALTER COLUMN last_activity_at SET NOT NULL,
+    ALTER COLUMN last_activity_at SET DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_active_activity
+    ON refresh_tokens (last_activity_at) WHERE revoked = FALSE;
+
+COMMENT ON COLUMN refresh_tokens.revoke_reason IS 'Why the token was revoked: logout, rotated, revoked_all, inactivity or session_limit.';
+COMMENT ON COLUMN refresh_tokens.session_started_at IS 'Login time of the session, carried across token rotation.';
+COMMENT ON COLUMN refresh_tokens.last_activity_at IS 'Last login or refresh of the session, used for the inactivity timeout.';
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE phone_otps
+(
+    id          UUID PRIMARY KEY                  DEFAULT gen_random_uuid(),
+    user_id     UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose     VARCHAR(30)              NOT NULL,
+    code_hash   VARCHAR(64)              NOT NULL,
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts    INTEGER                  NOT NULL DEFAULT 0,
+    consumed_at TIMESTAMP WITH TIME ZONE,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_phone_otps_user_purpose ON phone_otps (user_id, purpose, created_at DESC);
+CREATE INDEX idx_phone_otps_expires_at ON phone_otps (expires_at);
+
+COMMENT ON COLUMN users.phone_verified_at IS 'When the phone number was confirmed by SMS code; cleared when the number changes.';
+COMMENT ON TABLE phone_otps IS 'One-time passwords sent by SMS. Only a hash of the code is stored.';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_search_text;
+ALTER TABLE users DROP COLUMN IF EXISTS search_text;
+
+-- search_normalize and the extensions are shared with the shipments
+-- migration and are left in place
@@ -0,0 +1,19 @@
+-- Accent and case insensitive search. unaccent() is only STABLE, so it is
+-- wrapped in an IMMUTABLE function that generated columns and indexes accept.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE OR REPLACE FUNCTION search_normalize(value TEXT) RETURNS TEXT AS
+$$
+SELECT regexp_replace(lower(public.unaccent('public.unaccent'::regdictionary, COALESCE(value, ''))), '\s+', ' ', 'g')
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Phone numbers and addresses are encrypted and cannot be searched
+ALTER TABLE users
+    ADD COLUMN search_text TEXT GENERATED ALWAYS AS (
+        search_normalize(full_name) || ' ' || search_normalize(email)
+    ) STORED;
+
+CREATE INDEX idx_users_search_text ON users USING gin (search_text gin_trgm_ops);
+
+COMMENT ON COLUMN users.search_text IS 'Lowercased, unaccented name and email for admin user search.';
@@ -0,0 +1,14 @@
+CREATE TABLE account_merges
+(
+    id                UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    primary_user_id   UUID        NOT NULL REFERENCES users (id),
+    duplicate_user_id UUID        NOT NULL UNIQUE REFERENCES users (id),
+    merged_by         UUID        NOT NULL REFERENCES users (id),
+    counts            JSONB       NOT NULL DEFAULT '[]',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_account_merges_primary ON account_merges (primary_user_id);
+
+COMMENT ON TABLE account_merges IS 'Audit log of duplicate accounts folded into a primary account; merges are irreversible.';
+COMMENT ON COLUMN account_merges.counts IS 'Rows moved and skipped per table column.';
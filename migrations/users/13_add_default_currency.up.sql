@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS default_currency VARCHAR(3);
+
+COMMENT ON COLUMN users.default_currency IS 'Providers: currency used for shipments that do not name one.';
@@ -0,0 +1,3 @@
+-- PostgreSQL cannot drop a value from an enum type; disable analyst
+-- accounts instead so the role grants nothing
+UPDATE users SET is_active = FALSE WHERE role = 'analyst';
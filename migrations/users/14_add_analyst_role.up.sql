@@ -0,0 +1,2 @@
+-- Read-only accounts for auditors; their responses are redacted of PII
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'analyst';
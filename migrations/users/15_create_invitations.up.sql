@@ -0,0 +1,18 @@
+CREATE TABLE user_invitations
+(
+    id          UUID PRIMARY KEY                  DEFAULT gen_random_uuid(),
+    email       VARCHAR(255)             NOT NULL,
+    role        VARCHAR(50)              NOT NULL CHECK (role IN ('provider', 'shipper')),
+    token_hash  VARCHAR(64)              NOT NULL UNIQUE,
+    invited_by  UUID                     NOT NULL REFERENCES users (id),
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    accepted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    revoked_at  TIMESTAMP WITH TIME ZONE,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_invitations_pending ON user_invitations (created_at DESC)
+    WHERE accepted_at IS NULL AND revoked_at IS NULL;
+
+COMMENT ON TABLE user_invitations IS 'Admin-issued links to register as provider or shipper. Only a hash of the token is stored.';
@@ -0,0 +1,5 @@
+-- Existing accounts keep working without caps
+ALTER TABLE users
+    ADD COLUMN plan VARCHAR(20) NOT NULL DEFAULT 'standard' CHECK (plan IN ('free', 'standard'));
+
+COMMENT ON COLUMN users.plan IS 'Usage plan; decides the quota limits of the account.';
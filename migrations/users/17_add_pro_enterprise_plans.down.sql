@@ -0,0 +1,5 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_plan_check;
+UPDATE users SET plan = 'standard' WHERE plan IN ('pro', 'enterprise');
+ALTER TABLE users
+    ALTER COLUMN plan SET DEFAULT 'standard',
+    ADD CONSTRAINT users_plan_check CHECK (plan IN ('free', 'standard'));
@@ -0,0 +1,6 @@
+-- The standard plan was unlimited; enterprise is its unlimited successor
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_plan_check;
+UPDATE users SET plan = 'enterprise' WHERE plan = 'standard';
+ALTER TABLE users
+    ALTER COLUMN plan SET DEFAULT 'free',
+    ADD CONSTRAINT users_plan_check CHECK (plan IN ('free', 'pro', 'enterprise'));
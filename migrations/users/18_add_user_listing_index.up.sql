@@ -0,0 +1,2 @@
+-- The admin user listing pages through users newest first by default
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at DESC, id);
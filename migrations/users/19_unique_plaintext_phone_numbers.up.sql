@@ -0,0 +1,6 @@
+-- Without ENCRYPTION_KEYS phone numbers are stored in plaintext with no blind
+-- index, so they are kept unique by value. Encrypted rows are covered by
+-- idx_users_phone_number_hash once their blind index is backfilled.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number_plaintext
+    ON users (phone_number)
+    WHERE phone_number_hash IS NULL AND phone_number NOT LIKE 'enc:v1:%';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_users_phone_number_plaintext;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number_plaintext
+    ON users (phone_number)
+    WHERE phone_number_hash IS NULL AND phone_number NOT LIKE 'enc:v1:%';
+
+COMMENT ON COLUMN users.phone_number IS 'Encrypted at rest (enc:v1 envelope).';
+COMMENT ON COLUMN users.address IS 'Encrypted at rest (enc:v1 envelope).';
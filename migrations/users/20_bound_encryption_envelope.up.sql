@@ -0,0 +1,9 @@
+-- Encrypted values now use the enc:v2 envelope, which binds the ciphertext to
+-- its table, column and row. enc:v1 values stay readable until rotated.
+DROP INDEX IF EXISTS idx_users_phone_number_plaintext;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number_plaintext
+    ON users (phone_number)
+    WHERE phone_number_hash IS NULL AND phone_number NOT LIKE 'enc:v1:%' AND phone_number NOT LIKE 'enc:v2:%';
+
+COMMENT ON COLUMN users.phone_number IS 'Encrypted at rest (enc:v2 envelope bound to the row).';
+COMMENT ON COLUMN users.address IS 'Encrypted at rest (enc:v2 envelope bound to the row).';
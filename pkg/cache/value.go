@@ -0,0 +1,154 @@
+// Package cache keeps the result of expensive loads in memory.
+//
+// A Value is fresh for TTL after it was loaded and served as is. For a
+// further Stale window it is still served, but the first caller in that
+// window triggers a reload in the background. Once the stale window has
+// passed callers wait for a reload; concurrent callers share a single load.
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loadTimeout bounds a single load, which runs detached from callers
+const loadTimeout = 30 * time.Second
+
+// LoadFunc produces the value to cache
+type LoadFunc[T any] func(ctx context.Context) (T, error)
+
+// Value caches the result of a LoadFunc
+type Value[T any] struct {
+	load  LoadFunc[T]
+	ttl   time.Duration
+	stale time.Duration
+
+	mu         sync.Mutex
+	value      T
+	loadedAt   time.Time
+	accessedAt time.Time
+	inflight   *call[T]
+}
+
+type call[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// NewValue creates a cache around load. A ttl of zero disables caching and
+// every Get calls load directly.
+func NewValue[T any](load LoadFunc[T], ttl, stale time.Duration) *Value[T] {
+	return &Value[T]{
+		load:  load,
+		ttl:   ttl,
+		stale: stale,
+	}
+}
+
+// Get returns the cached value, loading it when it is missing or expired
+func (v *Value[T]) Get(ctx context.Context) (T, error) {
+	if v.ttl <= 0 {
+		return v.load(ctx)
+	}
+
+	v.mu.Lock()
+	now := time.Now()
+	v.accessedAt = now
+	if !v.loadedAt.IsZero() {
+		age := now.Sub(v.loadedAt)
+		if age < v.ttl {
+			value := v.value
+			v.mu.Unlock()
+			return value, nil
+		}
+		if age < v.ttl+v.stale {
+			value := v.value
+			v.startLocked()
+			v.mu.Unlock()
+			return value, nil
+		}
+	}
+	c := v.startLocked()
+	v.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Refresh reloads the value now, unless a load is already running
+func (v *Value[T]) Refresh() {
+	v.mu.Lock()
+	c := v.startLocked()
+	v.mu.Unlock()
+	<-c.done
+}
+
+// Invalidate drops the cached value so the next Get loads it again
+func (v *Value[T]) Invalidate() {
+	v.mu.Lock()
+	v.loadedAt = time.Time{}
+	v.mu.Unlock()
+}
+
+// StartRefresher reloads the value every interval while it is being read,
+// so readers keep getting fresh values without waiting for a load. Values
+// nobody asked for within the stale window are left to expire.
+func (v *Value[T]) StartRefresher(ctx context.Context, interval time.Duration) {
+	if v.ttl <= 0 || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			v.mu.Lock()
+			active := !v.accessedAt.IsZero() && time.Since(v.accessedAt) < v.ttl+v.stale
+			v.mu.Unlock()
+			if active {
+				v.Refresh()
+			}
+		}
+	}
+}
+
+// startLocked starts a background load unless one is running and returns
+// it. v.mu must be held.
+func (v *Value[T]) startLocked() *call[T] {
+	if v.inflight != nil {
+		return v.inflight
+	}
+
+	c := &call[T]{done: make(chan struct{})}
+	v.inflight = c
+
+	go func() {
+		// Detached from the caller so an aborted request does not fail the
+		// load for everyone waiting on it
+		ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+		c.value, c.err = v.load(ctx)
+		cancel()
+
+		v.mu.Lock()
+		if c.err == nil {
+			v.value = c.value
+			v.loadedAt = time.Now()
+		}
+		v.inflight = nil
+		v.mu.Unlock()
+		close(c.done)
+	}()
+
+	return c
+}
@@ -24,6 +24,9 @@ var (
 	ErrTokenExpired   = errors.New("token has expired")
 	ErrTokenInvalid   = errors.New("token is invalid")
 	ErrResetTokenUsed = errors.New("reset token has already been used")
+
+	ErrSessionDisplaced = errors.New("you were signed out because your account signed in on another device")
+	ErrSessionIdle      = errors.New("you were signed out after a period of inactivity")
 )
 
 type AppError struct {
@@ -0,0 +1,314 @@
+// Package pdf is a minimal PDF 1.4 writer for simple printable documents such
+// as labels and manifests. It supports text in the standard Helvetica fonts,
+// lines, filled rectangles, fill colors and PNG or JPEG images, which is all
+// those layouts need.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Common page sizes in points (1/72 inch).
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+
+	// 4x6 inch thermal label
+	LabelWidth  = 288
+	LabelHeight = 432
+)
+
+// Document is a PDF document made of one or more pages.
+type Document struct {
+	width  float64
+	height float64
+	pages  []*Page
+	images []*Image
+	title  string
+}
+
+// New creates a document whose pages have the given size in points.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// SetTitle sets the document title shown by viewers.
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// AddPage appends a new blank page.
+func (d *Document) AddPage() *Page {
+	p := &Page{height: d.height}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Image is a raster image embedded once in a document and drawable on any of
+// its pages.
+type Image struct {
+	name   string
+	width  int
+	height int
+	data   []byte // zlib compressed RGB samples
+}
+
+// Width returns the width of the image in pixels.
+func (img *Image) Width() int { return img.width }
+
+// Height returns the height of the image in pixels.
+func (img *Image) Height() int { return img.height }
+
+// AddImage decodes a PNG or JPEG image and embeds it in the document.
+// Transparent pixels are flattened onto white.
+func (d *Document) AddImage(data []byte) (*Image, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	var raw bytes.Buffer
+	zw := zlib.NewWriter(&raw)
+	row := make([]byte, 0, bounds.Dx()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row = row[:0]
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			row = append(row, flatten(c.R, c.A), flatten(c.G, c.A), flatten(c.B, c.A))
+		}
+		if _, err := zw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	img := &Image{
+		name:   "Im" + strconv.Itoa(len(d.images)+1),
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		data:   raw.Bytes(),
+	}
+	d.images = append(d.images, img)
+	return img, nil
+}
+
+// flatten blends a color channel with the given alpha onto white.
+func flatten(v, alpha uint8) uint8 {
+	return uint8((uint16(v)*uint16(alpha) + 255*uint16(255-alpha)) / 255)
+}
+
+// Color is an RGB color used for text, lines and fills.
+type Color struct {
+	R, G, B uint8
+}
+
+// Black is the default drawing color.
+var Black = Color{}
+
+// ParseHexColor parses a color in #rgb or #rrggbb notation.
+func ParseHexColor(s string) (Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return Black, errors.New("invalid hex color")
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Black, errors.New("invalid hex color")
+	}
+	return Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
+
+// Page is a single page. Coordinates are in points with the origin at the top
+// left corner, y growing downwards.
+type Page struct {
+	height  float64
+	content bytes.Buffer
+}
+
+// Text draws s with its baseline starting at (x, y).
+func (p *Page) Text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, p.height-y, escape(s))
+}
+
+// Line draws a straight line of the given width.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, p.height-y1, x2, p.height-y2)
+}
+
+// Rect draws a rectangle whose top left corner is at (x, y), either filled
+// black or stroked with a thin outline.
+func (p *Page) Rect(x, y, w, h float64, fill bool) {
+	op := "S"
+	if fill {
+		op = "f"
+	}
+	fmt.Fprintf(&p.content, "0.75 w %.2f %.2f %.2f %.2f re %s\n", x, p.height-y-h, w, h, op)
+}
+
+// SetColor sets the color used by the following text, lines and rectangles.
+func (p *Page) SetColor(c Color) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f rg %.3f %.3f %.3f RG\n", r, g, b, r, g, b)
+}
+
+// Image draws img scaled to fit within the w x h box whose top left corner is
+// at (x, y), keeping its aspect ratio. It returns the drawn width and height.
+func (p *Page) Image(img *Image, x, y, w, h float64) (float64, float64) {
+	scale := w / float64(img.width)
+	if s := h / float64(img.height); s < scale {
+		scale = s
+	}
+	dw, dh := float64(img.width)*scale, float64(img.height)*scale
+	fmt.Fprintf(&p.content, "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", dw, dh, x, p.height-y-dh, img.name)
+	return dw, dh
+}
+
+// TextWidth estimates the width of s in points. Helvetica metrics are
+// approximated, which is accurate enough for wrapping and right alignment.
+func TextWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * size * 0.52
+}
+
+// Wrap splits s into lines that fit within width at the given font size.
+func Wrap(s string, size, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(candidate, size) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo renders the document to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Fixed objects: 1 catalog, 2 page tree, 3-4 fonts, 5 info; then one
+	// object per image and a page and content stream pair per page.
+	const firstImageObj = 6
+	firstPageObj := firstImageObj + len(d.images)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+i*2)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (cargo-tracker) >>", escape(d.title)))
+
+	xobjects := make([]string, len(d.images))
+	for i, img := range d.images {
+		xobjects[i] = fmt.Sprintf("/%s %d 0 R", img.name, firstImageObj+i)
+		object(fmt.Sprintf(
+			"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+			img.width, img.height, len(img.data), img.data,
+		))
+	}
+	resources := "/Font << /F1 3 0 R /F2 4 0 R >>"
+	if len(xobjects) > 0 {
+		resources += " /XObject << " + strings.Join(xobjects, " ") + " >>"
+	}
+
+	for i, p := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %d 0 R >>",
+			d.width, d.height, resources, firstPageObj+i*2+1,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// winAnsi maps the few non Latin-1 characters we print to WinAnsiEncoding.
+var winAnsi = map[rune]byte{
+	'–': 0x96,
+	'—': 0x97,
+	'•': 0x95,
+	'…': 0x85,
+	'‘': 0x91,
+	'’': 0x92,
+	'“': 0x93,
+	'”': 0x94,
+	'€': 0x80,
+}
+
+// escape converts s to a PDF string literal body in WinAnsiEncoding.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x80:
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			fmt.Fprintf(&b, "\\%03o", winAnsi[r])
+		case r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
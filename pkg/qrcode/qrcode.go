@@ -0,0 +1,211 @@
+// Package qrcode implements a small QR Code (model 2) encoder supporting byte
+// mode at error correction level M, versions 1 through 10 (up to 213 bytes).
+// That is plenty for shipment identifiers and tracking URLs on labels.
+package qrcode
+
+import (
+	"errors"
+)
+
+var ErrDataTooLong = errors.New("qrcode: data too long")
+
+// versionInfo describes the codeword layout of one version at level M.
+type versionInfo struct {
+	ecPerBlock int
+	groups     [][2]int // {block count, data codewords per block}
+	alignment  []int
+	remainder  int
+}
+
+var versions = []versionInfo{
+	1:  {ecPerBlock: 10, groups: [][2]int{{1, 16}}},
+	2:  {ecPerBlock: 16, groups: [][2]int{{1, 28}}, alignment: []int{6, 18}, remainder: 7},
+	3:  {ecPerBlock: 26, groups: [][2]int{{1, 44}}, alignment: []int{6, 22}, remainder: 7},
+	4:  {ecPerBlock: 18, groups: [][2]int{{2, 32}}, alignment: []int{6, 26}, remainder: 7},
+	5:  {ecPerBlock: 24, groups: [][2]int{{2, 43}}, alignment: []int{6, 30}, remainder: 7},
+	6:  {ecPerBlock: 16, groups: [][2]int{{4, 27}}, alignment: []int{6, 34}, remainder: 7},
+	7:  {ecPerBlock: 18, groups: [][2]int{{4, 31}}, alignment: []int{6, 22, 38}},
+	8:  {ecPerBlock: 22, groups: [][2]int{{2, 38}, {2, 39}}, alignment: []int{6, 24, 42}},
+	9:  {ecPerBlock: 22, groups: [][2]int{{3, 36}, {2, 37}}, alignment: []int{6, 26, 46}},
+	10: {ecPerBlock: 26, groups: [][2]int{{4, 43}, {1, 44}}, alignment: []int{6, 28, 50}},
+}
+
+func (v versionInfo) dataCodewords() int {
+	n := 0
+	for _, g := range v.groups {
+		n += g[0] * g[1]
+	}
+	return n
+}
+
+// Code is an encoded QR symbol.
+type Code struct {
+	Size    int
+	modules [][]bool
+}
+
+// Dark reports whether the module at column x, row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode encodes data as a QR code using the smallest version that fits.
+func Encode(data string) (*Code, error) {
+	payload := []byte(data)
+
+	version := 0
+	for v := 1; v < len(versions); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+len(payload)*8 <= versions[v].dataCodewords()*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrDataTooLong
+	}
+
+	codewords := addErrorCorrection(encodeData(payload, version), versions[version])
+
+	q := newSymbol(version)
+	q.drawFunctionPatterns()
+	q.drawCodewords(codewords, versions[version].remainder)
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if p := q.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		q.applyMask(mask) // masking is an XOR, so this undoes it
+	}
+	q.applyMask(best)
+	q.drawFormatBits(best)
+
+	return &Code{Size: q.size, modules: q.modules}, nil
+}
+
+// encodeData builds the data codewords: mode, length, payload, terminator and padding.
+func encodeData(payload []byte, version int) []byte {
+	capacity := versions[version].dataCodewords()
+	var bb bitBuffer
+
+	bb.append(0x4, 4) // byte mode
+	if version >= 10 {
+		bb.append(len(payload), 16)
+	} else {
+		bb.append(len(payload), 8)
+	}
+	for _, b := range payload {
+		bb.append(int(b), 8)
+	}
+
+	terminator := capacity*8 - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	for len(bb)%8 != 0 {
+		bb = append(bb, false)
+	}
+	for pad := 0xEC; len(bb) < capacity*8; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	out := make([]byte, capacity)
+	for i, bit := range bb {
+		if bit {
+			out[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+	return out
+}
+
+// addErrorCorrection splits data into blocks, appends Reed-Solomon codewords
+// and interleaves the result.
+func addErrorCorrection(data []byte, v versionInfo) []byte {
+	divisor := rsDivisor(v.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for _, g := range v.groups {
+		for i := 0; i < g[0]; i++ {
+			block := data[offset : offset+g[1]]
+			offset += g[1]
+			dataBlocks = append(dataBlocks, block)
+			ecBlocks = append(ecBlocks, rsRemainder(block, divisor))
+		}
+	}
+
+	var out []byte
+	for i := 0; ; i++ {
+		wrote := false
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				out = append(out, block[i])
+				wrote = true
+			}
+		}
+		if !wrote {
+			break
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			out = append(out, block[i])
+		}
+	}
+	return out
+}
+
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*bb = append(*bb, (value>>uint(i))&1 != 0)
+	}
+}
+
+// Reed-Solomon arithmetic over GF(2^8) with the QR polynomial 0x11D.
+
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
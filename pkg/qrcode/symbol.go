@@ -0,0 +1,262 @@
+package qrcode
+
+// symbol is the module grid being built, tracking which modules belong to
+// function patterns and must not hold data or be masked.
+type symbol struct {
+	version    int
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+func newSymbol(version int) *symbol {
+	size := version*4 + 17
+	q := &symbol{version: version, size: size}
+	q.modules = make([][]bool, size)
+	q.isFunction = make([][]bool, size)
+	for i := range q.modules {
+		q.modules[i] = make([]bool, size)
+		q.isFunction[i] = make([]bool, size)
+	}
+	return q
+}
+
+func (q *symbol) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+func (q *symbol) drawFunctionPatterns() {
+	// Timing patterns
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	q.drawFinder(3, 3)
+	q.drawFinder(q.size-4, 3)
+	q.drawFinder(3, q.size-4)
+
+	// Alignment patterns, skipping the three finder corners
+	pos := versions[q.version].alignment
+	last := len(pos) - 1
+	for i := range pos {
+		for j := range pos {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			q.drawAlignment(pos[i], pos[j])
+		}
+	}
+
+	// Reserve format areas; real bits are drawn once the mask is chosen
+	q.drawFormatBits(0)
+	q.drawVersion()
+}
+
+func (q *symbol) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || x >= q.size || y < 0 || y >= q.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			q.setFunction(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (q *symbol) drawAlignment(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits writes both copies of the format information for level M.
+func (q *symbol) drawFormatBits(mask int) {
+	const levelM = 0
+	data := levelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>uint(i))&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(i))
+	}
+	q.setFunction(8, q.size-8, true) // dark module
+}
+
+// drawVersion writes the version information blocks used from version 7 on.
+func (q *symbol) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+	rem := q.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := q.version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 != 0
+		a, b := q.size-11+i%3, i/3
+		q.setFunction(a, b, dark)
+		q.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places the data in the zigzag order defined by the standard.
+func (q *symbol) drawCodewords(data []byte, remainder int) {
+	total := len(data)*8 + remainder
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = q.size - 1 - vert
+				}
+				if q.isFunction[y][x] || i >= total {
+					continue
+				}
+				if i < len(data)*8 {
+					q.modules[y][x] = (data[i>>3]>>uint(7-i&7))&1 != 0
+				}
+				i++
+			}
+		}
+	}
+}
+
+func (q *symbol) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.isFunction[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the four mask evaluation rules; lower is better.
+func (q *symbol) penalty() int {
+	score := 0
+	get := func(x, y int, horizontal bool) bool {
+		if horizontal {
+			return q.modules[y][x]
+		}
+		return q.modules[x][y]
+	}
+
+	for _, horizontal := range []bool{true, false} {
+		for y := 0; y < q.size; y++ {
+			// Rule 1: runs of five or more same-coloured modules
+			run := 1
+			for x := 1; x < q.size; x++ {
+				if get(x, y, horizontal) == get(x-1, y, horizontal) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+
+			// Rule 3: finder-like 1:1:3:1:1 patterns with four light modules on a side
+			for x := 0; x+10 < q.size; x++ {
+				pattern := [11]bool{}
+				for k := range pattern {
+					pattern[k] = get(x+k, y, horizontal)
+				}
+				core := pattern[4] && !pattern[5] && pattern[6] && pattern[7] && pattern[8] && !pattern[9] && pattern[10]
+				coreRev := pattern[0] && !pattern[1] && pattern[2] && pattern[3] && pattern[4] && !pattern[5] && pattern[6]
+				if core && !pattern[0] && !pattern[1] && !pattern[2] && !pattern[3] {
+					score += 40
+				}
+				if coreRev && !pattern[7] && !pattern[8] && !pattern[9] && !pattern[10] {
+					score += 40
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of the same colour
+	dark := 0
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				c := q.modules[y][x]
+				if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+
+	// Rule 4: balance of dark and light modules
+	total := q.size * q.size
+	deviation := abs(dark*20-total*10) / total
+	score += deviation * 10
+
+	return score
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -1,5 +1,7 @@
 package utils
 
+import "net"
+
 func StringPtr(s string) *string {
 	return &s
 }
@@ -11,3 +13,9 @@ func IntPtr(i int) *int {
 func BoolPtr(b bool) *bool {
 	return &b
 }
+
+// IsPublicIP reports whether ip is a globally routable unicast address
+func IsPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() &&
+		!ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsUnspecified()
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// foldedLetters maps accented letters to their base letter. It covers
+// Vietnamese and the common Latin-1 accents, matching what the Postgres
+// unaccent dictionary does for these letters.
+var foldedLetters = buildFoldTable(map[rune]string{
+	'a': "àáảãạăằắẳẵặâầấẩẫậäåā",
+	'e': "èéẻẽẹêềếểễệëē",
+	'i': "ìíỉĩịîïī",
+	'o': "òóỏõọôồốổỗộơờớởỡợöøō",
+	'u': "ùúủũụưừứửữựûüū",
+	'y': "ỳýỷỹỵÿ",
+	'd': "đ",
+	'c': "ç",
+	'n': "ñ",
+})
+
+func buildFoldTable(groups map[rune]string) map[rune]rune {
+	table := make(map[rune]rune)
+	for base, letters := range groups {
+		for _, r := range letters {
+			table[r] = base
+		}
+	}
+	return table
+}
+
+// NormalizeSearch lowercases a search term, strips accents and collapses
+// whitespace, so "Hà Nội" and "ha noi" match the same rows. Queries compare
+// it against columns normalized with lower(unaccent(...)) in the database.
+func NormalizeSearch(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+	for _, r := range strings.ToLower(input) {
+		if folded, ok := foldedLetters[r]; ok {
+			r = folded
+		}
+		// Drop combining marks left by decomposed input
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
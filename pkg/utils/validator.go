@@ -9,10 +9,13 @@ import (
 
 var validate *validator.Validate
 
+// referenceLookup reports whether code is a value of a kind of reference data
+var referenceLookup func(kind, code string) bool
+
 func init() {
 	validate = validator.New()
 
-	err := validate.RegisterValidation("user_role", validateUserRole)
+	err := validate.RegisterValidation("ref", validateReference)
 	if err != nil {
 		return
 	}
@@ -26,16 +29,17 @@ func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
 
-func validateUserRole(fl validator.FieldLevel) bool {
-	role := fl.Field().String()
-	validRoles := []string{"customer", "provider", "shipper", "admin"}
+// SetReferenceLookup backs the ref validation tag, e.g. ref=issue_type, with
+// the reference tables. Until it is set no value passes.
+func SetReferenceLookup(lookup func(kind, code string) bool) {
+	referenceLookup = lookup
+}
 
-	for _, validRole := range validRoles {
-		if role == validRole {
-			return true
-		}
+func validateReference(fl validator.FieldLevel) bool {
+	if referenceLookup == nil {
+		return false
 	}
-	return false
+	return referenceLookup(fl.Param(), fl.Field().String())
 }
 
 func validatePhone(fl validator.FieldLevel) bool {
@@ -0,0 +1,106 @@
+// Package webhooksig signs outgoing webhook requests and verifies them on the
+// receiving side.
+//
+// Every request carries three headers:
+//
+//	X-Cargo-Event-Id:  unique event ID, identical across retries
+//	X-Cargo-Timestamp: Unix time in seconds when the attempt was signed
+//	X-Cargo-Signature: v1=<hex HMAC-SHA256 of "<timestamp>.<raw body>">
+//
+// Events the sender replays from its archive, on the owner's request, keep
+// their original event ID and carry X-Cargo-Replay: true; the timestamp and
+// signature are fresh. Receivers that already processed the event ID should
+// acknowledge the replay without processing it again.
+//
+// While a secret is being rotated the signature header carries one v1 entry
+// per valid secret, e.g. "v1=abc...,v1=def...", and a receiver accepts the
+// request if any entry matches a secret it knows.
+//
+// Receivers should:
+//   - compute the HMAC over the raw request body, before any JSON decoding
+//   - compare signatures in constant time (Verify does)
+//   - reject timestamps further than DefaultTolerance from their clock, so a
+//     captured request cannot be replayed later
+//   - remember event IDs seen within the tolerance window and drop repeats,
+//     so a request cannot be replayed inside the window either
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderEventID   = "X-Cargo-Event-Id"
+	HeaderTimestamp = "X-Cargo-Timestamp"
+	HeaderSignature = "X-Cargo-Signature"
+	HeaderReplay    = "X-Cargo-Replay"
+
+	// DefaultTolerance is the recommended replay window
+	DefaultTolerance = 5 * time.Minute
+
+	schemeV1 = "v1"
+)
+
+var (
+	ErrMissingHeader     = errors.New("webhooksig: missing signature or timestamp header")
+	ErrInvalidTimestamp  = errors.New("webhooksig: invalid timestamp")
+	ErrTimestampExpired  = errors.New("webhooksig: timestamp outside tolerance")
+	ErrSignatureMismatch = errors.New("webhooksig: no matching signature")
+)
+
+// Sign returns the hex encoded v1 signature of body sent at timestamp
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader returns the signature header value for body, with one entry
+// per secret
+func SignatureHeader(secrets []string, timestamp time.Time, body []byte) string {
+	entries := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		entries = append(entries, schemeV1+"="+Sign(secret, timestamp, body))
+	}
+	return strings.Join(entries, ",")
+}
+
+// Verify checks the timestamp and signature headers of a received request
+// against secret. A tolerance of zero uses DefaultTolerance.
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, now time.Time, tolerance time.Duration) error {
+	if timestampHeader == "" || signatureHeader == "" {
+		return ErrMissingHeader
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	unix, err := strconv.ParseInt(strings.TrimSpace(timestampHeader), 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	timestamp := time.Unix(unix, 0)
+	if diff := now.Sub(timestamp); diff > tolerance || diff < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	expected := []byte(Sign(secret, timestamp, body))
+	for _, entry := range strings.Split(signatureHeader, ",") {
+		scheme, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || scheme != schemeV1 {
+			continue
+		}
+		if hmac.Equal([]byte(value), expected) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}